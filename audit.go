@@ -0,0 +1,80 @@
+package gaws
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// AuditRecord describes an API call gaws made, for an audit log kept by the application alongside CloudTrail.
+type AuditRecord struct {
+	Time      time.Time         // When the call was made
+	Operation string            // Like DynamoDB_20120810.PutItem or SendMessage, the same as RequestMetrics.Operation
+	Endpoint  string            // The host the call was made to, like kinesis.us-east-1.amazonaws.com
+	Resources map[string]string // The resources the call named, keyed by parameter, like {"StreamName": "orders"}. Calls with a path, like S3's, have it as "Path".
+	Principal string            // The access key ID the call was signed with. It is empty if awsauth found the credentials itself.
+	Err       error             // The error the call returned, or nil if it succeeded
+}
+
+// Auditor is told about every API call gaws makes, once it has finished, including its retries.
+// Audit is called from every goroutine making requests, so it must be safe for concurrent use and should return quickly.
+type Auditor interface {
+	Audit(record AuditRecord)
+}
+
+// Audit is told about every API call gaws makes. If it is nil, calls are not audited.
+var Audit Auditor
+
+// auditParameters are the parameters that name the resources of calls, across services.
+var auditParameters = []string{
+	"StreamName", "StreamARN", "ConsumerARN", "TableName", "IndexName", "QueueUrl", "QueueName", "TopicArn", "TargetArn",
+	"FunctionName", "LogGroupName", "LogStreamName", "StackName", "KeyId", "DomainName", "ClusterIdentifier", "RoleArn",
+}
+
+// resources returns the resources the request names in its parameters, or its path.
+func (r *AWSRequest) resources() map[string]string {
+	resources := map[string]string{}
+
+	if body := bytes.TrimSpace(r.Body); len(body) > 0 && body[0] == '{' {
+		params := map[string]json.RawMessage{}
+		if json.Unmarshal(body, &params) == nil {
+			for _, name := range auditParameters {
+				var value string
+				if json.Unmarshal(params[name], &value) == nil && value != "" {
+					resources[name] = value
+				}
+			}
+		}
+	} else if r.Headers["Content-Type"] == "application/x-www-form-urlencoded" {
+		if params, err := url.ParseQuery(string(r.Body)); err == nil {
+			for _, name := range auditParameters {
+				if value := params.Get(name); value != "" {
+					resources[name] = value
+				}
+			}
+		}
+	}
+
+	if u, err := url.Parse(r.URL); err == nil && u.Path != "" && u.Path != "/" {
+		resources["Path"] = u.Path
+	}
+	return resources
+}
+
+// audit tells Audit about a call that started at start, if there is an auditor. An auditor that panics does not fail the call.
+func (r *AWSRequest) audit(start time.Time, err error) {
+	if Audit == nil {
+		return
+	}
+
+	record := AuditRecord{Time: start, Operation: r.operation(), Endpoint: r.host(), Resources: r.resources(), Err: err}
+	if keys, _ := r.credentials(); len(keys) > 0 {
+		record.Principal = keys[0].AccessKeyID
+	}
+
+	Protect("gaws.Audit", func() error {
+		Audit.Audit(record)
+		return nil
+	})
+}
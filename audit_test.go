@@ -0,0 +1,108 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testAuditor struct {
+	lock    sync.Mutex
+	records []AuditRecord
+}
+
+func (a *testAuditor) Audit(r AuditRecord) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	a.records = append(a.records, r)
+}
+
+type panickingAuditor struct{}
+
+func (panickingAuditor) Audit(AuditRecord) {
+	panic("nope")
+}
+
+func TestAudit(t *testing.T) {
+	Convey("Given an auditor", t, func() {
+		a := &testAuditor{}
+		Audit = a
+		defer func() { Audit = nil }()
+
+		Convey("When a JSON request succeeds", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL
+			r.Method = "POST"
+			r.Headers["X-Amz-Target"] = "Kinesis_20131202.PutRecord"
+			r.Body = []byte(`{"StreamName":"orders","Data":"e30=","PartitionKey":"1"}`)
+			r.Credentials = StaticCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+			r.Do()
+
+			Convey("It is told about the call, the stream and the key it was signed with", func() {
+				So(len(a.records), ShouldEqual, 1)
+				So(a.records[0].Operation, ShouldEqual, "Kinesis_20131202.PutRecord")
+				So(a.records[0].Endpoint, ShouldEqual, ts.URL[len("http://"):])
+				So(a.records[0].Resources, ShouldResemble, map[string]string{"StreamName": "orders"})
+				So(a.records[0].Principal, ShouldEqual, "AKID")
+				So(a.records[0].Time.IsZero(), ShouldBeFalse)
+				So(a.records[0].Err, ShouldBeNil)
+			})
+		})
+
+		Convey("When a form request fails", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL + "/"
+			r.Method = "POST"
+			r.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			r.Body = []byte("Action=SendMessage&QueueUrl=https%3A%2F%2Fsqs.us-east-1.amazonaws.com%2F1%2Fjobs&Version=2012-11-05")
+			_, err := r.DoResponse()
+
+			Convey("It is told about the queue and the error", func() {
+				So(len(a.records), ShouldEqual, 1)
+				So(a.records[0].Operation, ShouldEqual, "SendMessage")
+				So(a.records[0].Resources, ShouldResemble, map[string]string{"QueueUrl": "https://sqs.us-east-1.amazonaws.com/1/jobs"})
+				So(a.records[0].Principal, ShouldEqual, "")
+				So(a.records[0].Err, ShouldNotBeNil)
+				So(a.records[0].Err, ShouldResemble, err)
+			})
+		})
+
+		Convey("When a request has a path", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL + "/bucket/key"
+			r.Do()
+
+			Convey("It is told about the path", func() {
+				So(a.records[0].Resources, ShouldResemble, map[string]string{"Path": "/bucket/key"})
+			})
+		})
+	})
+
+	Convey("Given an auditor that panics", t, func() {
+		Audit = panickingAuditor{}
+		defer func() { Audit = nil }()
+
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		_, err := r.Do()
+
+		Convey("The request still succeeds", func() {
+			So(err, ShouldBeNil)
+		})
+	})
+}
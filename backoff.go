@@ -0,0 +1,101 @@
+package gaws
+
+import (
+	"math/rand"
+	"time"
+)
+
+// maxWait is the longest time.Duration. Backoffs wait no longer, rather than overflow.
+const maxWait = time.Duration(1<<63 - 1)
+
+// Backoff decides how long to wait before trying something again, like a request that failed, or a waiter checking a table again.
+// Wait returns the wait after a try fails, where tries count from 1 and last is the wait after the try before, or 0 after the first.
+// Backoffs are used from every goroutine making requests, so they must be safe for concurrent use. They need not be comparable; a
+// func can be a Backoff.
+type Backoff interface {
+	Wait(try int, last time.Duration) time.Duration
+}
+
+// ConstantBackoff waits the same time after every try.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// Wait returns the delay.
+func (b ConstantBackoff) Wait(try int, last time.Duration) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff waits Base after the first try, and twice as long after each try after it. It is what gaws uses by default, from
+// 200 milliseconds.
+type ExponentialBackoff struct {
+	Base time.Duration
+}
+
+// Wait returns Base doubled for each try after the first, or the longest time.Duration if that would overflow.
+func (b ExponentialBackoff) Wait(try int, last time.Duration) time.Duration {
+	if try < 1 {
+		try = 1
+	}
+	if try > 63 || b.Base > maxWait>>uint(try-1) {
+		return maxWait
+	}
+	return b.Base << uint(try-1)
+}
+
+// FibonacciBackoff waits Base times the Fibonacci number of the try: Base, Base, 2*Base, 3*Base, 5*Base and so on. It grows slower
+// than ExponentialBackoff.
+type FibonacciBackoff struct {
+	Base time.Duration
+}
+
+// Wait returns Base times the Fibonacci number of the try.
+func (b FibonacciBackoff) Wait(try int, last time.Duration) time.Duration {
+	previous, current := int64(0), int64(1)
+	for i := 1; i < try && i < 80; i++ {
+		previous, current = current, previous+current
+	}
+	return time.Duration(current) * b.Base
+}
+
+// DecorrelatedJitter waits a random time between Base and three times the last wait, but no more than Cap, which spreads out the retries
+// of clients that failed at the same time. It is the "decorrelated jitter" of
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/. If Cap is 0, the waits are capped only by the longest
+// time.Duration.
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Cap  time.Duration
+}
+
+// Wait returns a random time between Base and three times last.
+func (b DecorrelatedJitter) Wait(try int, last time.Duration) time.Duration {
+	if last < b.Base {
+		last = b.Base
+	}
+	if last > maxWait/3 {
+		last = maxWait / 3
+	}
+	wait := b.Base
+	if spread := 3*last - b.Base; spread > 0 {
+		wait += time.Duration(rand.Int63n(int64(spread)))
+	}
+	if b.Cap > 0 && wait > b.Cap {
+		wait = b.Cap
+	}
+	return wait
+}
+
+// CappedBackoff waits as Backoff does, but never longer than Max, like CappedBackoff{ExponentialBackoff{time.Second}, time.Minute}.
+type CappedBackoff struct {
+	Backoff Backoff
+	Max     time.Duration
+}
+
+// Wait returns the wait of Backoff, or Max if it is longer.
+func (b CappedBackoff) Wait(try int, last time.Duration) time.Duration {
+	wait := b.Backoff.Wait(try, last)
+	if wait > b.Max {
+		return b.Max
+	}
+	return wait
+}
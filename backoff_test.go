@@ -0,0 +1,111 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// waits returns the waits of a backoff after each of n tries.
+func waits(b Backoff, n int) []time.Duration {
+	waits := []time.Duration{}
+	var last time.Duration
+	for try := 1; try <= n; try++ {
+		last = b.Wait(try, last)
+		waits = append(waits, last)
+	}
+	return waits
+}
+
+// funcBackoff is a Backoff that is a func, which cannot be compared with ==.
+type funcBackoff func(try int) time.Duration
+
+func (f funcBackoff) Wait(try int, last time.Duration) time.Duration {
+	return f(try)
+}
+
+func TestBackoffs(t *testing.T) {
+	Convey("Given backoffs", t, func() {
+		Convey("A constant backoff waits the same each time", func() {
+			So(waits(ConstantBackoff{Delay: time.Second}, 3), ShouldResemble, []time.Duration{time.Second, time.Second, time.Second})
+		})
+		Convey("An exponential backoff doubles", func() {
+			So(waits(ExponentialBackoff{Base: time.Second}, 4), ShouldResemble, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second})
+		})
+		Convey("An exponential backoff does not overflow", func() {
+			So(ExponentialBackoff{Base: time.Millisecond}.Wait(100, 0), ShouldBeGreaterThan, 0)
+			So(ExponentialBackoff{Base: time.Hour}.Wait(31, 0), ShouldEqual, maxWait)
+			So(ExponentialBackoff{Base: time.Hour}.Wait(100, 0), ShouldEqual, maxWait)
+		})
+		Convey("A Fibonacci backoff grows by the Fibonacci numbers", func() {
+			So(waits(FibonacciBackoff{Base: time.Second}, 6), ShouldResemble, []time.Duration{1 * time.Second, 1 * time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 8 * time.Second})
+		})
+		Convey("Decorrelated jitter waits between the base and three times the last wait, up to the cap", func() {
+			b := DecorrelatedJitter{Base: 100 * time.Millisecond, Cap: time.Second}
+			var last time.Duration
+			for try := 1; try <= 100; try++ {
+				wait := b.Wait(try, last)
+				So(wait, ShouldBeGreaterThanOrEqualTo, b.Base)
+				So(wait, ShouldBeLessThanOrEqualTo, b.Cap)
+				if last > 0 {
+					So(wait, ShouldBeLessThanOrEqualTo, 3*last)
+				}
+				last = wait
+			}
+		})
+		Convey("Decorrelated jitter without a cap does not overflow", func() {
+			b := DecorrelatedJitter{Base: time.Second}
+			var last time.Duration
+			for try := 1; try <= 200; try++ {
+				last = b.Wait(try, last)
+				So(last, ShouldBeGreaterThanOrEqualTo, b.Base)
+			}
+			So(b.Wait(201, maxWait), ShouldBeGreaterThanOrEqualTo, b.Base)
+		})
+		Convey("A capped backoff waits no more than its max", func() {
+			b := CappedBackoff{Backoff: ExponentialBackoff{Base: time.Second}, Max: 3 * time.Second}
+			So(waits(b, 4), ShouldResemble, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second})
+		})
+	})
+}
+
+func TestRetryPolicyStrategy(t *testing.T) {
+	Convey("Given a client with a backoff", t, func() {
+		c, restore := useTestClock()
+		defer restore()
+
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		config := NewConfig(WithRetryPolicy(RetryPolicy{MaxTries: 4, Backoff: time.Minute}), WithBackoff(FibonacciBackoff{Base: time.Second}))
+		r := canonicalRequest()
+		r.URL = ts.URL
+		config.Apply(&r)
+		r.Do()
+
+		Convey("Its requests are retried with the backoff", func() {
+			So(c.sleeps, ShouldResemble, []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second})
+		})
+		Convey("The rest of its retry policy is kept", func() {
+			So(r.RetryPolicy.MaxTries, ShouldEqual, 4)
+		})
+	})
+
+	Convey("Given a config whose backoff is a func", t, func() {
+		config := NewConfig(WithBackoff(funcBackoff(func(try int) time.Duration { return time.Second })))
+		r := canonicalRequest()
+
+		Convey("Applying it to a request does not panic", func() {
+			So(func() { config.Apply(&r) }, ShouldNotPanic)
+			So(r.RetryPolicy.Delay(1, 0), ShouldEqual, time.Second)
+		})
+		Convey("Applying it to a request that has a policy keeps the request's", func() {
+			r.RetryPolicy = RetryPolicy{Strategy: funcBackoff(func(try int) time.Duration { return time.Minute })}
+			So(func() { config.Apply(&r) }, ShouldNotPanic)
+			So(r.RetryPolicy.Delay(1, 0), ShouldEqual, time.Minute)
+		})
+	})
+}
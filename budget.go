@@ -0,0 +1,65 @@
+package gaws
+
+import (
+	"sync"
+)
+
+// DefaultRetryBudget is the retry budget of requests whose RetryPolicy has none. If it is nil, they are retried up to MaxTries times.
+var DefaultRetryBudget *RetryBudget
+
+// RetryBudget limits the retries of every request that shares it, like those of a client, so that when AWS throttles a burst of requests,
+// retrying them does not send it even more. Each retry takes tokens from the budget, and requests that succeed put tokens back. When it
+// runs out, failing requests return their error instead of being retried, until enough requests succeed.
+// It is safe for concurrent use. Make one with NewRetryBudget.
+type RetryBudget struct {
+	RetryCost         int // The tokens a retry takes
+	ThrottleRetryCost int // The tokens a retry of a throttled request takes. Throttling means AWS wants fewer requests, so it costs more.
+	Refill            int // The tokens a request that succeeds without retries puts back. One that was retried puts back what its retries took.
+
+	lock     sync.Mutex
+	capacity int
+	tokens   int
+}
+
+// NewRetryBudget returns a full budget of capacity tokens. Retries cost 5 tokens, or 10 if the request was throttled, and requests that
+// succeed put 1 back, so a budget of 500 allows 100 retries in a row.
+func NewRetryBudget(capacity int) *RetryBudget {
+	return &RetryBudget{RetryCost: 5, ThrottleRetryCost: 10, Refill: 1, capacity: capacity, tokens: capacity}
+}
+
+// Available returns the tokens left in the budget.
+func (b *RetryBudget) Available() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.tokens
+}
+
+// take takes the tokens for a retry, and returns how many it took. It returns false, and takes none, if there are not enough.
+func (b *RetryBudget) take(throttled bool) (int, bool) {
+	cost := b.RetryCost
+	if throttled {
+		cost = b.ThrottleRetryCost
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if cost > b.tokens {
+		return 0, false
+	}
+	b.tokens -= cost
+	return cost, true
+}
+
+// succeeded puts tokens back for a request that succeeded, after its retries took spent tokens.
+func (b *RetryBudget) succeeded(spent int) {
+	if spent == 0 {
+		spent = b.Refill
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.tokens += spent
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
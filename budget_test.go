@@ -0,0 +1,127 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRetryBudget(t *testing.T) {
+	Convey("Given a budget", t, func() {
+		b := NewRetryBudget(12)
+
+		Convey("It starts full", func() {
+			So(b.Available(), ShouldEqual, 12)
+		})
+		Convey("A retry takes its cost", func() {
+			cost, ok := b.take(false)
+			So(ok, ShouldBeTrue)
+			So(cost, ShouldEqual, 5)
+			So(b.Available(), ShouldEqual, 7)
+		})
+		Convey("A retry of a throttled request takes more", func() {
+			cost, ok := b.take(true)
+			So(ok, ShouldBeTrue)
+			So(cost, ShouldEqual, 10)
+			So(b.Available(), ShouldEqual, 2)
+		})
+		Convey("A retry it cannot afford takes nothing", func() {
+			b.take(true)
+			_, ok := b.take(false)
+			So(ok, ShouldBeFalse)
+			So(b.Available(), ShouldEqual, 2)
+		})
+		Convey("A request that succeeds after retries puts back what they took", func() {
+			cost, _ := b.take(true)
+			b.succeeded(cost)
+			So(b.Available(), ShouldEqual, 12)
+		})
+		Convey("A request that succeeds without retries puts back the refill", func() {
+			b.take(false)
+			b.succeeded(0)
+			So(b.Available(), ShouldEqual, 8)
+		})
+		Convey("It never holds more than its capacity", func() {
+			b.succeeded(0)
+			So(b.Available(), ShouldEqual, 12)
+		})
+	})
+
+	Convey("Given requests with a budget to a server that always throttles", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		c := &testCollector{}
+		Metrics = c
+		defer func() { Metrics = nil }()
+
+		b := NewRetryBudget(15)
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.RetryPolicy = RetryPolicy{Backoff: time.Millisecond, Budget: b}
+
+		Convey("Do stops retrying when the budget runs out, and returns the error", func() {
+			_, err := r.Do()
+			So(err, ShouldResemble, throttlingError)
+			So(c.metrics[0].Tries, ShouldEqual, 2)
+			So(c.metrics[0].RetryBudgetExhausted, ShouldBeTrue)
+			So(c.metrics[0].RetryBudgetAvailable, ShouldEqual, 5)
+		})
+		Convey("DoResponse stops retrying when the budget runs out", func() {
+			_, err := r.DoResponse()
+			So(err, ShouldResemble, throttlingError)
+			So(c.metrics[0].Tries, ShouldEqual, 2)
+			So(c.metrics[0].RetryBudgetExhausted, ShouldBeTrue)
+		})
+		Convey("Another request with the budget is not retried at all", func() {
+			r.Do()
+			_, err := r.Do()
+			So(err, ShouldResemble, throttlingError)
+			So(c.metrics[1].Tries, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a request with a budget to a server that throttles once", t, func() {
+		throttled := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !throttled {
+				throttled = true
+				testAWSThrottle(w, r)
+				return
+			}
+			testHTTP200(w, r)
+		}))
+		defer ts.Close()
+
+		b := NewRetryBudget(15)
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.RetryPolicy = RetryPolicy{Backoff: time.Millisecond, Budget: b}
+
+		Convey("The budget is full again when it succeeds", func() {
+			_, err := r.Do()
+			So(err, ShouldBeNil)
+			So(b.Available(), ShouldEqual, 15)
+		})
+	})
+
+	Convey("Given a default budget", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		DefaultRetryBudget = NewRetryBudget(0)
+		defer func() { DefaultRetryBudget = nil }()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("Requests without a budget use it", func() {
+			_, err := r.Do()
+			So(err, ShouldResemble, throttlingError)
+			So(DefaultRetryBudget.Available(), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,102 @@
+package gaws
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithMethod returns a copy of the request with an HTTP method, like GET, PUT or DELETE.
+func (r AWSRequest) WithMethod(method string) AWSRequest {
+	r.Method = method
+	return r
+}
+
+// WithPath returns a copy of the request with a path added to its URL, from a template like the ones in AWS's API documentation:
+//
+//	r.WithPath("/2015-03-31/functions/{FunctionName}/invocations", map[string]string{"FunctionName": name})
+//
+// Each {Name} is replaced by its parameter escaped as one path segment. A {Name+} keeps the slashes in its parameter, like an S3 key.
+// If the template has a name that is not in params, or a brace that is not closed, Do and DoResponse return an error.
+func (r AWSRequest) WithPath(template string, params map[string]string) AWSRequest {
+	path := &strings.Builder{}
+	for {
+		open := strings.Index(template, "{")
+		if open < 0 {
+			path.WriteString(template)
+			break
+		}
+		end := strings.Index(template[open:], "}")
+		if end < 0 {
+			r.err = gawsError{Type: "GawsBadPathTemplate", Message: "The path template has a { that is not closed."}
+			return r
+		}
+		name := template[open+1 : open+end]
+		path.WriteString(template[:open])
+		template = template[open+end+1:]
+
+		greedy := strings.HasSuffix(name, "+")
+		name = strings.TrimSuffix(name, "+")
+		value, ok := params[name]
+		if !ok {
+			r.err = gawsError{Type: "GawsBadPathTemplate", Message: "The path template has a " + name + " parameter, which was not given."}
+			return r
+		}
+		if !greedy {
+			path.WriteString(url.PathEscape(value))
+			continue
+		}
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		path.WriteString(strings.Join(segments, "/"))
+	}
+
+	base, query := r.URL, ""
+	if i := strings.Index(base, "?"); i >= 0 {
+		base, query = base[:i], base[i:]
+	}
+	if strings.HasPrefix(path.String(), "/") {
+		base = strings.TrimSuffix(base, "/")
+	}
+	r.URL = base + path.String() + query
+	return r
+}
+
+// WithQuery returns a copy of the request with query parameters added to its URL. Parameters it already has are kept.
+func (r AWSRequest) WithQuery(query url.Values) AWSRequest {
+	if len(query) == 0 {
+		return r
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	q := u.Query()
+	for name, values := range query {
+		for _, v := range values {
+			q.Add(name, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	r.URL = u.String()
+	return r
+}
+
+// HTTPRequest returns the request as an http.Request that is not signed, with its headers and body, like for Presign.
+func (r AWSRequest) HTTPRequest() (*http.Request, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	req, err := http.NewRequestWithContext(r.context(), r.Method, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
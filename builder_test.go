@@ -0,0 +1,91 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWithPath(t *testing.T) {
+	Convey("Given a request to an endpoint", t, func() {
+		r := AWSRequest{URL: "https://lambda.us-east-1.amazonaws.com/"}
+
+		Convey("A path template is filled in and escaped", func() {
+			r = r.WithPath("/2015-03-31/functions/{FunctionName}/invocations", map[string]string{"FunctionName": "a b/c"})
+			So(r.URL, ShouldEqual, "https://lambda.us-east-1.amazonaws.com/2015-03-31/functions/a%20b%2Fc/invocations")
+		})
+		Convey("A greedy parameter keeps its slashes", func() {
+			r = r.WithPath("/{Bucket}/{Key+}", map[string]string{"Bucket": "logs", "Key": "2015/01/a b.gz"})
+			So(r.URL, ShouldEqual, "https://lambda.us-east-1.amazonaws.com/logs/2015/01/a%20b.gz")
+		})
+		Convey("Paths can be added to a path, before its query", func() {
+			r.URL = "https://s3.amazonaws.com/logs?versioning"
+			r = r.WithPath("/{Key}", map[string]string{"Key": "a"})
+			So(r.URL, ShouldEqual, "https://s3.amazonaws.com/logs/a?versioning")
+		})
+		Convey("A missing parameter is an error when the request is made", func() {
+			r = r.WithPath("/functions/{FunctionName}", map[string]string{})
+			_, err := r.Do()
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "FunctionName")
+			_, err = r.DoResponse()
+			So(err, ShouldNotBeNil)
+			_, err = r.HTTPRequest()
+			So(err, ShouldNotBeNil)
+		})
+		Convey("A brace that is not closed is an error", func() {
+			r = r.WithPath("/functions/{FunctionName", nil)
+			_, err := r.Do()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWithQuery(t *testing.T) {
+	Convey("Given a request with a query", t, func() {
+		r := AWSRequest{URL: "https://s3.amazonaws.com/logs?prefix=2015"}
+
+		Convey("Parameters are added to it", func() {
+			r = r.WithQuery(url.Values{"max-keys": {"10"}, "prefix": {"2016"}})
+			So(r.URL, ShouldEqual, "https://s3.amazonaws.com/logs?max-keys=10&prefix=2015&prefix=2016")
+		})
+		Convey("No parameters leave it unchanged", func() {
+			So(r.WithQuery(nil).URL, ShouldEqual, "https://s3.amazonaws.com/logs?prefix=2015")
+		})
+	})
+}
+
+func TestBuiltRequest(t *testing.T) {
+	Convey("Given a request built with a method, path and query", t, func() {
+		var got *http.Request
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r
+			testHTTP200(w, r)
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r = r.WithMethod("DELETE").WithPath("/vaults/{VaultName}", map[string]string{"VaultName": "logs"}).WithQuery(url.Values{"force": {"true"}})
+		_, err := r.Do()
+
+		Convey("It is sent as built", func() {
+			So(err, ShouldBeNil)
+			So(got.Method, ShouldEqual, "DELETE")
+			So(got.URL.Path, ShouldEqual, "/vaults/logs")
+			So(got.URL.Query().Get("force"), ShouldEqual, "true")
+		})
+		Convey("It can be made into an http.Request for presigning", func() {
+			r.Headers["X-Amz-Glacier-Version"] = "2012-06-01"
+			req, err := r.HTTPRequest()
+			So(err, ShouldBeNil)
+			So(req.Method, ShouldEqual, "DELETE")
+			So(req.URL.String(), ShouldEqual, ts.URL+"/vaults/logs?force=true")
+			So(req.Header.Get("X-Amz-Glacier-Version"), ShouldEqual, "2012-06-01")
+			So(req.Header.Get("Authorization"), ShouldEqual, "")
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package gaws
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Call makes a call to a JSON API, like Kinesis, and decodes the response into a T. r is a request from the service, with its endpoint,
+// headers and retry predicate. target is the X-Amz-Target of the call, like Kinesis_20131202.ListStreams. body is sent as JSON, unless it
+// is nil, when nothing is sent. Calls with no result use struct{} as T, and their response is not decoded. An empty response leaves the T
+// empty. ctx cancels the call, including any wait before a retry.
+func Call[T any](ctx context.Context, r AWSRequest, target string, body interface{}) (T, error) {
+	var result T
+
+	if body != nil {
+		bodyAsJson, err := json.Marshal(body)
+		if err != nil {
+			return result, err
+		}
+		r.Body = bodyAsJson
+	}
+
+	// Copy the headers, so the target is not added to a map the caller still has.
+	headers := make(map[string]string, len(r.Headers)+1)
+	for k, v := range r.Headers {
+		headers[k] = v
+	}
+	headers["X-Amz-Target"] = target
+	r.Headers = headers
+	r.Context = ctx
+
+	resp, err := r.Do()
+	if err != nil {
+		return result, err
+	}
+	if _, ok := interface{}(result).(struct{}); ok || len(resp) == 0 {
+		return result, nil
+	}
+
+	err = DecodeJSON(resp, &result)
+	return result, err
+}
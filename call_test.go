@@ -0,0 +1,89 @@
+package gaws
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCallRequest struct {
+	StreamName string
+}
+
+type testCallResponse struct {
+	ShardCount int
+}
+
+func TestCall(t *testing.T) {
+	Convey("Given a JSON API", t, func() {
+		var target, body string
+		response := `{"ShardCount": 2}`
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			target = r.Header.Get("X-Amz-Target")
+			b, _ := ioutil.ReadAll(r.Body)
+			body = string(b)
+			w.Write([]byte(response))
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.Method = "POST"
+		r.URL = ts.URL
+
+		Convey("Call sends the body to the target and decodes the response", func() {
+			result, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", testCallRequest{StreamName: "test"})
+			So(err, ShouldBeNil)
+			So(result.ShardCount, ShouldEqual, 2)
+			So(target, ShouldEqual, "Test_20150101.Describe")
+			So(body, ShouldEqual, `{"StreamName":"test"}`)
+		})
+		Convey("It does not change the request's headers", func() {
+			_, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", nil)
+			So(err, ShouldBeNil)
+			So(r.Headers, ShouldBeEmpty)
+		})
+		Convey("A nil body sends nothing", func() {
+			_, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", nil)
+			So(err, ShouldBeNil)
+			So(body, ShouldEqual, "")
+		})
+		Convey("An empty response leaves the result empty", func() {
+			response = ""
+			result, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Delete", nil)
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, testCallResponse{})
+		})
+		Convey("The response to a call with no result is not decoded", func() {
+			response = "OK"
+			_, err := Call[struct{}](context.Background(), r, "Test_20150101.Delete", nil)
+			So(err, ShouldBeNil)
+		})
+		Convey("A body that is not JSON is an error", func() {
+			response = "not json"
+			_, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", nil)
+			So(err, ShouldNotBeNil)
+		})
+		Convey("A body that cannot be sent as JSON is an error, and is not sent", func() {
+			_, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", make(chan int))
+			So(err, ShouldNotBeNil)
+			So(target, ShouldEqual, "")
+		})
+	})
+
+	Convey("Given a JSON API that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("Call returns the error", func() {
+			_, err := Call[testCallResponse](context.Background(), r, "Test_20150101.Describe", nil)
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
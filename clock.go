@@ -0,0 +1,35 @@
+package gaws
+
+import (
+	"time"
+)
+
+// Clock tells the time and waits. gaws waits with it between retries, and service packages wait with it in waiters and before retrying
+// the parts of a batch that failed, so tests can use one that does not really wait, like a gawstest.Clock.
+// A Clock is used from every goroutine making requests, so it must be safe for concurrent use.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time // Like time.After
+}
+
+// systemClock is the real time.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration, then sends the time on the channel.
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// DefaultClock is the clock gaws and its service packages use, except for clients made with WithClock. It is the real time unless a
+// test changes it.
+var DefaultClock Clock = systemClock{}
+
+// Sleep waits for the duration on DefaultClock. Service packages use it instead of time.Sleep.
+func Sleep(d time.Duration) {
+	<-DefaultClock.After(d)
+}
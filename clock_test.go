@@ -0,0 +1,81 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testClock is a Clock that does not really wait, like gawstest.Clock, which tests of this package cannot import.
+type testClock struct {
+	lock   sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (c *testClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *testClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// useTestClock makes DefaultClock a testClock, and returns it and a func that puts the old clock back.
+func useTestClock() (*testClock, func()) {
+	old := DefaultClock
+	c := &testClock{now: time.Now()}
+	DefaultClock = c
+	return c, func() { DefaultClock = old }
+}
+
+func TestSleep(t *testing.T) {
+	Convey("Given a clock", t, func() {
+		c, restore := useTestClock()
+		defer restore()
+		start := c.Now()
+
+		Convey("Sleep waits on it", func() {
+			Sleep(time.Minute)
+			So(c.sleeps, ShouldResemble, []time.Duration{time.Minute})
+			So(c.Now(), ShouldEqual, start.Add(time.Minute))
+		})
+	})
+	Convey("Given the default clock", t, func() {
+		Convey("It is the real time", func() {
+			So(DefaultClock.Now(), ShouldHappenWithin, time.Second, time.Now())
+		})
+	})
+}
+
+func TestRetryClock(t *testing.T) {
+	Convey("Given a clock and a request that is always throttled", t, func() {
+		c, restore := useTestClock()
+		defer restore()
+
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		_, err := r.Do()
+
+		Convey("It backs off on the clock", func() {
+			So(err, ShouldEqual, exceededRetriesError)
+			So(c.sleeps, ShouldResemble, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, 1600 * time.Millisecond})
+		})
+	})
+}
@@ -0,0 +1,112 @@
+// Package cloudformation provides a way to interact with the AWS CloudFormation service.
+package cloudformation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type cloudFormationError -field Code -service CloudFormation ValidationError AlreadyExistsException LimitExceededException InsufficientCapabilitiesException TokenAlreadyExistsException
+
+// cloudFormationError is the error document returned from the CloudFormation service.
+type cloudFormationError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the cloudFormationError into an error message.
+func (e cloudFormationError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+var waiterTimeoutError = cloudFormationError{Code: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks.
+var WaitInterval = 15 * time.Second
+
+// MaxWaits is the number of times a waiter checks before giving up. Stacks can take a long time, so it is high.
+var MaxWaits = 240
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *CloudFormationService) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func cloudFormationRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := cloudFormationError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// CloudFormationService is the CloudFormation service at AWS. The endpoint for a region is gaws.Endpoint("cloudformation", region).
+type CloudFormationService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// CloudFormationAPI is implemented by *CloudFormationService. Code that takes a CloudFormationAPI can be given a fake in tests instead.
+type CloudFormationAPI interface {
+	CreateStack(input StackInput) (string, error)
+	DeleteStack(name string) error
+	DescribeStack(name string) (Stack, error)
+	DescribeStackEvents(name string) ([]StackEvent, error)
+	DescribeStacks(name string) ([]Stack, error)
+	UpdateStack(input StackInput) (string, error)
+	WaitUntilCreateComplete(name string) (Stack, error)
+	WaitUntilDeleteComplete(name string) error
+	WaitUntilUpdateComplete(name string) (Stack, error)
+}
+
+var _ CloudFormationAPI = (*CloudFormationService)(nil)
+
+// New returns a CloudFormationService with the options applied. Its endpoint is gaws.Endpoint("cloudformation", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *CloudFormationService {
+	config := gaws.NewConfig(options...)
+	return &CloudFormationService{Endpoint: config.ServiceEndpoint("cloudformation"), config: config}
+}
+
+func (s *CloudFormationService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: cloudFormationRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("cloudformation", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for a CloudFormation API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-05-15"},
+	}
+}
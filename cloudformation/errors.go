@@ -0,0 +1,18 @@
+// Code generated by errgen from the go:generate line in cloudformation.go. DO NOT EDIT.
+
+package cloudformation
+
+// These are errors CloudFormation returns. errors.Is(err, cloudformation.ErrValidation) is true for any error with the same code, whatever its message.
+var (
+	ErrValidation               = cloudFormationError{Code: "ValidationError"}
+	ErrAlreadyExists            = cloudFormationError{Code: "AlreadyExistsException"}
+	ErrLimitExceeded            = cloudFormationError{Code: "LimitExceededException"}
+	ErrInsufficientCapabilities = cloudFormationError{Code: "InsufficientCapabilitiesException"}
+	ErrTokenAlreadyExists       = cloudFormationError{Code: "TokenAlreadyExistsException"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e cloudFormationError) Is(target error) bool {
+	t, ok := target.(cloudFormationError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,58 @@
+package cloudformation
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// StackEvent is something that happened to a stack or one of its resources.
+type StackEvent struct {
+	EventId              string
+	StackId              string
+	StackName            string
+	LogicalResourceId    string // The name of the resource in the template
+	PhysicalResourceId   string // The name or ID of the resource AWS created
+	ResourceType         string // Like AWS::Kinesis::Stream
+	ResourceStatus       string // Like CREATE_FAILED
+	ResourceStatusReason string // Why the status changed, like why creating the resource failed
+	Timestamp            time.Time
+}
+
+type describeStackEventsResponse struct {
+	StackEvents []StackEvent `xml:"DescribeStackEventsResult>StackEvents>member"`
+	NextToken   string       `xml:"DescribeStackEventsResult>NextToken"`
+}
+
+// DescribeStackEvents describes what happened to a stack, by name or ID, newest first.
+// It is calling the DescribeStackEvents API call until there are no more pages.
+// See http://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_DescribeStackEvents.html for more details.
+func (s *CloudFormationService) DescribeStackEvents(name string) ([]StackEvent, error) {
+	events := []StackEvent{}
+
+	params := query("DescribeStackEvents")
+	params.Set("StackName", name)
+
+	for {
+		result := describeStackEventsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []StackEvent{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []StackEvent{}, err
+		}
+
+		events = append(events, result.StackEvents...)
+
+		if result.NextToken == "" {
+			return events, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
@@ -0,0 +1,69 @@
+package cloudformation
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDescribeStackEvents(t *testing.T) {
+	Convey("Given events that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<DescribeStackEventsResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <DescribeStackEventsResult>
+    <StackEvents>
+      <member>
+        <EventId>Event-1-Id</EventId>
+        <StackId>`+testStackId+`</StackId>
+        <StackName>workers</StackName>
+        <LogicalResourceId>Stream</LogicalResourceId>
+        <PhysicalResourceId>workers-Stream-1AB2CD3EF4GH</PhysicalResourceId>
+        <ResourceType>AWS::Kinesis::Stream</ResourceType>
+        <Timestamp>2015-05-11T17:06:00.000Z</Timestamp>
+        <ResourceStatus>CREATE_FAILED</ResourceStatus>
+        <ResourceStatusReason>Shard limit exceeded</ResourceStatusReason>
+      </member>
+    </StackEvents>
+    <NextToken>page2</NextToken>
+  </DescribeStackEventsResult>
+</DescribeStackEventsResponse>`,
+			`<DescribeStackEventsResponse><DescribeStackEventsResult><StackEvents><member><EventId>Event-2-Id</EventId></member></StackEvents></DescribeStackEventsResult></DescribeStackEventsResponse>`,
+		), &params)
+		defer ts.Close()
+
+		events, err := s.DescribeStackEvents("workers")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(events, ShouldResemble, []StackEvent{
+				{
+					EventId:              "Event-1-Id",
+					StackId:              testStackId,
+					StackName:            "workers",
+					LogicalResourceId:    "Stream",
+					PhysicalResourceId:   "workers-Stream-1AB2CD3EF4GH",
+					ResourceType:         "AWS::Kinesis::Stream",
+					ResourceStatus:       "CREATE_FAILED",
+					ResourceStatusReason: "Shard limit exceeded",
+					Timestamp:            time.Date(2015, 5, 11, 17, 6, 0, 0, time.UTC),
+				},
+				{EventId: "Event-2-Id"},
+			})
+			So(params[0].Get("StackName"), ShouldEqual, "workers")
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeStackEvents("workers")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, stackDoesNotExistError)
+		})
+	})
+}
@@ -0,0 +1,304 @@
+package cloudformation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The capabilities a template can need to be acknowledged.
+const (
+	CapabilityIAM      = "CAPABILITY_IAM"       // The template creates IAM resources
+	CapabilityNamedIAM = "CAPABILITY_NAMED_IAM" // The template creates IAM resources with custom names
+)
+
+// What CreateStack does when creating a stack fails.
+const (
+	DoNothing = "DO_NOTHING"
+	Rollback  = "ROLLBACK"
+	Delete    = "DELETE"
+)
+
+// Parameter is a value for a template parameter.
+type Parameter struct {
+	ParameterKey     string
+	ParameterValue   string
+	UsePreviousValue bool // When updating, keep the value the stack has instead of ParameterValue
+}
+
+// Tag is a tag on a stack. It is also added to the resources in the stack.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Output is a value a stack outputs, like the name of a stream it created.
+type Output struct {
+	OutputKey   string
+	OutputValue string
+	Description string
+}
+
+// StackInput describes a stack to create or update.
+type StackInput struct {
+	StackName        string
+	TemplateBody     string // The template. Either it or TemplateURL has to be set.
+	TemplateURL      string // The URL of a template in S3
+	Parameters       []Parameter
+	Capabilities     []string // The capabilities to acknowledge, like CapabilityIAM
+	Tags             []Tag
+	TimeoutInMinutes int    // How long creating the stack can take, if it is not 0. It is only used by CreateStack.
+	OnFailure        string // One of DoNothing, Rollback or Delete. It is only used by CreateStack. If it is empty, the stack is rolled back.
+
+	// ClientRequestToken makes retries of the call safe, and is given to the stack events the call causes. If it is empty, a new one is
+	// made with gaws.NewIdempotencyToken. Set it to retry a call that failed, so that CloudFormation does it once.
+	ClientRequestToken string
+}
+
+func (input StackInput) setParams(params url.Values) error {
+	if err := setClientRequestToken(params, input.ClientRequestToken); err != nil {
+		return err
+	}
+	params.Set("StackName", input.StackName)
+	if input.TemplateBody != "" {
+		params.Set("TemplateBody", input.TemplateBody)
+	}
+	if input.TemplateURL != "" {
+		params.Set("TemplateURL", input.TemplateURL)
+	}
+	for i, parameter := range input.Parameters {
+		prefix := fmt.Sprintf("Parameters.member.%d.", i+1)
+		params.Set(prefix+"ParameterKey", parameter.ParameterKey)
+		if parameter.UsePreviousValue {
+			params.Set(prefix+"UsePreviousValue", "true")
+		} else {
+			params.Set(prefix+"ParameterValue", parameter.ParameterValue)
+		}
+	}
+	for i, capability := range input.Capabilities {
+		params.Set(fmt.Sprintf("Capabilities.member.%d", i+1), capability)
+	}
+	for i, tag := range input.Tags {
+		prefix := fmt.Sprintf("Tags.member.%d.", i+1)
+		params.Set(prefix+"Key", tag.Key)
+		params.Set(prefix+"Value", tag.Value)
+	}
+	return nil
+}
+
+// setClientRequestToken sets the ClientRequestToken parameter to the token, or to a new one if it is empty.
+func setClientRequestToken(params url.Values, token string) error {
+	if token == "" {
+		var err error
+		token, err = gaws.NewIdempotencyToken()
+		if err != nil {
+			return err
+		}
+	}
+	params.Set("ClientRequestToken", token)
+	return nil
+}
+
+type createStackResponse struct {
+	StackId string `xml:"CreateStackResult>StackId"`
+}
+
+// CreateStack starts creating a stack, and returns its ID. Use WaitUntilCreateComplete to know when it is done.
+// It is calling the CreateStack API call.
+// See http://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_CreateStack.html for more details.
+func (s *CloudFormationService) CreateStack(input StackInput) (string, error) {
+	result := createStackResponse{}
+
+	params := query("CreateStack")
+	if err := input.setParams(params); err != nil {
+		return "", err
+	}
+	if input.TimeoutInMinutes > 0 {
+		params.Set("TimeoutInMinutes", fmt.Sprint(input.TimeoutInMinutes))
+	}
+	if input.OnFailure != "" {
+		params.Set("OnFailure", input.OnFailure)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.StackId, err
+}
+
+type updateStackResponse struct {
+	StackId string `xml:"UpdateStackResult>StackId"`
+}
+
+// UpdateStack starts updating a stack, and returns its ID. Use WaitUntilUpdateComplete to know when it is done.
+// If nothing would change, it returns a ValidationError saying no updates are to be performed.
+// It is calling the UpdateStack API call.
+// See http://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_UpdateStack.html for more details.
+func (s *CloudFormationService) UpdateStack(input StackInput) (string, error) {
+	result := updateStackResponse{}
+
+	params := query("UpdateStack")
+	if err := input.setParams(params); err != nil {
+		return "", err
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.StackId, err
+}
+
+// DeleteStack starts deleting a stack. Use WaitUntilDeleteComplete to know when it is done. It is calling the DeleteStack API call.
+// It sends a new ClientRequestToken, so that retries of the request do not delete a stack of the same name that was made since.
+// See http://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_DeleteStack.html for more details.
+func (s *CloudFormationService) DeleteStack(name string) error {
+	params := query("DeleteStack")
+	params.Set("StackName", name)
+	if err := setClientRequestToken(params, ""); err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
+
+// Stack is a CloudFormation stack.
+type Stack struct {
+	StackId           string
+	StackName         string
+	Description       string
+	StackStatus       string // Like CREATE_COMPLETE or UPDATE_ROLLBACK_IN_PROGRESS
+	StackStatusReason string
+	CreationTime      time.Time
+	LastUpdatedTime   time.Time
+	Parameters        []Parameter `xml:"Parameters>member"`
+	Outputs           []Output    `xml:"Outputs>member"`
+	Capabilities      []string    `xml:"Capabilities>member"`
+	Tags              []Tag       `xml:"Tags>member"`
+}
+
+// Output returns the value of one of the stack's outputs, or "" if it does not have the output.
+func (s Stack) Output(key string) string {
+	for _, output := range s.Outputs {
+		if output.OutputKey == key {
+			return output.OutputValue
+		}
+	}
+	return ""
+}
+
+type describeStacksResponse struct {
+	Stacks    []Stack `xml:"DescribeStacksResult>Stacks>member"`
+	NextToken string  `xml:"DescribeStacksResult>NextToken"`
+}
+
+// DescribeStacks describes a stack, by name or ID. If name is empty, every stack that has not been deleted is described.
+// It is calling the DescribeStacks API call until there are no more pages.
+// See http://docs.aws.amazon.com/AWSCloudFormation/latest/APIReference/API_DescribeStacks.html for more details.
+func (s *CloudFormationService) DescribeStacks(name string) ([]Stack, error) {
+	stacks := []Stack{}
+
+	params := query("DescribeStacks")
+	if name != "" {
+		params.Set("StackName", name)
+	}
+
+	for {
+		result := describeStacksResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Stack{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Stack{}, err
+		}
+
+		stacks = append(stacks, result.Stacks...)
+
+		if result.NextToken == "" {
+			return stacks, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
+
+// DescribeStack describes a stack, by name or ID.
+func (s *CloudFormationService) DescribeStack(name string) (Stack, error) {
+	stacks, err := s.DescribeStacks(name)
+	if err != nil {
+		return Stack{}, err
+	}
+	if len(stacks) == 0 {
+		return Stack{}, cloudFormationError{Code: "ValidationError", Message: fmt.Sprintf("Stack with id %v does not exist", name)}
+	}
+	return stacks[0], nil
+}
+
+// waitForStatus waits for a stack to reach status. It fails if the stack stops in another status, like ROLLBACK_COMPLETE.
+func (s *CloudFormationService) waitForStatus(name string, status string) (Stack, error) {
+	var stack Stack
+
+	err := s.wait(func() (bool, error) {
+		var err error
+		stack, err = s.DescribeStack(name)
+		if err != nil {
+			return false, err
+		}
+
+		if stack.StackStatus == status {
+			return true, nil
+		}
+		if strings.HasSuffix(stack.StackStatus, "_IN_PROGRESS") {
+			return false, nil
+		}
+		return false, cloudFormationError{Code: "GawsStackFailed", Message: fmt.Sprintf("Stack %v is %v: %v", name, stack.StackStatus, stack.StackStatusReason)}
+	})
+	return stack, err
+}
+
+// WaitUntilCreateComplete checks a stack every WaitInterval until it is CREATE_COMPLETE. It returns the stack, so its outputs can be read.
+// It fails if creating the stack fails, and gives up after MaxWaits checks.
+func (s *CloudFormationService) WaitUntilCreateComplete(name string) (Stack, error) {
+	return s.waitForStatus(name, "CREATE_COMPLETE")
+}
+
+// WaitUntilUpdateComplete checks a stack every WaitInterval until it is UPDATE_COMPLETE. It returns the stack, so its outputs can be read.
+// It fails if updating the stack fails, and gives up after MaxWaits checks.
+func (s *CloudFormationService) WaitUntilUpdateComplete(name string) (Stack, error) {
+	return s.waitForStatus(name, "UPDATE_COMPLETE")
+}
+
+// WaitUntilDeleteComplete checks a stack every WaitInterval until it is deleted. name should be the stack's ID,
+// because deleted stacks can only be described by ID. It fails if deleting the stack fails, and gives up after MaxWaits checks.
+func (s *CloudFormationService) WaitUntilDeleteComplete(name string) error {
+	_, err := s.waitForStatus(name, "DELETE_COMPLETE")
+	if e, ok := err.(cloudFormationError); ok && e.Code == "ValidationError" && strings.Contains(e.Message, "does not exist") {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,344 @@
+package cloudformation
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testStackId = "arn:aws:cloudformation:us-east-1:123456789012:stack/workers/aaf549a0-a413-11df-adb3-5081b3858e83"
+
+// testStack returns a DescribeStacks response for a stack with the status.
+func testStack(status string) string {
+	return fmt.Sprintf(`<DescribeStacksResponse xmlns="http://cloudformation.amazonaws.com/doc/2010-05-15/">
+  <DescribeStacksResult>
+    <Stacks>
+      <member>
+        <StackName>workers</StackName>
+        <StackId>%v</StackId>
+        <Description>Kinesis workers</Description>
+        <Parameters>
+          <member>
+            <ParameterKey>ShardCount</ParameterKey>
+            <ParameterValue>4</ParameterValue>
+          </member>
+        </Parameters>
+        <CreationTime>2015-05-11T17:05:00.000Z</CreationTime>
+        <StackStatus>%v</StackStatus>
+        <StackStatusReason>Resource creation cancelled</StackStatusReason>
+        <Capabilities>
+          <member>CAPABILITY_IAM</member>
+        </Capabilities>
+        <Outputs>
+          <member>
+            <OutputKey>StreamName</OutputKey>
+            <OutputValue>workers-Stream-1AB2CD3EF4GH</OutputValue>
+          </member>
+        </Outputs>
+        <Tags>
+          <member>
+            <Key>team</Key>
+            <Value>data</Value>
+          </member>
+        </Tags>
+      </member>
+    </Stacks>
+  </DescribeStacksResult>
+</DescribeStacksResponse>`, testStackId, status)
+}
+
+func testStackStatus(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testStack(status)))
+	}
+}
+
+func TestCreateStack(t *testing.T) {
+	Convey("Given a stack", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<CreateStackResponse><CreateStackResult><StackId>`+testStackId+`</StackId></CreateStackResult></CreateStackResponse>`, ""), &params)
+		defer ts.Close()
+
+		id, err := s.CreateStack(StackInput{
+			StackName:          "workers",
+			TemplateBody:       `{"Resources":{}}`,
+			Parameters:         []Parameter{{ParameterKey: "ShardCount", ParameterValue: "4"}},
+			Capabilities:       []string{CapabilityIAM},
+			Tags:               []Tag{{Key: "team", Value: "data"}},
+			TimeoutInMinutes:   30,
+			OnFailure:          Delete,
+			ClientRequestToken: "create-workers-1",
+		})
+
+		Convey("It returns the stack's ID", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, testStackId)
+		})
+		Convey("It sends the stack", func() {
+			So(params[0], ShouldResemble, url.Values{
+				"Action":                             {"CreateStack"},
+				"Version":                            {"2010-05-15"},
+				"StackName":                          {"workers"},
+				"TemplateBody":                       {`{"Resources":{}}`},
+				"Parameters.member.1.ParameterKey":   {"ShardCount"},
+				"Parameters.member.1.ParameterValue": {"4"},
+				"Capabilities.member.1":              {"CAPABILITY_IAM"},
+				"Tags.member.1.Key":                  {"team"},
+				"Tags.member.1.Value":                {"data"},
+				"TimeoutInMinutes":                   {"30"},
+				"OnFailure":                          {"DELETE"},
+				"ClientRequestToken":                 {"create-workers-1"},
+			})
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.CreateStack(StackInput{StackName: "workers"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUpdateStack(t *testing.T) {
+	Convey("Given a stack with a template in S3", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<UpdateStackResponse><UpdateStackResult><StackId>`+testStackId+`</StackId></UpdateStackResult></UpdateStackResponse>`, ""), &params)
+		defer ts.Close()
+
+		id, err := s.UpdateStack(StackInput{
+			StackName:        "workers",
+			TemplateURL:      "https://s3.amazonaws.com/templates/workers.json",
+			Parameters:       []Parameter{{ParameterKey: "ShardCount", UsePreviousValue: true}},
+			TimeoutInMinutes: 30,
+		})
+
+		Convey("It returns the stack's ID", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, testStackId)
+		})
+		Convey("It sends the stack, without the create options", func() {
+			So(len(params[0].Get("ClientRequestToken")), ShouldEqual, 36)
+			params[0].Del("ClientRequestToken")
+			So(params[0], ShouldResemble, url.Values{
+				"Action":                               {"UpdateStack"},
+				"Version":                              {"2010-05-15"},
+				"StackName":                            {"workers"},
+				"TemplateURL":                          {"https://s3.amazonaws.com/templates/workers.json"},
+				"Parameters.member.1.ParameterKey":     {"ShardCount"},
+				"Parameters.member.1.UsePreviousValue": {"true"},
+			})
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.UpdateStack(StackInput{StackName: "workers"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, stackDoesNotExistError)
+		})
+	})
+}
+
+func TestDeleteStack(t *testing.T) {
+	Convey("Given a stack", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.DeleteStack("workers")
+
+		Convey("It is deleted", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "DeleteStack")
+			So(params[0].Get("StackName"), ShouldEqual, "workers")
+			So(len(params[0].Get("ClientRequestToken")), ShouldEqual, 36)
+		})
+	})
+	Convey("Given a stack and a server that fails the first request", t, func() {
+		params := []url.Values{}
+		failed := false
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if !failed {
+				failed = true
+				w.WriteHeader(500)
+				w.Write([]byte(`<ErrorResponse><Error><Type>Receiver</Type><Code>InternalFailure</Code></Error></ErrorResponse>`))
+				return
+			}
+			testHTTP200(w, r)
+		}, &params)
+		defer ts.Close()
+
+		err := s.DeleteStack("workers")
+
+		Convey("The retry sends the same token", func() {
+			So(err, ShouldBeNil)
+			So(len(params), ShouldEqual, 2)
+			So(params[1].Get("ClientRequestToken"), ShouldEqual, params[0].Get("ClientRequestToken"))
+		})
+	})
+}
+
+func TestDescribeStacks(t *testing.T) {
+	Convey("Given stacks that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<DescribeStacksResponse><DescribeStacksResult><Stacks><member><StackName>workers</StackName></member></Stacks><NextToken>page2</NextToken></DescribeStacksResult></DescribeStacksResponse>`,
+			`<DescribeStacksResponse><DescribeStacksResult><Stacks><member><StackName>tables</StackName></member></Stacks></DescribeStacksResult></DescribeStacksResponse>`,
+		), &params)
+		defer ts.Close()
+
+		stacks, err := s.DescribeStacks("")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(stacks), ShouldEqual, 2)
+			So(stacks[1].StackName, ShouldEqual, "tables")
+			_, hasName := params[0]["StackName"]
+			So(hasName, ShouldBeFalse)
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+	})
+}
+
+func TestDescribeStack(t *testing.T) {
+	Convey("Given a stack", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testStackStatus("CREATE_COMPLETE"), &params)
+		defer ts.Close()
+
+		stack, err := s.DescribeStack("workers")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(stack, ShouldResemble, Stack{
+				StackId:           testStackId,
+				StackName:         "workers",
+				Description:       "Kinesis workers",
+				StackStatus:       "CREATE_COMPLETE",
+				StackStatusReason: "Resource creation cancelled",
+				CreationTime:      time.Date(2015, 5, 11, 17, 5, 0, 0, time.UTC),
+				Parameters:        []Parameter{{ParameterKey: "ShardCount", ParameterValue: "4"}},
+				Outputs:           []Output{{OutputKey: "StreamName", OutputValue: "workers-Stream-1AB2CD3EF4GH"}},
+				Capabilities:      []string{CapabilityIAM},
+				Tags:              []Tag{{Key: "team", Value: "data"}},
+			})
+			So(params[0].Get("StackName"), ShouldEqual, "workers")
+		})
+		Convey("Its outputs can be looked up", func() {
+			So(stack.Output("StreamName"), ShouldEqual, "workers-Stream-1AB2CD3EF4GH")
+			So(stack.Output("TableName"), ShouldEqual, "")
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeStack("workers")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, stackDoesNotExistError)
+		})
+	})
+}
+
+func TestStackWaiters(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a stack that is being created", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 3 {
+				testStackStatus("CREATE_IN_PROGRESS")(w, r)
+			} else {
+				testStackStatus("CREATE_COMPLETE")(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		stack, err := s.WaitUntilCreateComplete("workers")
+
+		Convey("It waits until the stack is created", func() {
+			So(err, ShouldBeNil)
+			So(describes, ShouldEqual, 3)
+			So(stack.Output("StreamName"), ShouldEqual, "workers-Stream-1AB2CD3EF4GH")
+		})
+	})
+	Convey("Given a stack that fails to be created", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 2 {
+				testStackStatus("ROLLBACK_IN_PROGRESS")(w, r)
+			} else {
+				testStackStatus("ROLLBACK_COMPLETE")(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		_, err := s.WaitUntilCreateComplete("workers")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, cloudFormationError{Code: "GawsStackFailed", Message: "Stack workers is ROLLBACK_COMPLETE: Resource creation cancelled"})
+		})
+	})
+	Convey("Given a stack that is being updated", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 2 {
+				testStackStatus("UPDATE_COMPLETE_CLEANUP_IN_PROGRESS")(w, r)
+			} else {
+				testStackStatus("UPDATE_COMPLETE")(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		_, err := s.WaitUntilUpdateComplete("workers")
+
+		Convey("It waits until the stack is updated", func() {
+			So(err, ShouldBeNil)
+			So(describes, ShouldEqual, 2)
+		})
+	})
+	Convey("Given a stack that is being deleted", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 2 {
+				testStackStatus("DELETE_IN_PROGRESS")(w, r)
+			} else {
+				testHTTP400(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		err := s.WaitUntilDeleteComplete("workers")
+
+		Convey("It waits until the stack does not exist", func() {
+			So(err, ShouldBeNil)
+			So(describes, ShouldEqual, 2)
+		})
+	})
+	Convey("Given a stack that is never created", t, func() {
+		ts, s := testService(testStackStatus("CREATE_IN_PROGRESS"), nil)
+		defer ts.Close()
+
+		_, err := s.WaitUntilCreateComplete("workers")
+
+		Convey("It gives up", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+}
@@ -0,0 +1,87 @@
+// Package cloudtrail provides a way to interact with the AWS CloudTrail service.
+package cloudtrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type cloudTrailError -field Type -service CloudTrail TrailNotFoundException TrailAlreadyExistsException InvalidTrailNameException S3BucketDoesNotExistException InsufficientS3BucketPolicyException
+
+// cloudTrailError is the error document returned from the CloudTrail service.
+type cloudTrailError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the cloudTrailError into an error message.
+func (e cloudTrailError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e cloudTrailError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func cloudTrailRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := cloudTrailError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	// LookupEvents only takes two requests a second.
+	if error.is("ThrottlingException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// CloudTrailService is the CloudTrail service at AWS. The endpoint for a region is gaws.Endpoint("cloudtrail", region).
+type CloudTrailService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// CloudTrailAPI is implemented by *CloudTrailService. Code that takes a CloudTrailAPI can be given a fake in tests instead.
+type CloudTrailAPI interface {
+	LookupEvents(q EventQuery) ([]Event, error)
+}
+
+var _ CloudTrailAPI = (*CloudTrailService)(nil)
+
+// New returns a CloudTrailService with the options applied. Its endpoint is gaws.Endpoint("cloudtrail", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *CloudTrailService {
+	config := gaws.NewConfig(options...)
+	return &CloudTrailService{Endpoint: config.ServiceEndpoint("cloudtrail"), config: config}
+}
+
+func (s *CloudTrailService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: cloudTrailRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("cloudtrail", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
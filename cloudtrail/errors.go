@@ -0,0 +1,20 @@
+// Code generated by errgen from the go:generate line in cloudtrail.go. DO NOT EDIT.
+
+package cloudtrail
+
+import "strings"
+
+// These are errors CloudTrail returns. errors.Is(err, cloudtrail.ErrTrailNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrTrailNotFound              = cloudTrailError{Type: "TrailNotFoundException"}
+	ErrTrailAlreadyExists         = cloudTrailError{Type: "TrailAlreadyExistsException"}
+	ErrInvalidTrailName           = cloudTrailError{Type: "InvalidTrailNameException"}
+	ErrS3BucketDoesNotExist       = cloudTrailError{Type: "S3BucketDoesNotExistException"}
+	ErrInsufficientS3BucketPolicy = cloudTrailError{Type: "InsufficientS3BucketPolicyException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e cloudTrailError) Is(target error) bool {
+	t, ok := target.(cloudTrailError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
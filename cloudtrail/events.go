@@ -0,0 +1,121 @@
+package cloudtrail
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The attributes events can be looked up by.
+const (
+	EventId      = "EventId"
+	EventName    = "EventName" // The API call, like PutRecord
+	Username     = "Username"
+	ResourceType = "ResourceType" // Like AWS::DynamoDB::Table
+	ResourceName = "ResourceName" // Like the name or ARN of a stream or table
+	EventSource  = "EventSource"  // The service, like kinesis.amazonaws.com
+)
+
+// LookupAttribute is an attribute to look up events by, like the ResourceName of a stream.
+type LookupAttribute struct {
+	AttributeKey   string
+	AttributeValue string
+}
+
+// EventQuery says which events LookupEvents finds. CloudTrail keeps events for 90 days.
+type EventQuery struct {
+	Attribute LookupAttribute // If its key is empty, every event is found
+	Start     time.Time       // If it is zero, events are found from 90 days ago
+	End       time.Time       // If it is zero, events are found up to now
+}
+
+// ByResource returns a query for the events that touched the named resource, like a stream or table.
+func ByResource(name string) EventQuery {
+	return EventQuery{Attribute: LookupAttribute{AttributeKey: ResourceName, AttributeValue: name}}
+}
+
+// ByUsername returns a query for the events made by the user, like an IAM user name or the session name of a role.
+func ByUsername(name string) EventQuery {
+	return EventQuery{Attribute: LookupAttribute{AttributeKey: Username, AttributeValue: name}}
+}
+
+// Resource is a resource an event touched.
+type Resource struct {
+	ResourceType string
+	ResourceName string
+}
+
+// Event is an API call CloudTrail recorded.
+type Event struct {
+	EventId         string
+	EventName       string
+	EventTime       float64 // Seconds since the epoch
+	Username        string
+	Resources       []Resource
+	CloudTrailEvent string // The whole event, as JSON
+}
+
+// Time returns the event's EventTime as a time.Time.
+func (e Event) Time() time.Time {
+	return time.Unix(0, int64(e.EventTime*float64(time.Second)))
+}
+
+type lookupEventsRequest struct {
+	LookupAttributes []LookupAttribute `json:",omitempty"`
+	StartTime        int64             `json:",omitempty"` // Seconds since the epoch
+	EndTime          int64             `json:",omitempty"`
+	NextToken        string            `json:",omitempty"`
+}
+
+type lookupEventsResult struct {
+	Events    []Event
+	NextToken string
+}
+
+// LookupEvents finds the events that match the query, newest first. It is calling the LookupEvents API call until there are no more pages.
+// CloudTrail only takes two lookups a second, so finding many events is slow.
+// See http://docs.aws.amazon.com/awscloudtrail/latest/APIReference/API_LookupEvents.html for more details.
+func (s *CloudTrailService) LookupEvents(q EventQuery) ([]Event, error) {
+	events := []Event{}
+
+	body := lookupEventsRequest{}
+	if q.Attribute.AttributeKey != "" {
+		body.LookupAttributes = []LookupAttribute{q.Attribute}
+	}
+	if !q.Start.IsZero() {
+		body.StartTime = q.Start.Unix()
+	}
+	if !q.End.IsZero() {
+		body.EndTime = q.End.Unix()
+	}
+
+	for {
+		result := lookupEventsResult{}
+
+		bodyAsJson, err := json.Marshal(body)
+		if err != nil {
+			return events, err
+		}
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "com.amazonaws.cloudtrail.v20131101.CloudTrail_20131101.LookupEvents"
+
+		resp, err := req.Do()
+		if err != nil {
+			return events, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, result.Events...)
+
+		if result.NextToken == "" {
+			return events, nil
+		}
+		body.NextToken = result.NextToken
+	}
+}
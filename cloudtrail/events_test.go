@@ -0,0 +1,107 @@
+package cloudtrail
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEvent(t *testing.T) {
+	Convey("Given an event", t, func() {
+		e := Event{EventTime: 1438000000.5}
+
+		Convey("Its time is converted", func() {
+			So(e.Time().Equal(time.Date(2015, 7, 27, 12, 26, 40, 500000000, time.UTC)), ShouldBeTrue)
+		})
+	})
+}
+
+func TestLookupEvents(t *testing.T) {
+	Convey("Given events for a resource that take two pages", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			request := lookupEventsRequest{}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.NextToken == "page2" {
+				w.Write([]byte(`{"Events":[{"EventId":"2","EventName":"CreateStream","EventTime":1438000000,"Username":"deploy"}]}`))
+				return
+			}
+			w.Write([]byte(`{"Events":[{"EventId":"1","EventName":"DeleteStream","EventTime":1438003600,"Username":"web-1",
+				"Resources":[{"ResourceType":"AWS::Kinesis::Stream","ResourceName":"payments"}],"CloudTrailEvent":"{\"eventVersion\":\"1.03\"}"}],
+				"NextToken":"page2"}`))
+		})
+		defer ts.Close()
+
+		q := ByResource("payments")
+		q.Start = time.Unix(1437000000, 0)
+		events, err := s.LookupEvents(q)
+
+		Convey("Every page is returned", func() {
+			So(err, ShouldBeNil)
+			So(len(events), ShouldEqual, 2)
+			So(events[0], ShouldResemble, Event{
+				EventId:         "1",
+				EventName:       "DeleteStream",
+				EventTime:       1438003600,
+				Username:        "web-1",
+				Resources:       []Resource{{ResourceType: "AWS::Kinesis::Stream", ResourceName: "payments"}},
+				CloudTrailEvent: `{"eventVersion":"1.03"}`,
+			})
+			So(events[1].EventName, ShouldEqual, "CreateStream")
+		})
+		Convey("It looks up the resource", func() {
+			So(requests()[0].Target, ShouldEqual, "com.amazonaws.cloudtrail.v20131101.CloudTrail_20131101.LookupEvents")
+			So(string(requests()[0].Body), ShouldEqual, `{"LookupAttributes":[{"AttributeKey":"ResourceName","AttributeValue":"payments"}],"StartTime":1437000000}`)
+			So(string(requests()[1].Body), ShouldEqual, `{"LookupAttributes":[{"AttributeKey":"ResourceName","AttributeValue":"payments"}],"StartTime":1437000000,"NextToken":"page2"}`)
+		})
+	})
+	Convey("Given a user", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"Events":[]}`))
+		})
+		defer ts.Close()
+
+		_, err := s.LookupEvents(ByUsername("web-1"))
+
+		Convey("It looks up the user", func() {
+			So(err, ShouldBeNil)
+			So(string(requests()[0].Body), ShouldEqual, `{"LookupAttributes":[{"AttributeKey":"Username","AttributeValue":"web-1"}]}`)
+		})
+	})
+	Convey("Given no attribute", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"Events":[]}`))
+		})
+		defer ts.Close()
+
+		events, err := s.LookupEvents(EventQuery{})
+
+		Convey("Every event is looked up", func() {
+			So(err, ShouldBeNil)
+			So(len(events), ShouldEqual, 0)
+			So(string(requests()[0].Body), ShouldEqual, `{}`)
+		})
+	})
+	Convey("Given a lookup that is not valid", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.LookupEvents(EventQuery{Attribute: LookupAttribute{AttributeKey: "Color", AttributeValue: "blue"}})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidLookupAttributesError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.LookupEvents(EventQuery{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,163 @@
+package cloudwatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// FlushInterval is how often a MetricBuffer sends what it has buffered, even if it is less than a full request.
+var FlushInterval = time.Minute
+
+// DefaultMaxBuffered is how many datapoints a MetricBuffer holds, if it was made with a maximum of 0.
+const DefaultMaxBuffered = 10000
+
+// MetricBuffer buffers datapoints for a namespace and sends them with PutMetricData in the background, MaxMetricData at a time.
+// Put does not wait for a request, so it can be used on hot code paths. When the buffer is full, new datapoints are dropped, so it never holds
+// more than its maximum. Datapoints in a request that failed are dropped too. Errors are returned by the next call to Flush or Close.
+type MetricBuffer struct {
+	service   CloudWatchAPI
+	clock     gaws.Clock // Times the flushes, and the datapoints that have no Timestamp
+	namespace string
+	max       int
+	lock      sync.Mutex // Protects pending, err and dropped
+	pending   []MetricDatum
+	err       error
+	dropped   int64
+	sending   sync.Mutex // Makes sure only one request is sent at a time
+	full      chan bool
+	done      chan bool
+	stopped   chan bool
+}
+
+// NewMetricBuffer starts a MetricBuffer for the namespace that holds at most max datapoints. Call Close when you are done with it.
+func (s *CloudWatchService) NewMetricBuffer(namespace string, max int) *MetricBuffer {
+	return NewMetricBuffer(s, namespace, max)
+}
+
+// NewMetricBuffer starts a MetricBuffer that sends to any CloudWatchAPI, like a fake in tests. Otherwise it is the same as CloudWatchService.NewMetricBuffer.
+// Only a *CloudWatchService has a client, so a fake is flushed on gaws.DefaultClock.
+func NewMetricBuffer(service CloudWatchAPI, namespace string, max int) *MetricBuffer {
+	if max <= 0 {
+		max = DefaultMaxBuffered
+	}
+
+	b := &MetricBuffer{
+		service:   service,
+		clock:     serviceClock(service),
+		namespace: namespace,
+		max:       max,
+		full:      make(chan bool, 1),
+		done:      make(chan bool),
+		stopped:   make(chan bool),
+	}
+	go b.run()
+	return b
+}
+
+// serviceClock returns the clock of the service's client, or gaws.DefaultClock if the service is a fake.
+func serviceClock(service CloudWatchAPI) gaws.Clock {
+	if s, ok := service.(*CloudWatchService); ok {
+		return s.config.CurrentClock()
+	}
+	return gaws.DefaultClock
+}
+
+// Put buffers a datapoint. If it does not have a Timestamp, it is given the current time, since it is sent later.
+func (b *MetricBuffer) Put(m MetricDatum) {
+	if m.Timestamp.IsZero() {
+		m.Timestamp = b.clock.Now()
+	}
+
+	b.lock.Lock()
+	if len(b.pending) >= b.max {
+		b.dropped++
+		b.lock.Unlock()
+		return
+	}
+	b.pending = append(b.pending, m)
+	full := len(b.pending) >= MaxMetricData
+	b.lock.Unlock()
+
+	if full {
+		select {
+		case b.full <- true:
+		default:
+		}
+	}
+}
+
+// Dropped returns how many datapoints have been dropped, because the buffer was full or their request failed.
+func (b *MetricBuffer) Dropped() int64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.dropped
+}
+
+func (b *MetricBuffer) run() {
+	defer close(b.stopped)
+
+	tick := b.clock.After(FlushInterval)
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-b.full:
+			b.send(false)
+		case <-tick:
+			b.send(true)
+			tick = b.clock.After(FlushInterval)
+		}
+	}
+}
+
+// send sends the buffered datapoints. Unless all is true, a partial request is left in the buffer.
+func (b *MetricBuffer) send(all bool) {
+	b.sending.Lock()
+	defer b.sending.Unlock()
+
+	for {
+		b.lock.Lock()
+		n := len(b.pending)
+		if n > MaxMetricData {
+			n = MaxMetricData
+		}
+		if n == 0 || (!all && n < MaxMetricData) {
+			b.lock.Unlock()
+			return
+		}
+		batch := make([]MetricDatum, n)
+		copy(batch, b.pending)
+		b.pending = b.pending[n:]
+		b.lock.Unlock()
+
+		err := b.service.PutMetricData(b.namespace, batch)
+		if err != nil {
+			b.lock.Lock()
+			b.dropped += int64(len(batch))
+			if b.err == nil {
+				b.err = err
+			}
+			b.lock.Unlock()
+		}
+	}
+}
+
+// Flush sends everything that is buffered. It returns the first error since the last Flush, if there was one.
+func (b *MetricBuffer) Flush() error {
+	b.send(true)
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	err := b.err
+	b.err = nil
+	return err
+}
+
+// Close stops the background sends and flushes everything that is buffered. The MetricBuffer cannot be used after it is closed.
+func (b *MetricBuffer) Close() error {
+	close(b.done)
+	<-b.stopped
+	return b.Flush()
+}
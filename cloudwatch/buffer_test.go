@@ -0,0 +1,162 @@
+package cloudwatch
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testBufferService returns a service that runs handler, and a function that returns the parameters of the requests it has gotten so far.
+func testBufferService(handler http.HandlerFunc) (func(), *CloudWatchService, func() []url.Values) {
+	var lock sync.Mutex
+	params := []url.Values{}
+
+	ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		params = append(params, r.PostForm)
+		lock.Unlock()
+		handler(w, r)
+	}, nil)
+
+	return ts.Close, &s, func() []url.Values {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]url.Values{}, params...)
+	}
+}
+
+func TestMetricBuffer(t *testing.T) {
+	Convey("Given a MetricBuffer", t, func() {
+		stop, s, requests := testBufferService(testHTTP200)
+		defer stop()
+		b := s.NewMetricBuffer("MyApp", 0)
+
+		Convey("When I put 45 datapoints and close it", func() {
+			for i := 0; i < 45; i++ {
+				b.Put(MetricDatum{MetricName: "Count", Value: 1})
+			}
+			err := b.Close()
+
+			Convey("They are sent 20 at a time", func() {
+				So(err, ShouldBeNil)
+				So(len(requests()), ShouldEqual, 3)
+				So(requests()[2].Get("MetricData.member.5.MetricName"), ShouldEqual, "Count")
+				So(requests()[2].Get("MetricData.member.6.MetricName"), ShouldEqual, "")
+			})
+			Convey("They are given the time they were put", func() {
+				So(requests()[0].Get("MetricData.member.1.Timestamp"), ShouldNotEqual, "")
+			})
+		})
+
+		Convey("When I put a full request", func() {
+			for i := 0; i < MaxMetricData; i++ {
+				b.Put(MetricDatum{MetricName: "Count", Value: 1})
+			}
+
+			Convey("It is sent without a Flush", func() {
+				for i := 0; i < 100 && len(requests()) == 0; i++ {
+					time.Sleep(10 * time.Millisecond)
+				}
+				So(len(requests()), ShouldEqual, 1)
+				b.Close()
+			})
+		})
+	})
+
+	Convey("Given a MetricBuffer of a client with a manual clock", t, func() {
+		stop, s, requests := testBufferService(testHTTP200)
+		defer stop()
+		start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := gawstest.NewManualClock(start)
+		s.config.Clock = clock
+		b := s.NewMetricBuffer("MyApp", 0)
+		defer b.Close()
+
+		b.Put(MetricDatum{MetricName: "Count", Value: 1})
+		clock.BlockUntil(1)
+
+		Convey("A partial request is sent each flush interval", func() {
+			So(requests(), ShouldBeEmpty)
+			clock.Advance(FlushInterval)
+			clock.BlockUntil(1)
+			So(len(requests()), ShouldEqual, 1)
+		})
+		Convey("Datapoints are given the clock's time", func() {
+			clock.Advance(FlushInterval)
+			clock.BlockUntil(1)
+			So(requests()[0].Get("MetricData.member.1.Timestamp"), ShouldEqual, "2015-01-01T00:00:00Z")
+		})
+	})
+
+	Convey("Given a MetricBuffer that is full", t, func() {
+		stop, s, requests := testBufferService(testHTTP200)
+		defer stop()
+		b := s.NewMetricBuffer("MyApp", 5)
+
+		// Hold the buffer's sends so it stays full.
+		b.sending.Lock()
+		for i := 0; i < 8; i++ {
+			b.Put(MetricDatum{MetricName: "Count", Value: float64(i)})
+		}
+		b.sending.Unlock()
+		err := b.Close()
+
+		Convey("New datapoints are dropped and counted", func() {
+			So(err, ShouldBeNil)
+			So(b.Dropped(), ShouldEqual, 3)
+			So(requests()[0].Get("MetricData.member.5.Value"), ShouldEqual, "4")
+			So(requests()[0].Get("MetricData.member.6.Value"), ShouldEqual, "")
+		})
+	})
+
+	Convey("Given a service that returns errors", t, func() {
+		stop, s, _ := testBufferService(testHTTP400)
+		defer stop()
+		b := s.NewMetricBuffer("MyApp", 0)
+
+		b.Put(MetricDatum{MetricName: "Count", Value: 1})
+		b.Put(MetricDatum{MetricName: "Count", Value: 2})
+		err := b.Close()
+
+		Convey("Close returns the error and the datapoints are counted as dropped", func() {
+			So(err, ShouldNotBeNil)
+			So(b.Dropped(), ShouldEqual, 2)
+		})
+	})
+}
+
+// fakeCloudWatch is a CloudWatchAPI without a server. It records the datapoints put in each namespace.
+type fakeCloudWatch struct {
+	CloudWatchAPI
+	lock sync.Mutex
+	data map[string][]MetricDatum
+}
+
+func (f *fakeCloudWatch) PutMetricData(namespace string, data []MetricDatum) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.data[namespace] = append(f.data[namespace], data...)
+	return nil
+}
+
+func TestMetricBufferWithFakeService(t *testing.T) {
+	Convey("Given a MetricBuffer on a fake service", t, func() {
+		f := &fakeCloudWatch{data: map[string][]MetricDatum{}}
+		b := NewMetricBuffer(f, "MyApp", 0)
+
+		b.Put(MetricDatum{MetricName: "Count", Value: 1})
+		b.Put(MetricDatum{MetricName: "Count", Value: 2})
+		err := b.Close()
+
+		Convey("It puts the datapoints through the fake", func() {
+			So(err, ShouldBeNil)
+			So(len(f.data["MyApp"]), ShouldEqual, 2)
+			So(f.data["MyApp"][1].Value, ShouldEqual, 2)
+		})
+	})
+}
@@ -0,0 +1,91 @@
+// Package cloudwatch provides a way to interact with the AWS CloudWatch service.
+package cloudwatch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type cloudWatchError -field Code -service CloudWatch ResourceNotFound InvalidParameterValue InvalidParameterCombination MissingParameter LimitExceeded InternalServiceError
+
+// cloudWatchError is the error document returned from the CloudWatch service.
+type cloudWatchError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the cloudWatchError into an error message.
+func (e cloudWatchError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func cloudWatchRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := cloudWatchError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// CloudWatchService is the CloudWatch service at AWS. The endpoint for a region is gaws.Endpoint("monitoring", region).
+type CloudWatchService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// CloudWatchAPI is implemented by *CloudWatchService. Code that takes a CloudWatchAPI can be given a fake in tests instead.
+type CloudWatchAPI interface {
+	GetMetricStatistics(q StatisticsQuery) ([]Datapoint, error)
+	PutMetricData(namespace string, data []MetricDatum) error
+}
+
+var _ CloudWatchAPI = (*CloudWatchService)(nil)
+
+// New returns a CloudWatchService with the options applied. Its endpoint is gaws.Endpoint("monitoring", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *CloudWatchService {
+	config := gaws.NewConfig(options...)
+	return &CloudWatchService{Endpoint: config.ServiceEndpoint("monitoring"), config: config}
+}
+
+func (s *CloudWatchService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: cloudWatchRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("monitoring", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for a CloudWatch API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-08-01"},
+	}
+}
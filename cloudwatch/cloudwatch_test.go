@@ -0,0 +1,104 @@
+package cloudwatch
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<GetMetricStatisticsResponse><GetMetricStatisticsResult>"))
+}
+
+var testInvalidParameterError = []byte(`<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>InvalidParameterValue</Code>
+    <Message>The parameter Namespace must not be empty.</Message>
+  </Error>
+  <RequestId>9dd01905-5012-5f99-8663-4b3ecd0dfaef</RequestId>
+</ErrorResponse>`)
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(400)
+	w.Write(testInvalidParameterError)
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, CloudWatchService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, CloudWatchService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := cloudWatchRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := cloudWatchRetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalError</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := cloudWatchRetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says a parameter is invalid", t, func() {
+		result, err := cloudWatchRetryPredicate(400, testInvalidParameterError)
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("The error has the code and message", func() {
+			So(err.Error(), ShouldEqual, "InvalidParameterValue: The parameter Namespace must not be empty.")
+		})
+	})
+	Convey("Given a response that is a success", t, func() {
+		result, err := cloudWatchRetryPredicate(200, []byte("OK"))
+		Convey("RetryPredicate returns false and no error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("monitoring", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
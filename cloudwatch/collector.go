@@ -0,0 +1,127 @@
+package cloudwatch
+
+import (
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// operationStats are the metrics of the requests for one operation since they were last published.
+type operationStats struct {
+	requests  float64
+	retries   float64
+	throttles float64
+	errors    float64
+	latency   StatisticSet // In milliseconds
+}
+
+// Collector is a gaws.MetricsCollector that publishes the metrics of the requests gaws makes to CloudWatch. Use it by setting gaws.Metrics to it.
+// Each FlushInterval, it puts the Requests, Retries, Throttles, Errors and Latency of each operation in a MetricBuffer, with an Operation dimension.
+// Requests are added up rather than sent one by one, so it is cheap to use on a busy client. Its own PutMetricData requests are not counted.
+type Collector struct {
+	buffer  *MetricBuffer
+	clock   gaws.Clock // Times the publishes
+	lock    sync.Mutex // Protects stats
+	stats   map[string]*operationStats
+	done    chan bool
+	stopped chan bool
+}
+
+// NewCollector starts a Collector that publishes to the namespace. Call Close when you are done with it.
+func (s *CloudWatchService) NewCollector(namespace string) *Collector {
+	return NewCollector(s, namespace)
+}
+
+// NewCollector starts a Collector that publishes to any CloudWatchAPI, like a fake in tests. Otherwise it is the same as CloudWatchService.NewCollector.
+func NewCollector(service CloudWatchAPI, namespace string) *Collector {
+	c := &Collector{
+		buffer:  NewMetricBuffer(service, namespace, 0),
+		clock:   serviceClock(service),
+		stats:   map[string]*operationStats{},
+		done:    make(chan bool),
+		stopped: make(chan bool),
+	}
+	go c.run()
+	return c
+}
+
+// RequestDone adds a request to the metrics of its operation.
+func (c *Collector) RequestDone(m gaws.RequestMetrics) {
+	if m.Operation == "PutMetricData" {
+		return
+	}
+	latency := float64(m.Latency) / float64(time.Millisecond)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats, ok := c.stats[m.Operation]
+	if !ok {
+		stats = &operationStats{latency: StatisticSet{Minimum: latency, Maximum: latency}}
+		c.stats[m.Operation] = stats
+	}
+	stats.requests++
+	if m.Tries > 1 {
+		stats.retries += float64(m.Tries - 1)
+	}
+	stats.throttles += float64(m.Throttles)
+	if m.Err != nil {
+		stats.errors++
+	}
+	stats.latency.SampleCount++
+	stats.latency.Sum += latency
+	if latency < stats.latency.Minimum {
+		stats.latency.Minimum = latency
+	}
+	if latency > stats.latency.Maximum {
+		stats.latency.Maximum = latency
+	}
+}
+
+// Dropped returns how many datapoints have been dropped, because the buffer was full or their request failed.
+func (c *Collector) Dropped() int64 {
+	return c.buffer.Dropped()
+}
+
+func (c *Collector) run() {
+	defer close(c.stopped)
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.clock.After(FlushInterval):
+			c.publish()
+		}
+	}
+}
+
+// publish puts the metrics since the last publish in the buffer and starts over.
+func (c *Collector) publish() {
+	c.lock.Lock()
+	stats := c.stats
+	c.stats = map[string]*operationStats{}
+	c.lock.Unlock()
+
+	now := c.clock.Now()
+	for operation, s := range stats {
+		dimensions := []Dimension{{Name: "Operation", Value: operation}}
+		latency := s.latency
+
+		c.buffer.Put(MetricDatum{MetricName: "Requests", Dimensions: dimensions, Timestamp: now, Unit: "Count", Value: s.requests})
+		c.buffer.Put(MetricDatum{MetricName: "Retries", Dimensions: dimensions, Timestamp: now, Unit: "Count", Value: s.retries})
+		c.buffer.Put(MetricDatum{MetricName: "Throttles", Dimensions: dimensions, Timestamp: now, Unit: "Count", Value: s.throttles})
+		c.buffer.Put(MetricDatum{MetricName: "Errors", Dimensions: dimensions, Timestamp: now, Unit: "Count", Value: s.errors})
+		c.buffer.Put(MetricDatum{MetricName: "Latency", Dimensions: dimensions, Timestamp: now, Unit: "Milliseconds", StatisticValues: &latency})
+	}
+}
+
+// Close stops the Collector and publishes the metrics it has. It returns the first error from publishing, if there was one.
+// Set gaws.Metrics to something else first, since the Collector cannot be used after it is closed.
+func (c *Collector) Close() error {
+	close(c.done)
+	<-c.stopped
+	c.publish()
+	return c.buffer.Close()
+}
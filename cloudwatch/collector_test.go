@@ -0,0 +1,86 @@
+package cloudwatch
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCollector(t *testing.T) {
+	Convey("Given a Collector", t, func() {
+		stop, s, requests := testBufferService(testHTTP200)
+		defer stop()
+		c := s.NewCollector("gaws")
+
+		Convey("When it is told about requests and closed", func() {
+			c.RequestDone(gaws.RequestMetrics{Operation: "SendMessage", Tries: 1, Latency: 10 * time.Millisecond})
+			c.RequestDone(gaws.RequestMetrics{Operation: "SendMessage", Tries: 3, Throttles: 2, Latency: 30 * time.Millisecond, Err: errors.New("throttled")})
+			c.RequestDone(gaws.RequestMetrics{Operation: "PutMetricData", Tries: 1})
+			err := c.Close()
+
+			Convey("The metrics of each operation are published", func() {
+				So(err, ShouldBeNil)
+				So(len(requests()), ShouldEqual, 1)
+
+				params := requests()[0]
+				So(params.Get("Namespace"), ShouldEqual, "gaws")
+				values := map[string]string{}
+				for i := 1; i <= 4; i++ {
+					member := fmt.Sprintf("MetricData.member.%d", i)
+					So(params.Get(member+".Dimensions.member.1.Value"), ShouldEqual, "SendMessage")
+					values[params.Get(member+".MetricName")] = params.Get(member + ".Value")
+				}
+				So(values, ShouldResemble, map[string]string{"Requests": "2", "Retries": "2", "Throttles": "2", "Errors": "1"})
+			})
+			Convey("The latency is sent as a statistic set", func() {
+				params := requests()[0]
+				So(params.Get("MetricData.member.5.MetricName"), ShouldEqual, "Latency")
+				So(params.Get("MetricData.member.5.Unit"), ShouldEqual, "Milliseconds")
+				So(params.Get("MetricData.member.5.StatisticValues.SampleCount"), ShouldEqual, "2")
+				So(params.Get("MetricData.member.5.StatisticValues.Sum"), ShouldEqual, "40")
+				So(params.Get("MetricData.member.5.StatisticValues.Minimum"), ShouldEqual, "10")
+				So(params.Get("MetricData.member.5.StatisticValues.Maximum"), ShouldEqual, "30")
+			})
+			Convey("Its own requests are not counted", func() {
+				So(requests()[0].Get("MetricData.member.6.MetricName"), ShouldEqual, "")
+			})
+		})
+
+		Convey("When it is not told about any requests", func() {
+			err := c.Close()
+
+			Convey("Nothing is published", func() {
+				So(err, ShouldBeNil)
+				So(len(requests()), ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a Collector of a client with a manual clock", t, func() {
+		stop, s, requests := testBufferService(testHTTP200)
+		defer stop()
+		start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		clock := gawstest.NewManualClock(start)
+		s.config.Clock = clock
+		c := s.NewCollector("gaws")
+		defer c.Close()
+
+		c.RequestDone(gaws.RequestMetrics{Operation: "SendMessage", Tries: 1, Latency: 10 * time.Millisecond})
+		// The collector and its buffer both wait a flush interval.
+		clock.BlockUntil(2)
+
+		Convey("It publishes each flush interval, and its buffer sends what it published", func() {
+			clock.Advance(FlushInterval)
+			clock.BlockUntil(2)
+			clock.Advance(FlushInterval)
+			clock.BlockUntil(2)
+			So(len(requests()), ShouldEqual, 1)
+			So(requests()[0].Get("MetricData.member.1.Timestamp"), ShouldEqual, "2015-01-01T00:01:00Z")
+		})
+	})
+}
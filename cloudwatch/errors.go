@@ -0,0 +1,19 @@
+// Code generated by errgen from the go:generate line in cloudwatch.go. DO NOT EDIT.
+
+package cloudwatch
+
+// These are errors CloudWatch returns. errors.Is(err, cloudwatch.ErrResourceNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrResourceNotFound            = cloudWatchError{Code: "ResourceNotFound"}
+	ErrInvalidParameterValue       = cloudWatchError{Code: "InvalidParameterValue"}
+	ErrInvalidParameterCombination = cloudWatchError{Code: "InvalidParameterCombination"}
+	ErrMissingParameter            = cloudWatchError{Code: "MissingParameter"}
+	ErrLimitExceeded               = cloudWatchError{Code: "LimitExceeded"}
+	ErrInternalService             = cloudWatchError{Code: "InternalServiceError"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e cloudWatchError) Is(target error) bool {
+	t, ok := target.(cloudWatchError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
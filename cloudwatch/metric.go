@@ -0,0 +1,97 @@
+package cloudwatch
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxMetricData is the most datapoints PutMetricData accepts in one request.
+const MaxMetricData = 20
+
+// Dimension is a name and value that, with the metric name, identifies a metric, like InstanceId=i-1234.
+type Dimension struct {
+	Name  string
+	Value string
+}
+
+// StatisticSet summarizes many values that were measured together, so they can be sent as one datapoint.
+type StatisticSet struct {
+	SampleCount float64
+	Sum         float64
+	Minimum     float64
+	Maximum     float64
+}
+
+// MetricDatum is a datapoint of a metric. It has either a Value or StatisticValues.
+type MetricDatum struct {
+	MetricName      string
+	Dimensions      []Dimension
+	Timestamp       time.Time     // When the value was measured. If it is zero, CloudWatch uses the time it gets the datapoint.
+	Unit            string        // Like Seconds, Bytes or Count. If it is empty, CloudWatch uses None.
+	Value           float64       // The value, if StatisticValues is nil
+	StatisticValues *StatisticSet // Many values at once
+}
+
+// formatFloat formats a number the way CloudWatch expects.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// setDimensionParams adds the dimensions to the parameters of a request, after the prefix.
+func setDimensionParams(params url.Values, prefix string, dimensions []Dimension) {
+	for i, d := range dimensions {
+		dimensionPrefix := fmt.Sprintf("%vDimensions.member.%d.", prefix, i+1)
+		params.Set(dimensionPrefix+"Name", d.Name)
+		params.Set(dimensionPrefix+"Value", d.Value)
+	}
+}
+
+// setParams adds the datum to the parameters of a request, after the prefix.
+func (m MetricDatum) setParams(params url.Values, prefix string) {
+	params.Set(prefix+"MetricName", m.MetricName)
+	setDimensionParams(params, prefix, m.Dimensions)
+	if !m.Timestamp.IsZero() {
+		params.Set(prefix+"Timestamp", m.Timestamp.UTC().Format(time.RFC3339))
+	}
+	if m.Unit != "" {
+		params.Set(prefix+"Unit", m.Unit)
+	}
+
+	if m.StatisticValues != nil {
+		params.Set(prefix+"StatisticValues.SampleCount", formatFloat(m.StatisticValues.SampleCount))
+		params.Set(prefix+"StatisticValues.Sum", formatFloat(m.StatisticValues.Sum))
+		params.Set(prefix+"StatisticValues.Minimum", formatFloat(m.StatisticValues.Minimum))
+		params.Set(prefix+"StatisticValues.Maximum", formatFloat(m.StatisticValues.Maximum))
+	} else {
+		params.Set(prefix+"Value", formatFloat(m.Value))
+	}
+}
+
+// PutMetricData sends datapoints to metrics in the namespace, like MyApp/Orders. Metrics are created when they are first sent to.
+// The datapoints are sent MaxMetricData at a time. If a request fails, the datapoints after it are not sent. It is calling the PutMetricData API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_PutMetricData.html for more details.
+func (s *CloudWatchService) PutMetricData(namespace string, data []MetricDatum) error {
+	for start := 0; start < len(data); start += MaxMetricData {
+		end := start + MaxMetricData
+		if end > len(data) {
+			end = len(data)
+		}
+
+		params := query("PutMetricData")
+		params.Set("Namespace", namespace)
+		for i, m := range data[start:end] {
+			m.setParams(params, fmt.Sprintf("MetricData.member.%d.", i+1))
+		}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		_, err := req.Do()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cloudwatch
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPutMetricData(t *testing.T) {
+	Convey("Given datapoints with dimensions and units", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.PutMetricData("MyApp/Orders", []MetricDatum{
+			{MetricName: "Latency", Dimensions: []Dimension{{Name: "Operation", Value: "Checkout"}}, Unit: "Milliseconds", Value: 12.5, Timestamp: time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)},
+			{MetricName: "Orders", Value: 1},
+		})
+
+		Convey("They are sent in one request", func() {
+			So(err, ShouldBeNil)
+			So(len(params), ShouldEqual, 1)
+			So(params[0].Get("Action"), ShouldEqual, "PutMetricData")
+			So(params[0].Get("Namespace"), ShouldEqual, "MyApp/Orders")
+		})
+		Convey("Each datapoint has its parameters", func() {
+			So(params[0].Get("MetricData.member.1.MetricName"), ShouldEqual, "Latency")
+			So(params[0].Get("MetricData.member.1.Dimensions.member.1.Name"), ShouldEqual, "Operation")
+			So(params[0].Get("MetricData.member.1.Dimensions.member.1.Value"), ShouldEqual, "Checkout")
+			So(params[0].Get("MetricData.member.1.Unit"), ShouldEqual, "Milliseconds")
+			So(params[0].Get("MetricData.member.1.Value"), ShouldEqual, "12.5")
+			So(params[0].Get("MetricData.member.1.Timestamp"), ShouldEqual, "2015-03-01T12:00:00Z")
+			So(params[0].Get("MetricData.member.2.Value"), ShouldEqual, "1")
+			_, hasUnit := params[0]["MetricData.member.2.Unit"]
+			So(hasUnit, ShouldBeFalse)
+		})
+	})
+	Convey("Given a statistic set", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.PutMetricData("MyApp", []MetricDatum{{MetricName: "Latency", StatisticValues: &StatisticSet{SampleCount: 3, Sum: 30, Minimum: 5, Maximum: 15}}})
+
+		Convey("It is sent instead of a value", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("MetricData.member.1.StatisticValues.SampleCount"), ShouldEqual, "3")
+			So(params[0].Get("MetricData.member.1.StatisticValues.Sum"), ShouldEqual, "30")
+			So(params[0].Get("MetricData.member.1.StatisticValues.Minimum"), ShouldEqual, "5")
+			So(params[0].Get("MetricData.member.1.StatisticValues.Maximum"), ShouldEqual, "15")
+			_, hasValue := params[0]["MetricData.member.1.Value"]
+			So(hasValue, ShouldBeFalse)
+		})
+	})
+	Convey("Given more datapoints than fit in a request", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		data := []MetricDatum{}
+		for i := 0; i < 45; i++ {
+			data = append(data, MetricDatum{MetricName: "Count", Value: float64(i)})
+		}
+		err := s.PutMetricData("MyApp", data)
+
+		Convey("They are sent 20 at a time", func() {
+			So(err, ShouldBeNil)
+			So(len(params), ShouldEqual, 3)
+			So(params[0].Get("MetricData.member.20.Value"), ShouldEqual, "19")
+			So(params[1].Get("MetricData.member.1.Value"), ShouldEqual, "20")
+			So(params[2].Get("MetricData.member.5.Value"), ShouldEqual, "44")
+			So(params[2].Get("MetricData.member.6.Value"), ShouldEqual, "")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP400, &params)
+		defer ts.Close()
+
+		data := make([]MetricDatum, 30)
+		err := s.PutMetricData("", data)
+
+		Convey("It returns the error and stops", func() {
+			So(err, ShouldNotBeNil)
+			So(len(params), ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package cloudwatch
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// The statistics GetMetricStatistics can return.
+const (
+	Average     = "Average"
+	Sum         = "Sum"
+	SampleCount = "SampleCount"
+	Maximum     = "Maximum"
+	Minimum     = "Minimum"
+)
+
+var badPeriodError = cloudWatchError{Code: "GawsBadPeriod", Message: "The period of a statistics query must be at least a minute."}
+
+// MaxDatapoints is the most datapoints GetMetricStatistics returns for one request.
+const MaxDatapoints = 1440
+
+// StatisticsQuery says which metric to get statistics for, over what time, and how they are grouped.
+type StatisticsQuery struct {
+	Namespace  string // Like AWS/Kinesis or AWS/DynamoDB
+	MetricName string
+	Dimensions []Dimension // Every dimension of the metric, like StreamName=foo
+	Start      time.Time
+	End        time.Time
+	Period     time.Duration // How long each datapoint covers. It must be a multiple of a minute.
+	Statistics []string      // Like Sum and Average
+	Unit       string        // Only get datapoints with this unit. If it is empty, every unit is returned.
+}
+
+// Datapoint is the statistics of a metric over one period. Only the statistics that were asked for are set.
+type Datapoint struct {
+	Timestamp   time.Time // The start of the period
+	Average     float64
+	Sum         float64
+	SampleCount float64
+	Maximum     float64
+	Minimum     float64
+	Unit        string
+}
+
+type getMetricStatisticsResponse struct {
+	Datapoints []Datapoint `xml:"GetMetricStatisticsResult>Datapoints>member"`
+}
+
+// Last returns the start and end of the time up to now, to the minute, so query.Start, query.End = Last(time.Hour) gets the last hour.
+func Last(d time.Duration) (time.Time, time.Time) {
+	end := time.Now().UTC().Truncate(time.Minute)
+	return end.Add(-d), end
+}
+
+// getMetricStatistics gets the datapoints from start to end.
+func (s *CloudWatchService) getMetricStatistics(q StatisticsQuery, start time.Time, end time.Time) ([]Datapoint, error) {
+	result := getMetricStatisticsResponse{}
+
+	params := query("GetMetricStatistics")
+	params.Set("Namespace", q.Namespace)
+	params.Set("MetricName", q.MetricName)
+	setDimensionParams(params, "", q.Dimensions)
+	params.Set("StartTime", start.UTC().Format(time.RFC3339))
+	params.Set("EndTime", end.UTC().Format(time.RFC3339))
+	params.Set("Period", fmt.Sprint(int64(q.Period/time.Second)))
+	for i, statistic := range q.Statistics {
+		params.Set(fmt.Sprintf("Statistics.member.%d", i+1), statistic)
+	}
+	if q.Unit != "" {
+		params.Set("Unit", q.Unit)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Datapoints, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Datapoints, err
+}
+
+// GetMetricStatistics gets the statistics of a metric from the start of the query to its end, one datapoint per period, sorted by time.
+// CloudWatch returns at most MaxDatapoints for a request, so longer times are split over several requests. It is calling the GetMetricStatistics API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_GetMetricStatistics.html for more details.
+func (s *CloudWatchService) GetMetricStatistics(q StatisticsQuery) ([]Datapoint, error) {
+	datapoints := []Datapoint{}
+	if q.Period < time.Minute {
+		return datapoints, badPeriodError
+	}
+
+	chunk := q.Period * MaxDatapoints
+	for start := q.Start; start.Before(q.End); start = start.Add(chunk) {
+		end := start.Add(chunk)
+		if end.After(q.End) {
+			end = q.End
+		}
+
+		found, err := s.getMetricStatistics(q, start, end)
+		if err != nil {
+			return datapoints, err
+		}
+		datapoints = append(datapoints, found...)
+	}
+
+	sort.Slice(datapoints, func(i, j int) bool { return datapoints[i].Timestamp.Before(datapoints[j].Timestamp) })
+	return datapoints, nil
+}
@@ -0,0 +1,116 @@
+package cloudwatch
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testStatistics(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`<GetMetricStatisticsResponse xmlns="http://monitoring.amazonaws.com/doc/2010-08-01/">
+  <GetMetricStatisticsResult>
+    <Datapoints>
+      <member>
+        <Timestamp>2015-03-01T12:01:00Z</Timestamp>
+        <Sum>30.0</Sum>
+        <Average>10.0</Average>
+        <Unit>Count</Unit>
+      </member>
+      <member>
+        <Timestamp>2015-03-01T12:00:00Z</Timestamp>
+        <Sum>12.0</Sum>
+        <Average>6.0</Average>
+        <Unit>Count</Unit>
+      </member>
+    </Datapoints>
+    <Label>IncomingRecords</Label>
+  </GetMetricStatisticsResult>
+</GetMetricStatisticsResponse>`))
+}
+
+func TestGetMetricStatistics(t *testing.T) {
+	start := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a query", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testStatistics, &params)
+		defer ts.Close()
+
+		datapoints, err := s.GetMetricStatistics(StatisticsQuery{
+			Namespace:  "AWS/Kinesis",
+			MetricName: "IncomingRecords",
+			Dimensions: []Dimension{{Name: "StreamName", Value: "foo"}},
+			Start:      start,
+			End:        start.Add(2 * time.Minute),
+			Period:     time.Minute,
+			Statistics: []string{Sum, Average},
+		})
+
+		Convey("It returns the datapoints sorted by time", func() {
+			So(err, ShouldBeNil)
+			So(len(datapoints), ShouldEqual, 2)
+			So(datapoints[0].Timestamp, ShouldResemble, start)
+			So(datapoints[0].Sum, ShouldEqual, 12)
+			So(datapoints[1].Average, ShouldEqual, 10)
+			So(datapoints[1].Unit, ShouldEqual, "Count")
+		})
+		Convey("It sends the query", func() {
+			So(params[0].Get("Action"), ShouldEqual, "GetMetricStatistics")
+			So(params[0].Get("Namespace"), ShouldEqual, "AWS/Kinesis")
+			So(params[0].Get("MetricName"), ShouldEqual, "IncomingRecords")
+			So(params[0].Get("Dimensions.member.1.Name"), ShouldEqual, "StreamName")
+			So(params[0].Get("StartTime"), ShouldEqual, "2015-03-01T12:00:00Z")
+			So(params[0].Get("EndTime"), ShouldEqual, "2015-03-01T12:02:00Z")
+			So(params[0].Get("Period"), ShouldEqual, "60")
+			So(params[0].Get("Statistics.member.1"), ShouldEqual, "Sum")
+			So(params[0].Get("Statistics.member.2"), ShouldEqual, "Average")
+		})
+	})
+	Convey("Given a query for more datapoints than fit in a request", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testStatistics, &params)
+		defer ts.Close()
+
+		_, err := s.GetMetricStatistics(StatisticsQuery{Start: start, End: start.Add(30 * time.Hour), Period: time.Minute, Statistics: []string{Sum}})
+
+		Convey("It is split over several requests", func() {
+			So(err, ShouldBeNil)
+			So(len(params), ShouldEqual, 2)
+			So(params[0].Get("EndTime"), ShouldEqual, "2015-03-02T12:00:00Z")
+			So(params[1].Get("StartTime"), ShouldEqual, "2015-03-02T12:00:00Z")
+			So(params[1].Get("EndTime"), ShouldEqual, "2015-03-02T18:00:00Z")
+		})
+	})
+	Convey("Given a period shorter than a minute", t, func() {
+		_, err := (&CloudWatchService{}).GetMetricStatistics(StatisticsQuery{Start: start, End: start.Add(time.Hour), Period: time.Second})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, badPeriodError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.GetMetricStatistics(StatisticsQuery{Start: start, End: start.Add(time.Hour), Period: time.Minute})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestLast(t *testing.T) {
+	Convey("Given a duration", t, func() {
+		start, end := Last(time.Hour)
+
+		Convey("It ends at the start of this minute and starts that long before", func() {
+			So(end.Second(), ShouldEqual, 0)
+			So(end.Sub(start), ShouldEqual, time.Hour)
+			So(time.Since(end), ShouldBeLessThan, time.Minute)
+		})
+	})
+}
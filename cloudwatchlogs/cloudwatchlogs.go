@@ -0,0 +1,118 @@
+// Package cloudwatchlogs provides a way to interact with the AWS CloudWatch Logs service.
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type logsError -field Type -service "CloudWatch Logs" ResourceNotFoundException ResourceAlreadyExistsException InvalidSequenceTokenException DataAlreadyAcceptedException InvalidParameterException LimitExceededException OperationAbortedException ServiceUnavailableException
+
+// logsError is the error document returned from the CloudWatch Logs service.
+type logsError struct {
+	Type                  string `json:"__type"`
+	Message               string `json:"message"`
+	ExpectedSequenceToken string `json:"expectedSequenceToken,omitempty"` // Set by InvalidSequenceTokenException and DataAlreadyAcceptedException
+}
+
+// Error formats the logsError into an error message.
+func (e logsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e logsError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func logsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := logsError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") || error.is("Throttling") {
+		return true, error
+	}
+
+	if error.is("ServiceUnavailableException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// CloudWatchLogsService is the CloudWatch Logs service at AWS. The endpoint for a region is gaws.Endpoint("logs", region).
+type CloudWatchLogsService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// CloudWatchLogsAPI is implemented by *CloudWatchLogsService. Code that takes a CloudWatchLogsAPI can be given a fake in tests instead.
+type CloudWatchLogsAPI interface {
+	CreateLogGroup(name string) error
+	CreateLogGroupIfNotExists(name string) error
+	CreateLogStream(group string, name string) (*LogStream, error)
+	CreateLogStreamIfNotExists(group string, name string) (*LogStream, error)
+	DeleteLogGroup(name string) error
+	DeleteRetentionPolicy(group string) error
+	DescribeLogGroups(prefix string) ([]LogGroupDescription, error)
+	DescribeLogStreams(group string, prefix string) ([]LogStreamDescription, error)
+	FilterLogEvents(q FilterQuery) ([]FilteredLogEvent, error)
+	FollowLogEvents(q FilterQuery, done <-chan bool) (<-chan FilteredLogEvent, <-chan error)
+	PutRetentionPolicy(group string, days int) error
+}
+
+var _ CloudWatchLogsAPI = (*CloudWatchLogsService)(nil)
+
+// New returns a CloudWatchLogsService with the options applied. Its endpoint is gaws.Endpoint("logs", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *CloudWatchLogsService {
+	config := gaws.NewConfig(options...)
+	return &CloudWatchLogsService{Endpoint: config.ServiceEndpoint("logs"), config: config}
+}
+
+func (s *CloudWatchLogsService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: logsRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("logs", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// LogStream is a stream of log events in a log group.
+// It keeps the sequence token PutLogEvents needs, so use one LogStream for each stream you write to.
+type LogStream struct {
+	Group   string                 // The name of the log group
+	Name    string                 // The name of the stream
+	Service *CloudWatchLogsService // The service for this region
+
+	lock  sync.Mutex // Protects token and makes sure only one PutLogEvents request is sent at a time
+	token string     // The sequence token for the next PutLogEvents request
+}
+
+// LogStream returns the stream with the name in the log group.
+func (s *CloudWatchLogsService) LogStream(group string, name string) *LogStream {
+	return &LogStream{Group: group, Name: name, Service: s}
+}
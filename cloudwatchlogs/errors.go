@@ -0,0 +1,23 @@
+// Code generated by errgen from the go:generate line in cloudwatchlogs.go. DO NOT EDIT.
+
+package cloudwatchlogs
+
+import "strings"
+
+// These are errors CloudWatch Logs returns. errors.Is(err, cloudwatchlogs.ErrResourceNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrResourceNotFound      = logsError{Type: "ResourceNotFoundException"}
+	ErrResourceAlreadyExists = logsError{Type: "ResourceAlreadyExistsException"}
+	ErrInvalidSequenceToken  = logsError{Type: "InvalidSequenceTokenException"}
+	ErrDataAlreadyAccepted   = logsError{Type: "DataAlreadyAcceptedException"}
+	ErrInvalidParameter      = logsError{Type: "InvalidParameterException"}
+	ErrLimitExceeded         = logsError{Type: "LimitExceededException"}
+	ErrOperationAborted      = logsError{Type: "OperationAbortedException"}
+	ErrServiceUnavailable    = logsError{Type: "ServiceUnavailableException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e logsError) Is(target error) bool {
+	t, ok := target.(logsError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
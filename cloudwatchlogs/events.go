@@ -0,0 +1,155 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The limits of a PutLogEvents request.
+const (
+	MaxBatchEvents = 10000          // The most events in a request
+	MaxBatchSize   = 1048576        // The most bytes in a request, counting EventOverhead for each event
+	MaxBatchSpan   = 24 * time.Hour // The most time between the first and last events in a request
+	EventOverhead  = 26             // The bytes each event adds to the size of a request, on top of its message
+)
+
+// MaxTokenRetries is how many times PutLogEvents resends a request with the sequence token CloudWatch Logs expected.
+var MaxTokenRetries = 3
+
+var eventTooLargeError = logsError{Type: "GawsEventTooLarge", Message: "A log event is larger than a PutLogEvents request can hold."}
+var rejectedEventsError = logsError{Type: "GawsRejectedLogEvents", Message: "Some log events were rejected because they were too old, too new, or expired."}
+
+// LogEvent is an event in a log stream.
+type LogEvent struct {
+	Timestamp time.Time
+	Message   string
+}
+
+// inputLogEvent is a LogEvent as it is sent to CloudWatch Logs.
+type inputLogEvent struct {
+	Timestamp int64  `json:"timestamp"` // Milliseconds since the epoch
+	Message   string `json:"message"`
+}
+
+// milliseconds returns t as milliseconds since the epoch.
+func milliseconds(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// putLogEventsRequest is the request to the PutLogEvents API call.
+type putLogEventsRequest struct {
+	LogGroupName  string          `json:"logGroupName"`
+	LogStreamName string          `json:"logStreamName"`
+	LogEvents     []inputLogEvent `json:"logEvents"`
+	SequenceToken string          `json:"sequenceToken,omitempty"`
+}
+
+// putLogEventsResponse is the response to the PutLogEvents API call.
+type putLogEventsResponse struct {
+	NextSequenceToken     string `json:"nextSequenceToken"`
+	RejectedLogEventsInfo *struct {
+		TooNewLogEventStartIndex *int `json:"tooNewLogEventStartIndex"`
+		TooOldLogEventEndIndex   *int `json:"tooOldLogEventEndIndex"`
+		ExpiredLogEventEndIndex  *int `json:"expiredLogEventEndIndex"`
+	} `json:"rejectedLogEventsInfo"`
+}
+
+// batches splits events, which must be sorted by time, into requests that are within the PutLogEvents limits.
+func batches(events []inputLogEvent) ([][]inputLogEvent, error) {
+	result := [][]inputLogEvent{}
+	start, size := 0, 0
+	span := int64(MaxBatchSpan / time.Millisecond)
+
+	for i, e := range events {
+		eventSize := len(e.Message) + EventOverhead
+		if eventSize > MaxBatchSize {
+			return result, eventTooLargeError
+		}
+
+		if i > start && (i-start == MaxBatchEvents || size+eventSize > MaxBatchSize || e.Timestamp-events[start].Timestamp > span) {
+			result = append(result, events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	if start < len(events) {
+		result = append(result, events[start:])
+	}
+	return result, nil
+}
+
+// putLogEvents sends one request with the stream's sequence token and keeps the next one.
+// If the token was wrong, it is corrected and the request is sent again. The stream must be locked.
+func (l *LogStream) putLogEvents(events []inputLogEvent) (bool, error) {
+	for try := 0; ; try++ {
+		request := putLogEventsRequest{LogGroupName: l.Group, LogStreamName: l.Name, LogEvents: events, SequenceToken: l.token}
+		bodyAsJson, err := json.Marshal(request)
+		if err != nil {
+			return false, err
+		}
+
+		req := l.Service.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "Logs_20140328.PutLogEvents"
+
+		resp, err := req.Do()
+		if e, ok := err.(logsError); ok {
+			switch {
+			case e.is("DataAlreadyAcceptedException"):
+				// The events were sent before, so only the token needs to be kept
+				l.token = e.ExpectedSequenceToken
+				return false, nil
+			case e.is("InvalidSequenceTokenException") && try < MaxTokenRetries:
+				l.token = e.ExpectedSequenceToken
+				continue
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+
+		result := putLogEventsResponse{}
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return false, err
+		}
+		l.token = result.NextSequenceToken
+		return result.RejectedLogEventsInfo != nil, nil
+	}
+}
+
+// PutLogEvents sends events to the log stream. The events are sorted by time and split into as many requests as the PutLogEvents limits need.
+// The stream's sequence token is kept between calls, and if it is missing or out of date, like when another writer used the stream,
+// it is corrected from the error CloudWatch Logs returns. It stops at the first request that fails. If CloudWatch Logs rejected some events,
+// because they were too old or too new, the rest are still sent, and an error is returned at the end. It is calling the PutLogEvents API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html for more details.
+func (l *LogStream) PutLogEvents(events []LogEvent) error {
+	input := make([]inputLogEvent, len(events))
+	for i, e := range events {
+		input[i] = inputLogEvent{Timestamp: milliseconds(e.Timestamp), Message: e.Message}
+	}
+	sort.SliceStable(input, func(i, j int) bool { return input[i].Timestamp < input[j].Timestamp })
+
+	requests, err := batches(input)
+	if err != nil {
+		return err
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var rejected error
+	for _, batch := range requests {
+		someRejected, err := l.putLogEvents(batch)
+		if err != nil {
+			return err
+		}
+		if someRejected {
+			rejected = rejectedEventsError
+		}
+	}
+	return rejected
+}
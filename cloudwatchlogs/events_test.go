@@ -0,0 +1,189 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testPutLogEvents returns a handler for a stream whose next sequence token is token. Requests with another token are rejected.
+// If errorType is set, it is returned instead of accepting the events.
+func testPutLogEvents(token string, errorType string, response string) http.HandlerFunc {
+	next := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		request := putLogEventsRequest{}
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &request)
+
+		if errorType == "" && request.SequenceToken != token {
+			errorType = "InvalidSequenceTokenException"
+		}
+		if errorType != "" {
+			b, _ := json.Marshal(logsError{Type: errorType, Message: "The given sequenceToken is invalid.", ExpectedSequenceToken: token})
+			errorType = ""
+			w.WriteHeader(400)
+			w.Write(b)
+			return
+		}
+
+		next++
+		token = fmt.Sprintf("token%d", next)
+		if response != "" {
+			w.Write([]byte(response))
+			return
+		}
+		fmt.Fprintf(w, `{"nextSequenceToken":"%v"}`, token)
+	}
+}
+
+// testPutRequests decodes the PutLogEvents requests.
+func testPutRequests(requests []testRequest) []putLogEventsRequest {
+	result := make([]putLogEventsRequest, len(requests))
+	for i, r := range requests {
+		json.Unmarshal(r.Body, &result[i])
+	}
+	return result
+}
+
+func TestPutLogEvents(t *testing.T) {
+	start := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given a new log stream", t, func() {
+		ts, s, requests := testService(testPutLogEvents("", "", ""))
+		defer ts.Close()
+		l := s.LogStream("app", "web-1")
+
+		err := l.PutLogEvents([]LogEvent{{Timestamp: start.Add(time.Second), Message: "second"}, {Timestamp: start, Message: "first"}})
+
+		Convey("The events are sent sorted by time", func() {
+			So(err, ShouldBeNil)
+			put := testPutRequests(requests())
+			So(len(put), ShouldEqual, 1)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.PutLogEvents")
+			So(put[0].LogGroupName, ShouldEqual, "app")
+			So(put[0].LogStreamName, ShouldEqual, "web-1")
+			So(put[0].SequenceToken, ShouldEqual, "")
+			So(put[0].LogEvents, ShouldResemble, []inputLogEvent{{Timestamp: 1425211200000, Message: "first"}, {Timestamp: 1425211201000, Message: "second"}})
+		})
+
+		Convey("When more events are sent", func() {
+			err := l.PutLogEvents([]LogEvent{{Timestamp: start, Message: "third"}})
+
+			Convey("The next sequence token is used", func() {
+				So(err, ShouldBeNil)
+				So(testPutRequests(requests())[1].SequenceToken, ShouldEqual, "token1")
+			})
+		})
+	})
+	Convey("Given a log stream that another writer has used", t, func() {
+		ts, s, requests := testService(testPutLogEvents("abc", "", ""))
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: "hello"}})
+
+		Convey("The request is sent again with the expected sequence token", func() {
+			So(err, ShouldBeNil)
+			put := testPutRequests(requests())
+			So(len(put), ShouldEqual, 2)
+			So(put[1].SequenceToken, ShouldEqual, "abc")
+		})
+	})
+	Convey("Given events that were already accepted", t, func() {
+		ts, s, requests := testService(testPutLogEvents("abc", "DataAlreadyAcceptedException", ""))
+		defer ts.Close()
+		l := s.LogStream("app", "web-1")
+
+		err := l.PutLogEvents([]LogEvent{{Timestamp: start, Message: "hello"}})
+
+		Convey("There is no error and the token is kept", func() {
+			So(err, ShouldBeNil)
+			So(l.token, ShouldEqual, "abc")
+			So(len(requests()), ShouldEqual, 1)
+		})
+	})
+	Convey("Given more events than fit in a request", t, func() {
+		ts, s, requests := testService(testPutLogEvents("", "", ""))
+		defer ts.Close()
+
+		events := make([]LogEvent, MaxBatchEvents+5)
+		for i := range events {
+			events[i] = LogEvent{Timestamp: start, Message: "x"}
+		}
+		err := s.LogStream("app", "web-1").PutLogEvents(events)
+
+		Convey("They are split over several requests", func() {
+			So(err, ShouldBeNil)
+			put := testPutRequests(requests())
+			So(len(put), ShouldEqual, 2)
+			So(len(put[0].LogEvents), ShouldEqual, MaxBatchEvents)
+			So(len(put[1].LogEvents), ShouldEqual, 5)
+			So(put[1].SequenceToken, ShouldEqual, "token1")
+		})
+	})
+	Convey("Given events that are too large for one request", t, func() {
+		ts, s, requests := testService(testPutLogEvents("", "", ""))
+		defer ts.Close()
+
+		message := strings.Repeat("x", MaxBatchSize/2)
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: message}, {Timestamp: start, Message: message}})
+
+		Convey("They are split over several requests", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 2)
+		})
+	})
+	Convey("Given events more than a day apart", t, func() {
+		ts, s, requests := testService(testPutLogEvents("", "", ""))
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: "a"}, {Timestamp: start.Add(25 * time.Hour), Message: "b"}})
+
+		Convey("They are split over several requests", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 2)
+		})
+	})
+	Convey("Given an event larger than a request", t, func() {
+		err := (&CloudWatchLogsService{}).LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: strings.Repeat("x", MaxBatchSize)}})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, eventTooLargeError)
+		})
+	})
+	Convey("Given events that are rejected", t, func() {
+		ts, s, _ := testService(testPutLogEvents("", "", `{"nextSequenceToken":"token1","rejectedLogEventsInfo":{"tooOldLogEventEndIndex":0}}`))
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: "old"}})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, rejectedEventsError)
+		})
+	})
+	Convey("Given a log group that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: "hello"}})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").PutLogEvents([]LogEvent{{Timestamp: start, Message: "hello"}})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,146 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/controlgroup/gaws"
+)
+
+// LogGroupDescription describes a log group.
+type LogGroupDescription struct {
+	LogGroupName      string
+	Arn               string
+	CreationTime      int64 // Milliseconds since the epoch
+	RetentionInDays   int   // How long events are kept. If it is 0, they are kept forever.
+	MetricFilterCount int
+	StoredBytes       int64
+}
+
+// logGroupRequest is the request to API calls that only take a log group, like CreateLogGroup.
+type logGroupRequest struct {
+	LogGroupName string `json:"logGroupName"`
+}
+
+// CreateLogGroup creates a log group. It is calling the CreateLogGroup API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_CreateLogGroup.html for more details.
+func (s *CloudWatchLogsService) CreateLogGroup(name string) error {
+	bodyAsJson, err := json.Marshal(logGroupRequest{LogGroupName: name})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.CreateLogGroup"
+
+	_, err = req.Do()
+	return err
+}
+
+// CreateLogGroupIfNotExists creates a log group if it does not exist yet. It is safe to call from several processes at once.
+func (s *CloudWatchLogsService) CreateLogGroupIfNotExists(name string) error {
+	err := s.CreateLogGroup(name)
+	if errors.Is(err, ErrResourceAlreadyExists) {
+		return nil
+	}
+	return err
+}
+
+// DeleteLogGroup deletes a log group and all of its streams and events. It is calling the DeleteLogGroup API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_DeleteLogGroup.html for more details.
+func (s *CloudWatchLogsService) DeleteLogGroup(name string) error {
+	bodyAsJson, err := json.Marshal(logGroupRequest{LogGroupName: name})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.DeleteLogGroup"
+
+	_, err = req.Do()
+	return err
+}
+
+type describeLogGroupsRequest struct {
+	LogGroupNamePrefix string `json:"logGroupNamePrefix,omitempty"`
+	NextToken          string `json:"nextToken,omitempty"`
+}
+
+type describeLogGroupsResult struct {
+	LogGroups []LogGroupDescription
+	NextToken string
+}
+
+// DescribeLogGroups describes all of the log groups whose names start with prefix. If prefix is empty, it describes every log group in the region.
+// It is calling the DescribeLogGroups API call until there are no more pages.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_DescribeLogGroups.html for more details.
+func (s *CloudWatchLogsService) DescribeLogGroups(prefix string) ([]LogGroupDescription, error) {
+	groups := []LogGroupDescription{}
+	body := describeLogGroupsRequest{LogGroupNamePrefix: prefix}
+
+	for {
+		result := describeLogGroupsResult{}
+		bodyAsJson, err := json.Marshal(body)
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "Logs_20140328.DescribeLogGroups"
+
+		resp, err := req.Do()
+		if err != nil {
+			return []LogGroupDescription{}, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return []LogGroupDescription{}, err
+		}
+
+		groups = append(groups, result.LogGroups...)
+
+		if result.NextToken == "" {
+			return groups, nil
+		}
+		body.NextToken = result.NextToken
+	}
+}
+
+type putRetentionPolicyRequest struct {
+	LogGroupName    string `json:"logGroupName"`
+	RetentionInDays int    `json:"retentionInDays"`
+}
+
+// PutRetentionPolicy sets how many days the events in a log group are kept. CloudWatch Logs only allows some numbers of days, like 1, 7, 30, and 365.
+// It is calling the PutRetentionPolicy API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutRetentionPolicy.html for more details.
+func (s *CloudWatchLogsService) PutRetentionPolicy(group string, days int) error {
+	bodyAsJson, err := json.Marshal(putRetentionPolicyRequest{LogGroupName: group, RetentionInDays: days})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.PutRetentionPolicy"
+
+	_, err = req.Do()
+	return err
+}
+
+// DeleteRetentionPolicy makes a log group keep its events forever. It is calling the DeleteRetentionPolicy API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_DeleteRetentionPolicy.html for more details.
+func (s *CloudWatchLogsService) DeleteRetentionPolicy(group string) error {
+	bodyAsJson, err := json.Marshal(logGroupRequest{LogGroupName: group})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.DeleteRetentionPolicy"
+
+	_, err = req.Do()
+	return err
+}
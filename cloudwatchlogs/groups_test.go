@@ -0,0 +1,139 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var alreadyExistsError = logsError{Type: "ResourceAlreadyExistsException", Message: "The specified resource already exists."}
+
+func testAlreadyExists(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(alreadyExistsError)
+
+	w.WriteHeader(400)
+	w.Write(b)
+}
+
+// testDescribeLogGroups returns two pages of log groups.
+func testDescribeLogGroups(w http.ResponseWriter, r *http.Request) {
+	request := describeLogGroupsRequest{}
+	json.NewDecoder(r.Body).Decode(&request)
+
+	if request.NextToken == "" {
+		w.Write([]byte(`{"logGroups":[{"logGroupName":"app","arn":"arn:aws:logs:us-east-1:123456789012:log-group:app:*","creationTime":1425211200000,"retentionInDays":30,"storedBytes":1024}],"nextToken":"page2"}`))
+		return
+	}
+	w.Write([]byte(`{"logGroups":[{"logGroupName":"app-worker","storedBytes":0}]}`))
+}
+
+func TestCreateLogGroup(t *testing.T) {
+	Convey("Given a log group name", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.CreateLogGroup("app")
+
+		Convey("The log group is created", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.CreateLogGroup")
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app"}`)
+		})
+	})
+	Convey("Given a log group that already exists", t, func() {
+		ts, s, _ := testService(testAlreadyExists)
+		defer ts.Close()
+
+		Convey("CreateLogGroup returns an error", func() {
+			So(s.CreateLogGroup("app"), ShouldResemble, alreadyExistsError)
+		})
+		Convey("CreateLogGroupIfNotExists does not", func() {
+			So(s.CreateLogGroupIfNotExists("app"), ShouldBeNil)
+		})
+	})
+	Convey("Given a server that returns another error", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		Convey("CreateLogGroupIfNotExists returns it", func() {
+			So(s.CreateLogGroupIfNotExists("app"), ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestDeleteLogGroup(t *testing.T) {
+	Convey("Given a log group", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.DeleteLogGroup("app")
+
+		Convey("It is deleted", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.DeleteLogGroup")
+		})
+	})
+}
+
+func TestDescribeLogGroups(t *testing.T) {
+	Convey("Given log groups that take two pages", t, func() {
+		ts, s, requests := testService(testDescribeLogGroups)
+		defer ts.Close()
+
+		groups, err := s.DescribeLogGroups("app")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 2)
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupNamePrefix":"app"}`)
+			So(string(requests()[1].Body), ShouldEqual, `{"logGroupNamePrefix":"app","nextToken":"page2"}`)
+			So(len(groups), ShouldEqual, 2)
+			So(groups[0], ShouldResemble, LogGroupDescription{
+				LogGroupName:    "app",
+				Arn:             "arn:aws:logs:us-east-1:123456789012:log-group:app:*",
+				CreationTime:    1425211200000,
+				RetentionInDays: 30,
+				StoredBytes:     1024,
+			})
+			So(groups[1].LogGroupName, ShouldEqual, "app-worker")
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.DescribeLogGroups("")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPutRetentionPolicy(t *testing.T) {
+	Convey("Given a log group and a number of days", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.PutRetentionPolicy("app", 30)
+
+		Convey("The retention policy is set", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.PutRetentionPolicy")
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app","retentionInDays":30}`)
+		})
+	})
+	Convey("Given a log group to keep forever", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.DeleteRetentionPolicy("app")
+
+		Convey("The retention policy is deleted", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.DeleteRetentionPolicy")
+		})
+	})
+}
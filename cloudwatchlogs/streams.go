@@ -0,0 +1,122 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/controlgroup/gaws"
+)
+
+// LogStreamDescription describes a log stream.
+type LogStreamDescription struct {
+	LogStreamName       string
+	Arn                 string
+	CreationTime        int64 // Milliseconds since the epoch, like the other times
+	FirstEventTimestamp int64
+	LastEventTimestamp  int64
+	LastIngestionTime   int64
+	UploadSequenceToken string
+	StoredBytes         int64
+}
+
+// logStreamRequest is the request to API calls that only take a log stream, like CreateLogStream.
+type logStreamRequest struct {
+	LogGroupName  string `json:"logGroupName"`
+	LogStreamName string `json:"logStreamName"`
+}
+
+// CreateLogStream creates a log stream in a log group. It returns the new stream. It is calling the CreateLogStream API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_CreateLogStream.html for more details.
+func (s *CloudWatchLogsService) CreateLogStream(group string, name string) (*LogStream, error) {
+	stream := s.LogStream(group, name)
+
+	bodyAsJson, err := json.Marshal(logStreamRequest{LogGroupName: group, LogStreamName: name})
+	if err != nil {
+		return stream, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.CreateLogStream"
+
+	_, err = req.Do()
+	return stream, err
+}
+
+// CreateLogStreamIfNotExists creates a log stream, and the log group it is in, if they do not exist yet. It returns the stream.
+// It is safe to call from several processes at once, so an application can call it when it starts to set up where it logs to.
+func (s *CloudWatchLogsService) CreateLogStreamIfNotExists(group string, name string) (*LogStream, error) {
+	stream := s.LogStream(group, name)
+
+	err := s.CreateLogGroupIfNotExists(group)
+	if err != nil {
+		return stream, err
+	}
+
+	_, err = s.CreateLogStream(group, name)
+	if errors.Is(err, ErrResourceAlreadyExists) {
+		return stream, nil
+	}
+	return stream, err
+}
+
+// Delete deletes the log stream and all of its events. It is calling the DeleteLogStream API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_DeleteLogStream.html for more details.
+func (l *LogStream) Delete() error {
+	bodyAsJson, err := json.Marshal(logStreamRequest{LogGroupName: l.Group, LogStreamName: l.Name})
+	if err != nil {
+		return err
+	}
+
+	req := l.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.DeleteLogStream"
+
+	_, err = req.Do()
+	return err
+}
+
+type describeLogStreamsRequest struct {
+	LogGroupName        string `json:"logGroupName"`
+	LogStreamNamePrefix string `json:"logStreamNamePrefix,omitempty"`
+	NextToken           string `json:"nextToken,omitempty"`
+}
+
+type describeLogStreamsResult struct {
+	LogStreams []LogStreamDescription
+	NextToken  string
+}
+
+// DescribeLogStreams describes all of the streams in a log group whose names start with prefix. If prefix is empty, it describes every stream in the group.
+// It is calling the DescribeLogStreams API call until there are no more pages.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_DescribeLogStreams.html for more details.
+func (s *CloudWatchLogsService) DescribeLogStreams(group string, prefix string) ([]LogStreamDescription, error) {
+	streams := []LogStreamDescription{}
+	body := describeLogStreamsRequest{LogGroupName: group, LogStreamNamePrefix: prefix}
+
+	for {
+		result := describeLogStreamsResult{}
+		bodyAsJson, err := json.Marshal(body)
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "Logs_20140328.DescribeLogStreams"
+
+		resp, err := req.Do()
+		if err != nil {
+			return []LogStreamDescription{}, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return []LogStreamDescription{}, err
+		}
+
+		streams = append(streams, result.LogStreams...)
+
+		if result.NextToken == "" {
+			return streams, nil
+		}
+		body.NextToken = result.NextToken
+	}
+}
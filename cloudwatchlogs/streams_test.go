@@ -0,0 +1,113 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testDescribeLogStreams returns two pages of log streams.
+func testDescribeLogStreams(w http.ResponseWriter, r *http.Request) {
+	request := describeLogStreamsRequest{}
+	json.NewDecoder(r.Body).Decode(&request)
+
+	if request.NextToken == "" {
+		w.Write([]byte(`{"logStreams":[{"logStreamName":"web-1","creationTime":1425211200000,"lastEventTimestamp":1425211260000,"uploadSequenceToken":"abc"}],"nextToken":"page2"}`))
+		return
+	}
+	w.Write([]byte(`{"logStreams":[{"logStreamName":"web-2"}]}`))
+}
+
+func TestCreateLogStream(t *testing.T) {
+	Convey("Given a log group and a stream name", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		stream, err := s.CreateLogStream("app", "web-1")
+
+		Convey("The stream is created and returned", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.CreateLogStream")
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app","logStreamName":"web-1"}`)
+			So(stream.Group, ShouldEqual, "app")
+			So(stream.Name, ShouldEqual, "web-1")
+			So(stream.Service, ShouldEqual, s)
+		})
+	})
+	Convey("Given a log group and stream that already exist", t, func() {
+		ts, s, requests := testService(testAlreadyExists)
+		defer ts.Close()
+
+		stream, err := s.CreateLogStreamIfNotExists("app", "web-1")
+
+		Convey("CreateLogStreamIfNotExists creates the group and the stream without an error", func() {
+			So(err, ShouldBeNil)
+			So(stream.Name, ShouldEqual, "web-1")
+			So(len(requests()), ShouldEqual, 2)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.CreateLogGroup")
+			So(requests()[1].Target, ShouldEqual, "Logs_20140328.CreateLogStream")
+		})
+	})
+	Convey("Given a log group that cannot be created", t, func() {
+		ts, s, requests := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.CreateLogStreamIfNotExists("app", "web-1")
+
+		Convey("CreateLogStreamIfNotExists returns the error without creating the stream", func() {
+			So(err, ShouldResemble, notFoundError)
+			So(len(requests()), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestDeleteLogStream(t *testing.T) {
+	Convey("Given a log stream", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.LogStream("app", "web-1").Delete()
+
+		Convey("It is deleted", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.DeleteLogStream")
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app","logStreamName":"web-1"}`)
+		})
+	})
+}
+
+func TestDescribeLogStreams(t *testing.T) {
+	Convey("Given log streams that take two pages", t, func() {
+		ts, s, requests := testService(testDescribeLogStreams)
+		defer ts.Close()
+
+		streams, err := s.DescribeLogStreams("app", "web")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 2)
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app","logStreamNamePrefix":"web"}`)
+			So(string(requests()[1].Body), ShouldEqual, `{"logGroupName":"app","logStreamNamePrefix":"web","nextToken":"page2"}`)
+			So(len(streams), ShouldEqual, 2)
+			So(streams[0], ShouldResemble, LogStreamDescription{
+				LogStreamName:       "web-1",
+				CreationTime:        1425211200000,
+				LastEventTimestamp:  1425211260000,
+				UploadSequenceToken: "abc",
+			})
+			So(streams[1].LogStreamName, ShouldEqual, "web-2")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.DescribeLogStreams("app", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
@@ -0,0 +1,155 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// FollowInterval is how long FollowLogEvents waits between checks for new events.
+var FollowInterval = 5 * time.Second
+
+// FilterQuery says which events FilterLogEvents gets from a log group.
+type FilterQuery struct {
+	Group       string
+	StreamNames []string  // Only get events from these streams. If it is empty, events from every stream are returned.
+	Pattern     string    // A filter pattern, like ERROR or { $.status = 500 }. If it is empty, every event matches.
+	Start       time.Time // If it is zero, events are returned from the start of the log
+	End         time.Time // If it is zero, events are returned up to now
+}
+
+// FilteredLogEvent is an event FilterLogEvents found.
+type FilteredLogEvent struct {
+	EventId       string
+	LogStreamName string
+	Timestamp     int64 // Milliseconds since the epoch
+	IngestionTime int64
+	Message       string
+}
+
+// Time returns the event's Timestamp as a time.Time.
+func (e FilteredLogEvent) Time() time.Time {
+	return time.Unix(0, e.Timestamp*int64(time.Millisecond))
+}
+
+type filterLogEventsRequest struct {
+	LogGroupName   string   `json:"logGroupName"`
+	LogStreamNames []string `json:"logStreamNames,omitempty"`
+	FilterPattern  string   `json:"filterPattern,omitempty"`
+	StartTime      int64    `json:"startTime,omitempty"`
+	EndTime        int64    `json:"endTime,omitempty"`
+	NextToken      string   `json:"nextToken,omitempty"`
+}
+
+type filterLogEventsResult struct {
+	Events    []FilteredLogEvent
+	NextToken string
+}
+
+// filterLogEvents gets one page of events.
+func (s *CloudWatchLogsService) filterLogEvents(q FilterQuery, nextToken string) ([]FilteredLogEvent, string, error) {
+	result := filterLogEventsResult{}
+	body := filterLogEventsRequest{LogGroupName: q.Group, LogStreamNames: q.StreamNames, FilterPattern: q.Pattern, NextToken: nextToken}
+	if !q.Start.IsZero() {
+		body.StartTime = milliseconds(q.Start)
+	}
+	if !q.End.IsZero() {
+		body.EndTime = milliseconds(q.End)
+	}
+
+	bodyAsJson, err := json.Marshal(body)
+	if err != nil {
+		return result.Events, "", err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "Logs_20140328.FilterLogEvents"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Events, "", err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Events, result.NextToken, err
+}
+
+// FilterLogEvents gets the events in a log group that match the query, from every page, sorted by time so the events of different streams are interleaved.
+// It is calling the FilterLogEvents API call.
+// See http://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_FilterLogEvents.html for more details.
+func (s *CloudWatchLogsService) FilterLogEvents(q FilterQuery) ([]FilteredLogEvent, error) {
+	events := []FilteredLogEvent{}
+	nextToken := ""
+
+	for {
+		found, token, err := s.filterLogEvents(q, nextToken)
+		if err != nil {
+			return events, err
+		}
+		events = append(events, found...)
+
+		if token == "" {
+			sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+			return events, nil
+		}
+		nextToken = token
+	}
+}
+
+// FollowLogEvents creates a goroutine that sends the events in a log group that match the query over a channel as they arrive, like tail -f.
+// It starts at query.Start, or now if it is zero, and checks for new events every FollowInterval. Each event is sent once, in time order.
+// Events that arrive after events with later timestamps were sent are missed. If it encounters an error, it sends it over the error channel
+// and closes the event channel. Close done to stop it, which also closes the event channel.
+func (s *CloudWatchLogsService) FollowLogEvents(q FilterQuery, done <-chan bool) (<-chan FilteredLogEvent, <-chan error) {
+	c := make(chan FilteredLogEvent)
+	errc := make(chan error, 1)
+	if q.Start.IsZero() {
+		q.Start = time.Now()
+	}
+	q.End = time.Time{}
+	interval := FollowInterval
+
+	go func() {
+		defer close(c)
+
+		// The IDs of the events sent with the latest timestamp, which are found again by the next check
+		seen := map[string]bool{}
+
+		for {
+			events, err := s.FilterLogEvents(q)
+			if err != nil {
+				errc <- err
+				return
+			}
+
+			for _, e := range events {
+				if seen[e.EventId] {
+					continue
+				}
+
+				select {
+				case c <- e:
+				case <-done:
+					return
+				}
+
+				if e.Time().After(q.Start) {
+					q.Start = e.Time()
+					seen = map[string]bool{}
+				}
+				seen[e.EventId] = true
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return c, errc
+}
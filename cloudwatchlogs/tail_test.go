@@ -0,0 +1,139 @@
+package cloudwatchlogs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testLogGroup is a log group that FilterLogEvents can be run against. It returns one event per page.
+type testLogGroup struct {
+	lock   sync.Mutex
+	events []FilteredLogEvent
+}
+
+func (g *testLogGroup) add(stream string, timestamp int64, message string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.events = append(g.events, FilteredLogEvent{EventId: strconv.Itoa(len(g.events)), LogStreamName: stream, Timestamp: timestamp, Message: message})
+}
+
+func (g *testLogGroup) handler(w http.ResponseWriter, r *http.Request) {
+	request := filterLogEventsRequest{}
+	json.NewDecoder(r.Body).Decode(&request)
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	skip, _ := strconv.Atoi(request.NextToken)
+	result := filterLogEventsResult{Events: []FilteredLogEvent{}}
+	for i, e := range g.events {
+		if e.Timestamp < request.StartTime || i < skip {
+			continue
+		}
+		result.Events = append(result.Events, e)
+		if i+1 < len(g.events) {
+			result.NextToken = strconv.Itoa(i + 1)
+		}
+		break
+	}
+
+	b, _ := json.Marshal(result)
+	w.Write(b)
+}
+
+func TestFilterLogEvents(t *testing.T) {
+	start := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	Convey("Given events in several streams that take several pages", t, func() {
+		g := &testLogGroup{}
+		g.add("web-1", 1425211202000, "third")
+		g.add("web-2", 1425211200000, "first")
+		g.add("web-1", 1425211201000, "second")
+
+		ts, s, requests := testService(g.handler)
+		defer ts.Close()
+
+		events, err := s.FilterLogEvents(FilterQuery{Group: "app", StreamNames: []string{"web-1", "web-2"}, Pattern: "ERROR", Start: start, End: start.Add(time.Hour)})
+
+		Convey("Every page is returned, interleaved by time", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 3)
+			So(len(events), ShouldEqual, 3)
+			So(events[0].Message, ShouldEqual, "first")
+			So(events[0].LogStreamName, ShouldEqual, "web-2")
+			So(events[1].Message, ShouldEqual, "second")
+			So(events[2].Message, ShouldEqual, "third")
+			So(events[0].Time(), ShouldResemble, time.Unix(1425211200, 0))
+		})
+		Convey("The query is sent", func() {
+			So(requests()[0].Target, ShouldEqual, "Logs_20140328.FilterLogEvents")
+			So(string(requests()[0].Body), ShouldEqual, `{"logGroupName":"app","logStreamNames":["web-1","web-2"],"filterPattern":"ERROR","startTime":1425211200000,"endTime":1425214800000}`)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.FilterLogEvents(FilterQuery{Group: "app"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestFollowLogEvents(t *testing.T) {
+	defer func(interval time.Duration) { FollowInterval = interval }(FollowInterval)
+	FollowInterval = 10 * time.Millisecond
+
+	Convey("Given a log group that gets new events", t, func() {
+		g := &testLogGroup{}
+		g.add("web-1", 1425211199000, "before the start")
+		g.add("web-1", 1425211200000, "first")
+		g.add("web-2", 1425211200000, "second")
+
+		ts, s, _ := testService(g.handler)
+		defer ts.Close()
+
+		done := make(chan bool)
+		events, errc := s.FollowLogEvents(FilterQuery{Group: "app", Start: time.Unix(1425211200, 0)}, done)
+
+		received := []string{}
+		for len(received) < 2 {
+			received = append(received, (<-events).Message)
+		}
+		g.add("web-1", 1425211200000, "third")
+		g.add("web-2", 1425211201000, "fourth")
+		for len(received) < 4 {
+			received = append(received, (<-events).Message)
+		}
+		close(done)
+
+		Convey("Each event from the start is sent once, in order", func() {
+			So(received, ShouldResemble, []string{"first", "second", "third", "fourth"})
+		})
+		Convey("The event channel is closed when it is stopped", func() {
+			for range events {
+			}
+			So(len(errc), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		events, errc := s.FollowLogEvents(FilterQuery{Group: "app"}, make(chan bool))
+
+		Convey("The error is sent and the event channel is closed", func() {
+			So(<-errc, ShouldResemble, notFoundError)
+			_, open := <-events
+			So(open, ShouldBeFalse)
+		})
+	})
+}
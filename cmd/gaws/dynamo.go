@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/controlgroup/gaws/dynamodb"
+)
+
+func (c *cli) table(name string) *dynamodb.Table {
+	return &dynamodb.Table{Name: name, Service: dynamodb.New(c.options()...)}
+}
+
+// dynamoGet prints the item with a key as JSON. It prints nothing if there is no such item.
+func (c *cli) dynamoGet(args []string) error {
+	flags := flag.NewFlagSet("dynamo get", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	consistent := flags.Bool("consistent", false, "read every write that succeeded before the read")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 2 {
+		return errUsage
+	}
+
+	key := dynamodb.Item{}
+	err := json.Unmarshal([]byte(flags.Arg(1)), &key)
+	if err != nil {
+		return fmt.Errorf("the key is not a DynamoDB item: %v", err)
+	}
+
+	item, err := c.table(flags.Arg(0)).GetItem(key, *consistent)
+	if err != nil || item == nil {
+		return err
+	}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(c.stdout, string(b))
+	return nil
+}
+
+// dynamoPut puts an item, or the item on standard input if it is not given.
+func (c *cli) dynamoPut(args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return errUsage
+	}
+
+	var data []byte
+	if len(args) == 2 {
+		data = []byte(args[1])
+	} else {
+		var err error
+		data, err = ioutil.ReadAll(c.stdin)
+		if err != nil {
+			return err
+		}
+	}
+
+	item := dynamodb.Item{}
+	err := json.Unmarshal(data, &item)
+	if err != nil {
+		return fmt.Errorf("the item is not a DynamoDB item: %v", err)
+	}
+	return c.table(args[0]).PutItem(item)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// readAll reads the body of a request.
+func readAll(r *http.Request) []byte {
+	b, _ := ioutil.ReadAll(r.Body)
+	return b
+}
+
+func TestDynamoGet(t *testing.T) {
+	Convey("Given an item", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("GetItem", 200, `{"Item":{"id":{"S":"1"},"name":{"S":"foo"}}}`)
+
+		out, err := testRun(s, "", "dynamo", "get", "-consistent", "users", `{"id":{"S":"1"}}`)
+
+		Convey("It prints the item", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, `{"id":{"S":"1"},"name":{"S":"foo"}}`+"\n")
+		})
+		Convey("It asks for the key", func() {
+			So(string(s.RequestsFor("GetItem")[0].Body), ShouldEqual, `{"ConsistentRead":true,"Key":{"id":{"S":"1"}},"TableName":"users"}`)
+		})
+	})
+	Convey("Given no item", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("GetItem", 200, `{}`)
+
+		out, err := testRun(s, "", "dynamo", "get", "users", `{"id":{"S":"2"}}`)
+
+		Convey("It prints nothing", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "")
+		})
+	})
+	Convey("Given a key that is not JSON", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+
+		_, err := testRun(s, "", "dynamo", "get", "users", `id=1`)
+
+		Convey("It returns an error without a request", func() {
+			So(err, ShouldNotBeNil)
+			So(len(s.Requests()), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestDynamoPut(t *testing.T) {
+	Convey("Given an item", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("PutItem", 200, `{}`)
+
+		_, err := testRun(s, "", "dynamo", "put", "users", `{"id":{"S":"1"}}`)
+
+		Convey("It puts the item", func() {
+			So(err, ShouldBeNil)
+			So(string(s.RequestsFor("PutItem")[0].Body), ShouldEqual, `{"Item":{"id":{"S":"1"}},"TableName":"users"}`)
+		})
+	})
+	Convey("Given an item on standard input", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("PutItem", 200, `{}`)
+
+		_, err := testRun(s, `{"id":{"S":"2"}}`, "dynamo", "put", "users")
+
+		Convey("It puts the item", func() {
+			So(err, ShouldBeNil)
+			So(string(s.RequestsFor("PutItem")[0].Body), ShouldEqual, `{"Item":{"id":{"S":"2"}},"TableName":"users"}`)
+		})
+	})
+	Convey("Given an error", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.RespondError("PutItem", 400, "ResourceNotFoundException", "Requested resource not found")
+
+		_, err := testRun(s, "", "dynamo", "put", "users", `{"id":{"S":"1"}}`)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws/kinesis"
+)
+
+// PollInterval is how long tail waits before asking a shard for records again when it had none.
+var PollInterval = time.Second
+
+func (c *cli) kinesis() *kinesis.KinesisService {
+	return kinesis.New(c.options()...)
+}
+
+// kinesisList prints the name of each stream.
+func (c *cli) kinesisList(args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	streams, err := c.kinesis().ListStreams()
+	if err != nil {
+		return err
+	}
+	for _, s := range streams {
+		fmt.Fprintln(c.stdout, s.Name)
+	}
+	return nil
+}
+
+// kinesisPut puts data to a stream, or each line of standard input if there is no data.
+func (c *cli) kinesisPut(args []string) error {
+	if len(args) != 2 && len(args) != 3 {
+		return errUsage
+	}
+	stream := kinesis.Stream{Name: args[0], Service: c.kinesis()}
+	partitionKey := args[1]
+
+	if len(args) == 3 {
+		return stream.PutRecord(partitionKey, []byte(args[2]))
+	}
+
+	lines := bufio.NewScanner(c.stdin)
+	for lines.Scan() {
+		err := stream.PutRecord(partitionKey, lines.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return lines.Err()
+}
+
+// kinesisTail prints the data of the records in every shard of a stream as they arrive, one per line.
+// It stops after -n records, if -n is given, or at the first error.
+func (c *cli) kinesisTail(args []string) error {
+	flags := flag.NewFlagSet("kinesis tail", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	from := flags.String("from", "latest", "where to start reading each shard: latest or trim_horizon")
+	max := flags.Int("n", 0, "stop after this many records")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+
+	stream := kinesis.Stream{Name: flags.Arg(0), Service: c.kinesis()}
+	description, err := stream.Describe()
+	if err != nil {
+		return err
+	}
+
+	if len(description.Shards) == 0 {
+		return nil
+	}
+
+	records := make(chan kinesis.Record)
+	errs := make(chan error, len(description.Shards))
+	done := make(chan bool)
+	var readers sync.WaitGroup
+
+	for i := range description.Shards {
+		iterator, err := description.Shards[i].GetShardIterator(strings.ToUpper(*from), "")
+		if err != nil {
+			close(done)
+			readers.Wait()
+			return err
+		}
+
+		readers.Add(1)
+		go func(iterator string) {
+			defer readers.Done()
+			errs <- c.readShard(iterator, records, done)
+		}(iterator)
+	}
+
+	printed := 0
+	for {
+		select {
+		case r := <-records:
+			data, err := r.Bytes()
+			if err != nil {
+				close(done)
+				readers.Wait()
+				return err
+			}
+			fmt.Fprintln(c.stdout, string(data))
+
+			printed++
+			if printed == *max {
+				close(done)
+				readers.Wait()
+				return nil
+			}
+		case err := <-errs:
+			close(done)
+			readers.Wait()
+			return err
+		}
+	}
+}
+
+// readShard sends the records of a shard from the iterator to records until done is closed or the shard is closed.
+func (c *cli) readShard(iterator string, records chan<- kinesis.Record, done <-chan bool) error {
+	k := c.kinesis()
+	for iterator != "" {
+		batch, next, err := k.GetRecords(iterator, 0)
+		if err != nil {
+			return err
+		}
+		iterator = next
+
+		for _, r := range batch {
+			select {
+			case records <- r:
+			case <-done:
+				return nil
+			}
+		}
+
+		if len(batch) == 0 {
+			select {
+			case <-time.After(PollInterval):
+			case <-done:
+				return nil
+			}
+		}
+	}
+
+	// The shard was closed, by a split or a merge. Its children are not followed.
+	<-done
+	return nil
+}
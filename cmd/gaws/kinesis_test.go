@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKinesisList(t *testing.T) {
+	Convey("Given streams", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, `{"StreamNames":["clicks","views"],"HasMoreStreams":false}`)
+
+		out, err := testRun(s, "", "kinesis", "list")
+
+		Convey("It prints their names", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "clicks\nviews\n")
+		})
+	})
+	Convey("Given an error", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.RespondError("ListStreams", 400, "AccessDeniedException", "Not allowed")
+
+		_, err := testRun(s, "", "kinesis", "list")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestKinesisPut(t *testing.T) {
+	Convey("Given data", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("PutRecord", 200, `{"SequenceNumber":"1","ShardId":"shardId-000000000000"}`)
+
+		_, err := testRun(s, "", "kinesis", "put", "clicks", "user-1", "hello")
+
+		Convey("It puts one record", func() {
+			So(err, ShouldBeNil)
+			So(s, gawstest.ShouldHaveReceived, "PutRecord", 1)
+
+			body := map[string]string{}
+			s.RequestsFor("PutRecord")[0].Decode(&body)
+			So(body["StreamName"], ShouldEqual, "clicks")
+			So(body["PartitionKey"], ShouldEqual, "user-1")
+			So(body["Data"], ShouldEqual, base64.StdEncoding.EncodeToString([]byte("hello")))
+		})
+	})
+	Convey("Given lines on standard input", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("PutRecord", 200, `{"SequenceNumber":"1","ShardId":"shardId-000000000000"}`)
+
+		_, err := testRun(s, "one\ntwo\nthree\n", "kinesis", "put", "clicks", "user-1")
+
+		Convey("It puts a record for each line", func() {
+			So(err, ShouldBeNil)
+			So(s, gawstest.ShouldHaveReceived, "PutRecord", 3)
+		})
+	})
+	Convey("Given no partition key", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+
+		_, err := testRun(s, "", "kinesis", "put", "clicks")
+
+		Convey("It is a usage error", func() {
+			So(err, ShouldEqual, errUsage)
+		})
+	})
+}
+
+func TestKinesisTail(t *testing.T) {
+	PollInterval = time.Millisecond
+
+	Convey("Given a stream with two shards", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("DescribeStream", 200, `{"StreamDescription":{"StreamName":"clicks","StreamStatus":"ACTIVE",
+			"Shards":[{"ShardId":"shardId-000000000000"},{"ShardId":"shardId-000000000001"}]}}`)
+		s.Handle("GetShardIterator", func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]string{}
+			gawstest.Request{Body: readAll(r)}.Decode(&body)
+			w.Write([]byte(`{"ShardIterator":"` + body["ShardId"] + `"}`))
+		})
+		s.Handle("GetRecords", func(w http.ResponseWriter, r *http.Request) {
+			body := map[string]interface{}{}
+			gawstest.Request{Body: readAll(r)}.Decode(&body)
+			if body["ShardIterator"] == "shardId-000000000000" {
+				w.Write([]byte(`{"Records":[{"Data":"` + base64.StdEncoding.EncodeToString([]byte("hello")) + `","PartitionKey":"a","SequenceNumber":"1"}],"NextShardIterator":"empty"}`))
+				return
+			}
+			w.Write([]byte(`{"Records":[],"NextShardIterator":"empty"}`))
+		})
+
+		out, err := testRun(s, "", "kinesis", "tail", "-from", "trim_horizon", "-n", "1", "clicks")
+
+		Convey("It prints the records", func() {
+			So(err, ShouldBeNil)
+			So(out, ShouldEqual, "hello\n")
+		})
+		Convey("It reads every shard from where it was asked to", func() {
+			So(s, gawstest.ShouldHaveReceived, "GetShardIterator", 2)
+			body := map[string]string{}
+			s.RequestsFor("GetShardIterator")[0].Decode(&body)
+			So(body["ShardIteratorType"], ShouldEqual, "TRIM_HORIZON")
+		})
+	})
+	Convey("Given a stream that does not exist", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.RespondError("DescribeStream", 400, "ResourceNotFoundException", "Stream clicks under account 123456789012 not found.")
+
+		_, err := testRun(s, "", "kinesis", "tail", "clicks")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a shard that fails", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+		s.Respond("DescribeStream", 200, `{"StreamDescription":{"StreamName":"clicks","Shards":[{"ShardId":"shardId-000000000000"}]}}`)
+		s.Respond("GetShardIterator", 200, `{"ShardIterator":"iterator"}`)
+		s.RespondError("GetRecords", 400, "ExpiredIteratorException", "Iterator expired")
+
+		_, err := testRun(s, "", "kinesis", "tail", "clicks")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
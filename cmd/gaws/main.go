@@ -0,0 +1,97 @@
+// Command gaws is a small command line tool for the services gaws supports. It signs requests with the credentials awsauth finds
+// in the environment or the instance's role.
+//
+//	gaws [-region us-east-1] [-endpoint url] kinesis list
+//	gaws kinesis put <stream> <partition key> [data]
+//	gaws kinesis tail [-from latest|trim_horizon] [-n max] <stream>
+//	gaws dynamo get [-consistent] <table> <key>
+//	gaws dynamo put <table> [item]
+//
+// Keys and items are JSON in DynamoDB's form, like {"id":{"S":"1"}}. When put is not given data or an item, it reads them from
+// standard input, one record per line for kinesis, or one item for dynamo.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/controlgroup/gaws"
+)
+
+const usage = `usage: gaws [-region region] [-endpoint url] <command>
+
+commands:
+  kinesis list                                        list streams
+  kinesis put <stream> <partition key> [data]         put a record, or a record for each line of standard input
+  kinesis tail [-from type] [-n max] <stream>         print the records put to a stream
+  dynamo get [-consistent] <table> <key>              print an item
+  dynamo put <table> [item]                           put an item, or the item on standard input
+`
+
+// errUsage is returned when a command is not used correctly.
+var errUsage = errors.New("bad usage")
+
+// cli is the options for a run of the command.
+type cli struct {
+	region   string
+	endpoint string
+	stdin    io.Reader
+	stdout   io.Writer
+}
+
+// options returns the options for the service clients, from the -region and -endpoint flags.
+func (c *cli) options() []gaws.Option {
+	options := []gaws.Option{gaws.WithRegion(c.region)}
+	if c.endpoint != "" {
+		options = append(options, gaws.WithEndpoint(c.endpoint))
+	}
+	return options
+}
+
+// run runs the command in args.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	c := &cli{stdin: stdin, stdout: stdout}
+
+	flags := flag.NewFlagSet("gaws", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.StringVar(&c.region, "region", gaws.Region, "the region of the service")
+	flags.StringVar(&c.endpoint, "endpoint", "", "the URL of the service, instead of the region's endpoint")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	args = flags.Args()
+	if len(args) < 2 {
+		return errUsage
+	}
+
+	switch args[0] + " " + args[1] {
+	case "kinesis list":
+		return c.kinesisList(args[2:])
+	case "kinesis put":
+		return c.kinesisPut(args[2:])
+	case "kinesis tail":
+		return c.kinesisTail(args[2:])
+	case "dynamo get":
+		return c.dynamoGet(args[2:])
+	case "dynamo put":
+		return c.dynamoPut(args[2:])
+	}
+	return errUsage
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	if err == errUsage {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gaws:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/controlgroup/gaws/gawstest"
+	"github.com/controlgroup/gaws/kinesis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testRun runs the command against the server, and returns what it printed.
+func testRun(s *gawstest.Server, stdin string, args ...string) (string, error) {
+	stdout := &bytes.Buffer{}
+	err := run(append([]string{"-endpoint", s.URL}, args...), strings.NewReader(stdin), stdout)
+	return stdout.String(), err
+}
+
+func TestRun(t *testing.T) {
+	Convey("Given commands that do not exist or are missing", t, func() {
+		s := gawstest.NewServer()
+		defer s.Close()
+
+		Convey("They are usage errors", func() {
+			_, err := testRun(s, "")
+			So(err, ShouldEqual, errUsage)
+			_, err = testRun(s, "", "kinesis")
+			So(err, ShouldEqual, errUsage)
+			_, err = testRun(s, "", "kinesis", "rewind")
+			So(err, ShouldEqual, errUsage)
+			_, err = testRun(s, "", "-bogus", "kinesis", "list")
+			So(err, ShouldEqual, errUsage)
+		})
+	})
+	Convey("Given a region", t, func() {
+		c := &cli{region: "eu-west-1"}
+
+		Convey("The region's endpoint is used", func() {
+			So(kinesis.New(c.options()...).Endpoint, ShouldEqual, "https://kinesis.eu-west-1.amazonaws.com")
+		})
+		Convey("An endpoint wins over the region", func() {
+			c.endpoint = "http://localhost:4567"
+			So(kinesis.New(c.options()...).Endpoint, ShouldEqual, "http://localhost:4567")
+		})
+	})
+}
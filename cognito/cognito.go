@@ -0,0 +1,101 @@
+// Package cognito provides a way to interact with the AWS Cognito Identity service.
+package cognito
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+//go:generate go run ../internal/errgen -type cognitoError -field Type -service Cognito ResourceNotFoundException ResourceConflictException NotAuthorizedException InvalidParameterException LimitExceededException TooManyRequestsException
+
+// cognitoError is the error document returned from the Cognito Identity service.
+type cognitoError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the cognitoError into an error message.
+func (e cognitoError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e cognitoError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func cognitoRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := cognitoError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("TooManyRequestsException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// unsigned leaves a request unsigned. The calls in this package are public, since they are how devices without credentials get them.
+func unsigned(req *http.Request, keys ...awsauth.Credentials) *http.Request {
+	return req
+}
+
+// CognitoService is the Cognito Identity service at AWS. The endpoint for a region is gaws.Endpoint("cognito-identity", region).
+type CognitoService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// CognitoAPI is implemented by *CognitoService. Code that takes a CognitoAPI can be given a fake in tests instead.
+type CognitoAPI interface {
+	GetCredentialsForIdentity(identityId string, logins Logins) (Credentials, error)
+	GetId(identityPoolId string, logins Logins) (string, error)
+	GetOpenIdToken(identityId string, logins Logins) (string, error)
+}
+
+var _ CognitoAPI = (*CognitoService)(nil)
+
+// New returns a CognitoService with the options applied. Its endpoint is gaws.Endpoint("cognito-identity", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *CognitoService {
+	config := gaws.NewConfig(options...)
+	return &CognitoService{Endpoint: config.ServiceEndpoint("cognito-identity"), config: config}
+}
+
+// request returns an unsigned request. It uses empty credentials, so gaws.Credentials is never asked for them, even if it is an IdentityProvider.
+func (s *CognitoService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: cognitoRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+		Signer:      unsigned,
+		Credentials: gaws.StaticCredentials{},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Logins are tokens from identity providers, keyed by the name of the provider, like graph.facebook.com or accounts.google.com.
+// Identities without logins are unauthenticated.
+type Logins map[string]string
@@ -0,0 +1,142 @@
+package cognito
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var notAuthorizedError = cognitoError{Type: "NotAuthorizedException", Message: "Invalid login token."}
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(notAuthorizedError)
+
+	w.WriteHeader(400)
+	w.Write(b)
+}
+
+// testJson returns a handler that returns body.
+func testJson(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Target string // The X-Amz-Target header
+	Body   []byte
+}
+
+// testService returns a service on a server that runs handler, and a function that returns the requests it has gotten so far.
+func testService(handler http.HandlerFunc) (*httptest.Server, *CognitoService, func() []testRequest) {
+	var lock sync.Mutex
+	requests := []testRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		lock.Lock()
+		requests = append(requests, testRequest{Target: r.Header.Get("X-Amz-Target"), Body: body})
+		lock.Unlock()
+		handler(w, r)
+	}))
+
+	return ts, &CognitoService{Endpoint: ts.URL}, func() []testRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]testRequest{}, requests...)
+	}
+}
+
+// testCountingProvider counts how many times it is asked for credentials.
+type testCountingProvider struct {
+	calls int
+}
+
+func (p *testCountingProvider) Credentials() (awsauth.Credentials, error) {
+	p.calls++
+	return awsauth.Credentials{AccessKeyID: "AKID"}, nil
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := cognitoRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a too many requests error", t, func() {
+		result, _ := cognitoRetryPredicate(400, []byte(`{"__type":"TooManyRequestsException","message":"Rate exceeded"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a login that is not valid", t, func() {
+		result, err := cognitoRetryPredicate(400, []byte(`{"__type":"NotAuthorizedException","message":"Invalid login token."}`))
+
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, notAuthorizedError)
+		})
+	})
+	Convey("Given a server error", t, func() {
+		result, _ := cognitoRetryPredicate(500, []byte(`{"__type":"InternalErrorException"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+}
+
+func TestRequest(t *testing.T) {
+	Convey("Given gaws.Credentials", t, func() {
+		ts, s, _ := testService(testJson(`{"IdentityId":"us-east-1:1234"}`))
+		defer ts.Close()
+		provider := &testCountingProvider{}
+		gaws.Credentials = provider
+		defer func() { gaws.Credentials = nil }()
+
+		_, err := s.GetId("us-east-1:pool", nil)
+
+		Convey("They are not asked for", func() {
+			So(err, ShouldBeNil)
+			So(provider.calls, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("cognito-identity", "eu-west-1"))
+		})
+		Convey("Requests are still unsigned", func() {
+			So(s.request().Credentials, ShouldResemble, gaws.StaticCredentials{})
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
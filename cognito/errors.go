@@ -0,0 +1,21 @@
+// Code generated by errgen from the go:generate line in cognito.go. DO NOT EDIT.
+
+package cognito
+
+import "strings"
+
+// These are errors Cognito returns. errors.Is(err, cognito.ErrResourceNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrResourceNotFound = cognitoError{Type: "ResourceNotFoundException"}
+	ErrResourceConflict = cognitoError{Type: "ResourceConflictException"}
+	ErrNotAuthorized    = cognitoError{Type: "NotAuthorizedException"}
+	ErrInvalidParameter = cognitoError{Type: "InvalidParameterException"}
+	ErrLimitExceeded    = cognitoError{Type: "LimitExceededException"}
+	ErrTooManyRequests  = cognitoError{Type: "TooManyRequestsException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e cognitoError) Is(target error) bool {
+	t, ok := target.(cognitoError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
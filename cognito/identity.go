@@ -0,0 +1,145 @@
+package cognito
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+type getIdRequest struct {
+	IdentityPoolId string
+	Logins         Logins `json:",omitempty"`
+}
+
+type getIdResult struct {
+	IdentityId string
+}
+
+// GetId returns the ID of the identity in the pool for the logins, creating it if it does not exist.
+// If there are no logins, it creates an unauthenticated identity. It is calling the GetId API call.
+// See http://docs.aws.amazon.com/cognitoidentity/latest/APIReference/API_GetId.html for more details.
+func (s *CognitoService) GetId(identityPoolId string, logins Logins) (string, error) {
+	result := getIdResult{}
+
+	bodyAsJson, err := json.Marshal(getIdRequest{IdentityPoolId: identityPoolId, Logins: logins})
+	if err != nil {
+		return result.IdentityId, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AWSCognitoIdentityService.GetId"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.IdentityId, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.IdentityId, err
+}
+
+type identityRequest struct {
+	IdentityId string
+	Logins     Logins `json:",omitempty"`
+}
+
+type getOpenIdTokenResult struct {
+	IdentityId string
+	Token      string
+}
+
+// GetOpenIdToken returns an OpenID Connect token for the identity, which is valid for 10 minutes. It can be exchanged
+// for credentials with sts.AssumeRoleWithWebIdentity. Authenticated identities need their logins. It is calling the GetOpenIdToken API call.
+// See http://docs.aws.amazon.com/cognitoidentity/latest/APIReference/API_GetOpenIdToken.html for more details.
+func (s *CognitoService) GetOpenIdToken(identityId string, logins Logins) (string, error) {
+	result := getOpenIdTokenResult{}
+
+	bodyAsJson, err := json.Marshal(identityRequest{IdentityId: identityId, Logins: logins})
+	if err != nil {
+		return result.Token, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AWSCognitoIdentityService.GetOpenIdToken"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Token, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Token, err
+}
+
+// Credentials are temporary security credentials for an identity. They are a gaws.CredentialsProvider, so they can be used as gaws.Credentials,
+// or as the Credentials of a request or service.
+type Credentials struct {
+	AccessKeyId  string
+	SecretKey    string
+	SessionToken string
+	Expiration   time.Time
+}
+
+// Credentials returns the credentials for awsauth to sign requests with.
+func (c Credentials) Credentials() (awsauth.Credentials, error) {
+	return awsauth.Credentials{
+		AccessKeyID:     c.AccessKeyId,
+		SecretAccessKey: c.SecretKey,
+		SecurityToken:   c.SessionToken,
+		Expiration:      c.Expiration,
+	}, nil
+}
+
+// Expired returns true if the credentials expire within d.
+func (c Credentials) Expired(d time.Duration) bool {
+	return !c.Expiration.After(time.Now().Add(d))
+}
+
+type getCredentialsForIdentityResult struct {
+	IdentityId  string
+	Credentials struct {
+		AccessKeyId  string
+		SecretKey    string
+		SessionToken string
+		Expiration   float64 // Seconds since the epoch
+	}
+}
+
+// GetCredentialsForIdentity returns credentials for the identity, for the role its pool gives authenticated or unauthenticated identities.
+// Authenticated identities need their logins. It is calling the GetCredentialsForIdentity API call.
+// See http://docs.aws.amazon.com/cognitoidentity/latest/APIReference/API_GetCredentialsForIdentity.html for more details.
+func (s *CognitoService) GetCredentialsForIdentity(identityId string, logins Logins) (Credentials, error) {
+	result := getCredentialsForIdentityResult{}
+
+	bodyAsJson, err := json.Marshal(identityRequest{IdentityId: identityId, Logins: logins})
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AWSCognitoIdentityService.GetCredentialsForIdentity"
+
+	resp, err := req.Do()
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	seconds, fraction := math.Modf(result.Credentials.Expiration)
+	return Credentials{
+		AccessKeyId:  result.Credentials.AccessKeyId,
+		SecretKey:    result.Credentials.SecretKey,
+		SessionToken: result.Credentials.SessionToken,
+		Expiration:   time.Unix(int64(seconds), int64(fraction*1e9)),
+	}, nil
+}
@@ -0,0 +1,133 @@
+package cognito
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetId(t *testing.T) {
+	Convey("Given a pool and logins", t, func() {
+		ts, s, requests := testService(testJson(`{"IdentityId":"us-east-1:1234"}`))
+		defer ts.Close()
+
+		id, err := s.GetId("us-east-1:pool", Logins{"accounts.google.com": "google-token"})
+
+		Convey("It returns the identity", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "us-east-1:1234")
+		})
+		Convey("It sends the logins", func() {
+			So(requests()[0].Target, ShouldEqual, "AWSCognitoIdentityService.GetId")
+			So(string(requests()[0].Body), ShouldEqual, `{"IdentityPoolId":"us-east-1:pool","Logins":{"accounts.google.com":"google-token"}}`)
+		})
+	})
+	Convey("Given no logins", t, func() {
+		ts, s, requests := testService(testJson(`{"IdentityId":"us-east-1:1234"}`))
+		defer ts.Close()
+
+		s.GetId("us-east-1:pool", nil)
+
+		Convey("They are not sent", func() {
+			So(string(requests()[0].Body), ShouldEqual, `{"IdentityPoolId":"us-east-1:pool"}`)
+		})
+	})
+	Convey("Given a login that is not valid", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.GetId("us-east-1:pool", Logins{"accounts.google.com": "bad-token"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notAuthorizedError)
+		})
+	})
+}
+
+func TestGetOpenIdToken(t *testing.T) {
+	Convey("Given an identity", t, func() {
+		ts, s, requests := testService(testJson(`{"IdentityId":"us-east-1:1234","Token":"open-id-token"}`))
+		defer ts.Close()
+
+		token, err := s.GetOpenIdToken("us-east-1:1234", nil)
+
+		Convey("It returns the token", func() {
+			So(err, ShouldBeNil)
+			So(token, ShouldEqual, "open-id-token")
+			So(requests()[0].Target, ShouldEqual, "AWSCognitoIdentityService.GetOpenIdToken")
+			So(string(requests()[0].Body), ShouldEqual, `{"IdentityId":"us-east-1:1234"}`)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.GetOpenIdToken("us-east-1:1234", nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetCredentialsForIdentity(t *testing.T) {
+	Convey("Given an identity", t, func() {
+		ts, s, requests := testService(testJson(`{"IdentityId":"us-east-1:1234","Credentials":
+			{"AccessKeyId":"ASIA1","SecretKey":"secret","SessionToken":"token","Expiration":1438003600.5}}`))
+		defer ts.Close()
+
+		credentials, err := s.GetCredentialsForIdentity("us-east-1:1234", Logins{"graph.facebook.com": "facebook-token"})
+
+		Convey("It returns the credentials", func() {
+			So(err, ShouldBeNil)
+			So(credentials.AccessKeyId, ShouldEqual, "ASIA1")
+			So(credentials.SecretKey, ShouldEqual, "secret")
+			So(credentials.SessionToken, ShouldEqual, "token")
+			So(credentials.Expiration.Equal(time.Date(2015, 7, 27, 13, 26, 40, 500000000, time.UTC)), ShouldBeTrue)
+		})
+		Convey("They can sign requests", func() {
+			keys, _ := credentials.Credentials()
+			So(keys.AccessKeyID, ShouldEqual, "ASIA1")
+			So(keys.SecretAccessKey, ShouldEqual, "secret")
+			So(keys.SecurityToken, ShouldEqual, "token")
+		})
+		Convey("It sends the logins", func() {
+			So(requests()[0].Target, ShouldEqual, "AWSCognitoIdentityService.GetCredentialsForIdentity")
+			So(string(requests()[0].Body), ShouldEqual, `{"IdentityId":"us-east-1:1234","Logins":{"graph.facebook.com":"facebook-token"}}`)
+		})
+	})
+	Convey("Given a login that is not valid", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.GetCredentialsForIdentity("us-east-1:1234", Logins{"graph.facebook.com": "bad-token"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notAuthorizedError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.GetCredentialsForIdentity("us-east-1:1234", nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestExpired(t *testing.T) {
+	Convey("Given credentials that expire in ten minutes", t, func() {
+		credentials := Credentials{Expiration: time.Now().Add(10 * time.Minute)}
+
+		Convey("They have not expired within five minutes", func() {
+			So(credentials.Expired(5*time.Minute), ShouldBeFalse)
+		})
+		Convey("They have expired within fifteen minutes", func() {
+			So(credentials.Expired(15*time.Minute), ShouldBeTrue)
+		})
+	})
+}
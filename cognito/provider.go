@@ -0,0 +1,94 @@
+package cognito
+
+import (
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// ExpiryWindow is how long before they expire an IdentityProvider gets new credentials, so requests are not signed with credentials that are about to expire.
+var ExpiryWindow = 5 * time.Minute
+
+// IdentityProvider is a gaws.CredentialsProvider that gets credentials for an identity in an identity pool, and gets them again
+// when they are about to expire. It lets devices without AWS credentials of their own, like phones putting records to Kinesis,
+// sign requests with the role of the pool. The identity is created the first time credentials are needed, and kept after that.
+type IdentityProvider struct {
+	Service        CognitoAPI // Usually a *CognitoService
+	IdentityPoolId string     // Like us-east-1:d2ae9c4a-4b3e-4a7b-8e0c-2b3f3e9a7c11
+	Logins         Logins     // If it is empty, the identity is unauthenticated
+
+	lock        sync.Mutex // Protects identityId and credentials, and makes sure only one request for credentials is made at a time
+	identityId  string
+	credentials Credentials
+}
+
+var _ gaws.RefreshingProvider = (*IdentityProvider)(nil)
+
+// NewIdentityProvider returns an IdentityProvider for the pool. It does not get an identity or credentials until credentials are needed.
+func (s *CognitoService) NewIdentityProvider(identityPoolId string, logins Logins) *IdentityProvider {
+	return &IdentityProvider{Service: s, IdentityPoolId: identityPoolId, Logins: logins}
+}
+
+// IdentityId returns the ID of the provider's identity, getting it if it does not have one yet.
+func (p *IdentityProvider) IdentityId() (string, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.getId()
+}
+
+// getId gets the identity if the provider does not have one yet. The lock must be held.
+func (p *IdentityProvider) getId() (string, error) {
+	if p.identityId == "" {
+		id, err := p.Service.GetId(p.IdentityPoolId, p.Logins)
+		if err != nil {
+			return "", err
+		}
+		p.identityId = id
+	}
+	return p.identityId, nil
+}
+
+// Credentials returns the credentials for the identity, getting them if they are missing or about to expire.
+func (p *IdentityProvider) Credentials() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.credentials.Expired(ExpiryWindow) {
+		err := p.getCredentials()
+		if err != nil {
+			return awsauth.Credentials{}, err
+		}
+	}
+	return p.credentials.Credentials()
+}
+
+// Refresh gets credentials for the identity now, even if the ones it has have not expired, and returns them. A gaws.Refresher calls it
+// to get them ahead of time. If it fails, the old credentials are kept.
+func (p *IdentityProvider) Refresh() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	err := p.getCredentials()
+	if err != nil {
+		return awsauth.Credentials{}, err
+	}
+	return p.credentials.Credentials()
+}
+
+// getCredentials gets credentials for the identity, and the identity if it does not have one yet. The lock must be held.
+func (p *IdentityProvider) getCredentials() error {
+	id, err := p.getId()
+	if err != nil {
+		return err
+	}
+
+	credentials, err := p.Service.GetCredentialsForIdentity(id, p.Logins)
+	if err != nil {
+		return err
+	}
+	p.credentials = credentials
+	return nil
+}
@@ -0,0 +1,139 @@
+package cognito
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testPool returns a handler that gives out one identity, with credentials that expire after d. Each has a new access key.
+func testPool(d time.Duration) http.HandlerFunc {
+	n := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") == "AWSCognitoIdentityService.GetId" {
+			w.Write([]byte(`{"IdentityId":"us-east-1:1234"}`))
+			return
+		}
+		n++
+		fmt.Fprintf(w, `{"IdentityId":"us-east-1:1234","Credentials":{"AccessKeyId":"ASIA%d","SecretKey":"secret","SessionToken":"token","Expiration":%d}}`,
+			n, time.Now().Add(d).Unix())
+	}
+}
+
+func TestIdentityProvider(t *testing.T) {
+	Convey("Given an identity provider", t, func() {
+		ts, s, requests := testService(testPool(time.Hour))
+		defer ts.Close()
+		p := s.NewIdentityProvider("us-east-1:pool", Logins{"accounts.google.com": "google-token"})
+
+		Convey("It gets the identity and credentials once and caches them", func() {
+			first, err := p.Credentials()
+			So(err, ShouldBeNil)
+			second, _ := p.Credentials()
+
+			So(len(requests()), ShouldEqual, 2)
+			So(requests()[0].Target, ShouldEqual, "AWSCognitoIdentityService.GetId")
+			So(string(requests()[1].Body), ShouldEqual, `{"IdentityId":"us-east-1:1234","Logins":{"accounts.google.com":"google-token"}}`)
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(first.SecurityToken, ShouldEqual, "token")
+			So(second, ShouldResemble, first)
+		})
+		Convey("Refresh gets new credentials even though they have not expired", func() {
+			first, _ := p.Credentials()
+			refreshed, err := p.Refresh()
+			So(err, ShouldBeNil)
+			second, _ := p.Credentials()
+
+			So(len(requests()), ShouldEqual, 3)
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(refreshed.AccessKeyID, ShouldEqual, "ASIA2")
+			So(second, ShouldResemble, refreshed)
+		})
+		Convey("It returns the identity", func() {
+			id, err := p.IdentityId()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "us-east-1:1234")
+		})
+
+		Convey("When it is gaws.Credentials", func() {
+			gaws.Credentials = p
+			defer func() { gaws.Credentials = nil }()
+
+			_, err := p.Credentials()
+
+			Convey("It does not sign its own requests with itself", func() {
+				So(err, ShouldBeNil)
+				So(len(requests()), ShouldEqual, 2)
+			})
+		})
+	})
+	Convey("Given credentials that are about to expire", t, func() {
+		ts, s, requests := testService(testPool(time.Minute))
+		defer ts.Close()
+		p := s.NewIdentityProvider("us-east-1:pool", nil)
+
+		first, _ := p.Credentials()
+		second, _ := p.Credentials()
+
+		Convey("They are gotten again for the same identity", func() {
+			So(len(requests()), ShouldEqual, 3)
+			So(requests()[2].Target, ShouldEqual, "AWSCognitoIdentityService.GetCredentialsForIdentity")
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(second.AccessKeyID, ShouldEqual, "ASIA2")
+		})
+	})
+	Convey("Given a login that is not valid", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		p := s.NewIdentityProvider("us-east-1:pool", Logins{"accounts.google.com": "bad-token"})
+		_, err := p.Credentials()
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notAuthorizedError)
+		})
+		Convey("It has no identity", func() {
+			_, err := p.IdentityId()
+			So(err, ShouldResemble, notAuthorizedError)
+		})
+	})
+}
+
+// testFakeCognito is a CognitoAPI that does not make requests. It counts the identities it gives out.
+type testFakeCognito struct {
+	ids int
+}
+
+func (f *testFakeCognito) GetId(identityPoolId string, logins Logins) (string, error) {
+	f.ids++
+	return fmt.Sprintf("us-east-1:identity-%d", f.ids), nil
+}
+
+func (f *testFakeCognito) GetOpenIdToken(identityId string, logins Logins) (string, error) {
+	return "token", nil
+}
+
+func (f *testFakeCognito) GetCredentialsForIdentity(identityId string, logins Logins) (Credentials, error) {
+	return Credentials{AccessKeyId: "AKID-" + identityId, SecretKey: "secret", Expiration: time.Now().Add(time.Hour)}, nil
+}
+
+func TestIdentityProviderWithFake(t *testing.T) {
+	Convey("Given a provider with a fake service", t, func() {
+		fake := &testFakeCognito{}
+		p := &IdentityProvider{Service: fake, IdentityPoolId: "us-east-1:pool"}
+
+		credentials, err := p.Credentials()
+		again, _ := p.Credentials()
+
+		Convey("It gets credentials from the fake, once", func() {
+			So(err, ShouldBeNil)
+			So(credentials.AccessKeyID, ShouldEqual, "AKID-us-east-1:identity-1")
+			So(again, ShouldResemble, credentials)
+			So(fake.ids, ShouldEqual, 1)
+		})
+	})
+}
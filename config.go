@@ -0,0 +1,292 @@
+package gaws
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy is how a failing request is retried. Its zero value uses MaxTries and a backoff that starts at 200 milliseconds.
+type RetryPolicy struct {
+	MaxTries int           // The number of times to try a request, so 1 does not retry. If it is 0, MaxTries is used.
+	Backoff  time.Duration // How long to wait before the first retry. The wait doubles after each retry.
+	Budget   *RetryBudget  // Limits retries, and is usually shared by every request of a client. If it is nil, DefaultRetryBudget is used.
+	Strategy Backoff       // How long to wait before each retry, like DecorrelatedJitter. If it is set, Backoff is not used.
+}
+
+// budget returns the retry budget, or nil if retries are not limited.
+func (p RetryPolicy) budget() *RetryBudget {
+	if p.Budget != nil {
+		return p.Budget
+	}
+	return DefaultRetryBudget
+}
+
+// isZero returns true if the policy is the zero value. It does not compare with ==, which panics if the Strategy is not comparable.
+func (p RetryPolicy) isZero() bool {
+	return p.MaxTries == 0 && p.Backoff == 0 && p.Budget == nil && p.Strategy == nil
+}
+
+// Tries returns the number of times to try a request. The package variable MaxTries has always been tried one time fewer than it says.
+// Service packages use it for retries they make themselves, like of the entries of a batch that failed.
+func (p RetryPolicy) Tries() int {
+	if p.MaxTries > 0 {
+		return p.MaxTries
+	}
+	return MaxTries - 1
+}
+
+// Delay returns how long to wait after the given try fails, when the wait after the try before was last, or 0 after the first try.
+// Service packages use it for retries they make themselves, like of the unprocessed items of a batch.
+func (p RetryPolicy) Delay(try int, last time.Duration) time.Duration {
+	if p.Strategy != nil {
+		return p.Strategy.Wait(try, last)
+	}
+	if p.Backoff > 0 {
+		return ExponentialBackoff{Base: p.Backoff}.Wait(try, last)
+	}
+	return ExponentialBackoff{Base: 200 * time.Millisecond}.Wait(try, last)
+}
+
+// Config is how a service client talks to AWS. Service packages make one in their New functions from options, like
+// kinesis.New(gaws.WithRegion("eu-west-1")), and apply it to every request. Anything that is not set falls back to the package variables,
+// like Region and Credentials.
+type Config struct {
+	Region      string              // The region of the service. It is Region unless WithRegion is given.
+	Endpoint    string              // The endpoint of the service. If it is empty, the endpoint for Region is used.
+	HTTPClient  *http.Client        // Sends the requests. If it is nil, a client using Transport is used.
+	RetryPolicy RetryPolicy         // How failing requests are retried
+	Credentials CredentialsProvider // The credentials requests are signed with. If it is nil, Credentials is used.
+
+	MaxResponseSize int64          // The most bytes of a response to read. If it is 0, MaxResponseSize is used.
+	ConnectionPool  ConnectionPool // How connections are kept for reuse. It is not used if HTTPClient is set.
+	DisableHTTP2    bool           // Makes requests use HTTP/1.1, even to endpoints that support HTTP/2. It is not used if HTTPClient is set.
+
+	Endpoints map[string]string // Endpoints of services, keyed by service like "kinesis", that are used instead of the ones in the region
+	DryRun    DryRunner         // If it is set, requests are given to it instead of being sent, as WithDryRun says
+	Router    *EndpointRouter   // If it is set, requests are sent to its best endpoint instead of the service's endpoint
+	Clock     Clock             // Times and waits for requests, and the client's background loops. If it is nil, DefaultClock is used.
+
+	WaitBackoff Backoff // How long the client's waiters sleep between checks. If it is nil, they sleep their package's WaitInterval.
+
+	QuotaLimits bool // Makes the client's consumers and producers keep to the service's quotas, as WithQuotaLimits says
+}
+
+// ConnectionPool tunes how a client keeps connections to AWS open for reuse. Clients that make many requests at once may want more idle
+// connections than the default of two to each endpoint. Fields that are 0 keep the defaults of http.DefaultTransport.
+type ConnectionPool struct {
+	MaxIdleConns        int           // The most idle connections to keep open, to every endpoint
+	MaxIdleConnsPerHost int           // The most idle connections to keep open to each endpoint
+	IdleConnTimeout     time.Duration // How long an idle connection is kept open
+	DialTimeout         time.Duration // How long connecting can take
+	KeepAlive           time.Duration // How often TCP keep-alives are sent on open connections
+	TLSHandshakeTimeout time.Duration // How long the TLS handshake can take, after connecting
+}
+
+// client returns an http.Client for the config, whose transport is http.DefaultTransport tuned by its ConnectionPool.
+// Like http.DefaultTransport, it uses HTTP/2 with endpoints that support it, and HTTP/1.1 with the rest, unless HTTP/2 is disabled.
+func (c Config) client() *http.Client {
+	p := c.ConnectionPool
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = !c.DisableHTTP2
+	if c.DisableHTTP2 {
+		// An empty, rather than nil, map of protocols stops the transport from offering HTTP/2.
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	if p.MaxIdleConns > 0 {
+		transport.MaxIdleConns = p.MaxIdleConns
+	}
+	if p.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = p.MaxIdleConnsPerHost
+	}
+	if p.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = p.IdleConnTimeout
+	}
+	if p.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = p.TLSHandshakeTimeout
+	}
+	if p.DialTimeout > 0 || p.KeepAlive > 0 {
+		// These are the dialer settings of http.DefaultTransport.
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if p.DialTimeout > 0 {
+			dialer.Timeout = p.DialTimeout
+		}
+		if p.KeepAlive > 0 {
+			dialer.KeepAlive = p.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	return &http.Client{Transport: transport}
+}
+
+// Option sets something in a Config.
+type Option func(*Config)
+
+// WithRegion makes a client use the service in a region, like eu-west-1.
+func WithRegion(region string) Option {
+	return func(c *Config) {
+		c.Region = region
+	}
+}
+
+// WithEndpoint makes a client use an endpoint, like a local emulator, instead of the endpoint for its region.
+func WithEndpoint(endpoint string) Option {
+	return func(c *Config) {
+		c.Endpoint = endpoint
+	}
+}
+
+// WithHTTPClient makes a client send requests with an http.Client, like one with a proxy. A request's own Timeout still applies.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = client
+	}
+}
+
+// WithRetryPolicy makes a client retry failing requests with a policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithBackoff makes a client wait between retries as the backoff says, like gaws.WithBackoff(gaws.DecorrelatedJitter{Base: 100 *
+// time.Millisecond, Cap: 5 * time.Second}). The rest of its retry policy is kept.
+func WithBackoff(backoff Backoff) Option {
+	return func(c *Config) {
+		c.RetryPolicy.Strategy = backoff
+	}
+}
+
+// WithWaitBackoff makes a client's waiters, like a DynamoDB table's WaitUntilActive, sleep between checks as the backoff says, like
+// gaws.WithWaitBackoff(gaws.CappedBackoff{Backoff: gaws.ExponentialBackoff{Base: time.Second}, Max: 30 * time.Second}).
+func WithWaitBackoff(backoff Backoff) Option {
+	return func(c *Config) {
+		c.WaitBackoff = backoff
+	}
+}
+
+// WithCredentials makes a client sign requests with credentials from a provider, instead of Credentials.
+func WithCredentials(credentials CredentialsProvider) Option {
+	return func(c *Config) {
+		c.Credentials = credentials
+	}
+}
+
+// WithMaxResponseSize makes a client fail requests whose response is larger than limit bytes, instead of reading it into memory.
+func WithMaxResponseSize(limit int64) Option {
+	return func(c *Config) {
+		c.MaxResponseSize = limit
+	}
+}
+
+// WithConnectionPool makes a client keep connections open for reuse as the pool says. The client's requests are sent with their own
+// http.Transport, not gaws.Transport. It does nothing if gaws.WithHTTPClient is also given.
+func WithConnectionPool(pool ConnectionPool) Option {
+	return func(c *Config) {
+		c.ConnectionPool = pool
+	}
+}
+
+// WithHTTP2 turns HTTP/2 on or off for a client. It is on by default, and is used with endpoints that support it, like Kinesis's for
+// SubscribeToShard. Other endpoints are sent HTTP/1.1. RequestMetrics.Protocol says which was used. Turning it off makes the client's
+// requests be sent with their own http.Transport, not gaws.Transport. It does nothing if gaws.WithHTTPClient is also given.
+func WithHTTP2(enabled bool) Option {
+	return func(c *Config) {
+		c.DisableHTTP2 = !enabled
+	}
+}
+
+// WithDryRun puts a client in dry-run mode: its requests are built and signed, but given to the dry runner instead of being sent, and
+// get an empty successful response. It is for trying out scripts that change things, like
+// kinesis.New(gaws.WithDryRun(gaws.LogDryRunner{})), before they touch production streams and tables. Requests are signed with the
+// client's credentials as usual, but nothing is sent to AWS, and Metrics and Audit are not told about them.
+func WithDryRun(dryRunner DryRunner) Option {
+	return func(c *Config) {
+		c.DryRun = dryRunner
+	}
+}
+
+// WithEndpointRouter makes a client send its requests to the best of the router's endpoints, instead of to its own endpoint. The router's
+// endpoints must be endpoints of the client's service.
+func WithEndpointRouter(router *EndpointRouter) Option {
+	return func(c *Config) {
+		c.Router = router
+	}
+}
+
+// WithQuotaLimits makes the consumers and producers of a client keep to the quotas of the service, with the RateLimiter presets of the
+// service package, instead of being throttled. Like Kinesis's StreamRecords, which reads a shard at no more than 5 GetRecords calls and
+// 2 MB a second, and DynamoDB's BatchWriter. Calls made one at a time are not limited.
+func WithQuotaLimits() Option {
+	return func(c *Config) {
+		c.QuotaLimits = true
+	}
+}
+
+// WithClock makes a client, and the buffers, batch writers and heartbeats made from it, tell the time and wait with the clock instead of
+// DefaultClock. Tests can give each client a clock of its own this way, like a gawstest.ManualClock to run flushes when they want.
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.Clock = clock
+	}
+}
+
+// NewConfig returns a Config with the options applied, in order.
+func NewConfig(options ...Option) Config {
+	c := Config{Region: Region}
+	for _, option := range options {
+		option(&c)
+	}
+	if c.HTTPClient == nil && (c.ConnectionPool != (ConnectionPool{}) || c.DisableHTTP2) {
+		c.HTTPClient = c.client()
+	}
+	return c
+}
+
+// ServiceEndpoint returns the endpoint of a service, like "kinesis". It is Endpoint if that is set, then the service's endpoint in
+// Endpoints, and then the endpoint in Region.
+func (c Config) ServiceEndpoint(service string) string {
+	if c.Endpoint != "" {
+		return c.Endpoint
+	}
+	if endpoint, ok := c.Endpoints[service]; ok {
+		return endpoint
+	}
+	return Endpoint(service, c.Region)
+}
+
+// CurrentClock returns the Clock of the config, or DefaultClock if it has none. Service packages wait with it in the loops they run in
+// the background, like the flushes of a buffer.
+func (c Config) CurrentClock() Clock {
+	if c.Clock == nil {
+		return DefaultClock
+	}
+	return c.Clock
+}
+
+// Apply sets the parts of a request that come from the config. Parts the request already has are kept.
+func (c Config) Apply(r *AWSRequest) {
+	if r.Credentials == nil {
+		r.Credentials = c.Credentials
+	}
+	if r.HTTPClient == nil {
+		r.HTTPClient = c.HTTPClient
+	}
+	if r.RetryPolicy.isZero() {
+		r.RetryPolicy = c.RetryPolicy
+	}
+	if r.MaxResponseSize == 0 {
+		r.MaxResponseSize = c.MaxResponseSize
+	}
+	if r.DryRun == nil {
+		r.DryRun = c.DryRun
+	}
+	if r.Clock == nil {
+		r.Clock = c.Clock
+	}
+	if c.Router != nil {
+		r.URL = c.Router.route(r.URL)
+	}
+}
@@ -0,0 +1,305 @@
+package gaws
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestNewConfig(t *testing.T) {
+	Convey("Given no options", t, func() {
+		c := NewConfig()
+
+		Convey("The region is Region", func() {
+			So(c.Region, ShouldEqual, Region)
+		})
+		Convey("The endpoint of a service is the one in Region", func() {
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, Endpoint("kinesis", Region))
+		})
+	})
+
+	Convey("Given options", t, func() {
+		client := &http.Client{}
+		credentials := StaticCredentials{AccessKeyID: "AKID"}
+		c := NewConfig(
+			WithRegion("eu-west-1"),
+			WithHTTPClient(client),
+			WithRetryPolicy(RetryPolicy{MaxTries: 2}),
+			WithCredentials(credentials),
+		)
+
+		Convey("They are set", func() {
+			So(c.Region, ShouldEqual, "eu-west-1")
+			So(c.HTTPClient, ShouldEqual, client)
+			So(c.RetryPolicy.MaxTries, ShouldEqual, 2)
+			So(c.Credentials, ShouldResemble, credentials)
+		})
+		Convey("The endpoint of a service is the one in the region", func() {
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, "https://kinesis.eu-west-1.amazonaws.com")
+		})
+		Convey("An endpoint is used instead", func() {
+			c = NewConfig(WithRegion("eu-west-1"), WithEndpoint("http://localhost:4566"))
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
+
+func TestConfigApply(t *testing.T) {
+	Convey("Given a config", t, func() {
+		client := &http.Client{}
+		credentials := StaticCredentials{AccessKeyID: "AKID"}
+		c := NewConfig(WithHTTPClient(client), WithRetryPolicy(RetryPolicy{MaxTries: 2}), WithCredentials(credentials))
+
+		Convey("It sets what a request does not have", func() {
+			r := canonicalRequest()
+			c.Apply(&r)
+			So(r.HTTPClient, ShouldEqual, client)
+			So(r.RetryPolicy.MaxTries, ShouldEqual, 2)
+			So(r.Credentials, ShouldResemble, credentials)
+		})
+		Convey("It keeps what a request has", func() {
+			r := canonicalRequest()
+			r.Credentials = StaticCredentials(awsauth.Credentials{AccessKeyID: "OTHER"})
+			r.RetryPolicy = RetryPolicy{MaxTries: 3}
+			c.Apply(&r)
+			So(r.RetryPolicy.MaxTries, ShouldEqual, 3)
+			So(r.Credentials.(StaticCredentials).AccessKeyID, ShouldEqual, "OTHER")
+		})
+	})
+
+	Convey("Given a config with a clock, and a server that always throttles", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+		clock := &testClock{now: time.Now()}
+		c := NewConfig(WithClock(clock), WithRetryPolicy(RetryPolicy{MaxTries: 2}))
+
+		Convey("Its requests back off on the clock instead of DefaultClock", func() {
+			r := canonicalRequest()
+			r.URL = ts.URL
+			c.Apply(&r)
+			r.Do()
+			So(clock.sleeps, ShouldResemble, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond})
+		})
+		Convey("It is the clock of its background loops", func() {
+			So(c.CurrentClock(), ShouldEqual, clock)
+			So(NewConfig().CurrentClock(), ShouldEqual, DefaultClock)
+		})
+	})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	Convey("Given a server that always throttles", t, func() {
+		tries := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tries++
+			testAWSThrottle(w, r)
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.RetryPolicy = RetryPolicy{MaxTries: 3, Backoff: time.Millisecond}
+
+		Convey("A request gives up when its policy says", func() {
+			_, err := r.Do()
+			So(err, ShouldEqual, exceededRetriesError)
+			So(tries, ShouldEqual, 3)
+		})
+		Convey("A request whose policy tries once is sent once", func() {
+			r.RetryPolicy = RetryPolicy{MaxTries: 1, Backoff: time.Millisecond}
+			_, err := r.Do()
+			So(err, ShouldEqual, exceededRetriesError)
+			So(tries, ShouldEqual, 1)
+		})
+		Convey("A streamed request whose policy tries once is sent once", func() {
+			r.RetryPolicy = RetryPolicy{MaxTries: 1, Backoff: time.Millisecond}
+			_, err := r.DoResponse()
+			So(err, ShouldEqual, exceededRetriesError)
+			So(tries, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a policy with a backoff", t, func() {
+		p := RetryPolicy{Backoff: 10 * time.Millisecond}
+
+		Convey("It doubles after each try", func() {
+			So(p.Delay(1, 0), ShouldEqual, 10*time.Millisecond)
+			So(p.Delay(3, 0), ShouldEqual, 40*time.Millisecond)
+		})
+	})
+
+	Convey("Given the zero policy", t, func() {
+		p := RetryPolicy{}
+
+		Convey("It uses MaxTries and waits 200 milliseconds first", func() {
+			So(p.Tries(), ShouldEqual, MaxTries-1)
+			So(p.Delay(1, 0), ShouldEqual, 200*time.Millisecond)
+		})
+	})
+}
+
+func TestHTTPClient(t *testing.T) {
+	Convey("Given a request with an http.Client", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("OK"))
+		}))
+		defer ts.Close()
+
+		transport := &testRoundTripper{}
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.HTTPClient = &http.Client{Transport: transport}
+
+		Convey("Do sends the request with it", func() {
+			_, err := r.Do()
+			So(err, ShouldBeNil)
+			So(transport.requests, ShouldEqual, 1)
+		})
+		Convey("A timeout does not change it", func() {
+			r.Timeout = time.Second
+			resp, err := r.DoResponse()
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(transport.requests, ShouldEqual, 1)
+			So(r.HTTPClient.Timeout, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestConnectionPool(t *testing.T) {
+	Convey("Given a connection pool", t, func() {
+		pool := ConnectionPool{
+			MaxIdleConns:        200,
+			MaxIdleConnsPerHost: 50,
+			IdleConnTimeout:     time.Minute,
+			DialTimeout:         time.Second,
+			TLSHandshakeTimeout: 2 * time.Second,
+		}
+		c := NewConfig(WithConnectionPool(pool))
+
+		Convey("The client's transport is tuned by it", func() {
+			transport := c.HTTPClient.Transport.(*http.Transport)
+			So(transport.MaxIdleConns, ShouldEqual, 200)
+			So(transport.MaxIdleConnsPerHost, ShouldEqual, 50)
+			So(transport.IdleConnTimeout, ShouldEqual, time.Minute)
+			So(transport.TLSHandshakeTimeout, ShouldEqual, 2*time.Second)
+			So(transport.DialContext, ShouldNotBeNil)
+		})
+		Convey("http.DefaultTransport is not changed", func() {
+			So(http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost, ShouldNotEqual, 50)
+		})
+		Convey("Requests are sent with the client", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL
+			c.Apply(&r)
+
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "OK")
+		})
+		Convey("A client that is given wins", func() {
+			client := &http.Client{}
+			c = NewConfig(WithConnectionPool(pool), WithHTTPClient(client))
+			So(c.HTTPClient, ShouldEqual, client)
+		})
+	})
+	Convey("Given no connection pool", t, func() {
+		c := NewConfig()
+
+		Convey("There is no client of its own", func() {
+			So(c.HTTPClient, ShouldBeNil)
+		})
+	})
+}
+
+// testTLSServer returns a TLS server that returns the protocol of each request, and supports HTTP/2 if http2 is true.
+func testTLSServer(http2 bool) *httptest.Server {
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	ts.EnableHTTP2 = http2
+	ts.StartTLS()
+	return ts
+}
+
+// testTrust makes the client of a config trust the certificate of a test server.
+func testTrust(c Config, ts *httptest.Server) {
+	roots := x509.NewCertPool()
+	roots.AddCert(ts.Certificate())
+	c.HTTPClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: roots}
+}
+
+func TestHTTP2(t *testing.T) {
+	Convey("Given an endpoint that supports HTTP/2", t, func() {
+		ts := testTLSServer(true)
+		defer ts.Close()
+
+		collector := &testCollector{}
+		Metrics = collector
+		defer func() { Metrics = nil }()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("A client uses it", func() {
+			c := NewConfig(WithConnectionPool(ConnectionPool{MaxIdleConnsPerHost: 10}))
+			testTrust(c, ts)
+			c.Apply(&r)
+
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "HTTP/2.0")
+			So(collector.metrics[0].Protocol, ShouldEqual, "HTTP/2.0")
+		})
+		Convey("A client with it turned off uses HTTP/1.1", func() {
+			c := NewConfig(WithHTTP2(false))
+			testTrust(c, ts)
+			c.Apply(&r)
+
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "HTTP/1.1")
+			So(collector.metrics[0].Protocol, ShouldEqual, "HTTP/1.1")
+		})
+	})
+	Convey("Given an endpoint that does not support HTTP/2", t, func() {
+		ts := testTLSServer(false)
+		defer ts.Close()
+
+		collector := &testCollector{}
+		Metrics = collector
+		defer func() { Metrics = nil }()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("A client falls back to HTTP/1.1", func() {
+			c := NewConfig(WithHTTP2(true), WithConnectionPool(ConnectionPool{MaxIdleConnsPerHost: 10}))
+			testTrust(c, ts)
+			c.Apply(&r)
+
+			resp, err := r.DoResponse()
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(resp.Proto, ShouldEqual, "HTTP/1.1")
+			So(collector.metrics[0].Protocol, ShouldEqual, "HTTP/1.1")
+		})
+	})
+	Convey("Given HTTP/2 turned off", t, func() {
+		c := NewConfig(WithHTTP2(false))
+
+		Convey("The client has its own transport", func() {
+			So(c.HTTPClient, ShouldNotBeNil)
+			So(c.HTTPClient.Transport.(*http.Transport).ForceAttemptHTTP2, ShouldBeFalse)
+		})
+	})
+}
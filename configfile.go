@@ -0,0 +1,201 @@
+package gaws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// ConfigFile is what a config file has. The file is JSON, like
+//
+//	{
+//		"region": "eu-west-1",
+//		"endpoints": {"kinesis": "http://localhost:4566"},
+//		"retryPolicy": {"maxTries": 3, "backoff": "200ms"},
+//		"credentials": {"source": "environment"}
+//	}
+//
+// or YAML, like
+//
+//	region: eu-west-1
+//	endpoints:
+//	  kinesis: http://localhost:4566
+//	retryPolicy:
+//	  maxTries: 3
+//	  backoff: 200ms
+//	credentials:
+//	  source: environment
+//
+// Anything it does not have is left as it is.
+type ConfigFile struct {
+	Region          string            `json:"region"`
+	Endpoints       map[string]string `json:"endpoints"` // Keyed by service, like "kinesis"
+	RetryPolicy     *RetryPolicyFile  `json:"retryPolicy"`
+	Credentials     *CredentialsFile  `json:"credentials"`
+	MaxResponseSize int64             `json:"maxResponseSize"`
+	HTTP2           *bool             `json:"http2"`
+}
+
+// RetryPolicyFile is the retry policy in a config file.
+type RetryPolicyFile struct {
+	MaxTries int    `json:"maxTries"`
+	Backoff  string `json:"backoff"` // Like "200ms" or "1s"
+}
+
+// CredentialsFile says where the credentials in a config file come from. Source is one of
+//   - default: awsauth looks for them in the environment and the instance's role, like when there is no config file, even if an
+//     option before the file gave the client credentials
+//   - environment: they are read from the environment, like EnvironmentCredentials
+//   - static: they are the keys in the file, which should only be used for tests and local emulators
+type CredentialsFile struct {
+	Source          string `json:"source"`
+	AccessKeyID     string `json:"accessKeyId"`
+	SecretAccessKey string `json:"secretAccessKey"`
+	SessionToken    string `json:"sessionToken"`
+}
+
+// ConfigFileError is returned when a config file cannot be used. It has every problem the file has, so they can be fixed at once.
+type ConfigFileError struct {
+	Path     string
+	Problems []string
+}
+
+// Error formats the ConfigFileError into an error message.
+func (e ConfigFileError) Error() string {
+	return fmt.Sprintf("gaws: config file %v: %v", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// regionPattern matches region names, like us-east-1 or us-gov-west-1.
+var regionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+// LoadConfigFile reads a config file, and returns an option that configures a client the way it says, like
+//
+//	options, err := gaws.LoadConfigFile("/etc/worker/gaws.json")
+//	k := kinesis.New(options)
+//
+// Options given after it win. The file must not have fields a ConfigFile does not have, so that misspellings are not ignored.
+// Files ending in .yaml or .yml are read as YAML. So that gaws does not depend on a YAML package, only the YAML a ConfigFile needs is
+// read: mappings nested by indentation, scalars, and comments.
+func LoadConfigFile(path string) (Option, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		mapping, err := parseYAML(data)
+		if err != nil {
+			return nil, ConfigFileError{Path: path, Problems: []string{err.Error()}}
+		}
+		data, err = json.Marshal(mapping)
+		if err != nil {
+			return nil, ConfigFileError{Path: path, Problems: []string{err.Error()}}
+		}
+	}
+
+	file := ConfigFile{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&file)
+	if err != nil {
+		return nil, ConfigFileError{Path: path, Problems: []string{err.Error()}}
+	}
+	return file.option(path)
+}
+
+// option checks the file, and returns the option that applies it.
+func (f ConfigFile) option(path string) (Option, error) {
+	problems := []string{}
+
+	if f.Region != "" && !regionPattern.MatchString(f.Region) {
+		problems = append(problems, fmt.Sprintf("region %q is not a region, like us-east-1", f.Region))
+	}
+	services := make([]string, 0, len(f.Endpoints))
+	for service := range f.Endpoints {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		endpoint := f.Endpoints[service]
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("endpoint %q of %v is not an http or https URL", endpoint, service))
+		}
+	}
+
+	policy := RetryPolicy{}
+	if f.RetryPolicy != nil {
+		if f.RetryPolicy.MaxTries < 0 {
+			problems = append(problems, fmt.Sprintf("retryPolicy.maxTries is %d, and cannot be negative", f.RetryPolicy.MaxTries))
+		}
+		policy.MaxTries = f.RetryPolicy.MaxTries
+		if f.RetryPolicy.Backoff != "" {
+			backoff, err := time.ParseDuration(f.RetryPolicy.Backoff)
+			if err != nil || backoff < 0 {
+				problems = append(problems, fmt.Sprintf("retryPolicy.backoff %q is not a duration, like 200ms", f.RetryPolicy.Backoff))
+			}
+			policy.Backoff = backoff
+		}
+	}
+
+	var credentials CredentialsProvider
+	defaultCredentials := false
+	if f.Credentials != nil {
+		c := f.Credentials
+		switch c.Source {
+		case "", "default":
+			defaultCredentials = true
+		case "environment":
+			credentials = EnvironmentCredentials{}
+		case "static":
+			if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+				problems = append(problems, "static credentials need an accessKeyId and a secretAccessKey")
+			}
+			credentials = StaticCredentials(awsauth.Credentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SecurityToken: c.SessionToken})
+		default:
+			problems = append(problems, fmt.Sprintf("credentials.source %q is not default, environment or static", c.Source))
+		}
+		if c.Source != "static" && (c.AccessKeyID != "" || c.SecretAccessKey != "" || c.SessionToken != "") {
+			problems = append(problems, "credentials have keys, but their source is not static")
+		}
+	}
+
+	if f.MaxResponseSize < 0 {
+		problems = append(problems, fmt.Sprintf("maxResponseSize is %d, and cannot be negative", f.MaxResponseSize))
+	}
+
+	if len(problems) > 0 {
+		return nil, ConfigFileError{Path: path, Problems: problems}
+	}
+
+	return func(c *Config) {
+		if f.Region != "" {
+			c.Region = f.Region
+		}
+		if len(f.Endpoints) > 0 {
+			c.Endpoints = f.Endpoints
+		}
+		if f.RetryPolicy != nil {
+			c.RetryPolicy = policy
+		}
+		if credentials != nil || defaultCredentials {
+			c.Credentials = credentials
+		}
+		if f.MaxResponseSize > 0 {
+			c.MaxResponseSize = f.MaxResponseSize
+		}
+		if f.HTTP2 != nil {
+			c.DisableHTTP2 = !*f.HTTP2
+		}
+	}, nil
+}
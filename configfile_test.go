@@ -0,0 +1,193 @@
+package gaws
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testConfigFile writes a config file and returns its path. remove deletes it.
+func testConfigFile(contents string) (path string, remove func()) {
+	dir, _ := ioutil.TempDir("", "gaws")
+	path = filepath.Join(dir, "gaws.json")
+	ioutil.WriteFile(path, []byte(contents), 0600)
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	Convey("Given a config file", t, func() {
+		path, remove := testConfigFile(`{
+			"region": "eu-west-1",
+			"endpoints": {"kinesis": "http://localhost:4566"},
+			"retryPolicy": {"maxTries": 3, "backoff": "50ms"},
+			"credentials": {"source": "static", "accessKeyId": "AKID", "secretAccessKey": "secret"},
+			"maxResponseSize": 1048576,
+			"http2": false
+		}`)
+		defer remove()
+
+		option, err := LoadConfigFile(path)
+		So(err, ShouldBeNil)
+		c := NewConfig(option)
+
+		Convey("A client is configured the way it says", func() {
+			So(c.Region, ShouldEqual, "eu-west-1")
+			So(c.RetryPolicy, ShouldResemble, RetryPolicy{MaxTries: 3, Backoff: 50 * time.Millisecond})
+			So(c.Credentials.(StaticCredentials).AccessKeyID, ShouldEqual, "AKID")
+			So(c.MaxResponseSize, ShouldEqual, 1048576)
+			So(c.DisableHTTP2, ShouldBeTrue)
+		})
+		Convey("Services with an endpoint use it, and the rest use the region", func() {
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, "http://localhost:4566")
+			So(c.ServiceEndpoint("dynamodb"), ShouldEqual, "https://dynamodb.eu-west-1.amazonaws.com")
+		})
+		Convey("Options given after it win", func() {
+			c = NewConfig(option, WithRegion("us-west-2"), WithEndpoint("http://localhost:8000"))
+			So(c.Region, ShouldEqual, "us-west-2")
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, "http://localhost:8000")
+		})
+	})
+
+	Convey("Given a config file with only some settings", t, func() {
+		path, remove := testConfigFile(`{"credentials": {"source": "environment"}}`)
+		defer remove()
+
+		option, err := LoadConfigFile(path)
+		So(err, ShouldBeNil)
+		c := NewConfig(WithRegion("ap-southeast-2"), option)
+
+		Convey("The rest are left as they are", func() {
+			So(c.Region, ShouldEqual, "ap-southeast-2")
+			So(c.RetryPolicy, ShouldResemble, RetryPolicy{})
+			So(c.HTTPClient, ShouldBeNil)
+			So(c.Credentials, ShouldResemble, EnvironmentCredentials{})
+		})
+	})
+
+	Convey("Given a config file with problems", t, func() {
+		path, remove := testConfigFile(`{
+			"region": "Ireland",
+			"endpoints": {"sqs": "localhost:9324", "kinesis": "ftp://localhost"},
+			"retryPolicy": {"maxTries": -1, "backoff": "soon"},
+			"credentials": {"source": "vault"}
+		}`)
+		defer remove()
+
+		_, err := LoadConfigFile(path)
+
+		Convey("It returns every problem", func() {
+			So(err, ShouldHaveSameTypeAs, ConfigFileError{})
+			So(err.(ConfigFileError).Problems, ShouldResemble, []string{
+				`region "Ireland" is not a region, like us-east-1`,
+				`endpoint "ftp://localhost" of kinesis is not an http or https URL`,
+				`endpoint "localhost:9324" of sqs is not an http or https URL`,
+				"retryPolicy.maxTries is -1, and cannot be negative",
+				`retryPolicy.backoff "soon" is not a duration, like 200ms`,
+				`credentials.source "vault" is not default, environment or static`,
+			})
+			So(err.Error(), ShouldStartWith, "gaws: config file "+path+`: region "Ireland"`)
+		})
+	})
+
+	Convey("Given a config file with a misspelled field", t, func() {
+		path, remove := testConfigFile(`{"regoin": "eu-west-1"}`)
+		defer remove()
+
+		_, err := LoadConfigFile(path)
+
+		Convey("It returns an error naming the field", func() {
+			So(err, ShouldHaveSameTypeAs, ConfigFileError{})
+			So(err.Error(), ShouldContainSubstring, `"regoin"`)
+		})
+	})
+
+	Convey("Given static credentials without keys, and keys without static credentials", t, func() {
+		path, remove := testConfigFile(`{"credentials": {"source": "static", "accessKeyId": "AKID"}}`)
+		defer remove()
+		_, err := LoadConfigFile(path)
+
+		other, removeOther := testConfigFile(`{"credentials": {"secretAccessKey": "secret"}}`)
+		defer removeOther()
+		_, otherErr := LoadConfigFile(other)
+
+		Convey("They are problems", func() {
+			So(err.Error(), ShouldContainSubstring, "static credentials need an accessKeyId and a secretAccessKey")
+			So(otherErr.Error(), ShouldContainSubstring, "credentials have keys, but their source is not static")
+		})
+	})
+
+	Convey("Given a config file that says to use the default credentials", t, func() {
+		path, remove := testConfigFile(`{"credentials": {"source": "default"}}`)
+		defer remove()
+
+		option, err := LoadConfigFile(path)
+		So(err, ShouldBeNil)
+		c := NewConfig(WithCredentials(EnvironmentCredentials{}), option)
+
+		Convey("Credentials given before it are dropped", func() {
+			So(c.Credentials, ShouldBeNil)
+		})
+	})
+
+	Convey("Given a YAML config file", t, func() {
+		path, remove := testConfigFile(`{}`)
+		defer remove()
+		path = strings.TrimSuffix(path, ".json") + ".yaml"
+		ioutil.WriteFile(path, []byte(`# The worker's clients
+region: eu-west-1
+endpoints:
+  kinesis: http://localhost:4566 # An emulator
+retryPolicy:
+  maxTries: 3
+  backoff: "50ms"
+credentials:
+  source: static
+  accessKeyId: AKID
+  secretAccessKey: 'secret'
+http2: false
+`), 0600)
+
+		option, err := LoadConfigFile(path)
+		So(err, ShouldBeNil)
+		c := NewConfig(option)
+
+		Convey("A client is configured the way it says", func() {
+			So(c.Region, ShouldEqual, "eu-west-1")
+			So(c.ServiceEndpoint("kinesis"), ShouldEqual, "http://localhost:4566")
+			So(c.RetryPolicy, ShouldResemble, RetryPolicy{MaxTries: 3, Backoff: 50 * time.Millisecond})
+			So(c.Credentials.(StaticCredentials).SecretAccessKey, ShouldEqual, "secret")
+			So(c.DisableHTTP2, ShouldBeTrue)
+		})
+	})
+
+	Convey("Given a YAML config file with a misspelled field, and one that is not YAML a config file can have", t, func() {
+		path, remove := testConfigFile(`{}`)
+		defer remove()
+		misspelled := strings.TrimSuffix(path, ".json") + ".yml"
+		ioutil.WriteFile(misspelled, []byte("regoin: eu-west-1\n"), 0600)
+		_, misspelledErr := LoadConfigFile(misspelled)
+
+		unsupported := strings.TrimSuffix(path, ".json") + ".yaml"
+		ioutil.WriteFile(unsupported, []byte("endpoints: [kinesis]\n"), 0600)
+		_, unsupportedErr := LoadConfigFile(unsupported)
+
+		Convey("They return errors that say why", func() {
+			So(misspelledErr, ShouldHaveSameTypeAs, ConfigFileError{})
+			So(misspelledErr.Error(), ShouldContainSubstring, `"regoin"`)
+			So(unsupportedErr, ShouldResemble, ConfigFileError{Path: unsupported, Problems: []string{"line 1: [kinesis] is not supported, only mappings and scalars are"}})
+		})
+	})
+
+	Convey("Given a file that does not exist", t, func() {
+		_, err := LoadConfigFile("/does/not/exist.json")
+
+		Convey("It returns the error", func() {
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
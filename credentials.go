@@ -0,0 +1,50 @@
+package gaws
+
+import (
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// CredentialsProvider provides the credentials requests are signed with. Providers that get temporary credentials, like an sts.RoleProvider,
+// should cache them until they expire. Credentials is called from every goroutine making requests, so it must be safe for concurrent use.
+type CredentialsProvider interface {
+	Credentials() (awsauth.Credentials, error)
+}
+
+// Credentials provides the credentials requests are signed with, unless a request has its own. If it is nil, or the credentials it provides
+// have no access key, awsauth looks for credentials in the environment and the instance's role.
+var Credentials CredentialsProvider
+
+// StaticCredentials are credentials that do not change, like keys read from a file.
+// Empty StaticCredentials make awsauth look for credentials itself, even if Credentials is set.
+type StaticCredentials awsauth.Credentials
+
+// Credentials returns the credentials.
+func (c StaticCredentials) Credentials() (awsauth.Credentials, error) {
+	return awsauth.Credentials(c), nil
+}
+
+// EnvironmentCredentials are read from the environment each time they are needed, from the same variables awsauth uses, like
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+type EnvironmentCredentials struct{}
+
+// Credentials returns the credentials in the environment.
+func (EnvironmentCredentials) Credentials() (awsauth.Credentials, error) {
+	return envCredentials(), nil
+}
+
+// credentials returns the credentials to sign the request with. It returns none if awsauth should look for them itself.
+func (r *AWSRequest) credentials() ([]awsauth.Credentials, error) {
+	provider := r.Credentials
+	if provider == nil {
+		provider = Credentials
+	}
+	if provider == nil {
+		return nil, nil
+	}
+
+	keys, err := provider.Credentials()
+	if err != nil || keys.AccessKeyID == "" {
+		return nil, err
+	}
+	return []awsauth.Credentials{keys}, nil
+}
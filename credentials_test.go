@@ -0,0 +1,101 @@
+package gaws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testFailingProvider struct{}
+
+func (p testFailingProvider) Credentials() (awsauth.Credentials, error) {
+	return awsauth.Credentials{}, errors.New("no credentials")
+}
+
+// testSignedWith returns a request that records the credentials it is signed with.
+func testSignedWith(signedWith *[]awsauth.Credentials) AWSRequest {
+	r := canonicalRequest()
+	r.Signer = func(req *http.Request, credentials ...awsauth.Credentials) *http.Request {
+		*signedWith = credentials
+		return req
+	}
+	return r
+}
+
+func TestCredentials(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+	defer ts.Close()
+
+	Convey("Given credentials for every request", t, func() {
+		Credentials = StaticCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+		defer func() { Credentials = nil }()
+
+		signedWith := []awsauth.Credentials{}
+		r := testSignedWith(&signedWith)
+		r.URL = ts.URL
+
+		Convey("Requests are signed with them", func() {
+			_, err := r.Do()
+
+			So(err, ShouldBeNil)
+			So(signedWith, ShouldResemble, []awsauth.Credentials{{AccessKeyID: "AKID", SecretAccessKey: "secret"}})
+		})
+		Convey("A request's own credentials are used instead", func() {
+			r.Credentials = StaticCredentials{AccessKeyID: "OTHER"}
+			r.DoResponse()
+
+			So(signedWith[0].AccessKeyID, ShouldEqual, "OTHER")
+		})
+		Convey("Empty credentials let awsauth find them", func() {
+			r.Credentials = StaticCredentials{}
+			r.Do()
+
+			So(len(signedWith), ShouldEqual, 0)
+		})
+	})
+	Convey("Given no credentials provider", t, func() {
+		signedWith := []awsauth.Credentials{}
+		r := testSignedWith(&signedWith)
+		r.URL = ts.URL
+		r.Do()
+
+		Convey("awsauth finds the credentials", func() {
+			So(len(signedWith), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a provider that fails", t, func() {
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.Credentials = testFailingProvider{}
+
+		Convey("Do returns its error", func() {
+			_, err := r.Do()
+			So(err, ShouldResemble, errors.New("no credentials"))
+		})
+		Convey("DoResponse returns its error", func() {
+			_, err := r.DoResponse()
+			So(err, ShouldResemble, errors.New("no credentials"))
+		})
+	})
+}
+
+func TestEnvironmentCredentials(t *testing.T) {
+	Convey("Given credentials in the environment", t, func() {
+		os.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+		os.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+		defer os.Unsetenv("AWS_ACCESS_KEY_ID")
+		defer os.Unsetenv("AWS_SECRET_ACCESS_KEY")
+
+		Convey("They are read", func() {
+			keys, err := EnvironmentCredentials{}.Credentials()
+			So(err, ShouldBeNil)
+			So(keys.AccessKeyID, ShouldEqual, "AKIDENV")
+			So(keys.SecretAccessKey, ShouldEqual, "secret")
+		})
+	})
+}
@@ -0,0 +1,37 @@
+package gaws
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// StrictDecoding makes responses with a field that gaws has nowhere to put fail to decode, instead of the field being dropped. Each
+// such field is also logged to Log. It is for tests, so that AWS adding to its responses, or a gaws struct with a misspelled tag, is
+// noticed rather than losing data quietly. Error responses are always decoded leniently.
+var StrictDecoding = false
+
+// DecodeJSON decodes a JSON response from AWS into v, like json.Unmarshal, but fails on unknown fields if StrictDecoding is set.
+func DecodeJSON(data []byte, v interface{}) error {
+	if !StrictDecoding {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err != nil && strings.HasPrefix(err.Error(), "json: unknown field ") {
+		logf("gaws: %T has no field for %v in a response", v, strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+// DecodeXML decodes an XML response from a REST service, like S3 or Route 53, into v, like xml.Unmarshal. An empty response, which is what
+// those requests get in a dry run, leaves v as it is, so the call succeeds with zero values.
+func DecodeXML(data []byte, v interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return xml.Unmarshal(data, v)
+}
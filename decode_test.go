@@ -0,0 +1,99 @@
+package gaws
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testDecoded struct {
+	StreamName string
+}
+
+func TestDecodeJSON(t *testing.T) {
+	Convey("Given a response with a field gaws does not know", t, func() {
+		data := []byte(`{"StreamName":"orders","StreamMode":"ON_DEMAND"}`)
+
+		Convey("It is dropped by default", func() {
+			var v testDecoded
+			So(DecodeJSON(data, &v), ShouldBeNil)
+			So(v.StreamName, ShouldEqual, "orders")
+		})
+
+		Convey("When decoding is strict", func() {
+			StrictDecoding = true
+			defer func() { StrictDecoding = false }()
+			b := &bytes.Buffer{}
+			Log = log.New(b, "", 0)
+			defer func() { Log = nil }()
+
+			var v testDecoded
+			err := DecodeJSON(data, &v)
+
+			Convey("It fails, and the field is logged", func() {
+				So(err, ShouldNotBeNil)
+				So(b.String(), ShouldEqual, "gaws: *gaws.testDecoded has no field for \"StreamMode\" in a response\n")
+			})
+		})
+
+		Convey("When decoding is strict and the fields are known", func() {
+			StrictDecoding = true
+			defer func() { StrictDecoding = false }()
+
+			var v testDecoded
+			So(DecodeJSON([]byte(`{"StreamName":"orders"}`), &v), ShouldBeNil)
+			So(v.StreamName, ShouldEqual, "orders")
+		})
+	})
+
+	Convey("Given a call whose response has a field gaws does not know, and strict decoding", t, func() {
+		StrictDecoding = true
+		defer func() { StrictDecoding = false }()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"StreamName":"orders","StreamMode":"ON_DEMAND"}`))
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		_, err := Call[testDecoded](context.Background(), r, "Kinesis_20131202.DescribeStreamSummary", nil)
+
+		Convey("The call fails", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDecodeXML(t *testing.T) {
+	Convey("Given an XML response", t, func() {
+		v := testDecoded{}
+		err := DecodeXML([]byte("<DescribeStreamResult><StreamName>orders</StreamName></DescribeStreamResult>"), &v)
+
+		Convey("It is decoded", func() {
+			So(err, ShouldBeNil)
+			So(v.StreamName, ShouldEqual, "orders")
+		})
+	})
+	Convey("Given an empty response, like in a dry run", t, func() {
+		v := testDecoded{}
+		err := DecodeXML(nil, &v)
+
+		Convey("It decodes to the zero value", func() {
+			So(err, ShouldBeNil)
+			So(v, ShouldResemble, testDecoded{})
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		err := DecodeXML([]byte("not xml"), &testDecoded{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
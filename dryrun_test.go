@@ -0,0 +1,138 @@
+package gaws
+
+import (
+	"bytes"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testDryRunner struct {
+	lock     sync.Mutex
+	requests []*http.Request
+}
+
+func (d *testDryRunner) WouldSend(req *http.Request) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.requests = append(d.requests, req)
+}
+
+func TestDryRun(t *testing.T) {
+	Convey("Given a request in dry-run mode", t, func() {
+		sent := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			sent++
+			testHTTP200(w, r)
+		}))
+		defer ts.Close()
+
+		d := &testDryRunner{}
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.Method = "POST"
+		r.DryRun = d
+		r.Credentials = StaticCredentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+		r.Signer = Sign4("kinesis", "us-east-1")
+
+		Convey("When it is for a JSON service", func() {
+			r.Headers["X-Amz-Target"] = "Kinesis_20131202.DeleteStream"
+			r.Body = []byte(`{"StreamName":"orders"}`)
+			body, err := r.Do()
+
+			Convey("It is not sent", func() {
+				So(err, ShouldBeNil)
+				So(sent, ShouldEqual, 0)
+			})
+			Convey("The dry runner gets it signed", func() {
+				So(len(d.requests), ShouldEqual, 1)
+				So(d.requests[0].Header.Get("X-Amz-Target"), ShouldEqual, "Kinesis_20131202.DeleteStream")
+				So(d.requests[0].Header.Get("Authorization"), ShouldContainSubstring, "Credential=AKID/")
+
+				b, _ := d.requests[0].GetBody()
+				requestBody, _ := ioutil.ReadAll(b)
+				So(string(requestBody), ShouldEqual, `{"StreamName":"orders"}`)
+			})
+			Convey("It gets an empty JSON response", func() {
+				So(string(body), ShouldEqual, "{}")
+			})
+		})
+
+		Convey("When its body buffer is used again after it", func() {
+			r.Headers["X-Amz-Target"] = "Kinesis_20131202.PutRecord"
+			buffer := []byte(`{"StreamName":"orders"}`)
+			r.Body = buffer
+			r.Do()
+			copy(buffer, `{"StreamName":"events"}`)
+
+			Convey("The dry runner keeps the body it was given", func() {
+				b, _ := d.requests[0].GetBody()
+				requestBody, _ := ioutil.ReadAll(b)
+				So(string(requestBody), ShouldEqual, `{"StreamName":"orders"}`)
+			})
+		})
+
+		Convey("When it is for a query service and streamed", func() {
+			r.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			r.Body = []byte("Action=DeleteQueue&Version=2012-11-05")
+			resp, err := r.DoResponse()
+
+			Convey("It gets an empty XML response", func() {
+				So(err, ShouldBeNil)
+				So(sent, ShouldEqual, 0)
+				So(resp.StatusCode, ShouldEqual, 200)
+				body, _ := ioutil.ReadAll(resp.Body)
+				So(string(body), ShouldEqual, "<DryRunResponse/>")
+			})
+		})
+
+		Convey("When there are metrics and audit hooks", func() {
+			c := &testCollector{}
+			Metrics = c
+			a := &testAuditor{}
+			Audit = a
+			defer func() { Metrics, Audit = nil, nil }()
+
+			r.Do()
+
+			Convey("They are not told about it", func() {
+				So(len(c.metrics), ShouldEqual, 0)
+				So(len(a.records), ShouldEqual, 0)
+			})
+		})
+	})
+
+	Convey("Given a config with a dry runner", t, func() {
+		d := &testDryRunner{}
+		r := canonicalRequest()
+		NewConfig(WithDryRun(d)).Apply(&r)
+
+		Convey("Its requests are in dry-run mode", func() {
+			So(r.DryRun, ShouldEqual, d)
+		})
+	})
+}
+
+func TestLogDryRunner(t *testing.T) {
+	Convey("Given a dry runner that logs", t, func() {
+		b := &bytes.Buffer{}
+		d := LogDryRunner{Logger: log.New(b, "", 0)}
+
+		r := canonicalRequest()
+		r.URL = "https://kinesis.us-east-1.amazonaws.com/"
+		r.Method = "POST"
+		r.Headers["X-Amz-Target"] = "Kinesis_20131202.DeleteStream"
+		r.Body = []byte(`{"StreamName":"orders"}`)
+		r.DryRun = d
+		r.Do()
+
+		Convey("It logs the operation, URL and body", func() {
+			So(b.String(), ShouldEqual, "gaws: dry run: Kinesis_20131202.DeleteStream https://kinesis.us-east-1.amazonaws.com/ {\"StreamName\":\"orders\"}\n")
+		})
+	})
+}
@@ -0,0 +1,194 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// BackupDetails are the details of an on-demand backup.
+type BackupDetails struct {
+	BackupArn              string
+	BackupCreationDateTime float64
+	BackupExpiryDateTime   float64
+	BackupName             string
+	BackupSizeBytes        int64
+	BackupStatus           string // The status of the backup. May be CREATING, DELETED, or AVAILABLE.
+	BackupType             string // USER, SYSTEM, or AWS_BACKUP
+}
+
+// BackupSummary is a backup returned by ListBackups.
+type BackupSummary struct {
+	BackupDetails
+	TableArn  string
+	TableId   string
+	TableName string
+}
+
+// BackupDescription is the description of a backup and the table it was taken from.
+type BackupDescription struct {
+	BackupDetails      BackupDetails
+	SourceTableDetails struct {
+		ItemCount      int64
+		KeySchema      []KeySchemaElement
+		TableArn       string
+		TableId        string
+		TableName      string
+		TableSizeBytes int64
+	}
+}
+
+type createBackupRequest struct {
+	BackupName string
+	TableName  string
+}
+
+type createBackupResult struct {
+	BackupDetails BackupDetails
+}
+
+// CreateBackup creates an on-demand backup of the table. The backup is CREATING until WaitForBackup says it is done.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_CreateBackup.html for more details.
+func (t *Table) CreateBackup(backupName string) (BackupDetails, error) {
+	result := createBackupResult{}
+
+	body := createBackupRequest{TableName: t.Name, BackupName: backupName}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := t.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.CreateBackup"
+
+	resp, err := req.Do()
+	if err != nil {
+		return BackupDetails{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return BackupDetails{}, err
+	}
+	return result.BackupDetails, nil
+}
+
+type describeBackupRequest struct {
+	BackupArn string
+}
+
+type describeBackupResult struct {
+	BackupDescription BackupDescription
+}
+
+// DescribeBackup describes a backup.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_DescribeBackup.html for more details.
+func (s *DynamoDBService) DescribeBackup(backupArn string) (BackupDescription, error) {
+	result := describeBackupResult{}
+
+	body := describeBackupRequest{BackupArn: backupArn}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.DescribeBackup"
+
+	resp, err := req.Do()
+	if err != nil {
+		return BackupDescription{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return BackupDescription{}, err
+	}
+	return result.BackupDescription, nil
+}
+
+type listBackupsRequest struct {
+	ExclusiveStartBackupArn string `json:",omitempty"`
+	TableName               string `json:",omitempty"`
+}
+
+type listBackupsResult struct {
+	BackupSummaries        []BackupSummary
+	LastEvaluatedBackupArn string
+}
+
+// ListBackups lists all of the backups of a table. If tableName is empty, it lists the backups of every table in the region.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_ListBackups.html for more details.
+func (s *DynamoDBService) ListBackups(tableName string) ([]BackupSummary, error) {
+	backups := []BackupSummary{}
+	body := listBackupsRequest{TableName: tableName}
+
+	for {
+		result := listBackupsResult{}
+		bodyAsJson, err := json.Marshal(body)
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "DynamoDB_20120810.ListBackups"
+
+		resp, err := req.Do()
+		if err != nil {
+			return []BackupSummary{}, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return []BackupSummary{}, err
+		}
+
+		backups = append(backups, result.BackupSummaries...)
+
+		if result.LastEvaluatedBackupArn == "" {
+			return backups, nil
+		}
+		body.ExclusiveStartBackupArn = result.LastEvaluatedBackupArn
+	}
+}
+
+type restoreTableFromBackupRequest struct {
+	BackupArn       string
+	TargetTableName string
+}
+
+// RestoreTableFromBackup creates a new table from a backup. It returns the new Table, which can be passed to WaitUntilRestored.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_RestoreTableFromBackup.html for more details.
+func (s *DynamoDBService) RestoreTableFromBackup(backupArn string, targetTableName string) (Table, error) {
+	table := Table{Name: targetTableName, Service: s}
+
+	body := restoreTableFromBackupRequest{BackupArn: backupArn, TargetTableName: targetTableName}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.RestoreTableFromBackup"
+
+	_, err = req.Do()
+
+	return table, err
+}
+
+// WaitForBackup waits for a backup's status to become AVAILABLE. It returns the details of the backup and an error if it fails or takes too long.
+func (s *DynamoDBService) WaitForBackup(backupArn string) (BackupDetails, error) {
+	var description BackupDescription
+
+	err := s.wait(func() (bool, error) {
+		var err error
+		description, err = s.DescribeBackup(backupArn)
+		return description.BackupDetails.BackupStatus == "AVAILABLE", err
+	})
+	return description.BackupDetails, err
+}
+
+// WaitUntilRestored waits for a table that is being restored from a backup to finish restoring and become ACTIVE.
+func (t *Table) WaitUntilRestored() (TableDescription, error) {
+	var description TableDescription
+
+	err := t.Service.wait(func() (bool, error) {
+		var err error
+		description, err = t.Describe()
+		restoring := description.RestoreSummary != nil && description.RestoreSummary.RestoreInProgress
+		return description.TableStatus == "ACTIVE" && !restoring, err
+	})
+	return description, err
+}
@@ -0,0 +1,238 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testBackupArn = "arn:aws:dynamodb:us-east-1:123456789012:table/foo/backup/01489602797149-73d8d5bc"
+
+var testBackupDetails = []byte(`{
+  "BackupDetails": {
+    "BackupArn": "arn:aws:dynamodb:us-east-1:123456789012:table/foo/backup/01489602797149-73d8d5bc",
+    "BackupCreationDateTime": 1.489602797149E9,
+    "BackupName": "nightly",
+    "BackupStatus": "CREATING",
+    "BackupType": "USER"
+  }
+}`)
+
+func testCreateBackupSuccess(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(200)
+	w.Write(testBackupDetails)
+}
+
+func TestCreateBackup(t *testing.T) {
+	Convey("Given a table and a server that responds to CreateBackup requests", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testCreateBackupSuccess))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		details, err := testTable.CreateBackup("nightly")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the details of the backup", func() {
+			So(details.BackupArn, ShouldEqual, testBackupArn)
+			So(details.BackupStatus, ShouldEqual, "CREATING")
+		})
+	})
+	Convey("Given a table and a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		_, err := testTable.CreateBackup("nightly")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func testDescribeBackup(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"BackupDescription": {"BackupDetails": {"BackupArn": "` + testBackupArn + `", "BackupStatus": "` + status + `"}, "SourceTableDetails": {"TableName": "foo"}}}`))
+	}
+}
+
+func TestDescribeBackup(t *testing.T) {
+	Convey("Given a server that describes backups", t, func() {
+		ts := httptest.NewServer(testDescribeBackup("AVAILABLE"))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		description, err := ds.DescribeBackup(testBackupArn)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the backup and its source table", func() {
+			So(description.BackupDetails.BackupStatus, ShouldEqual, "AVAILABLE")
+			So(description.SourceTableDetails.TableName, ShouldEqual, "foo")
+		})
+	})
+	Convey("Given a server that returns bad data", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testBadJson))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.DescribeBackup(testBackupArn)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListBackups(t *testing.T) {
+	Convey("Given a server that returns backups over two pages", t, func() {
+		requests := []listBackupsRequest{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := listBackupsRequest{}
+			json.NewDecoder(r.Body).Decode(&request)
+			requests = append(requests, request)
+
+			if request.ExclusiveStartBackupArn == "" {
+				w.Write([]byte(`{"BackupSummaries": [{"BackupName": "one", "TableName": "foo"}], "LastEvaluatedBackupArn": "arn:one"}`))
+				return
+			}
+			w.Write([]byte(`{"BackupSummaries": [{"BackupName": "two", "TableName": "foo"}]}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		backups, err := ds.ListBackups("foo")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the backups from every page", func() {
+			So(len(backups), ShouldEqual, 2)
+			So(backups[1].BackupName, ShouldEqual, "two")
+		})
+		Convey("It asks for the second page where the first one ended", func() {
+			So(requests[1].ExclusiveStartBackupArn, ShouldEqual, "arn:one")
+			So(requests[1].TableName, ShouldEqual, "foo")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		backups, err := ds.ListBackups("")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+		Convey("And the result is empty", func() {
+			So(backups, ShouldResemble, []BackupSummary{})
+		})
+	})
+}
+
+func TestRestoreTableFromBackup(t *testing.T) {
+	Convey("Given a server that always returns 200", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		table, err := ds.RestoreTableFromBackup(testBackupArn, "bar")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the new table", func() {
+			So(table.Name, ShouldEqual, "bar")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.RestoreTableFromBackup(testBackupArn, "bar")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWaitForBackup(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a backup that becomes available on the second check", t, func() {
+		checks := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			checks++
+			if checks < 2 {
+				testDescribeBackup("CREATING")(w, r)
+				return
+			}
+			testDescribeBackup("AVAILABLE")(w, r)
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		details, err := ds.WaitForBackup(testBackupArn)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the available backup", func() {
+			So(details.BackupStatus, ShouldEqual, "AVAILABLE")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.WaitForBackup(testBackupArn)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWaitUntilRestored(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a table that is active but still restoring on the first check", t, func() {
+		checks := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			checks++
+			if checks < 2 {
+				w.Write([]byte(`{"Table": {"TableName": "bar", "TableStatus": "ACTIVE", "RestoreSummary": {"RestoreInProgress": true}}}`))
+				return
+			}
+			w.Write([]byte(`{"Table": {"TableName": "bar", "TableStatus": "ACTIVE", "RestoreSummary": {"RestoreInProgress": false}}}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "bar", Service: &ds}
+
+		description, err := testTable.WaitUntilRestored()
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It waits for the restore to finish", func() {
+			So(checks, ShouldEqual, 2)
+			So(description.RestoreSummary.RestoreInProgress, ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,217 @@
+package dynamodb
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// maxBatchWriteItems is the most writes DynamoDB accepts in one BatchWriteItem request.
+const maxBatchWriteItems = 25
+
+// FlushInterval is how often a BatchWriter writes what it has buffered, even if it is less than a full batch.
+var FlushInterval = time.Second
+
+var unprocessedItemsError = dynamoDBError{Type: "GawsUnprocessedItems", Message: "Some items were still unprocessed after the maximum number of retries."}
+
+// PutRequest puts an item in a BatchWriteItem request.
+type PutRequest struct {
+	Item Item
+}
+
+// DeleteRequest deletes the item with the key in a BatchWriteItem request.
+type DeleteRequest struct {
+	Key Item
+}
+
+// WriteRequest is a put or a delete in a BatchWriteItem request. Only one of the fields should be set.
+type WriteRequest struct {
+	DeleteRequest *DeleteRequest `json:",omitempty"`
+	PutRequest    *PutRequest    `json:",omitempty"`
+}
+
+type batchWriteItemRequest struct {
+	RequestItems map[string][]WriteRequest
+}
+
+type batchWriteItemResult struct {
+	UnprocessedItems map[string][]WriteRequest
+}
+
+// BatchWriteItem writes up to 25 puts and deletes to the table. Items DynamoDB did not process are retried with the backoff and tries of the service's retry policy.
+// It is calling the BatchWriteItem API call. See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_BatchWriteItem.html for more details.
+func (t *Table) BatchWriteItem(requests []WriteRequest) error {
+	var waited time.Duration
+	tries := t.Service.config.RetryPolicy.Tries()
+	for try := 1; try <= tries; try++ {
+		result := batchWriteItemResult{}
+
+		body := batchWriteItemRequest{RequestItems: map[string][]WriteRequest{t.Name: requests}}
+		bodyAsJson, err := json.Marshal(body)
+
+		req := t.Service.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "DynamoDB_20120810.BatchWriteItem"
+
+		resp, err := req.Do()
+		if err != nil {
+			return err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return err
+		}
+
+		requests = result.UnprocessedItems[t.Name]
+		if len(requests) == 0 {
+			return nil
+		}
+		if try == tries {
+			break
+		}
+
+		// Back off before retrying the unprocessed items, as the service's retry policy says, on its clock
+		waited = t.Service.config.RetryPolicy.Delay(try, waited)
+		<-t.Service.config.CurrentClock().After(waited)
+	}
+	return unprocessedItemsError
+}
+
+// BatchWriter buffers puts and deletes for a table and writes them in batches of 25 in the background.
+// Errors from background writes are returned by the next call to Flush or Close. The items in a batch that failed are not retried again.
+type BatchWriter struct {
+	table   TableAPI
+	clock   gaws.Clock // Times the flushes
+	lock    sync.Mutex // Protects pending and err
+	pending []WriteRequest
+	err     error
+	sending sync.Mutex // Makes sure only one batch is written at a time
+	full    chan bool
+	done    chan bool
+	stopped chan bool
+
+	limiter *gaws.RateLimiter // Paces the batches to a partition's write capacity, with gaws.WithQuotaLimits
+}
+
+// NewBatchWriter starts a BatchWriter for the table. Call Close when you are done with it. If the table's service has
+// gaws.WithQuotaLimits, batches are written no faster than one partition takes them, with NewPartitionWriteLimiter.
+func (t *Table) NewBatchWriter() *BatchWriter {
+	return NewBatchWriter(t)
+}
+
+// NewBatchWriter starts a BatchWriter for any TableAPI, like a fake table in tests. Otherwise it is the same as Table.NewBatchWriter.
+// Only a *Table has a client, so a fake table is flushed on gaws.DefaultClock and its batches are not paced.
+func NewBatchWriter(table TableAPI) *BatchWriter {
+	w := &BatchWriter{
+		table:   table,
+		clock:   gaws.DefaultClock,
+		full:    make(chan bool, 1),
+		done:    make(chan bool),
+		stopped: make(chan bool),
+	}
+	if t, ok := table.(*Table); ok && t.Service != nil {
+		w.clock = t.Service.config.CurrentClock()
+		if t.Service.config.QuotaLimits {
+			w.limiter = t.Service.NewPartitionWriteLimiter()
+		}
+	}
+	go w.run()
+	return w
+}
+
+// Put buffers an item to be put in the table.
+func (w *BatchWriter) Put(item Item) {
+	w.add(WriteRequest{PutRequest: &PutRequest{Item: item}})
+}
+
+// Delete buffers the deletion of the item with the given key.
+func (w *BatchWriter) Delete(key Item) {
+	w.add(WriteRequest{DeleteRequest: &DeleteRequest{Key: key}})
+}
+
+func (w *BatchWriter) add(r WriteRequest) {
+	w.lock.Lock()
+	w.pending = append(w.pending, r)
+	full := len(w.pending) >= maxBatchWriteItems
+	w.lock.Unlock()
+
+	if full {
+		select {
+		case w.full <- true:
+		default:
+		}
+	}
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.stopped)
+
+	tick := w.clock.After(FlushInterval)
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.full:
+			w.write(false)
+		case <-tick:
+			w.write(true)
+			tick = w.clock.After(FlushInterval)
+		}
+	}
+}
+
+// write sends the buffered requests in batches. Unless all is true, a partial batch is left in the buffer.
+func (w *BatchWriter) write(all bool) {
+	w.sending.Lock()
+	defer w.sending.Unlock()
+
+	for {
+		w.lock.Lock()
+		n := len(w.pending)
+		if n > maxBatchWriteItems {
+			n = maxBatchWriteItems
+		}
+		if n == 0 || (!all && n < maxBatchWriteItems) {
+			w.lock.Unlock()
+			return
+		}
+		batch := make([]WriteRequest, n)
+		copy(batch, w.pending)
+		w.pending = w.pending[n:]
+		w.lock.Unlock()
+
+		if w.limiter != nil {
+			w.limiter.Wait(context.Background(), writeUnits(batch))
+		}
+		err := w.table.BatchWriteItem(batch)
+		if err != nil {
+			w.lock.Lock()
+			if w.err == nil {
+				w.err = err
+			}
+			w.lock.Unlock()
+		}
+	}
+}
+
+// Flush writes everything that is buffered. It returns the first error since the last Flush, if there was one.
+func (w *BatchWriter) Flush() error {
+	w.write(true)
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	err := w.err
+	w.err = nil
+	return err
+}
+
+// Close stops the background writes and flushes everything that is buffered. The BatchWriter cannot be used after it is closed.
+func (w *BatchWriter) Close() error {
+	close(w.done)
+	<-w.stopped
+	return w.Flush()
+}
@@ -0,0 +1,252 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testBatchServer records the BatchWriteItem requests it gets. unprocessed is returned as unprocessed items on the first request.
+func testBatchServer(unprocessed []WriteRequest) (*httptest.Server, func() []batchWriteItemRequest) {
+	var lock sync.Mutex
+	requests := []batchWriteItemRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := batchWriteItemRequest{}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		lock.Lock()
+		requests = append(requests, request)
+		first := len(requests) == 1
+		lock.Unlock()
+
+		result := batchWriteItemResult{}
+		if first && unprocessed != nil {
+			result.UnprocessedItems = map[string][]WriteRequest{"foo": unprocessed}
+		}
+		b, _ := json.Marshal(result)
+		w.Write(b)
+	}))
+
+	return ts, func() []batchWriteItemRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]batchWriteItemRequest{}, requests...)
+	}
+}
+
+func TestBatchWriter(t *testing.T) {
+	Convey("Given a BatchWriter on a table", t, func() {
+		ts, requests := testBatchServer(nil)
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+		w := testTable.NewBatchWriter()
+
+		Convey("When I put 60 items and close it", func() {
+			for i := 0; i < 60; i++ {
+				w.Put(Item{"id": N(strconv.Itoa(i))})
+			}
+			err := w.Close()
+
+			Convey("There is no error", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("The items are written in batches of no more than 25", func() {
+				sizes := []int{}
+				for _, r := range requests() {
+					sizes = append(sizes, len(r.RequestItems["foo"]))
+				}
+				So(sizes, ShouldResemble, []int{25, 25, 10})
+			})
+		})
+
+		Convey("When I put a full batch", func() {
+			for i := 0; i < 25; i++ {
+				w.Put(Item{"id": N(strconv.Itoa(i))})
+			}
+
+			Convey("It is written without a Flush", func() {
+				for i := 0; i < 100 && len(requests()) == 0; i++ {
+					time.Sleep(10 * time.Millisecond)
+				}
+				So(len(requests()), ShouldEqual, 1)
+				w.Close()
+			})
+		})
+
+		Convey("When I delete an item and flush", func() {
+			w.Delete(Item{"id": N("1")})
+			err := w.Flush()
+			w.Close()
+
+			Convey("A delete request is sent", func() {
+				So(err, ShouldBeNil)
+				So(requests()[0].RequestItems["foo"][0].DeleteRequest.Key, ShouldResemble, Item{"id": N("1")})
+			})
+		})
+	})
+
+	Convey("Given a BatchWriter of a client with a manual clock", t, func() {
+		ts, requests := testBatchServer(nil)
+		defer ts.Close()
+		clock := gawstest.NewManualClock(time.Now())
+		testTable := Table{Name: "foo", Service: New(gaws.WithEndpoint(ts.URL), gaws.WithClock(clock))}
+		w := testTable.NewBatchWriter()
+		defer w.Close()
+
+		w.Put(Item{"id": N("1")})
+		clock.BlockUntil(1)
+
+		Convey("A partial batch is not written before the flush interval", func() {
+			clock.Advance(FlushInterval - time.Millisecond)
+			time.Sleep(10 * time.Millisecond)
+			So(requests(), ShouldBeEmpty)
+		})
+		Convey("A partial batch is written each flush interval", func() {
+			clock.Advance(FlushInterval)
+			clock.BlockUntil(1)
+			So(len(requests()), ShouldEqual, 1)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{FlushInterval, FlushInterval})
+		})
+	})
+
+	Convey("Given a table that does not process some items the first time", t, func() {
+		unprocessed := []WriteRequest{{PutRequest: &PutRequest{Item: Item{"id": N("2")}}}}
+		ts, requests := testBatchServer(unprocessed)
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+		w := testTable.NewBatchWriter()
+
+		clock := gawstest.NewClock(time.Now())
+		defaultClock := gaws.DefaultClock
+		gaws.DefaultClock = clock
+		defer func() { gaws.DefaultClock = defaultClock }()
+
+		w.Put(Item{"id": N("1")})
+		w.Put(Item{"id": N("2")})
+		err := w.Close()
+
+		Convey("There is no error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("The unprocessed items are sent again", func() {
+			So(len(requests()), ShouldEqual, 2)
+			So(requests()[1].RequestItems["foo"], ShouldResemble, unprocessed)
+		})
+		Convey("They are sent again after a backoff", func() {
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{200 * time.Millisecond})
+		})
+	})
+
+	Convey("Given a table of a client with a backoff that does not process some items the first time", t, func() {
+		unprocessed := []WriteRequest{{PutRequest: &PutRequest{Item: Item{"id": N("2")}}}}
+		ts, _ := testBatchServer(unprocessed)
+		defer ts.Close()
+		testTable := Table{Name: "foo", Service: New(gaws.WithEndpoint(ts.URL), gaws.WithBackoff(gaws.ConstantBackoff{Delay: time.Second}))}
+		w := testTable.NewBatchWriter()
+
+		clock := gawstest.NewClock(time.Now())
+		defaultClock := gaws.DefaultClock
+		gaws.DefaultClock = clock
+		defer func() { gaws.DefaultClock = defaultClock }()
+
+		w.Put(Item{"id": N("1")})
+		w.Put(Item{"id": N("2")})
+		err := w.Close()
+
+		Convey("They are sent again after the client's backoff", func() {
+			So(err, ShouldBeNil)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{time.Second})
+		})
+	})
+
+	Convey("Given a table of a client with a clock that does not process some items the first time", t, func() {
+		unprocessed := []WriteRequest{{PutRequest: &PutRequest{Item: Item{"id": N("2")}}}}
+		ts, _ := testBatchServer(unprocessed)
+		defer ts.Close()
+		clock := gawstest.NewClock(time.Now())
+		testTable := Table{Name: "foo", Service: New(gaws.WithEndpoint(ts.URL), gaws.WithClock(clock))}
+
+		err := testTable.BatchWriteItem([]WriteRequest{{PutRequest: &PutRequest{Item: Item{"id": N("1")}}}, unprocessed[0]})
+
+		Convey("They are sent again after a backoff on the client's clock", func() {
+			So(err, ShouldBeNil)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{200 * time.Millisecond})
+		})
+	})
+
+	Convey("Given a table of a client that tries once, that does not process some items the first time", t, func() {
+		unprocessed := []WriteRequest{{PutRequest: &PutRequest{Item: Item{"id": N("2")}}}}
+		ts, requests := testBatchServer(unprocessed)
+		defer ts.Close()
+		testTable := Table{Name: "foo", Service: New(gaws.WithEndpoint(ts.URL), gaws.WithRetryPolicy(gaws.RetryPolicy{MaxTries: 1}))}
+		w := testTable.NewBatchWriter()
+
+		w.Put(Item{"id": N("1")})
+		w.Put(Item{"id": N("2")})
+		err := w.Close()
+
+		Convey("The unprocessed items are not sent again", func() {
+			So(err, ShouldResemble, unprocessedItemsError)
+			So(len(requests()), ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a table that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+		w := testTable.NewBatchWriter()
+
+		w.Put(Item{"id": N("1")})
+
+		Convey("Close returns the error", func() {
+			So(w.Close(), ShouldNotBeNil)
+		})
+	})
+}
+
+// fakeTable is a TableAPI without a server. It records the batches it is given.
+// Calls it does not implement panic, through the nil TableAPI it embeds.
+type fakeTable struct {
+	TableAPI
+	lock    sync.Mutex
+	batches [][]WriteRequest
+}
+
+func (t *fakeTable) BatchWriteItem(requests []WriteRequest) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.batches = append(t.batches, requests)
+	return nil
+}
+
+func TestBatchWriterWithFakeTable(t *testing.T) {
+	Convey("Given a BatchWriter on a fake table", t, func() {
+		table := &fakeTable{}
+		w := NewBatchWriter(table)
+
+		for i := 0; i < 30; i++ {
+			w.Put(Item{"id": N(strconv.Itoa(i))})
+		}
+		err := w.Close()
+
+		Convey("It writes the items to the fake table in batches", func() {
+			So(err, ShouldBeNil)
+			So(len(table.batches), ShouldEqual, 2)
+			So(len(table.batches[0]), ShouldEqual, 25)
+			So(len(table.batches[1]), ShouldEqual, 5)
+		})
+	})
+}
@@ -0,0 +1,74 @@
+package dynamodb
+
+import (
+	"errors"
+)
+
+// CheckpointTableDefinition returns the definition of a table for storing stream consumer leases and checkpoints.
+// It is keyed by leaseKey, the same as the Kinesis Client Library, and uses on-demand capacity so it never needs to be sized.
+func CheckpointTableDefinition(name string) TableDefinition {
+	return TableDefinition{
+		TableName:            name,
+		AttributeDefinitions: []AttributeDefinition{{AttributeName: "leaseKey", AttributeType: "S"}},
+		KeySchema:            []KeySchemaElement{{AttributeName: "leaseKey", KeyType: "HASH"}},
+		BillingMode:          "PAY_PER_REQUEST",
+	}
+}
+
+// CreateTableIfNotExists creates the table described by definition if it does not exist yet, then waits for it to become ACTIVE.
+// It is safe to call from several processes at once.
+func (s *DynamoDBService) CreateTableIfNotExists(definition TableDefinition) (TableDescription, error) {
+	table := Table{Name: definition.TableName, Service: s}
+
+	_, err := table.Describe()
+	if errors.Is(err, ErrResourceNotFound) {
+		_, err = s.CreateTable(definition)
+
+		// Someone else created it first
+		if errors.Is(err, ErrResourceInUse) {
+			err = nil
+		}
+	}
+
+	if err != nil {
+		return TableDescription{}, err
+	}
+	return table.WaitUntilActive()
+}
+
+// CheckpointStore keeps the sequence number each shard of a stream has been processed up to, in a table defined by CheckpointTableDefinition.
+// Checkpoints are kept in the checkpoint attribute, the same as the Kinesis Client Library.
+type CheckpointStore struct {
+	Table TableAPI // The table the checkpoints are in. It can be a fake in tests.
+}
+
+// CheckpointStoreOptions say how NewCheckpointStore sets up the table.
+type CheckpointStoreOptions struct {
+	CreateTable bool // Create the table with CheckpointTableDefinition if it does not exist, and wait for it to become ACTIVE
+}
+
+// NewCheckpointStore returns a CheckpointStore that uses the table with the name. If options.CreateTable is set, the table is created
+// if it does not exist, and NewCheckpointStore returns once it is ACTIVE, so a consumer can start using the store straight away.
+func (s *DynamoDBService) NewCheckpointStore(name string, options CheckpointStoreOptions) (*CheckpointStore, error) {
+	if options.CreateTable {
+		_, err := s.CreateTableIfNotExists(CheckpointTableDefinition(name))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &CheckpointStore{Table: &Table{Name: name, Service: s}}, nil
+}
+
+// Checkpoint records that the shard has been processed up to and including the sequence number.
+func (c *CheckpointStore) Checkpoint(shardID string, sequenceNumber string) error {
+	return c.Table.PutItem(Item{"leaseKey": S(shardID), "checkpoint": S(sequenceNumber)})
+}
+
+// GetCheckpoint returns the sequence number the shard has been processed up to. It is empty if there is no checkpoint for the shard.
+func (c *CheckpointStore) GetCheckpoint(shardID string) (string, error) {
+	item, err := c.Table.GetItem(Item{"leaseKey": S(shardID)}, true)
+	if err != nil {
+		return "", err
+	}
+	return item["checkpoint"].S, nil
+}
@@ -0,0 +1,224 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testBootstrapServer pretends to be DynamoDB where the table does not exist until it is created.
+// If createError is set, it is returned from CreateTable.
+func testBootstrapServer(calls *[]string, createError *dynamoDBError) *httptest.Server {
+	created := false
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get("X-Amz-Target")
+		*calls = append(*calls, target)
+
+		switch {
+		case target == "DynamoDB_20120810.CreateTable" && createError != nil:
+			b, _ := json.Marshal(createError)
+			w.WriteHeader(400)
+			w.Write(b)
+			created = true
+		case target == "DynamoDB_20120810.CreateTable":
+			w.Write([]byte("{}"))
+			created = true
+		case !created:
+			testHTTP404(w, r)
+		default:
+			w.Write(testDescribeTableResult)
+		}
+	}))
+}
+
+func TestCheckpointTableDefinition(t *testing.T) {
+	Convey("The checkpoint table definition", t, func() {
+		definition := CheckpointTableDefinition("leases")
+
+		Convey("Uses the given name", func() {
+			So(definition.TableName, ShouldEqual, "leases")
+		})
+		Convey("Is keyed by leaseKey", func() {
+			So(definition.KeySchema, ShouldResemble, []KeySchemaElement{{AttributeName: "leaseKey", KeyType: "HASH"}})
+		})
+		Convey("Uses on-demand capacity", func() {
+			So(definition.BillingMode, ShouldEqual, "PAY_PER_REQUEST")
+			So(definition.ProvisionedThroughput, ShouldBeNil)
+		})
+	})
+}
+
+func TestCreateTableIfNotExists(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a table that does not exist", t, func() {
+		calls := []string{}
+		ts := testBootstrapServer(&calls, nil)
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		description, err := ds.CreateTableIfNotExists(CheckpointTableDefinition("foo"))
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It creates the table and waits for it", func() {
+			So(calls, ShouldResemble, []string{"DynamoDB_20120810.DescribeTable", "DynamoDB_20120810.CreateTable", "DynamoDB_20120810.DescribeTable"})
+			So(description.TableStatus, ShouldEqual, "ACTIVE")
+		})
+	})
+	Convey("Given a table that already exists", t, func() {
+		calls := []string{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Header.Get("X-Amz-Target"))
+			w.Write(testDescribeTableResult)
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.CreateTableIfNotExists(CheckpointTableDefinition("foo"))
+
+		Convey("It does not create it again", func() {
+			So(err, ShouldBeNil)
+			So(calls, ShouldNotContain, "DynamoDB_20120810.CreateTable")
+		})
+	})
+	Convey("Given a table that someone else creates at the same time", t, func() {
+		calls := []string{}
+		inUse := dynamoDBError{Type: "com.amazonaws.dynamodb.v20120810#ResourceInUseException", Message: "Table already exists: foo"}
+		ts := testBootstrapServer(&calls, &inUse)
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.CreateTableIfNotExists(CheckpointTableDefinition("foo"))
+
+		Convey("It waits for their table", func() {
+			So(err, ShouldBeNil)
+			So(calls[len(calls)-1], ShouldEqual, "DynamoDB_20120810.DescribeTable")
+		})
+	})
+	Convey("Given a server that returns other errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#AccessDeniedException", "message": "no"}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.CreateTableIfNotExists(CheckpointTableDefinition("foo"))
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNewCheckpointStore(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a checkpoint table that does not exist", t, func() {
+		calls := []string{}
+		ts := testBootstrapServer(&calls, nil)
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		Convey("With CreateTable, it creates the table and waits for it", func() {
+			store, err := ds.NewCheckpointStore("leases", CheckpointStoreOptions{CreateTable: true})
+			So(err, ShouldBeNil)
+			So(store.Table.(*Table).Name, ShouldEqual, "leases")
+			So(calls, ShouldResemble, []string{"DynamoDB_20120810.DescribeTable", "DynamoDB_20120810.CreateTable", "DynamoDB_20120810.DescribeTable"})
+		})
+		Convey("Without CreateTable, it does not call DynamoDB", func() {
+			store, err := ds.NewCheckpointStore("leases", CheckpointStoreOptions{})
+			So(err, ShouldBeNil)
+			So(store.Table.(*Table).Name, ShouldEqual, "leases")
+			So(calls, ShouldBeEmpty)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"__type": "com.amazonaws.dynamodb.v20120810#AccessDeniedException", "message": "no"}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		store, err := ds.NewCheckpointStore("leases", CheckpointStoreOptions{CreateTable: true})
+
+		Convey("It returns the error and no store", func() {
+			So(err, ShouldNotBeNil)
+			So(store, ShouldBeNil)
+		})
+	})
+}
+
+func TestCheckpointStore(t *testing.T) {
+	Convey("Given a checkpoint store", t, func() {
+		targets := []string{}
+		bodies := []string{}
+		ts := testItemServer(`{"Item": {"leaseKey": {"S": "shardId-000000000000"}, "checkpoint": {"S": "49590338271490256608559692538361571095921575989136588898"}}}`, &targets, &bodies)
+		defer ts.Close()
+		store := CheckpointStore{Table: &Table{Name: "leases", Service: &DynamoDBService{Endpoint: ts.URL}}}
+
+		Convey("Checkpoint puts the sequence number under the shard", func() {
+			err := store.Checkpoint("shardId-000000000000", "49590338271490256608559692538361571095921575989136588898")
+			So(err, ShouldBeNil)
+			So(targets, ShouldResemble, []string{"DynamoDB_20120810.PutItem"})
+			So(bodies[0], ShouldEqual, `{"Item":{"checkpoint":{"S":"49590338271490256608559692538361571095921575989136588898"},"leaseKey":{"S":"shardId-000000000000"}},"TableName":"leases"}`)
+		})
+		Convey("GetCheckpoint reads it back consistently", func() {
+			sequenceNumber, err := store.GetCheckpoint("shardId-000000000000")
+			So(err, ShouldBeNil)
+			So(sequenceNumber, ShouldEqual, "49590338271490256608559692538361571095921575989136588898")
+			So(bodies[0], ShouldEqual, `{"ConsistentRead":true,"Key":{"leaseKey":{"S":"shardId-000000000000"}},"TableName":"leases"}`)
+		})
+	})
+	Convey("Given a shard without a checkpoint", t, func() {
+		targets := []string{}
+		bodies := []string{}
+		ts := testItemServer(`{}`, &targets, &bodies)
+		defer ts.Close()
+		store := CheckpointStore{Table: &Table{Name: "leases", Service: &DynamoDBService{Endpoint: ts.URL}}}
+
+		sequenceNumber, err := store.GetCheckpoint("shardId-000000000001")
+
+		Convey("It returns an empty sequence number", func() {
+			So(err, ShouldBeNil)
+			So(sequenceNumber, ShouldEqual, "")
+		})
+	})
+}
+
+// fakeItemTable is a TableAPI without a server that keeps items in memory, by their leaseKey.
+type fakeItemTable struct {
+	TableAPI
+	items map[string]Item
+}
+
+func (t *fakeItemTable) PutItem(item Item) error {
+	t.items[item["leaseKey"].S] = item
+	return nil
+}
+
+func (t *fakeItemTable) GetItem(key Item, consistent bool) (Item, error) {
+	return t.items[key["leaseKey"].S], nil
+}
+
+func TestCheckpointStoreWithFakeTable(t *testing.T) {
+	Convey("Given a checkpoint store on a fake table", t, func() {
+		store := CheckpointStore{Table: &fakeItemTable{items: map[string]Item{}}}
+
+		Convey("A checkpoint can be read back", func() {
+			So(store.Checkpoint("shardId-000000000000", "42"), ShouldBeNil)
+			sequenceNumber, err := store.GetCheckpoint("shardId-000000000000")
+			So(err, ShouldBeNil)
+			So(sequenceNumber, ShouldEqual, "42")
+		})
+	})
+}
@@ -0,0 +1,222 @@
+// Package dynamodb provides a way to interact with the AWS DynamoDB service.
+package dynamodb
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type dynamoDBError -field Type -service DynamoDB ConditionalCheckFailedException ResourceNotFoundException ResourceInUseException ProvisionedThroughputExceededException RequestLimitExceeded LimitExceededException ItemCollectionSizeLimitExceededException TransactionCanceledException TransactionConflictException ValidationException ThrottlingException
+
+// dynamoDBError is the error document returned from the DynamoDB service.
+type dynamoDBError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the dynamoDBError into an error message.
+func (e dynamoDBError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. DynamoDB prefixes its error types with a namespace, so only the end is compared.
+func (e dynamoDBError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+var waiterTimeoutError = dynamoDBError{Type: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks.
+var WaitInterval = 5 * time.Second
+
+// MaxWaits is the number of times a waiter checks before giving up.
+var MaxWaits = 120
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *DynamoDBService) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func dynamoDBRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := dynamoDBError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") || error.is("Throttling") {
+		return true, error
+	}
+
+	if error.is("ProvisionedThroughputExceededException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// DynamoDBService is the DynamoDB service at AWS.
+type DynamoDBService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// DynamoDBAPI is implemented by *DynamoDBService. Code that takes a DynamoDBAPI can be given a fake in tests instead.
+type DynamoDBAPI interface {
+	CreateGlobalTable(name string, regions []string) (GlobalTableDescription, error)
+	CreateTable(definition TableDefinition) (Table, error)
+	CreateTableIfNotExists(definition TableDefinition) (TableDescription, error)
+	DescribeBackup(backupArn string) (BackupDescription, error)
+	DescribeGlobalTable(name string) (GlobalTableDescription, error)
+	ListBackups(tableName string) ([]BackupSummary, error)
+	RestoreTableFromBackup(backupArn string, targetTableName string) (Table, error)
+	WaitForBackup(backupArn string) (BackupDetails, error)
+}
+
+var _ DynamoDBAPI = (*DynamoDBService)(nil)
+
+// New returns a DynamoDBService with the options applied. Its endpoint is gaws.Endpoint("dynamodb", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *DynamoDBService {
+	config := gaws.NewConfig(options...)
+	return &DynamoDBService{Endpoint: config.ServiceEndpoint("dynamodb"), config: config}
+}
+
+func (s *DynamoDBService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: dynamoDBRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("dynamodb", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.0",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Table is a DynamoDB table.
+type Table struct {
+	Name    string           // The name of the table
+	Service *DynamoDBService // The service for this region
+}
+
+// TableAPI is implemented by *Table. The helpers that work on a table, like BatchWriter and CheckpointStore, take a TableAPI,
+// so they can be given a fake in tests instead.
+type TableAPI interface {
+	BatchWriteItem(requests []WriteRequest) error
+	CreateBackup(backupName string) (BackupDetails, error)
+	Describe() (TableDescription, error)
+	GetItem(key Item, consistent bool) (Item, error)
+	PutItem(item Item) error
+	Query(condition KeyCondition) ([]Item, error)
+	Update(update TableUpdate) error
+	WaitUntilActive() (TableDescription, error)
+	WaitUntilRestored() (TableDescription, error)
+}
+
+var _ TableAPI = (*Table)(nil)
+
+// AttributeDefinition describes an attribute used in a key schema.
+type AttributeDefinition struct {
+	AttributeName string
+	AttributeType string // S, N, or B
+}
+
+// KeySchemaElement is one part of a table's primary key.
+type KeySchemaElement struct {
+	AttributeName string
+	KeyType       string // HASH or RANGE
+}
+
+// ProvisionedThroughput is the read and write capacity of a table.
+type ProvisionedThroughput struct {
+	ReadCapacityUnits  int
+	WriteCapacityUnits int
+}
+
+// StreamSpecification turns DynamoDB Streams on or off for a table.
+type StreamSpecification struct {
+	StreamEnabled  bool
+	StreamViewType string `json:",omitempty"` // KEYS_ONLY, NEW_IMAGE, OLD_IMAGE, or NEW_AND_OLD_IMAGES
+}
+
+// SSESpecification is the encryption at rest settings for a table.
+type SSESpecification struct {
+	Enabled        bool   // True to use a KMS key, false to use an AWS owned key
+	KMSMasterKeyId string `json:",omitempty"` // The KMS key to use. The AWS managed key, alias/aws/dynamodb, is used if this is empty.
+	SSEType        string `json:",omitempty"` // KMS is the only supported type
+}
+
+// SSEDescription is how a table is encrypted at rest.
+type SSEDescription struct {
+	KMSMasterKeyArn string
+	SSEType         string
+	Status          string // The status of the encryption. May be ENABLING, ENABLED, DISABLING, DISABLED, or UPDATING.
+}
+
+// TableDefinition is everything needed to create a table.
+type TableDefinition struct {
+	AttributeDefinitions  []AttributeDefinition
+	BillingMode           string `json:",omitempty"` // PROVISIONED or PAY_PER_REQUEST
+	KeySchema             []KeySchemaElement
+	ProvisionedThroughput *ProvisionedThroughput `json:",omitempty"` // Required unless BillingMode is PAY_PER_REQUEST
+	SSESpecification      *SSESpecification      `json:",omitempty"` // Encryption at rest. Tables are encrypted with an AWS owned key if this is nil.
+	StreamSpecification   *StreamSpecification   `json:",omitempty"`
+	TableName             string
+}
+
+// TableDescription is the description of a DynamoDB table.
+type TableDescription struct {
+	AttributeDefinitions []AttributeDefinition
+	CreationDateTime     float64
+	ItemCount            int64
+	KeySchema            []KeySchemaElement
+	LatestStreamArn      string
+	RestoreSummary       *RestoreSummary // Set if the table was restored from a backup
+	SSEDescription       *SSEDescription // How the table is encrypted at rest. It is nil for tables encrypted with an AWS owned key.
+	StreamSpecification  *StreamSpecification
+	TableArn             string
+	TableName            string
+	TableSizeBytes       int64
+	TableStatus          string // The status of the table. May be CREATING, UPDATING, DELETING, or ACTIVE.
+}
+
+// RestoreSummary describes the restore of a table from a backup.
+type RestoreSummary struct {
+	RestoreDateTime   float64
+	RestoreInProgress bool
+	SourceBackupArn   string
+	SourceTableArn    string
+}
+
+// CreateTable creates a new DynamoDB table. It returns a Table and an error if it fails.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_CreateTable.html for more details.
+func (s *DynamoDBService) CreateTable(definition TableDefinition) (Table, error) {
+	table := Table{Name: definition.TableName, Service: s}
+
+	bodyAsJson, err := json.Marshal(definition)
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.CreateTable"
+
+	_, err = req.Do()
+
+	return table, err
+}
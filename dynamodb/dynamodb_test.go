@@ -0,0 +1,174 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var notFoundError = dynamoDBError{Type: "com.amazonaws.dynamodb.v20120810#ResourceNotFoundException", Message: "Requested resource not found"}
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(notFoundError)
+
+	w.WriteHeader(400)
+	w.Write([]byte(b))
+}
+
+var testTableDefinition = TableDefinition{
+	TableName:             "foo",
+	AttributeDefinitions:  []AttributeDefinition{{AttributeName: "id", AttributeType: "S"}},
+	KeySchema:             []KeySchemaElement{{AttributeName: "id", KeyType: "HASH"}},
+	ProvisionedThroughput: &ProvisionedThroughput{ReadCapacityUnits: 5, WriteCapacityUnits: 5},
+}
+
+func TestCreateTable(t *testing.T) {
+	Convey("Given a table definition", t, func() {
+		Convey("When CreateTable is run against a server that always returns 200", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+			defer ts.Close()
+
+			ds := DynamoDBService{Endpoint: ts.URL}
+
+			result, err := ds.CreateTable(testTableDefinition)
+
+			Convey("It does not return an error", func() {
+				So(err, ShouldBeNil)
+			})
+			Convey("It returns a Table with the right name", func() {
+				So(result.Name, ShouldEqual, "foo")
+			})
+		})
+		Convey("When CreateTable is run with encryption settings", func() {
+			definition := TableDefinition{}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&definition)
+				w.Write([]byte("{}"))
+			}))
+			defer ts.Close()
+
+			ds := DynamoDBService{Endpoint: ts.URL}
+			encrypted := testTableDefinition
+			encrypted.SSESpecification = &SSESpecification{Enabled: true, SSEType: "KMS", KMSMasterKeyId: "alias/foo"}
+
+			_, err := ds.CreateTable(encrypted)
+
+			Convey("It sends the encryption settings", func() {
+				So(err, ShouldBeNil)
+				So(definition.SSESpecification, ShouldResemble, encrypted.SSESpecification)
+			})
+		})
+		Convey("When CreateTable is run against a server that always returns errors", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+			defer ts.Close()
+
+			ds := DynamoDBService{Endpoint: ts.URL}
+
+			_, err := ds.CreateTable(testTableDefinition)
+
+			Convey("It returns an error", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+	})
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := dynamoDBRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := dynamoDBRetryPredicate(500, []byte("{\"__type\": \"foo\",\"message\":\"bar\"}"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a namespaced \"ProvisionedThroughputExceededException\" type", t, func() {
+		result, _ := dynamoDBRetryPredicate(400, []byte("{\"__type\": \"com.amazonaws.dynamodb.v20120810#ProvisionedThroughputExceededException\",\"message\":\"bar\"}"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"ResourceNotFoundException\" type", t, func() {
+		result, err := dynamoDBRetryPredicate(400, []byte("{\"__type\": \"com.amazonaws.dynamodb.v20120810#ResourceNotFoundException\",\"message\":\"bar\"}"))
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("The error is returned", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestErrors(t *testing.T) {
+	Convey("Given an error from DynamoDB, whose type has a namespace", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.CreateTable(testTableDefinition)
+
+		Convey("It is the error for its type", func() {
+			So(errors.Is(err, ErrResourceNotFound), ShouldBeTrue)
+			So(errors.Is(err, ErrConditionalCheckFailed), ShouldBeFalse)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("dynamodb", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
+
+func TestWaitBackoff(t *testing.T) {
+	Convey("Given a client with a waiter backoff and something that is done on the fourth check", t, func() {
+		clock := gawstest.NewClock(time.Now())
+		s := New(gaws.WithWaitBackoff(gaws.ExponentialBackoff{Base: time.Second}), gaws.WithClock(clock))
+
+		checks := 0
+		err := s.wait(func() (bool, error) {
+			checks++
+			return checks == 4, nil
+		})
+
+		Convey("The waiter sleeps on the client's clock as the backoff says", func() {
+			So(err, ShouldBeNil)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second})
+		})
+	})
+}
@@ -0,0 +1,26 @@
+// Code generated by errgen from the go:generate line in dynamodb.go. DO NOT EDIT.
+
+package dynamodb
+
+import "strings"
+
+// These are errors DynamoDB returns. errors.Is(err, dynamodb.ErrConditionalCheckFailed) is true for any error with the same type, whatever its message.
+var (
+	ErrConditionalCheckFailed          = dynamoDBError{Type: "ConditionalCheckFailedException"}
+	ErrResourceNotFound                = dynamoDBError{Type: "ResourceNotFoundException"}
+	ErrResourceInUse                   = dynamoDBError{Type: "ResourceInUseException"}
+	ErrProvisionedThroughputExceeded   = dynamoDBError{Type: "ProvisionedThroughputExceededException"}
+	ErrRequestLimitExceeded            = dynamoDBError{Type: "RequestLimitExceeded"}
+	ErrLimitExceeded                   = dynamoDBError{Type: "LimitExceededException"}
+	ErrItemCollectionSizeLimitExceeded = dynamoDBError{Type: "ItemCollectionSizeLimitExceededException"}
+	ErrTransactionCanceled             = dynamoDBError{Type: "TransactionCanceledException"}
+	ErrTransactionConflict             = dynamoDBError{Type: "TransactionConflictException"}
+	ErrValidation                      = dynamoDBError{Type: "ValidationException"}
+	ErrThrottling                      = dynamoDBError{Type: "ThrottlingException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e dynamoDBError) Is(target error) bool {
+	t, ok := target.(dynamoDBError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
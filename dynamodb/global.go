@@ -0,0 +1,111 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// Replica is a region that a global table is replicated to.
+type Replica struct {
+	RegionName string
+}
+
+// GlobalTableDescription is the description of a DynamoDB global table.
+type GlobalTableDescription struct {
+	CreationDateTime  float64
+	GlobalTableArn    string
+	GlobalTableName   string
+	GlobalTableStatus string    // The status of the global table. May be CREATING, ACTIVE, DELETING, or UPDATING.
+	ReplicationGroup  []Replica // The regions the table is replicated to
+}
+
+type globalTableRequest struct {
+	GlobalTableName  string
+	ReplicationGroup []Replica `json:",omitempty"`
+}
+
+type globalTableResult struct {
+	GlobalTableDescription GlobalTableDescription
+}
+
+// CreateGlobalTable joins tables with the same name in several regions into a global table. The tables must already exist, be empty, and have streams of new and old images enabled.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_CreateGlobalTable.html for more details.
+func (s *DynamoDBService) CreateGlobalTable(name string, regions []string) (GlobalTableDescription, error) {
+	result := globalTableResult{}
+
+	body := globalTableRequest{GlobalTableName: name, ReplicationGroup: make([]Replica, len(regions))}
+	for i, region := range regions {
+		body.ReplicationGroup[i] = Replica{RegionName: region}
+	}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.CreateGlobalTable"
+
+	resp, err := req.Do()
+	if err != nil {
+		return GlobalTableDescription{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return GlobalTableDescription{}, err
+	}
+	return result.GlobalTableDescription, nil
+}
+
+// DescribeGlobalTable describes a global table.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_DescribeGlobalTable.html for more details.
+func (s *DynamoDBService) DescribeGlobalTable(name string) (GlobalTableDescription, error) {
+	result := globalTableResult{}
+
+	body := globalTableRequest{GlobalTableName: name}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.DescribeGlobalTable"
+
+	resp, err := req.Do()
+	if err != nil {
+		return GlobalTableDescription{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return GlobalTableDescription{}, err
+	}
+	return result.GlobalTableDescription, nil
+}
+
+// ReplicateTable creates the table described by definition in each of the regions, waits for the tables to become active, and joins them into a global table.
+// Global tables need streams of new and old images, so the definition's StreamSpecification is replaced. Endpoints are found with gaws.Endpoint.
+func ReplicateTable(definition TableDefinition, regions []string) (GlobalTableDescription, error) {
+	if len(regions) == 0 {
+		return GlobalTableDescription{}, dynamoDBError{Type: "GawsNoRegions", Message: "A global table needs at least one region."}
+	}
+
+	definition.StreamSpecification = &StreamSpecification{StreamEnabled: true, StreamViewType: "NEW_AND_OLD_IMAGES"}
+
+	tables := make([]Table, len(regions))
+	for i, region := range regions {
+		service := &DynamoDBService{Endpoint: gaws.Endpoint("dynamodb", region)}
+
+		table, err := service.CreateTable(definition)
+		if err != nil {
+			return GlobalTableDescription{}, err
+		}
+		tables[i] = table
+	}
+
+	for _, table := range tables {
+		_, err := table.WaitUntilActive()
+		if err != nil {
+			return GlobalTableDescription{}, err
+		}
+	}
+
+	return tables[0].Service.CreateGlobalTable(definition.TableName, regions)
+}
@@ -0,0 +1,136 @@
+package dynamodb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testGlobalTableResult = []byte(`{
+  "GlobalTableDescription": {
+    "CreationDateTime": 1.519161181107E9,
+    "GlobalTableArn": "arn:aws:dynamodb::123456789012:global-table/foo",
+    "GlobalTableName": "foo",
+    "GlobalTableStatus": "CREATING",
+    "ReplicationGroup": [{"RegionName": "us-east-1"}, {"RegionName": "us-west-2"}]
+  }
+}`)
+
+func testGlobalTableSuccess(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(200)
+	w.Write(testGlobalTableResult)
+}
+
+func TestCreateGlobalTable(t *testing.T) {
+	Convey("When CreateGlobalTable is run against a server that returns a global table", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testGlobalTableSuccess))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		description, err := ds.CreateGlobalTable("foo", []string{"us-east-1", "us-west-2"})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the replication group", func() {
+			So(description.ReplicationGroup, ShouldResemble, []Replica{{RegionName: "us-east-1"}, {RegionName: "us-west-2"}})
+		})
+	})
+	Convey("When CreateGlobalTable is run against a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.CreateGlobalTable("foo", []string{"us-east-1"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDescribeGlobalTable(t *testing.T) {
+	Convey("When DescribeGlobalTable is run against a server that returns a global table", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testGlobalTableSuccess))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		description, err := ds.DescribeGlobalTable("foo")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the description", func() {
+			So(description.GlobalTableName, ShouldEqual, "foo")
+		})
+	})
+	Convey("When DescribeGlobalTable is run against a server that returns bad data", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testBadJson))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+
+		_, err := ds.DescribeGlobalTable("foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+// testReplicaServer pretends to be DynamoDB in one region and records the API calls made to it.
+func testReplicaServer(calls *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get("X-Amz-Target")
+		*calls = append(*calls, target)
+
+		switch target {
+		case "DynamoDB_20120810.DescribeTable":
+			w.Write(testDescribeTableResult)
+		case "DynamoDB_20120810.CreateGlobalTable":
+			w.Write(testGlobalTableResult)
+		default:
+			w.Write([]byte("{}"))
+		}
+	}))
+}
+
+func TestReplicateTable(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given two regions with DynamoDB endpoints", t, func() {
+		eastCalls, westCalls := []string{}, []string{}
+		east, west := testReplicaServer(&eastCalls), testReplicaServer(&westCalls)
+		defer east.Close()
+		defer west.Close()
+
+		gaws.Regions["test-east-1"] = gaws.AWSRegion{Name: "test-east-1", Endpoints: map[string]string{"dynamodb": east.URL}}
+		gaws.Regions["test-west-1"] = gaws.AWSRegion{Name: "test-west-1", Endpoints: map[string]string{"dynamodb": west.URL}}
+		defer delete(gaws.Regions, "test-east-1")
+		defer delete(gaws.Regions, "test-west-1")
+
+		description, err := ReplicateTable(testTableDefinition, []string{"test-east-1", "test-west-1"})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the global table", func() {
+			So(description.GlobalTableName, ShouldEqual, "foo")
+		})
+		Convey("It creates the table in every region and the global table in the first", func() {
+			So(eastCalls, ShouldResemble, []string{"DynamoDB_20120810.CreateTable", "DynamoDB_20120810.DescribeTable", "DynamoDB_20120810.CreateGlobalTable"})
+			So(westCalls, ShouldResemble, []string{"DynamoDB_20120810.CreateTable", "DynamoDB_20120810.DescribeTable"})
+		})
+	})
+	Convey("Given no regions", t, func() {
+		_, err := ReplicateTable(testTableDefinition, []string{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// AttributeValue is the value of an attribute in a DynamoDB item. Only one of the fields should be set.
+type AttributeValue struct {
+	B    []byte                    `json:",omitempty"` // Binary
+	BOOL *bool                     `json:",omitempty"` // Boolean
+	BS   [][]byte                  `json:",omitempty"` // Binary set
+	L    []AttributeValue          `json:",omitempty"` // List
+	M    map[string]AttributeValue `json:",omitempty"` // Map
+	N    string                    `json:",omitempty"` // Number, as a string
+	NS   []string                  `json:",omitempty"` // Number set
+	NULL bool                      `json:",omitempty"` // Null
+	S    string                    `json:",omitempty"` // String
+	SS   []string                  `json:",omitempty"` // String set
+}
+
+// Item is a DynamoDB item. It maps attribute names to their values.
+type Item map[string]AttributeValue
+
+// S returns a string AttributeValue.
+func S(value string) AttributeValue {
+	return AttributeValue{S: value}
+}
+
+// N returns a number AttributeValue. DynamoDB sends numbers as strings so no precision is lost.
+func N(value string) AttributeValue {
+	return AttributeValue{N: value}
+}
+
+// B returns a binary AttributeValue.
+func B(value []byte) AttributeValue {
+	return AttributeValue{B: value}
+}
+
+type getItemRequest struct {
+	ConsistentRead bool `json:",omitempty"`
+	Key            Item
+	TableName      string
+}
+
+type getItemResult struct {
+	Item Item
+}
+
+// GetItem gets the item with the key, which has the table's key attributes. If there is no such item, it returns a nil Item.
+// If consistent is true, the read reflects every write that succeeded before it. It is calling the GetItem API call.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_GetItem.html for more details.
+func (t *Table) GetItem(key Item, consistent bool) (Item, error) {
+	result := getItemResult{}
+
+	bodyAsJson, err := json.Marshal(getItemRequest{ConsistentRead: consistent, Key: key, TableName: t.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	req := t.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.GetItem"
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Item, err
+}
+
+type putItemRequest struct {
+	Item      Item
+	TableName string
+}
+
+// PutItem writes the item, replacing the item with the same key if there is one. It is calling the PutItem API call.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_PutItem.html for more details.
+func (t *Table) PutItem(item Item) error {
+	bodyAsJson, err := json.Marshal(putItemRequest{Item: item, TableName: t.Name})
+	if err != nil {
+		return err
+	}
+
+	req := t.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.PutItem"
+
+	_, err = req.Do()
+	return err
+}
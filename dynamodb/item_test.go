@@ -0,0 +1,120 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAttributeValue(t *testing.T) {
+	Convey("When I marshal an item made with S, N, and B", t, func() {
+		item := Item{"name": S("foo"), "count": N("42"), "data": B([]byte("Hello World"))}
+		result, err := json.Marshal(item)
+
+		Convey("There is no error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("Each attribute only has its type set", func() {
+			So(string(result), ShouldEqual, `{"count":{"N":"42"},"data":{"B":"SGVsbG8gV29ybGQ="},"name":{"S":"foo"}}`)
+		})
+	})
+	Convey("When I unmarshal an item from DynamoDB", t, func() {
+		item := Item{}
+		err := json.Unmarshal([]byte(`{"tags": {"SS": ["a", "b"]}, "nested": {"M": {"ok": {"BOOL": true}}}}`), &item)
+
+		Convey("There is no error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("The values are decoded", func() {
+			So(item["tags"].SS, ShouldResemble, []string{"a", "b"})
+			So(*item["nested"].M["ok"].BOOL, ShouldBeTrue)
+		})
+	})
+}
+
+// testItemServer returns a server that responds with body and records the target and body of each request.
+func testItemServer(body string, targets *[]string, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		*targets = append(*targets, r.Header.Get("X-Amz-Target"))
+		*bodies = append(*bodies, string(b))
+		w.Write([]byte(body))
+	}))
+}
+
+func TestGetItem(t *testing.T) {
+	Convey("Given a table with the item", t, func() {
+		targets, bodies := []string{}, []string{}
+		ts := testItemServer(`{"Item": {"leaseKey": {"S": "shard-1"}, "checkpoint": {"S": "49546986683135544286507457936321625675700192471156785154"}}}`, &targets, &bodies)
+		defer ts.Close()
+		table := Table{Name: "foo", Service: &DynamoDBService{Endpoint: ts.URL}}
+
+		item, err := table.GetItem(Item{"leaseKey": S("shard-1")}, true)
+
+		Convey("It returns the item", func() {
+			So(err, ShouldBeNil)
+			So(item["checkpoint"].S, ShouldEqual, "49546986683135544286507457936321625675700192471156785154")
+		})
+		Convey("It asks for a consistent read of the key", func() {
+			So(targets[0], ShouldEqual, "DynamoDB_20120810.GetItem")
+			So(bodies[0], ShouldEqual, `{"ConsistentRead":true,"Key":{"leaseKey":{"S":"shard-1"}},"TableName":"foo"}`)
+		})
+	})
+	Convey("Given a table without the item", t, func() {
+		targets, bodies := []string{}, []string{}
+		ts := testItemServer(`{}`, &targets, &bodies)
+		defer ts.Close()
+		table := Table{Name: "foo", Service: &DynamoDBService{Endpoint: ts.URL}}
+
+		item, err := table.GetItem(Item{"leaseKey": S("shard-1")}, false)
+
+		Convey("It returns a nil item", func() {
+			So(err, ShouldBeNil)
+			So(item, ShouldBeNil)
+			So(bodies[0], ShouldEqual, `{"Key":{"leaseKey":{"S":"shard-1"}},"TableName":"foo"}`)
+		})
+	})
+	Convey("Given a table that does not exist", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		table := Table{Name: "foo", Service: &DynamoDBService{Endpoint: ts.URL}}
+
+		_, err := table.GetItem(Item{"leaseKey": S("shard-1")}, false)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestPutItem(t *testing.T) {
+	Convey("Given an item", t, func() {
+		targets, bodies := []string{}, []string{}
+		ts := testItemServer(`{}`, &targets, &bodies)
+		defer ts.Close()
+		table := Table{Name: "foo", Service: &DynamoDBService{Endpoint: ts.URL}}
+
+		err := table.PutItem(Item{"leaseKey": S("shard-1"), "owner": S("worker-1")})
+
+		Convey("It puts the item", func() {
+			So(err, ShouldBeNil)
+			So(targets[0], ShouldEqual, "DynamoDB_20120810.PutItem")
+			So(bodies[0], ShouldEqual, `{"Item":{"leaseKey":{"S":"shard-1"},"owner":{"S":"worker-1"}},"TableName":"foo"}`)
+		})
+	})
+	Convey("Given a table that does not exist", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		table := Table{Name: "foo", Service: &DynamoDBService{Endpoint: ts.URL}}
+
+		err := table.PutItem(Item{"leaseKey": S("shard-1")})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
@@ -0,0 +1,131 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// KeyCondition is a KeyConditionExpression and the names and values it refers to. Build one with HashKey and narrow it with the sort key methods.
+type KeyCondition struct {
+	Expression string                    // The KeyConditionExpression
+	Names      map[string]string         // The ExpressionAttributeNames
+	Values     map[string]AttributeValue // The ExpressionAttributeValues
+}
+
+// HashKey returns a KeyCondition that matches the items whose partition key is value.
+func HashKey(name string, value AttributeValue) KeyCondition {
+	return KeyCondition{
+		Expression: "#hk = :hk",
+		Names:      map[string]string{"#hk": name},
+		Values:     map[string]AttributeValue{":hk": value},
+	}
+}
+
+// sortKey adds a condition on the sort key. The attribute name is always #sk and the values are :sk and :sk2.
+func (c KeyCondition) sortKey(name string, condition string, values ...AttributeValue) KeyCondition {
+	narrowed := KeyCondition{
+		Expression: c.Expression + " AND " + condition,
+		Names:      map[string]string{"#sk": name},
+		Values:     map[string]AttributeValue{},
+	}
+
+	for k, v := range c.Names {
+		narrowed.Names[k] = v
+	}
+	for k, v := range c.Values {
+		narrowed.Values[k] = v
+	}
+
+	placeholders := []string{":sk", ":sk2"}
+	for i, v := range values {
+		narrowed.Values[placeholders[i]] = v
+	}
+	return narrowed
+}
+
+// Equal narrows the condition to items whose sort key is value.
+func (c KeyCondition) Equal(name string, value AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk = :sk", value)
+}
+
+// LessThan narrows the condition to items whose sort key is less than value.
+func (c KeyCondition) LessThan(name string, value AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk < :sk", value)
+}
+
+// LessThanOrEqual narrows the condition to items whose sort key is less than or equal to value.
+func (c KeyCondition) LessThanOrEqual(name string, value AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk <= :sk", value)
+}
+
+// GreaterThan narrows the condition to items whose sort key is greater than value. It is useful for reading a time series after a point in time.
+func (c KeyCondition) GreaterThan(name string, value AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk > :sk", value)
+}
+
+// GreaterThanOrEqual narrows the condition to items whose sort key is greater than or equal to value.
+func (c KeyCondition) GreaterThanOrEqual(name string, value AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk >= :sk", value)
+}
+
+// Between narrows the condition to items whose sort key is between low and high, inclusive.
+func (c KeyCondition) Between(name string, low AttributeValue, high AttributeValue) KeyCondition {
+	return c.sortKey(name, "#sk BETWEEN :sk AND :sk2", low, high)
+}
+
+// BeginsWith narrows the condition to items whose sort key starts with prefix. It is useful for hierarchical keys like "country#state#city".
+func (c KeyCondition) BeginsWith(name string, prefix string) KeyCondition {
+	return c.sortKey(name, "begins_with(#sk, :sk)", S(prefix))
+}
+
+type queryRequest struct {
+	ExclusiveStartKey         Item `json:",omitempty"`
+	ExpressionAttributeNames  map[string]string
+	ExpressionAttributeValues map[string]AttributeValue
+	KeyConditionExpression    string
+	TableName                 string
+}
+
+type queryResult struct {
+	Items            []Item
+	LastEvaluatedKey Item
+}
+
+// Query returns all of the items in the table that match the key condition. It keeps asking for pages until there are no more.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_Query.html for more details.
+func (t *Table) Query(condition KeyCondition) ([]Item, error) {
+	items := []Item{}
+	body := queryRequest{
+		ExpressionAttributeNames:  condition.Names,
+		ExpressionAttributeValues: condition.Values,
+		KeyConditionExpression:    condition.Expression,
+		TableName:                 t.Name,
+	}
+
+	for {
+		result := queryResult{}
+		bodyAsJson, err := json.Marshal(body)
+
+		req := t.Service.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "DynamoDB_20120810.Query"
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Item{}, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return []Item{}, err
+		}
+
+		items = append(items, result.Items...)
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return items, nil
+		}
+		body.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKeyConditions(t *testing.T) {
+	Convey("Given a hash key condition", t, func() {
+		c := HashKey("device", S("sensor-1"))
+
+		Convey("It matches the partition key", func() {
+			So(c.Expression, ShouldEqual, "#hk = :hk")
+			So(c.Names, ShouldResemble, map[string]string{"#hk": "device"})
+			So(c.Values, ShouldResemble, map[string]AttributeValue{":hk": S("sensor-1")})
+		})
+
+		Convey("BeginsWith adds a begins_with condition on the sort key", func() {
+			narrowed := c.BeginsWith("path", "us#ny#")
+			So(narrowed.Expression, ShouldEqual, "#hk = :hk AND begins_with(#sk, :sk)")
+			So(narrowed.Names["#sk"], ShouldEqual, "path")
+			So(narrowed.Values[":sk"], ShouldResemble, S("us#ny#"))
+		})
+
+		Convey("Between adds both bounds", func() {
+			narrowed := c.Between("time", N("100"), N("200"))
+			So(narrowed.Expression, ShouldEqual, "#hk = :hk AND #sk BETWEEN :sk AND :sk2")
+			So(narrowed.Values[":sk"], ShouldResemble, N("100"))
+			So(narrowed.Values[":sk2"], ShouldResemble, N("200"))
+		})
+
+		Convey("GreaterThan adds a comparison", func() {
+			narrowed := c.GreaterThan("time", N("100"))
+			So(narrowed.Expression, ShouldEqual, "#hk = :hk AND #sk > :sk")
+		})
+
+		Convey("Narrowing does not change the original condition", func() {
+			c.LessThan("time", N("100"))
+			So(c.Expression, ShouldEqual, "#hk = :hk")
+			So(len(c.Values), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestQuery(t *testing.T) {
+	Convey("Given a server that returns items over two pages", t, func() {
+		requests := []queryRequest{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := queryRequest{}
+			json.NewDecoder(r.Body).Decode(&request)
+			requests = append(requests, request)
+
+			if request.ExclusiveStartKey == nil {
+				w.Write([]byte(`{"Items": [{"device": {"S": "sensor-1"}, "time": {"N": "150"}}], "LastEvaluatedKey": {"device": {"S": "sensor-1"}, "time": {"N": "150"}}}`))
+				return
+			}
+			w.Write([]byte(`{"Items": [{"device": {"S": "sensor-1"}, "time": {"N": "175"}}]}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "readings", Service: &ds}
+
+		items, err := testTable.Query(HashKey("device", S("sensor-1")).Between("time", N("100"), N("200")))
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the items from every page", func() {
+			So(len(items), ShouldEqual, 2)
+			So(items[1]["time"], ShouldResemble, N("175"))
+		})
+		Convey("It sends the key condition", func() {
+			So(requests[0].KeyConditionExpression, ShouldEqual, "#hk = :hk AND #sk BETWEEN :sk AND :sk2")
+			So(requests[0].TableName, ShouldEqual, "readings")
+		})
+		Convey("It starts the second page after the last key", func() {
+			So(requests[1].ExclusiveStartKey["time"], ShouldResemble, N("150"))
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "readings", Service: &ds}
+
+		items, err := testTable.Query(HashKey("device", S("sensor-1")))
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+		Convey("And the result is empty", func() {
+			So(items, ShouldResemble, []Item{})
+		})
+	})
+}
@@ -0,0 +1,43 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// These are the most a DynamoDB partition serves a second, however much capacity its table has. A table spreads its items over
+// partitions by hash key, so a table whose writes go to few keys is held to them.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/developerguide/bp-partition-key-design.html for more details.
+const (
+	PartitionReadUnitsPerSecond  = 3000 // Read capacity units, each a strongly consistent read of up to 4 KB
+	PartitionWriteUnitsPerSecond = 1000 // Write capacity units, each a write of up to 1 KB
+)
+
+// writeUnitBytes is the most bytes one write capacity unit writes.
+const writeUnitBytes = 1024
+
+// NewPartitionWriteLimiter returns a limiter of write capacity units that keeps to what one partition takes, and waits on the service's
+// clock. It is a cautious guess for tables whose partitions are not known, since a table with many partitions and well spread keys takes
+// more. BatchWriter uses it with gaws.WithQuotaLimits.
+func (s *DynamoDBService) NewPartitionWriteLimiter() *gaws.RateLimiter {
+	return s.config.NewRateLimiter(PartitionWriteUnitsPerSecond, PartitionWriteUnitsPerSecond)
+}
+
+// writeUnits estimates the write capacity units a batch takes: one for each KB of each item, or one for each delete. An item's size is
+// taken from its JSON, which is a little larger than the size DynamoDB counts, so the estimate is cautious.
+func writeUnits(batch []WriteRequest) float64 {
+	units := 0
+	for _, r := range batch {
+		if r.PutRequest == nil {
+			units++
+			continue
+		}
+		size := writeUnitBytes
+		if b, err := json.Marshal(r.PutRequest.Item); err == nil {
+			size = len(b)
+		}
+		units += (size + writeUnitBytes - 1) / writeUnitBytes
+	}
+	return float64(units)
+}
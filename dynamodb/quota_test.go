@@ -0,0 +1,53 @@
+package dynamodb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWriteUnits(t *testing.T) {
+	Convey("Given a batch of puts and deletes", t, func() {
+		batch := []WriteRequest{
+			{PutRequest: &PutRequest{Item: Item{"id": S("1")}}},
+			{PutRequest: &PutRequest{Item: Item{"id": S(strings.Repeat("x", 2000))}}},
+			{DeleteRequest: &DeleteRequest{Key: Item{"id": S("2")}}},
+		}
+
+		Convey("Each put takes a unit for each KB, and each delete one", func() {
+			So(writeUnits(batch), ShouldEqual, 1+2+1)
+		})
+	})
+}
+
+func TestBatchWriterQuotaLimits(t *testing.T) {
+	Convey("Given a batch writer of a service with quota limits, and more than a partition takes in a second", t, func() {
+		ts, requests := testBatchServer(nil)
+		defer ts.Close()
+
+		clock := gawstest.NewManualClock(time.Now())
+		testTable := Table{Name: "foo", Service: New(gaws.WithEndpoint(ts.URL), gaws.WithQuotaLimits(), gaws.WithClock(clock))}
+		w := testTable.NewBatchWriter()
+		for i := 0; i < 50; i++ {
+			// Each item takes 30 write units, so two batches take 1500.
+			w.Put(Item{"id": N(strings.Repeat("1", 30*1024-20))})
+		}
+
+		// The writer waits for its next flush, and for the partition to take the second batch.
+		clock.BlockUntil(2)
+		sent := len(requests())
+		clock.Advance(500 * time.Millisecond)
+		err := w.Close()
+
+		Convey("The batches are paced to the partition's write capacity", func() {
+			So(err, ShouldBeNil)
+			So(sent, ShouldEqual, 1)
+			So(len(requests()), ShouldEqual, 2)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{FlushInterval, 500 * time.Millisecond})
+		})
+	})
+}
@@ -0,0 +1,76 @@
+package dynamodb
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+type describeTableRequest struct {
+	TableName string
+}
+
+type describeTableResult struct {
+	Table TableDescription
+}
+
+// Describe describes a table. It is calling the DescribeTable API call.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_DescribeTable.html for more details.
+func (t *Table) Describe() (TableDescription, error) {
+	result := describeTableResult{}
+
+	body := describeTableRequest{TableName: t.Name}
+	bodyAsJson, err := json.Marshal(body)
+
+	req := t.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.DescribeTable"
+
+	resp, err := req.Do()
+	if err != nil {
+		return TableDescription{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return TableDescription{}, err
+	}
+	return result.Table, nil
+}
+
+// TableUpdate is the set of changes to make to a table. Fields that are nil or empty are left alone.
+type TableUpdate struct {
+	AttributeDefinitions  []AttributeDefinition  `json:",omitempty"`
+	BillingMode           string                 `json:",omitempty"`
+	ProvisionedThroughput *ProvisionedThroughput `json:",omitempty"`
+	SSESpecification      *SSESpecification      `json:",omitempty"`
+	StreamSpecification   *StreamSpecification   `json:",omitempty"`
+	TableName             string
+}
+
+// Update changes the settings of a table. It is calling the UpdateTable API call.
+// See http://docs.aws.amazon.com/amazondynamodb/latest/APIReference/API_UpdateTable.html for more details.
+func (t *Table) Update(update TableUpdate) error {
+	update.TableName = t.Name
+	bodyAsJson, err := json.Marshal(update)
+
+	req := t.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "DynamoDB_20120810.UpdateTable"
+
+	_, err = req.Do()
+
+	return err
+}
+
+// WaitUntilActive waits for the table's status to become ACTIVE. It returns the description of the active table and an error if it fails or takes too long.
+func (t *Table) WaitUntilActive() (TableDescription, error) {
+	var description TableDescription
+
+	err := t.Service.wait(func() (bool, error) {
+		var err error
+		description, err = t.Describe()
+		return description.TableStatus == "ACTIVE", err
+	})
+	return description, err
+}
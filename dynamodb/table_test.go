@@ -0,0 +1,182 @@
+package dynamodb
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testDescribeTableResult = []byte(`{
+  "Table": {
+    "AttributeDefinitions": [{"AttributeName": "id", "AttributeType": "S"}],
+    "CreationDateTime": 1.363729002358E9,
+    "ItemCount": 0,
+    "KeySchema": [{"AttributeName": "id", "KeyType": "HASH"}],
+    "TableArn": "arn:aws:dynamodb:us-east-1:123456789012:table/foo",
+    "TableName": "foo",
+    "TableSizeBytes": 0,
+    "TableStatus": "ACTIVE"
+  }
+}`)
+
+func testDescribeTableSuccess(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(200)
+	w.Write(testDescribeTableResult)
+}
+
+func TestDescribeTable(t *testing.T) {
+	Convey("When you call table.Describe() on a table with an endpoint that returns a description", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testDescribeTableSuccess))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		description, err := testTable.Describe()
+
+		Convey("The result will not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("The result will look like the example", func() {
+			So(description.TableStatus, ShouldEqual, "ACTIVE")
+			So(description.KeySchema[0].AttributeName, ShouldEqual, "id")
+		})
+	})
+	Convey("When you call table.Describe() on a table with an endpoint that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		_, err := testTable.Describe()
+
+		Convey("The result will return an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("When you call table.Describe() on a table with an endpoint that returns bad data", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testBadJson))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		_, err := testTable.Describe()
+
+		Convey("The result will return an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWaitUntilActive(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a table that becomes active on the second check", t, func() {
+		checks := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			checks++
+			if checks < 2 {
+				w.Write([]byte(`{"Table": {"TableName": "foo", "TableStatus": "CREATING"}}`))
+				return
+			}
+			w.Write(testDescribeTableResult)
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		description, err := testTable.WaitUntilActive()
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the active description", func() {
+			So(description.TableStatus, ShouldEqual, "ACTIVE")
+		})
+	})
+	Convey("Given a table that never becomes active", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"Table": {"TableName": "foo", "TableStatus": "CREATING"}}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		_, err := testTable.WaitUntilActive()
+
+		Convey("It returns a waiter timeout error", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+}
+
+func TestUpdateTable(t *testing.T) {
+	Convey("Given a table and a server that records the update", t, func() {
+		update := TableUpdate{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&update)
+			w.Write([]byte("{}"))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		err := testTable.Update(TableUpdate{SSESpecification: &SSESpecification{Enabled: true, SSEType: "KMS", KMSMasterKeyId: "alias/foo"}})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends the table name and the encryption settings", func() {
+			So(update.TableName, ShouldEqual, "foo")
+			So(update.SSESpecification.KMSMasterKeyId, ShouldEqual, "alias/foo")
+		})
+	})
+	Convey("Given a table and a server that returns errors", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		err := testTable.Update(TableUpdate{BillingMode: "PAY_PER_REQUEST"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDescribeEncryptedTable(t *testing.T) {
+	Convey("When you call table.Describe() on a table encrypted with a KMS key", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"Table": {"TableName": "foo", "TableStatus": "ACTIVE", "SSEDescription": {"KMSMasterKeyArn": "arn:aws:kms:us-east-1:123456789012:key/abcd", "SSEType": "KMS", "Status": "ENABLED"}}}`))
+		}))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		description, err := testTable.Describe()
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It reports the encryption settings", func() {
+			So(description.SSEDescription, ShouldResemble, &SSEDescription{KMSMasterKeyArn: "arn:aws:kms:us-east-1:123456789012:key/abcd", SSEType: "KMS", Status: "ENABLED"})
+		})
+	})
+	Convey("When you call table.Describe() on a table encrypted with an AWS owned key", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testDescribeTableSuccess))
+		defer ts.Close()
+		ds := DynamoDBService{Endpoint: ts.URL}
+		testTable := Table{Name: "foo", Service: &ds}
+
+		description, _ := testTable.Describe()
+
+		Convey("There is no encryption description", func() {
+			So(description.SSEDescription, ShouldBeNil)
+		})
+	})
+}
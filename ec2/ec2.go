@@ -0,0 +1,130 @@
+// Package ec2 provides a way to interact with the AWS Elastic Compute Cloud service.
+package ec2
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type ec2Error -field Code -service EC2 InvalidInstanceID.NotFound InvalidInstanceID.Malformed InvalidGroup.NotFound InvalidParameterValue IncorrectInstanceState InsufficientInstanceCapacity InstanceLimitExceeded UnauthorizedOperation DryRunOperation
+
+// ec2Error is the error document returned from the EC2 service.
+type ec2Error struct {
+	Code    string `xml:"Errors>Error>Code"`
+	Message string `xml:"Errors>Error>Message"`
+}
+
+// Error formats the ec2Error into an error message.
+func (e ec2Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func ec2RetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := ec2Error{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "RequestLimitExceeded" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// EC2Service is the EC2 service at AWS. The endpoint for a region is gaws.Endpoint("ec2", region).
+type EC2Service struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// EC2API is implemented by *EC2Service. Code that takes an EC2API can be given a fake in tests instead.
+type EC2API interface {
+	CreateTags(resourceIds []string, tags ...Tag) error
+	DeleteTags(resourceIds []string, tags ...Tag) error
+	DescribeInstances(ids []string, filters ...Filter) ([]Instance, error)
+	DescribeTags(filters ...Filter) ([]TagDescription, error)
+}
+
+var _ EC2API = (*EC2Service)(nil)
+
+// New returns an EC2Service with the options applied. Its endpoint is gaws.Endpoint("ec2", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *EC2Service {
+	config := gaws.NewConfig(options...)
+	return &EC2Service{Endpoint: config.ServiceEndpoint("ec2"), config: config}
+}
+
+func (s *EC2Service) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: ec2RetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("ec2", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an EC2 API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2015-10-01"},
+	}
+}
+
+// setList sets a list parameter. EC2 lists are numbered without .member, like InstanceId.1.
+func setList(params url.Values, name string, values []string) {
+	for i, value := range values {
+		params.Set(fmt.Sprintf("%v.%d", name, i+1), value)
+	}
+}
+
+// Filter limits what a Describe call returns to what has a value in Values for the filter Name.
+// See the Describe calls in the EC2 API reference for the names each of them supports.
+type Filter struct {
+	Name   string
+	Values []string
+}
+
+// TagFilter returns a filter for resources that have a tag with one of the values.
+func TagFilter(key string, values ...string) Filter {
+	return Filter{Name: "tag:" + key, Values: values}
+}
+
+// TagKeyFilter returns a filter for resources that have a tag, whatever its value.
+func TagKeyFilter(keys ...string) Filter {
+	return Filter{Name: "tag-key", Values: keys}
+}
+
+func setFilters(params url.Values, filters []Filter) {
+	for i, filter := range filters {
+		prefix := fmt.Sprintf("Filter.%d.", i+1)
+		params.Set(prefix+"Name", filter.Name)
+		setList(params, prefix+"Value", filter.Values)
+	}
+}
+
+// Tag is a tag on an EC2 resource.
+type Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
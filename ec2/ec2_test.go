@@ -0,0 +1,128 @@
+package ec2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<DescribeInstancesResponse><reservationSet>"))
+}
+
+var testInvalidInstanceIDError = []byte(`<Response>
+  <Errors>
+    <Error>
+      <Code>InvalidInstanceID.NotFound</Code>
+      <Message>The instance ID 'i-1a2b3c4d' does not exist</Message>
+    </Error>
+  </Errors>
+  <RequestID>ea966190-f9aa-478e-9ede-example</RequestID>
+</Response>`)
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(400)
+	w.Write(testInvalidInstanceIDError)
+}
+
+var invalidInstanceIDError = ec2Error{Code: "InvalidInstanceID.NotFound", Message: "The instance ID 'i-1a2b3c4d' does not exist"}
+
+// testPages returns a handler that returns first, then second when it is asked for the page after NextToken page2.
+func testPages(first string, second string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("NextToken") == "page2" {
+			w.Write([]byte(second))
+			return
+		}
+		w.Write([]byte(first))
+	}
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, EC2Service) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, EC2Service{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := ec2RetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := ec2RetryPredicate(500, []byte("<Response><Errors><Error><Code>InternalError</Code></Error></Errors></Response>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"RequestLimitExceeded\" code", t, func() {
+		result, _ := ec2RetryPredicate(503, []byte("<Response><Errors><Error><Code>RequestLimitExceeded</Code></Error></Errors></Response>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says an instance does not exist", t, func() {
+		result, err := ec2RetryPredicate(400, testInvalidInstanceIDError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, invalidInstanceIDError)
+		})
+	})
+}
+
+func TestSetFilters(t *testing.T) {
+	Convey("Given filters", t, func() {
+		params := url.Values{}
+		setFilters(params, []Filter{TagFilter("role", "worker", "web"), StateFilter(Running), TagKeyFilter("stream")})
+
+		Convey("They are numbered parameters", func() {
+			So(params, ShouldResemble, url.Values{
+				"Filter.1.Name":    {"tag:role"},
+				"Filter.1.Value.1": {"worker"},
+				"Filter.1.Value.2": {"web"},
+				"Filter.2.Name":    {"instance-state-name"},
+				"Filter.2.Value.1": {"running"},
+				"Filter.3.Name":    {"tag-key"},
+				"Filter.3.Value.1": {"stream"},
+			})
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("ec2", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
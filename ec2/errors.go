@@ -0,0 +1,22 @@
+// Code generated by errgen from the go:generate line in ec2.go. DO NOT EDIT.
+
+package ec2
+
+// These are errors EC2 returns. errors.Is(err, ec2.ErrInvalidInstanceIDNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrInvalidInstanceIDNotFound    = ec2Error{Code: "InvalidInstanceID.NotFound"}
+	ErrInvalidInstanceIDMalformed   = ec2Error{Code: "InvalidInstanceID.Malformed"}
+	ErrInvalidGroupNotFound         = ec2Error{Code: "InvalidGroup.NotFound"}
+	ErrInvalidParameterValue        = ec2Error{Code: "InvalidParameterValue"}
+	ErrIncorrectInstanceState       = ec2Error{Code: "IncorrectInstanceState"}
+	ErrInsufficientInstanceCapacity = ec2Error{Code: "InsufficientInstanceCapacity"}
+	ErrInstanceLimitExceeded        = ec2Error{Code: "InstanceLimitExceeded"}
+	ErrUnauthorizedOperation        = ec2Error{Code: "UnauthorizedOperation"}
+	ErrDryRunOperation              = ec2Error{Code: "DryRunOperation"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e ec2Error) Is(target error) bool {
+	t, ok := target.(ec2Error)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,106 @@
+package ec2
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// The states of an instance.
+const (
+	Pending      = "pending"
+	Running      = "running"
+	ShuttingDown = "shutting-down"
+	Terminated   = "terminated"
+	Stopping     = "stopping"
+	Stopped      = "stopped"
+)
+
+// StateFilter returns a filter for instances in one of the states, like Running.
+func StateFilter(states ...string) Filter {
+	return Filter{Name: "instance-state-name", Values: states}
+}
+
+// InstanceState is the state an instance is in.
+type InstanceState struct {
+	Code int    `xml:"code"`
+	Name string `xml:"name"` // One of Pending, Running, ShuttingDown, Terminated, Stopping or Stopped
+}
+
+// Instance is an EC2 instance.
+type Instance struct {
+	InstanceId       string        `xml:"instanceId"`
+	ImageId          string        `xml:"imageId"`
+	InstanceType     string        `xml:"instanceType"`
+	State            InstanceState `xml:"instanceState"`
+	PrivateDnsName   string        `xml:"privateDnsName"`
+	PublicDnsName    string        `xml:"dnsName"`
+	PrivateIpAddress string        `xml:"privateIpAddress"`
+	PublicIpAddress  string        `xml:"ipAddress"` // Empty if the instance has no public IP address
+	KeyName          string        `xml:"keyName"`
+	LaunchTime       time.Time     `xml:"launchTime"`
+	AvailabilityZone string        `xml:"placement>availabilityZone"`
+	VpcId            string        `xml:"vpcId"`
+	SubnetId         string        `xml:"subnetId"`
+	ReservationId    string        `xml:"-"`
+	Tags             []Tag         `xml:"tagSet>item"`
+}
+
+// Tag returns the value of a tag on the instance, or "" if it does not have the tag.
+func (i Instance) Tag(key string) string {
+	for _, tag := range i.Tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+type reservation struct {
+	ReservationId string     `xml:"reservationId"`
+	Instances     []Instance `xml:"instancesSet>item"`
+}
+
+type describeInstancesResponse struct {
+	Reservations []reservation `xml:"reservationSet>item"`
+	NextToken    string        `xml:"nextToken"`
+}
+
+// DescribeInstances describes the instances with the IDs that match every filter. If there are no IDs, every instance that matches is described.
+// It is calling the DescribeInstances API call until there are no more pages.
+// See http://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeInstances.html for more details.
+func (s *EC2Service) DescribeInstances(ids []string, filters ...Filter) ([]Instance, error) {
+	instances := []Instance{}
+
+	params := query("DescribeInstances")
+	setList(params, "InstanceId", ids)
+	setFilters(params, filters)
+
+	for {
+		result := describeInstancesResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Instance{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Instance{}, err
+		}
+
+		for _, r := range result.Reservations {
+			for _, instance := range r.Instances {
+				instance.ReservationId = r.ReservationId
+				instances = append(instances, instance)
+			}
+		}
+
+		if result.NextToken == "" {
+			return instances, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
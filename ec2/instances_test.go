@@ -0,0 +1,139 @@
+package ec2
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testDescribeInstancesPage1 = `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2015-10-01/">
+  <requestId>8f7724cf-496f-496e-8fe3-example</requestId>
+  <reservationSet>
+    <item>
+      <reservationId>r-1234567890abcdef0</reservationId>
+      <instancesSet>
+        <item>
+          <instanceId>i-1234567890abcdef0</instanceId>
+          <imageId>ami-bff32ccc</imageId>
+          <instanceState>
+            <code>16</code>
+            <name>running</name>
+          </instanceState>
+          <privateDnsName>ip-192-168-1-88.eu-west-1.compute.internal</privateDnsName>
+          <dnsName>ec2-54-194-252-215.eu-west-1.compute.amazonaws.com</dnsName>
+          <keyName>my_keypair</keyName>
+          <instanceType>t2.micro</instanceType>
+          <launchTime>2015-12-22T10:44:05.000Z</launchTime>
+          <placement>
+            <availabilityZone>eu-west-1c</availabilityZone>
+            <tenancy>default</tenancy>
+          </placement>
+          <subnetId>subnet-56f5f633</subnetId>
+          <vpcId>vpc-11112222</vpcId>
+          <privateIpAddress>192.168.1.88</privateIpAddress>
+          <ipAddress>54.194.252.215</ipAddress>
+          <tagSet>
+            <item>
+              <key>role</key>
+              <value>worker</value>
+            </item>
+          </tagSet>
+        </item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+  <nextToken>page2</nextToken>
+</DescribeInstancesResponse>`
+
+const testDescribeInstancesPage2 = `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2015-10-01/">
+  <reservationSet>
+    <item>
+      <reservationId>r-0abcdef1234567890</reservationId>
+      <instancesSet>
+        <item><instanceId>i-0abcdef1234567890</instanceId><instanceState><code>80</code><name>stopped</name></instanceState></item>
+        <item><instanceId>i-0abcdef1234567891</instanceId><instanceState><code>80</code><name>stopped</name></instanceState></item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+</DescribeInstancesResponse>`
+
+func TestDescribeInstances(t *testing.T) {
+	Convey("Given instances that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testDescribeInstancesPage1, testDescribeInstancesPage2), &params)
+		defer ts.Close()
+
+		instances, err := s.DescribeInstances(nil, TagFilter("role", "worker"))
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(instances), ShouldEqual, 3)
+			So(instances[2].InstanceId, ShouldEqual, "i-0abcdef1234567891")
+			So(instances[2].ReservationId, ShouldEqual, "r-0abcdef1234567890")
+			So(instances[2].State, ShouldResemble, InstanceState{Code: 80, Name: Stopped})
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+		Convey("Instances are decoded", func() {
+			So(instances[0], ShouldResemble, Instance{
+				InstanceId:       "i-1234567890abcdef0",
+				ImageId:          "ami-bff32ccc",
+				InstanceType:     "t2.micro",
+				State:            InstanceState{Code: 16, Name: Running},
+				PrivateDnsName:   "ip-192-168-1-88.eu-west-1.compute.internal",
+				PublicDnsName:    "ec2-54-194-252-215.eu-west-1.compute.amazonaws.com",
+				PrivateIpAddress: "192.168.1.88",
+				PublicIpAddress:  "54.194.252.215",
+				KeyName:          "my_keypair",
+				LaunchTime:       time.Date(2015, 12, 22, 10, 44, 5, 0, time.UTC),
+				AvailabilityZone: "eu-west-1c",
+				VpcId:            "vpc-11112222",
+				SubnetId:         "subnet-56f5f633",
+				ReservationId:    "r-1234567890abcdef0",
+				Tags:             []Tag{{Key: "role", Value: "worker"}},
+			})
+		})
+		Convey("Tags can be looked up", func() {
+			So(instances[0].Tag("role"), ShouldEqual, "worker")
+			So(instances[0].Tag("name"), ShouldEqual, "")
+		})
+		Convey("The filters are sent", func() {
+			So(params[0].Get("Action"), ShouldEqual, "DescribeInstances")
+			So(params[0].Get("Filter.1.Name"), ShouldEqual, "tag:role")
+			So(params[0].Get("Filter.1.Value.1"), ShouldEqual, "worker")
+		})
+	})
+	Convey("Given instance IDs", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testDescribeInstancesPage2, ""), &params)
+		defer ts.Close()
+
+		s.DescribeInstances([]string{"i-0abcdef1234567890", "i-0abcdef1234567891"})
+
+		Convey("They are sent", func() {
+			So(params[0].Get("InstanceId.1"), ShouldEqual, "i-0abcdef1234567890")
+			So(params[0].Get("InstanceId.2"), ShouldEqual, "i-0abcdef1234567891")
+		})
+	})
+	Convey("Given an instance that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeInstances([]string{"i-1a2b3c4d"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidInstanceIDError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeInstances(nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
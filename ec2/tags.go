@@ -0,0 +1,99 @@
+package ec2
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+func setTags(params url.Values, tags []Tag, values bool) {
+	for i, tag := range tags {
+		prefix := fmt.Sprintf("Tag.%d.", i+1)
+		params.Set(prefix+"Key", tag.Key)
+		if values || tag.Value != "" {
+			params.Set(prefix+"Value", tag.Value)
+		}
+	}
+}
+
+// CreateTags adds the tags to every resource, like instances, volumes and security groups, replacing the value of any tag that is already there.
+// It is calling the CreateTags API call.
+// See http://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_CreateTags.html for more details.
+func (s *EC2Service) CreateTags(resourceIds []string, tags ...Tag) error {
+	params := query("CreateTags")
+	setList(params, "ResourceId", resourceIds)
+	setTags(params, tags, true)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
+
+// DeleteTags removes the tags from every resource. A tag with an empty value is removed whatever its value is, and one with a value is only removed if it has that value.
+// It is calling the DeleteTags API call.
+// See http://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DeleteTags.html for more details.
+func (s *EC2Service) DeleteTags(resourceIds []string, tags ...Tag) error {
+	params := query("DeleteTags")
+	setList(params, "ResourceId", resourceIds)
+	setTags(params, tags, false)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
+
+// ResourceFilter returns a filter for the tags of the resources, for DescribeTags.
+func ResourceFilter(resourceIds ...string) Filter {
+	return Filter{Name: "resource-id", Values: resourceIds}
+}
+
+// TagDescription is a tag on a resource.
+type TagDescription struct {
+	ResourceId   string `xml:"resourceId"`
+	ResourceType string `xml:"resourceType"` // Like instance, volume or security-group
+	Key          string `xml:"key"`
+	Value        string `xml:"value"`
+}
+
+type describeTagsResponse struct {
+	Tags      []TagDescription `xml:"tagSet>item"`
+	NextToken string           `xml:"nextToken"`
+}
+
+// DescribeTags describes the tags that match every filter, like ResourceFilter("i-1234567890abcdef0"). If there are no filters, every tag is described.
+// It is calling the DescribeTags API call until there are no more pages.
+// See http://docs.aws.amazon.com/AWSEC2/latest/APIReference/API_DescribeTags.html for more details.
+func (s *EC2Service) DescribeTags(filters ...Filter) ([]TagDescription, error) {
+	tags := []TagDescription{}
+
+	params := query("DescribeTags")
+	setFilters(params, filters)
+
+	for {
+		result := describeTagsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []TagDescription{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []TagDescription{}, err
+		}
+
+		tags = append(tags, result.Tags...)
+
+		if result.NextToken == "" {
+			return tags, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
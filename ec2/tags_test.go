@@ -0,0 +1,98 @@
+package ec2
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateTags(t *testing.T) {
+	Convey("Given resources and tags", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.CreateTags([]string{"i-1234567890abcdef0", "vol-1234567890abcdef0"}, Tag{Key: "role", Value: "worker"}, Tag{Key: "empty"})
+
+		Convey("Every resource is tagged", func() {
+			So(err, ShouldBeNil)
+			So(params[0], ShouldResemble, url.Values{
+				"Action":       {"CreateTags"},
+				"Version":      {"2015-10-01"},
+				"ResourceId.1": {"i-1234567890abcdef0"},
+				"ResourceId.2": {"vol-1234567890abcdef0"},
+				"Tag.1.Key":    {"role"},
+				"Tag.1.Value":  {"worker"},
+				"Tag.2.Key":    {"empty"},
+				"Tag.2.Value":  {""},
+			})
+		})
+	})
+	Convey("Given a resource that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		err := s.CreateTags([]string{"i-1a2b3c4d"}, Tag{Key: "role", Value: "worker"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidInstanceIDError)
+		})
+	})
+}
+
+func TestDeleteTags(t *testing.T) {
+	Convey("Given tags with and without values", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.DeleteTags([]string{"i-1234567890abcdef0"}, Tag{Key: "role", Value: "worker"}, Tag{Key: "stream"})
+
+		Convey("Values are only sent when they are set", func() {
+			So(err, ShouldBeNil)
+			So(params[0], ShouldResemble, url.Values{
+				"Action":       {"DeleteTags"},
+				"Version":      {"2015-10-01"},
+				"ResourceId.1": {"i-1234567890abcdef0"},
+				"Tag.1.Key":    {"role"},
+				"Tag.1.Value":  {"worker"},
+				"Tag.2.Key":    {"stream"},
+			})
+		})
+	})
+}
+
+func TestDescribeTags(t *testing.T) {
+	Convey("Given tags that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<DescribeTagsResponse><tagSet><item><resourceId>i-1234567890abcdef0</resourceId><resourceType>instance</resourceType><key>role</key><value>worker</value></item></tagSet><nextToken>page2</nextToken></DescribeTagsResponse>`,
+			`<DescribeTagsResponse><tagSet><item><resourceId>i-1234567890abcdef0</resourceId><resourceType>instance</resourceType><key>stream</key><value>clicks</value></item></tagSet></DescribeTagsResponse>`,
+		), &params)
+		defer ts.Close()
+
+		tags, err := s.DescribeTags(ResourceFilter("i-1234567890abcdef0"))
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(tags, ShouldResemble, []TagDescription{
+				{ResourceId: "i-1234567890abcdef0", ResourceType: "instance", Key: "role", Value: "worker"},
+				{ResourceId: "i-1234567890abcdef0", ResourceType: "instance", Key: "stream", Value: "clicks"},
+			})
+			So(params[0].Get("Filter.1.Name"), ShouldEqual, "resource-id")
+			So(params[0].Get("Filter.1.Value.1"), ShouldEqual, "i-1234567890abcdef0")
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeTags()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,96 @@
+// Package ecs provides a way to interact with the AWS EC2 Container Service.
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type ecsError -field Type -service ECS ClusterNotFoundException ServiceNotFoundException ServiceNotActiveException InvalidParameterException AccessDeniedException ClientException ServerException
+
+// ecsError is the error document returned from the ECS service.
+type ecsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the ecsError into an error message.
+func (e ecsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e ecsError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func ecsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := ecsError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// ECSService is the ECS service at AWS. The endpoint for a region is gaws.Endpoint("ecs", region).
+type ECSService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// ECSAPI is implemented by *ECSService. Code that takes an ECSAPI can be given a fake in tests instead.
+type ECSAPI interface {
+	DescribeTasks(cluster string, tasks ...string) ([]Task, []Failure, error)
+	RegisterTaskDefinition(family string, containers ...ContainerDefinition) (TaskDefinition, error)
+	RunTask(cluster string, taskDefinition string, count int, startedBy string) ([]Task, []Failure, error)
+	SetDesiredCount(cluster string, service string, count int) (Service, error)
+	UpdateService(update ServiceUpdate) (Service, error)
+}
+
+var _ ECSAPI = (*ECSService)(nil)
+
+// New returns an ECSService with the options applied. Its endpoint is gaws.Endpoint("ecs", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *ECSService {
+	config := gaws.NewConfig(options...)
+	return &ECSService{Endpoint: config.ServiceEndpoint("ecs"), config: config}
+}
+
+func (s *ECSService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: ecsRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("ecs", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Failure is why ECS could not do something with a resource, like MISSING when a task does not exist.
+type Failure struct {
+	Arn    string `json:"arn"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,22 @@
+// Code generated by errgen from the go:generate line in ecs.go. DO NOT EDIT.
+
+package ecs
+
+import "strings"
+
+// These are errors ECS returns. errors.Is(err, ecs.ErrClusterNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrClusterNotFound  = ecsError{Type: "ClusterNotFoundException"}
+	ErrServiceNotFound  = ecsError{Type: "ServiceNotFoundException"}
+	ErrServiceNotActive = ecsError{Type: "ServiceNotActiveException"}
+	ErrInvalidParameter = ecsError{Type: "InvalidParameterException"}
+	ErrAccessDenied     = ecsError{Type: "AccessDeniedException"}
+	ErrClient           = ecsError{Type: "ClientException"}
+	ErrServer           = ecsError{Type: "ServerException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e ecsError) Is(target error) bool {
+	t, ok := target.(ecsError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
@@ -0,0 +1,60 @@
+package ecs
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// Service keeps a number of copies of a task definition running on a cluster.
+type Service struct {
+	ServiceArn     string `json:"serviceArn"`
+	ServiceName    string `json:"serviceName"`
+	ClusterArn     string `json:"clusterArn"`
+	TaskDefinition string `json:"taskDefinition"`
+	Status         string `json:"status"` // ACTIVE, DRAINING or INACTIVE
+	DesiredCount   int    `json:"desiredCount"`
+	RunningCount   int    `json:"runningCount"`
+	PendingCount   int    `json:"pendingCount"`
+}
+
+// ServiceUpdate is how to change a service.
+type ServiceUpdate struct {
+	Cluster        string `json:"cluster,omitempty"` // If it is empty, the default cluster is used
+	Service        string `json:"service"`
+	DesiredCount   *int   `json:"desiredCount,omitempty"`   // If it is nil, the count is not changed
+	TaskDefinition string `json:"taskDefinition,omitempty"` // The revision to deploy, like consumer:4. If it is empty, it is not changed.
+}
+
+type updateServiceResult struct {
+	Service Service `json:"service"`
+}
+
+// UpdateService changes how many tasks a service runs, or deploys a new revision of its task definition, replacing its tasks.
+// It returns the service. It is calling the UpdateService API call.
+// See http://docs.aws.amazon.com/AmazonECS/latest/APIReference/API_UpdateService.html for more details.
+func (s *ECSService) UpdateService(update ServiceUpdate) (Service, error) {
+	result := updateServiceResult{}
+
+	bodyAsJson, err := json.Marshal(update)
+	if err != nil {
+		return result.Service, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AmazonEC2ContainerServiceV20141113.UpdateService"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Service, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Service, err
+}
+
+// SetDesiredCount changes how many tasks a service runs, to scale it up or down.
+func (s *ECSService) SetDesiredCount(cluster string, service string, count int) (Service, error) {
+	return s.UpdateService(ServiceUpdate{Cluster: cluster, Service: service, DesiredCount: &count})
+}
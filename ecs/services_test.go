@@ -0,0 +1,65 @@
+package ecs
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testUpdatedService = `{"service":{"serviceArn":"arn:aws:ecs:us-east-1:123456789012:service/consumers","serviceName":"consumers",
+	"taskDefinition":"arn:aws:ecs:us-east-1:123456789012:task-definition/consumer:4","status":"ACTIVE","desiredCount":6,"runningCount":4,"pendingCount":2}}`
+
+func TestUpdateService(t *testing.T) {
+	Convey("Given a new revision to deploy", t, func() {
+		ts, s, requests := testService(testJson(testUpdatedService))
+		defer ts.Close()
+
+		service, err := s.UpdateService(ServiceUpdate{Cluster: "streams", Service: "consumers", TaskDefinition: "consumer:4"})
+
+		Convey("It returns the service", func() {
+			So(err, ShouldBeNil)
+			So(service.ServiceName, ShouldEqual, "consumers")
+			So(service.DesiredCount, ShouldEqual, 6)
+			So(service.RunningCount, ShouldEqual, 4)
+			So(service.PendingCount, ShouldEqual, 2)
+		})
+		Convey("It does not change the count", func() {
+			So(requests()[0].Target, ShouldEqual, "AmazonEC2ContainerServiceV20141113.UpdateService")
+			So(string(requests()[0].Body), ShouldEqual, `{"cluster":"streams","service":"consumers","taskDefinition":"consumer:4"}`)
+		})
+	})
+	Convey("Given a service that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.UpdateService(ServiceUpdate{Service: "consumers"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, serviceNotFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.UpdateService(ServiceUpdate{Service: "consumers"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSetDesiredCount(t *testing.T) {
+	Convey("Given a count", t, func() {
+		ts, s, requests := testService(testJson(testUpdatedService))
+		defer ts.Close()
+
+		_, err := s.SetDesiredCount("streams", "consumers", 0)
+
+		Convey("It is sent, even if it is 0", func() {
+			So(err, ShouldBeNil)
+			So(string(requests()[0].Body), ShouldEqual, `{"cluster":"streams","service":"consumers","desiredCount":0}`)
+		})
+	})
+}
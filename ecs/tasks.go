@@ -0,0 +1,180 @@
+package ecs
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The statuses a task can have.
+const (
+	Pending = "PENDING"
+	Running = "RUNNING"
+	Stopped = "STOPPED"
+)
+
+// KeyValuePair is a name and value, like an environment variable.
+type KeyValuePair struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PortMapping maps a port of a container to a port of the instance it runs on.
+type PortMapping struct {
+	ContainerPort int    `json:"containerPort"`
+	HostPort      int    `json:"hostPort,omitempty"` // If it is 0, the instance picks a port
+	Protocol      string `json:"protocol,omitempty"` // tcp or udp. If it is empty, tcp is used.
+}
+
+// ContainerDefinition describes a container in a task.
+type ContainerDefinition struct {
+	Name         string         `json:"name"`
+	Image        string         `json:"image"`               // Like amazon/amazon-ecs-sample, or a repository URL and tag
+	Cpu          int            `json:"cpu,omitempty"`       // CPU units, where a core has 1024
+	Memory       int            `json:"memory,omitempty"`    // Megabytes
+	Essential    *bool          `json:"essential,omitempty"` // Whether the task stops if the container stops. If it is nil, it is essential.
+	Command      []string       `json:"command,omitempty"`
+	Environment  []KeyValuePair `json:"environment,omitempty"`
+	PortMappings []PortMapping  `json:"portMappings,omitempty"`
+}
+
+// TaskDefinition is a registered revision of a task, which can be run or used by services.
+type TaskDefinition struct {
+	TaskDefinitionArn    string                `json:"taskDefinitionArn"`
+	Family               string                `json:"family"`
+	Revision             int                   `json:"revision"`
+	Status               string                `json:"status"` // ACTIVE or INACTIVE
+	ContainerDefinitions []ContainerDefinition `json:"containerDefinitions"`
+}
+
+type registerTaskDefinitionRequest struct {
+	Family               string                `json:"family"`
+	ContainerDefinitions []ContainerDefinition `json:"containerDefinitions"`
+}
+
+type registerTaskDefinitionResult struct {
+	TaskDefinition TaskDefinition `json:"taskDefinition"`
+}
+
+// RegisterTaskDefinition registers a new revision of the family, like consumer, with the containers. It returns the task definition,
+// whose ARN names the revision. It is calling the RegisterTaskDefinition API call.
+// See http://docs.aws.amazon.com/AmazonECS/latest/APIReference/API_RegisterTaskDefinition.html for more details.
+func (s *ECSService) RegisterTaskDefinition(family string, containers ...ContainerDefinition) (TaskDefinition, error) {
+	result := registerTaskDefinitionResult{}
+
+	bodyAsJson, err := json.Marshal(registerTaskDefinitionRequest{Family: family, ContainerDefinitions: containers})
+	if err != nil {
+		return result.TaskDefinition, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AmazonEC2ContainerServiceV20141113.RegisterTaskDefinition"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.TaskDefinition, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.TaskDefinition, err
+}
+
+// Container is a container of a task.
+type Container struct {
+	ContainerArn string `json:"containerArn"`
+	Name         string `json:"name"`
+	LastStatus   string `json:"lastStatus"`
+	ExitCode     *int   `json:"exitCode"` // Nil until the container stops
+	Reason       string `json:"reason"`
+}
+
+// Task is a task that was run.
+type Task struct {
+	TaskArn              string      `json:"taskArn"`
+	ClusterArn           string      `json:"clusterArn"`
+	TaskDefinitionArn    string      `json:"taskDefinitionArn"`
+	ContainerInstanceArn string      `json:"containerInstanceArn"`
+	LastStatus           string      `json:"lastStatus"` // Pending, Running or Stopped
+	DesiredStatus        string      `json:"desiredStatus"`
+	StartedBy            string      `json:"startedBy"`
+	StoppedReason        string      `json:"stoppedReason"`
+	Containers           []Container `json:"containers"`
+	CreatedAt            float64     `json:"createdAt"` // Seconds since the epoch
+	StartedAt            float64     `json:"startedAt"`
+	StoppedAt            float64     `json:"stoppedAt"`
+}
+
+type runTaskRequest struct {
+	Cluster        string `json:"cluster,omitempty"`
+	TaskDefinition string `json:"taskDefinition"`
+	Count          int    `json:"count"`
+	StartedBy      string `json:"startedBy,omitempty"`
+	ClientToken    string `json:"clientToken"`
+}
+
+// tasksResult is the result of API calls that return tasks, like RunTask.
+type tasksResult struct {
+	Tasks    []Task    `json:"tasks"`
+	Failures []Failure `json:"failures"`
+}
+
+// RunTask runs count copies of the task definition, like consumer:3, or the latest revision of a family, like consumer, on the cluster.
+// If cluster is empty, the default cluster is used. startedBy says who started them, and can be used to find them later.
+// It returns the tasks that were started, and why the others were not. It is calling the RunTask API call.
+// It sends a new client token, so that retries of the request do not start the tasks again.
+// See http://docs.aws.amazon.com/AmazonECS/latest/APIReference/API_RunTask.html for more details.
+func (s *ECSService) RunTask(cluster string, taskDefinition string, count int, startedBy string) ([]Task, []Failure, error) {
+	result := tasksResult{}
+
+	token, err := gaws.NewIdempotencyToken()
+	if err != nil {
+		return result.Tasks, result.Failures, err
+	}
+
+	bodyAsJson, err := json.Marshal(runTaskRequest{Cluster: cluster, TaskDefinition: taskDefinition, Count: count, StartedBy: startedBy, ClientToken: token})
+	if err != nil {
+		return result.Tasks, result.Failures, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AmazonEC2ContainerServiceV20141113.RunTask"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Tasks, result.Failures, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Tasks, result.Failures, err
+}
+
+type describeTasksRequest struct {
+	Cluster string   `json:"cluster,omitempty"`
+	Tasks   []string `json:"tasks"`
+}
+
+// DescribeTasks describes tasks on the cluster, by ID or ARN. If cluster is empty, the default cluster is used.
+// Tasks that do not exist are returned as failures. It is calling the DescribeTasks API call.
+// See http://docs.aws.amazon.com/AmazonECS/latest/APIReference/API_DescribeTasks.html for more details.
+func (s *ECSService) DescribeTasks(cluster string, tasks ...string) ([]Task, []Failure, error) {
+	result := tasksResult{}
+
+	bodyAsJson, err := json.Marshal(describeTasksRequest{Cluster: cluster, Tasks: tasks})
+	if err != nil {
+		return result.Tasks, result.Failures, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "AmazonEC2ContainerServiceV20141113.DescribeTasks"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Tasks, result.Failures, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Tasks, result.Failures, err
+}
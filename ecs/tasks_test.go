@@ -0,0 +1,129 @@
+package ecs
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRegisterTaskDefinition(t *testing.T) {
+	Convey("Given containers", t, func() {
+		ts, s, requests := testService(testJson(`{"taskDefinition":{"taskDefinitionArn":"arn:aws:ecs:us-east-1:123456789012:task-definition/consumer:3",
+			"family":"consumer","revision":3,"status":"ACTIVE","containerDefinitions":[{"name":"consumer","image":"example/consumer:1.2","memory":256}]}}`))
+		defer ts.Close()
+
+		notEssential := false
+		definition, err := s.RegisterTaskDefinition("consumer",
+			ContainerDefinition{Name: "consumer", Image: "example/consumer:1.2", Memory: 256,
+				Environment: []KeyValuePair{{Name: "STREAM", Value: "payments"}}, PortMappings: []PortMapping{{ContainerPort: 8080}}},
+			ContainerDefinition{Name: "logs", Image: "example/logs", Memory: 64, Essential: &notEssential},
+		)
+
+		Convey("It returns the revision", func() {
+			So(err, ShouldBeNil)
+			So(definition.TaskDefinitionArn, ShouldEqual, "arn:aws:ecs:us-east-1:123456789012:task-definition/consumer:3")
+			So(definition.Revision, ShouldEqual, 3)
+			So(definition.ContainerDefinitions[0].Memory, ShouldEqual, 256)
+		})
+		Convey("It sends the containers", func() {
+			So(requests()[0].Target, ShouldEqual, "AmazonEC2ContainerServiceV20141113.RegisterTaskDefinition")
+			So(string(requests()[0].Body), ShouldEqual, `{"family":"consumer","containerDefinitions":[`+
+				`{"name":"consumer","image":"example/consumer:1.2","memory":256,"environment":[{"name":"STREAM","value":"payments"}],"portMappings":[{"containerPort":8080}]},`+
+				`{"name":"logs","image":"example/logs","memory":64,"essential":false}]}`)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.RegisterTaskDefinition("consumer")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, serviceNotFoundError)
+		})
+	})
+}
+
+func TestRunTask(t *testing.T) {
+	Convey("Given a task definition to run twice where one cannot be placed", t, func() {
+		ts, s, requests := testService(testJson(`{"tasks":[{"taskArn":"arn:aws:ecs:us-east-1:123456789012:task/1","lastStatus":"PENDING",
+			"desiredStatus":"RUNNING","startedBy":"backfill","containers":[{"name":"consumer","lastStatus":"PENDING"}],"createdAt":1438000000}],
+			"failures":[{"arn":"arn:aws:ecs:us-east-1:123456789012:container-instance/2","reason":"RESOURCE:MEMORY"}]}`))
+		defer ts.Close()
+
+		tasks, failures, err := s.RunTask("streams", "consumer:3", 2, "backfill")
+
+		Convey("It returns the task that was started and why the other was not", func() {
+			So(err, ShouldBeNil)
+			So(len(tasks), ShouldEqual, 1)
+			So(tasks[0].LastStatus, ShouldEqual, Pending)
+			So(tasks[0].Containers[0].ExitCode, ShouldBeNil)
+			So(failures, ShouldResemble, []Failure{{Arn: "arn:aws:ecs:us-east-1:123456789012:container-instance/2", Reason: "RESOURCE:MEMORY"}})
+		})
+		Convey("It sends the task definition", func() {
+			So(requests()[0].Target, ShouldEqual, "AmazonEC2ContainerServiceV20141113.RunTask")
+			body := runTaskRequest{}
+			So(json.Unmarshal(requests()[0].Body, &body), ShouldBeNil)
+			So(len(body.ClientToken), ShouldEqual, 36)
+			So(string(requests()[0].Body), ShouldEqual, `{"cluster":"streams","taskDefinition":"consumer:3","count":2,"startedBy":"backfill","clientToken":"`+body.ClientToken+`"}`)
+		})
+	})
+	Convey("Given the default cluster", t, func() {
+		ts, s, requests := testService(testJson(`{"tasks":[],"failures":[]}`))
+		defer ts.Close()
+
+		s.RunTask("", "consumer", 1, "")
+
+		Convey("No cluster is sent", func() {
+			body := runTaskRequest{}
+			So(json.Unmarshal(requests()[0].Body, &body), ShouldBeNil)
+			So(string(requests()[0].Body), ShouldEqual, `{"taskDefinition":"consumer","count":1,"clientToken":"`+body.ClientToken+`"}`)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, _, err := s.RunTask("", "consumer", 1, "")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDescribeTasks(t *testing.T) {
+	Convey("Given a stopped task and one that does not exist", t, func() {
+		ts, s, requests := testService(testJson(`{"tasks":[{"taskArn":"arn:aws:ecs:us-east-1:123456789012:task/1","lastStatus":"STOPPED",
+			"stoppedReason":"Essential container in task exited","containers":[{"name":"consumer","lastStatus":"STOPPED","exitCode":1}]}],
+			"failures":[{"arn":"arn:aws:ecs:us-east-1:123456789012:task/2","reason":"MISSING"}]}`))
+		defer ts.Close()
+
+		tasks, failures, err := s.DescribeTasks("streams", "1", "2")
+
+		Convey("It describes the task", func() {
+			So(err, ShouldBeNil)
+			So(tasks[0].LastStatus, ShouldEqual, Stopped)
+			So(tasks[0].StoppedReason, ShouldEqual, "Essential container in task exited")
+			So(*tasks[0].Containers[0].ExitCode, ShouldEqual, 1)
+		})
+		Convey("It returns the one that does not exist as a failure", func() {
+			So(failures[0].Reason, ShouldEqual, "MISSING")
+		})
+		Convey("It asks for the tasks", func() {
+			So(requests()[0].Target, ShouldEqual, "AmazonEC2ContainerServiceV20141113.DescribeTasks")
+			So(string(requests()[0].Body), ShouldEqual, `{"cluster":"streams","tasks":["1","2"]}`)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, _, err := s.DescribeTasks("", "1")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, serviceNotFoundError)
+		})
+	})
+}
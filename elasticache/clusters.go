@@ -0,0 +1,103 @@
+package elasticache
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// CacheNode is a node in a cache cluster.
+type CacheNode struct {
+	CacheNodeId              string
+	CacheNodeStatus          string
+	CacheNodeCreateTime      time.Time
+	Endpoint                 Endpoint
+	CustomerAvailabilityZone string
+}
+
+// CacheCluster is an ElastiCache cluster.
+type CacheCluster struct {
+	CacheClusterId            string
+	CacheClusterStatus        string // Like available or modifying
+	Engine                    string // memcached or redis
+	EngineVersion             string
+	CacheNodeType             string
+	NumCacheNodes             int
+	PreferredAvailabilityZone string
+	CacheClusterCreateTime    time.Time
+	ConfigurationEndpoint     *Endpoint   // The endpoint memcached clients discover the nodes from. Redis clusters do not have one.
+	CacheNodes                []CacheNode `xml:"CacheNodes>CacheNode"` // Only described if showNodes is true
+	ReplicationGroupId        string      // The replication group the cluster is a member of, if any
+}
+
+// Endpoints returns the endpoints to connect to the cluster on. A memcached cluster has its configuration endpoint,
+// and other clusters have the endpoints of their nodes, if they were described.
+func (c CacheCluster) Endpoints() []Endpoint {
+	if c.ConfigurationEndpoint != nil {
+		return []Endpoint{*c.ConfigurationEndpoint}
+	}
+
+	endpoints := []Endpoint{}
+	for _, node := range c.CacheNodes {
+		endpoints = append(endpoints, node.Endpoint)
+	}
+	return endpoints
+}
+
+type describeCacheClustersResponse struct {
+	CacheClusters []CacheCluster `xml:"DescribeCacheClustersResult>CacheClusters>CacheCluster"`
+	Marker        string         `xml:"DescribeCacheClustersResult>Marker"`
+}
+
+// DescribeCacheClusters describes a cache cluster. If id is empty, every cluster is described.
+// If showNodes is true, the nodes of the clusters and their endpoints are described too.
+// It is calling the DescribeCacheClusters API call until there are no more pages.
+// See http://docs.aws.amazon.com/AmazonElastiCache/latest/APIReference/API_DescribeCacheClusters.html for more details.
+func (s *ElastiCacheService) DescribeCacheClusters(id string, showNodes bool) ([]CacheCluster, error) {
+	clusters := []CacheCluster{}
+
+	params := query("DescribeCacheClusters")
+	if id != "" {
+		params.Set("CacheClusterId", id)
+	}
+	if showNodes {
+		params.Set("ShowCacheNodeInfo", "true")
+	}
+
+	for {
+		result := describeCacheClustersResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []CacheCluster{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []CacheCluster{}, err
+		}
+
+		clusters = append(clusters, result.CacheClusters...)
+
+		if result.Marker == "" {
+			return clusters, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
+
+// ClusterEndpoints returns the endpoints to connect to a cache cluster on, so applications can find their cache when they start.
+func (s *ElastiCacheService) ClusterEndpoints(id string) ([]Endpoint, error) {
+	clusters, err := s.DescribeCacheClusters(id, true)
+	if err != nil {
+		return []Endpoint{}, err
+	}
+
+	endpoints := []Endpoint{}
+	for _, cluster := range clusters {
+		endpoints = append(endpoints, cluster.Endpoints()...)
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,171 @@
+package elasticache
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testMemcachedCluster = `<DescribeCacheClustersResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <DescribeCacheClustersResult>
+    <CacheClusters>
+      <CacheCluster>
+        <CacheClusterId>sessions</CacheClusterId>
+        <CacheClusterStatus>available</CacheClusterStatus>
+        <Engine>memcached</Engine>
+        <EngineVersion>1.4.24</EngineVersion>
+        <CacheNodeType>cache.m3.medium</CacheNodeType>
+        <NumCacheNodes>1</NumCacheNodes>
+        <PreferredAvailabilityZone>us-east-1a</PreferredAvailabilityZone>
+        <CacheClusterCreateTime>2015-02-02T01:21:46.607Z</CacheClusterCreateTime>
+        <ConfigurationEndpoint>
+          <Address>sessions.abc123.cfg.use1.cache.amazonaws.com</Address>
+          <Port>11211</Port>
+        </ConfigurationEndpoint>
+        <CacheNodes>
+          <CacheNode>
+            <CacheNodeId>0001</CacheNodeId>
+            <CacheNodeStatus>available</CacheNodeStatus>
+            <CacheNodeCreateTime>2015-02-02T01:21:46.607Z</CacheNodeCreateTime>
+            <Endpoint>
+              <Address>sessions.abc123.0001.use1.cache.amazonaws.com</Address>
+              <Port>11211</Port>
+            </Endpoint>
+            <CustomerAvailabilityZone>us-east-1a</CustomerAvailabilityZone>
+          </CacheNode>
+        </CacheNodes>
+      </CacheCluster>
+    </CacheClusters>
+    <Marker>page2</Marker>
+  </DescribeCacheClustersResult>
+</DescribeCacheClustersResponse>`
+
+const testRedisClusters = `<DescribeCacheClustersResponse>
+  <DescribeCacheClustersResult>
+    <CacheClusters>
+      <CacheCluster>
+        <CacheClusterId>queue-001</CacheClusterId>
+        <Engine>redis</Engine>
+        <ReplicationGroupId>queue</ReplicationGroupId>
+        <CacheNodes>
+          <CacheNode><CacheNodeId>0001</CacheNodeId><Endpoint><Address>queue-001.abc123.0001.use1.cache.amazonaws.com</Address><Port>6379</Port></Endpoint></CacheNode>
+        </CacheNodes>
+      </CacheCluster>
+    </CacheClusters>
+  </DescribeCacheClustersResult>
+</DescribeCacheClustersResponse>`
+
+func TestDescribeCacheClusters(t *testing.T) {
+	Convey("Given clusters that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testMemcachedCluster, testRedisClusters), &params)
+		defer ts.Close()
+
+		clusters, err := s.DescribeCacheClusters("", true)
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(clusters), ShouldEqual, 2)
+			So(clusters[1].CacheClusterId, ShouldEqual, "queue-001")
+			So(clusters[1].ReplicationGroupId, ShouldEqual, "queue")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+		Convey("Clusters are decoded", func() {
+			created := time.Date(2015, 2, 2, 1, 21, 46, 607000000, time.UTC)
+			So(clusters[0], ShouldResemble, CacheCluster{
+				CacheClusterId:            "sessions",
+				CacheClusterStatus:        "available",
+				Engine:                    "memcached",
+				EngineVersion:             "1.4.24",
+				CacheNodeType:             "cache.m3.medium",
+				NumCacheNodes:             1,
+				PreferredAvailabilityZone: "us-east-1a",
+				CacheClusterCreateTime:    created,
+				ConfigurationEndpoint:     &Endpoint{Address: "sessions.abc123.cfg.use1.cache.amazonaws.com", Port: 11211},
+				CacheNodes: []CacheNode{{
+					CacheNodeId:              "0001",
+					CacheNodeStatus:          "available",
+					CacheNodeCreateTime:      created,
+					Endpoint:                 Endpoint{Address: "sessions.abc123.0001.use1.cache.amazonaws.com", Port: 11211},
+					CustomerAvailabilityZone: "us-east-1a",
+				}},
+			})
+		})
+		Convey("The nodes are asked for", func() {
+			So(params[0].Get("Action"), ShouldEqual, "DescribeCacheClusters")
+			So(params[0].Get("ShowCacheNodeInfo"), ShouldEqual, "true")
+			_, hasId := params[0]["CacheClusterId"]
+			So(hasId, ShouldBeFalse)
+		})
+	})
+	Convey("Given a cluster ID without nodes", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testRedisClusters, ""), &params)
+		defer ts.Close()
+
+		s.DescribeCacheClusters("queue-001", false)
+
+		Convey("The ID is sent and the nodes are not asked for", func() {
+			So(params[0].Get("CacheClusterId"), ShouldEqual, "queue-001")
+			_, hasNodes := params[0]["ShowCacheNodeInfo"]
+			So(hasNodes, ShouldBeFalse)
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeCacheClusters("sessions", false)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, cacheClusterNotFoundError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeCacheClusters("", false)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestClusterEndpoints(t *testing.T) {
+	Convey("Given a memcached cluster", t, func() {
+		ts, s := testService(testPages(testMemcachedCluster, `<DescribeCacheClustersResponse/>`), nil)
+		defer ts.Close()
+
+		endpoints, err := s.ClusterEndpoints("sessions")
+
+		Convey("It returns the configuration endpoint", func() {
+			So(err, ShouldBeNil)
+			So(endpoints, ShouldResemble, []Endpoint{{Address: "sessions.abc123.cfg.use1.cache.amazonaws.com", Port: 11211}})
+		})
+	})
+	Convey("Given a redis cluster", t, func() {
+		ts, s := testService(testPages(testRedisClusters, ""), nil)
+		defer ts.Close()
+
+		endpoints, err := s.ClusterEndpoints("queue-001")
+
+		Convey("It returns the endpoints of the nodes", func() {
+			So(err, ShouldBeNil)
+			So(endpoints, ShouldResemble, []Endpoint{{Address: "queue-001.abc123.0001.use1.cache.amazonaws.com", Port: 6379}})
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ClusterEndpoints("sessions")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, cacheClusterNotFoundError)
+		})
+	})
+}
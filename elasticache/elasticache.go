@@ -0,0 +1,105 @@
+// Package elasticache provides a way to interact with the AWS ElastiCache service.
+package elasticache
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type elastiCacheError -field Code -service ElastiCache CacheClusterNotFound CacheClusterAlreadyExists InvalidCacheClusterState ReplicationGroupNotFoundFault InvalidParameterValue
+
+// elastiCacheError is the error document returned from the ElastiCache service.
+type elastiCacheError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the elastiCacheError into an error message.
+func (e elastiCacheError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func elastiCacheRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := elastiCacheError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// ElastiCacheService is the ElastiCache service at AWS. The endpoint for a region is gaws.Endpoint("elasticache", region).
+type ElastiCacheService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// ElastiCacheAPI is implemented by *ElastiCacheService. Code that takes an ElastiCacheAPI can be given a fake in tests instead.
+type ElastiCacheAPI interface {
+	ClusterEndpoints(id string) ([]Endpoint, error)
+	DescribeCacheClusters(id string, showNodes bool) ([]CacheCluster, error)
+	DescribeReplicationGroups(id string) ([]ReplicationGroup, error)
+}
+
+var _ ElastiCacheAPI = (*ElastiCacheService)(nil)
+
+// New returns an ElastiCacheService with the options applied. Its endpoint is gaws.Endpoint("elasticache", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *ElastiCacheService {
+	config := gaws.NewConfig(options...)
+	return &ElastiCacheService{Endpoint: config.ServiceEndpoint("elasticache"), config: config}
+}
+
+func (s *ElastiCacheService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: elastiCacheRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("elasticache", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an ElastiCache API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2015-02-02"},
+	}
+}
+
+// Endpoint is the address and port to connect to a cache on.
+type Endpoint struct {
+	Address string
+	Port    int
+}
+
+// String returns the endpoint as host:port, for dialing.
+func (e Endpoint) String() string {
+	return net.JoinHostPort(e.Address, strconv.Itoa(e.Port))
+}
@@ -0,0 +1,114 @@
+package elasticache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<DescribeCacheClustersResponse><DescribeCacheClustersResult>"))
+}
+
+var testCacheClusterNotFoundError = []byte(`<ErrorResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>CacheClusterNotFound</Code>
+    <Message>CacheCluster not found: sessions</Message>
+  </Error>
+  <RequestId>9dd01905-5012-5f99-8663-4b3ecd0dfaef</RequestId>
+</ErrorResponse>`)
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(404)
+	w.Write(testCacheClusterNotFoundError)
+}
+
+var cacheClusterNotFoundError = elastiCacheError{Type: "Sender", Code: "CacheClusterNotFound", Message: "CacheCluster not found: sessions"}
+
+// testPages returns a handler that returns first, then second when it is asked for the page after Marker page2.
+func testPages(first string, second string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("Marker") == "page2" {
+			w.Write([]byte(second))
+			return
+		}
+		w.Write([]byte(first))
+	}
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, ElastiCacheService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, ElastiCacheService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := elastiCacheRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := elastiCacheRetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalFailure</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := elastiCacheRetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the cluster does not exist", t, func() {
+		result, err := elastiCacheRetryPredicate(404, testCacheClusterNotFoundError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, cacheClusterNotFoundError)
+		})
+	})
+}
+
+func TestEndpoint(t *testing.T) {
+	Convey("Given an endpoint", t, func() {
+		e := Endpoint{Address: "sessions.abc123.cfg.use1.cache.amazonaws.com", Port: 11211}
+
+		Convey("String returns it as host:port", func() {
+			So(e.String(), ShouldEqual, "sessions.abc123.cfg.use1.cache.amazonaws.com:11211")
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("elasticache", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
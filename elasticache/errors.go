@@ -0,0 +1,18 @@
+// Code generated by errgen from the go:generate line in elasticache.go. DO NOT EDIT.
+
+package elasticache
+
+// These are errors ElastiCache returns. errors.Is(err, elasticache.ErrCacheClusterNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrCacheClusterNotFound      = elastiCacheError{Code: "CacheClusterNotFound"}
+	ErrCacheClusterAlreadyExists = elastiCacheError{Code: "CacheClusterAlreadyExists"}
+	ErrInvalidCacheClusterState  = elastiCacheError{Code: "InvalidCacheClusterState"}
+	ErrReplicationGroupNotFound  = elastiCacheError{Code: "ReplicationGroupNotFoundFault"}
+	ErrInvalidParameterValue     = elastiCacheError{Code: "InvalidParameterValue"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e elastiCacheError) Is(target error) bool {
+	t, ok := target.(elastiCacheError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
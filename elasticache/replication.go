@@ -0,0 +1,101 @@
+package elasticache
+
+import (
+	"encoding/xml"
+)
+
+// NodeGroupMember is a node in a node group.
+type NodeGroupMember struct {
+	CacheClusterId            string
+	CacheNodeId               string
+	ReadEndpoint              Endpoint
+	PreferredAvailabilityZone string
+	CurrentRole               string // primary or replica
+}
+
+// NodeGroup is a primary node and its replicas. A replication group without cluster mode has one.
+type NodeGroup struct {
+	NodeGroupId      string
+	Status           string
+	PrimaryEndpoint  *Endpoint         // The endpoint to write to. Groups with cluster mode enabled do not have one.
+	NodeGroupMembers []NodeGroupMember `xml:"NodeGroupMembers>NodeGroupMember"`
+}
+
+// ReplicationGroup is a group of redis clusters that replicate from a primary.
+type ReplicationGroup struct {
+	ReplicationGroupId    string
+	Description           string
+	Status                string      // Like available or modifying
+	MemberClusters        []string    `xml:"MemberClusters>ClusterId"`
+	NodeGroups            []NodeGroup `xml:"NodeGroups>NodeGroup"`
+	AutomaticFailover     string
+	ConfigurationEndpoint *Endpoint // The endpoint of a group with cluster mode enabled
+}
+
+// PrimaryEndpoint returns the endpoint to write to: the configuration endpoint of a group with cluster mode enabled,
+// or the primary endpoint of the node group. It returns nil if the group does not have one yet.
+func (g ReplicationGroup) PrimaryEndpoint() *Endpoint {
+	if g.ConfigurationEndpoint != nil {
+		return g.ConfigurationEndpoint
+	}
+	for _, group := range g.NodeGroups {
+		if group.PrimaryEndpoint != nil {
+			return group.PrimaryEndpoint
+		}
+	}
+	return nil
+}
+
+// ReadEndpoints returns the endpoints of the replicas, to spread reads over.
+func (g ReplicationGroup) ReadEndpoints() []Endpoint {
+	endpoints := []Endpoint{}
+	for _, group := range g.NodeGroups {
+		for _, member := range group.NodeGroupMembers {
+			if member.CurrentRole == "replica" {
+				endpoints = append(endpoints, member.ReadEndpoint)
+			}
+		}
+	}
+	return endpoints
+}
+
+type describeReplicationGroupsResponse struct {
+	ReplicationGroups []ReplicationGroup `xml:"DescribeReplicationGroupsResult>ReplicationGroups>ReplicationGroup"`
+	Marker            string             `xml:"DescribeReplicationGroupsResult>Marker"`
+}
+
+// DescribeReplicationGroups describes a replication group. If id is empty, every replication group is described.
+// It is calling the DescribeReplicationGroups API call until there are no more pages.
+// See http://docs.aws.amazon.com/AmazonElastiCache/latest/APIReference/API_DescribeReplicationGroups.html for more details.
+func (s *ElastiCacheService) DescribeReplicationGroups(id string) ([]ReplicationGroup, error) {
+	groups := []ReplicationGroup{}
+
+	params := query("DescribeReplicationGroups")
+	if id != "" {
+		params.Set("ReplicationGroupId", id)
+	}
+
+	for {
+		result := describeReplicationGroupsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []ReplicationGroup{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []ReplicationGroup{}, err
+		}
+
+		groups = append(groups, result.ReplicationGroups...)
+
+		if result.Marker == "" {
+			return groups, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
@@ -0,0 +1,130 @@
+package elasticache
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testReplicationGroup = `<DescribeReplicationGroupsResponse xmlns="http://elasticache.amazonaws.com/doc/2015-02-02/">
+  <DescribeReplicationGroupsResult>
+    <ReplicationGroups>
+      <ReplicationGroup>
+        <ReplicationGroupId>queue</ReplicationGroupId>
+        <Description>Work queue</Description>
+        <Status>available</Status>
+        <AutomaticFailover>enabled</AutomaticFailover>
+        <MemberClusters>
+          <ClusterId>queue-001</ClusterId>
+          <ClusterId>queue-002</ClusterId>
+        </MemberClusters>
+        <NodeGroups>
+          <NodeGroup>
+            <NodeGroupId>0001</NodeGroupId>
+            <Status>available</Status>
+            <PrimaryEndpoint>
+              <Address>queue.abc123.ng.0001.use1.cache.amazonaws.com</Address>
+              <Port>6379</Port>
+            </PrimaryEndpoint>
+            <NodeGroupMembers>
+              <NodeGroupMember>
+                <CacheClusterId>queue-001</CacheClusterId>
+                <CacheNodeId>0001</CacheNodeId>
+                <ReadEndpoint><Address>queue-001.abc123.0001.use1.cache.amazonaws.com</Address><Port>6379</Port></ReadEndpoint>
+                <PreferredAvailabilityZone>us-east-1a</PreferredAvailabilityZone>
+                <CurrentRole>primary</CurrentRole>
+              </NodeGroupMember>
+              <NodeGroupMember>
+                <CacheClusterId>queue-002</CacheClusterId>
+                <CacheNodeId>0001</CacheNodeId>
+                <ReadEndpoint><Address>queue-002.abc123.0001.use1.cache.amazonaws.com</Address><Port>6379</Port></ReadEndpoint>
+                <PreferredAvailabilityZone>us-east-1b</PreferredAvailabilityZone>
+                <CurrentRole>replica</CurrentRole>
+              </NodeGroupMember>
+            </NodeGroupMembers>
+          </NodeGroup>
+        </NodeGroups>
+      </ReplicationGroup>
+    </ReplicationGroups>
+    <Marker>page2</Marker>
+  </DescribeReplicationGroupsResult>
+</DescribeReplicationGroupsResponse>`
+
+const testClusteredReplicationGroup = `<DescribeReplicationGroupsResponse>
+  <DescribeReplicationGroupsResult>
+    <ReplicationGroups>
+      <ReplicationGroup>
+        <ReplicationGroupId>sharded</ReplicationGroupId>
+        <ConfigurationEndpoint><Address>sharded.abc123.clustercfg.use1.cache.amazonaws.com</Address><Port>6379</Port></ConfigurationEndpoint>
+        <NodeGroups><NodeGroup><NodeGroupId>0001</NodeGroupId></NodeGroup></NodeGroups>
+      </ReplicationGroup>
+    </ReplicationGroups>
+  </DescribeReplicationGroupsResult>
+</DescribeReplicationGroupsResponse>`
+
+func TestDescribeReplicationGroups(t *testing.T) {
+	Convey("Given replication groups that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testReplicationGroup, testClusteredReplicationGroup), &params)
+		defer ts.Close()
+
+		groups, err := s.DescribeReplicationGroups("")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(groups), ShouldEqual, 2)
+			So(groups[1].ReplicationGroupId, ShouldEqual, "sharded")
+			So(params[0].Get("Action"), ShouldEqual, "DescribeReplicationGroups")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+		Convey("Groups are decoded", func() {
+			So(groups[0].Description, ShouldEqual, "Work queue")
+			So(groups[0].MemberClusters, ShouldResemble, []string{"queue-001", "queue-002"})
+			So(groups[0].NodeGroups[0].NodeGroupMembers[1], ShouldResemble, NodeGroupMember{
+				CacheClusterId:            "queue-002",
+				CacheNodeId:               "0001",
+				ReadEndpoint:              Endpoint{Address: "queue-002.abc123.0001.use1.cache.amazonaws.com", Port: 6379},
+				PreferredAvailabilityZone: "us-east-1b",
+				CurrentRole:               "replica",
+			})
+		})
+		Convey("The primary endpoint is the node group's", func() {
+			So(groups[0].PrimaryEndpoint(), ShouldResemble, &Endpoint{Address: "queue.abc123.ng.0001.use1.cache.amazonaws.com", Port: 6379})
+		})
+		Convey("The read endpoints are the replicas'", func() {
+			So(groups[0].ReadEndpoints(), ShouldResemble, []Endpoint{{Address: "queue-002.abc123.0001.use1.cache.amazonaws.com", Port: 6379}})
+		})
+		Convey("A group with cluster mode has its configuration endpoint as primary", func() {
+			So(groups[1].PrimaryEndpoint(), ShouldResemble, &Endpoint{Address: "sharded.abc123.clustercfg.use1.cache.amazonaws.com", Port: 6379})
+		})
+	})
+	Convey("Given a group ID", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testClusteredReplicationGroup, ""), &params)
+		defer ts.Close()
+
+		s.DescribeReplicationGroups("sharded")
+
+		Convey("It is sent", func() {
+			So(params[0].Get("ReplicationGroupId"), ShouldEqual, "sharded")
+		})
+	})
+	Convey("Given a group that is still being created", t, func() {
+		g := ReplicationGroup{NodeGroups: []NodeGroup{{NodeGroupId: "0001"}}}
+
+		Convey("It has no primary endpoint", func() {
+			So(g.PrimaryEndpoint(), ShouldBeNil)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeReplicationGroups("queue")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, cacheClusterNotFoundError)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+// Package elasticbeanstalk provides a way to interact with the AWS Elastic Beanstalk service.
+package elasticbeanstalk
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type elasticBeanstalkError -field Code -service "Elastic Beanstalk" InvalidParameterValue InsufficientPrivilegesException TooManyApplicationsException TooManyEnvironmentsException OperationInProgressFailure
+
+// elasticBeanstalkError is the error document returned from the Elastic Beanstalk service.
+type elasticBeanstalkError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the elasticBeanstalkError into an error message.
+func (e elasticBeanstalkError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func elasticBeanstalkRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := elasticBeanstalkError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// ElasticBeanstalkService is the Elastic Beanstalk service at AWS. The endpoint for a region is gaws.Endpoint("elasticbeanstalk", region).
+type ElasticBeanstalkService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// ElasticBeanstalkAPI is implemented by *ElasticBeanstalkService. Code that takes an ElasticBeanstalkAPI can be given a fake in tests instead.
+type ElasticBeanstalkAPI interface {
+	DescribeEnvironments(application string, names ...string) ([]Environment, error)
+	RestartAppServer(environmentName string) error
+	UpdateEnvironment(update EnvironmentUpdate) (Environment, error)
+}
+
+var _ ElasticBeanstalkAPI = (*ElasticBeanstalkService)(nil)
+
+// New returns an ElasticBeanstalkService with the options applied. Its endpoint is gaws.Endpoint("elasticbeanstalk", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *ElasticBeanstalkService {
+	config := gaws.NewConfig(options...)
+	return &ElasticBeanstalkService{Endpoint: config.ServiceEndpoint("elasticbeanstalk"), config: config}
+}
+
+func (s *ElasticBeanstalkService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: elasticBeanstalkRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("elasticbeanstalk", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an Elastic Beanstalk API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-12-01"},
+	}
+}
@@ -0,0 +1,157 @@
+package elasticbeanstalk
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// The statuses of an environment.
+const (
+	Launching   = "Launching"
+	Updating    = "Updating"
+	Ready       = "Ready"
+	Terminating = "Terminating"
+	Terminated  = "Terminated"
+)
+
+// Environment is an Elastic Beanstalk environment, a version of an application running on AWS resources.
+type Environment struct {
+	EnvironmentName   string
+	EnvironmentId     string
+	ApplicationName   string
+	VersionLabel      string // The application version the environment runs
+	SolutionStackName string
+	Description       string
+	EndpointURL       string // The URL of the environment's load balancer
+	CNAME             string
+	Status            string // One of Launching, Updating, Ready, Terminating or Terminated
+	Health            string // Green, Yellow, Red or Grey
+	HealthStatus      string // The status from enhanced health reporting, if it is on
+	DateCreated       time.Time
+	DateUpdated       time.Time
+}
+
+type describeEnvironmentsResponse struct {
+	Environments []Environment `xml:"DescribeEnvironmentsResult>Environments>member"`
+	NextToken    string        `xml:"DescribeEnvironmentsResult>NextToken"`
+}
+
+// DescribeEnvironments describes the environments of an application with the names. If application is empty, environments of every application
+// are described, and if there are no names, every environment is. Terminated environments are not included.
+// It is calling the DescribeEnvironments API call until there are no more pages.
+// See http://docs.aws.amazon.com/elasticbeanstalk/latest/api/API_DescribeEnvironments.html for more details.
+func (s *ElasticBeanstalkService) DescribeEnvironments(application string, names ...string) ([]Environment, error) {
+	environments := []Environment{}
+
+	params := query("DescribeEnvironments")
+	params.Set("IncludeDeleted", "false")
+	if application != "" {
+		params.Set("ApplicationName", application)
+	}
+	for i, name := range names {
+		params.Set(fmt.Sprintf("EnvironmentNames.member.%d", i+1), name)
+	}
+
+	for {
+		result := describeEnvironmentsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Environment{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Environment{}, err
+		}
+
+		environments = append(environments, result.Environments...)
+
+		if result.NextToken == "" {
+			return environments, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
+
+// OptionSetting is a configuration option of an environment, like the instance type in the aws:autoscaling:launchconfiguration namespace.
+type OptionSetting struct {
+	Namespace    string
+	OptionName   string
+	Value        string
+	ResourceName string // The resource the option is for, if it is for one, like the name of an Auto Scaling group's scheduled action
+}
+
+// EnvironmentUpdate is a change to an environment. Fields that are empty are not changed.
+type EnvironmentUpdate struct {
+	EnvironmentName string
+	VersionLabel    string // The application version to deploy
+	Description     string
+	OptionSettings  []OptionSetting // Options to set
+	OptionsToRemove []OptionSetting // Options to set back to their defaults. Their values are ignored.
+}
+
+type updateEnvironmentResponse struct {
+	Environment Environment `xml:"UpdateEnvironmentResult"`
+}
+
+// UpdateEnvironment starts changing an environment, and returns the environment. It is Updating until the change is done, and then Ready.
+// It is calling the UpdateEnvironment API call.
+// See http://docs.aws.amazon.com/elasticbeanstalk/latest/api/API_UpdateEnvironment.html for more details.
+func (s *ElasticBeanstalkService) UpdateEnvironment(update EnvironmentUpdate) (Environment, error) {
+	result := updateEnvironmentResponse{}
+
+	params := query("UpdateEnvironment")
+	params.Set("EnvironmentName", update.EnvironmentName)
+	if update.VersionLabel != "" {
+		params.Set("VersionLabel", update.VersionLabel)
+	}
+	if update.Description != "" {
+		params.Set("Description", update.Description)
+	}
+	for i, option := range update.OptionSettings {
+		prefix := fmt.Sprintf("OptionSettings.member.%d.", i+1)
+		params.Set(prefix+"Namespace", option.Namespace)
+		params.Set(prefix+"OptionName", option.OptionName)
+		params.Set(prefix+"Value", option.Value)
+		if option.ResourceName != "" {
+			params.Set(prefix+"ResourceName", option.ResourceName)
+		}
+	}
+	for i, option := range update.OptionsToRemove {
+		prefix := fmt.Sprintf("OptionsToRemove.member.%d.", i+1)
+		params.Set(prefix+"Namespace", option.Namespace)
+		params.Set(prefix+"OptionName", option.OptionName)
+		if option.ResourceName != "" {
+			params.Set(prefix+"ResourceName", option.ResourceName)
+		}
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Environment, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Environment, err
+}
+
+// RestartAppServer restarts the application server on every instance of an environment. It is calling the RestartAppServer API call.
+// See http://docs.aws.amazon.com/elasticbeanstalk/latest/api/API_RestartAppServer.html for more details.
+func (s *ElasticBeanstalkService) RestartAppServer(environmentName string) error {
+	params := query("RestartAppServer")
+	params.Set("EnvironmentName", environmentName)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,190 @@
+package elasticbeanstalk
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testEnvironmentsPage1 = `<DescribeEnvironmentsResponse xmlns="http://elasticbeanstalk.amazonaws.com/docs/2010-12-01/">
+  <DescribeEnvironmentsResult>
+    <Environments>
+      <member>
+        <EnvironmentName>workers</EnvironmentName>
+        <EnvironmentId>e-icsgecu3wf</EnvironmentId>
+        <ApplicationName>gaws</ApplicationName>
+        <VersionLabel>v12</VersionLabel>
+        <SolutionStackName>64bit Amazon Linux running Go 1.5</SolutionStackName>
+        <EndpointURL>awseb-e-i-AWSEBLoa-1RDLX6TC9VUAO-0123456789.us-east-1.elb.amazonaws.com</EndpointURL>
+        <CNAME>workers.elasticbeanstalk.com</CNAME>
+        <Status>Ready</Status>
+        <Health>Green</Health>
+        <DateCreated>2015-08-07T20:48:49.599Z</DateCreated>
+        <DateUpdated>2015-08-12T18:16:55.019Z</DateUpdated>
+      </member>
+    </Environments>
+    <NextToken>page2</NextToken>
+  </DescribeEnvironmentsResult>
+</DescribeEnvironmentsResponse>`
+
+const testEnvironmentsPage2 = `<DescribeEnvironmentsResponse xmlns="http://elasticbeanstalk.amazonaws.com/docs/2010-12-01/">
+  <DescribeEnvironmentsResult>
+    <Environments>
+      <member>
+        <EnvironmentName>web</EnvironmentName>
+        <EnvironmentId>e-8cx3hmq2kp</EnvironmentId>
+        <ApplicationName>gaws</ApplicationName>
+        <Status>Updating</Status>
+        <Health>Grey</Health>
+      </member>
+    </Environments>
+  </DescribeEnvironmentsResult>
+</DescribeEnvironmentsResponse>`
+
+const testUpdateEnvironment = `<UpdateEnvironmentResponse xmlns="http://elasticbeanstalk.amazonaws.com/docs/2010-12-01/">
+  <UpdateEnvironmentResult>
+    <EnvironmentName>workers</EnvironmentName>
+    <EnvironmentId>e-icsgecu3wf</EnvironmentId>
+    <ApplicationName>gaws</ApplicationName>
+    <VersionLabel>v13</VersionLabel>
+    <Status>Updating</Status>
+    <Health>Grey</Health>
+  </UpdateEnvironmentResult>
+</UpdateEnvironmentResponse>`
+
+func TestDescribeEnvironments(t *testing.T) {
+	Convey("Given environments on two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testEnvironmentsPage1, testEnvironmentsPage2), &params)
+		defer ts.Close()
+
+		environments, err := s.DescribeEnvironments("gaws", "workers", "web")
+
+		Convey("It returns the environments from both pages", func() {
+			So(err, ShouldBeNil)
+			So(len(environments), ShouldEqual, 2)
+			So(environments[0].EnvironmentId, ShouldEqual, "e-icsgecu3wf")
+			So(environments[0].CNAME, ShouldEqual, "workers.elasticbeanstalk.com")
+			So(environments[0].Status, ShouldEqual, Ready)
+			So(environments[0].DateUpdated.Day(), ShouldEqual, 12)
+			So(environments[1].Status, ShouldEqual, Updating)
+		})
+		Convey("It asks for the application's environments", func() {
+			So(params[0].Get("Action"), ShouldEqual, "DescribeEnvironments")
+			So(params[0].Get("ApplicationName"), ShouldEqual, "gaws")
+			So(params[0].Get("EnvironmentNames.member.1"), ShouldEqual, "workers")
+			So(params[0].Get("EnvironmentNames.member.2"), ShouldEqual, "web")
+			So(params[0].Get("IncludeDeleted"), ShouldEqual, "false")
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given no application", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(testEnvironmentsPage2, ""), &params)
+		defer ts.Close()
+
+		_, err := s.DescribeEnvironments("")
+
+		Convey("It does not send an application", func() {
+			So(err, ShouldBeNil)
+			So(params[0]["ApplicationName"], ShouldBeNil)
+		})
+	})
+	Convey("Given bad XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		environments, err := s.DescribeEnvironments("gaws")
+
+		Convey("It returns an error and no environments", func() {
+			So(err, ShouldNotBeNil)
+			So(len(environments), ShouldEqual, 0)
+		})
+	})
+	Convey("Given an error", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeEnvironments("gaws")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidParameterError)
+		})
+	})
+}
+
+func TestUpdateEnvironment(t *testing.T) {
+	Convey("Given a new version and option settings", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testUpdateEnvironment))
+		}, &params)
+		defer ts.Close()
+
+		environment, err := s.UpdateEnvironment(EnvironmentUpdate{
+			EnvironmentName: "workers",
+			VersionLabel:    "v13",
+			OptionSettings: []OptionSetting{
+				{Namespace: "aws:autoscaling:launchconfiguration", OptionName: "InstanceType", Value: "m3.medium"},
+				{Namespace: "aws:elasticbeanstalk:application:environment", OptionName: "QUEUE", Value: "jobs"},
+			},
+			OptionsToRemove: []OptionSetting{{Namespace: "aws:elasticbeanstalk:application:environment", OptionName: "DEBUG"}},
+		})
+
+		Convey("It returns the environment", func() {
+			So(err, ShouldBeNil)
+			So(environment.VersionLabel, ShouldEqual, "v13")
+			So(environment.Status, ShouldEqual, Updating)
+		})
+		Convey("It sends the changes", func() {
+			So(params[0].Get("Action"), ShouldEqual, "UpdateEnvironment")
+			So(params[0].Get("EnvironmentName"), ShouldEqual, "workers")
+			So(params[0].Get("VersionLabel"), ShouldEqual, "v13")
+			So(params[0]["Description"], ShouldBeNil)
+			So(params[0].Get("OptionSettings.member.1.Namespace"), ShouldEqual, "aws:autoscaling:launchconfiguration")
+			So(params[0].Get("OptionSettings.member.1.OptionName"), ShouldEqual, "InstanceType")
+			So(params[0].Get("OptionSettings.member.1.Value"), ShouldEqual, "m3.medium")
+			So(params[0].Get("OptionSettings.member.2.Value"), ShouldEqual, "jobs")
+			So(params[0].Get("OptionsToRemove.member.1.OptionName"), ShouldEqual, "DEBUG")
+			So(params[0]["OptionsToRemove.member.1.Value"], ShouldBeNil)
+		})
+	})
+	Convey("Given an error", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.UpdateEnvironment(EnvironmentUpdate{EnvironmentName: "workers"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidParameterError)
+		})
+	})
+}
+
+func TestRestartAppServer(t *testing.T) {
+	Convey("Given an environment", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.RestartAppServer("workers")
+
+		Convey("It restarts the environment's app servers", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "RestartAppServer")
+			So(params[0].Get("EnvironmentName"), ShouldEqual, "workers")
+		})
+	})
+	Convey("Given an error", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		err := s.RestartAppServer("workers")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidParameterError)
+		})
+	})
+}
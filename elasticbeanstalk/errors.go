@@ -0,0 +1,18 @@
+// Code generated by errgen from the go:generate line in elasticbeanstalk.go. DO NOT EDIT.
+
+package elasticbeanstalk
+
+// These are errors Elastic Beanstalk returns. errors.Is(err, elasticbeanstalk.ErrInvalidParameterValue) is true for any error with the same code, whatever its message.
+var (
+	ErrInvalidParameterValue      = elasticBeanstalkError{Code: "InvalidParameterValue"}
+	ErrInsufficientPrivileges     = elasticBeanstalkError{Code: "InsufficientPrivilegesException"}
+	ErrTooManyApplications        = elasticBeanstalkError{Code: "TooManyApplicationsException"}
+	ErrTooManyEnvironments        = elasticBeanstalkError{Code: "TooManyEnvironmentsException"}
+	ErrOperationInProgressFailure = elasticBeanstalkError{Code: "OperationInProgressFailure"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e elasticBeanstalkError) Is(target error) bool {
+	t, ok := target.(elasticBeanstalkError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,91 @@
+// Package elastictranscoder provides a way to interact with the AWS Elastic Transcoder service.
+package elastictranscoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+// apiVersion is the version of the Elastic Transcoder API, which starts the path of every request.
+const apiVersion = "/2012-09-25"
+
+// transcoderError is the error document returned from the Elastic Transcoder service.
+type transcoderError struct {
+	Status  int    `json:"-"` // The HTTP status of the response, like 404 when a job does not exist
+	Message string `json:"message"`
+}
+
+// Error formats the transcoderError into an error message.
+func (e transcoderError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Status, e.Message)
+}
+
+func transcoderRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := transcoderError{Status: status}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	// LimitExceededException
+	if status == 429 {
+		return true, error
+	}
+
+	return false, error
+}
+
+// ElasticTranscoderService is the Elastic Transcoder service at AWS. The endpoint for a region is gaws.Endpoint("elastictranscoder", region).
+type ElasticTranscoderService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// ElasticTranscoderAPI is implemented by *ElasticTranscoderService. Code that takes an ElasticTranscoderAPI can be given a fake in tests instead.
+type ElasticTranscoderAPI interface {
+	CreateJob(job JobRequest) (Job, error)
+	ListJobsByPipeline(pipelineId string, ascending bool) ([]Job, error)
+	ReadJob(id string) (Job, error)
+}
+
+var _ ElasticTranscoderAPI = (*ElasticTranscoderService)(nil)
+
+// New returns an ElasticTranscoderService with the options applied. Its endpoint is gaws.Endpoint("elastictranscoder", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *ElasticTranscoderService {
+	config := gaws.NewConfig(options...)
+	return &ElasticTranscoderService{Endpoint: config.ServiceEndpoint("elastictranscoder"), config: config}
+}
+
+// request returns a request to a path under the API version on the endpoint.
+func (s *ElasticTranscoderService) request(method string, path string, query url.Values) gaws.AWSRequest {
+	u := s.Endpoint + apiVersion + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	r := gaws.AWSRequest{
+		RetryPredicate: transcoderRetryPredicate,
+		Method:         method,
+		URL:            u,
+		Signer:         gaws.Sign4("elastictranscoder", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
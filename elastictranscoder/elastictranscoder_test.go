@@ -0,0 +1,105 @@
+package elastictranscoder
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var notFoundError = transcoderError{Status: 404, Message: "The specified job was not found: account=123456789012, jobId=1111111111111-abcdef"}
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amzn-ErrorType", "ResourceNotFoundException")
+	w.WriteHeader(404)
+	w.Write([]byte(`{"message":"The specified job was not found: account=123456789012, jobId=1111111111111-abcdef"}`))
+}
+
+// testJson returns a handler that returns body.
+func testJson(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Method  string
+	URI     string // The path and query
+	Headers http.Header
+	Body    []byte
+}
+
+// testService returns a service on a server that runs handler and records every request.
+func testService(handler http.HandlerFunc, requests *[]testRequest) (*httptest.Server, *ElasticTranscoderService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if requests != nil {
+			*requests = append(*requests, testRequest{Method: r.Method, URI: r.URL.RequestURI(), Headers: r.Header, Body: body})
+		}
+		handler(w, r)
+	}))
+	return ts, &ElasticTranscoderService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := transcoderRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 429", t, func() {
+		result, _ := transcoderRetryPredicate(429, []byte(`{"message":"Too many requests"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := transcoderRetryPredicate(500, []byte(`{"message":"Internal failure"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says a job does not exist", t, func() {
+		result, err := transcoderRetryPredicate(404, []byte(`{"message":"The specified job was not found: account=123456789012, jobId=1111111111111-abcdef"}`))
+
+		Convey("RetryPredicate returns false and the error with its status", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("elastictranscoder", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request("GET", "/", nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
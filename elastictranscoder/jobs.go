@@ -0,0 +1,157 @@
+package elastictranscoder
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The statuses a job and its outputs can have.
+const (
+	Submitted   = "Submitted"
+	Progressing = "Progressing"
+	Complete    = "Complete"
+	Canceled    = "Canceled"
+	Error       = "Error"
+)
+
+// JobInput is the file a job transcodes. Settings that are empty are detected by Elastic Transcoder.
+type JobInput struct {
+	Key         string // The key of the file in the pipeline's input bucket
+	FrameRate   string `json:",omitempty"`
+	Resolution  string `json:",omitempty"`
+	AspectRatio string `json:",omitempty"`
+	Interlaced  string `json:",omitempty"`
+	Container   string `json:",omitempty"`
+}
+
+// CreateJobOutput is a file for a job to transcode its input into.
+type CreateJobOutput struct {
+	Key              string // The key of the file in the pipeline's output bucket, after the job's OutputKeyPrefix
+	PresetId         string // Like 1351620000001-000010 for generic 720p
+	ThumbnailPattern string `json:",omitempty"` // Like thumbnails/video-{count}. If it is empty, there are no thumbnails.
+	Rotate           string `json:",omitempty"`
+	SegmentDuration  string `json:",omitempty"` // The length of segments in seconds, for HLS and Smooth outputs
+}
+
+// JobRequest is the input to CreateJob.
+type JobRequest struct {
+	PipelineId      string
+	Input           JobInput
+	Outputs         []CreateJobOutput
+	OutputKeyPrefix string            `json:",omitempty"`
+	UserMetadata    map[string]string `json:",omitempty"` // Returned with the job, like {"upload": "42"}
+}
+
+// JobOutput is a file a job transcodes its input into.
+type JobOutput struct {
+	Id               string
+	Key              string
+	PresetId         string
+	ThumbnailPattern string
+	Status           string
+	StatusDetail     string // Why the output has the Error status
+	Duration         int64  // Seconds
+	Width            int
+	Height           int
+}
+
+// Timing says when a job was submitted, started and finished, in milliseconds since the epoch. They are 0 if it has not happened.
+type Timing struct {
+	SubmitTimeMillis int64
+	StartTimeMillis  int64
+	FinishTimeMillis int64
+}
+
+// Job is an Elastic Transcoder job.
+type Job struct {
+	Id              string
+	Arn             string
+	PipelineId      string
+	Input           JobInput
+	Outputs         []JobOutput
+	OutputKeyPrefix string
+	Status          string
+	UserMetadata    map[string]string
+	Timing          Timing
+}
+
+type jobResult struct {
+	Job Job
+}
+
+// CreateJob starts a job, which transcodes a file in the pipeline's input bucket into its output bucket. It is calling the CreateJob API call.
+// See http://docs.aws.amazon.com/elastictranscoder/latest/developerguide/create-job.html for more details.
+func (s *ElasticTranscoderService) CreateJob(job JobRequest) (Job, error) {
+	result := jobResult{}
+
+	bodyAsJson, err := json.Marshal(job)
+	if err != nil {
+		return result.Job, err
+	}
+
+	req := s.request("POST", "/jobs", nil)
+	req.Body = bodyAsJson
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Job, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Job, err
+}
+
+// ReadJob describes a job. It is calling the ReadJob API call.
+// See http://docs.aws.amazon.com/elastictranscoder/latest/developerguide/get-job.html for more details.
+func (s *ElasticTranscoderService) ReadJob(id string) (Job, error) {
+	result := jobResult{}
+
+	req := s.request("GET", "/jobs/"+url.PathEscape(id), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Job, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Job, err
+}
+
+type listJobsResult struct {
+	Jobs          []Job
+	NextPageToken string
+}
+
+// ListJobsByPipeline lists the jobs of a pipeline, oldest first if ascending is true, or newest first if it is not.
+// It is calling the ListJobsByPipeline API call until there are no more pages.
+// See http://docs.aws.amazon.com/elastictranscoder/latest/developerguide/list-jobs-by-pipeline.html for more details.
+func (s *ElasticTranscoderService) ListJobsByPipeline(pipelineId string, ascending bool) ([]Job, error) {
+	jobs := []Job{}
+
+	query := url.Values{"Ascending": {strconv.FormatBool(ascending)}}
+
+	for {
+		result := listJobsResult{}
+
+		req := s.request("GET", "/jobsByPipeline/"+url.PathEscape(pipelineId), query)
+
+		resp, err := req.Do()
+		if err != nil {
+			return jobs, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, result.Jobs...)
+
+		if result.NextPageToken == "" {
+			return jobs, nil
+		}
+		query.Set("PageToken", result.NextPageToken)
+	}
+}
@@ -0,0 +1,141 @@
+package elastictranscoder
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testJob = `{"Job":{"Id":"1111111111111-abcdef","Arn":"arn:aws:elastictranscoder:us-east-1:123456789012:job/1111111111111-abcdef",
+	"PipelineId":"1111111111111-pipeln","Input":{"Key":"uploads/42.mov","FrameRate":"auto"},
+	"Outputs":[{"Id":"1","Key":"42-720p.mp4","PresetId":"1351620000001-000010","Status":"Complete","Duration":61,"Width":1280,"Height":720}],
+	"OutputKeyPrefix":"videos/","Status":"Complete","UserMetadata":{"upload":"42"},
+	"Timing":{"SubmitTimeMillis":1438000000000,"StartTimeMillis":1438000001000,"FinishTimeMillis":1438000060000}}}`
+
+func TestCreateJob(t *testing.T) {
+	Convey("Given a job", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testJson(testJob), &requests)
+		defer ts.Close()
+
+		job, err := s.CreateJob(JobRequest{
+			PipelineId:      "1111111111111-pipeln",
+			Input:           JobInput{Key: "uploads/42.mov", FrameRate: "auto"},
+			Outputs:         []CreateJobOutput{{Key: "42-720p.mp4", PresetId: "1351620000001-000010"}},
+			OutputKeyPrefix: "videos/",
+			UserMetadata:    map[string]string{"upload": "42"},
+		})
+
+		Convey("It returns the job", func() {
+			So(err, ShouldBeNil)
+			So(job.Id, ShouldEqual, "1111111111111-abcdef")
+			So(job.Status, ShouldEqual, Complete)
+			So(job.Outputs[0], ShouldResemble, JobOutput{Id: "1", Key: "42-720p.mp4", PresetId: "1351620000001-000010", Status: Complete, Duration: 61, Width: 1280, Height: 720})
+			So(job.Timing.FinishTimeMillis, ShouldEqual, 1438000060000)
+			So(job.UserMetadata["upload"], ShouldEqual, "42")
+		})
+		Convey("It posts the job", func() {
+			So(requests[0].Method, ShouldEqual, "POST")
+			So(requests[0].URI, ShouldEqual, "/2012-09-25/jobs")
+			So(requests[0].Headers.Get("Content-Type"), ShouldEqual, "application/json")
+			So(string(requests[0].Body), ShouldEqual, `{"PipelineId":"1111111111111-pipeln","Input":{"Key":"uploads/42.mov","FrameRate":"auto"},`+
+				`"Outputs":[{"Key":"42-720p.mp4","PresetId":"1351620000001-000010"}],"OutputKeyPrefix":"videos/","UserMetadata":{"upload":"42"}}`)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s := testService(testBadJson, nil)
+		defer ts.Close()
+
+		_, err := s.CreateJob(JobRequest{PipelineId: "1111111111111-pipeln"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestReadJob(t *testing.T) {
+	Convey("Given a job", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testJson(testJob), &requests)
+		defer ts.Close()
+
+		job, err := s.ReadJob("1111111111111-abcdef")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(job.PipelineId, ShouldEqual, "1111111111111-pipeln")
+			So(job.Input, ShouldResemble, JobInput{Key: "uploads/42.mov", FrameRate: "auto"})
+			So(requests[0].Method, ShouldEqual, "GET")
+			So(requests[0].URI, ShouldEqual, "/2012-09-25/jobs/1111111111111-abcdef")
+		})
+	})
+	Convey("Given an ID with a slash", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testJson(testJob), &requests)
+		defer ts.Close()
+
+		s.ReadJob("../pipelines")
+
+		Convey("It is escaped", func() {
+			So(requests[0].URI, ShouldEqual, "/2012-09-25/jobs/..%2Fpipelines")
+		})
+	})
+	Convey("Given a job that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ReadJob("1111111111111-abcdef")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestListJobsByPipeline(t *testing.T) {
+	Convey("Given jobs that take two pages", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("PageToken") == "page2" {
+				w.Write([]byte(`{"Jobs":[{"Id":"2","Status":"Progressing"}]}`))
+				return
+			}
+			w.Write([]byte(`{"Jobs":[{"Id":"1","Status":"Complete"}],"NextPageToken":"page2"}`))
+		}, &requests)
+		defer ts.Close()
+
+		jobs, err := s.ListJobsByPipeline("1111111111111-pipeln", true)
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(jobs), ShouldEqual, 2)
+			So(jobs[1].Status, ShouldEqual, Progressing)
+		})
+		Convey("It asks for the pipeline in order", func() {
+			So(requests[0].URI, ShouldEqual, "/2012-09-25/jobsByPipeline/1111111111111-pipeln?Ascending=true")
+			So(requests[1].URI, ShouldEqual, "/2012-09-25/jobsByPipeline/1111111111111-pipeln?Ascending=true&PageToken=page2")
+		})
+	})
+	Convey("Given a pipeline that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ListJobsByPipeline("1111111111111-pipeln", false)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s := testService(testBadJson, nil)
+		defer ts.Close()
+
+		_, err := s.ListJobsByPipeline("1111111111111-pipeln", false)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
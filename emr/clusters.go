@@ -0,0 +1,155 @@
+package emr
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The states a cluster can be in.
+const (
+	Starting             = "STARTING"
+	Bootstrapping        = "BOOTSTRAPPING"
+	Running              = "RUNNING"
+	Waiting              = "WAITING" // Done with its steps and waiting for more
+	Terminating          = "TERMINATING"
+	Terminated           = "TERMINATED"
+	TerminatedWithErrors = "TERMINATED_WITH_ERRORS"
+)
+
+// StateChangeReason says why a cluster changed state, like why it terminated.
+type StateChangeReason struct {
+	Code    string // Like ALL_STEPS_COMPLETED or STEP_FAILURE
+	Message string
+}
+
+// Timeline says when a cluster was created, ready and terminated, in seconds since the epoch. They are 0 if it has not happened.
+type Timeline struct {
+	CreationDateTime float64
+	ReadyDateTime    float64
+	EndDateTime      float64
+}
+
+// Created returns CreationDateTime as a time.Time.
+func (t Timeline) Created() time.Time {
+	return seconds(t.CreationDateTime)
+}
+
+// Ready returns ReadyDateTime as a time.Time.
+func (t Timeline) Ready() time.Time {
+	return seconds(t.ReadyDateTime)
+}
+
+// Ended returns EndDateTime as a time.Time.
+func (t Timeline) Ended() time.Time {
+	return seconds(t.EndDateTime)
+}
+
+// ClusterStatus is the state of a cluster.
+type ClusterStatus struct {
+	State             string
+	StateChangeReason StateChangeReason
+	Timeline          Timeline
+}
+
+// Cluster is an EMR cluster.
+type Cluster struct {
+	Id                      string
+	Name                    string
+	Status                  ClusterStatus
+	MasterPublicDnsName     string
+	NormalizedInstanceHours int
+	ReleaseLabel            string
+	LogUri                  string
+	AutoTerminate           bool
+	TerminationProtected    bool
+	Applications            []Application
+	Tags                    []Tag
+}
+
+type clusterRequest struct {
+	ClusterId string
+}
+
+type describeClusterResult struct {
+	Cluster Cluster
+}
+
+// DescribeCluster describes a cluster. It is calling the DescribeCluster API call.
+// See http://docs.aws.amazon.com/ElasticMapReduce/latest/API/API_DescribeCluster.html for more details.
+func (s *EMRService) DescribeCluster(id string) (Cluster, error) {
+	result := describeClusterResult{}
+
+	bodyAsJson, err := json.Marshal(clusterRequest{ClusterId: id})
+	if err != nil {
+		return result.Cluster, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "ElasticMapReduce.DescribeCluster"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Cluster, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Cluster, err
+}
+
+// ClusterSummary is a cluster ListClusters found.
+type ClusterSummary struct {
+	Id                      string
+	Name                    string
+	Status                  ClusterStatus
+	NormalizedInstanceHours int
+}
+
+type listClustersRequest struct {
+	ClusterStates []string `json:",omitempty"`
+	Marker        string   `json:",omitempty"`
+}
+
+type listClustersResult struct {
+	Clusters []ClusterSummary
+	Marker   string
+}
+
+// ListClusters lists the clusters in the given states, like Running and Waiting. If there are no states, every cluster is listed.
+// Clusters that terminated more than two months ago are not listed. It is calling the ListClusters API call until there are no more pages.
+// See http://docs.aws.amazon.com/ElasticMapReduce/latest/API/API_ListClusters.html for more details.
+func (s *EMRService) ListClusters(states ...string) ([]ClusterSummary, error) {
+	clusters := []ClusterSummary{}
+	input := listClustersRequest{ClusterStates: states}
+
+	for {
+		result := listClustersResult{}
+
+		bodyAsJson, err := json.Marshal(input)
+		if err != nil {
+			return clusters, err
+		}
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "ElasticMapReduce.ListClusters"
+
+		resp, err := req.Do()
+		if err != nil {
+			return clusters, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return clusters, err
+		}
+		clusters = append(clusters, result.Clusters...)
+
+		if result.Marker == "" {
+			return clusters, nil
+		}
+		input.Marker = result.Marker
+	}
+}
@@ -0,0 +1,123 @@
+package emr
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTimeline(t *testing.T) {
+	Convey("Given a cluster that is ready but has not ended", t, func() {
+		timeline := Timeline{CreationDateTime: 1438000000.5, ReadyDateTime: 1438000300}
+
+		Convey("The times are converted", func() {
+			So(timeline.Created().Equal(time.Date(2015, 7, 27, 12, 26, 40, 500000000, time.UTC)), ShouldBeTrue)
+			So(timeline.Ready().Equal(time.Date(2015, 7, 27, 12, 31, 40, 0, time.UTC)), ShouldBeTrue)
+		})
+		Convey("The end is zero", func() {
+			So(timeline.Ended().IsZero(), ShouldBeTrue)
+		})
+	})
+}
+
+func TestDescribeCluster(t *testing.T) {
+	Convey("Given a cluster", t, func() {
+		ts, s, requests := testService(testJson(`{"Cluster":{"Id":"j-1K48XXXXXXHCB","Name":"compact-archives",
+			"Status":{"State":"TERMINATED","StateChangeReason":{"Code":"ALL_STEPS_COMPLETED","Message":"Steps completed"},
+			"Timeline":{"CreationDateTime":1438000000.5,"ReadyDateTime":1438000300,"EndDateTime":1438003900}},
+			"MasterPublicDnsName":"ec2-54-0-0-1.compute-1.amazonaws.com","NormalizedInstanceHours":24,"ReleaseLabel":"emr-4.2.0",
+			"Applications":[{"Name":"Spark","Version":"1.5.2"}],"Tags":[{"Key":"team","Value":"streams"}]}}`))
+		defer ts.Close()
+
+		cluster, err := s.DescribeCluster("j-1K48XXXXXXHCB")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(cluster.Id, ShouldEqual, "j-1K48XXXXXXHCB")
+			So(cluster.Status.State, ShouldEqual, Terminated)
+			So(cluster.Status.StateChangeReason.Code, ShouldEqual, "ALL_STEPS_COMPLETED")
+			So(cluster.Status.Timeline.EndDateTime, ShouldEqual, 1438003900)
+			So(cluster.NormalizedInstanceHours, ShouldEqual, 24)
+			So(cluster.Applications, ShouldResemble, []Application{{Name: "Spark", Version: "1.5.2"}})
+			So(cluster.Tags, ShouldResemble, []Tag{{Key: "team", Value: "streams"}})
+		})
+		Convey("It asks for the cluster", func() {
+			So(requests()[0].Target, ShouldEqual, "ElasticMapReduce.DescribeCluster")
+			So(string(requests()[0].Body), ShouldEqual, `{"ClusterId":"j-1K48XXXXXXHCB"}`)
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.DescribeCluster("j-NOTFOUND")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.DescribeCluster("j-1K48XXXXXXHCB")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListClusters(t *testing.T) {
+	Convey("Given clusters that take two pages", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			request := listClustersRequest{}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.Marker == "page2" {
+				w.Write([]byte(`{"Clusters":[{"Id":"j-2","Name":"index","Status":{"State":"WAITING"}}]}`))
+				return
+			}
+			w.Write([]byte(`{"Clusters":[{"Id":"j-1","Name":"compact-archives","Status":{"State":"RUNNING"}}],"Marker":"page2"}`))
+		})
+		defer ts.Close()
+
+		clusters, err := s.ListClusters(Running, Waiting)
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(clusters), ShouldEqual, 2)
+			So(clusters[0].Id, ShouldEqual, "j-1")
+			So(clusters[1].Status.State, ShouldEqual, Waiting)
+		})
+		Convey("It asks for the states", func() {
+			So(requests()[0].Target, ShouldEqual, "ElasticMapReduce.ListClusters")
+			So(string(requests()[0].Body), ShouldEqual, `{"ClusterStates":["RUNNING","WAITING"]}`)
+			So(string(requests()[1].Body), ShouldEqual, `{"ClusterStates":["RUNNING","WAITING"],"Marker":"page2"}`)
+		})
+	})
+	Convey("Given no states", t, func() {
+		ts, s, requests := testService(testJson(`{"Clusters":[]}`))
+		defer ts.Close()
+
+		clusters, err := s.ListClusters()
+
+		Convey("Every cluster is asked for", func() {
+			So(err, ShouldBeNil)
+			So(len(clusters), ShouldEqual, 0)
+			So(string(requests()[0].Body), ShouldEqual, `{}`)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.ListClusters()
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+}
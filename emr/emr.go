@@ -0,0 +1,107 @@
+// Package emr provides a way to interact with the AWS Elastic MapReduce service.
+package emr
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type emrError -field Type -service EMR InternalServerException InvalidRequestException
+
+// emrError is the error document returned from the EMR service.
+type emrError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the emrError into an error message.
+func (e emrError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e emrError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func emrRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := emrError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// EMRService is the EMR service at AWS. The endpoint for a region is gaws.Endpoint("elasticmapreduce", region).
+type EMRService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// EMRAPI is implemented by *EMRService. Code that takes an EMRAPI can be given a fake in tests instead.
+type EMRAPI interface {
+	AddJobFlowSteps(jobFlowId string, steps ...StepConfig) ([]string, error)
+	DescribeCluster(id string) (Cluster, error)
+	ListClusters(states ...string) ([]ClusterSummary, error)
+	RunJobFlow(input JobFlowInput) (string, error)
+	TerminateJobFlows(jobFlowIds ...string) error
+}
+
+var _ EMRAPI = (*EMRService)(nil)
+
+// New returns an EMRService with the options applied. Its endpoint is gaws.Endpoint("elasticmapreduce", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *EMRService {
+	config := gaws.NewConfig(options...)
+	return &EMRService{Endpoint: config.ServiceEndpoint("elasticmapreduce"), config: config}
+}
+
+func (s *EMRService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: emrRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("elasticmapreduce", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Tag is a key and value that labels a cluster.
+type Tag struct {
+	Key   string
+	Value string `json:",omitempty"`
+}
+
+// seconds returns a time EMR gives as seconds since the epoch, or the zero time if it is 0.
+func seconds(t float64) time.Time {
+	if t == 0 {
+		return time.Time{}
+	}
+	whole, fraction := math.Modf(t)
+	return time.Unix(int64(whole), int64(fraction*1e9))
+}
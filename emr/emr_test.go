@@ -0,0 +1,118 @@
+package emr
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{}"))
+}
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var invalidRequestError = emrError{Type: "InvalidRequestException", Message: "Cluster id 'j-NOTFOUND' is not valid."}
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(invalidRequestError)
+
+	w.WriteHeader(400)
+	w.Write(b)
+}
+
+// testJson returns a handler that returns body.
+func testJson(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Target string // The X-Amz-Target header
+	Body   []byte
+}
+
+// testService returns a service on a server that runs handler, and a function that returns the requests it has gotten so far.
+func testService(handler http.HandlerFunc) (*httptest.Server, *EMRService, func() []testRequest) {
+	var lock sync.Mutex
+	requests := []testRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		lock.Lock()
+		requests = append(requests, testRequest{Target: r.Header.Get("X-Amz-Target"), Body: body})
+		lock.Unlock()
+		handler(w, r)
+	}))
+
+	return ts, &EMRService{Endpoint: ts.URL}, func() []testRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]testRequest{}, requests...)
+	}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := emrRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a throttling error", t, func() {
+		result, _ := emrRetryPredicate(400, []byte(`{"__type":"ThrottlingException","message":"Rate exceeded"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given an invalid request", t, func() {
+		result, err := emrRetryPredicate(400, []byte(`{"__type":"InvalidRequestException","message":"Cluster id 'j-NOTFOUND' is not valid."}`))
+
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+	Convey("Given a server error", t, func() {
+		result, _ := emrRetryPredicate(500, []byte(`{"__type":"InternalServerError"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("elasticmapreduce", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
@@ -0,0 +1,17 @@
+// Code generated by errgen from the go:generate line in emr.go. DO NOT EDIT.
+
+package emr
+
+import "strings"
+
+// These are errors EMR returns. errors.Is(err, emr.ErrInternalServer) is true for any error with the same type, whatever its message.
+var (
+	ErrInternalServer = emrError{Type: "InternalServerException"}
+	ErrInvalidRequest = emrError{Type: "InvalidRequestException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e emrError) Is(target error) bool {
+	t, ok := target.(emrError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
@@ -0,0 +1,140 @@
+package emr
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// What happens to a cluster when one of its steps fails.
+const (
+	TerminateCluster = "TERMINATE_CLUSTER"
+	CancelAndWait    = "CANCEL_AND_WAIT" // Cancel the steps after it and wait for more
+	Continue         = "CONTINUE"
+)
+
+// HadoopJarStep is a JAR a step runs, with its arguments.
+type HadoopJarStep struct {
+	Jar       string   // Like s3://archives/jobs/compact.jar, or command-runner.jar to run a command like spark-submit
+	MainClass string   `json:",omitempty"` // If it is empty, the JAR's manifest says what to run
+	Args      []string `json:",omitempty"`
+}
+
+// StepConfig is a step for a cluster to run.
+type StepConfig struct {
+	Name            string
+	ActionOnFailure string `json:",omitempty"` // TerminateCluster, CancelAndWait or Continue
+	HadoopJarStep   HadoopJarStep
+}
+
+// Application is software installed on a cluster, like Hadoop or Spark.
+type Application struct {
+	Name    string
+	Version string `json:",omitempty"` // Set by EMR, from the release label
+}
+
+// JobFlowInstancesConfig says which instances a cluster runs on.
+type JobFlowInstancesConfig struct {
+	MasterInstanceType          string
+	SlaveInstanceType           string `json:",omitempty"`
+	InstanceCount               int    // Including the master
+	Ec2KeyName                  string `json:",omitempty"`
+	Ec2SubnetId                 string `json:",omitempty"`
+	KeepJobFlowAliveWhenNoSteps bool   // If it is false, the cluster terminates once its steps are done
+	TerminationProtected        bool
+}
+
+// JobFlowInput is the input to RunJobFlow.
+type JobFlowInput struct {
+	Name              string
+	LogUri            string `json:",omitempty"` // Where logs are written, like s3://archives/logs/
+	ReleaseLabel      string `json:",omitempty"` // Like emr-4.2.0
+	Instances         JobFlowInstancesConfig
+	Steps             []StepConfig  `json:",omitempty"`
+	Applications      []Application `json:",omitempty"`
+	JobFlowRole       string        `json:",omitempty"` // The instance profile of the instances, like EMR_EC2_DefaultRole
+	ServiceRole       string        `json:",omitempty"` // The role EMR uses, like EMR_DefaultRole
+	VisibleToAllUsers bool
+	Tags              []Tag `json:",omitempty"`
+}
+
+type runJobFlowResult struct {
+	JobFlowId string
+}
+
+// RunJobFlow starts a cluster, which runs the input's steps. It returns the ID of the cluster. It is calling the RunJobFlow API call.
+// See http://docs.aws.amazon.com/ElasticMapReduce/latest/API/API_RunJobFlow.html for more details.
+func (s *EMRService) RunJobFlow(input JobFlowInput) (string, error) {
+	result := runJobFlowResult{}
+
+	bodyAsJson, err := json.Marshal(input)
+	if err != nil {
+		return result.JobFlowId, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "ElasticMapReduce.RunJobFlow"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.JobFlowId, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.JobFlowId, err
+}
+
+type addJobFlowStepsRequest struct {
+	JobFlowId string
+	Steps     []StepConfig
+}
+
+type addJobFlowStepsResult struct {
+	StepIds []string
+}
+
+// AddJobFlowSteps adds steps to a running cluster, which runs them after its other steps. It returns the IDs of the steps.
+// It is calling the AddJobFlowSteps API call.
+// See http://docs.aws.amazon.com/ElasticMapReduce/latest/API/API_AddJobFlowSteps.html for more details.
+func (s *EMRService) AddJobFlowSteps(jobFlowId string, steps ...StepConfig) ([]string, error) {
+	result := addJobFlowStepsResult{}
+
+	bodyAsJson, err := json.Marshal(addJobFlowStepsRequest{JobFlowId: jobFlowId, Steps: steps})
+	if err != nil {
+		return result.StepIds, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "ElasticMapReduce.AddJobFlowSteps"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.StepIds, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.StepIds, err
+}
+
+type terminateJobFlowsRequest struct {
+	JobFlowIds []string
+}
+
+// TerminateJobFlows terminates clusters, unless they are termination protected. Steps that are running are stopped.
+// It is calling the TerminateJobFlows API call.
+// See http://docs.aws.amazon.com/ElasticMapReduce/latest/API/API_TerminateJobFlows.html for more details.
+func (s *EMRService) TerminateJobFlows(jobFlowIds ...string) error {
+	bodyAsJson, err := json.Marshal(terminateJobFlowsRequest{JobFlowIds: jobFlowIds})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "ElasticMapReduce.TerminateJobFlows"
+
+	_, err = req.Do()
+	return err
+}
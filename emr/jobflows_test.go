@@ -0,0 +1,132 @@
+package emr
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRunJobFlow(t *testing.T) {
+	Convey("Given a cluster with a step", t, func() {
+		ts, s, requests := testService(testJson(`{"JobFlowId":"j-1K48XXXXXXHCB"}`))
+		defer ts.Close()
+
+		id, err := s.RunJobFlow(JobFlowInput{
+			Name:         "compact-archives",
+			LogUri:       "s3://archives/logs/",
+			ReleaseLabel: "emr-4.2.0",
+			Instances:    JobFlowInstancesConfig{MasterInstanceType: "m3.xlarge", SlaveInstanceType: "m3.xlarge", InstanceCount: 3},
+			Steps: []StepConfig{{
+				Name:            "compact",
+				ActionOnFailure: TerminateCluster,
+				HadoopJarStep:   HadoopJarStep{Jar: "command-runner.jar", Args: []string{"spark-submit", "s3://archives/jobs/compact.py"}},
+			}},
+			Applications: []Application{{Name: "Spark"}},
+			JobFlowRole:  "EMR_EC2_DefaultRole",
+			ServiceRole:  "EMR_DefaultRole",
+		})
+
+		Convey("It returns the ID of the cluster", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "j-1K48XXXXXXHCB")
+		})
+		Convey("It sends the input", func() {
+			So(requests()[0].Target, ShouldEqual, "ElasticMapReduce.RunJobFlow")
+
+			body := map[string]interface{}{}
+			json.Unmarshal(requests()[0].Body, &body)
+			So(body["Name"], ShouldEqual, "compact-archives")
+			So(body["ReleaseLabel"], ShouldEqual, "emr-4.2.0")
+			So(body["Instances"].(map[string]interface{})["InstanceCount"], ShouldEqual, 3)
+			So(body["Applications"], ShouldResemble, []interface{}{map[string]interface{}{"Name": "Spark"}})
+
+			step := body["Steps"].([]interface{})[0].(map[string]interface{})
+			So(step["ActionOnFailure"], ShouldEqual, "TERMINATE_CLUSTER")
+			So(step["HadoopJarStep"], ShouldResemble, map[string]interface{}{
+				"Jar":  "command-runner.jar",
+				"Args": []interface{}{"spark-submit", "s3://archives/jobs/compact.py"},
+			})
+			_, hasTags := body["Tags"]
+			So(hasTags, ShouldBeFalse)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.RunJobFlow(JobFlowInput{Name: "compact-archives"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.RunJobFlow(JobFlowInput{Name: "compact-archives"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestAddJobFlowSteps(t *testing.T) {
+	Convey("Given steps for a cluster", t, func() {
+		ts, s, requests := testService(testJson(`{"StepIds":["s-1","s-2"]}`))
+		defer ts.Close()
+
+		ids, err := s.AddJobFlowSteps("j-1K48XXXXXXHCB",
+			StepConfig{Name: "compact", HadoopJarStep: HadoopJarStep{Jar: "s3://archives/jobs/compact.jar"}},
+			StepConfig{Name: "index", ActionOnFailure: Continue, HadoopJarStep: HadoopJarStep{Jar: "s3://archives/jobs/index.jar", MainClass: "Index"}},
+		)
+
+		Convey("It returns the IDs of the steps", func() {
+			So(err, ShouldBeNil)
+			So(ids, ShouldResemble, []string{"s-1", "s-2"})
+		})
+		Convey("It sends the steps", func() {
+			So(requests()[0].Target, ShouldEqual, "ElasticMapReduce.AddJobFlowSteps")
+			So(string(requests()[0].Body), ShouldEqual, `{"JobFlowId":"j-1K48XXXXXXHCB","Steps":[`+
+				`{"Name":"compact","HadoopJarStep":{"Jar":"s3://archives/jobs/compact.jar"}},`+
+				`{"Name":"index","ActionOnFailure":"CONTINUE","HadoopJarStep":{"Jar":"s3://archives/jobs/index.jar","MainClass":"Index"}}]}`)
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.AddJobFlowSteps("j-NOTFOUND")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+}
+
+func TestTerminateJobFlows(t *testing.T) {
+	Convey("Given clusters", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.TerminateJobFlows("j-1", "j-2")
+
+		Convey("They are terminated", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "ElasticMapReduce.TerminateJobFlows")
+			So(string(requests()[0].Body), ShouldEqual, `{"JobFlowIds":["j-1","j-2"]}`)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		err := s.TerminateJobFlows("j-NOTFOUND")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, invalidRequestError)
+		})
+	})
+}
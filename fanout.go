@@ -0,0 +1,96 @@
+package gaws
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ForRegion returns a copy of the config for another region. Its Endpoint, Endpoints and Router are left out, since they are for the
+// region of the config, so the copy uses the endpoints in the region. Everything else, like the credentials and HTTP client, is shared.
+func (c Config) ForRegion(region string) Config {
+	c.Region = region
+	c.Endpoint = ""
+	c.Endpoints = nil
+	c.Router = nil
+	return c
+}
+
+// WithConfig makes a client use a config, like one from Config.ForRegion. Options after it change the config.
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		*c = config
+	}
+}
+
+// RegionErrors are the errors of the regions that failed in a FanOut, keyed by region. errors.Is and errors.As match any of them.
+type RegionErrors map[string]error
+
+// Error formats the errors of the regions, in order of region.
+func (e RegionErrors) Error() string {
+	regions := make([]string, 0, len(e))
+	for region := range e {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	messages := make([]string, len(regions))
+	for i, region := range regions {
+		messages[i] = region + ": " + e[region].Error()
+	}
+	return "GawsRegionErrors: " + strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors of the regions, so that errors.Is and errors.As match them.
+func (e RegionErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// FanOut calls f in each region at once, with the parent config for the region, and returns the results of the regions that succeeded,
+// keyed by region. It is for making the same call in several regions, like putting a record into a stream in two regions for disaster
+// recovery:
+//
+//	_, err := gaws.FanOut(config, []string{"us-east-1", "us-west-2"}, func(region string, c gaws.Config) (struct{}, error) {
+//		stream := kinesis.Stream{Name: "orders", Service: kinesis.New(gaws.WithConfig(c))}
+//		return struct{}{}, stream.PutRecord(key, data)
+//	})
+//
+// It waits for every region. If any failed, err is the RegionErrors of those that did. A panic in f is the error of its region.
+func FanOut[T any](parent Config, regions []string, f func(region string, config Config) (T, error)) (map[string]T, error) {
+	lock := sync.Mutex{}
+	results := map[string]T{}
+	errs := RegionErrors{}
+
+	wg := sync.WaitGroup{}
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+
+			var result T
+			err := Protect("gaws.FanOut", func() error {
+				var err error
+				result, err = f(region, parent.ForRegion(region))
+				return err
+			})
+
+			lock.Lock()
+			defer lock.Unlock()
+			if err != nil {
+				errs[region] = err
+			} else {
+				results[region] = result
+			}
+		}(region)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return results, errs
+	}
+	return results, nil
+}
@@ -0,0 +1,85 @@
+package gaws
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestForRegion(t *testing.T) {
+	Convey("Given a config with endpoints", t, func() {
+		c := NewConfig(WithRegion("us-east-1"), WithEndpoint("http://localhost:4567"), WithMaxResponseSize(10))
+		c.Endpoints = map[string]string{"sqs": "http://localhost:4568"}
+
+		Convey("The config for another region uses the endpoints in that region", func() {
+			r := c.ForRegion("eu-west-1")
+			So(r.Region, ShouldEqual, "eu-west-1")
+			So(r.ServiceEndpoint("kinesis"), ShouldEqual, Endpoint("kinesis", "eu-west-1"))
+			So(r.ServiceEndpoint("sqs"), ShouldEqual, Endpoint("sqs", "eu-west-1"))
+		})
+		Convey("It keeps the rest of the config", func() {
+			So(c.ForRegion("eu-west-1").MaxResponseSize, ShouldEqual, 10)
+		})
+		Convey("The config is unchanged", func() {
+			So(c.Region, ShouldEqual, "us-east-1")
+			So(c.Endpoint, ShouldEqual, "http://localhost:4567")
+		})
+	})
+}
+
+func TestWithConfig(t *testing.T) {
+	Convey("Given a config and options after it", t, func() {
+		c := NewConfig(WithConfig(NewConfig(WithRegion("eu-west-1"), WithMaxResponseSize(10))), WithMaxResponseSize(20))
+
+		Convey("The options change the config", func() {
+			So(c.Region, ShouldEqual, "eu-west-1")
+			So(c.MaxResponseSize, ShouldEqual, 20)
+		})
+	})
+}
+
+func TestFanOut(t *testing.T) {
+	Convey("Given calls in three regions", t, func() {
+		parent := NewConfig(WithRegion("us-east-1"))
+		notFound := errors.New("not found")
+
+		results, err := FanOut(parent, []string{"us-east-1", "us-west-2", "eu-west-1"}, func(region string, c Config) (string, error) {
+			switch region {
+			case "us-west-2":
+				return "", notFound
+			case "eu-west-1":
+				panic("nope")
+			}
+			return c.Region, nil
+		})
+
+		Convey("Each region gets its own config", func() {
+			So(results, ShouldResemble, map[string]string{"us-east-1": "us-east-1"})
+		})
+		Convey("The errors of the regions that failed are returned", func() {
+			errs, ok := err.(RegionErrors)
+			So(ok, ShouldBeTrue)
+			So(len(errs), ShouldEqual, 2)
+			So(errs["us-west-2"], ShouldEqual, notFound)
+			So(errors.Is(err, notFound), ShouldBeTrue)
+		})
+		Convey("A panic is the error of its region", func() {
+			var panicErr *PanicError
+			So(errors.As(err, &panicErr), ShouldBeTrue)
+			So(err.Error(), ShouldStartWith, "GawsRegionErrors: eu-west-1: GawsPanic: ")
+			So(err.Error(), ShouldContainSubstring, "; us-west-2: not found")
+		})
+	})
+
+	Convey("Given calls that all succeed", t, func() {
+		results, err := FanOut(Config{}, []string{"us-east-1", "us-west-2"}, func(region string, c Config) (int, error) {
+			return len(region), nil
+		})
+
+		Convey("There is no error", func() {
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 2)
+		})
+	})
+}
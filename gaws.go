@@ -3,18 +3,24 @@ package gaws
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
-	"math"
 	"net/http"
+	"net/http/httptrace"
 	"time"
 
 	"github.com/smartystreets/go-aws-auth"
 )
 
-// MaxTries is the number of times to retry a failing AWS request.
+// MaxTries is the number of times to retry a failing AWS request. Requests are tried one time fewer than it, so the default of 5 tries them
+// 4 times. RetryPolicy.MaxTries is the exact number of tries.
 var MaxTries int = 5
 
+// Transport sends the HTTP requests gaws makes. If it is nil, http.DefaultTransport is used. It can be wrapped to change how requests are sent,
+// like with gawstest.FaultTransport to make some of them fail.
+var Transport http.RoundTripper
+
 // gawsError is the error document returned from many AWS requests.
 type gawsError struct {
 	Type    string `json:"__type"`
@@ -30,57 +36,261 @@ func (e gawsError) Error() string {
 
 type retryPredicate func(int, []byte) (bool, error)
 
+// signer signs a request, like awsauth.Sign4.
+type signer func(*http.Request, ...awsauth.Credentials) *http.Request
+
 // AWSRequest is a request to AWS. It is used instead of http.Request to facilitate retries.
+// Requests to REST services, like S3's, can be built with WithMethod, WithPath and WithQuery.
 type AWSRequest struct {
 	RetryPredicate retryPredicate
 	URL            string
 	Method         string
 	Headers        map[string]string
 	Body           []byte
+	Signer         signer              // How the request is signed. If it is nil, awsauth.Sign picks the signature version for the service.
+	Credentials    CredentialsProvider // The credentials the request is signed with. If it is nil, gaws.Credentials is used.
+	Timeout        time.Duration       // How long each try can take, including reading the response. If it is 0, there is no limit.
+	HTTPClient     *http.Client        // Sends the request. If it is nil, a client using Transport is used.
+	RetryPolicy    RetryPolicy         // How the request is retried if it fails
+	Context        context.Context     // Cancels the request, and any wait before a retry. If it is nil, the request cannot be canceled.
+
+	MaxResponseSize int64     // The most bytes of the response to read. If it is 0, MaxResponseSize is used.
+	DryRun          DryRunner // If it is set, the request is not sent, but built, signed and given to it, and gets an empty response
+	Clock           Clock     // Times the request, and waits before retries. If it is nil, DefaultClock is used.
+
+	err error // Why the request cannot be made, from building it with WithPath or WithQuery
+}
+
+// context returns the context of the request.
+func (r *AWSRequest) context() context.Context {
+	if r.Context == nil {
+		return context.Background()
+	}
+	return r.Context
+}
+
+// clock returns the clock of the request.
+func (r *AWSRequest) clock() Clock {
+	if r.Clock == nil {
+		return DefaultClock
+	}
+	return r.Clock
+}
+
+// wait waits before the retry after a try fails, and returns how long it waited. last is how long it waited after the try before.
+// It returns early with the error of the context if the request is canceled.
+func (r *AWSRequest) wait(try int, last time.Duration) (time.Duration, error) {
+	backoff := r.RetryPolicy.Delay(try, last)
+	stats.backoff.Store(int64(backoff))
+	stats.waiting.Add(1)
+	defer stats.waiting.Add(-1)
+
+	select {
+	case <-r.clock().After(backoff):
+		return backoff, nil
+	case <-r.context().Done():
+		return backoff, r.context().Err()
+	}
+}
+
+// client returns the http.Client to send the request with.
+func (r *AWSRequest) client() *http.Client {
+	if r.HTTPClient == nil {
+		return &http.Client{Transport: Transport, Timeout: r.Timeout}
+	}
+	if r.Timeout == 0 {
+		return r.HTTPClient
+	}
+	client := *r.HTTPClient
+	client.Timeout = r.Timeout
+	return &client
 }
 
-func (r *AWSRequest) getRequest() *http.Request {
+func (r *AWSRequest) getRequest(keys ...awsauth.Credentials) *http.Request {
 
 	payload := bytes.NewReader(r.Body)
-	req, _ := http.NewRequest(r.Method, r.URL, payload)
+	req, _ := http.NewRequestWithContext(httptrace.WithClientTrace(r.context(), connectionTrace), r.Method, r.URL, payload)
 
 	for k, v := range r.Headers {
 		req.Header.Set(k, v)
 	}
 
-	awsauth.Sign(req)
+	if r.Signer != nil {
+		r.Signer(req, keys...)
+	} else {
+		awsauth.Sign(req, keys...)
+	}
 	return req
 }
 
-// Do makes the request to AWS and retries with an exponential backoff.
+// Do makes the request to AWS and retries with the backoff of its RetryPolicy, which is exponential unless it says otherwise.
 func (r *AWSRequest) Do() ([]byte, error) {
-	client := &http.Client{}
+	if r.err != nil {
+		return make([]byte, 0), r.err
+	}
+	if r.DryRun != nil {
+		resp, err := r.dryRun()
+		if err != nil {
+			return make([]byte, 0), err
+		}
+		defer resp.Body.Close()
+		return ioutil.ReadAll(resp.Body)
+	}
+
+	stats.inFlight.Add(1)
+	defer stats.inFlight.Add(-1)
+
+	m := RequestMetrics{Operation: r.operation(), Endpoint: r.host()}
+	start := r.clock().Now()
+	body, err := r.do(&m)
+	if budget := r.RetryPolicy.budget(); budget != nil {
+		m.RetryBudgetAvailable = budget.Available()
+	}
+	countDone(m, err)
+	report(m, r.clock().Now().Sub(start), err)
+	r.audit(start, err)
+	return body, err
+}
+
+func (r *AWSRequest) do(m *RequestMetrics) ([]byte, error) {
+	client := r.client()
 	var lastBody []byte
 
-	for try := 1; try < MaxTries; try++ {
-		req := r.getRequest()
+	keys, err := r.credentials()
+	if err != nil {
+		return make([]byte, 0), err
+	}
+
+	budget := r.RetryPolicy.budget()
+	spent := 0
+	var waited time.Duration
+
+	for try := 1; try <= r.RetryPolicy.Tries(); try++ {
+		req := r.getRequest(keys...)
+		m.Tries++
 		resp, err := client.Do(req)
 
 		if err != nil {
 			return make([]byte, 0), err
 		}
+		m.Protocol = resp.Proto
 		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
+		body, err := r.readBody(resp)
 
 		if err != nil {
 			return body, err
 		}
+		throttled := isThrottle(resp.StatusCode, body)
+		if throttled {
+			m.Throttles++
+		}
 
 		shouldRetry, err := r.RetryPredicate(resp.StatusCode, body)
+		if shouldRetry && budget != nil {
+			cost, ok := budget.take(throttled)
+			if !ok {
+				m.RetryBudgetExhausted = true
+				return body, err
+			}
+			spent += cost
+		}
 		if shouldRetry {
 			lastBody = body
 
-			// Exponential backoff for the retry
-			sleepDuration := time.Duration(100 * math.Pow(2.0, float64(try)))
-			time.Sleep(sleepDuration * time.Millisecond)
+			// Back off before the retry
+			if waited, err = r.wait(try, waited); err != nil {
+				return body, err
+			}
 		} else {
+			if err == nil && budget != nil {
+				budget.succeeded(spent)
+			}
 			return body, err
 		}
 	}
 	return lastBody, exceededRetriesError
 }
+
+// DoResponse makes the request to AWS like Do, but returns a successful response without reading its body, so the body can be streamed.
+// Responses with a status below 400 are successful. The caller must close the body.
+func (r *AWSRequest) DoResponse() (*http.Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.DryRun != nil {
+		return r.dryRun()
+	}
+
+	stats.inFlight.Add(1)
+	defer stats.inFlight.Add(-1)
+
+	m := RequestMetrics{Operation: r.operation(), Endpoint: r.host()}
+	start := r.clock().Now()
+	resp, err := r.doResponse(&m)
+	if budget := r.RetryPolicy.budget(); budget != nil {
+		m.RetryBudgetAvailable = budget.Available()
+	}
+	countDone(m, err)
+	report(m, r.clock().Now().Sub(start), err)
+	r.audit(start, err)
+	return resp, err
+}
+
+func (r *AWSRequest) doResponse(m *RequestMetrics) (*http.Response, error) {
+	client := r.client()
+
+	keys, err := r.credentials()
+	if err != nil {
+		return nil, err
+	}
+
+	budget := r.RetryPolicy.budget()
+	spent := 0
+	var waited time.Duration
+
+	for try := 1; try <= r.RetryPolicy.Tries(); try++ {
+		req := r.getRequest(keys...)
+		m.Tries++
+		resp, err := client.Do(req)
+
+		if err != nil {
+			return nil, err
+		}
+		m.Protocol = resp.Proto
+		if resp.StatusCode < 400 {
+			if budget != nil {
+				budget.succeeded(spent)
+			}
+			return resp, nil
+		}
+
+		body, err := r.readBody(resp)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		throttled := isThrottle(resp.StatusCode, body)
+		if throttled {
+			m.Throttles++
+		}
+
+		shouldRetry, err := r.RetryPredicate(resp.StatusCode, body)
+		if !shouldRetry {
+			return nil, err
+		}
+		if budget != nil {
+			cost, ok := budget.take(throttled)
+			if !ok {
+				m.RetryBudgetExhausted = true
+				return nil, err
+			}
+			spent += cost
+		}
+
+		// Back off before the retry
+		if waited, err = r.wait(try, waited); err != nil {
+			return nil, err
+		}
+	}
+	return nil, exceededRetriesError
+}
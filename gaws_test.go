@@ -1,11 +1,15 @@
 package gaws
 
 import (
+	"context"
 	"encoding/json"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/smartystreets/go-aws-auth"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -123,6 +127,8 @@ func TestFailNoRetry(t *testing.T) {
 
 func TestThrottleRetry(t *testing.T) {
 	Convey("Given a server that only returns 400 errors with the Trottle type", t, func() {
+		_, restore := useTestClock()
+		defer restore()
 
 		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
 		defer ts.Close()
@@ -172,3 +178,177 @@ func TestBadRequest(t *testing.T) {
 		})
 	})
 }
+
+func TestSigner(t *testing.T) {
+	Convey("Given a request with a signer", t, func() {
+		r := canonicalRequest()
+		r.URL = "http://www.google.com"
+		r.Signer = func(req *http.Request, credentials ...awsauth.Credentials) *http.Request {
+			req.Header.Set("Authorization", "signed")
+			return req
+		}
+		req := r.getRequest()
+
+		Convey("It is signed by the signer", func() {
+			So(req.Header.Get("Authorization"), ShouldEqual, "signed")
+		})
+	})
+}
+
+func TestDoResponse(t *testing.T) {
+	Convey("Given a server that returns 200s", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		resp, err := r.DoResponse()
+
+		Convey("It returns the response with its body unread", func() {
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			body, _ := ioutil.ReadAll(resp.Body)
+			So(string(body), ShouldEqual, "OK")
+		})
+	})
+	Convey("Given a server that returns 404 errors with proper JSON", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		resp, err := r.DoResponse()
+
+		Convey("It returns the error and no response", func() {
+			So(resp, ShouldBeNil)
+			So(err.Error(), ShouldEqual, notFoundError.Error())
+		})
+	})
+	Convey("Given a server that only throttles", t, func() {
+		_, restore := useTestClock()
+		defer restore()
+
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		_, err := r.DoResponse()
+
+		Convey("It returns an exceeded retries error", func() {
+			So(err.Error(), ShouldEqual, exceededRetriesError.Error())
+		})
+	})
+	Convey("Given a nonexistent host", t, func() {
+		r := canonicalRequest()
+		r.URL = "this will not work"
+
+		_, err := r.DoResponse()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	Convey("Given a request with a timeout to a server that takes longer", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte("OK"))
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.Timeout = 50 * time.Millisecond
+
+		Convey("Do gives up", func() {
+			_, err := r.Do()
+			So(err, ShouldNotBeNil)
+		})
+		Convey("DoResponse gives up", func() {
+			_, err := r.DoResponse()
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Without the timeout, it waits", func() {
+			r.Timeout = 0
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "OK")
+		})
+	})
+}
+
+// testRoundTripper counts the requests it sends with http.DefaultTransport.
+type testRoundTripper struct {
+	requests int
+}
+
+func (t *testRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	t.requests++
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestTransport(t *testing.T) {
+	Convey("Given a transport", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("OK"))
+		}))
+		defer ts.Close()
+
+		transport := &testRoundTripper{}
+		Transport = transport
+		defer func() { Transport = nil }()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("Do sends the request with it", func() {
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "OK")
+			So(transport.requests, ShouldEqual, 1)
+		})
+		Convey("DoResponse sends the request with it", func() {
+			resp, err := r.DoResponse()
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+			So(transport.requests, ShouldEqual, 1)
+		})
+	})
+}
+
+func TestContext(t *testing.T) {
+	Convey("Given a request to a server that always throttles, with a long wait before retries", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.RetryPolicy = RetryPolicy{Backoff: time.Hour}
+		r.Context = ctx
+
+		Convey("Canceling it stops Do waiting", func() {
+			time.AfterFunc(50*time.Millisecond, cancel)
+			_, err := r.Do()
+			So(err, ShouldEqual, context.Canceled)
+		})
+		Convey("Canceling it stops DoResponse waiting", func() {
+			time.AfterFunc(50*time.Millisecond, cancel)
+			_, err := r.DoResponse()
+			So(err, ShouldEqual, context.Canceled)
+		})
+		Convey("A canceled request is not sent", func() {
+			cancel()
+			_, err := r.Do()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+package gawstest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a gaws.Clock that does not really wait. Waiting on it moves its time forward at once, so code that backs off or polls runs
+// as fast as it can. Use it with gaws.WithClock, or by setting gaws.DefaultClock to it. The waits it was asked for are kept, to check how
+// code backed off. Loops that run in the background, like the flushes of a buffer, would spin on it, so use a ManualClock for them.
+// It is safe for concurrent use.
+type Clock struct {
+	lock   sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+// NewClock returns a clock whose time starts at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the clock's time.
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// After moves the clock's time forward by the duration, and returns a channel that already has the new time on it.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+	c.sleeps = append(c.sleeps, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+// Advance moves the clock's time forward by the duration without counting it as a wait.
+func (c *Clock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleeps returns the waits the clock was asked for, in order.
+func (c *Clock) Sleeps() []time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
+
+// ManualClock is a gaws.Clock whose time only moves when a test advances it. Waits on it end once its time reaches them, so tests decide
+// when loops that run in the background, like the flushes of a buffer, go around. Use it with gaws.WithClock.
+// It is safe for concurrent use.
+type ManualClock struct {
+	lock    sync.Mutex
+	changed *sync.Cond // Told when a wait starts
+	now     time.Time
+	waits   []manualWait
+	sleeps  []time.Duration
+}
+
+// manualWait is a wait on a ManualClock that has not ended.
+type manualWait struct {
+	until time.Time
+	c     chan time.Time
+}
+
+// NewManualClock returns a manual clock whose time starts at now.
+func NewManualClock(now time.Time) *ManualClock {
+	c := &ManualClock{now: now}
+	c.changed = sync.NewCond(&c.lock)
+	return c
+}
+
+// Now returns the clock's time.
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// After returns a channel that gets the clock's time once it has been advanced by the duration.
+func (c *ManualClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.sleeps = append(c.sleeps, d)
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waits = append(c.waits, manualWait{until: c.now.Add(d), c: ch})
+	c.changed.Broadcast()
+	return ch
+}
+
+// Advance moves the clock's time forward by the duration, and ends the waits it reaches.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+
+	waiting := c.waits[:0]
+	for _, w := range c.waits {
+		if w.until.After(c.now) {
+			waiting = append(waiting, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waits = waiting
+}
+
+// BlockUntil returns once n waits on the clock have started and not ended, so a test can advance it knowing what it ends.
+func (c *ManualClock) BlockUntil(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for len(c.waits) < n {
+		c.changed.Wait()
+	}
+}
+
+// Sleeps returns the waits the clock was asked for, in order, including the ones that have not ended.
+func (c *ManualClock) Sleeps() []time.Duration {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
@@ -0,0 +1,91 @@
+package gawstest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/kinesis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClock(t *testing.T) {
+	Convey("Given a clock", t, func() {
+		start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewClock(start)
+
+		Convey("It starts at its time", func() {
+			So(c.Now(), ShouldEqual, start)
+		})
+		Convey("Waiting on it moves its time forward at once", func() {
+			now := <-c.After(time.Hour)
+			So(now, ShouldEqual, start.Add(time.Hour))
+			So(c.Now(), ShouldEqual, start.Add(time.Hour))
+			So(c.Sleeps(), ShouldResemble, []time.Duration{time.Hour})
+		})
+		Convey("Advancing it is not a wait", func() {
+			c.Advance(time.Minute)
+			So(c.Now(), ShouldEqual, start.Add(time.Minute))
+			So(c.Sleeps(), ShouldBeEmpty)
+		})
+	})
+}
+
+func TestManualClock(t *testing.T) {
+	Convey("Given a manual clock and a wait on it", t, func() {
+		start := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+		c := NewManualClock(start)
+		wait := c.After(time.Minute)
+		c.BlockUntil(1)
+
+		Convey("The wait does not end until the clock reaches it", func() {
+			c.Advance(30 * time.Second)
+			So(wait, ShouldBeEmpty)
+			c.Advance(30 * time.Second)
+			So(<-wait, ShouldEqual, start.Add(time.Minute))
+			So(c.Now(), ShouldEqual, start.Add(time.Minute))
+		})
+		Convey("Waits for no time end at once", func() {
+			So(<-c.After(0), ShouldEqual, start)
+		})
+		Convey("The waits are kept", func() {
+			So(c.Sleeps(), ShouldResemble, []time.Duration{time.Minute})
+		})
+	})
+	Convey("Given a manual clock and a goroutine that waits on it", t, func() {
+		c := NewManualClock(time.Now())
+		done := make(chan bool)
+		go func() {
+			<-c.After(time.Second)
+			close(done)
+		}()
+
+		Convey("A test can wait for the goroutine to wait, and then end its wait", func() {
+			c.BlockUntil(1)
+			c.Advance(time.Second)
+			<-done
+		})
+	})
+}
+
+func TestClockWithGaws(t *testing.T) {
+	Convey("Given gaws using a clock and a transport that always fails", t, func() {
+		s := NewServer()
+		defer s.Close()
+
+		c := NewClock(time.Now())
+		clock := gaws.DefaultClock
+		gaws.DefaultClock = c
+		defer func() { gaws.DefaultClock = clock }()
+		gaws.Transport = &FaultTransport{ServerErrorRate: 1}
+		defer func() { gaws.Transport = nil }()
+
+		k := kinesis.KinesisService{Endpoint: s.URL}
+		_, err := k.ListStreams()
+
+		Convey("gaws backs off on the clock instead of sleeping", func() {
+			So(err, ShouldNotBeNil)
+			So(c.Sleeps(), ShouldResemble, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, 1600 * time.Millisecond})
+		})
+	})
+}
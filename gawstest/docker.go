@@ -0,0 +1,269 @@
+package gawstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// EmulatorTimeout is how long StartEmulator waits for an emulator to be ready, including pulling its image.
+var EmulatorTimeout = 2 * time.Minute
+
+// Emulator is a Docker image of an AWS emulator, and how to run it.
+type Emulator struct {
+	Image     string   // The image, like localstack/localstack:3
+	Port      int      // The port the emulator listens on in the container
+	Env       []string // Environment variables for the container, like SERVICES=kinesis
+	Cmd       []string // The command to run in the container. If it is empty, the image's command is run.
+	ReadyPath string   // A path the emulator answers with a status below 500 once it is ready. If it is empty, / is used.
+}
+
+// These are emulators of the services gaws's integration tests use.
+var (
+	Localstack    = Emulator{Image: "localstack/localstack:3", Port: 4566, ReadyPath: "/_localstack/health"}
+	Kinesalite    = Emulator{Image: "instructure/kinesalite", Port: 4567}
+	DynamoDBLocal = Emulator{Image: "amazon/dynamodb-local", Port: 8000}
+)
+
+// Container is a running emulator. Stop it when the tests are done, usually with defer.
+type Container struct {
+	ID       string // The ID of the Docker container
+	Endpoint string // The endpoint of the emulator, like http://127.0.0.1:32768
+
+	docker *dockerClient
+}
+
+// StartEmulator starts a container of the emulator with the Docker API, and waits for it to be ready. The image is pulled if Docker
+// does not have it. Docker is reached at DOCKER_HOST, which can be a unix:// or tcp:// address, or /var/run/docker.sock if it is not
+// set. The emulator's port is published on a free port of the Docker host, so emulators can run side by side.
+//
+//	c, err := gawstest.StartEmulator(gawstest.Kinesalite)
+//	if err != nil {
+//		t.Skip(err)
+//	}
+//	defer c.Stop()
+//	k := kinesis.New(gaws.WithConfig(c.Config()))
+func StartEmulator(e Emulator) (*Container, error) {
+	docker, err := newDockerClient(os.Getenv("DOCKER_HOST"))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), EmulatorTimeout)
+	defer cancel()
+
+	err = docker.do(ctx, "GET", "/images/"+e.Image+"/json", nil, nil)
+	if isNotFound(err) {
+		// Pulling streams progress until it is done, so waiting for the response to end waits for the pull.
+		err = docker.do(ctx, "POST", "/images/create?fromImage="+url.QueryEscape(e.Image), nil, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	port := fmt.Sprintf("%d/tcp", e.Port)
+	create := map[string]interface{}{
+		"Image":        e.Image,
+		"Env":          e.Env,
+		"ExposedPorts": map[string]interface{}{port: struct{}{}},
+		"HostConfig": map[string]interface{}{
+			"PortBindings": map[string]interface{}{port: []map[string]string{{"HostPort": ""}}},
+			"AutoRemove":   true,
+		},
+	}
+	if len(e.Cmd) > 0 {
+		create["Cmd"] = e.Cmd
+	}
+	var created struct {
+		Id string
+	}
+	err = docker.do(ctx, "POST", "/containers/create", create, &created)
+	if err != nil {
+		return nil, err
+	}
+	c := &Container{ID: created.Id, docker: docker}
+
+	err = docker.do(ctx, "POST", "/containers/"+c.ID+"/start", nil, nil)
+	if err != nil {
+		c.Stop()
+		return nil, err
+	}
+
+	var inspected struct {
+		NetworkSettings struct {
+			Ports map[string][]struct {
+				HostPort string
+			}
+		}
+	}
+	err = docker.do(ctx, "GET", "/containers/"+c.ID+"/json", nil, &inspected)
+	if err != nil {
+		c.Stop()
+		return nil, err
+	}
+	bindings := inspected.NetworkSettings.Ports[port]
+	if len(bindings) == 0 {
+		c.Stop()
+		return nil, fmt.Errorf("gawstest: %v did not publish port %v", e.Image, port)
+	}
+	c.Endpoint = "http://" + net.JoinHostPort(docker.host, bindings[0].HostPort)
+
+	err = waitUntilReady(ctx, c.Endpoint+readyPath(e))
+	if err != nil {
+		c.Stop()
+		return nil, fmt.Errorf("gawstest: %v was not ready: %v", e.Image, err)
+	}
+	return c, nil
+}
+
+// Config returns a config for clients of the emulator, with the options applied after it. Its requests are signed with fake
+// credentials, which emulators accept, in us-east-1.
+func (c *Container) Config(options ...gaws.Option) gaws.Config {
+	defaults := []gaws.Option{
+		gaws.WithRegion("us-east-1"),
+		gaws.WithEndpoint(c.Endpoint),
+		gaws.WithCredentials(gaws.StaticCredentials{AccessKeyID: "test", SecretAccessKey: "test"}),
+	}
+	return gaws.NewConfig(append(defaults, options...)...)
+}
+
+// Stop stops the container, which Docker then removes.
+func (c *Container) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), EmulatorTimeout)
+	defer cancel()
+
+	err := c.docker.do(ctx, "POST", "/containers/"+c.ID+"/stop?t=1", nil, nil)
+	if isNotFound(err) {
+		// It is already gone.
+		return nil
+	}
+	return err
+}
+
+// readyPath returns the path the emulator answers once it is ready.
+func readyPath(e Emulator) string {
+	if e.ReadyPath == "" {
+		return "/"
+	}
+	return e.ReadyPath
+}
+
+// waitUntilReady gets the URL until it is answered with a status below 500, or the context is done.
+func waitUntilReady(ctx context.Context, u string) error {
+	for {
+		req, _ := http.NewRequestWithContext(ctx, "GET", u, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 500 {
+				return nil
+			}
+			err = fmt.Errorf("status %v", resp.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
+
+// dockerError is an error from the Docker API.
+type dockerError struct {
+	Status  int
+	Message string
+}
+
+// Error formats the dockerError into an error message.
+func (e dockerError) Error() string {
+	return fmt.Sprintf("gawstest: Docker returned %v: %v", e.Status, e.Message)
+}
+
+// isNotFound returns true if err is the Docker API saying something does not exist.
+func isNotFound(err error) bool {
+	e, ok := err.(dockerError)
+	return ok && e.Status == 404
+}
+
+// dockerClient calls the Docker API.
+type dockerClient struct {
+	client *http.Client
+	base   string // Like http://docker, for a socket, or http://10.0.0.1:2375
+	host   string // The host containers publish their ports on
+}
+
+// newDockerClient returns a client of the Docker API at a DOCKER_HOST address.
+func newDockerClient(address string) (*dockerClient, error) {
+	if address == "" {
+		address = "unix:///var/run/docker.sock"
+	}
+
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		socket := strings.TrimPrefix(address, "unix://")
+		transport := &http.Transport{DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		}}
+		return &dockerClient{client: &http.Client{Transport: transport}, base: "http://docker", host: "127.0.0.1"}, nil
+	case strings.HasPrefix(address, "tcp://"):
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, err
+		}
+		return &dockerClient{client: http.DefaultClient, base: "http://" + u.Host, host: u.Hostname()}, nil
+	}
+	return nil, fmt.Errorf("gawstest: DOCKER_HOST %q is not a unix:// or tcp:// address", address)
+}
+
+// do calls the Docker API with body as JSON, and decodes the response into result unless it is nil.
+func (d *dockerClient) do(ctx context.Context, method string, path string, body interface{}, result interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, d.base+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 400 {
+		var message struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(b, &message)
+		return dockerError{Status: resp.StatusCode, Message: message.Message}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(b, result)
+}
@@ -0,0 +1,138 @@
+package gawstest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testDocker is a fake Docker API that runs one container, whose emulator is an httptest.Server.
+type testDocker struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	calls    []string
+	created  map[string]interface{}
+	hasImage bool
+	emulator *httptest.Server
+}
+
+func newTestDocker(hasImage bool) *testDocker {
+	d := &testDocker{hasImage: hasImage}
+	d.emulator = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+	}))
+	d.Server = httptest.NewServer(http.HandlerFunc(d.serve))
+	return d
+}
+
+func (d *testDocker) Close() {
+	d.Server.Close()
+	d.emulator.Close()
+}
+
+func (d *testDocker) Calls() []string {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return append([]string(nil), d.calls...)
+}
+
+func (d *testDocker) serve(w http.ResponseWriter, r *http.Request) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.calls = append(d.calls, r.Method+" "+r.URL.RequestURI())
+
+	switch {
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/images/"):
+		if !d.hasImage {
+			w.WriteHeader(404)
+			w.Write([]byte(`{"message":"No such image"}`))
+		}
+	case r.URL.Path == "/images/create":
+		d.hasImage = true
+		w.Write([]byte(`{"status":"Downloaded newer image"}`))
+	case r.URL.Path == "/containers/create":
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &d.created)
+		w.WriteHeader(201)
+		w.Write([]byte(`{"Id":"abc123"}`))
+	case r.URL.Path == "/containers/abc123/json":
+		u, _ := url.Parse(d.emulator.URL)
+		w.Write([]byte(`{"NetworkSettings":{"Ports":{"4567/tcp":[{"HostIp":"0.0.0.0","HostPort":"` + u.Port() + `"}]}}}`))
+	default:
+		w.WriteHeader(204)
+	}
+}
+
+func TestStartEmulator(t *testing.T) {
+	Convey("Given Docker without the emulator's image", t, func() {
+		d := newTestDocker(false)
+		defer d.Close()
+		os.Setenv("DOCKER_HOST", strings.Replace(d.URL, "http://", "tcp://", 1))
+		defer os.Unsetenv("DOCKER_HOST")
+
+		c, err := StartEmulator(Kinesalite)
+
+		Convey("The image is pulled, and the container started", func() {
+			So(err, ShouldBeNil)
+			So(c.ID, ShouldEqual, "abc123")
+			So(d.Calls(), ShouldResemble, []string{
+				"GET /images/instructure/kinesalite/json",
+				"POST /images/create?fromImage=instructure%2Fkinesalite",
+				"POST /containers/create",
+				"POST /containers/abc123/start",
+				"GET /containers/abc123/json",
+			})
+			So(d.created["Image"], ShouldEqual, "instructure/kinesalite")
+		})
+		Convey("Its endpoint is the published port", func() {
+			So(c.Endpoint, ShouldEqual, d.emulator.URL)
+		})
+		Convey("Its config is for the emulator", func() {
+			config := c.Config(gaws.WithMaxResponseSize(10))
+			So(config.Endpoint, ShouldEqual, d.emulator.URL)
+			So(config.Region, ShouldEqual, "us-east-1")
+			So(config.Credentials, ShouldResemble, gaws.StaticCredentials{AccessKeyID: "test", SecretAccessKey: "test"})
+			So(config.MaxResponseSize, ShouldEqual, 10)
+		})
+		Convey("It is stopped", func() {
+			So(c.Stop(), ShouldBeNil)
+			calls := d.Calls()
+			So(calls[len(calls)-1], ShouldEqual, "POST /containers/abc123/stop?t=1")
+		})
+	})
+
+	Convey("Given Docker with the emulator's image", t, func() {
+		d := newTestDocker(true)
+		defer d.Close()
+		os.Setenv("DOCKER_HOST", strings.Replace(d.URL, "http://", "tcp://", 1))
+		defer os.Unsetenv("DOCKER_HOST")
+
+		_, err := StartEmulator(Kinesalite)
+
+		Convey("It is not pulled", func() {
+			So(err, ShouldBeNil)
+			So(d.Calls(), ShouldNotContain, "POST /images/create?fromImage=instructure%2Fkinesalite")
+		})
+	})
+
+	Convey("Given a DOCKER_HOST that is not supported", t, func() {
+		os.Setenv("DOCKER_HOST", "ssh://docker.example.com")
+		defer os.Unsetenv("DOCKER_HOST")
+
+		_, err := StartEmulator(Kinesalite)
+
+		Convey("It fails", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
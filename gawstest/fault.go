@@ -0,0 +1,222 @@
+package gawstest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// errConnectionDropped is returned for requests a FaultTransport drops.
+var errConnectionDropped = errors.New("gawstest: connection dropped")
+
+// FaultCounts are how many requests a FaultTransport has seen, and how many of them it made fail or delayed.
+type FaultCounts struct {
+	Requests     int
+	Delayed      int
+	Throttled    int
+	ServerErrors int
+	Dropped      int
+}
+
+// FaultTransport is an http.RoundTripper that makes some requests fail, to test how code copes with AWS being slow, throttling or failing.
+// Use it by setting gaws.Transport to it. Rates are chances from 0 to 1. A request is delayed with LatencyRate, and then dropped with DropRate,
+// throttled with ThrottleRate or given a server error with ServerErrorRate, in that order. Requests that do not fail are sent with Transport.
+//
+// Throttling and server errors are sent as JSON to requests with an X-Amz-Target header or a JSON body, and as XML to the rest. Throttling
+// is an error the service's package retries: ProvisionedThroughputExceededException for Kinesis, TooManyRequestsException for Cognito,
+// RequestLimitExceeded for EC2, SlowDown for S3, a 429 TooManyRequestsException for Lambda and Elastic Transcoder, and ThrottlingException
+// or Throttling for the rest. The service is told by the scope of the request's signature, its X-Amz-Target or its host, so requests to an
+// emulator on localhost that are not signed and are not to Kinesis or Cognito get ThrottlingException or Throttling. SimpleDB does not
+// retry throttling at all, so throttling it tests how its callers handle the error.
+type FaultTransport struct {
+	Transport http.RoundTripper // Sends the requests that do not fail. If it is nil, http.DefaultTransport is used.
+
+	Latency         time.Duration // How long a delayed request waits before it is sent
+	Clock           gaws.Clock    // Times the delays. If it is nil, gaws.DefaultClock is used.
+	LatencyRate     float64
+	DropRate        float64 // Dropped requests return an error, like a connection that was reset
+	ThrottleRate    float64
+	ThrottleStatus  int    // If it is 0, 429 is used for Lambda and Elastic Transcoder, and 400 for the rest
+	ThrottleBody    string // If it is empty, a ThrottlingException or Throttling error is used
+	ServerErrorRate float64
+	ServerErrorBody string // The body of the 500 response. If it is empty, an InternalFailure error is used.
+	Seed            int64  // Seeds the random numbers, so a test fails the same requests each time it runs
+
+	lock   sync.Mutex // Protects random and counts
+	random *rand.Rand
+	counts FaultCounts
+}
+
+// Counts returns how many requests the transport has seen, and what it did to them.
+func (t *FaultTransport) Counts() FaultCounts {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.counts
+}
+
+// chance returns true with the probability rate.
+func (t *FaultTransport) chance(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if t.random == nil {
+		t.random = rand.New(rand.NewSource(t.Seed))
+	}
+	return t.random.Float64() < rate
+}
+
+// fault decides what happens to a request, counts it, and returns whether it is delayed and the fault, if there is one.
+func (t *FaultTransport) fault() (bool, string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.counts.Requests++
+	delayed := t.chance(t.LatencyRate)
+	if delayed {
+		t.counts.Delayed++
+	}
+
+	switch {
+	case t.chance(t.DropRate):
+		t.counts.Dropped++
+		return delayed, "drop"
+	case t.chance(t.ThrottleRate):
+		t.counts.Throttled++
+		return delayed, "throttle"
+	case t.chance(t.ServerErrorRate):
+		t.counts.ServerErrors++
+		return delayed, "server error"
+	}
+	return delayed, ""
+}
+
+// RoundTrip sends the request, or makes it fail.
+func (t *FaultTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	delayed, fault := t.fault()
+	if delayed {
+		clock := t.Clock
+		if clock == nil {
+			clock = gaws.DefaultClock
+		}
+		select {
+		case <-r.Context().Done():
+			closeBody(r)
+			return nil, r.Context().Err()
+		case <-clock.After(t.Latency):
+		}
+	}
+
+	switch fault {
+	case "drop":
+		closeBody(r)
+		return nil, errConnectionDropped
+	case "throttle":
+		closeBody(r)
+		status, body := throttle(r)
+		if t.ThrottleStatus != 0 {
+			status = t.ThrottleStatus
+		}
+		if t.ThrottleBody != "" {
+			body = t.ThrottleBody
+		}
+		return response(r, status, body), nil
+	case "server error":
+		closeBody(r)
+		body := t.ServerErrorBody
+		if body == "" {
+			body = errorBody(r, "InternalFailure", "InternalFailure", "The request processing has failed because of an unknown error.")
+		}
+		return response(r, 500, body), nil
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(r)
+}
+
+// closeBody closes the body of a request that is not sent, as a RoundTripper must.
+func closeBody(r *http.Request) {
+	if r.Body != nil {
+		r.Body.Close()
+	}
+}
+
+// errorBody returns an error document in the form the request's service uses: JSON with jsonType, or XML with xmlCode.
+func errorBody(r *http.Request, jsonType string, xmlCode string, message string) string {
+	if r.Header.Get("X-Amz-Target") != "" || strings.Contains(r.Header.Get("Content-Type"), "json") {
+		return `{"__type":"` + jsonType + `","message":"` + message + `"}`
+	}
+	return "<ErrorResponse><Error><Code>" + xmlCode + "</Code><Message>" + message + "</Message></Error></ErrorResponse>"
+}
+
+// service returns the name of the service the request is to, like kinesis. It is in the scope of the request's signature, or else the
+// X-Amz-Target of some services, or else the first part of the host.
+func service(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if i := strings.Index(auth, "/aws4_request"); i >= 0 {
+		scope := auth[:i]
+		return scope[strings.LastIndex(scope, "/")+1:]
+	}
+
+	target := r.Header.Get("X-Amz-Target")
+	switch {
+	case strings.HasPrefix(target, "Kinesis_"):
+		return "kinesis"
+	case strings.HasPrefix(target, "AWSCognitoIdentityService."):
+		return "cognito-identity"
+	}
+
+	for _, label := range strings.Split(r.URL.Hostname(), ".") {
+		if label == "s3" || strings.HasPrefix(label, "s3-") {
+			// Buckets can be the first part of the host.
+			return "s3"
+		}
+	}
+	return strings.SplitN(r.URL.Hostname(), ".", 2)[0]
+}
+
+// throttle returns the status and body of a throttling error that the package of the request's service retries.
+func throttle(r *http.Request) (int, string) {
+	message := "Rate exceeded"
+	switch service(r) {
+	case "kinesis":
+		return 400, `{"__type":"ProvisionedThroughputExceededException","message":"` + message + `"}`
+	case "cognito-identity":
+		return 400, `{"__type":"TooManyRequestsException","message":"` + message + `"}`
+	case "glacier":
+		return 400, `{"code":"ThrottlingException","message":"` + message + `","type":"Client"}`
+	case "ec2":
+		return 400, "<Response><Errors><Error><Code>RequestLimitExceeded</Code><Message>" + message + "</Message></Error></Errors></Response>"
+	case "s3":
+		return 400, "<Error><Code>SlowDown</Code><Message>" + message + "</Message></Error>"
+	case "lambda", "elastictranscoder":
+		// Their REST APIs always answer in JSON, and tell throttling by the status.
+		return 429, `{"Type":"User","message":"` + message + `"}`
+	}
+	return 400, errorBody(r, "ThrottlingException", "Throttling", message)
+}
+
+// response returns a response to the request with the status and body.
+func response(r *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %v", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(body))),
+		ContentLength: int64(len(body)),
+		Request:       r,
+	}
+}
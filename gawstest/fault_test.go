@@ -0,0 +1,236 @@
+package gawstest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/kinesis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testSend sends a JSON request for the target through the transport, and returns the status and body of the response.
+func testSend(transport http.RoundTripper, s *Server, target string) (int, string, error) {
+	req, _ := http.NewRequest("POST", s.URL, strings.NewReader("{}"))
+	req.Header.Set("X-Amz-Target", target)
+	resp, err := (&http.Client{Transport: transport}).Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(body), nil
+}
+
+func TestFaultTransport(t *testing.T) {
+	Convey("Given a transport without faults", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, `{"StreamNames":[]}`)
+		ft := &FaultTransport{}
+
+		status, body, err := testSend(ft, s, "Kinesis_20131202.ListStreams")
+
+		Convey("It sends the request", func() {
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, 200)
+			So(body, ShouldEqual, `{"StreamNames":[]}`)
+			So(s, ShouldHaveReceived, "ListStreams", 1)
+			So(ft.Counts(), ShouldResemble, FaultCounts{Requests: 1})
+		})
+	})
+	Convey("Given a transport that always throttles", t, func() {
+		s := NewServer()
+		defer s.Close()
+		ft := &FaultTransport{ThrottleRate: 1}
+
+		status, body, err := testSend(ft, s, "Kinesis_20131202.ListStreams")
+
+		Convey("It returns a JSON throttling error without sending the request", func() {
+			So(err, ShouldBeNil)
+			So(status, ShouldEqual, 400)
+			So(body, ShouldContainSubstring, `"__type":"ProvisionedThroughputExceededException"`)
+			So(s, ShouldNotHaveReceived, "ListStreams")
+			So(ft.Counts().Throttled, ShouldEqual, 1)
+		})
+		Convey("Other JSON services get a ThrottlingException", func() {
+			_, body, _ := testSend(ft, s, "DynamoDB_20120810.GetItem")
+			So(body, ShouldContainSubstring, `"__type":"ThrottlingException"`)
+		})
+		Convey("Signed requests get the throttling of the service they are signed for", func() {
+			req, _ := http.NewRequest("POST", s.URL, strings.NewReader("{}"))
+			req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=AKID/20150101/us-east-1/kinesis/aws4_request, SignedHeaders=host, Signature=0")
+			req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+			resp, _ := ft.RoundTrip(req)
+			body, _ := ioutil.ReadAll(resp.Body)
+			So(string(body), ShouldContainSubstring, "ProvisionedThroughputExceededException")
+		})
+		Convey("EC2 and S3 get their own throttling codes", func() {
+			req, _ := http.NewRequest("POST", "https://ec2.us-east-1.amazonaws.com/", nil)
+			resp, _ := ft.RoundTrip(req)
+			body, _ := ioutil.ReadAll(resp.Body)
+			So(string(body), ShouldContainSubstring, "<Errors><Error><Code>RequestLimitExceeded</Code>")
+
+			req, _ = http.NewRequest("PUT", "https://bucket.s3.amazonaws.com/key", nil)
+			resp, _ = ft.RoundTrip(req)
+			body, _ = ioutil.ReadAll(resp.Body)
+			So(string(body), ShouldContainSubstring, "<Code>SlowDown</Code>")
+		})
+		Convey("Lambda and Elastic Transcoder get a 429, which they retry", func() {
+			for _, host := range []string{"lambda.us-east-1.amazonaws.com", "elastictranscoder.us-east-1.amazonaws.com"} {
+				req, _ := http.NewRequest("GET", "https://"+host+"/", nil)
+				resp, _ := ft.RoundTrip(req)
+				body, _ := ioutil.ReadAll(resp.Body)
+				So(resp.StatusCode, ShouldEqual, 429)
+				So(string(body), ShouldContainSubstring, `"message":"Rate exceeded"`)
+			}
+		})
+		Convey("A query request gets an XML error", func() {
+			resp, err := (&http.Client{Transport: ft}).PostForm(s.URL, url.Values{"Action": {"SendMessage"}})
+			So(err, ShouldBeNil)
+			defer resp.Body.Close()
+			xml, _ := ioutil.ReadAll(resp.Body)
+			So(string(xml), ShouldContainSubstring, "<Code>Throttling</Code>")
+		})
+	})
+	Convey("Given a transport with a throttling status and body", t, func() {
+		s := NewServer()
+		defer s.Close()
+		ft := &FaultTransport{ThrottleRate: 1, ThrottleStatus: 503, ThrottleBody: "<Error><Code>SlowDown</Code></Error>"}
+
+		status, body, _ := testSend(ft, s, "")
+
+		Convey("They are used", func() {
+			So(status, ShouldEqual, 503)
+			So(body, ShouldEqual, "<Error><Code>SlowDown</Code></Error>")
+		})
+	})
+	Convey("Given a transport that always fails", t, func() {
+		s := NewServer()
+		defer s.Close()
+		ft := &FaultTransport{ServerErrorRate: 1}
+
+		status, body, _ := testSend(ft, s, "Kinesis_20131202.ListStreams")
+
+		Convey("It returns a server error", func() {
+			So(status, ShouldEqual, 500)
+			So(body, ShouldContainSubstring, "InternalFailure")
+			So(ft.Counts().ServerErrors, ShouldEqual, 1)
+		})
+	})
+	Convey("Given a transport that always drops", t, func() {
+		s := NewServer()
+		defer s.Close()
+		ft := &FaultTransport{DropRate: 1, ThrottleRate: 1}
+
+		_, _, err := testSend(ft, s, "Kinesis_20131202.ListStreams")
+
+		Convey("It returns an error, and does not throttle as well", func() {
+			So(err, ShouldNotBeNil)
+			So(ft.Counts(), ShouldResemble, FaultCounts{Requests: 1, Dropped: 1})
+		})
+	})
+	Convey("Given a transport that always delays", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, "{}")
+		clock := NewClock(time.Now())
+		ft := &FaultTransport{Latency: 50 * time.Millisecond, LatencyRate: 1, Clock: clock}
+
+		status, _, _ := testSend(ft, s, "Kinesis_20131202.ListStreams")
+
+		Convey("It sends the request late, by its clock", func() {
+			So(status, ShouldEqual, 200)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{50 * time.Millisecond})
+			So(ft.Counts().Delayed, ShouldEqual, 1)
+		})
+	})
+	Convey("Given a transport that delays for longer than requests wait", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, "{}")
+		ft := &FaultTransport{Latency: time.Hour, LatencyRate: 1}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "POST", s.URL, strings.NewReader("{}"))
+		req.Header.Set("X-Amz-Target", "Kinesis_20131202.ListStreams")
+		_, err := ft.RoundTrip(req)
+
+		Convey("Requests stop waiting when they are cancelled, and are not sent", func() {
+			So(err, ShouldEqual, context.DeadlineExceeded)
+			So(s, ShouldNotHaveReceived, "ListStreams")
+		})
+	})
+	Convey("Given two transports with the same seed", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, "{}")
+		first := &FaultTransport{ThrottleRate: 0.5, Seed: 7}
+		second := &FaultTransport{ThrottleRate: 0.5, Seed: 7}
+
+		for i := 0; i < 20; i++ {
+			testSend(first, s, "Kinesis_20131202.ListStreams")
+			testSend(second, s, "Kinesis_20131202.ListStreams")
+		}
+
+		Convey("They fail the same requests", func() {
+			So(first.Counts(), ShouldResemble, second.Counts())
+			So(first.Counts().Throttled, ShouldBeGreaterThan, 0)
+			So(first.Counts().Throttled, ShouldBeLessThan, 20)
+		})
+	})
+}
+
+func TestFaultTransportWithGaws(t *testing.T) {
+	Convey("Given gaws using a transport that fails some requests", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, `{"StreamNames":["foo"],"HasMoreStreams":false}`)
+
+		ft := &FaultTransport{ServerErrorRate: 0.5, Seed: 6}
+		gaws.Transport = ft
+		defer func() { gaws.Transport = nil }()
+
+		k := kinesis.KinesisService{Endpoint: s.URL}
+		streams, err := k.ListStreams()
+
+		Convey("gaws retries until a request gets through", func() {
+			So(err, ShouldBeNil)
+			So(len(streams), ShouldEqual, 1)
+			So(ft.Counts(), ShouldResemble, FaultCounts{Requests: 2, ServerErrors: 1})
+		})
+	})
+}
+
+func TestFaultTransportThrottlingWithGaws(t *testing.T) {
+	Convey("Given gaws using a transport that throttles some Kinesis requests", t, func() {
+		clock := NewClock(time.Now())
+		old := gaws.DefaultClock
+		gaws.DefaultClock = clock
+		defer func() { gaws.DefaultClock = old }()
+
+		s := NewServer()
+		defer s.Close()
+		s.Respond("ListStreams", 200, `{"StreamNames":["foo"],"HasMoreStreams":false}`)
+
+		ft := &FaultTransport{ThrottleRate: 0.5, Seed: 6}
+		gaws.Transport = ft
+		defer func() { gaws.Transport = nil }()
+
+		k := kinesis.KinesisService{Endpoint: s.URL}
+		streams, err := k.ListStreams()
+
+		Convey("gaws retries the throttled requests", func() {
+			So(err, ShouldBeNil)
+			So(len(streams), ShouldEqual, 1)
+			So(ft.Counts().Throttled, ShouldBeGreaterThan, 0)
+		})
+	})
+}
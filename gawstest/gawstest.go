@@ -0,0 +1,191 @@
+// Package gawstest provides a fake AWS server for testing code that uses gaws.
+//
+// Tests register a response or a handler for each operation, point a service's Endpoint at the server's URL, and then check the requests
+// the server got. An operation is the X-Amz-Target header of JSON services, like Kinesis_20131202.PutRecord, or the Action parameter of
+// query services, like SendMessage. Handlers can be registered with the whole target or just the part after the last dot.
+package gawstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Request is a request the server got.
+type Request struct {
+	Operation string      // The X-Amz-Target header, or the Action parameter if there is no header
+	Method    string      // The HTTP method
+	URI       string      // The path and query
+	Header    http.Header // The headers
+	Body      []byte      // The body
+	Form      url.Values  // The query and form parameters
+}
+
+// Decode decodes the request's JSON body into v.
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Server is a fake AWS server. Operations without a handler get a 400 response with an UnknownOperationException error.
+type Server struct {
+	*httptest.Server
+
+	lock     sync.Mutex
+	handlers map[string]http.HandlerFunc
+	requests []Request
+}
+
+// NewServer starts a server. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{handlers: map[string]http.HandlerFunc{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Handle runs handler for the operation's requests. The request's body can be read again.
+func (s *Server) Handle(operation string, handler http.HandlerFunc) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[operation] = handler
+}
+
+// Respond sends body with the status for the operation's requests.
+func (s *Server) Respond(operation string, status int, body string) {
+	s.Handle(operation, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+}
+
+// RespondJSON sends v, encoded as JSON, for the operation's requests.
+func (s *Server) RespondJSON(operation string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	s.Respond(operation, 200, string(body))
+	return nil
+}
+
+// RespondError sends a JSON error document, the kind JSON services send, with the status for the operation's requests.
+func (s *Server) RespondError(operation string, status int, errorType string, message string) {
+	body, _ := json.Marshal(map[string]string{"__type": errorType, "message": message})
+	s.Respond(operation, status, string(body))
+}
+
+// Requests returns the requests the server has gotten so far, in order.
+func (s *Server) Requests() []Request {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// RequestsFor returns the requests the server has gotten so far for the operation, in order.
+func (s *Server) RequestsFor(operation string) []Request {
+	requests := []Request{}
+	for _, r := range s.Requests() {
+		if matches(operation, r.Operation) {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// matches reports whether a registered operation, which may be the whole target or the part after the last dot, names the operation.
+func matches(registered string, operation string) bool {
+	return registered == operation || registered == operation[strings.LastIndex(operation, ".")+1:]
+}
+
+// handler returns the handler for the operation. A handler for the whole target wins over one for the part after the last dot.
+func (s *Server) handler(operation string) (http.HandlerFunc, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if h, ok := s.handlers[operation]; ok {
+		return h, true
+	}
+	h, ok := s.handlers[operation[strings.LastIndex(operation, ".")+1:]]
+	return h, ok
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	// Parse the form from a copy of the body, so the handler can read it again.
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.ParseForm()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	operation := r.Header.Get("X-Amz-Target")
+	if operation == "" {
+		operation = r.Form.Get("Action")
+	}
+
+	s.lock.Lock()
+	s.requests = append(s.requests, Request{
+		Operation: operation,
+		Method:    r.Method,
+		URI:       r.URL.RequestURI(),
+		Header:    r.Header,
+		Body:      body,
+		Form:      r.Form,
+	})
+	s.lock.Unlock()
+
+	handler, ok := s.handler(operation)
+	if !ok {
+		w.WriteHeader(400)
+		w.Write([]byte(fmt.Sprintf(`{"__type":"UnknownOperationException","message":"gawstest has no handler for %q"}`, operation)))
+		return
+	}
+	handler(w, r)
+}
+
+// ShouldHaveReceived is an assertion, for goconvey's So, that a Server got requests for an operation. If a count is given,
+// it checks that there were that many of them.
+//
+//	So(server, gawstest.ShouldHaveReceived, "PutRecord", 2)
+func ShouldHaveReceived(actual interface{}, expected ...interface{}) string {
+	s, ok := actual.(*Server)
+	if !ok {
+		return fmt.Sprintf("Expected a *gawstest.Server, but got %T.", actual)
+	}
+	if len(expected) == 0 || len(expected) > 2 {
+		return "This assertion needs an operation and, optionally, a count."
+	}
+	operation, ok := expected[0].(string)
+	if !ok {
+		return fmt.Sprintf("Expected the operation to be a string, but got %T.", expected[0])
+	}
+
+	got := len(s.RequestsFor(operation))
+	if len(expected) == 1 {
+		if got == 0 {
+			return fmt.Sprintf("Expected requests for %v, but there were none.", operation)
+		}
+		return ""
+	}
+
+	count, ok := expected[1].(int)
+	if !ok {
+		return fmt.Sprintf("Expected the count to be an int, but got %T.", expected[1])
+	}
+	if got != count {
+		return fmt.Sprintf("Expected %d requests for %v, but there were %d.", count, operation, got)
+	}
+	return ""
+}
+
+// ShouldNotHaveReceived is an assertion, for goconvey's So, that a Server did not get any requests for an operation.
+func ShouldNotHaveReceived(actual interface{}, expected ...interface{}) string {
+	if len(expected) != 1 {
+		return "This assertion needs an operation."
+	}
+	return ShouldHaveReceived(actual, expected[0], 0)
+}
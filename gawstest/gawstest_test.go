@@ -0,0 +1,147 @@
+package gawstest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testPost sends a JSON request for the target to the server, and returns the status and body of the response.
+func testPost(s *Server, target string, body string) (int, string) {
+	req, _ := http.NewRequest("POST", s.URL, strings.NewReader(body))
+	req.Header.Set("X-Amz-Target", target)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+
+	b, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(b)
+}
+
+func TestRespond(t *testing.T) {
+	Convey("Given a response for an operation", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("PutRecord", 200, `{"SequenceNumber":"1"}`)
+
+		status, body := testPost(s, "Kinesis_20131202.PutRecord", `{"StreamName":"foo"}`)
+
+		Convey("It is sent for the whole target", func() {
+			So(status, ShouldEqual, 200)
+			So(body, ShouldEqual, `{"SequenceNumber":"1"}`)
+		})
+		Convey("The request is recorded", func() {
+			requests := s.Requests()
+			So(len(requests), ShouldEqual, 1)
+			So(requests[0].Operation, ShouldEqual, "Kinesis_20131202.PutRecord")
+			So(requests[0].Method, ShouldEqual, "POST")
+			So(string(requests[0].Body), ShouldEqual, `{"StreamName":"foo"}`)
+
+			decoded := map[string]string{}
+			So(requests[0].Decode(&decoded), ShouldBeNil)
+			So(decoded["StreamName"], ShouldEqual, "foo")
+		})
+	})
+	Convey("Given responses for the whole target and the operation", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("PutRecord", 200, "short")
+		s.Respond("Kinesis_20131202.PutRecord", 200, "whole")
+
+		_, body := testPost(s, "Kinesis_20131202.PutRecord", "")
+
+		Convey("The whole target wins", func() {
+			So(body, ShouldEqual, "whole")
+		})
+	})
+	Convey("Given an operation without a handler", t, func() {
+		s := NewServer()
+		defer s.Close()
+
+		status, body := testPost(s, "Kinesis_20131202.GetRecords", "")
+
+		Convey("It returns an error", func() {
+			So(status, ShouldEqual, 400)
+			So(body, ShouldContainSubstring, "UnknownOperationException")
+		})
+	})
+}
+
+func TestRespondJSONAndError(t *testing.T) {
+	Convey("Given a JSON response and an error", t, func() {
+		s := NewServer()
+		defer s.Close()
+		err := s.RespondJSON("DescribeStream", map[string]string{"StreamName": "foo"})
+		s.RespondError("DeleteStream", 400, "ResourceNotFoundException", "Stream foo not found")
+
+		_, described := testPost(s, "Kinesis_20131202.DescribeStream", "")
+		status, deleted := testPost(s, "Kinesis_20131202.DeleteStream", "")
+
+		Convey("They are sent", func() {
+			So(err, ShouldBeNil)
+			So(described, ShouldEqual, `{"StreamName":"foo"}`)
+			So(status, ShouldEqual, 400)
+			So(deleted, ShouldEqual, `{"__type":"ResourceNotFoundException","message":"Stream foo not found"}`)
+		})
+	})
+}
+
+func TestHandle(t *testing.T) {
+	Convey("Given a handler for a query action", t, func() {
+		s := NewServer()
+		defer s.Close()
+		var body []byte
+		s.Handle("SendMessage", func(w http.ResponseWriter, r *http.Request) {
+			body, _ = ioutil.ReadAll(r.Body)
+			w.Write([]byte("<SendMessageResponse></SendMessageResponse>"))
+		})
+
+		resp, err := http.PostForm(s.URL+"/123456789012/foo", url.Values{"Action": {"SendMessage"}, "MessageBody": {"hi"}})
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		Convey("It runs the handler, which can read the body", func() {
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "Action=SendMessage&MessageBody=hi")
+		})
+		Convey("The request's parameters are recorded", func() {
+			requests := s.RequestsFor("SendMessage")
+			So(len(requests), ShouldEqual, 1)
+			So(requests[0].Form.Get("MessageBody"), ShouldEqual, "hi")
+			So(requests[0].URI, ShouldEqual, "/123456789012/foo")
+		})
+	})
+}
+
+func TestAssertions(t *testing.T) {
+	Convey("Given a server that got two requests for an operation", t, func() {
+		s := NewServer()
+		defer s.Close()
+		s.Respond("PutRecord", 200, "{}")
+		testPost(s, "Kinesis_20131202.PutRecord", "")
+		testPost(s, "Kinesis_20131202.PutRecord", "")
+
+		Convey("ShouldHaveReceived passes for the operation and its count", func() {
+			So(ShouldHaveReceived(s, "PutRecord"), ShouldEqual, "")
+			So(ShouldHaveReceived(s, "Kinesis_20131202.PutRecord", 2), ShouldEqual, "")
+			So(s, ShouldHaveReceived, "PutRecord", 2)
+		})
+		Convey("ShouldHaveReceived fails for another count or operation", func() {
+			So(ShouldHaveReceived(s, "PutRecord", 1), ShouldNotEqual, "")
+			So(ShouldHaveReceived(s, "GetRecords"), ShouldNotEqual, "")
+			So(ShouldHaveReceived("server", "PutRecord"), ShouldNotEqual, "")
+			So(ShouldHaveReceived(s), ShouldNotEqual, "")
+		})
+		Convey("ShouldNotHaveReceived checks there were none", func() {
+			So(ShouldNotHaveReceived(s, "GetRecords"), ShouldEqual, "")
+			So(ShouldNotHaveReceived(s, "PutRecord"), ShouldNotEqual, "")
+		})
+	})
+}
@@ -0,0 +1,33 @@
+package glacier
+
+import (
+	"net/url"
+)
+
+// UploadArchive uploads data as an archive in the vault, in one request. Use UploadMultipart for archives over 100 MB.
+// It returns the ID of the archive, which is needed to retrieve it. It is calling the UploadArchive API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-archive-post.html for more details.
+func (s *GlacierService) UploadArchive(vault string, description string, data []byte) (string, error) {
+	req := s.request("POST", vaultPath(vault, "/archives"), data)
+	req.Headers["X-Amz-Sha256-Tree-Hash"] = TreeHash(data)
+	if description != "" {
+		req.Headers["X-Amz-Archive-Description"] = description
+	}
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get("X-Amz-Archive-Id"), nil
+}
+
+// DeleteArchive deletes an archive from the vault. It is calling the DeleteArchive API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-archive-delete.html for more details.
+func (s *GlacierService) DeleteArchive(vault string, archiveId string) error {
+	req := s.request("DELETE", vaultPath(vault, "/archives/"+url.PathEscape(archiveId)), nil)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,67 @@
+package glacier
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUploadArchive(t *testing.T) {
+	Convey("Given an archive", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Amz-Archive-Id", "archive-1")
+			w.WriteHeader(201)
+		})
+		defer ts.Close()
+
+		id, err := s.UploadArchive("logs", "2015-07-27", []byte("archive"))
+
+		Convey("It returns the ID of the archive", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "archive-1")
+		})
+		Convey("It sends the archive with its tree hash", func() {
+			So(requests()[0].Method, ShouldEqual, "POST")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs/archives")
+			So(requests()[0].Headers.Get("X-Amz-Archive-Description"), ShouldEqual, "2015-07-27")
+			So(requests()[0].Headers.Get("X-Amz-Sha256-Tree-Hash"), ShouldEqual, TreeHash([]byte("archive")))
+			So(string(requests()[0].Body), ShouldEqual, "archive")
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP404)
+		defer ts.Close()
+
+		_, err := s.UploadArchive("logs", "", []byte("archive"))
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
+
+func TestDeleteArchive(t *testing.T) {
+	Convey("Given an archive", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.DeleteArchive("logs", "archive-1")
+
+		Convey("It is deleted", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Method, ShouldEqual, "DELETE")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs/archives/archive-1")
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP404)
+		defer ts.Close()
+
+		err := s.DeleteArchive("logs", "archive-1")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
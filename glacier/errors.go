@@ -0,0 +1,20 @@
+// Code generated by errgen from the go:generate line in glacier.go. DO NOT EDIT.
+
+package glacier
+
+// These are errors Glacier returns. errors.Is(err, glacier.ErrResourceNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrResourceNotFound      = glacierError{Code: "ResourceNotFoundException"}
+	ErrInvalidParameterValue = glacierError{Code: "InvalidParameterValueException"}
+	ErrMissingParameterValue = glacierError{Code: "MissingParameterValueException"}
+	ErrLimitExceeded         = glacierError{Code: "LimitExceededException"}
+	ErrPolicyEnforced        = glacierError{Code: "PolicyEnforcedException"}
+	ErrRequestTimeout        = glacierError{Code: "RequestTimeoutException"}
+	ErrServiceUnavailable    = glacierError{Code: "ServiceUnavailableException"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e glacierError) Is(target error) bool {
+	t, ok := target.(glacierError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,121 @@
+// Package glacier provides a way to interact with the AWS Glacier archive storage service.
+package glacier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// apiVersion is the version of the Glacier API, which every request names in a header.
+const apiVersion = "2012-06-01"
+
+//go:generate go run ../internal/errgen -type glacierError -field Code -service Glacier ResourceNotFoundException InvalidParameterValueException MissingParameterValueException LimitExceededException PolicyEnforcedException RequestTimeoutException ServiceUnavailableException
+
+// glacierError is the error document returned from the Glacier service.
+type glacierError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Type    string `json:"type"` // Client or Server
+}
+
+// Error formats the glacierError into an error message.
+func (e glacierError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+var waiterTimeoutError = glacierError{Code: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks. Retrieval jobs take hours, so it is long.
+var WaitInterval = 15 * time.Minute
+
+// MaxWaits is the number of times a waiter checks before giving up.
+var MaxWaits = 32
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *GlacierService) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func glacierRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := glacierError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "ThrottlingException" || error.Code == "RequestTimeoutException" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// GlacierService is the Glacier service at AWS. The endpoint for a region is gaws.Endpoint("glacier", region).
+type GlacierService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// GlacierAPI is implemented by *GlacierService. Code that takes a GlacierAPI can be given a fake in tests instead.
+type GlacierAPI interface {
+	CreateVault(name string) error
+	DeleteArchive(vault string, archiveId string) error
+	DescribeJob(vault string, jobId string) (Job, error)
+	DescribeVault(name string) (Vault, error)
+	GetJobOutput(vault string, jobId string) (io.ReadCloser, error)
+	InitiateJob(vault string, parameters JobParameters) (string, error)
+	ListVaults() ([]Vault, error)
+	UploadArchive(vault string, description string, data []byte) (string, error)
+	UploadMultipart(vault string, description string, r io.Reader) (string, error)
+	WaitUntilJobComplete(vault string, jobId string) (Job, error)
+}
+
+var _ GlacierAPI = (*GlacierService)(nil)
+
+// New returns a GlacierService with the options applied. Its endpoint is gaws.Endpoint("glacier", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *GlacierService {
+	config := gaws.NewConfig(options...)
+	return &GlacierService{Endpoint: config.ServiceEndpoint("glacier"), config: config}
+}
+
+// request returns a request to a path under the account of the credentials. It is signed with signature version 4, including the SHA256 hash of the body.
+func (s *GlacierService) request(method string, path string, body []byte) gaws.AWSRequest {
+	hash := sha256.Sum256(body)
+
+	r := gaws.AWSRequest{
+		RetryPredicate: glacierRetryPredicate,
+		Method:         method,
+		URL:            s.Endpoint + "/-" + path,
+		Headers: map[string]string{
+			"X-Amz-Glacier-Version": apiVersion,
+			"X-Amz-Content-Sha256":  hex.EncodeToString(hash[:]),
+		},
+		Body:   body,
+		Signer: gaws.Sign4("glacier", s.config.Region),
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// vaultPath returns the path of a vault, or of something in it, like vaultPath("logs", "/archives").
+func vaultPath(vault string, rest string) string {
+	return "/vaults/" + url.PathEscape(vault) + rest
+}
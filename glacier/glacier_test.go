@@ -0,0 +1,141 @@
+package glacier
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(204)
+}
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var resourceNotFoundError = glacierError{Code: "ResourceNotFoundException", Message: "Vault not found for ARN: arn:aws:glacier:us-east-1:123456789012:vaults/logs", Type: "Client"}
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(404)
+	w.Write([]byte(`{"code":"ResourceNotFoundException","message":"Vault not found for ARN: arn:aws:glacier:us-east-1:123456789012:vaults/logs","type":"Client"}`))
+}
+
+// testJson returns a handler that returns body.
+func testJson(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Method  string
+	URI     string // The path and query
+	Headers http.Header
+	Body    []byte
+}
+
+// testService returns a service on a server that runs handler, and a function that returns the requests it has gotten so far.
+func testService(handler http.HandlerFunc) (*httptest.Server, *GlacierService, func() []testRequest) {
+	var lock sync.Mutex
+	requests := []testRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		lock.Lock()
+		requests = append(requests, testRequest{Method: r.Method, URI: r.URL.RequestURI(), Headers: r.Header, Body: body})
+		lock.Unlock()
+		handler(w, r)
+	}))
+
+	return ts, &GlacierService{Endpoint: ts.URL}, func() []testRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]testRequest{}, requests...)
+	}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := glacierRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a throttling error", t, func() {
+		result, _ := glacierRetryPredicate(400, []byte(`{"code":"ThrottlingException","message":"Rate exceeded","type":"Client"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a request timeout", t, func() {
+		result, _ := glacierRetryPredicate(408, []byte(`{"code":"RequestTimeoutException","message":"Request timed out.","type":"Client"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		result, err := glacierRetryPredicate(404, []byte(`{"code":"ResourceNotFoundException","message":"Vault not found for ARN: arn:aws:glacier:us-east-1:123456789012:vaults/logs","type":"Client"}`))
+
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+	Convey("Given a server error", t, func() {
+		result, _ := glacierRetryPredicate(500, []byte(`{"code":"ServiceUnavailableException","type":"Server"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+}
+
+func TestRequest(t *testing.T) {
+	Convey("Given a request", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		req := s.request("PUT", vaultPath("logs 2015", ""), nil)
+		_, err := req.Do()
+
+		Convey("It is for the account of the credentials, with the API version", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs%202015")
+			So(requests()[0].Headers.Get("X-Amz-Glacier-Version"), ShouldEqual, "2012-06-01")
+			So(requests()[0].Headers.Get("X-Amz-Content-Sha256"), ShouldEqual, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("glacier", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request("GET", "/", nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
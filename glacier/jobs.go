@@ -0,0 +1,121 @@
+package glacier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The types of jobs.
+const (
+	ArchiveRetrieval   = "archive-retrieval"   // Makes an archive available to download
+	InventoryRetrieval = "inventory-retrieval" // Makes a list of the archives in a vault available to download
+)
+
+// The statuses a job can have.
+const (
+	InProgress = "InProgress"
+	Succeeded  = "Succeeded"
+	Failed     = "Failed"
+)
+
+// JobParameters describe a job to start.
+type JobParameters struct {
+	Type        string // ArchiveRetrieval or InventoryRetrieval
+	ArchiveId   string `json:",omitempty"` // The archive to retrieve, for ArchiveRetrieval
+	Description string `json:",omitempty"`
+	SNSTopic    string `json:",omitempty"` // The ARN of a topic to notify when the job is done
+	Tier        string `json:",omitempty"` // Expedited, Standard or Bulk, for ArchiveRetrieval. If it is empty, Standard is used.
+	Format      string `json:",omitempty"` // JSON or CSV, for InventoryRetrieval. If it is empty, JSON is used.
+}
+
+// Job is a Glacier job, which retrieves an archive or inventory so it can be downloaded.
+type Job struct {
+	JobId                 string
+	JobDescription        string
+	Action                string // What the job does, like ArchiveRetrieval or InventoryRetrieval
+	ArchiveId             string
+	VaultARN              string
+	CreationDate          time.Time
+	Completed             bool
+	StatusCode            string // InProgress, Succeeded or Failed
+	StatusMessage         string
+	ArchiveSizeInBytes    int64
+	InventorySizeInBytes  int64
+	CompletionDate        time.Time
+	SHA256TreeHash        string // The tree hash of the output, which can be checked with TreeHash
+	ArchiveSHA256TreeHash string
+}
+
+// InitiateJob starts a job in the vault. It returns the ID of the job. It is calling the InitiateJob API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-initiate-job-post.html for more details.
+func (s *GlacierService) InitiateJob(vault string, parameters JobParameters) (string, error) {
+	body, err := json.Marshal(parameters)
+	if err != nil {
+		return "", err
+	}
+
+	req := s.request("POST", vaultPath(vault, "/jobs"), body)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get("X-Amz-Job-Id"), nil
+}
+
+// jobPath returns the path of a job in the vault, or of something of the job's, like its output.
+func jobPath(vault string, jobId string, rest string) string {
+	return vaultPath(vault, "/jobs/"+url.PathEscape(jobId)+rest)
+}
+
+// DescribeJob describes a job in the vault. It is calling the DescribeJob API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-describe-job-get.html for more details.
+func (s *GlacierService) DescribeJob(vault string, jobId string) (Job, error) {
+	result := Job{}
+
+	req := s.request("GET", jobPath(vault, jobId, ""), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+// WaitUntilJobComplete waits until a job has succeeded, and returns it. It returns an error if the job fails.
+// Jobs take hours, so this checks every WaitInterval, up to MaxWaits times. Jobs with an SNSTopic do not need to be waited for.
+func (s *GlacierService) WaitUntilJobComplete(vault string, jobId string) (Job, error) {
+	var job Job
+
+	err := s.wait(func() (bool, error) {
+		var err error
+		job, err = s.DescribeJob(vault, jobId)
+		if err == nil && job.StatusCode == Failed {
+			return false, glacierError{Code: "GawsJobFailed", Message: fmt.Sprintf("Job %v failed: %v", jobId, job.StatusMessage)}
+		}
+		return job.Completed, err
+	})
+	return job, err
+}
+
+// GetJobOutput returns the output of a job that has succeeded, like the archive it retrieved. It must be closed.
+// The output can be downloaded for 24 hours after the job completes. It is calling the GetJobOutput API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-job-output-get.html for more details.
+func (s *GlacierService) GetJobOutput(vault string, jobId string) (io.ReadCloser, error) {
+	req := s.request("GET", jobPath(vault, jobId, "/output"), nil)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
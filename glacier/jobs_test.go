@@ -0,0 +1,151 @@
+package glacier
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testJobStatuses returns a handler that describes a job with each status in turn, staying at the last.
+func testJobStatuses(statuses ...string) http.HandlerFunc {
+	n := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[n]
+		if n < len(statuses)-1 {
+			n++
+		}
+		fmt.Fprintf(w, `{"JobId":"job-1","Action":"ArchiveRetrieval","StatusCode":"%v","StatusMessage":"%v","Completed":%v}`, status, status, status != InProgress)
+	}
+}
+
+func TestInitiateJob(t *testing.T) {
+	Convey("Given an archive to retrieve", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Amz-Job-Id", "job-1")
+			w.WriteHeader(202)
+		})
+		defer ts.Close()
+
+		id, err := s.InitiateJob("logs", JobParameters{Type: ArchiveRetrieval, ArchiveId: "archive-1", Tier: "Bulk"})
+
+		Convey("It returns the ID of the job", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "job-1")
+		})
+		Convey("It sends the parameters", func() {
+			So(requests()[0].Method, ShouldEqual, "POST")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs/jobs")
+			So(string(requests()[0].Body), ShouldEqual, `{"Type":"archive-retrieval","ArchiveId":"archive-1","Tier":"Bulk"}`)
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP404)
+		defer ts.Close()
+
+		_, err := s.InitiateJob("logs", JobParameters{Type: InventoryRetrieval})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
+
+func TestDescribeJob(t *testing.T) {
+	Convey("Given a job", t, func() {
+		ts, s, requests := testService(testJson(`{"JobId":"job-1","Action":"ArchiveRetrieval","ArchiveId":"archive-1",
+			"CreationDate":"2015-07-27T12:00:00.000Z","Completed":true,"StatusCode":"Succeeded","ArchiveSizeInBytes":7,"SHA256TreeHash":"abc"}`))
+		defer ts.Close()
+
+		job, err := s.DescribeJob("logs", "job-1")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(job.ArchiveId, ShouldEqual, "archive-1")
+			So(job.Completed, ShouldBeTrue)
+			So(job.StatusCode, ShouldEqual, Succeeded)
+			So(job.ArchiveSizeInBytes, ShouldEqual, 7)
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs/jobs/job-1")
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.DescribeJob("logs", "job-1")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestWaitUntilJobComplete(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a job that succeeds", t, func() {
+		ts, s, requests := testService(testJobStatuses(InProgress, InProgress, Succeeded))
+		defer ts.Close()
+
+		job, err := s.WaitUntilJobComplete("logs", "job-1")
+
+		Convey("It waits until it is complete", func() {
+			So(err, ShouldBeNil)
+			So(job.StatusCode, ShouldEqual, Succeeded)
+			So(len(requests()), ShouldEqual, 3)
+		})
+	})
+	Convey("Given a job that fails", t, func() {
+		ts, s, _ := testService(testJobStatuses(InProgress, Failed))
+		defer ts.Close()
+
+		_, err := s.WaitUntilJobComplete("logs", "job-1")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, glacierError{Code: "GawsJobFailed", Message: "Job job-1 failed: Failed"})
+		})
+	})
+	Convey("Given a job that takes too long", t, func() {
+		defer func(waits int) { MaxWaits = waits }(MaxWaits)
+		MaxWaits = 2
+		ts, s, _ := testService(testJobStatuses(InProgress))
+		defer ts.Close()
+
+		_, err := s.WaitUntilJobComplete("logs", "job-1")
+
+		Convey("It gives up", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+}
+
+func TestGetJobOutput(t *testing.T) {
+	Convey("Given a job that has succeeded", t, func() {
+		ts, s, requests := testService(testJson("archive"))
+		defer ts.Close()
+
+		output, err := s.GetJobOutput("logs", "job-1")
+
+		Convey("It returns the output", func() {
+			So(err, ShouldBeNil)
+			defer output.Close()
+			data, _ := ioutil.ReadAll(output)
+			So(string(data), ShouldEqual, "archive")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs/jobs/job-1/output")
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP404)
+		defer ts.Close()
+
+		_, err := s.GetJobOutput("logs", "job-1")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
@@ -0,0 +1,144 @@
+package glacier
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+// PartSize is how many bytes UploadMultipart sends in each request. Glacier needs it to be a megabyte times a power of two, up to 4 GB.
+var PartSize int64 = 8 << 20
+
+var invalidPartSizeError = glacierError{Code: "GawsInvalidPartSize", Message: "PartSize must be a megabyte times a power of two, up to 4 GB."}
+
+// validPartSize reports whether Glacier takes parts of the size.
+func validPartSize(size int64) bool {
+	if size < chunkSize || size > 4<<30 || size%chunkSize != 0 {
+		return false
+	}
+	chunks := size / chunkSize
+	return chunks&(chunks-1) == 0
+}
+
+// multipartUpload is an archive being uploaded in parts. Glacier needs the tree hash of the whole archive to put the parts together,
+// so the hashes of each megabyte are kept as the parts are sent.
+type multipartUpload struct {
+	service *GlacierService
+	vault   string
+	id      string
+	size    int64
+	hashes  [][]byte
+}
+
+// initiateMultipartUpload starts uploading an archive in parts of the size. It is calling the InitiateMultipartUpload API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-multipart-initiate-upload.html for more details.
+func (s *GlacierService) initiateMultipartUpload(vault string, description string, partSize int64) (*multipartUpload, error) {
+	req := s.request("POST", vaultPath(vault, "/multipart-uploads"), nil)
+	req.Headers["X-Amz-Part-Size"] = strconv.FormatInt(partSize, 10)
+	if description != "" {
+		req.Headers["X-Amz-Archive-Description"] = description
+	}
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &multipartUpload{service: s, vault: vault, id: resp.Header.Get("X-Amz-Multipart-Upload-Id")}, nil
+}
+
+// path returns the path of the upload.
+func (u *multipartUpload) path() string {
+	return vaultPath(u.vault, "/multipart-uploads/"+url.PathEscape(u.id))
+}
+
+// uploadPart uploads the contents as the part after the ones uploaded so far. It is calling the UploadMultipartPart API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-upload-part.html for more details.
+func (u *multipartUpload) uploadPart(contents []byte) error {
+	hashes := chunkHashes(contents)
+
+	req := u.service.request("PUT", u.path(), contents)
+	req.Headers["Content-Range"] = fmt.Sprintf("bytes %v-%v/*", u.size, u.size+int64(len(contents))-1)
+	req.Headers["X-Amz-Sha256-Tree-Hash"] = hex.EncodeToString(treeHash(hashes))
+
+	_, err := req.Do()
+	if err != nil {
+		return err
+	}
+
+	u.size += int64(len(contents))
+	u.hashes = append(u.hashes, hashes...)
+	return nil
+}
+
+// complete puts the parts together into an archive. It returns the ID of the archive. It is calling the CompleteMultipartUpload API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-multipart-complete-upload.html for more details.
+func (u *multipartUpload) complete() (string, error) {
+	req := u.service.request("POST", u.path(), nil)
+	req.Headers["X-Amz-Archive-Size"] = strconv.FormatInt(u.size, 10)
+	req.Headers["X-Amz-Sha256-Tree-Hash"] = hex.EncodeToString(treeHash(u.hashes))
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get("X-Amz-Archive-Id"), nil
+}
+
+// abort stops the upload and deletes the parts that were uploaded. It is calling the AbortMultipartUpload API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-multipart-abort-upload.html for more details.
+func (u *multipartUpload) abort() error {
+	req := u.service.request("DELETE", u.path(), nil)
+
+	_, err := req.Do()
+	return err
+}
+
+// readPart reads up to size bytes from r. It returns false when r has nothing more after them.
+func readPart(r io.Reader, size int64) ([]byte, bool, error) {
+	part := make([]byte, size)
+	n, err := io.ReadFull(r, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return part[:n], false, nil
+	}
+	return part, err == nil, err
+}
+
+// UploadMultipart uploads everything read from r as an archive in the vault, in parts of PartSize, one at a time.
+// Only one part is kept in memory, so r can be much larger than memory. If a part fails, the upload is aborted.
+// It returns the ID of the archive, which is needed to retrieve it.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/uploading-archive-mpu.html for more details.
+func (s *GlacierService) UploadMultipart(vault string, description string, r io.Reader) (string, error) {
+	partSize := PartSize
+	if !validPartSize(partSize) {
+		return "", invalidPartSizeError
+	}
+
+	upload, err := s.initiateMultipartUpload(vault, description, partSize)
+	if err != nil {
+		return "", err
+	}
+
+	for more := true; more; {
+		var contents []byte
+		contents, more, err = readPart(r, partSize)
+		if err == nil && len(contents) > 0 {
+			err = upload.uploadPart(contents)
+		}
+		if err != nil {
+			upload.abort()
+			return "", err
+		}
+	}
+
+	id, err := upload.complete()
+	if err != nil {
+		upload.abort()
+	}
+	return id, err
+}
@@ -0,0 +1,106 @@
+package glacier
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testMultipart returns a handler that accepts a multipart upload, and fails parts if failParts is true.
+func testMultipart(failParts bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/-/vaults/logs/multipart-uploads":
+			w.Header().Set("X-Amz-Multipart-Upload-Id", "upload-1")
+			w.WriteHeader(201)
+		case r.Method == "PUT" && failParts:
+			testHTTP404(w, r)
+		case r.Method == "POST":
+			w.Header().Set("X-Amz-Archive-Id", "archive-1")
+			w.WriteHeader(201)
+		default:
+			w.WriteHeader(204)
+		}
+	}
+}
+
+func TestValidPartSize(t *testing.T) {
+	Convey("Given part sizes", t, func() {
+		Convey("A megabyte times a power of two is valid", func() {
+			So(validPartSize(1<<20), ShouldBeTrue)
+			So(validPartSize(8<<20), ShouldBeTrue)
+			So(validPartSize(4<<30), ShouldBeTrue)
+		})
+		Convey("Other sizes are not", func() {
+			So(validPartSize(0), ShouldBeFalse)
+			So(validPartSize(3<<20), ShouldBeFalse)
+			So(validPartSize(1<<20+1), ShouldBeFalse)
+			So(validPartSize(8<<30), ShouldBeFalse)
+		})
+	})
+}
+
+func TestUploadMultipart(t *testing.T) {
+	defer func(size int64) { PartSize = size }(PartSize)
+	PartSize = 1 << 20
+
+	Convey("Given two and a half parts", t, func() {
+		ts, s, requests := testService(testMultipart(false))
+		defer ts.Close()
+		data := bytes.Repeat([]byte{1, 2, 3, 4, 5}, chunkSize/2)
+
+		id, err := s.UploadMultipart("logs", "2015-07-27", bytes.NewReader(data))
+
+		Convey("It returns the ID of the archive", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "archive-1")
+		})
+		Convey("It starts the upload with the part size", func() {
+			So(requests()[0].Headers.Get("X-Amz-Part-Size"), ShouldEqual, "1048576")
+			So(requests()[0].Headers.Get("X-Amz-Archive-Description"), ShouldEqual, "2015-07-27")
+		})
+		Convey("It sends each part with its range and tree hash", func() {
+			So(len(requests()), ShouldEqual, 5)
+			So(requests()[1].Method, ShouldEqual, "PUT")
+			So(requests()[1].URI, ShouldEqual, "/-/vaults/logs/multipart-uploads/upload-1")
+			So(requests()[1].Headers.Get("Content-Range"), ShouldEqual, "bytes 0-1048575/*")
+			So(requests()[1].Headers.Get("X-Amz-Sha256-Tree-Hash"), ShouldEqual, TreeHash(data[:chunkSize]))
+			So(requests()[2].Headers.Get("Content-Range"), ShouldEqual, "bytes 1048576-2097151/*")
+			So(requests()[3].Headers.Get("Content-Range"), ShouldEqual, "bytes 2097152-2621439/*")
+			So(requests()[3].Body, ShouldResemble, data[2*chunkSize:])
+		})
+		Convey("It completes the upload with the size and tree hash of the whole archive", func() {
+			So(requests()[4].Method, ShouldEqual, "POST")
+			So(requests()[4].URI, ShouldEqual, "/-/vaults/logs/multipart-uploads/upload-1")
+			So(requests()[4].Headers.Get("X-Amz-Archive-Size"), ShouldEqual, "2621440")
+			So(requests()[4].Headers.Get("X-Amz-Sha256-Tree-Hash"), ShouldEqual, TreeHash(data))
+		})
+	})
+	Convey("Given a part that fails", t, func() {
+		ts, s, requests := testService(testMultipart(true))
+		defer ts.Close()
+
+		_, err := s.UploadMultipart("logs", "", bytes.NewReader(make([]byte, 3*chunkSize)))
+
+		Convey("It returns the error and aborts the upload", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+			So(len(requests()), ShouldEqual, 3)
+			So(requests()[2].Method, ShouldEqual, "DELETE")
+			So(requests()[2].URI, ShouldEqual, "/-/vaults/logs/multipart-uploads/upload-1")
+		})
+	})
+	Convey("Given a part size that Glacier does not take", t, func() {
+		ts, s, requests := testService(testMultipart(false))
+		defer ts.Close()
+		PartSize = 3 << 20
+
+		_, err := s.UploadMultipart("logs", "", bytes.NewReader([]byte("archive")))
+
+		Convey("It returns an error without starting an upload", func() {
+			So(err, ShouldResemble, invalidPartSizeError)
+			So(len(requests()), ShouldEqual, 0)
+		})
+	})
+}
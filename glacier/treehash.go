@@ -0,0 +1,50 @@
+package glacier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// chunkSize is the size of the pieces a tree hash hashes separately.
+const chunkSize = 1 << 20
+
+// chunkHashes returns the SHA256 hashes of each megabyte of data. Empty data has one hash, of nothing.
+func chunkHashes(data []byte) [][]byte {
+	hashes := [][]byte{}
+	for len(data) > chunkSize {
+		hash := sha256.Sum256(data[:chunkSize])
+		hashes = append(hashes, hash[:])
+		data = data[chunkSize:]
+	}
+	hash := sha256.Sum256(data)
+	return append(hashes, hash[:])
+}
+
+// treeHash returns the root of the tree of hashes, where each level hashes pairs from the level below, and a hash without a pair is moved up as it is.
+// No hashes have the hash of nothing as their root.
+func treeHash(hashes [][]byte) []byte {
+	if len(hashes) == 0 {
+		hash := sha256.Sum256(nil)
+		return hash[:]
+	}
+	for len(hashes) > 1 {
+		level := [][]byte{}
+		for i := 0; i < len(hashes); i += 2 {
+			if i+1 == len(hashes) {
+				level = append(level, hashes[i])
+				continue
+			}
+			hash := sha256.Sum256(append(append([]byte{}, hashes[i]...), hashes[i+1]...))
+			level = append(level, hash[:])
+		}
+		hashes = level
+	}
+	return hashes[0]
+}
+
+// TreeHash returns the SHA256 tree hash of data, as hex. Glacier checks archives and their parts with tree hashes, which can be
+// put together from the hashes of each megabyte, so parts can be checked on their own.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/checksum-calculations.html for more details.
+func TreeHash(data []byte) string {
+	return hex.EncodeToString(treeHash(chunkHashes(data)))
+}
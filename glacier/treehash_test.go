@@ -0,0 +1,73 @@
+package glacier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testSum returns the SHA256 hash of the parts put together.
+func testSum(parts ...[]byte) []byte {
+	hash := sha256.Sum256(bytes.Join(parts, nil))
+	return hash[:]
+}
+
+func TestTreeHash(t *testing.T) {
+	Convey("Given less than a megabyte", t, func() {
+		data := []byte("archive")
+
+		Convey("The tree hash is the SHA256 hash", func() {
+			So(TreeHash(data), ShouldEqual, hex.EncodeToString(testSum(data)))
+		})
+	})
+	Convey("Given nothing", t, func() {
+		Convey("The tree hash is the hash of nothing", func() {
+			So(TreeHash(nil), ShouldEqual, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+			So(hex.EncodeToString(treeHash(nil)), ShouldEqual, TreeHash(nil))
+		})
+	})
+	Convey("Given exactly a megabyte", t, func() {
+		data := bytes.Repeat([]byte{1}, chunkSize)
+
+		Convey("It is one chunk", func() {
+			So(len(chunkHashes(data)), ShouldEqual, 1)
+			So(TreeHash(data), ShouldEqual, hex.EncodeToString(testSum(data)))
+		})
+	})
+	Convey("Given three and a half megabytes", t, func() {
+		a := bytes.Repeat([]byte{1}, chunkSize)
+		b := bytes.Repeat([]byte{2}, chunkSize)
+		c := bytes.Repeat([]byte{3}, chunkSize)
+		d := bytes.Repeat([]byte{4}, chunkSize/2)
+		data := bytes.Join([][]byte{a, b, c, d}, nil)
+
+		Convey("Each level hashes pairs from the level below", func() {
+			expected := testSum(testSum(testSum(a), testSum(b)), testSum(testSum(c), testSum(d)))
+			So(TreeHash(data), ShouldEqual, hex.EncodeToString(expected))
+		})
+	})
+	Convey("Given three megabytes", t, func() {
+		a := bytes.Repeat([]byte{1}, chunkSize)
+		b := bytes.Repeat([]byte{2}, chunkSize)
+		c := bytes.Repeat([]byte{3}, chunkSize)
+		data := bytes.Join([][]byte{a, b, c}, nil)
+
+		Convey("The hash without a pair is moved up as it is", func() {
+			expected := testSum(testSum(testSum(a), testSum(b)), testSum(c))
+			So(TreeHash(data), ShouldEqual, hex.EncodeToString(expected))
+		})
+	})
+	Convey("Given parts of a megabyte times a power of two", t, func() {
+		data := bytes.Repeat([]byte{5, 6, 7}, chunkSize*2)
+
+		Convey("The tree hash of the parts' tree hashes is the tree hash of the whole", func() {
+			first := treeHash(chunkHashes(data[:2*chunkSize]))
+			second := treeHash(chunkHashes(data[2*chunkSize : 4*chunkSize]))
+			third := treeHash(chunkHashes(data[4*chunkSize:]))
+			So(hex.EncodeToString(treeHash([][]byte{first, second, third})), ShouldEqual, TreeHash(data))
+		})
+	})
+}
@@ -0,0 +1,81 @@
+package glacier
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// Vault is a Glacier vault, which holds archives.
+type Vault struct {
+	VaultARN          string
+	VaultName         string
+	CreationDate      time.Time
+	LastInventoryDate time.Time // Glacier takes an inventory about once a day. NumberOfArchives and SizeInBytes are from it.
+	NumberOfArchives  int64
+	SizeInBytes       int64
+}
+
+// CreateVault creates a vault. Creating a vault that exists does nothing. It is calling the CreateVault API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-vault-put.html for more details.
+func (s *GlacierService) CreateVault(name string) error {
+	req := s.request("PUT", vaultPath(name, ""), nil)
+
+	_, err := req.Do()
+	return err
+}
+
+// DescribeVault describes a vault. It is calling the DescribeVault API call.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-vault-get.html for more details.
+func (s *GlacierService) DescribeVault(name string) (Vault, error) {
+	result := Vault{}
+
+	req := s.request("GET", vaultPath(name, ""), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+type listVaultsResult struct {
+	VaultList []Vault
+	Marker    string
+}
+
+// ListVaults lists the vaults in the region. It is calling the ListVaults API call until there are no more pages.
+// See http://docs.aws.amazon.com/amazonglacier/latest/dev/api-vaults-get.html for more details.
+func (s *GlacierService) ListVaults() ([]Vault, error) {
+	vaults := []Vault{}
+	query := url.Values{}
+
+	for {
+		result := listVaultsResult{}
+
+		path := "/vaults"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		req := s.request("GET", path, nil)
+
+		resp, err := req.Do()
+		if err != nil {
+			return vaults, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return vaults, err
+		}
+		vaults = append(vaults, result.VaultList...)
+
+		if result.Marker == "" {
+			return vaults, nil
+		}
+		query.Set("marker", result.Marker)
+	}
+}
@@ -0,0 +1,96 @@
+package glacier
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateVault(t *testing.T) {
+	Convey("Given a vault name", t, func() {
+		ts, s, requests := testService(testHTTP200)
+		defer ts.Close()
+
+		err := s.CreateVault("logs")
+
+		Convey("It is created", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Method, ShouldEqual, "PUT")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults/logs")
+		})
+	})
+}
+
+func TestDescribeVault(t *testing.T) {
+	Convey("Given a vault", t, func() {
+		ts, s, _ := testService(testJson(`{"VaultARN":"arn:aws:glacier:us-east-1:123456789012:vaults/logs","VaultName":"logs",
+			"CreationDate":"2015-07-27T12:00:00.000Z","LastInventoryDate":"2015-07-28T03:00:00.000Z","NumberOfArchives":42,"SizeInBytes":1048576}`))
+		defer ts.Close()
+
+		vault, err := s.DescribeVault("logs")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(vault.VaultName, ShouldEqual, "logs")
+			So(vault.CreationDate.Equal(time.Date(2015, 7, 27, 12, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			So(vault.NumberOfArchives, ShouldEqual, 42)
+			So(vault.SizeInBytes, ShouldEqual, 1048576)
+		})
+	})
+	Convey("Given a vault without an inventory yet", t, func() {
+		ts, s, _ := testService(testJson(`{"VaultName":"logs","CreationDate":"2015-07-27T12:00:00.000Z","LastInventoryDate":null}`))
+		defer ts.Close()
+
+		vault, err := s.DescribeVault("logs")
+
+		Convey("Its inventory date is zero", func() {
+			So(err, ShouldBeNil)
+			So(vault.LastInventoryDate.IsZero(), ShouldBeTrue)
+		})
+	})
+	Convey("Given a vault that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP404)
+		defer ts.Close()
+
+		_, err := s.DescribeVault("logs")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
+
+func TestListVaults(t *testing.T) {
+	Convey("Given vaults that take two pages", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("marker") == "page2" {
+				w.Write([]byte(`{"VaultList":[{"VaultName":"backups"}],"Marker":null}`))
+				return
+			}
+			w.Write([]byte(`{"VaultList":[{"VaultName":"logs"}],"Marker":"page2"}`))
+		})
+		defer ts.Close()
+
+		vaults, err := s.ListVaults()
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(vaults), ShouldEqual, 2)
+			So(vaults[1].VaultName, ShouldEqual, "backups")
+			So(requests()[0].URI, ShouldEqual, "/-/vaults")
+			So(requests()[1].URI, ShouldEqual, "/-/vaults?marker=page2")
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.ListVaults()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,19 @@
+// Code generated by errgen from the go:generate line in iam.go. DO NOT EDIT.
+
+package iam
+
+// These are errors IAM returns. errors.Is(err, iam.ErrNoSuchEntity) is true for any error with the same code, whatever its message.
+var (
+	ErrNoSuchEntity            = iamError{Code: "NoSuchEntity"}
+	ErrEntityAlreadyExists     = iamError{Code: "EntityAlreadyExists"}
+	ErrDeleteConflict          = iamError{Code: "DeleteConflict"}
+	ErrLimitExceeded           = iamError{Code: "LimitExceeded"}
+	ErrMalformedPolicyDocument = iamError{Code: "MalformedPolicyDocument"}
+	ErrServiceFailure          = iamError{Code: "ServiceFailure"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e iamError) Is(target error) bool {
+	t, ok := target.(iamError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,118 @@
+// Package iam provides a way to interact with the AWS Identity and Access Management service.
+package iam
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type iamError -field Code -service IAM NoSuchEntity EntityAlreadyExists DeleteConflict LimitExceeded MalformedPolicyDocument ServiceFailure
+
+// iamError is the error document returned from the IAM service.
+type iamError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the iamError into an error message.
+func (e iamError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func iamRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := iamError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// IAMService is the IAM service at AWS. IAM is global, so there is one endpoint, https://iam.amazonaws.com.
+type IAMService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// IAMAPI is implemented by *IAMService. Code that takes an IAMAPI can be given a fake in tests instead.
+type IAMAPI interface {
+	AttachRolePolicy(name string, policyArn string) error
+	AttachUserPolicy(name string, policyArn string) error
+	CreatePolicy(name string, document string, path string) (Policy, error)
+	CreateRole(name string, trustPolicy string, path string) (Role, error)
+	CreateUser(name string, path string) (User, error)
+	GetPolicy(arn string) (Policy, error)
+	GetRole(name string) (Role, error)
+	GetUser(name string) (User, error)
+	ListAttachedRolePolicies(name string) ([]AttachedPolicy, error)
+	ListPolicies(scope string) ([]Policy, error)
+	ListRoles(pathPrefix string) ([]Role, error)
+	ListUsers(pathPrefix string) ([]User, error)
+}
+
+var _ IAMAPI = (*IAMService)(nil)
+
+// New returns an IAMService with the options applied. IAM is global, so gaws.WithRegion does not change its endpoint.
+func New(options ...gaws.Option) *IAMService {
+	config := gaws.NewConfig(options...)
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://iam.amazonaws.com"
+	}
+	return &IAMService{Endpoint: endpoint, config: config}
+}
+
+func (s *IAMService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: iamRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("iam", "us-east-1"),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an IAM API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-05-08"},
+	}
+}
+
+// setPath sets the Path parameter, if there is a path. Paths organize users, roles, and policies, like /workers/.
+func setPath(params url.Values, name string, path string) {
+	if path != "" {
+		params.Set(name, path)
+	}
+}
+
+// TrustPolicy returns a policy document that lets the AWS services, like ec2.amazonaws.com or lambda.amazonaws.com, assume a role.
+func TrustPolicy(services ...string) string {
+	return fmt.Sprintf(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"Service":["%v"]},"Action":"sts:AssumeRole"}]}`, strings.Join(services, `","`))
+}
@@ -0,0 +1,129 @@
+package iam
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<GetRoleResponse><GetRoleResult>"))
+}
+
+var testNoSuchEntityError = []byte(`<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>NoSuchEntity</Code>
+    <Message>The role with name worker cannot be found.</Message>
+  </Error>
+  <RequestId>9dd01905-5012-5f99-8663-4b3ecd0dfaef</RequestId>
+</ErrorResponse>`)
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(404)
+	w.Write(testNoSuchEntityError)
+}
+
+var noSuchEntityError = iamError{Type: "Sender", Code: "NoSuchEntity", Message: "The role with name worker cannot be found."}
+
+// testPages returns a handler that returns first, then second when it is asked for the page after Marker page2.
+func testPages(first string, second string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("Marker") == "page2" {
+			w.Write([]byte(second))
+			return
+		}
+		w.Write([]byte(first))
+	}
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, IAMService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, IAMService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := iamRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := iamRetryPredicate(500, []byte("<ErrorResponse><Error><Code>ServiceFailure</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := iamRetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says an entity does not exist", t, func() {
+		result, err := iamRetryPredicate(404, testNoSuchEntityError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, noSuchEntityError)
+		})
+	})
+}
+
+func TestTrustPolicy(t *testing.T) {
+	Convey("Given services", t, func() {
+		policy := TrustPolicy("ec2.amazonaws.com", "lambda.amazonaws.com")
+
+		Convey("The policy lets them assume a role", func() {
+			document := struct {
+				Statement []struct {
+					Effect    string
+					Principal struct{ Service []string }
+					Action    string
+				}
+			}{}
+			So(json.Unmarshal([]byte(policy), &document), ShouldBeNil)
+			So(document.Statement[0].Effect, ShouldEqual, "Allow")
+			So(document.Statement[0].Principal.Service, ShouldResemble, []string{"ec2.amazonaws.com", "lambda.amazonaws.com"})
+			So(document.Statement[0].Action, ShouldEqual, "sts:AssumeRole")
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the global one", func() {
+			So(s.Endpoint, ShouldEqual, "https://iam.amazonaws.com")
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
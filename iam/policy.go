@@ -0,0 +1,120 @@
+package iam
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// The scopes ListPolicies can list.
+const (
+	AllPolicies      = "All"
+	AWSPolicies      = "AWS"   // Policies managed by AWS, like AmazonKinesisReadOnlyAccess
+	CustomerPolicies = "Local" // Policies created in the account
+)
+
+// Policy is a managed policy.
+type Policy struct {
+	PolicyName       string
+	PolicyId         string
+	Arn              string
+	Path             string
+	DefaultVersionId string
+	AttachmentCount  int
+	IsAttachable     bool
+	Description      string
+	CreateDate       time.Time
+	UpdateDate       time.Time
+}
+
+type createPolicyResponse struct {
+	Policy Policy `xml:"CreatePolicyResult>Policy"`
+}
+
+// CreatePolicy creates a managed policy from a JSON policy document. path organizes policies, like /workers/. If it is empty, / is used.
+// It is calling the CreatePolicy API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_CreatePolicy.html for more details.
+func (s *IAMService) CreatePolicy(name string, document string, path string) (Policy, error) {
+	result := createPolicyResponse{}
+
+	params := query("CreatePolicy")
+	params.Set("PolicyName", name)
+	params.Set("PolicyDocument", document)
+	setPath(params, "Path", path)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Policy, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Policy, err
+}
+
+type getPolicyResponse struct {
+	Policy Policy `xml:"GetPolicyResult>Policy"`
+}
+
+// GetPolicy describes a managed policy. It is calling the GetPolicy API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_GetPolicy.html for more details.
+func (s *IAMService) GetPolicy(arn string) (Policy, error) {
+	result := getPolicyResponse{}
+
+	params := query("GetPolicy")
+	params.Set("PolicyArn", arn)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Policy, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Policy, err
+}
+
+type listPoliciesResponse struct {
+	Policies    []Policy `xml:"ListPoliciesResult>Policies>member"`
+	IsTruncated bool     `xml:"ListPoliciesResult>IsTruncated"`
+	Marker      string   `xml:"ListPoliciesResult>Marker"`
+}
+
+// ListPolicies lists the managed policies in the scope, AllPolicies, AWSPolicies, or CustomerPolicies. If it is empty, every policy is listed.
+// It is calling the ListPolicies API call until there are no more pages.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_ListPolicies.html for more details.
+func (s *IAMService) ListPolicies(scope string) ([]Policy, error) {
+	policies := []Policy{}
+
+	params := query("ListPolicies")
+	if scope != "" {
+		params.Set("Scope", scope)
+	}
+
+	for {
+		result := listPoliciesResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Policy{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Policy{}, err
+		}
+
+		policies = append(policies, result.Policies...)
+
+		if !result.IsTruncated {
+			return policies, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
@@ -0,0 +1,110 @@
+package iam
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testPolicy = `<Policy>
+  <PolicyName>checkpoints</PolicyName>
+  <PolicyId>ANPAJ2UCCR6DPCEXAMPLE</PolicyId>
+  <Arn>arn:aws:iam::123456789012:policy/workers/checkpoints</Arn>
+  <Path>/workers/</Path>
+  <DefaultVersionId>v1</DefaultVersionId>
+  <AttachmentCount>2</AttachmentCount>
+  <IsAttachable>true</IsAttachable>
+  <CreateDate>2015-03-01T12:00:00Z</CreateDate>
+  <UpdateDate>2015-03-01T12:00:00Z</UpdateDate>
+</Policy>`
+
+func TestCreatePolicy(t *testing.T) {
+	Convey("Given a policy document", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<CreatePolicyResponse><CreatePolicyResult>`+testPolicy+`</CreatePolicyResult></CreatePolicyResponse>`, ""), &params)
+		defer ts.Close()
+
+		document := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"dynamodb:*","Resource":"*"}]}`
+		policy, err := s.CreatePolicy("checkpoints", document, "/workers/")
+
+		Convey("It returns the policy", func() {
+			So(err, ShouldBeNil)
+			So(policy, ShouldResemble, Policy{
+				PolicyName:       "checkpoints",
+				PolicyId:         "ANPAJ2UCCR6DPCEXAMPLE",
+				Arn:              "arn:aws:iam::123456789012:policy/workers/checkpoints",
+				Path:             "/workers/",
+				DefaultVersionId: "v1",
+				AttachmentCount:  2,
+				IsAttachable:     true,
+				CreateDate:       time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC),
+				UpdateDate:       time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC),
+			})
+		})
+		Convey("It sends the document", func() {
+			So(params[0].Get("Action"), ShouldEqual, "CreatePolicy")
+			So(params[0].Get("PolicyName"), ShouldEqual, "checkpoints")
+			So(params[0].Get("PolicyDocument"), ShouldEqual, document)
+			So(params[0].Get("Path"), ShouldEqual, "/workers/")
+		})
+	})
+}
+
+func TestGetPolicy(t *testing.T) {
+	Convey("Given a policy", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<GetPolicyResponse><GetPolicyResult>`+testPolicy+`</GetPolicyResult></GetPolicyResponse>`, ""), &params)
+		defer ts.Close()
+
+		policy, err := s.GetPolicy("arn:aws:iam::123456789012:policy/workers/checkpoints")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(policy.PolicyName, ShouldEqual, "checkpoints")
+			So(params[0].Get("PolicyArn"), ShouldEqual, "arn:aws:iam::123456789012:policy/workers/checkpoints")
+		})
+	})
+	Convey("Given a policy that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.GetPolicy("arn:aws:iam::123456789012:policy/foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, noSuchEntityError)
+		})
+	})
+}
+
+func TestListPolicies(t *testing.T) {
+	Convey("Given policies that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<ListPoliciesResponse><ListPoliciesResult><Policies><member><PolicyName>checkpoints</PolicyName></member></Policies><IsTruncated>true</IsTruncated><Marker>page2</Marker></ListPoliciesResult></ListPoliciesResponse>`,
+			`<ListPoliciesResponse><ListPoliciesResult><Policies><member><PolicyName>streams</PolicyName></member></Policies><IsTruncated>false</IsTruncated></ListPoliciesResult></ListPoliciesResponse>`,
+		), &params)
+		defer ts.Close()
+
+		policies, err := s.ListPolicies(CustomerPolicies)
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(policies), ShouldEqual, 2)
+			So(policies[1].PolicyName, ShouldEqual, "streams")
+			So(params[0].Get("Scope"), ShouldEqual, "Local")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ListPolicies("")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchEntityError)
+		})
+	})
+}
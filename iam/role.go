@@ -0,0 +1,173 @@
+package iam
+
+import (
+	"encoding/xml"
+	"net/url"
+	"time"
+)
+
+// Role is an IAM role.
+type Role struct {
+	Path                     string
+	RoleName                 string
+	RoleId                   string
+	Arn                      string
+	CreateDate               time.Time
+	AssumeRolePolicyDocument string // The trust policy, which says who can assume the role. It is URL encoded, so use TrustPolicyDocument to read it.
+	Description              string
+}
+
+// TrustPolicyDocument returns the role's trust policy, decoded.
+func (r Role) TrustPolicyDocument() (string, error) {
+	return url.QueryUnescape(r.AssumeRolePolicyDocument)
+}
+
+type createRoleResponse struct {
+	Role Role `xml:"CreateRoleResult>Role"`
+}
+
+// CreateRole creates a role that can be assumed by who trustPolicy allows, like TrustPolicy("ec2.amazonaws.com").
+// path organizes roles, like /workers/. If it is empty, / is used. It is calling the CreateRole API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_CreateRole.html for more details.
+func (s *IAMService) CreateRole(name string, trustPolicy string, path string) (Role, error) {
+	result := createRoleResponse{}
+
+	params := query("CreateRole")
+	params.Set("RoleName", name)
+	params.Set("AssumeRolePolicyDocument", trustPolicy)
+	setPath(params, "Path", path)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Role, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Role, err
+}
+
+type getRoleResponse struct {
+	Role Role `xml:"GetRoleResult>Role"`
+}
+
+// GetRole describes a role. It is calling the GetRole API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_GetRole.html for more details.
+func (s *IAMService) GetRole(name string) (Role, error) {
+	result := getRoleResponse{}
+
+	params := query("GetRole")
+	params.Set("RoleName", name)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Role, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Role, err
+}
+
+type listRolesResponse struct {
+	Roles       []Role `xml:"ListRolesResult>Roles>member"`
+	IsTruncated bool   `xml:"ListRolesResult>IsTruncated"`
+	Marker      string `xml:"ListRolesResult>Marker"`
+}
+
+// ListRoles lists the roles whose paths start with pathPrefix. If it is empty, every role is listed.
+// It is calling the ListRoles API call until there are no more pages.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_ListRoles.html for more details.
+func (s *IAMService) ListRoles(pathPrefix string) ([]Role, error) {
+	roles := []Role{}
+
+	params := query("ListRoles")
+	setPath(params, "PathPrefix", pathPrefix)
+
+	for {
+		result := listRolesResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Role{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Role{}, err
+		}
+
+		roles = append(roles, result.Roles...)
+
+		if !result.IsTruncated {
+			return roles, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
+
+// AttachRolePolicy attaches a managed policy to a role. It is calling the AttachRolePolicy API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_AttachRolePolicy.html for more details.
+func (s *IAMService) AttachRolePolicy(name string, policyArn string) error {
+	params := query("AttachRolePolicy")
+	params.Set("RoleName", name)
+	params.Set("PolicyArn", policyArn)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
+
+// AttachedPolicy is a managed policy attached to a user or role.
+type AttachedPolicy struct {
+	PolicyName string
+	PolicyArn  string
+}
+
+type listAttachedRolePoliciesResponse struct {
+	AttachedPolicies []AttachedPolicy `xml:"ListAttachedRolePoliciesResult>AttachedPolicies>member"`
+	IsTruncated      bool             `xml:"ListAttachedRolePoliciesResult>IsTruncated"`
+	Marker           string           `xml:"ListAttachedRolePoliciesResult>Marker"`
+}
+
+// ListAttachedRolePolicies lists the managed policies attached to a role. It is calling the ListAttachedRolePolicies API call until there are no more pages.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_ListAttachedRolePolicies.html for more details.
+func (s *IAMService) ListAttachedRolePolicies(name string) ([]AttachedPolicy, error) {
+	policies := []AttachedPolicy{}
+
+	params := query("ListAttachedRolePolicies")
+	params.Set("RoleName", name)
+
+	for {
+		result := listAttachedRolePoliciesResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []AttachedPolicy{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []AttachedPolicy{}, err
+		}
+
+		policies = append(policies, result.AttachedPolicies...)
+
+		if !result.IsTruncated {
+			return policies, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
@@ -0,0 +1,139 @@
+package iam
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testRole = `<Role>
+  <Path>/workers/</Path>
+  <RoleName>kinesis-worker</RoleName>
+  <RoleId>AROADBQP57FF2AEXAMPLE</RoleId>
+  <Arn>arn:aws:iam::123456789012:role/workers/kinesis-worker</Arn>
+  <CreateDate>2015-03-01T12:00:00Z</CreateDate>
+  <AssumeRolePolicyDocument>%7B%22Version%22%3A%222012-10-17%22%7D</AssumeRolePolicyDocument>
+</Role>`
+
+func TestCreateRole(t *testing.T) {
+	Convey("Given a role name, trust policy and path", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<CreateRoleResponse><CreateRoleResult>`+testRole+`</CreateRoleResult></CreateRoleResponse>`, ""), &params)
+		defer ts.Close()
+
+		role, err := s.CreateRole("kinesis-worker", TrustPolicy("ec2.amazonaws.com"), "/workers/")
+
+		Convey("It returns the role", func() {
+			So(err, ShouldBeNil)
+			So(role.RoleName, ShouldEqual, "kinesis-worker")
+			So(role.Arn, ShouldEqual, "arn:aws:iam::123456789012:role/workers/kinesis-worker")
+		})
+		Convey("Its trust policy can be decoded", func() {
+			policy, err := role.TrustPolicyDocument()
+			So(err, ShouldBeNil)
+			So(policy, ShouldEqual, `{"Version":"2012-10-17"}`)
+		})
+		Convey("It sends the trust policy", func() {
+			So(params[0].Get("Action"), ShouldEqual, "CreateRole")
+			So(params[0].Get("RoleName"), ShouldEqual, "kinesis-worker")
+			So(params[0].Get("AssumeRolePolicyDocument"), ShouldEqual, TrustPolicy("ec2.amazonaws.com"))
+			So(params[0].Get("Path"), ShouldEqual, "/workers/")
+		})
+	})
+}
+
+func TestGetRole(t *testing.T) {
+	Convey("Given a role", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<GetRoleResponse><GetRoleResult>`+testRole+`</GetRoleResult></GetRoleResponse>`, ""), &params)
+		defer ts.Close()
+
+		role, err := s.GetRole("kinesis-worker")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(role.RoleId, ShouldEqual, "AROADBQP57FF2AEXAMPLE")
+			So(params[0].Get("RoleName"), ShouldEqual, "kinesis-worker")
+		})
+	})
+	Convey("Given a role that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.GetRole("worker")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, noSuchEntityError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.GetRole("worker")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListRoles(t *testing.T) {
+	Convey("Given roles that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<ListRolesResponse><ListRolesResult><Roles><member><RoleName>kinesis-worker</RoleName></member></Roles><IsTruncated>true</IsTruncated><Marker>page2</Marker></ListRolesResult></ListRolesResponse>`,
+			`<ListRolesResponse><ListRolesResult><Roles><member><RoleName>dynamodb-worker</RoleName></member></Roles><IsTruncated>false</IsTruncated></ListRolesResult></ListRolesResponse>`,
+		), &params)
+		defer ts.Close()
+
+		roles, err := s.ListRoles("")
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(roles), ShouldEqual, 2)
+			So(roles[1].RoleName, ShouldEqual, "dynamodb-worker")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+	})
+}
+
+func TestAttachRolePolicy(t *testing.T) {
+	Convey("Given a role and a policy", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.AttachRolePolicy("kinesis-worker", "arn:aws:iam::aws:policy/AmazonKinesisFullAccess")
+
+		Convey("The policy is attached", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "AttachRolePolicy")
+			So(params[0].Get("RoleName"), ShouldEqual, "kinesis-worker")
+			So(params[0].Get("PolicyArn"), ShouldEqual, "arn:aws:iam::aws:policy/AmazonKinesisFullAccess")
+		})
+	})
+}
+
+func TestListAttachedRolePolicies(t *testing.T) {
+	Convey("Given a role with policies that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<ListAttachedRolePoliciesResponse><ListAttachedRolePoliciesResult><AttachedPolicies><member><PolicyName>AmazonKinesisFullAccess</PolicyName><PolicyArn>arn:aws:iam::aws:policy/AmazonKinesisFullAccess</PolicyArn></member></AttachedPolicies><IsTruncated>true</IsTruncated><Marker>page2</Marker></ListAttachedRolePoliciesResult></ListAttachedRolePoliciesResponse>`,
+			`<ListAttachedRolePoliciesResponse><ListAttachedRolePoliciesResult><AttachedPolicies><member><PolicyName>checkpoints</PolicyName></member></AttachedPolicies><IsTruncated>false</IsTruncated></ListAttachedRolePoliciesResult></ListAttachedRolePoliciesResponse>`,
+		), &params)
+		defer ts.Close()
+
+		policies, err := s.ListAttachedRolePolicies("kinesis-worker")
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(policies, ShouldResemble, []AttachedPolicy{
+				{PolicyName: "AmazonKinesisFullAccess", PolicyArn: "arn:aws:iam::aws:policy/AmazonKinesisFullAccess"},
+				{PolicyName: "checkpoints"},
+			})
+			So(params[0].Get("RoleName"), ShouldEqual, "kinesis-worker")
+		})
+	})
+}
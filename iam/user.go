@@ -0,0 +1,120 @@
+package iam
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// User is an IAM user.
+type User struct {
+	Path       string
+	UserName   string
+	UserId     string
+	Arn        string
+	CreateDate time.Time
+}
+
+type createUserResponse struct {
+	User User `xml:"CreateUserResult>User"`
+}
+
+// CreateUser creates a user. path organizes users, like /workers/. If it is empty, / is used. It is calling the CreateUser API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_CreateUser.html for more details.
+func (s *IAMService) CreateUser(name string, path string) (User, error) {
+	result := createUserResponse{}
+
+	params := query("CreateUser")
+	params.Set("UserName", name)
+	setPath(params, "Path", path)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.User, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.User, err
+}
+
+type getUserResponse struct {
+	User User `xml:"GetUserResult>User"`
+}
+
+// GetUser describes a user. If name is empty, it describes the user whose credentials sign the request. It is calling the GetUser API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_GetUser.html for more details.
+func (s *IAMService) GetUser(name string) (User, error) {
+	result := getUserResponse{}
+
+	params := query("GetUser")
+	if name != "" {
+		params.Set("UserName", name)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.User, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.User, err
+}
+
+type listUsersResponse struct {
+	Users       []User `xml:"ListUsersResult>Users>member"`
+	IsTruncated bool   `xml:"ListUsersResult>IsTruncated"`
+	Marker      string `xml:"ListUsersResult>Marker"`
+}
+
+// ListUsers lists the users whose paths start with pathPrefix. If it is empty, every user is listed.
+// It is calling the ListUsers API call until there are no more pages.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_ListUsers.html for more details.
+func (s *IAMService) ListUsers(pathPrefix string) ([]User, error) {
+	users := []User{}
+
+	params := query("ListUsers")
+	setPath(params, "PathPrefix", pathPrefix)
+
+	for {
+		result := listUsersResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []User{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []User{}, err
+		}
+
+		users = append(users, result.Users...)
+
+		if !result.IsTruncated {
+			return users, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
+
+// AttachUserPolicy attaches a managed policy to a user. It is calling the AttachUserPolicy API call.
+// See http://docs.aws.amazon.com/IAM/latest/APIReference/API_AttachUserPolicy.html for more details.
+func (s *IAMService) AttachUserPolicy(name string, policyArn string) error {
+	params := query("AttachUserPolicy")
+	params.Set("UserName", name)
+	params.Set("PolicyArn", policyArn)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,132 @@
+package iam
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testUser = `<User>
+  <Path>/workers/</Path>
+  <UserName>web-1</UserName>
+  <UserId>AIDACKCEVSQ6C2EXAMPLE</UserId>
+  <Arn>arn:aws:iam::123456789012:user/workers/web-1</Arn>
+  <CreateDate>2015-03-01T12:00:00Z</CreateDate>
+</User>`
+
+func TestCreateUser(t *testing.T) {
+	Convey("Given a user name and path", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<CreateUserResponse><CreateUserResult>`+testUser+`</CreateUserResult></CreateUserResponse>`, ""), &params)
+		defer ts.Close()
+
+		user, err := s.CreateUser("web-1", "/workers/")
+
+		Convey("It returns the user", func() {
+			So(err, ShouldBeNil)
+			So(user, ShouldResemble, User{
+				Path:       "/workers/",
+				UserName:   "web-1",
+				UserId:     "AIDACKCEVSQ6C2EXAMPLE",
+				Arn:        "arn:aws:iam::123456789012:user/workers/web-1",
+				CreateDate: time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC),
+			})
+		})
+		Convey("It sends the name and path", func() {
+			So(params[0].Get("Action"), ShouldEqual, "CreateUser")
+			So(params[0].Get("Version"), ShouldEqual, "2010-05-08")
+			So(params[0].Get("UserName"), ShouldEqual, "web-1")
+			So(params[0].Get("Path"), ShouldEqual, "/workers/")
+		})
+	})
+	Convey("Given no path", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		s.CreateUser("web-1", "")
+
+		Convey("It is not sent", func() {
+			_, hasPath := params[0]["Path"]
+			So(hasPath, ShouldBeFalse)
+		})
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	Convey("Given no user name", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<GetUserResponse><GetUserResult>`+testUser+`</GetUserResult></GetUserResponse>`, ""), &params)
+		defer ts.Close()
+
+		user, err := s.GetUser("")
+
+		Convey("It describes the caller", func() {
+			So(err, ShouldBeNil)
+			So(user.UserName, ShouldEqual, "web-1")
+			So(params[0].Get("Action"), ShouldEqual, "GetUser")
+			_, hasName := params[0]["UserName"]
+			So(hasName, ShouldBeFalse)
+		})
+	})
+	Convey("Given a user that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.GetUser("foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, noSuchEntityError)
+		})
+	})
+}
+
+func TestListUsers(t *testing.T) {
+	Convey("Given users that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<ListUsersResponse><ListUsersResult><Users><member><UserName>web-1</UserName></member></Users><IsTruncated>true</IsTruncated><Marker>page2</Marker></ListUsersResult></ListUsersResponse>`,
+			`<ListUsersResponse><ListUsersResult><Users><member><UserName>web-2</UserName></member></Users><IsTruncated>false</IsTruncated></ListUsersResult></ListUsersResponse>`,
+		), &params)
+		defer ts.Close()
+
+		users, err := s.ListUsers("/workers/")
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(users), ShouldEqual, 2)
+			So(users[1].UserName, ShouldEqual, "web-2")
+			So(params[0].Get("PathPrefix"), ShouldEqual, "/workers/")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.ListUsers("")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestAttachUserPolicy(t *testing.T) {
+	Convey("Given a user and a policy", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.AttachUserPolicy("web-1", "arn:aws:iam::aws:policy/AmazonKinesisReadOnlyAccess")
+
+		Convey("The policy is attached", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "AttachUserPolicy")
+			So(params[0].Get("UserName"), ShouldEqual, "web-1")
+			So(params[0].Get("PolicyArn"), ShouldEqual, "arn:aws:iam::aws:policy/AmazonKinesisReadOnlyAccess")
+		})
+	})
+}
@@ -0,0 +1,105 @@
+// Command errgen writes the errors.go of a service package, which has an exported error for each error code the service returns that
+// callers may want to handle, and an Is method on the package's error type so that errors.Is matches them. It is run by go generate,
+// from a line next to the error type:
+//
+//	//go:generate go run ../internal/errgen -type kinesisError -field Type -service Kinesis ResourceInUseException ...
+//
+// Field is the field of the error type that holds the code, which is Type for JSON services and Code for the rest. Types of JSON
+// services can start with a namespace, like com.amazonaws.dynamodb.v20120810#ResourceNotFoundException, which is ignored.
+// An error is named for its code without dots or an Exception, Fault or Error suffix, like ErrResourceInUse. A code can be named by
+// giving it as Name=Code instead.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// suffixes are left off the end of codes to name their errors.
+var suffixes = []string{"Exception", "Fault", "Error"}
+
+// name returns the name of the error for a code.
+func name(code string) string {
+	name := strings.Replace(code, ".", "", -1)
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(name, suffix) && name != suffix {
+			name = strings.TrimSuffix(name, suffix)
+			break
+		}
+	}
+	return "Err" + name
+}
+
+// generate returns the source of errors.go for the package.
+func generate(pkg string, source string, service string, errorType string, field string, codes []string) ([]byte, error) {
+	if field != "Type" && field != "Code" {
+		return nil, fmt.Errorf("errgen: the field must be Type or Code, not %q", field)
+	}
+	if len(codes) == 0 {
+		return nil, errors.New("errgen: no codes were given")
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "// Code generated by errgen from the go:generate line in %v. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(b, "package %v\n\n", pkg)
+	if field == "Type" {
+		fmt.Fprintf(b, "import \"strings\"\n\n")
+	}
+
+	codes = append([]string(nil), codes...)
+	names := make([]string, len(codes))
+	named := map[string]bool{}
+	for i, code := range codes {
+		names[i] = name(code)
+		if j := strings.Index(code, "="); j >= 0 {
+			names[i], codes[i] = code[:j], code[j+1:]
+		}
+		if named[names[i]] {
+			return nil, fmt.Errorf("errgen: %v is the name of more than one code", names[i])
+		}
+		named[names[i]] = true
+	}
+
+	fmt.Fprintf(b, "// These are errors %v returns. errors.Is(err, %v.%v) is true for any error with the same %v, whatever its message.\n", service, pkg, names[0], strings.ToLower(field))
+	fmt.Fprintf(b, "var (\n")
+	for i, code := range codes {
+		fmt.Fprintf(b, "%v = %v{%v: %q}\n", names[i], errorType, field, code)
+	}
+	fmt.Fprintf(b, ")\n\n")
+
+	fmt.Fprintf(b, "// Is reports whether target is an error with the same %v as e, so that errors.Is matches the errors above.\n", strings.ToLower(field))
+	fmt.Fprintf(b, "func (e %v) Is(target error) bool {\n", errorType)
+	fmt.Fprintf(b, "t, ok := target.(%v)\n", errorType)
+	if field == "Type" {
+		fmt.Fprintf(b, "return ok && t.Type != \"\" && (e.Type == t.Type || strings.HasSuffix(e.Type, \"#\"+t.Type))\n")
+	} else {
+		fmt.Fprintf(b, "return ok && t.Code != \"\" && e.Code == t.Code\n")
+	}
+	fmt.Fprintf(b, "}\n")
+
+	return format.Source(b.Bytes())
+}
+
+func main() {
+	errorType := flag.String("type", "", "the error type of the package")
+	field := flag.String("field", "Type", "the field of the error type that holds the code")
+	service := flag.String("service", "", "the name of the service, for documentation")
+	flag.Parse()
+
+	source, err := generate(os.Getenv("GOPACKAGE"), os.Getenv("GOFILE"), *service, *errorType, *field, flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = ioutil.WriteFile("errors.go", source, 0644)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestName(t *testing.T) {
+	Convey("Given codes", t, func() {
+		Convey("Their suffixes are left off", func() {
+			So(name("ResourceInUseException"), ShouldEqual, "ErrResourceInUse")
+			So(name("UnknownResourceFault"), ShouldEqual, "ErrUnknownResource")
+			So(name("ValidationError"), ShouldEqual, "ErrValidation")
+			So(name("NoSuchKey"), ShouldEqual, "ErrNoSuchKey")
+		})
+		Convey("Their dots are left out", func() {
+			So(name("InvalidInstanceID.NotFound"), ShouldEqual, "ErrInvalidInstanceIDNotFound")
+		})
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	Convey("Given a JSON service", t, func() {
+		source, err := generate("kinesis", "kinesis.go", "Kinesis", "kinesisError", "Type", []string{"ResourceInUseException", "ErrExpired=ExpiredIteratorException"})
+
+		Convey("It has an error for each code", func() {
+			So(err, ShouldBeNil)
+			So(string(source), ShouldContainSubstring, `ErrResourceInUse = kinesisError{Type: "ResourceInUseException"}`)
+			So(string(source), ShouldContainSubstring, "ErrExpired ")
+			So(string(source), ShouldContainSubstring, `kinesisError{Type: "ExpiredIteratorException"}`)
+		})
+		Convey("Its types can have a namespace", func() {
+			So(string(source), ShouldContainSubstring, `strings.HasSuffix(e.Type, "#"+t.Type)`)
+		})
+	})
+	Convey("Given a query service", t, func() {
+		source, err := generate("sqs", "sqs.go", "SQS", "sqsError", "Code", []string{"QueueAlreadyExists"})
+
+		Convey("Its codes are compared", func() {
+			So(err, ShouldBeNil)
+			So(string(source), ShouldContainSubstring, `ErrQueueAlreadyExists = sqsError{Code: "QueueAlreadyExists"}`)
+			So(string(source), ShouldContainSubstring, `e.Code == t.Code`)
+			So(string(source), ShouldNotContainSubstring, `"strings"`)
+		})
+	})
+	Convey("Given two codes with the same name", t, func() {
+		_, err := generate("emr", "emr.go", "EMR", "emrError", "Type", []string{"InternalServerException", "InternalServerError"})
+
+		Convey("It fails", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a field that is not Type or Code", t, func() {
+		_, err := generate("s3", "s3.go", "S3", "s3Error", "Message", []string{"NoSuchKey"})
+
+		Convey("It fails", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,9 @@
+// Package integration runs gaws against AWS emulators, like localstack, kinesalite and dynamodb-local, to check that requests and responses
+// really work end to end. Its tests are behind the integration build tag, so they only run when asked for:
+//
+//	go test -tags integration ./internal/integration/
+//
+// By default every service is at localstack's endpoint, http://localhost:4566. GAWS_ENDPOINT changes that, and GAWS_KINESIS_ENDPOINT
+// and GAWS_DYNAMODB_ENDPOINT point a single service somewhere else, like kinesalite on http://localhost:4567 or dynamodb-local on
+// http://localhost:8000. Requests are signed with fake credentials, which the emulators accept.
+package integration
@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/controlgroup/gaws/dynamodb"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDynamoDBItems(t *testing.T) {
+	Convey("Given a new table", t, func() {
+		d := dynamodb.New(options("dynamodb")...)
+		definition := dynamodb.TableDefinition{
+			TableName: uniqueName("gaws-integration"),
+			AttributeDefinitions: []dynamodb.AttributeDefinition{
+				{AttributeName: "device", AttributeType: "S"},
+				{AttributeName: "time", AttributeType: "N"},
+			},
+			KeySchema: []dynamodb.KeySchemaElement{
+				{AttributeName: "device", KeyType: "HASH"},
+				{AttributeName: "time", KeyType: "RANGE"},
+			},
+			BillingMode: "PAY_PER_REQUEST",
+		}
+
+		description, err := d.CreateTableIfNotExists(definition)
+		So(err, ShouldBeNil)
+		So(description.TableStatus, ShouldEqual, "ACTIVE")
+
+		table := &dynamodb.Table{Name: definition.TableName, Service: d}
+
+		Convey("An item put to it can be read back", func() {
+			item := dynamodb.Item{"device": dynamodb.S("sensor-1"), "time": dynamodb.N("100"), "reading": dynamodb.N("21.5")}
+			So(table.PutItem(item), ShouldBeNil)
+
+			got, err := table.GetItem(dynamodb.Item{"device": dynamodb.S("sensor-1"), "time": dynamodb.N("100")}, true)
+			So(err, ShouldBeNil)
+			So(got["reading"].N, ShouldEqual, "21.5")
+		})
+
+		Convey("Items can be queried by key and written in batches", func() {
+			w := table.NewBatchWriter()
+			for _, time := range []string{"100", "200", "300"} {
+				w.Put(dynamodb.Item{"device": dynamodb.S("sensor-2"), "time": dynamodb.N(time)})
+			}
+			So(w.Close(), ShouldBeNil)
+
+			items, err := table.Query(dynamodb.HashKey("device", dynamodb.S("sensor-2")).GreaterThan("time", dynamodb.N("100")))
+			So(err, ShouldBeNil)
+			So(len(items), ShouldEqual, 2)
+		})
+
+		Convey("Creating it again does nothing", func() {
+			_, err := d.CreateTableIfNotExists(definition)
+			So(err, ShouldBeNil)
+		})
+	})
+}
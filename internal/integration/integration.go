@@ -0,0 +1,56 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// defaultEndpoint is where localstack listens for every service.
+const defaultEndpoint = "http://localhost:4566"
+
+// WaitTimeout is how long eventually keeps trying. Emulators are quick, so it is short.
+var WaitTimeout = 30 * time.Second
+
+// options returns the options for a client of the emulator for the service, like kinesis. Its requests are signed with fake credentials.
+func options(service string) []gaws.Option {
+	return []gaws.Option{
+		gaws.WithEndpoint(endpoint(service)),
+		gaws.WithCredentials(gaws.StaticCredentials{AccessKeyID: "test", SecretAccessKey: "test"}),
+	}
+}
+
+// endpoint returns the endpoint of the emulator for the service.
+func endpoint(service string) string {
+	if e := os.Getenv("GAWS_" + strings.ToUpper(service) + "_ENDPOINT"); e != "" {
+		return e
+	}
+	if e := os.Getenv("GAWS_ENDPOINT"); e != "" {
+		return e
+	}
+	return defaultEndpoint
+}
+
+// uniqueName returns a name that starts with prefix and is not used by another run, so runs against the same emulator do not collide.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%v-%d", prefix, time.Now().UnixNano())
+}
+
+// eventually calls done until it returns true or an error, or WaitTimeout passes.
+func eventually(done func() (bool, error)) error {
+	deadline := time.Now().Add(WaitTimeout)
+	for time.Now().Before(deadline) {
+		finished, err := done()
+		if err != nil || finished {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return fmt.Errorf("gave up after %v", WaitTimeout)
+}
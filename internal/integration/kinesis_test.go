@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"testing"
+
+	"github.com/controlgroup/gaws/kinesis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestKinesisStreamLifecycle(t *testing.T) {
+	Convey("Given a new stream", t, func() {
+		k := kinesis.New(options("kinesis")...)
+		name := uniqueName("gaws-integration")
+
+		stream, err := k.CreateStream(name, 1)
+		So(err, ShouldBeNil)
+		defer stream.Delete()
+
+		err = eventually(func() (bool, error) {
+			description, err := stream.Describe()
+			return description.StreamStatus == "ACTIVE", err
+		})
+		So(err, ShouldBeNil)
+
+		Convey("It is listed", func() {
+			streams, err := k.ListStreams()
+			So(err, ShouldBeNil)
+
+			names := []string{}
+			for _, s := range streams {
+				names = append(names, s.Name)
+			}
+			So(names, ShouldContain, name)
+		})
+
+		Convey("A record put to it can be read back", func() {
+			So(stream.PutRecord("key", []byte("hello from gaws")), ShouldBeNil)
+
+			description, err := stream.Describe()
+			So(err, ShouldBeNil)
+			So(len(description.Shards), ShouldEqual, 1)
+
+			iterator, err := description.Shards[0].GetShardIterator("TRIM_HORIZON", "")
+			So(err, ShouldBeNil)
+
+			var records []kinesis.Record
+			err = eventually(func() (bool, error) {
+				var err error
+				records, iterator, err = k.GetRecords(iterator, 0)
+				return len(records) > 0, err
+			})
+			So(err, ShouldBeNil)
+
+			data, err := records[0].Bytes()
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, "hello from gaws")
+			So(records[0].PartitionKey, ShouldEqual, "key")
+		})
+	})
+}
@@ -0,0 +1,24 @@
+// Code generated by errgen from the go:generate line in kinesis.go. DO NOT EDIT.
+
+package kinesis
+
+import "strings"
+
+// These are errors Kinesis returns. errors.Is(err, kinesis.ErrResourceInUse) is true for any error with the same type, whatever its message.
+var (
+	ErrResourceInUse                 = kinesisError{Type: "ResourceInUseException"}
+	ErrResourceNotFound              = kinesisError{Type: "ResourceNotFoundException"}
+	ErrLimitExceeded                 = kinesisError{Type: "LimitExceededException"}
+	ErrInvalidArgument               = kinesisError{Type: "InvalidArgumentException"}
+	ErrProvisionedThroughputExceeded = kinesisError{Type: "ProvisionedThroughputExceededException"}
+	ErrExpiredIterator               = kinesisError{Type: "ExpiredIteratorException"}
+	ErrExpiredNextToken              = kinesisError{Type: "ExpiredNextTokenException"}
+	ErrAccessDenied                  = kinesisError{Type: "AccessDeniedException"}
+	ErrKMSThrottling                 = kinesisError{Type: "KMSThrottlingException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e kinesisError) Is(target error) bool {
+	t, ok := target.(kinesisError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
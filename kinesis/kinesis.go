@@ -2,12 +2,15 @@
 package kinesis
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
 	"github.com/controlgroup/gaws"
 )
 
+//go:generate go run ../internal/errgen -type kinesisError -field Type -service Kinesis ResourceInUseException ResourceNotFoundException LimitExceededException InvalidArgumentException ProvisionedThroughputExceededException ExpiredIteratorException ExpiredNextTokenException AccessDeniedException KMSThrottlingException
+
 // kinesisError is the error document returned from the Kinesis service.
 type kinesisError struct {
 	Type    string `json:"__type"`
@@ -53,14 +56,16 @@ func (s *KinesisService) request() gaws.AWSRequest {
 		RetryPredicate: kinesisRetryPredicate,
 		Method:         "POST",
 		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("kinesis", s.config.Region),
 		Headers: map[string]string{
 			"Content-Type": "application/x-amz-json-1.1",
 		},
 	}
+	s.config.Apply(&r)
 	return r
 }
 
-// putRecordRequest is a Kinesis record. These are put onto Streams.
+// putRecordRequest is a Kinesis record. These are put onto Streams. encodePutRecord writes it as JSON without making the Data string.
 type putRecordRequest struct {
 	StreamName   string
 	Data         string
@@ -70,6 +75,23 @@ type putRecordRequest struct {
 // KinesisService is the Kinesis service at AWS.
 type KinesisService struct {
 	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// KinesisAPI is implemented by *KinesisService. Code that takes a KinesisAPI can be given a fake in tests instead.
+type KinesisAPI interface {
+	CreateStream(name string, shardCount int) (Stream, error)
+	GetRecords(shardIterator string, limit int) ([]Record, string, error)
+	ListStreams() ([]Stream, error)
+	StreamRecords(shardIterator string) (<-chan Record, <-chan error)
+}
+
+var _ KinesisAPI = (*KinesisService)(nil)
+
+// New returns a KinesisService with the options applied. Its endpoint is gaws.Endpoint("kinesis", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *KinesisService {
+	config := gaws.NewConfig(options...)
+	return &KinesisService{Endpoint: config.ServiceEndpoint("kinesis"), config: config}
 }
 
 // Stream is a Kinesis stream
@@ -78,6 +100,17 @@ type Stream struct {
 	Service *KinesisService // The service for this region
 }
 
+// StreamAPI is implemented by *Stream. Code that takes a StreamAPI can be given a fake in tests instead.
+type StreamAPI interface {
+	Delete() error
+	Describe() (StreamDescription, error)
+	MergeShards(shardToMerge string, adjacentShardToMerge string) error
+	PutRecord(partitionKey string, data []byte) error
+	SplitShard(shardToSplit string, newStartingHashKey string) error
+}
+
+var _ StreamAPI = (*Stream)(nil)
+
 // createStreamRequest is the request to the CreateStream API call.
 type createStreamRequest struct {
 	ShardCount int
@@ -87,19 +120,9 @@ type createStreamRequest struct {
 // CreateStream creates a new Kinesis stream. It returns a Stream and an error if it fails.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_CreateStream.html for more details.
 func (s *KinesisService) CreateStream(name string, shardCount int) (Stream, error) {
-
-	stream := Stream{Name: name, Service: s}
-
 	body := createStreamRequest{StreamName: name, ShardCount: shardCount}
-	bodyAsJson, err := json.Marshal(body)
-
-	req := s.request()
-	req.Body = bodyAsJson
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.CreateStream"
-
-	_, err = req.Do()
-
-	return stream, err
+	_, err := gaws.Call[struct{}](context.Background(), s.request(), "Kinesis_20131202.CreateStream", body)
+	return Stream{Name: name, Service: s}, err
 }
 
 // listStreamsResult is the result of the ListStreams API call
@@ -113,19 +136,7 @@ type listStreamsResult struct {
 // ListStreams lists the Kinesis streams in an account. It returns a list of streams and an error if it fails.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_ListStreams.html for more details
 func (s *KinesisService) ListStreams() ([]Stream, error) {
-
-	req := s.request()
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.ListStreams"
-
-	body, err := req.Do()
-
-	if err != nil {
-		return []Stream{}, err
-	}
-
-	result := listStreamsResult{}
-	err = json.Unmarshal(body, &result)
-
+	result, err := gaws.Call[listStreamsResult](context.Background(), s.request(), "Kinesis_20131202.ListStreams", nil)
 	if err != nil {
 		return []Stream{}, err
 	}
@@ -158,39 +169,50 @@ type getRecordsResponse struct {
 	Records           []Record // A slice of Record structs
 }
 
+// maxGetRecordsLimit is the most records GetRecords can return, which is what Kinesis returns if no limit is given.
+const maxGetRecordsLimit = 10000
+
 // GetRecords returns one or more data records from a stream. limit can be an integer up to 10,000. If it is 0, this will use the default limit.
+// If the records are larger than the service's gaws.WithMaxResponseSize, it asks for half as many, until they fit or it asks for one.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_GetRecords.html for more details.
 func (s *KinesisService) GetRecords(shardIterator string, limit int) ([]Record, string, error) {
-	request := getRecordsRequest{ShardIterator: shardIterator, Limit: limit}
-	result := getRecordsResponse{}
-
-	req := s.request()
-
-	bodyAsJson, err := json.Marshal(request)
-
-	req.Body = bodyAsJson
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.GetRecords"
-
-	resp, err := req.Do()
+	body := getRecordsRequest{ShardIterator: shardIterator, Limit: limit}
+	result, err := gaws.Call[getRecordsResponse](context.Background(), s.request(), "Kinesis_20131202.GetRecords", body)
+	if _, tooLarge := err.(gaws.ResponseTooLargeError); tooLarge && limit != 1 {
+		// Reading records does not move the iterator, so the same records can be asked for again.
+		if limit == 0 {
+			limit = maxGetRecordsLimit
+		}
+		return s.GetRecords(shardIterator, limit/2)
+	}
 	if err != nil {
 		return []Record{}, "", err
 	}
-
-	err = json.Unmarshal(resp, &result)
-
-	return result.Records, result.NextShardIterator, err
-
+	return result.Records, result.NextShardIterator, nil
 }
 
 // BUG(drocamor): StreamRecords is a terrible name.
 
 // StreamRecords creates a goroutine and uses GetRecords to send records over a channel. If it encounters an error, it will send the error over the error channel and exit the goroutine.
+// If the service has gaws.WithQuotaLimits, it reads the shard no faster than its quotas allow, with NewShardReadLimiters.
 func (s *KinesisService) StreamRecords(shardIterator string) (<-chan Record, <-chan error) {
 	c := make(chan Record)
 	errc := make(chan error)
 	go func() {
+		var calls, bytes *gaws.RateLimiter
+		if s.config.QuotaLimits {
+			calls, bytes = s.NewShardReadLimiters()
+		}
+
 		for {
+			if calls != nil {
+				calls.Wait(context.Background(), 1)
+				bytes.Wait(context.Background(), 0)
+			}
 			records, newiterator, err := s.GetRecords(shardIterator, 0)
+			if bytes != nil {
+				bytes.Take(float64(recordsSize(records)))
+			}
 
 			if err != nil {
 				errc <- err
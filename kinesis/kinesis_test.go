@@ -2,10 +2,13 @@ package kinesis
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/controlgroup/gaws"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -158,13 +161,59 @@ func TestGetRecords(t *testing.T) {
 			So(err, ShouldNotBeNil)
 		})
 	})
+	Convey("When calling GetRecords on a stream whose records are too large until fewer are asked for", t, func() {
+		limits := []int{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request := getRecordsRequest{}
+			json.NewDecoder(r.Body).Decode(&request)
+			limits = append(limits, request.Limit)
+			if request.Limit == 0 || request.Limit > 2500 {
+				w.Write([]byte(strings.Repeat(" ", 2*len(testGetRecordsResult))))
+			}
+			w.Write(testGetRecordsResult)
+		}))
+		defer ts.Close()
+		ks := New(gaws.WithEndpoint(ts.URL), gaws.WithMaxResponseSize(int64(len(testGetRecordsResult))))
+
+		records, _, err := ks.GetRecords("foo", 0)
+
+		Convey("It asks for half as many until they fit", func() {
+			So(err, ShouldBeNil)
+			So(records[0].Data, ShouldEqual, "XzxkYXRhPl8w")
+			So(limits, ShouldResemble, []int{0, 5000, 2500})
+		})
+	})
+	Convey("When calling GetRecords on a stream whose records never fit", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testGetRecordsSuccess))
+		defer ts.Close()
+		ks := New(gaws.WithEndpoint(ts.URL), gaws.WithMaxResponseSize(10))
+
+		_, _, err := ks.GetRecords("foo", 2)
+
+		Convey("It returns the error after asking for one", func() {
+			So(err, ShouldResemble, gaws.ResponseTooLargeError{Operation: "Kinesis_20131202.GetRecords", Limit: 10})
+		})
+	})
+}
+
+// drainRecords closes the server a stream reads from, and reads the stream until its goroutine stops with an error.
+func drainRecords(ts *httptest.Server, c <-chan Record, errc <-chan error) {
+	ts.Close()
+	for {
+		select {
+		case <-c:
+		case <-errc:
+			return
+		}
+	}
 }
 
 func TestStreamRecords(t *testing.T) {
 	Convey("When StreamRecords is used on a service that returns a record", t, func() {
 		ts := httptest.NewServer(http.HandlerFunc(testGetRecordsSuccess))
 		ks := KinesisService{Endpoint: ts.URL}
-		c, _ := ks.StreamRecords("foo")
+		c, errc := ks.StreamRecords("foo")
+		defer drainRecords(ts, c, errc)
 		record := <-c
 		Convey("The record will be what we expect", func() {
 			So(record.Data, ShouldEqual, "XzxkYXRhPl8w")
@@ -173,7 +222,8 @@ func TestStreamRecords(t *testing.T) {
 	Convey("When StreamRecords is used on a service that returns an error", t, func() {
 		ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
 		ks := KinesisService{Endpoint: ts.URL}
-		_, e := ks.StreamRecords("foo")
+		c, e := ks.StreamRecords("foo")
+		defer drainRecords(ts, c, e)
 		Convey("The error will be returned on the error channel", func() {
 			So(e, ShouldNotBeNil)
 		})
@@ -189,7 +239,7 @@ func TestRetryPredicate(t *testing.T) {
 			So(result, ShouldBeFalse)
 		})
 
-		Convey("Error is not nil",func() {
+		Convey("Error is not nil", func() {
 			So(err, ShouldNotBeNil)
 		})
 	})
@@ -208,7 +258,7 @@ func TestRetryPredicate(t *testing.T) {
 			So(result, ShouldBeTrue)
 		})
 	})
-	
+
 	Convey("Given a response that is a \"ProvisionedThroughputExceededException\" type", t, func() {
 
 		result, _ := kinesisRetryPredicate(400, []byte("{\"__type\": \"ProvisionedThroughputExceededException\",\"message\":\"bar\"}"))
@@ -217,3 +267,32 @@ func TestRetryPredicate(t *testing.T) {
 		})
 	})
 }
+
+func TestErrors(t *testing.T) {
+	Convey("Given an error from Kinesis", t, func() {
+		_, err := kinesisRetryPredicate(400, []byte(`{"__type":"ResourceInUseException","message":"Stream foo under account 123 is in use."}`))
+
+		Convey("It is the error for its type, whatever its message", func() {
+			So(errors.Is(err, ErrResourceInUse), ShouldBeTrue)
+			So(errors.Is(err, ErrResourceNotFound), ShouldBeFalse)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("kinesis", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
@@ -0,0 +1,42 @@
+package kinesis
+
+import (
+	"encoding/base64"
+
+	"github.com/controlgroup/gaws"
+)
+
+// These are Kinesis's quotas for each shard of a stream.
+// See http://docs.aws.amazon.com/streams/latest/dev/service-sizes-and-limits.html for more details.
+const (
+	ShardGetRecordsPerSecond   = 5       // GetRecords calls a shard takes a second
+	ShardReadBytesPerSecond    = 2 << 20 // Bytes a shard returns a second
+	ShardWriteRecordsPerSecond = 1000    // Records a shard takes a second
+	ShardWriteBytesPerSecond   = 1 << 20 // Bytes a shard takes a second, including partition keys
+)
+
+// maxGetRecordsBytes is the most bytes one GetRecords call can return. Reading that much uses up the shard's reads for five seconds.
+const maxGetRecordsBytes = 10 << 20
+
+// NewShardReadLimiters returns limiters for reading a shard at its quotas, which wait on the service's clock: one for GetRecords calls,
+// and one for the bytes they return, which is paid for after each call. StreamRecords uses them with gaws.WithQuotaLimits.
+func (s *KinesisService) NewShardReadLimiters() (calls *gaws.RateLimiter, bytes *gaws.RateLimiter) {
+	return s.config.NewRateLimiter(ShardGetRecordsPerSecond, ShardGetRecordsPerSecond), s.config.NewRateLimiter(ShardReadBytesPerSecond, maxGetRecordsBytes)
+}
+
+// NewShardWriteLimiters returns limiters for writing to a stream of shards shards at its quotas, which wait on the service's clock: one
+// for records, and one for their bytes. Records are spread over the shards by partition key, so they only keep to the quotas if the keys
+// spread evenly.
+func (s *KinesisService) NewShardWriteLimiters(shards int) (records *gaws.RateLimiter, bytes *gaws.RateLimiter) {
+	return s.config.NewRateLimiter(float64(shards*ShardWriteRecordsPerSecond), float64(shards*ShardWriteRecordsPerSecond)),
+		s.config.NewRateLimiter(float64(shards*ShardWriteBytesPerSecond), float64(shards*ShardWriteBytesPerSecond))
+}
+
+// recordsSize returns the bytes Kinesis counts for records, which is their data and partition keys.
+func recordsSize(records []Record) int {
+	size := 0
+	for _, r := range records {
+		size += base64.StdEncoding.DecodedLen(len(r.Data)) + len(r.PartitionKey)
+	}
+	return size
+}
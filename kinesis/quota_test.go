@@ -0,0 +1,74 @@
+package kinesis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecordsSize(t *testing.T) {
+	Convey("Given records", t, func() {
+		records := []Record{{Data: "aGVsbG8=", PartitionKey: "key"}, {Data: "", PartitionKey: "k"}}
+
+		Convey("Their size is their data and partition keys", func() {
+			So(recordsSize(records), ShouldEqual, 6+3+1)
+		})
+	})
+}
+
+func TestStreamRecordsQuotaLimits(t *testing.T) {
+	Convey("Given a service with quota limits, and a shard that has six records", t, func() {
+		clock := gawstest.NewClock(time.Now())
+		lock := sync.Mutex{}
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			calls++
+			n := calls
+			lock.Unlock()
+
+			if n > 6 {
+				w.WriteHeader(400)
+				w.Write([]byte(`{"__type":"ExpiredIteratorException","message":"Iterator expired"}`))
+				return
+			}
+			w.Write([]byte(`{"NextShardIterator":"next","Records":[{"Data":"aGVsbG8=","PartitionKey":"key","SequenceNumber":"1"}]}`))
+		}))
+		defer ts.Close()
+
+		ks := New(gaws.WithEndpoint(ts.URL), gaws.WithQuotaLimits(), gaws.WithClock(clock))
+		c, errc := ks.StreamRecords("foo")
+		for i := 0; i < 6; i++ {
+			<-c
+		}
+		err := <-errc
+
+		Convey("It reads no more than five times a second", func() {
+			So(err, ShouldNotBeNil)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{200 * time.Millisecond, 200 * time.Millisecond})
+		})
+	})
+}
+
+func TestShardLimiters(t *testing.T) {
+	Convey("Given the write limiters of a stream of two shards", t, func() {
+		clock := gawstest.NewClock(time.Now())
+		records, bytes := New(gaws.WithClock(clock)).NewShardWriteLimiters(2)
+
+		Convey("They take the quotas of both shards a second", func() {
+			So(records.Wait(context.Background(), 2000), ShouldBeNil)
+			So(bytes.Wait(context.Background(), 2<<20), ShouldBeNil)
+			So(clock.Sleeps(), ShouldBeEmpty)
+
+			records.Wait(context.Background(), 1000)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{500 * time.Millisecond})
+		})
+	})
+}
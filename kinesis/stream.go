@@ -1,37 +1,89 @@
 package kinesis
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"sync"
+
+	"github.com/controlgroup/gaws"
 )
 
+// putRecordBuffers holds the buffers PutRecord encodes bodies into, so that a producer putting many records does not make garbage for
+// each of them. They hold *[]byte, so that putting one back does not allocate.
+var putRecordBuffers = sync.Pool{New: func() interface{} { return new([]byte) }}
+
+// appendJSONString appends s to dst as a JSON string. Stream names and partition keys are almost always plain ASCII, which is appended
+// as it is, and anything else is escaped by encoding/json.
+func appendJSONString(dst []byte, s string) ([]byte, error) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x7f || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			escaped, err := json.Marshal(s)
+			return append(dst, escaped...), err
+		}
+	}
+	dst = append(dst, '"')
+	dst = append(dst, s...)
+	return append(dst, '"'), nil
+}
+
+// encodePutRecord appends the body of a PutRecord call, which is a putRecordRequest as JSON, to dst and returns it. Records can be up to
+// 1 MB, so the data is base64 encoded straight into the body, instead of into a string that is then copied into it.
+// If dst is large enough, nothing is allocated.
+func encodePutRecord(dst []byte, streamName string, partitionKey string, data []byte) ([]byte, error) {
+	dst = append(dst, `{"StreamName":`...)
+	dst, err := appendJSONString(dst, streamName)
+	if err != nil {
+		return dst, err
+	}
+	dst = append(dst, `,"Data":"`...)
+
+	n := len(dst)
+	size := base64.StdEncoding.EncodedLen(len(data))
+	if cap(dst)-n < size {
+		grown := make([]byte, n, n+size+len(`","PartitionKey":""}`)+len(partitionKey))
+		copy(grown, dst)
+		dst = grown
+	}
+	dst = dst[:n+size]
+	base64.StdEncoding.Encode(dst[n:], data)
+
+	dst = append(dst, `","PartitionKey":`...)
+	dst, err = appendJSONString(dst, partitionKey)
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, '}'), nil
+}
+
 // PutRecord puts data on a Kinesis stream. It returns an error if it fails.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_PutRecord.html for more details.
 func (s *Stream) PutRecord(partitionKey string, data []byte) error {
-
-	encodedData := base64.StdEncoding.EncodeToString(data)
-
-	body := putRecordRequest{StreamName: s.Name, Data: encodedData, PartitionKey: partitionKey}
-	bodyAsJson, err := json.Marshal(body)
+	buffer := putRecordBuffers.Get().(*[]byte)
+	body, err := encodePutRecord((*buffer)[:0], s.Name, partitionKey, data)
+	if err != nil {
+		return err
+	}
 
 	req := s.Service.request()
-	req.Body = bodyAsJson
+	req.Body = body
 	req.Headers["X-Amz-Target"] = "Kinesis_20131202.PutRecord"
 
 	_, err = req.Do()
-
+	if err == nil {
+		// The body has been sent, so the buffer can be used again. If the request failed, it might still be being sent, so it is left
+		// to the garbage collector.
+		*buffer = body
+		putRecordBuffers.Put(buffer)
+	}
 	return err
 }
 
 // Delete deletes a stream. It is calling the DeleteStream API call.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_DeleteStream.html for more details.
 func (s *Stream) Delete() error {
-	req := s.Service.request()
-
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.DeleteStream"
-
-	_, err := req.Do()
-
+	_, err := gaws.Call[struct{}](context.Background(), s.Service.request(), "Kinesis_20131202.DeleteStream", nil)
 	return err
 }
 
@@ -57,30 +109,16 @@ type streamDescriptionRequest struct {
 // Describe describes a stream. It is calling the DescribeStream API call.
 // See http://docs.aws.amazon.com/kinesis/latest/APIReference/API_DescribeStream.html for more details.
 func (s *Stream) Describe() (StreamDescription, error) {
-	result := streamDescriptionResult{}
-
 	body := streamDescriptionRequest{StreamName: s.Name}
-	bodyAsJson, err := json.Marshal(body)
-
-	req := s.Service.request()
-	req.Body = bodyAsJson
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.DescribeStream"
-
-	resp, err := req.Do()
-	if err != nil {
-		return StreamDescription{}, err
-	}
-
-	err = json.Unmarshal(resp, &result)
+	result, err := gaws.Call[streamDescriptionResult](context.Background(), s.Service.request(), "Kinesis_20131202.DescribeStream", body)
 	if err != nil {
 		return StreamDescription{}, err
 	}
 
-	for i, _ := range result.StreamDescription.Shards {
+	for i := range result.StreamDescription.Shards {
 		result.StreamDescription.Shards[i].stream = s
-
 	}
-	return result.StreamDescription, err
+	return result.StreamDescription, nil
 }
 
 type mergeShardsRequest struct {
@@ -94,14 +132,7 @@ type mergeShardsRequest struct {
 func (s *Stream) MergeShards(shardToMerge string, adjacentShardToMerge string) error {
 
 	body := mergeShardsRequest{StreamName: s.Name, ShardToMerge: shardToMerge, AdjacentShardToMerge: adjacentShardToMerge}
-	bodyAsJson, err := json.Marshal(body)
-
-	req := s.Service.request()
-	req.Body = bodyAsJson
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.MergeShards"
-
-	_, err = req.Do()
-
+	_, err := gaws.Call[struct{}](context.Background(), s.Service.request(), "Kinesis_20131202.MergeShards", body)
 	return err
 }
 
@@ -116,12 +147,6 @@ type splitShardRequest struct {
 func (s *Stream) SplitShard(shardToSplit string, newStartingHashKey string) error {
 
 	body := splitShardRequest{StreamName: s.Name, ShardToSplit: shardToSplit, NewStartingHashKey: newStartingHashKey}
-	bodyAsJson, err := json.Marshal(body)
-
-	req := s.Service.request()
-	req.Body = bodyAsJson
-	req.Headers["X-Amz-Target"] = "Kinesis_20131202.SplitShard"
-
-	_, err = req.Do()
+	_, err := gaws.Call[struct{}](context.Background(), s.Service.request(), "Kinesis_20131202.SplitShard", body)
 	return err
 }
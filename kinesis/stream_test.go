@@ -1,6 +1,8 @@
 package kinesis
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -196,3 +198,78 @@ func TestSplitShard(t *testing.T) {
 		})
 	})
 }
+
+// testMarshalPutRecord returns the body of a PutRecord call the simple way, by marshaling the data's base64 string.
+func testMarshalPutRecord(streamName string, partitionKey string, data []byte) ([]byte, error) {
+	return json.Marshal(putRecordRequest{StreamName: streamName, Data: base64.StdEncoding.EncodeToString(data), PartitionKey: partitionKey})
+}
+
+func TestEncodePutRecord(t *testing.T) {
+	Convey("Given records of every length of final base64 block, and names that need escaping", t, func() {
+		records := [][]byte{{}, []byte("a"), []byte("ab"), []byte("abc"), bytes.Repeat([]byte{0xff, 0x00, 0x7f}, 1<<18)}
+
+		Convey("They are encoded as if marshaled", func() {
+			for _, data := range records {
+				body, err := encodePutRecord(nil, `logs "prod"`, "<user&1>", data)
+				So(err, ShouldBeNil)
+
+				expected, _ := testMarshalPutRecord(`logs "prod"`, "<user&1>", data)
+				So(string(body), ShouldEqual, string(expected))
+			}
+		})
+		Convey("Plain names are encoded as if marshaled", func() {
+			body, err := encodePutRecord(nil, "logs", "user-1", records[3])
+			So(err, ShouldBeNil)
+
+			expected, _ := testMarshalPutRecord("logs", "user-1", records[3])
+			So(string(body), ShouldEqual, string(expected))
+		})
+		Convey("The body is allocated at its full size", func() {
+			body, _ := encodePutRecord(nil, "logs", "key", records[4])
+			So(cap(body), ShouldEqual, len(body))
+		})
+		Convey("The body is appended to what is given", func() {
+			body, _ := encodePutRecord([]byte("prefix"), "logs", "key", records[1])
+			So(string(body), ShouldStartWith, `prefix{"StreamName":"logs"`)
+		})
+		Convey("A buffer that is large enough is used", func() {
+			buffer := make([]byte, 0, 1<<20)
+			body, _ := encodePutRecord(buffer, "logs", "key", records[4])
+			So(&body[0], ShouldEqual, &buffer[:1][0])
+		})
+	})
+}
+
+// testLargeRecord is near the 1 MB limit on a record.
+var testLargeRecord = bytes.Repeat([]byte("0123456789abcdef"), 63*1024)
+
+func BenchmarkEncodePutRecord(b *testing.B) {
+	b.ReportAllocs()
+	var buffer []byte
+	for i := 0; i < b.N; i++ {
+		buffer, _ = encodePutRecord(buffer[:0], "logs", "key", testLargeRecord)
+	}
+}
+
+// BenchmarkMarshalPutRecord is how PutRecord used to encode records, to compare with BenchmarkEncodePutRecord.
+func BenchmarkMarshalPutRecord(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		testMarshalPutRecord("logs", "key", testLargeRecord)
+	}
+}
+
+// BenchmarkPutRecord is the whole of putting a small record, as a busy producer does.
+func BenchmarkPutRecord(b *testing.B) {
+	ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+	defer ts.Close()
+
+	stream := Stream{Name: "logs", Service: &KinesisService{Endpoint: ts.URL}}
+	data := make([]byte, 200)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.PutRecord("key", data)
+	}
+}
@@ -0,0 +1,153 @@
+package kms
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The key specs GenerateDataKey can generate.
+const (
+	AES128 = "AES_128"
+	AES256 = "AES_256"
+)
+
+// EncryptionContext is non-secret data that is bound to a ciphertext. The same context must be given to decrypt it,
+// so it can say what the ciphertext is for, like {"stream": "payments"}. It is logged by CloudTrail.
+type EncryptionContext map[string]string
+
+type encryptRequest struct {
+	KeyId             string
+	Plaintext         []byte
+	EncryptionContext EncryptionContext `json:",omitempty"`
+}
+
+type encryptResult struct {
+	CiphertextBlob []byte
+	KeyId          string
+}
+
+// Encrypt encrypts up to 4 KB of plaintext with the key. Use GenerateDataKey to encrypt more. It is calling the Encrypt API call.
+// See http://docs.aws.amazon.com/kms/latest/APIReference/API_Encrypt.html for more details.
+func (k *Key) Encrypt(plaintext []byte, context EncryptionContext) ([]byte, error) {
+	result := encryptResult{}
+
+	bodyAsJson, err := json.Marshal(encryptRequest{KeyId: k.Id, Plaintext: plaintext, EncryptionContext: context})
+	if err != nil {
+		return result.CiphertextBlob, err
+	}
+
+	req := k.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "TrentService.Encrypt"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.CiphertextBlob, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.CiphertextBlob, err
+}
+
+type decryptRequest struct {
+	CiphertextBlob    []byte
+	EncryptionContext EncryptionContext `json:",omitempty"`
+}
+
+type decryptResult struct {
+	KeyId     string
+	Plaintext []byte
+}
+
+// Decrypt decrypts a ciphertext from Encrypt or GenerateDataKey, with the context it was encrypted with. The ciphertext says which key
+// encrypted it, so no key is needed. It returns the plaintext and the ARN of the key. It is calling the Decrypt API call.
+// See http://docs.aws.amazon.com/kms/latest/APIReference/API_Decrypt.html for more details.
+func (s *KMSService) Decrypt(ciphertext []byte, context EncryptionContext) ([]byte, string, error) {
+	result := decryptResult{}
+
+	bodyAsJson, err := json.Marshal(decryptRequest{CiphertextBlob: ciphertext, EncryptionContext: context})
+	if err != nil {
+		return result.Plaintext, "", err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "TrentService.Decrypt"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Plaintext, "", err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Plaintext, result.KeyId, err
+}
+
+// DataKey is a key for encrypting data locally. Use Plaintext to encrypt, then forget it and keep CiphertextBlob with the data.
+// Decrypt CiphertextBlob to get Plaintext back.
+type DataKey struct {
+	KeyId          string // The ARN of the master key that encrypted the data key
+	Plaintext      []byte
+	CiphertextBlob []byte
+}
+
+type generateDataKeyRequest struct {
+	KeyId             string
+	KeySpec           string
+	EncryptionContext EncryptionContext `json:",omitempty"`
+}
+
+// GenerateDataKey generates a data key of the spec, AES128 or AES256, encrypted with the key. It is calling the GenerateDataKey API call.
+// See http://docs.aws.amazon.com/kms/latest/APIReference/API_GenerateDataKey.html for more details.
+func (k *Key) GenerateDataKey(keySpec string, context EncryptionContext) (DataKey, error) {
+	result := DataKey{}
+
+	bodyAsJson, err := json.Marshal(generateDataKeyRequest{KeyId: k.Id, KeySpec: keySpec, EncryptionContext: context})
+	if err != nil {
+		return result, err
+	}
+
+	req := k.Service.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "TrentService.GenerateDataKey"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+type generateRandomRequest struct {
+	NumberOfBytes int
+}
+
+type generateRandomResult struct {
+	Plaintext []byte
+}
+
+// GenerateRandom returns n random bytes, up to 1024. It is calling the GenerateRandom API call.
+// See http://docs.aws.amazon.com/kms/latest/APIReference/API_GenerateRandom.html for more details.
+func (s *KMSService) GenerateRandom(n int) ([]byte, error) {
+	result := generateRandomResult{}
+
+	bodyAsJson, err := json.Marshal(generateRandomRequest{NumberOfBytes: n})
+	if err != nil {
+		return result.Plaintext, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "TrentService.GenerateRandom"
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Plaintext, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.Plaintext, err
+}
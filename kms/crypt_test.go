@@ -0,0 +1,123 @@
+package kms
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEncrypt(t *testing.T) {
+	Convey("Given a key and a context", t, func() {
+		ts, s, requests := testService(testKMS)
+		defer ts.Close()
+		k := Key{Id: "alias/payments", Service: s}
+		context := EncryptionContext{"stream": "payments"}
+
+		ciphertext, err := k.Encrypt([]byte("secret"), context)
+
+		Convey("It returns the ciphertext", func() {
+			So(err, ShouldBeNil)
+			So(len(ciphertext), ShouldBeGreaterThan, 0)
+		})
+		Convey("It sends the plaintext in base64", func() {
+			So(requests()[0].Target, ShouldEqual, "TrentService.Encrypt")
+			So(string(requests()[0].Body), ShouldEqual, `{"KeyId":"alias/payments","Plaintext":"c2VjcmV0","EncryptionContext":{"stream":"payments"}}`)
+		})
+
+		Convey("When the ciphertext is decrypted with the same context", func() {
+			plaintext, keyId, err := s.Decrypt(ciphertext, context)
+
+			Convey("It returns the plaintext and the key", func() {
+				So(err, ShouldBeNil)
+				So(string(plaintext), ShouldEqual, "secret")
+				So(keyId, ShouldEqual, "arn:aws:kms:us-east-1:123456789012:key/alias/payments")
+			})
+		})
+		Convey("When the ciphertext is decrypted with another context", func() {
+			_, _, err := s.Decrypt(ciphertext, EncryptionContext{"stream": "orders"})
+
+			Convey("It returns an error", func() {
+				So(err.(kmsError).is("InvalidCiphertextException"), ShouldBeTrue)
+			})
+		})
+	})
+	Convey("Given no context", t, func() {
+		ts, s, requests := testService(testKMS)
+		defer ts.Close()
+
+		(&Key{Id: "alias/payments", Service: s}).Encrypt([]byte("secret"), nil)
+
+		Convey("It is not sent", func() {
+			So(string(requests()[0].Body), ShouldEqual, `{"KeyId":"alias/payments","Plaintext":"c2VjcmV0"}`)
+		})
+	})
+	Convey("Given a key that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := (&Key{Id: "alias/foo", Service: s}).Encrypt([]byte("secret"), nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, _, err := s.Decrypt([]byte("ciphertext"), nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGenerateDataKey(t *testing.T) {
+	Convey("Given a key", t, func() {
+		ts, s, requests := testService(testKMS)
+		defer ts.Close()
+		k := Key{Id: "alias/payments", Service: s}
+
+		dataKey, err := k.GenerateDataKey(AES256, EncryptionContext{"stream": "payments"})
+
+		Convey("It returns a data key and its ciphertext", func() {
+			So(err, ShouldBeNil)
+			So(len(dataKey.Plaintext), ShouldEqual, 32)
+			So(dataKey.KeyId, ShouldEqual, "arn:aws:kms:us-east-1:123456789012:key/alias/payments")
+			So(string(requests()[0].Body), ShouldEqual, `{"KeyId":"alias/payments","KeySpec":"AES_256","EncryptionContext":{"stream":"payments"}}`)
+		})
+		Convey("Its ciphertext decrypts to the data key", func() {
+			plaintext, _, err := s.Decrypt(dataKey.CiphertextBlob, EncryptionContext{"stream": "payments"})
+
+			So(err, ShouldBeNil)
+			So(plaintext, ShouldResemble, dataKey.Plaintext)
+		})
+	})
+	Convey("Given a key that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := (&Key{Id: "alias/foo", Service: s}).GenerateDataKey(AES128, nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestGenerateRandom(t *testing.T) {
+	Convey("Given a number of bytes", t, func() {
+		ts, s, requests := testService(testKMS)
+		defer ts.Close()
+
+		random, err := s.GenerateRandom(16)
+
+		Convey("It returns that many random bytes", func() {
+			So(err, ShouldBeNil)
+			So(len(random), ShouldEqual, 16)
+			So(requests()[0].Target, ShouldEqual, "TrentService.GenerateRandom")
+			So(string(requests()[0].Body), ShouldEqual, `{"NumberOfBytes":16}`)
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package kms
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+)
+
+// envelopeVersion is the first byte of a sealed payload, so the format can change later.
+const envelopeVersion = 1
+
+var badEnvelopeError = kmsError{Type: "GawsBadEnvelope", Message: "The payload was not sealed by Seal, or it has been changed."}
+
+// Seal encrypts a payload of any size with envelope encryption. It generates an AES-256 data key with the key, encrypts the payload with it
+// using AES-GCM, and returns the encrypted data key and the ciphertext together, so only Open and KMS are needed to decrypt it.
+// The context must be given to Open too. Every Seal generates a new data key, which is one KMS request.
+func (k *Key) Seal(plaintext []byte, context EncryptionContext) ([]byte, error) {
+	dataKey, err := k.GenerateDataKey(AES256, context)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(dataKey.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// The version, the length of the encrypted data key, the encrypted data key, the nonce, and the ciphertext
+	sealed := make([]byte, 3, 3+len(dataKey.CiphertextBlob)+len(nonce)+len(plaintext)+gcm.Overhead())
+	sealed[0] = envelopeVersion
+	binary.BigEndian.PutUint16(sealed[1:3], uint16(len(dataKey.CiphertextBlob)))
+	sealed = append(sealed, dataKey.CiphertextBlob...)
+	sealed = append(sealed, nonce...)
+
+	// The header is authenticated too. It is copied, since the data it authenticates may not overlap where the ciphertext goes.
+	header := append([]byte(nil), sealed[:3]...)
+	return gcm.Seal(sealed, nonce, plaintext, header), nil
+}
+
+// Open decrypts a payload from Seal. It decrypts the data key with KMS, then decrypts the payload with it. The context must be the one it was sealed with.
+func (s *KMSService) Open(sealed []byte, context EncryptionContext) ([]byte, error) {
+	if len(sealed) < 3 || sealed[0] != envelopeVersion {
+		return nil, badEnvelopeError
+	}
+	keyLength := int(binary.BigEndian.Uint16(sealed[1:3]))
+	if len(sealed) < 3+keyLength {
+		return nil, badEnvelopeError
+	}
+	wrappedKey := sealed[3 : 3+keyLength]
+
+	key, _, err := s.Decrypt(wrappedKey, context)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := sealed[3+keyLength:]
+	if len(rest) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, badEnvelopeError
+	}
+
+	plaintext, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], sealed[:3])
+	if err != nil {
+		return nil, badEnvelopeError
+	}
+	return plaintext, nil
+}
+
+// newGCM returns AES-GCM with the key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
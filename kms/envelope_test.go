@@ -0,0 +1,89 @@
+package kms
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSeal(t *testing.T) {
+	Convey("Given a payload larger than KMS can encrypt", t, func() {
+		ts, s, requests := testService(testKMS)
+		defer ts.Close()
+		k := Key{Id: "alias/payments", Service: s}
+		context := EncryptionContext{"stream": "payments"}
+		payload := bytes.Repeat([]byte("a payment "), 1000)
+
+		sealed, err := k.Seal(payload, context)
+
+		Convey("It is sealed with a data key", func() {
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 1)
+			So(requests()[0].Target, ShouldEqual, "TrentService.GenerateDataKey")
+			So(bytes.Contains(sealed, payload[:10]), ShouldBeFalse)
+		})
+
+		Convey("When it is opened with the same context", func() {
+			opened, err := s.Open(sealed, context)
+
+			Convey("It is the payload", func() {
+				So(err, ShouldBeNil)
+				So(opened, ShouldResemble, payload)
+				So(requests()[1].Target, ShouldEqual, "TrentService.Decrypt")
+			})
+		})
+		Convey("When it is opened with another context", func() {
+			_, err := s.Open(sealed, EncryptionContext{"stream": "orders"})
+
+			Convey("It returns an error", func() {
+				So(err.(kmsError).is("InvalidCiphertextException"), ShouldBeTrue)
+			})
+		})
+		Convey("When it has been changed", func() {
+			sealed[len(sealed)-1] ^= 1
+			_, err := s.Open(sealed, context)
+
+			Convey("It returns an error", func() {
+				So(err, ShouldResemble, badEnvelopeError)
+			})
+		})
+		Convey("When it has been cut short", func() {
+			_, err := s.Open(sealed[:10], context)
+
+			Convey("It returns an error without asking KMS", func() {
+				So(err, ShouldResemble, badEnvelopeError)
+				So(len(requests()), ShouldEqual, 1)
+			})
+		})
+	})
+	Convey("Given the same payload sealed twice", t, func() {
+		ts, s, _ := testService(testKMS)
+		defer ts.Close()
+		k := Key{Id: "alias/payments", Service: s}
+
+		first, _ := k.Seal([]byte("secret"), nil)
+		second, _ := k.Seal([]byte("secret"), nil)
+
+		Convey("The sealed payloads are different", func() {
+			So(first, ShouldNotResemble, second)
+		})
+	})
+	Convey("Given a payload that was not sealed", t, func() {
+		_, err := (&KMSService{}).Open([]byte("not sealed"), nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, badEnvelopeError)
+		})
+	})
+	Convey("Given a key that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := (&Key{Id: "alias/foo", Service: s}).Seal([]byte("secret"), nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
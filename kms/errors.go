@@ -0,0 +1,23 @@
+// Code generated by errgen from the go:generate line in kms.go. DO NOT EDIT.
+
+package kms
+
+import "strings"
+
+// These are errors KMS returns. errors.Is(err, kms.ErrNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrNotFound          = kmsError{Type: "NotFoundException"}
+	ErrDisabled          = kmsError{Type: "DisabledException"}
+	ErrKMSInvalidState   = kmsError{Type: "KMSInvalidStateException"}
+	ErrInvalidCiphertext = kmsError{Type: "InvalidCiphertextException"}
+	ErrIncorrectKey      = kmsError{Type: "IncorrectKeyException"}
+	ErrInvalidKeyUsage   = kmsError{Type: "InvalidKeyUsageException"}
+	ErrKeyUnavailable    = kmsError{Type: "KeyUnavailableException"}
+	ErrLimitExceeded     = kmsError{Type: "LimitExceededException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e kmsError) Is(target error) bool {
+	t, ok := target.(kmsError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
@@ -0,0 +1,94 @@
+// Package kms provides a way to interact with the AWS Key Management Service.
+package kms
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type kmsError -field Type -service KMS NotFoundException DisabledException KMSInvalidStateException InvalidCiphertextException IncorrectKeyException InvalidKeyUsageException KeyUnavailableException LimitExceededException
+
+// kmsError is the error document returned from the KMS service.
+type kmsError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the kmsError into an error message.
+func (e kmsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e kmsError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func kmsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := kmsError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") || error.is("KMSInternalException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// KMSService is the KMS service at AWS. The endpoint for a region is gaws.Endpoint("kms", region).
+type KMSService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// KMSAPI is implemented by *KMSService. Code that takes a KMSAPI can be given a fake in tests instead.
+type KMSAPI interface {
+	Decrypt(ciphertext []byte, context EncryptionContext) ([]byte, string, error)
+	GenerateRandom(n int) ([]byte, error)
+	Open(sealed []byte, context EncryptionContext) ([]byte, error)
+}
+
+var _ KMSAPI = (*KMSService)(nil)
+
+// New returns a KMSService with the options applied. Its endpoint is gaws.Endpoint("kms", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *KMSService {
+	config := gaws.NewConfig(options...)
+	return &KMSService{Endpoint: config.ServiceEndpoint("kms"), config: config}
+}
+
+func (s *KMSService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: kmsRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("kms", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Key is a KMS customer master key.
+type Key struct {
+	Id      string      // The ID, ARN, or alias of the key, like alias/payments
+	Service *KMSService // The service for this region
+}
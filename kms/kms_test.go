@@ -0,0 +1,157 @@
+package kms
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var notFoundError = kmsError{Type: "NotFoundException", Message: "Alias arn:aws:kms:us-east-1:123456789012:alias/foo is not found."}
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	b, _ := json.Marshal(notFoundError)
+
+	w.WriteHeader(400)
+	w.Write(b)
+}
+
+// testCiphertext is what the test KMS "encrypts" plaintexts into. It is not encrypted at all.
+type testCiphertext struct {
+	KeyId     string
+	Context   EncryptionContext
+	Plaintext []byte
+}
+
+// testKMS is a KMS that can encrypt and decrypt, as long as the context matches.
+func testKMS(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		KeyId             string
+		Plaintext         []byte
+		CiphertextBlob    []byte
+		EncryptionContext EncryptionContext
+		KeySpec           string
+		NumberOfBytes     int
+	}{}
+	json.NewDecoder(r.Body).Decode(&request)
+	arn := "arn:aws:kms:us-east-1:123456789012:key/" + request.KeyId
+
+	var result interface{}
+	switch r.Header.Get("X-Amz-Target") {
+	case "TrentService.Encrypt":
+		blob, _ := json.Marshal(testCiphertext{KeyId: arn, Context: request.EncryptionContext, Plaintext: request.Plaintext})
+		result = encryptResult{CiphertextBlob: blob, KeyId: arn}
+	case "TrentService.GenerateDataKey":
+		plaintext := bytes.Repeat([]byte{7}, 32)
+		if request.KeySpec == AES128 {
+			plaintext = plaintext[:16]
+		}
+		blob, _ := json.Marshal(testCiphertext{KeyId: arn, Context: request.EncryptionContext, Plaintext: plaintext})
+		result = DataKey{KeyId: arn, Plaintext: plaintext, CiphertextBlob: blob}
+	case "TrentService.Decrypt":
+		ciphertext := testCiphertext{}
+		err := json.Unmarshal(request.CiphertextBlob, &ciphertext)
+		if err != nil || !reflect.DeepEqual(ciphertext.Context, request.EncryptionContext) {
+			w.WriteHeader(400)
+			w.Write([]byte(`{"__type":"InvalidCiphertextException"}`))
+			return
+		}
+		result = decryptResult{KeyId: ciphertext.KeyId, Plaintext: ciphertext.Plaintext}
+	case "TrentService.GenerateRandom":
+		result = generateRandomResult{Plaintext: bytes.Repeat([]byte{4}, request.NumberOfBytes)}
+	}
+
+	b, _ := json.Marshal(result)
+	w.Write(b)
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Target string // The X-Amz-Target header
+	Body   []byte
+}
+
+// testService returns a service on a server that runs handler, and a function that returns the requests it has gotten so far.
+func testService(handler http.HandlerFunc) (*httptest.Server, *KMSService, func() []testRequest) {
+	var lock sync.Mutex
+	requests := []testRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		lock.Lock()
+		requests = append(requests, testRequest{Target: r.Header.Get("X-Amz-Target"), Body: body})
+		lock.Unlock()
+		handler(w, r)
+	}))
+
+	return ts, &KMSService{Endpoint: ts.URL}, func() []testRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]testRequest{}, requests...)
+	}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := kmsRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a throttling error", t, func() {
+		result, _ := kmsRetryPredicate(400, []byte(`{"__type":"ThrottlingException","message":"Rate exceeded"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a ciphertext that cannot be decrypted", t, func() {
+		result, err := kmsRetryPredicate(400, []byte(`{"__type":"InvalidCiphertextException"}`))
+
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err.(kmsError).is("InvalidCiphertextException"), ShouldBeTrue)
+		})
+	})
+	Convey("Given a server error", t, func() {
+		result, _ := kmsRetryPredicate(500, []byte(`{"__type":"KMSInternalException"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("kms", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
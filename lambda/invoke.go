@@ -0,0 +1,129 @@
+package lambda
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+)
+
+// The ways a function can be invoked.
+const (
+	RequestResponse = "RequestResponse" // Wait for the function and return its result
+	Event           = "Event"           // Queue the event and return without waiting
+	DryRun          = "DryRun"          // Check that the caller may invoke the function, without running it
+)
+
+// InvokeOptions are the optional settings of Invoke.
+type InvokeOptions struct {
+	InvocationType string // RequestResponse, Event, or DryRun. If it is empty, RequestResponse is used.
+	Qualifier      string // The version or alias to invoke. If it is empty, $LATEST is invoked.
+	LogTail        bool   // Return the last 4 KB of the function's log. Only RequestResponse invocations have logs.
+}
+
+// InvokeResult is what invoking a function returned.
+type InvokeResult struct {
+	StatusCode      int    // 200 for RequestResponse, 202 for Event, and 204 for DryRun
+	Payload         []byte // What the function returned, or its error if FunctionError is set
+	FunctionError   string // Handled or Unhandled if the function failed
+	LogResult       string // The end of the function's log, if LogTail was set
+	ExecutedVersion string // The version of the function that ran
+}
+
+// FunctionError is the error a function returned when it failed.
+type FunctionError struct {
+	Type         string   `json:"-"` // Handled or Unhandled
+	ErrorType    string   `json:"errorType"`
+	ErrorMessage string   `json:"errorMessage"`
+	StackTrace   []string `json:"stackTrace"`
+}
+
+// Error formats the FunctionError into an error message.
+func (e *FunctionError) Error() string {
+	return fmt.Sprintf("%v: %v", e.ErrorType, e.ErrorMessage)
+}
+
+// Err returns the function's error, if it failed. Its payload is decoded into a FunctionError if it can be, otherwise the payload is the message.
+func (r InvokeResult) Err() error {
+	if r.FunctionError == "" {
+		return nil
+	}
+
+	e := &FunctionError{Type: r.FunctionError}
+	if json.Unmarshal(r.Payload, e) != nil {
+		e.ErrorMessage = string(r.Payload)
+	}
+	return e
+}
+
+// Decode decodes the payload from JSON into v.
+func (r InvokeResult) Decode(v interface{}) error {
+	return json.Unmarshal(r.Payload, v)
+}
+
+// Invoke runs the function with payload as its event. A function that failed does not make Invoke return an error,
+// so check the result's FunctionError or Err. It is calling the Invoke API call.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_Invoke.html for more details.
+func (f *Function) Invoke(payload []byte, options InvokeOptions) (InvokeResult, error) {
+	query := url.Values{}
+	if options.Qualifier != "" {
+		query.Set("Qualifier", options.Qualifier)
+	}
+
+	req := f.Service.request("POST", f.path()+"/invocations", query)
+	req.Body = payload
+	if options.InvocationType != "" {
+		req.Headers["X-Amz-Invocation-Type"] = options.InvocationType
+	}
+	if options.LogTail {
+		req.Headers["X-Amz-Log-Type"] = "Tail"
+	}
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return InvokeResult{}, err
+	}
+	defer resp.Body.Close()
+
+	result := InvokeResult{
+		StatusCode:      resp.StatusCode,
+		FunctionError:   resp.Header.Get("X-Amz-Function-Error"),
+		ExecutedVersion: resp.Header.Get("X-Amz-Executed-Version"),
+	}
+
+	if tail := resp.Header.Get("X-Amz-Log-Result"); tail != "" {
+		log, err := base64.StdEncoding.DecodeString(tail)
+		if err != nil {
+			return result, err
+		}
+		result.LogResult = string(log)
+	}
+
+	result.Payload, err = ioutil.ReadAll(resp.Body)
+	return result, err
+}
+
+// InvokeJSON runs the function with input encoded as JSON, waits for it, and decodes what it returned into output, unless output is nil.
+// If the function failed, its FunctionError is returned.
+func (f *Function) InvokeJSON(input interface{}, output interface{}, options InvokeOptions) (InvokeResult, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return InvokeResult{}, err
+	}
+
+	options.InvocationType = RequestResponse
+	result, err := f.Invoke(payload, options)
+	if err != nil {
+		return result, err
+	}
+
+	if err := result.Err(); err != nil {
+		return result, err
+	}
+
+	if output != nil && len(result.Payload) > 0 {
+		err = result.Decode(output)
+	}
+	return result, err
+}
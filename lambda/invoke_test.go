@@ -0,0 +1,125 @@
+package lambda
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testInvokeSuccess(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amz-Executed-Version", "7")
+	if r.Header.Get("X-Amz-Log-Type") == "Tail" {
+		// START RequestId: 1 Version: 7\nEND RequestId: 1
+		w.Header().Set("X-Amz-Log-Result", "U1RBUlQgUmVxdWVzdElkOiAxIFZlcnNpb246IDcKRU5EIFJlcXVlc3RJZDogMQ==")
+	}
+	w.Write([]byte(`{"greeting":"hello world"}`))
+}
+
+func testInvokeEvent(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(202)
+}
+
+func testInvokeFunctionError(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amz-Function-Error", "Unhandled")
+	w.Write([]byte(`{"errorMessage":"name is required","errorType":"ValueError","stackTrace":["handler.py:3"]}`))
+}
+
+func TestInvoke(t *testing.T) {
+	Convey("Given a function", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testInvokeSuccess, &requests)
+		defer ts.Close()
+		f := Function{Name: "hello", Service: s}
+
+		Convey("When it is invoked with a qualifier and a log tail", func() {
+			result, err := f.Invoke([]byte(`{"name":"world"}`), InvokeOptions{Qualifier: "live", LogTail: true})
+
+			Convey("It returns the result", func() {
+				So(err, ShouldBeNil)
+				So(result.StatusCode, ShouldEqual, 200)
+				So(string(result.Payload), ShouldEqual, `{"greeting":"hello world"}`)
+				So(result.ExecutedVersion, ShouldEqual, "7")
+				So(result.LogResult, ShouldEqual, "START RequestId: 1 Version: 7\nEND RequestId: 1")
+				So(result.Err(), ShouldBeNil)
+			})
+			Convey("It sends the request", func() {
+				So(requests[0].Method, ShouldEqual, "POST")
+				So(requests[0].URI, ShouldEqual, "/2015-03-31/functions/hello/invocations?Qualifier=live")
+				So(requests[0].Headers.Get("X-Amz-Log-Type"), ShouldEqual, "Tail")
+				So(string(requests[0].Body), ShouldEqual, `{"name":"world"}`)
+			})
+		})
+
+		Convey("When it is invoked with JSON", func() {
+			output := struct{ Greeting string }{}
+			_, err := f.InvokeJSON(map[string]string{"name": "world"}, &output, InvokeOptions{InvocationType: Event})
+
+			Convey("The result is decoded", func() {
+				So(err, ShouldBeNil)
+				So(output.Greeting, ShouldEqual, "hello world")
+			})
+			Convey("It waits for the result", func() {
+				So(requests[0].Headers.Get("X-Amz-Invocation-Type"), ShouldEqual, RequestResponse)
+				So(string(requests[0].Body), ShouldEqual, `{"name":"world"}`)
+			})
+		})
+	})
+	Convey("Given a function invoked as an event", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testInvokeEvent, &requests)
+		defer ts.Close()
+		f := Function{Name: "arn:aws:lambda:us-east-1:123456789012:function:hello", Service: s}
+
+		result, err := f.Invoke([]byte(`{}`), InvokeOptions{InvocationType: Event})
+
+		Convey("It returns without a payload", func() {
+			So(err, ShouldBeNil)
+			So(result.StatusCode, ShouldEqual, 202)
+			So(len(result.Payload), ShouldEqual, 0)
+			So(requests[0].Headers.Get("X-Amz-Invocation-Type"), ShouldEqual, Event)
+		})
+		Convey("The ARN is escaped in the path", func() {
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/functions/arn:aws:lambda:us-east-1:123456789012:function:hello/invocations")
+		})
+	})
+	Convey("Given a function that fails", t, func() {
+		ts, s := testService(testInvokeFunctionError, nil)
+		defer ts.Close()
+		f := Function{Name: "hello", Service: s}
+
+		Convey("Invoke returns the function error in the result", func() {
+			result, err := f.Invoke([]byte(`{}`), InvokeOptions{})
+
+			So(err, ShouldBeNil)
+			So(result.FunctionError, ShouldEqual, "Unhandled")
+			So(result.Err(), ShouldResemble, &FunctionError{Type: "Unhandled", ErrorType: "ValueError", ErrorMessage: "name is required", StackTrace: []string{"handler.py:3"}})
+		})
+		Convey("InvokeJSON returns it as an error", func() {
+			output := map[string]string{}
+			_, err := f.InvokeJSON(map[string]string{}, &output, InvokeOptions{})
+
+			So(err, ShouldHaveSameTypeAs, &FunctionError{})
+			So(err.Error(), ShouldEqual, "ValueError: name is required")
+			So(len(output), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a function error that is not JSON", t, func() {
+		result := InvokeResult{FunctionError: "Handled", Payload: []byte("Process exited before completing request")}
+
+		Convey("The payload is the message", func() {
+			So(result.Err(), ShouldResemble, &FunctionError{Type: "Handled", ErrorMessage: "Process exited before completing request"})
+		})
+	})
+	Convey("Given a function that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+		f := Function{Name: "foo", Service: s}
+
+		_, err := f.Invoke([]byte(`{}`), InvokeOptions{InvocationType: DryRun})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
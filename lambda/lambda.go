@@ -0,0 +1,101 @@
+// Package lambda provides a way to interact with the AWS Lambda service.
+package lambda
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+// apiVersion is the version of the Lambda API, which starts the path of every request.
+const apiVersion = "/2015-03-31"
+
+// lambdaError is the error document returned from the Lambda service.
+type lambdaError struct {
+	Status  int    `json:"-"`    // The HTTP status of the response, like 404 when a function does not exist
+	Type    string `json:"Type"` // User or Service
+	Message string `json:"message"`
+}
+
+// Error formats the lambdaError into an error message.
+func (e lambdaError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+func lambdaRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := lambdaError{Status: status}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	// TooManyRequestsException
+	if status == 429 {
+		return true, error
+	}
+
+	return false, error
+}
+
+// LambdaService is the Lambda service at AWS. The endpoint for a region is gaws.Endpoint("lambda", region).
+type LambdaService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// LambdaAPI is implemented by *LambdaService. Code that takes a LambdaAPI can be given a fake in tests instead.
+type LambdaAPI interface {
+	CreateEventSourceMapping(definition EventSourceMappingDefinition) (EventSourceMapping, error)
+	DeleteEventSourceMapping(uuid string) (EventSourceMapping, error)
+	GetEventSourceMapping(uuid string) (EventSourceMapping, error)
+	ListEventSourceMappings(eventSourceArn string, functionName string) ([]EventSourceMapping, error)
+	UpdateEventSourceMapping(uuid string, update EventSourceMappingUpdate) (EventSourceMapping, error)
+}
+
+var _ LambdaAPI = (*LambdaService)(nil)
+
+// New returns a LambdaService with the options applied. Its endpoint is gaws.Endpoint("lambda", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *LambdaService {
+	config := gaws.NewConfig(options...)
+	return &LambdaService{Endpoint: config.ServiceEndpoint("lambda"), config: config}
+}
+
+// request returns a request to a path under the API version on the endpoint.
+func (s *LambdaService) request(method string, path string, query url.Values) gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: lambdaRetryPredicate,
+		Method:         method,
+		URL:            s.Endpoint + apiVersion + path,
+		Signer:         gaws.Sign4("lambda", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+		},
+	}
+	r = r.WithQuery(query)
+	s.config.Apply(&r)
+	return r
+}
+
+// Function is a Lambda function.
+type Function struct {
+	Name    string         // The name or ARN of the function
+	Service *LambdaService // The service for this region
+}
+
+// path returns the path of the function.
+func (f *Function) path() string {
+	return "/functions/" + url.PathEscape(f.Name)
+}
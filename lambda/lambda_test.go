@@ -0,0 +1,104 @@
+package lambda
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{}"))
+}
+
+func testBadJson(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("{\"foo\":\"bar\""))
+}
+
+var notFoundError = lambdaError{Status: 404, Type: "User", Message: "Function not found: arn:aws:lambda:us-east-1:123456789012:function:foo"}
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Amzn-ErrorType", "ResourceNotFoundException")
+	w.WriteHeader(404)
+	w.Write([]byte(`{"Type":"User","message":"Function not found: arn:aws:lambda:us-east-1:123456789012:function:foo"}`))
+}
+
+// testRequest is a request a test server got.
+type testRequest struct {
+	Method  string
+	URI     string // The path and query
+	Headers http.Header
+	Body    []byte
+}
+
+// testService returns a service on a server that runs handler and records every request.
+func testService(handler http.HandlerFunc, requests *[]testRequest) (*httptest.Server, *LambdaService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		if requests != nil {
+			*requests = append(*requests, testRequest{Method: r.Method, URI: r.URL.RequestURI(), Headers: r.Header, Body: body})
+		}
+		handler(w, r)
+	}))
+	return ts, &LambdaService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not JSON", t, func() {
+		result, err := lambdaRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given too many requests", t, func() {
+		result, err := lambdaRetryPredicate(429, []byte(`{"Type":"User","message":"Rate Exceeded."}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+			So(err, ShouldResemble, lambdaError{Status: 429, Type: "User", Message: "Rate Exceeded."})
+		})
+	})
+	Convey("Given a function that does not exist", t, func() {
+		result, err := lambdaRetryPredicate(404, []byte(`{"Type":"User","Message":"Function not found"}`))
+
+		Convey("RetryPredicate returns false and the error has the status", func() {
+			So(result, ShouldBeFalse)
+			So(err.(lambdaError).Status, ShouldEqual, 404)
+			So(err.(lambdaError).Message, ShouldEqual, "Function not found")
+		})
+	})
+	Convey("Given a server error", t, func() {
+		result, _ := lambdaRetryPredicate(500, []byte(`{"Type":"Service","message":"try again"}`))
+
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("lambda", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request("GET", "/", nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
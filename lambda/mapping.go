@@ -0,0 +1,175 @@
+package lambda
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The positions in a stream an event source mapping can start reading from.
+const (
+	TrimHorizon = "TRIM_HORIZON" // The oldest record in each shard
+	Latest      = "LATEST"       // Only records added after the mapping is created
+	AtTimestamp = "AT_TIMESTAMP" // The first record at or after StartingPositionTimestamp
+)
+
+// EventSourceMapping is a mapping that makes Lambda read batches of records from a stream, like a Kinesis stream, and invoke a function with them.
+type EventSourceMapping struct {
+	UUID                  string
+	EventSourceArn        string
+	FunctionArn           string
+	BatchSize             int
+	State                 string  // Creating, Enabling, Enabled, Disabling, Disabled, Updating, or Deleting
+	StateTransitionReason string  // Why the state last changed
+	LastModified          float64 // Seconds since the epoch
+	LastProcessingResult  string  // Like OK or PROBLEM: Function call failed
+}
+
+// EventSourceMappingDefinition describes a new event source mapping.
+type EventSourceMappingDefinition struct {
+	EventSourceArn            string     // The ARN of the stream, like the StreamARN of a kinesis.StreamDescription
+	FunctionName              string     // The name or ARN of the function, which may have a version or alias, like hello:live
+	Enabled                   *bool      `json:",omitempty"` // If it is nil, the mapping is enabled
+	BatchSize                 int        `json:",omitempty"` // The most records in a batch. If it is 0, Lambda's default of 100 is used.
+	StartingPosition          string     // TrimHorizon, Latest, or AtTimestamp
+	StartingPositionTimestamp *time.Time `json:"-"` // Only used with AtTimestamp
+}
+
+// createEventSourceMappingRequest is the request to the CreateEventSourceMapping API call.
+type createEventSourceMappingRequest struct {
+	EventSourceMappingDefinition
+	StartingPositionTimestamp float64 `json:",omitempty"` // Seconds since the epoch
+}
+
+// CreateEventSourceMapping creates an event source mapping, which starts invoking the function with batches of records from the stream.
+// It returns the new mapping, which has the UUID to update it with. It is calling the CreateEventSourceMapping API call.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_CreateEventSourceMapping.html for more details.
+func (s *LambdaService) CreateEventSourceMapping(definition EventSourceMappingDefinition) (EventSourceMapping, error) {
+	result := EventSourceMapping{}
+
+	body := createEventSourceMappingRequest{EventSourceMappingDefinition: definition}
+	if definition.StartingPositionTimestamp != nil {
+		body.StartingPositionTimestamp = float64(definition.StartingPositionTimestamp.UnixNano()) / float64(time.Second)
+	}
+	bodyAsJson, err := json.Marshal(body)
+	if err != nil {
+		return result, err
+	}
+
+	req := s.request("POST", "/event-source-mappings/", nil)
+	req.Body = bodyAsJson
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+// GetEventSourceMapping describes the event source mapping with the UUID. It is calling the GetEventSourceMapping API call.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_GetEventSourceMapping.html for more details.
+func (s *LambdaService) GetEventSourceMapping(uuid string) (EventSourceMapping, error) {
+	result := EventSourceMapping{}
+
+	req := s.request("GET", "/event-source-mappings/"+url.PathEscape(uuid), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+type listEventSourceMappingsResult struct {
+	EventSourceMappings []EventSourceMapping
+	NextMarker          string
+}
+
+// ListEventSourceMappings lists the event source mappings of a stream, a function, or both. Either may be empty to list them for every stream
+// or function. It is calling the ListEventSourceMappings API call until there are no more pages.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_ListEventSourceMappings.html for more details.
+func (s *LambdaService) ListEventSourceMappings(eventSourceArn string, functionName string) ([]EventSourceMapping, error) {
+	mappings := []EventSourceMapping{}
+	query := url.Values{}
+	if eventSourceArn != "" {
+		query.Set("EventSourceArn", eventSourceArn)
+	}
+	if functionName != "" {
+		query.Set("FunctionName", functionName)
+	}
+
+	for {
+		result := listEventSourceMappingsResult{}
+
+		req := s.request("GET", "/event-source-mappings/", query)
+
+		resp, err := req.Do()
+		if err != nil {
+			return []EventSourceMapping{}, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return []EventSourceMapping{}, err
+		}
+
+		mappings = append(mappings, result.EventSourceMappings...)
+
+		if result.NextMarker == "" {
+			return mappings, nil
+		}
+		query.Set("Marker", result.NextMarker)
+	}
+}
+
+// EventSourceMappingUpdate is the changes to make to an event source mapping. Fields that are not set are not changed.
+type EventSourceMappingUpdate struct {
+	FunctionName string `json:",omitempty"` // Point the mapping at another function, version, or alias
+	Enabled      *bool  `json:",omitempty"` // Pause or resume the mapping
+	BatchSize    int    `json:",omitempty"`
+}
+
+// UpdateEventSourceMapping changes the event source mapping with the UUID, and returns it. It is calling the UpdateEventSourceMapping API call.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_UpdateEventSourceMapping.html for more details.
+func (s *LambdaService) UpdateEventSourceMapping(uuid string, update EventSourceMappingUpdate) (EventSourceMapping, error) {
+	result := EventSourceMapping{}
+
+	bodyAsJson, err := json.Marshal(update)
+	if err != nil {
+		return result, err
+	}
+
+	req := s.request("PUT", "/event-source-mappings/"+url.PathEscape(uuid), nil)
+	req.Body = bodyAsJson
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
+
+// DeleteEventSourceMapping deletes the event source mapping with the UUID, which stops it invoking its function. It returns the mapping,
+// which is Deleting. It is calling the DeleteEventSourceMapping API call.
+// See http://docs.aws.amazon.com/lambda/latest/dg/API_DeleteEventSourceMapping.html for more details.
+func (s *LambdaService) DeleteEventSourceMapping(uuid string) (EventSourceMapping, error) {
+	result := EventSourceMapping{}
+
+	req := s.request("DELETE", "/event-source-mappings/"+url.PathEscape(uuid), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result, err
+}
@@ -0,0 +1,179 @@
+package lambda
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testStreamArn = "arn:aws:kinesis:us-east-1:123456789012:stream/foo"
+
+func testMapping(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`{"UUID":"a1b2","EventSourceArn":"arn:aws:kinesis:us-east-1:123456789012:stream/foo","FunctionArn":"arn:aws:lambda:us-east-1:123456789012:function:hello","BatchSize":100,"State":"Creating","LastModified":1425211200.5,"LastProcessingResult":"No records processed"}`))
+}
+
+// testListMappings returns two pages of mappings.
+func testListMappings(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("Marker") == "" {
+		w.Write([]byte(`{"EventSourceMappings":[{"UUID":"a1b2","State":"Enabled"}],"NextMarker":"page2"}`))
+		return
+	}
+	w.Write([]byte(`{"EventSourceMappings":[{"UUID":"c3d4","State":"Disabled"}]}`))
+}
+
+func TestCreateEventSourceMapping(t *testing.T) {
+	Convey("Given a stream and a function", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testMapping, &requests)
+		defer ts.Close()
+
+		mapping, err := s.CreateEventSourceMapping(EventSourceMappingDefinition{EventSourceArn: testStreamArn, FunctionName: "hello:live", BatchSize: 500, StartingPosition: TrimHorizon})
+
+		Convey("The mapping is created and returned", func() {
+			So(err, ShouldBeNil)
+			So(mapping, ShouldResemble, EventSourceMapping{
+				UUID:                 "a1b2",
+				EventSourceArn:       testStreamArn,
+				FunctionArn:          "arn:aws:lambda:us-east-1:123456789012:function:hello",
+				BatchSize:            100,
+				State:                "Creating",
+				LastModified:         1425211200.5,
+				LastProcessingResult: "No records processed",
+			})
+		})
+		Convey("The definition is sent", func() {
+			So(requests[0].Method, ShouldEqual, "POST")
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/")
+			So(string(requests[0].Body), ShouldEqual, `{"EventSourceArn":"arn:aws:kinesis:us-east-1:123456789012:stream/foo","FunctionName":"hello:live","BatchSize":500,"StartingPosition":"TRIM_HORIZON"}`)
+		})
+	})
+	Convey("Given a mapping that starts at a time and is disabled", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testMapping, &requests)
+		defer ts.Close()
+
+		enabled := false
+		start := time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+		_, err := s.CreateEventSourceMapping(EventSourceMappingDefinition{EventSourceArn: testStreamArn, FunctionName: "hello", Enabled: &enabled, StartingPosition: AtTimestamp, StartingPositionTimestamp: &start})
+
+		Convey("The time is sent in seconds", func() {
+			So(err, ShouldBeNil)
+			So(string(requests[0].Body), ShouldEqual, `{"EventSourceArn":"arn:aws:kinesis:us-east-1:123456789012:stream/foo","FunctionName":"hello","Enabled":false,"StartingPosition":"AT_TIMESTAMP","StartingPositionTimestamp":1425211200}`)
+		})
+	})
+	Convey("Given a function that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.CreateEventSourceMapping(EventSourceMappingDefinition{EventSourceArn: testStreamArn, FunctionName: "foo", StartingPosition: Latest})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s := testService(testBadJson, nil)
+		defer ts.Close()
+
+		_, err := s.CreateEventSourceMapping(EventSourceMappingDefinition{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetEventSourceMapping(t *testing.T) {
+	Convey("Given a mapping's UUID", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testMapping, &requests)
+		defer ts.Close()
+
+		mapping, err := s.GetEventSourceMapping("a1b2")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(mapping.UUID, ShouldEqual, "a1b2")
+			So(requests[0].Method, ShouldEqual, "GET")
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/a1b2")
+		})
+	})
+}
+
+func TestListEventSourceMappings(t *testing.T) {
+	Convey("Given mappings that take two pages", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testListMappings, &requests)
+		defer ts.Close()
+
+		mappings, err := s.ListEventSourceMappings(testStreamArn, "hello")
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(len(mappings), ShouldEqual, 2)
+			So(mappings[0].UUID, ShouldEqual, "a1b2")
+			So(mappings[1].State, ShouldEqual, "Disabled")
+		})
+		Convey("The stream and function are sent", func() {
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/?EventSourceArn=arn%3Aaws%3Akinesis%3Aus-east-1%3A123456789012%3Astream%2Ffoo&FunctionName=hello")
+			So(requests[1].URI, ShouldEqual, "/2015-03-31/event-source-mappings/?EventSourceArn=arn%3Aaws%3Akinesis%3Aus-east-1%3A123456789012%3Astream%2Ffoo&FunctionName=hello&Marker=page2")
+		})
+	})
+	Convey("Given neither a stream nor a function", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testListMappings, &requests)
+		defer ts.Close()
+
+		s.ListEventSourceMappings("", "")
+
+		Convey("Every mapping is listed", func() {
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/")
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ListEventSourceMappings("", "foo")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, notFoundError)
+		})
+	})
+}
+
+func TestUpdateEventSourceMapping(t *testing.T) {
+	Convey("Given a mapping to pause", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testMapping, &requests)
+		defer ts.Close()
+
+		enabled := false
+		_, err := s.UpdateEventSourceMapping("a1b2", EventSourceMappingUpdate{Enabled: &enabled, BatchSize: 50})
+
+		Convey("Only the changes are sent", func() {
+			So(err, ShouldBeNil)
+			So(requests[0].Method, ShouldEqual, "PUT")
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/a1b2")
+			So(string(requests[0].Body), ShouldEqual, `{"Enabled":false,"BatchSize":50}`)
+		})
+	})
+}
+
+func TestDeleteEventSourceMapping(t *testing.T) {
+	Convey("Given a mapping", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testMapping, &requests)
+		defer ts.Close()
+
+		_, err := s.DeleteEventSourceMapping("a1b2")
+
+		Convey("It is deleted", func() {
+			So(err, ShouldBeNil)
+			So(requests[0].Method, ShouldEqual, "DELETE")
+			So(requests[0].URI, ShouldEqual, "/2015-03-31/event-source-mappings/a1b2")
+		})
+	})
+}
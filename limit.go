@@ -0,0 +1,55 @@
+package gaws
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// MaxResponseSize is the most bytes of a response body gaws reads into memory, for requests that do not have their own limit.
+// If it is 0, there is no limit. Bodies that DoResponse returns to be streamed are not limited.
+var MaxResponseSize int64
+
+// ResponseTooLargeError is returned when a response body is larger than the request's limit, so it was not read.
+// Some calls, like Kinesis's GetRecords, ask for less when they get it.
+type ResponseTooLargeError struct {
+	Operation string // The API call that was made
+	Limit     int64  // The most bytes that were allowed
+}
+
+// Error formats the ResponseTooLargeError into an error message.
+func (e ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("GawsResponseTooLarge: the response to %v is larger than %d bytes", e.Operation, e.Limit)
+}
+
+// maxResponseSize returns the limit on the size of the request's response.
+func (r *AWSRequest) maxResponseSize() int64 {
+	if r.MaxResponseSize > 0 {
+		return r.MaxResponseSize
+	}
+	return MaxResponseSize
+}
+
+// readBody reads the body of a response, unless it is larger than the request's limit.
+func (r *AWSRequest) readBody(resp *http.Response) ([]byte, error) {
+	limit := r.maxResponseSize()
+	if limit <= 0 {
+		return ioutil.ReadAll(resp.Body)
+	}
+
+	tooLarge := ResponseTooLargeError{Operation: r.operation(), Limit: limit}
+	if resp.ContentLength > limit {
+		return nil, tooLarge
+	}
+
+	// Read one byte more than the limit, to know if there was more.
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return body, err
+	}
+	if int64(len(body)) > limit {
+		return nil, tooLarge
+	}
+	return body, nil
+}
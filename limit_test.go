@@ -0,0 +1,65 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMaxResponseSize(t *testing.T) {
+	Convey("Given a server that returns 100 bytes", t, func() {
+		chunked := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if chunked {
+				// Flushing before writing sends the body without a Content-Length.
+				w.(http.Flusher).Flush()
+			}
+			w.Write([]byte(strings.Repeat("a", 100)))
+		}))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+		tooLarge := ResponseTooLargeError{Operation: "GET", Limit: 99}
+
+		Convey("A request with a smaller limit returns an error", func() {
+			r.MaxResponseSize = 99
+			_, err := r.Do()
+			So(err, ShouldResemble, tooLarge)
+		})
+		Convey("A request with a smaller limit returns an error when the size is not known until it is read", func() {
+			chunked = true
+			r.MaxResponseSize = 99
+			_, err := r.Do()
+			So(err, ShouldResemble, tooLarge)
+		})
+		Convey("A request with a limit it fits reads it", func() {
+			chunked = true
+			r.MaxResponseSize = 100
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(len(body), ShouldEqual, 100)
+		})
+		Convey("Requests without a limit use MaxResponseSize", func() {
+			MaxResponseSize = 99
+			defer func() { MaxResponseSize = 0 }()
+
+			_, err := r.Do()
+			So(err, ShouldResemble, tooLarge)
+		})
+		Convey("A config sets the limit", func() {
+			NewConfig(WithMaxResponseSize(99)).Apply(&r)
+			_, err := r.Do()
+			So(err, ShouldResemble, tooLarge)
+		})
+		Convey("DoResponse does not limit a body it returns", func() {
+			r.MaxResponseSize = 99
+			resp, err := r.DoResponse()
+			So(err, ShouldBeNil)
+			resp.Body.Close()
+		})
+	})
+}
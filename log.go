@@ -0,0 +1,17 @@
+package gaws
+
+// Logger is what gaws writes to when something goes wrong that it cannot return as an error, like a callback that panicked.
+// A *log.Logger is one. It is used from every goroutine making requests, so it must be safe for concurrent use.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// Log is where gaws logs to. If it is nil, nothing is logged.
+var Log Logger
+
+// logf writes to Log, if there is one.
+func logf(format string, v ...interface{}) {
+	if Log != nil {
+		Log.Printf(format, v...)
+	}
+}
@@ -0,0 +1,27 @@
+package gaws
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLog(t *testing.T) {
+	Convey("Given a logger", t, func() {
+		b := &bytes.Buffer{}
+		Log = log.New(b, "", 0)
+		defer func() { Log = nil }()
+
+		Convey("gaws writes to it", func() {
+			logf("gaws: %v", "something")
+			So(b.String(), ShouldEqual, "gaws: something\n")
+		})
+	})
+	Convey("Given no logger", t, func() {
+		Convey("Nothing is logged", func() {
+			So(func() { logf("gaws: %v", "something") }, ShouldNotPanic)
+		})
+	})
+}
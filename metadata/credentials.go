@@ -0,0 +1,112 @@
+package metadata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// ExpiryWindow is how long before they expire an InstanceProvider gets new credentials, so requests are not signed with credentials that are about to expire.
+var ExpiryWindow = 5 * time.Minute
+
+// RoleCredentials are the temporary credentials of the instance's role. They are a gaws.CredentialsProvider, so they can be used as
+// gaws.Credentials, or as the Credentials of a request or service.
+type RoleCredentials struct {
+	Code            string // Success if there are credentials
+	AccessKeyId     string
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+// Credentials returns the credentials for awsauth to sign requests with.
+func (c RoleCredentials) Credentials() (awsauth.Credentials, error) {
+	return awsauth.Credentials{
+		AccessKeyID:     c.AccessKeyId,
+		SecretAccessKey: c.SecretAccessKey,
+		SecurityToken:   c.Token,
+		Expiration:      c.Expiration,
+	}, nil
+}
+
+// Expired returns true if the credentials expire within d.
+func (c RoleCredentials) Expired(d time.Duration) bool {
+	return !c.Expiration.After(time.Now().Add(d))
+}
+
+// RoleCredentials returns the credentials of a role of the instance. If role is "", it is the role the instance was launched with.
+func (s *MetadataService) RoleCredentials(role string) (RoleCredentials, error) {
+	credentials := RoleCredentials{}
+
+	if role == "" {
+		roles, err := s.Get("iam/security-credentials/")
+		if err != nil {
+			return credentials, err
+		}
+		role = strings.TrimSpace(strings.SplitN(roles, "\n", 2)[0])
+	}
+
+	body, err := s.get("/meta-data/iam/security-credentials/" + role)
+	if err != nil {
+		return credentials, err
+	}
+
+	err = json.Unmarshal(body, &credentials)
+	if err == nil && credentials.Code != "Success" {
+		err = fmt.Errorf("metadata: there are no credentials for role %v: %v", role, credentials.Code)
+	}
+	return credentials, err
+}
+
+// InstanceProvider is a gaws.CredentialsProvider with the credentials of the instance's role, which it gets from the metadata service
+// again when they are about to expire. awsauth does the same when there are no credentials, but an InstanceProvider can be given to a
+// gaws.Refresher to get them ahead of time.
+type InstanceProvider struct {
+	Service *MetadataService
+	Role    string // If it is empty, it is the role the instance was launched with
+
+	lock        sync.Mutex // Protects credentials, and makes sure only one request for them is made at a time
+	credentials RoleCredentials
+}
+
+var _ gaws.RefreshingProvider = (*InstanceProvider)(nil)
+
+// NewInstanceProvider returns an InstanceProvider for the role the instance was launched with. It does not get credentials until they are needed.
+func (s *MetadataService) NewInstanceProvider() *InstanceProvider {
+	return &InstanceProvider{Service: s}
+}
+
+// Credentials returns the credentials of the role, getting them if they are missing or about to expire.
+func (p *InstanceProvider) Credentials() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.credentials.Expired(ExpiryWindow) {
+		return p.refresh()
+	}
+	return p.credentials.Credentials()
+}
+
+// Refresh gets the credentials of the role now, even if the ones it has have not expired, and returns them. A gaws.Refresher calls it
+// to get them ahead of time. If it fails, the old credentials are kept.
+func (p *InstanceProvider) Refresh() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.refresh()
+}
+
+// refresh gets the credentials of the role and keeps them. The lock must be held.
+func (p *InstanceProvider) refresh() (awsauth.Credentials, error) {
+	credentials, err := p.Service.RoleCredentials(p.Role)
+	if err != nil {
+		return awsauth.Credentials{}, err
+	}
+	p.credentials = credentials
+	return credentials.Credentials()
+}
@@ -0,0 +1,94 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testRoleCredentials returns the credentials document of a role, which expire after d.
+func testRoleCredentials(accessKeyId string, d time.Duration) string {
+	return `{"Code":"Success","LastUpdated":"2015-01-01T00:00:00Z","Type":"AWS-HMAC","AccessKeyId":"` + accessKeyId + `",` +
+		`"SecretAccessKey":"secret","Token":"token","Expiration":"` + time.Now().Add(d).UTC().Format(time.RFC3339) + `"}`
+}
+
+func TestRoleCredentials(t *testing.T) {
+	Convey("Given an instance with a role", t, func() {
+		ts, s, requests := testService(map[string]string{
+			"/latest/meta-data/iam/security-credentials/":       "worker\n",
+			"/latest/meta-data/iam/security-credentials/worker": testRoleCredentials("ASIA1", time.Hour),
+		})
+		defer ts.Close()
+
+		Convey("The credentials of its role are returned", func() {
+			credentials, err := s.RoleCredentials("")
+			So(err, ShouldBeNil)
+			So(credentials.AccessKeyId, ShouldEqual, "ASIA1")
+			So(credentials.Expired(ExpiryWindow), ShouldBeFalse)
+
+			keys, _ := credentials.Credentials()
+			So(keys.SecretAccessKey, ShouldEqual, "secret")
+			So(keys.SecurityToken, ShouldEqual, "token")
+		})
+		Convey("The credentials of a named role are returned without looking for the role", func() {
+			_, err := s.RoleCredentials("worker")
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 2)
+			So(requests()[1].Path, ShouldEqual, "/latest/meta-data/iam/security-credentials/worker")
+		})
+	})
+	Convey("Given a role whose credentials could not be made", t, func() {
+		ts, s, _ := testService(map[string]string{
+			"/latest/meta-data/iam/security-credentials/worker": `{"Code":"AssumeRoleUnauthorizedAccess"}`,
+		})
+		defer ts.Close()
+
+		_, err := s.RoleCredentials("worker")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestInstanceProvider(t *testing.T) {
+	Convey("Given an instance provider", t, func() {
+		ts, s, requests := testService(map[string]string{
+			"/latest/meta-data/iam/security-credentials/":       "worker\n",
+			"/latest/meta-data/iam/security-credentials/worker": testRoleCredentials("ASIA1", time.Hour),
+		})
+		defer ts.Close()
+		p := s.NewInstanceProvider()
+
+		Convey("It gets the credentials once and caches them", func() {
+			first, err := p.Credentials()
+			So(err, ShouldBeNil)
+			second, _ := p.Credentials()
+
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(second, ShouldResemble, first)
+			So(len(requests()), ShouldEqual, 3)
+		})
+		Convey("Refresh gets them again", func() {
+			p.Credentials()
+			_, err := p.Refresh()
+			So(err, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 5)
+		})
+	})
+	Convey("Given credentials that are about to expire", t, func() {
+		ts, s, requests := testService(map[string]string{
+			"/latest/meta-data/iam/security-credentials/worker": testRoleCredentials("ASIA1", time.Minute),
+		})
+		defer ts.Close()
+		p := &InstanceProvider{Service: s, Role: "worker"}
+
+		p.Credentials()
+		p.Credentials()
+
+		Convey("They are got again", func() {
+			So(len(requests()), ShouldEqual, 3)
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package metadata
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// IdentityDocument describes the instance. It is signed by AWS, so other services can trust it.
+type IdentityDocument struct {
+	AccountId        string    `json:"accountId"`
+	Architecture     string    `json:"architecture"`
+	AvailabilityZone string    `json:"availabilityZone"`
+	ImageId          string    `json:"imageId"`
+	InstanceId       string    `json:"instanceId"`
+	InstanceType     string    `json:"instanceType"`
+	KernelId         string    `json:"kernelId"`
+	PendingTime      time.Time `json:"pendingTime"`
+	PrivateIp        string    `json:"privateIp"`
+	Region           string    `json:"region"`
+	Version          string    `json:"version"`
+}
+
+// IdentityDocument returns the instance identity document, without checking its signature.
+func (s *MetadataService) IdentityDocument() (IdentityDocument, error) {
+	document := IdentityDocument{}
+
+	body, err := s.get("/dynamic/instance-identity/document")
+	if err != nil {
+		return document, err
+	}
+
+	err = json.Unmarshal(body, &document)
+	return document, err
+}
+
+// VerifiedIdentityDocument returns the instance identity document after checking that it was signed with certificate's key.
+// certificate is the AWS public certificate for the region, from
+// http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/instance-identity-documents.html.
+func (s *MetadataService) VerifiedIdentityDocument(certificate *x509.Certificate) (IdentityDocument, error) {
+	document := IdentityDocument{}
+
+	body, err := s.get("/dynamic/instance-identity/document")
+	if err != nil {
+		return document, err
+	}
+
+	encoded, err := s.get("/dynamic/instance-identity/signature")
+	if err != nil {
+		return document, err
+	}
+
+	// The signature is split over several lines.
+	signature, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(encoded)), ""))
+	if err != nil {
+		return document, err
+	}
+
+	err = certificate.CheckSignature(x509.SHA256WithRSA, body, signature)
+	if err != nil {
+		return document, err
+	}
+
+	err = json.Unmarshal(body, &document)
+	return document, err
+}
@@ -0,0 +1,149 @@
+package metadata
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testIdentityDocument = `{
+  "devpayProductCodes" : null,
+  "privateIp" : "10.1.2.3",
+  "availabilityZone" : "us-east-1d",
+  "version" : "2010-08-31",
+  "instanceId" : "i-1234567890abcdef0",
+  "billingProducts" : null,
+  "instanceType" : "t2.micro",
+  "accountId" : "123456789012",
+  "imageId" : "ami-5fb8c835",
+  "pendingTime" : "2016-11-19T16:32:11Z",
+  "architecture" : "x86_64",
+  "kernelId" : null,
+  "ramdiskId" : null,
+  "region" : "us-east-1"
+}`
+
+// testCertificate returns a self-signed certificate and its key, standing in for the AWS certificate.
+func testCertificate() (*x509.Certificate, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	So(err, ShouldBeNil)
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "Amazon Web Services LLC"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	So(err, ShouldBeNil)
+
+	certificate, err := x509.ParseCertificate(der)
+	So(err, ShouldBeNil)
+	return certificate, key
+}
+
+// testSignature returns the signature of the document, base64 encoded over lines like the metadata service's.
+func testSignature(key *rsa.PrivateKey, document string) string {
+	digest := sha256.Sum256([]byte(document))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	So(err, ShouldBeNil)
+
+	encoded := base64.StdEncoding.EncodeToString(signature)
+	return encoded[:64] + "\n" + encoded[64:]
+}
+
+var expectedIdentityDocument = IdentityDocument{
+	AccountId:        "123456789012",
+	Architecture:     "x86_64",
+	AvailabilityZone: "us-east-1d",
+	ImageId:          "ami-5fb8c835",
+	InstanceId:       "i-1234567890abcdef0",
+	InstanceType:     "t2.micro",
+	PendingTime:      time.Date(2016, 11, 19, 16, 32, 11, 0, time.UTC),
+	PrivateIp:        "10.1.2.3",
+	Region:           "us-east-1",
+	Version:          "2010-08-31",
+}
+
+func TestIdentityDocument(t *testing.T) {
+	Convey("Given an instance", t, func() {
+		ts, s, _ := testService(map[string]string{"/latest/dynamic/instance-identity/document": testIdentityDocument})
+		defer ts.Close()
+
+		document, err := s.IdentityDocument()
+
+		Convey("It returns the identity document", func() {
+			So(err, ShouldBeNil)
+			So(document, ShouldResemble, expectedIdentityDocument)
+		})
+	})
+	Convey("Given an instance with no identity document", t, func() {
+		ts, s, _ := testService(map[string]string{})
+		defer ts.Close()
+
+		_, err := s.IdentityDocument()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestVerifiedIdentityDocument(t *testing.T) {
+	Convey("Given a signed identity document", t, func() {
+		certificate, key := testCertificate()
+		ts, s, _ := testService(map[string]string{
+			"/latest/dynamic/instance-identity/document":  testIdentityDocument,
+			"/latest/dynamic/instance-identity/signature": testSignature(key, testIdentityDocument),
+		})
+		defer ts.Close()
+
+		Convey("It is returned if the certificate matches", func() {
+			document, err := s.VerifiedIdentityDocument(certificate)
+			So(err, ShouldBeNil)
+			So(document, ShouldResemble, expectedIdentityDocument)
+		})
+		Convey("It returns an error if the certificate does not match", func() {
+			other, _ := testCertificate()
+			_, err := s.VerifiedIdentityDocument(other)
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a document that has been changed", t, func() {
+		certificate, key := testCertificate()
+		ts, s, _ := testService(map[string]string{
+			"/latest/dynamic/instance-identity/document":  testIdentityDocument,
+			"/latest/dynamic/instance-identity/signature": testSignature(key, `{"accountId":"210987654321"}`),
+		})
+		defer ts.Close()
+
+		_, err := s.VerifiedIdentityDocument(certificate)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a signature that is not base64", t, func() {
+		certificate, _ := testCertificate()
+		ts, s, _ := testService(map[string]string{
+			"/latest/dynamic/instance-identity/document":  testIdentityDocument,
+			"/latest/dynamic/instance-identity/signature": "not base64!",
+		})
+		defer ts.Close()
+
+		_, err := s.VerifiedIdentityDocument(certificate)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,31 @@
+package metadata
+
+// InstanceId returns the ID of the instance, like i-1234567890abcdef0.
+func (s *MetadataService) InstanceId() (string, error) {
+	return s.Get("instance-id")
+}
+
+// AvailabilityZone returns the availability zone the instance is in, like us-east-1a.
+func (s *MetadataService) AvailabilityZone() (string, error) {
+	return s.Get("placement/availability-zone")
+}
+
+// Region returns the region the instance is in, like us-east-1, for gaws.Region.
+func (s *MetadataService) Region() (string, error) {
+	zone, err := s.AvailabilityZone()
+	if err != nil || zone == "" {
+		return "", err
+	}
+
+	// The zone is the region with a letter on the end.
+	return zone[:len(zone)-1], nil
+}
+
+// UserData returns the user data the instance was launched with. It returns no data if there is none.
+func (s *MetadataService) UserData() ([]byte, error) {
+	data, err := s.get("/user-data")
+	if e, ok := err.(metadataError); ok && e.Status == 404 {
+		return []byte{}, nil
+	}
+	return data, err
+}
@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestInstance(t *testing.T) {
+	Convey("Given an instance", t, func() {
+		ts, s, _ := testService(map[string]string{
+			"/latest/meta-data/instance-id":                 "i-1234567890abcdef0",
+			"/latest/meta-data/placement/availability-zone": "eu-west-1c",
+			"/latest/user-data":                             "#!/bin/sh\necho hello",
+		})
+		defer ts.Close()
+
+		Convey("InstanceId returns its ID", func() {
+			id, err := s.InstanceId()
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "i-1234567890abcdef0")
+		})
+		Convey("AvailabilityZone returns its zone", func() {
+			zone, err := s.AvailabilityZone()
+			So(err, ShouldBeNil)
+			So(zone, ShouldEqual, "eu-west-1c")
+		})
+		Convey("Region returns its region", func() {
+			region, err := s.Region()
+			So(err, ShouldBeNil)
+			So(region, ShouldEqual, "eu-west-1")
+		})
+		Convey("UserData returns its user data", func() {
+			data, err := s.UserData()
+			So(err, ShouldBeNil)
+			So(string(data), ShouldEqual, "#!/bin/sh\necho hello")
+		})
+	})
+	Convey("Given an instance without user data", t, func() {
+		ts, s, _ := testService(map[string]string{})
+		defer ts.Close()
+
+		data, err := s.UserData()
+
+		Convey("It returns no data", func() {
+			So(err, ShouldBeNil)
+			So(data, ShouldResemble, []byte{})
+		})
+		Convey("Region returns an error", func() {
+			_, err := s.Region()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,140 @@
+// Package metadata provides a way to read the EC2 instance metadata service, so workers can configure themselves from the instance they run on.
+// awsauth already reads credentials from the instance's role, but an InstanceProvider can get them ahead of time with a gaws.Refresher.
+package metadata
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Endpoint is the endpoint of the metadata service on every EC2 instance.
+const Endpoint = "http://169.254.169.254"
+
+// Timeout is how long a request to the metadata service can take. Off EC2, there is nothing to answer, so it is short.
+var Timeout = 2 * time.Second
+
+// TokenTTL is how long a session token is used for before another is requested.
+var TokenTTL = 6 * time.Hour
+
+// metadataError is returned when the metadata service does not return something.
+type metadataError struct {
+	Path   string
+	Status int
+}
+
+// Error formats the metadataError into an error message.
+func (e metadataError) Error() string {
+	return fmt.Sprintf("metadata: %v returned status %d", e.Path, e.Status)
+}
+
+// MetadataService is the metadata service of the instance. The zero value uses Endpoint.
+// It gets a session token before reading metadata, and reads without one if the instance does not support them.
+type MetadataService struct {
+	Endpoint string
+
+	lock    sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// MetadataAPI is implemented by *MetadataService. Code that takes a MetadataAPI can be given a fake in tests instead.
+type MetadataAPI interface {
+	AvailabilityZone() (string, error)
+	Get(item string) (string, error)
+	IdentityDocument() (IdentityDocument, error)
+	InstanceId() (string, error)
+	Region() (string, error)
+	RoleCredentials(role string) (RoleCredentials, error)
+	UserData() ([]byte, error)
+	VerifiedIdentityDocument(certificate *x509.Certificate) (IdentityDocument, error)
+}
+
+var _ MetadataAPI = (*MetadataService)(nil)
+
+func (s *MetadataService) endpoint() string {
+	if s.Endpoint == "" {
+		return Endpoint
+	}
+	return s.Endpoint
+}
+
+// sessionToken returns the session token to read metadata with, getting a new one if it has expired.
+// It returns "" if the instance does not hand out tokens.
+func (s *MetadataService) sessionToken(client *http.Client) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	req, _ := http.NewRequest("PUT", s.endpoint()+"/latest/api/token", nil)
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", strconv.Itoa(int(TokenTTL/time.Second)))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case resp.StatusCode == 200:
+		s.token = string(body)
+	case resp.StatusCode == 403 || resp.StatusCode == 404 || resp.StatusCode == 405:
+		// Tokens are turned off or not supported, so read without one.
+		s.token = ""
+	default:
+		return "", metadataError{Path: "/latest/api/token", Status: resp.StatusCode}
+	}
+
+	// Stop using the token a minute early, so it does not expire between here and the request.
+	s.expires = time.Now().Add(TokenTTL - time.Minute)
+	return s.token, nil
+}
+
+// get returns the body of a path under /latest, like /meta-data/instance-id.
+func (s *MetadataService) get(path string) ([]byte, error) {
+	client := &http.Client{Timeout: Timeout}
+
+	token, err := s.sessionToken(client)
+	if err != nil {
+		return nil, err
+	}
+
+	req, _ := http.NewRequest("GET", s.endpoint()+"/latest"+path, nil)
+	if token != "" {
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, metadataError{Path: path, Status: resp.StatusCode}
+	}
+	return body, nil
+}
+
+// Get returns a metadata item, like "instance-type" or "placement/availability-zone".
+// See http://docs.aws.amazon.com/AWSEC2/latest/UserGuide/ec2-instance-metadata.html for the items there are.
+func (s *MetadataService) Get(item string) (string, error) {
+	body, err := s.get("/meta-data/" + item)
+	return string(body), err
+}
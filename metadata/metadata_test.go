@@ -0,0 +1,148 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testToken = "AQAEAFTNrA4eEGx0AQgJ1arIq_Cc-t4tWt3fB0Hd8RKhXlKc5ccvhg=="
+
+// testRequest is a request the metadata service got.
+type testRequest struct {
+	Method string
+	Path   string
+	Token  string
+}
+
+// testService returns a service on a server that hands out tokens and returns items from metadata, and records every request.
+func testService(metadata map[string]string) (*httptest.Server, *MetadataService, func() []testRequest) {
+	lock := sync.Mutex{}
+	requests := []testRequest{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		requests = append(requests, testRequest{Method: r.Method, Path: r.URL.Path, Token: r.Header.Get("X-aws-ec2-metadata-token")})
+		lock.Unlock()
+
+		if r.URL.Path == "/latest/api/token" {
+			if r.Method != "PUT" || r.Header.Get("X-aws-ec2-metadata-token-ttl-seconds") == "" {
+				w.WriteHeader(400)
+				return
+			}
+			w.Write([]byte(testToken))
+			return
+		}
+
+		item, ok := metadata[r.URL.Path]
+		if !ok {
+			w.WriteHeader(404)
+			return
+		}
+		w.Write([]byte(item))
+	}))
+
+	return ts, &MetadataService{Endpoint: ts.URL}, func() []testRequest {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]testRequest{}, requests...)
+	}
+}
+
+func TestGet(t *testing.T) {
+	Convey("Given an instance that hands out tokens", t, func() {
+		ts, s, requests := testService(map[string]string{"/latest/meta-data/instance-type": "m3.medium"})
+		defer ts.Close()
+
+		instanceType, err := s.Get("instance-type")
+
+		Convey("It returns the item", func() {
+			So(err, ShouldBeNil)
+			So(instanceType, ShouldEqual, "m3.medium")
+		})
+		Convey("It reads with a token", func() {
+			So(requests(), ShouldResemble, []testRequest{
+				{Method: "PUT", Path: "/latest/api/token"},
+				{Method: "GET", Path: "/latest/meta-data/instance-type", Token: testToken},
+			})
+		})
+		Convey("The token is reused", func() {
+			s.Get("instance-type")
+			So(len(requests()), ShouldEqual, 3)
+			So(requests()[2].Token, ShouldEqual, testToken)
+		})
+	})
+	Convey("Given an instance that does not hand out tokens", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "PUT" {
+				w.WriteHeader(405)
+				return
+			}
+			if r.Header.Get("X-aws-ec2-metadata-token") != "" {
+				w.WriteHeader(401)
+				return
+			}
+			w.Write([]byte("m3.medium"))
+		}))
+		defer ts.Close()
+		s := MetadataService{Endpoint: ts.URL}
+
+		instanceType, err := s.Get("instance-type")
+
+		Convey("It reads without a token", func() {
+			So(err, ShouldBeNil)
+			So(instanceType, ShouldEqual, "m3.medium")
+		})
+	})
+	Convey("Given an item that does not exist", t, func() {
+		ts, s, _ := testService(map[string]string{})
+		defer ts.Close()
+
+		_, err := s.Get("foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, metadataError{Path: "/meta-data/foo", Status: 404})
+		})
+	})
+	Convey("Given a token endpoint that fails", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(500)
+		}))
+		defer ts.Close()
+		s := MetadataService{Endpoint: ts.URL}
+
+		_, err := s.Get("instance-type")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, metadataError{Path: "/latest/api/token", Status: 500})
+		})
+	})
+	Convey("Given no metadata service", t, func() {
+		timeout := Timeout
+		Timeout = 100 * time.Millisecond
+		defer func() { Timeout = timeout }()
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(time.Second)
+		}))
+		defer ts.Close()
+		s := MetadataService{Endpoint: ts.URL}
+
+		_, err := s.Get("instance-type")
+
+		Convey("It gives up", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given no endpoint", t, func() {
+		s := MetadataService{}
+
+		Convey("Endpoint is used", func() {
+			So(s.endpoint(), ShouldEqual, "http://169.254.169.254")
+		})
+	})
+}
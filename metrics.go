@@ -0,0 +1,85 @@
+package gaws
+
+import (
+	"bytes"
+	"net/url"
+	"time"
+)
+
+// RequestMetrics describes a request gaws made to AWS, including all of its retries.
+type RequestMetrics struct {
+	Operation string        // Like DynamoDB_20120810.PutItem or SendMessage. Requests without a named operation, like S3's, use the HTTP method.
+	Tries     int           // How many times the request was sent
+	Throttles int           // How many of the tries AWS throttled
+	Latency   time.Duration // From the first try to the last response, including the backoff between tries
+	Err       error         // The error the request returned, if there was one
+	Protocol  string        // The protocol of the last response, like HTTP/2.0 or HTTP/1.1. It is empty if there was no response.
+	Endpoint  string        // The host the request was sent to, like kinesis.us-east-1.amazonaws.com
+
+	RetryBudgetExhausted bool // True if the request was not retried because its retry budget had run out
+	RetryBudgetAvailable int  // The tokens left in the request's retry budget when it finished. It is 0 if it has no budget.
+}
+
+// MetricsCollector is told about every request gaws makes, so they can be monitored.
+// RequestDone is called from every goroutine making requests, so it must be safe for concurrent use and should return quickly.
+type MetricsCollector interface {
+	RequestDone(m RequestMetrics)
+}
+
+// Metrics is told about every request gaws makes. If it is nil, no metrics are collected.
+var Metrics MetricsCollector
+
+// throttleCodes are parts of the error codes AWS services use when they throttle a request.
+var throttleCodes = [][]byte{[]byte("Throttl"), []byte("ProvisionedThroughputExceeded"), []byte("RequestLimitExceeded"), []byte("SlowDown")}
+
+// isThrottle returns true if a response says the request was throttled.
+func isThrottle(status int, body []byte) bool {
+	if status == 429 {
+		return true
+	}
+	if status < 400 {
+		return false
+	}
+	for _, code := range throttleCodes {
+		if bytes.Contains(body, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// operation returns the name of the API call the request makes.
+func (r *AWSRequest) operation() string {
+	if target, ok := r.Headers["X-Amz-Target"]; ok {
+		return target
+	}
+	if r.Headers["Content-Type"] == "application/x-www-form-urlencoded" {
+		params, err := url.ParseQuery(string(r.Body))
+		if err == nil && params.Get("Action") != "" {
+			return params.Get("Action")
+		}
+	}
+	return r.Method
+}
+
+// host returns the host the request is sent to.
+func (r *AWSRequest) host() string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// report tells Metrics about a request that took latency, if there is a collector. A collector that panics does not fail the request.
+func report(m RequestMetrics, latency time.Duration, err error) {
+	if Metrics == nil {
+		return
+	}
+	m.Latency = latency
+	m.Err = err
+	Protect("gaws.Metrics", func() error {
+		Metrics.RequestDone(m)
+		return nil
+	})
+}
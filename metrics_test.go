@@ -0,0 +1,87 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testCollector struct {
+	lock    sync.Mutex
+	metrics []RequestMetrics
+}
+
+func (c *testCollector) RequestDone(m RequestMetrics) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.metrics = append(c.metrics, m)
+}
+
+func TestMetrics(t *testing.T) {
+	Convey("Given a metrics collector", t, func() {
+		c := &testCollector{}
+		Metrics = c
+		defer func() { Metrics = nil }()
+
+		Convey("When a request succeeds", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL
+			r.Headers["X-Amz-Target"] = "DynamoDB_20120810.PutItem"
+			r.Do()
+
+			Convey("It is told about the request", func() {
+				So(len(c.metrics), ShouldEqual, 1)
+				So(c.metrics[0].Operation, ShouldEqual, "DynamoDB_20120810.PutItem")
+				So(c.metrics[0].Tries, ShouldEqual, 1)
+				So(c.metrics[0].Throttles, ShouldEqual, 0)
+				So(c.metrics[0].Latency, ShouldBeGreaterThan, 0)
+				So(c.metrics[0].Err, ShouldBeNil)
+			})
+		})
+
+		Convey("When a request is throttled", func() {
+			_, restore := useTestClock()
+			defer restore()
+
+			ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL
+			_, err := r.Do()
+
+			Convey("It is told about the retries and throttles", func() {
+				So(len(c.metrics), ShouldEqual, 1)
+				So(c.metrics[0].Operation, ShouldEqual, "GET")
+				So(c.metrics[0].Tries, ShouldEqual, MaxTries-1)
+				So(c.metrics[0].Throttles, ShouldEqual, MaxTries-1)
+				So(c.metrics[0].Err, ShouldResemble, err)
+			})
+		})
+
+		Convey("When a streamed request fails", func() {
+			ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+			defer ts.Close()
+
+			r := canonicalRequest()
+			r.URL = ts.URL
+			r.Method = "POST"
+			r.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			r.Body = []byte("Action=SendMessage&Version=2012-11-05")
+			r.DoResponse()
+
+			Convey("It is told about the request and its error", func() {
+				So(len(c.metrics), ShouldEqual, 1)
+				So(c.metrics[0].Operation, ShouldEqual, "SendMessage")
+				So(c.metrics[0].Tries, ShouldEqual, 1)
+				So(c.metrics[0].Err, ShouldResemble, notFoundError)
+			})
+		})
+	})
+}
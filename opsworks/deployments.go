@@ -0,0 +1,67 @@
+package opsworks
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The names of deployment commands. Deploy, Rollback, Start, Stop, Restart and Undeploy are for apps, the rest for stacks.
+const (
+	Deploy                = "deploy"
+	Rollback              = "rollback"
+	Start                 = "start"
+	Stop                  = "stop"
+	Restart               = "restart"
+	Undeploy              = "undeploy"
+	ExecuteRecipes        = "execute_recipes"
+	UpdateCustomCookbooks = "update_custom_cookbooks"
+	UpdateDependencies    = "update_dependencies"
+	Setup                 = "setup"
+	Configure             = "configure"
+)
+
+// DeploymentCommand is what a deployment does, like Deploy. Args depend on the command, like {"recipes": ["nginx::default"]} for ExecuteRecipes.
+type DeploymentCommand struct {
+	Name string
+	Args map[string][]string `json:",omitempty"`
+}
+
+// DeploymentInput describes a deployment to create.
+type DeploymentInput struct {
+	StackId     string
+	AppId       string   `json:",omitempty"` // The app to deploy, for app commands
+	InstanceIds []string `json:",omitempty"` // The instances to deploy to. If there are none and no layers, every instance in the stack is used.
+	LayerIds    []string `json:",omitempty"` // The layers to deploy to
+	Command     DeploymentCommand
+	Comment     string `json:",omitempty"`
+	CustomJson  string `json:",omitempty"` // JSON that overrides the stack's custom JSON for this deployment
+}
+
+type createDeploymentResult struct {
+	DeploymentId string
+}
+
+// CreateDeployment runs a command on a stack's instances, like deploying an app, and returns the ID of the deployment.
+// It is calling the CreateDeployment API call.
+// See http://docs.aws.amazon.com/opsworks/latest/APIReference/API_CreateDeployment.html for more details.
+func (s *OpsWorksService) CreateDeployment(input DeploymentInput) (string, error) {
+	result := createDeploymentResult{}
+
+	bodyAsJson, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "OpsWorks_20130218.CreateDeployment"
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.DeploymentId, err
+}
@@ -0,0 +1,55 @@
+package opsworks
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateDeployment(t *testing.T) {
+	Convey("Given an app to deploy", t, func() {
+		ts, s, requests := testService(testJson(`{"DeploymentId":"5746c781-df7f-4c87-84a7-65a119880560"}`))
+		defer ts.Close()
+
+		id, err := s.CreateDeployment(DeploymentInput{
+			StackId: "2f18b4cb-4de5-4429-a149-ff7da9f0d8ee",
+			AppId:   "6a4a4b6e-5e2b-4a5c-9b4d-7b1c3f5e8d2a",
+			Command: DeploymentCommand{Name: Deploy},
+			Comment: "v13",
+		})
+
+		Convey("It returns the ID of the deployment", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "5746c781-df7f-4c87-84a7-65a119880560")
+		})
+		Convey("It sends the deployment", func() {
+			So(requests()[0].Target, ShouldEqual, "OpsWorks_20130218.CreateDeployment")
+			So(string(requests()[0].Body), ShouldEqual, `{"StackId":"2f18b4cb-4de5-4429-a149-ff7da9f0d8ee","AppId":"6a4a4b6e-5e2b-4a5c-9b4d-7b1c3f5e8d2a","Command":{"Name":"deploy"},"Comment":"v13"}`)
+		})
+	})
+	Convey("Given recipes to run on a layer", t, func() {
+		ts, s, requests := testService(testJson(`{"DeploymentId":"5746c781-df7f-4c87-84a7-65a119880560"}`))
+		defer ts.Close()
+
+		_, err := s.CreateDeployment(DeploymentInput{
+			StackId:  "2f18b4cb-4de5-4429-a149-ff7da9f0d8ee",
+			LayerIds: []string{"97c6b6a4-4b76-4fba-9dd9-05b15e457ab0"},
+			Command:  DeploymentCommand{Name: ExecuteRecipes, Args: map[string][]string{"recipes": {"workers::restart"}}},
+		})
+
+		Convey("It sends the command's arguments", func() {
+			So(err, ShouldBeNil)
+			So(string(requests()[0].Body), ShouldContainSubstring, `"Command":{"Name":"execute_recipes","Args":{"recipes":["workers::restart"]}}`)
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.CreateDeployment(DeploymentInput{StackId: "2f18b4cb-4de5-4429-a149-ff7da9f0d8ee", Command: DeploymentCommand{Name: Setup}})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+}
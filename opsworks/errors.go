@@ -0,0 +1,17 @@
+// Code generated by errgen from the go:generate line in opsworks.go. DO NOT EDIT.
+
+package opsworks
+
+import "strings"
+
+// These are errors OpsWorks returns. errors.Is(err, opsworks.ErrResourceNotFound) is true for any error with the same type, whatever its message.
+var (
+	ErrResourceNotFound = opsWorksError{Type: "ResourceNotFoundException"}
+	ErrValidation       = opsWorksError{Type: "ValidationException"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e opsWorksError) Is(target error) bool {
+	t, ok := target.(opsWorksError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
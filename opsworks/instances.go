@@ -0,0 +1,75 @@
+package opsworks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The statuses of an instance that matter most to deployments. See the API reference for the rest.
+const (
+	Online       = "online"
+	Stopped      = "stopped"
+	Booting      = "booting"
+	SetupFailed  = "setup_failed"
+	StartFailed  = "start_failed"
+	Terminated   = "terminated"
+	RunningSetup = "running_setup"
+)
+
+// Instance is an instance in an OpsWorks stack.
+type Instance struct {
+	InstanceId       string
+	Ec2InstanceId    string
+	Hostname         string
+	StackId          string
+	LayerIds         []string
+	Status           string // Like Online or Stopped
+	InstanceType     string
+	Os               string
+	AvailabilityZone string
+	SubnetId         string
+	PublicIp         string
+	PrivateIp        string
+	PublicDns        string
+	PrivateDns       string
+	CreatedAt        time.Time
+}
+
+// InstanceQuery is which instances DescribeInstances describes. Only one of its fields can be set.
+type InstanceQuery struct {
+	StackId     string   `json:",omitempty"` // The instances in a stack
+	LayerId     string   `json:",omitempty"` // The instances in a layer
+	InstanceIds []string `json:",omitempty"` // The instances with the IDs
+}
+
+type describeInstancesResult struct {
+	Instances []Instance
+}
+
+// DescribeInstances describes the instances in a stack or layer, or with IDs. It is calling the DescribeInstances API call.
+// See http://docs.aws.amazon.com/opsworks/latest/APIReference/API_DescribeInstances.html for more details.
+func (s *OpsWorksService) DescribeInstances(q InstanceQuery) ([]Instance, error) {
+	result := describeInstancesResult{}
+
+	bodyAsJson, err := json.Marshal(q)
+	if err != nil {
+		return []Instance{}, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "OpsWorks_20130218.DescribeInstances"
+
+	resp, err := req.Do()
+	if err != nil {
+		return []Instance{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return []Instance{}, err
+	}
+	return result.Instances, nil
+}
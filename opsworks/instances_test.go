@@ -0,0 +1,57 @@
+package opsworks
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testInstances = `{"Instances":[{"InstanceId":"4d6d1710-ded9-42a1-b08e-b043ad7af1e2","Ec2InstanceId":"i-bec6b16a","Hostname":"workers1",
+	"StackId":"2f18b4cb-4de5-4429-a149-ff7da9f0d8ee","LayerIds":["97c6b6a4-4b76-4fba-9dd9-05b15e457ab0"],"Status":"online",
+	"InstanceType":"c3.large","PrivateIp":"10.0.1.12","CreatedAt":"2015-10-14T21:20:01+00:00"},
+	{"InstanceId":"9fa1a2bf-1e4b-4a38-8c57-8bd3a2c1f2b1","Hostname":"workers2","Status":"stopped"}]}`
+
+func TestDescribeInstances(t *testing.T) {
+	Convey("Given a stack", t, func() {
+		ts, s, requests := testService(testJson(testInstances))
+		defer ts.Close()
+
+		instances, err := s.DescribeInstances(InstanceQuery{StackId: "2f18b4cb-4de5-4429-a149-ff7da9f0d8ee"})
+
+		Convey("It returns the stack's instances", func() {
+			So(err, ShouldBeNil)
+			So(len(instances), ShouldEqual, 2)
+			So(instances[0].Ec2InstanceId, ShouldEqual, "i-bec6b16a")
+			So(instances[0].LayerIds, ShouldResemble, []string{"97c6b6a4-4b76-4fba-9dd9-05b15e457ab0"})
+			So(instances[0].Status, ShouldEqual, Online)
+			So(instances[0].PrivateIp, ShouldEqual, "10.0.1.12")
+			So(instances[1].Status, ShouldEqual, Stopped)
+		})
+		Convey("It only sends the stack", func() {
+			So(requests()[0].Target, ShouldEqual, "OpsWorks_20130218.DescribeInstances")
+			So(string(requests()[0].Body), ShouldEqual, `{"StackId":"2f18b4cb-4de5-4429-a149-ff7da9f0d8ee"}`)
+		})
+	})
+	Convey("Given instance IDs", t, func() {
+		ts, s, requests := testService(testJson(testInstances))
+		defer ts.Close()
+
+		_, err := s.DescribeInstances(InstanceQuery{InstanceIds: []string{"4d6d1710-ded9-42a1-b08e-b043ad7af1e2"}})
+
+		Convey("It only sends the IDs", func() {
+			So(err, ShouldBeNil)
+			So(string(requests()[0].Body), ShouldEqual, `{"InstanceIds":["4d6d1710-ded9-42a1-b08e-b043ad7af1e2"]}`)
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		instances, err := s.DescribeInstances(InstanceQuery{StackId: "2f18b4cb-4de5-4429-a149-ff7da9f0d8ee"})
+
+		Convey("It returns the error and no instances", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+			So(len(instances), ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,92 @@
+// Package opsworks provides a way to interact with the AWS OpsWorks service.
+package opsworks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type opsWorksError -field Type -service OpsWorks ResourceNotFoundException ValidationException
+
+// opsWorksError is the error document returned from the OpsWorks service.
+type opsWorksError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the opsWorksError into an error message.
+func (e opsWorksError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e opsWorksError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func opsWorksRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := opsWorksError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// OpsWorksService is the OpsWorks service at AWS. One endpoint, gaws.Endpoint("opsworks", "us-east-1"), manages stacks in every region.
+type OpsWorksService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// OpsWorksAPI is implemented by *OpsWorksService. Code that takes an OpsWorksAPI can be given a fake in tests instead.
+type OpsWorksAPI interface {
+	CreateDeployment(input DeploymentInput) (string, error)
+	DescribeInstances(q InstanceQuery) ([]Instance, error)
+	DescribeStacks(stackIds ...string) ([]Stack, error)
+}
+
+var _ OpsWorksAPI = (*OpsWorksService)(nil)
+
+// New returns an OpsWorksService with the options applied. Its endpoint is in us-east-1 whatever the region, since that endpoint manages every stack.
+func New(options ...gaws.Option) *OpsWorksService {
+	config := gaws.NewConfig(options...)
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = gaws.Endpoint("opsworks", "us-east-1")
+	}
+	return &OpsWorksService{Endpoint: endpoint, config: config}
+}
+
+func (s *OpsWorksService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: opsWorksRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("opsworks", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.1",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
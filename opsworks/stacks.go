@@ -0,0 +1,58 @@
+package opsworks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// Stack is an OpsWorks stack, a set of layers of instances and the apps deployed to them.
+type Stack struct {
+	StackId            string
+	Name               string
+	Arn                string
+	Region             string // The region the stack's instances are in
+	VpcId              string
+	DefaultOs          string
+	ServiceRoleArn     string
+	DefaultSubnetId    string
+	HostnameTheme      string
+	CustomJson         string
+	UseCustomCookbooks bool
+	CreatedAt          time.Time
+}
+
+type describeStacksRequest struct {
+	StackIds []string `json:",omitempty"`
+}
+
+type describeStacksResult struct {
+	Stacks []Stack
+}
+
+// DescribeStacks describes the stacks with the IDs. If there are none, it describes every stack. It is calling the DescribeStacks API call.
+// See http://docs.aws.amazon.com/opsworks/latest/APIReference/API_DescribeStacks.html for more details.
+func (s *OpsWorksService) DescribeStacks(stackIds ...string) ([]Stack, error) {
+	result := describeStacksResult{}
+
+	bodyAsJson, err := json.Marshal(describeStacksRequest{StackIds: stackIds})
+	if err != nil {
+		return []Stack{}, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "OpsWorks_20130218.DescribeStacks"
+
+	resp, err := req.Do()
+	if err != nil {
+		return []Stack{}, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	if err != nil {
+		return []Stack{}, err
+	}
+	return result.Stacks, nil
+}
@@ -0,0 +1,66 @@
+package opsworks
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testStacks = `{"Stacks":[{"StackId":"2f18b4cb-4de5-4429-a149-ff7da9f0d8ee","Name":"workers","Region":"us-west-2",
+	"Arn":"arn:aws:opsworks:us-west-2:123456789012:stack/2f18b4cb-4de5-4429-a149-ff7da9f0d8ee/","DefaultOs":"Amazon Linux 2015.09",
+	"UseCustomCookbooks":true,"CreatedAt":"2015-10-14T21:16:32+00:00"}]}`
+
+func TestDescribeStacks(t *testing.T) {
+	Convey("Given a stack ID", t, func() {
+		ts, s, requests := testService(testJson(testStacks))
+		defer ts.Close()
+
+		stacks, err := s.DescribeStacks("2f18b4cb-4de5-4429-a149-ff7da9f0d8ee")
+
+		Convey("It returns the stack", func() {
+			So(err, ShouldBeNil)
+			So(len(stacks), ShouldEqual, 1)
+			So(stacks[0].Name, ShouldEqual, "workers")
+			So(stacks[0].Region, ShouldEqual, "us-west-2")
+			So(stacks[0].UseCustomCookbooks, ShouldBeTrue)
+			So(stacks[0].CreatedAt.Day(), ShouldEqual, 14)
+		})
+		Convey("It asks for the stack", func() {
+			So(requests()[0].Target, ShouldEqual, "OpsWorks_20130218.DescribeStacks")
+			So(string(requests()[0].Body), ShouldEqual, `{"StackIds":["2f18b4cb-4de5-4429-a149-ff7da9f0d8ee"]}`)
+		})
+	})
+	Convey("Given no stack IDs", t, func() {
+		ts, s, requests := testService(testJson(`{"Stacks":[]}`))
+		defer ts.Close()
+
+		stacks, err := s.DescribeStacks()
+
+		Convey("It asks for every stack", func() {
+			So(err, ShouldBeNil)
+			So(len(stacks), ShouldEqual, 0)
+			So(string(requests()[0].Body), ShouldEqual, `{}`)
+		})
+	})
+	Convey("Given a stack that does not exist", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.DescribeStacks("2f18b4cb-4de5-4429-a149-ff7da9f0d8ee")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, resourceNotFoundError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		stacks, err := s.DescribeStacks()
+
+		Convey("It returns an error and no stacks", func() {
+			So(err, ShouldNotBeNil)
+			So(len(stacks), ShouldEqual, 0)
+		})
+	})
+}
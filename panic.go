@@ -0,0 +1,32 @@
+package gaws
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error of a callback that panicked, like a handler of an SQS Consumer, so that one bad message does not crash the
+// program and every worker in it.
+type PanicError struct {
+	Callback string      // What panicked, like "sqs.Consumer handler"
+	Value    interface{} // What it panicked with
+	Stack    []byte      // The stack of the goroutine when it panicked
+}
+
+// Error formats the PanicError into an error message. The stack is left out.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("GawsPanic: %v panicked: %v", e.Callback, e.Value)
+}
+
+// Protect calls f and returns its error. If f panics, it returns a *PanicError instead, and logs it with its stack to Log.
+// Service packages call user callbacks with it. callback names f in the error, like "sqs.Consumer handler".
+func Protect(callback string, f func() error) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			e := &PanicError{Callback: callback, Value: value, Stack: debug.Stack()}
+			logf("gaws: %v\n%s", e, e.Stack)
+			err = e
+		}
+	}()
+	return f()
+}
@@ -0,0 +1,58 @@
+package gaws
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testPanicCollector struct{}
+
+func (testPanicCollector) RequestDone(m RequestMetrics) {
+	panic("collector broke")
+}
+
+func TestProtect(t *testing.T) {
+	Convey("Given a logger", t, func() {
+		b := &bytes.Buffer{}
+		Log = log.New(b, "", 0)
+		defer func() { Log = nil }()
+
+		Convey("A callback's error is returned", func() {
+			err := Protect("test callback", func() error { return errors.New("nope") })
+			So(err.Error(), ShouldEqual, "nope")
+			So(b.Len(), ShouldEqual, 0)
+		})
+		Convey("A callback's panic is returned as an error, and logged with its stack", func() {
+			err := Protect("test callback", func() error { panic("boom") })
+			So(err, ShouldHaveSameTypeAs, &PanicError{})
+			So(err.Error(), ShouldEqual, "GawsPanic: test callback panicked: boom")
+			So(err.(*PanicError).Value, ShouldEqual, "boom")
+			So(string(err.(*PanicError).Stack), ShouldContainSubstring, "TestProtect")
+			So(b.String(), ShouldContainSubstring, "gaws: GawsPanic: test callback panicked: boom\n")
+			So(b.String(), ShouldContainSubstring, "TestProtect")
+		})
+	})
+
+	Convey("Given a metrics collector that panics", t, func() {
+		Metrics = testPanicCollector{}
+		defer func() { Metrics = nil }()
+
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer ts.Close()
+
+		r := canonicalRequest()
+		r.URL = ts.URL
+
+		Convey("Requests still succeed", func() {
+			body, err := r.Do()
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, "OK")
+		})
+	})
+}
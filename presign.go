@@ -0,0 +1,127 @@
+package gaws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// envCredentials reads credentials from the environment, from the same variables awsauth uses.
+func envCredentials() awsauth.Credentials {
+	token := os.Getenv("AWS_SECURITY_TOKEN")
+	if token == "" {
+		token = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	return awsauth.Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SecurityToken:   token,
+	}
+}
+
+// uriEscape escapes s the way signature version 4 expects, with spaces as %20.
+func uriEscape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// canonicalQuery returns the query sorted by name and escaped for signing.
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []string{}
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, uriEscape(name)+"="+uriEscape(v))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Presign signs the URL of a request with signature version 4, so the request can be made by anyone who has the URL, without credentials,
+// until it expires. Only the host header is signed. If no credentials are given, they come from gaws.Credentials, or are read from the environment
+// like awsauth does. See http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html for more details.
+func Presign(req *http.Request, service string, region string, expires time.Duration, credentials ...awsauth.Credentials) *http.Request {
+	keys := envCredentials()
+	if Credentials != nil {
+		if provided, err := Credentials.Credentials(); err == nil && provided.AccessKeyID != "" {
+			keys = provided
+		}
+	}
+	if len(credentials) > 0 {
+		keys = credentials[0]
+	}
+	return presign(req, service, region, expires, time.Now(), keys)
+}
+
+// presign signs the URL of a request as of t.
+func presign(req *http.Request, service string, region string, expires time.Duration, t time.Time, keys awsauth.Credentials) *http.Request {
+	t = t.UTC()
+	date := t.Format("20060102")
+	scope := fmt.Sprintf("%v/%v/%v/aws4_request", date, region, service)
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", keys.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", fmt.Sprint(int64(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if keys.SecurityToken != "" {
+		query.Set("X-Amz-Security-Token", keys.SecurityToken)
+	}
+
+	// S3 does not know the body of a presigned request ahead of time, and other services sign an empty one.
+	payloadHash := "UNSIGNED-PAYLOAD"
+	if service != "s3" {
+		hash := sha256.Sum256(nil)
+		payloadHash = hex.EncodeToString(hash[:])
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(query),
+		"host:" + req.URL.Host + "\n",
+		"host",
+		payloadHash,
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		t.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := signingKeys.key(keys.SecretAccessKey, date, region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.URL.RawQuery = canonicalQuery(query) + "&X-Amz-Signature=" + signature
+	return req
+}
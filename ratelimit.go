@@ -0,0 +1,82 @@
+package gaws
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces something to a rate, like calls or bytes to a service quota, with a token bucket. Tokens are added at Rate a second,
+// up to Burst, and each call or byte takes one. It can owe tokens, for things only counted after they happen, like the bytes a call
+// returned. Service packages have presets of it for their quotas, which their consumers and producers use with WithQuotaLimits.
+// It is safe for concurrent use. Make one with NewRateLimiter, or with Config.NewRateLimiter to wait on the clock of a client.
+type RateLimiter struct {
+	rate  float64
+	burst float64
+	clock Clock // If it is nil, DefaultClock is used
+
+	lock   sync.Mutex // Protects tokens and last
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a full RateLimiter that adds rate tokens a second, and holds up to burst. It waits on DefaultClock.
+func NewRateLimiter(rate float64, burst float64) *RateLimiter {
+	return Config{}.NewRateLimiter(rate, burst)
+}
+
+// NewRateLimiter returns a full RateLimiter like the package's NewRateLimiter, that waits on the config's clock.
+func (c Config) NewRateLimiter(rate float64, burst float64) *RateLimiter {
+	l := &RateLimiter{rate: rate, burst: burst, clock: c.Clock, tokens: burst}
+	l.last = l.currentClock().Now()
+	return l
+}
+
+// currentClock returns the clock the limiter waits on.
+func (l *RateLimiter) currentClock() Clock {
+	if l.clock == nil {
+		return DefaultClock
+	}
+	return l.clock
+}
+
+// take takes n tokens, and returns how long until the limiter no longer owes any.
+func (l *RateLimiter) take(n float64) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.currentClock().Now()
+	if now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+		l.last = now
+	}
+
+	l.tokens -= n
+	if l.tokens >= 0 || l.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// Take takes n tokens without waiting, even if the limiter owes tokens after it. The next Wait waits for them to be paid back.
+func (l *RateLimiter) Take(n float64) {
+	l.take(n)
+}
+
+// Wait takes n tokens, and waits until the limiter no longer owes any. Wait(ctx, 0) waits for tokens owed by Take. It returns early with
+// the error of ctx if it is done, but the tokens are still taken.
+func (l *RateLimiter) Wait(ctx context.Context, n float64) error {
+	wait := l.take(n)
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-l.currentClock().After(wait):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
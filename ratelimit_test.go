@@ -0,0 +1,72 @@
+package gaws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRateLimiter(t *testing.T) {
+	Convey("Given a limiter of five a second", t, func() {
+		clock, restore := useTestClock()
+		defer restore()
+
+		l := NewRateLimiter(5, 5)
+
+		Convey("A burst of five does not wait", func() {
+			for i := 0; i < 5; i++ {
+				So(l.Wait(context.Background(), 1), ShouldBeNil)
+			}
+			So(clock.sleeps, ShouldBeEmpty)
+
+			Convey("The sixth waits for a token", func() {
+				l.Wait(context.Background(), 1)
+				So(clock.sleeps, ShouldResemble, []time.Duration{200 * time.Millisecond})
+			})
+		})
+
+		Convey("Tokens come back with time, up to the burst", func() {
+			l.Wait(context.Background(), 5)
+			clock.now = clock.now.Add(time.Hour)
+			l.Wait(context.Background(), 5)
+			So(clock.sleeps, ShouldBeEmpty)
+		})
+
+		Convey("Tokens that are taken without waiting are waited for later", func() {
+			l.Take(15)
+			So(clock.sleeps, ShouldBeEmpty)
+			l.Wait(context.Background(), 0)
+			So(clock.sleeps, ShouldResemble, []time.Duration{2 * time.Second})
+		})
+	})
+
+	Convey("Given a limiter that owes tokens, and a context that is done", t, func() {
+		l := NewRateLimiter(1, 1)
+		l.Take(10)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		Convey("Waiting returns the context's error", func() {
+			So(l.Wait(ctx, 1), ShouldEqual, context.Canceled)
+		})
+	})
+
+	Convey("Given a limiter of a config with a clock", t, func() {
+		clock := &testClock{now: time.Now()}
+		l := NewConfig(WithClock(clock)).NewRateLimiter(1, 1)
+
+		Convey("It waits on that clock", func() {
+			l.Wait(context.Background(), 2)
+			So(clock.sleeps, ShouldResemble, []time.Duration{time.Second})
+		})
+	})
+
+	Convey("Given a config with quota limits", t, func() {
+		Convey("They are turned on", func() {
+			So(NewConfig(WithQuotaLimits()).QuotaLimits, ShouldBeTrue)
+			So(NewConfig().QuotaLimits, ShouldBeFalse)
+		})
+	})
+}
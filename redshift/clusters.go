@@ -0,0 +1,93 @@
+package redshift
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Endpoint is the address and port to connect to a cluster's database on.
+type Endpoint struct {
+	Address string
+	Port    int
+}
+
+// Cluster is a Redshift cluster.
+type Cluster struct {
+	ClusterIdentifier string
+	ClusterStatus     string // Like creating, available or deleting
+	NodeType          string
+	NumberOfNodes     int
+	DBName            string
+	MasterUsername    string
+	Endpoint          *Endpoint // The endpoint of the cluster. A cluster that is being created does not have one.
+	ClusterCreateTime time.Time
+	AvailabilityZone  string
+	VpcId             string
+}
+
+type describeClustersResponse struct {
+	Clusters []Cluster `xml:"DescribeClustersResult>Clusters>Cluster"`
+	Marker   string    `xml:"DescribeClustersResult>Marker"`
+}
+
+// DescribeClusters describes a cluster. If id is empty, every cluster is described.
+// It is calling the DescribeClusters API call until there are no more pages.
+// See http://docs.aws.amazon.com/redshift/latest/APIReference/API_DescribeClusters.html for more details.
+func (s *RedshiftService) DescribeClusters(id string) ([]Cluster, error) {
+	clusters := []Cluster{}
+
+	params := query("DescribeClusters")
+	if id != "" {
+		params.Set("ClusterIdentifier", id)
+	}
+
+	for {
+		result := describeClustersResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Cluster{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Cluster{}, err
+		}
+
+		clusters = append(clusters, result.Clusters...)
+
+		if result.Marker == "" {
+			return clusters, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
+
+// DescribeCluster describes a cluster.
+func (s *RedshiftService) DescribeCluster(id string) (Cluster, error) {
+	clusters, err := s.DescribeClusters(id)
+	if err != nil {
+		return Cluster{}, err
+	}
+	if len(clusters) == 0 {
+		return Cluster{}, redshiftError{Code: "ClusterNotFound", Message: fmt.Sprintf("Cluster %v not found.", id)}
+	}
+	return clusters[0], nil
+}
+
+// WaitUntilClusterAvailable checks a cluster every WaitInterval until it is available. It returns the cluster, so its endpoint can be read.
+// It gives up after MaxWaits checks.
+func (s *RedshiftService) WaitUntilClusterAvailable(id string) (Cluster, error) {
+	var cluster Cluster
+
+	err := s.wait(func() (bool, error) {
+		var err error
+		cluster, err = s.DescribeCluster(id)
+		return cluster.ClusterStatus == "available", err
+	})
+	return cluster, err
+}
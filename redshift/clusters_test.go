@@ -0,0 +1,159 @@
+package redshift
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testCluster returns a DescribeClusters response for a cluster with the status.
+func testCluster(status string) string {
+	return fmt.Sprintf(`<DescribeClustersResponse xmlns="http://redshift.amazonaws.com/doc/2012-12-01/">
+  <DescribeClustersResult>
+    <Clusters>
+      <Cluster>
+        <ClusterIdentifier>events</ClusterIdentifier>
+        <ClusterStatus>%v</ClusterStatus>
+        <NodeType>dc1.large</NodeType>
+        <NumberOfNodes>2</NumberOfNodes>
+        <DBName>dev</DBName>
+        <MasterUsername>admin</MasterUsername>
+        <Endpoint>
+          <Address>events.cmtkm1tttxbe.us-east-1.redshift.amazonaws.com</Address>
+          <Port>5439</Port>
+        </Endpoint>
+        <ClusterCreateTime>2015-01-22T21:11:40.013Z</ClusterCreateTime>
+        <AvailabilityZone>us-east-1a</AvailabilityZone>
+        <VpcId>vpc-11112222</VpcId>
+      </Cluster>
+    </Clusters>
+  </DescribeClustersResult>
+</DescribeClustersResponse>`, status)
+}
+
+func testClusterStatus(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testCluster(status)))
+	}
+}
+
+func TestDescribeClusters(t *testing.T) {
+	Convey("Given clusters that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<DescribeClustersResponse><DescribeClustersResult><Clusters><Cluster><ClusterIdentifier>events</ClusterIdentifier></Cluster></Clusters><Marker>page2</Marker></DescribeClustersResult></DescribeClustersResponse>`,
+			`<DescribeClustersResponse><DescribeClustersResult><Clusters><Cluster><ClusterIdentifier>archive</ClusterIdentifier></Cluster></Clusters></DescribeClustersResult></DescribeClustersResponse>`,
+		), &params)
+		defer ts.Close()
+
+		clusters, err := s.DescribeClusters("")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(len(clusters), ShouldEqual, 2)
+			So(clusters[1].ClusterIdentifier, ShouldEqual, "archive")
+			So(params[0].Get("Action"), ShouldEqual, "DescribeClusters")
+			So(params[0].Get("Version"), ShouldEqual, "2012-12-01")
+			_, hasId := params[0]["ClusterIdentifier"]
+			So(hasId, ShouldBeFalse)
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeClusters("")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDescribeCluster(t *testing.T) {
+	Convey("Given a cluster", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testClusterStatus("available"), &params)
+		defer ts.Close()
+
+		cluster, err := s.DescribeCluster("events")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(cluster, ShouldResemble, Cluster{
+				ClusterIdentifier: "events",
+				ClusterStatus:     "available",
+				NodeType:          "dc1.large",
+				NumberOfNodes:     2,
+				DBName:            "dev",
+				MasterUsername:    "admin",
+				Endpoint:          &Endpoint{Address: "events.cmtkm1tttxbe.us-east-1.redshift.amazonaws.com", Port: 5439},
+				ClusterCreateTime: time.Date(2015, 1, 22, 21, 11, 40, 13000000, time.UTC),
+				AvailabilityZone:  "us-east-1a",
+				VpcId:             "vpc-11112222",
+			})
+			So(params[0].Get("ClusterIdentifier"), ShouldEqual, "events")
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.DescribeCluster("events")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, clusterNotFoundError)
+		})
+	})
+	Convey("Given no clusters are described", t, func() {
+		ts, s := testService(testPages(`<DescribeClustersResponse/>`, ""), nil)
+		defer ts.Close()
+
+		_, err := s.DescribeCluster("events")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, redshiftError{Code: "ClusterNotFound", Message: "Cluster events not found."})
+		})
+	})
+}
+
+func TestWaitUntilClusterAvailable(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a cluster that is being created", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 3 {
+				testClusterStatus("creating")(w, r)
+			} else {
+				testClusterStatus("available")(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		cluster, err := s.WaitUntilClusterAvailable("events")
+
+		Convey("It waits until the cluster is available", func() {
+			So(err, ShouldBeNil)
+			So(describes, ShouldEqual, 3)
+			So(cluster.Endpoint.Port, ShouldEqual, 5439)
+		})
+	})
+	Convey("Given a cluster that is never available", t, func() {
+		ts, s := testService(testClusterStatus("creating"), nil)
+		defer ts.Close()
+
+		_, err := s.WaitUntilClusterAvailable("events")
+
+		Convey("It gives up", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+}
@@ -0,0 +1,19 @@
+// Code generated by errgen from the go:generate line in redshift.go. DO NOT EDIT.
+
+package redshift
+
+// These are errors Redshift returns. errors.Is(err, redshift.ErrClusterNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrClusterNotFound              = redshiftError{Code: "ClusterNotFound"}
+	ErrClusterAlreadyExists         = redshiftError{Code: "ClusterAlreadyExists"}
+	ErrInvalidClusterState          = redshiftError{Code: "InvalidClusterState"}
+	ErrClusterSnapshotNotFound      = redshiftError{Code: "ClusterSnapshotNotFound"}
+	ErrClusterSnapshotAlreadyExists = redshiftError{Code: "ClusterSnapshotAlreadyExists"}
+	ErrUnauthorizedOperation        = redshiftError{Code: "UnauthorizedOperation"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e redshiftError) Is(target error) bool {
+	t, ok := target.(redshiftError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
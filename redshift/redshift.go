@@ -0,0 +1,110 @@
+// Package redshift provides a way to interact with the AWS Redshift service.
+package redshift
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type redshiftError -field Code -service Redshift ClusterNotFound ClusterAlreadyExists InvalidClusterState ClusterSnapshotNotFound ClusterSnapshotAlreadyExists UnauthorizedOperation
+
+// redshiftError is the error document returned from the Redshift service.
+type redshiftError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the redshiftError into an error message.
+func (e redshiftError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+var waiterTimeoutError = redshiftError{Code: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks.
+var WaitInterval = 30 * time.Second
+
+// MaxWaits is the number of times a waiter checks before giving up. Clusters can take a long time to create, so it is high.
+var MaxWaits = 120
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *RedshiftService) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func redshiftRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := redshiftError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// RedshiftService is the Redshift service at AWS. The endpoint for a region is gaws.Endpoint("redshift", region).
+type RedshiftService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// RedshiftAPI is implemented by *RedshiftService. Code that takes a RedshiftAPI can be given a fake in tests instead.
+type RedshiftAPI interface {
+	CreateClusterSnapshot(clusterId string, snapshotId string) (Snapshot, error)
+	DescribeCluster(id string) (Cluster, error)
+	DescribeClusterSnapshot(snapshotId string) (Snapshot, error)
+	DescribeClusterSnapshots(clusterId string) ([]Snapshot, error)
+	DescribeClusters(id string) ([]Cluster, error)
+	WaitUntilClusterAvailable(id string) (Cluster, error)
+	WaitUntilSnapshotAvailable(snapshotId string) (Snapshot, error)
+}
+
+var _ RedshiftAPI = (*RedshiftService)(nil)
+
+// New returns a RedshiftService with the options applied. Its endpoint is gaws.Endpoint("redshift", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *RedshiftService {
+	config := gaws.NewConfig(options...)
+	return &RedshiftService{Endpoint: config.ServiceEndpoint("redshift"), config: config}
+}
+
+func (s *RedshiftService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: redshiftRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("redshift", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for a Redshift API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2012-12-01"},
+	}
+}
@@ -0,0 +1,122 @@
+package redshift
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Snapshot is a snapshot of a cluster.
+type Snapshot struct {
+	SnapshotIdentifier         string
+	ClusterIdentifier          string
+	SnapshotCreateTime         time.Time
+	Status                     string // Like creating, available or failed
+	SnapshotType               string // manual or automated
+	NodeType                   string
+	NumberOfNodes              int
+	DBName                     string
+	TotalBackupSizeInMegaBytes float64
+}
+
+type createClusterSnapshotResponse struct {
+	Snapshot Snapshot `xml:"CreateClusterSnapshotResult>Snapshot"`
+}
+
+// CreateClusterSnapshot starts taking a manual snapshot of a cluster. Use WaitUntilSnapshotAvailable to know when it is done.
+// It is calling the CreateClusterSnapshot API call.
+// See http://docs.aws.amazon.com/redshift/latest/APIReference/API_CreateClusterSnapshot.html for more details.
+func (s *RedshiftService) CreateClusterSnapshot(clusterId string, snapshotId string) (Snapshot, error) {
+	result := createClusterSnapshotResponse{}
+
+	params := query("CreateClusterSnapshot")
+	params.Set("ClusterIdentifier", clusterId)
+	params.Set("SnapshotIdentifier", snapshotId)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Snapshot, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Snapshot, err
+}
+
+type describeClusterSnapshotsResponse struct {
+	Snapshots []Snapshot `xml:"DescribeClusterSnapshotsResult>Snapshots>Snapshot"`
+	Marker    string     `xml:"DescribeClusterSnapshotsResult>Marker"`
+}
+
+// DescribeClusterSnapshots describes the snapshots of a cluster. If clusterId is empty, the snapshots of every cluster are described.
+// It is calling the DescribeClusterSnapshots API call until there are no more pages.
+// See http://docs.aws.amazon.com/redshift/latest/APIReference/API_DescribeClusterSnapshots.html for more details.
+func (s *RedshiftService) DescribeClusterSnapshots(clusterId string) ([]Snapshot, error) {
+	params := query("DescribeClusterSnapshots")
+	if clusterId != "" {
+		params.Set("ClusterIdentifier", clusterId)
+	}
+	return s.describeClusterSnapshots(params)
+}
+
+// DescribeClusterSnapshot describes a snapshot.
+func (s *RedshiftService) DescribeClusterSnapshot(snapshotId string) (Snapshot, error) {
+	params := query("DescribeClusterSnapshots")
+	params.Set("SnapshotIdentifier", snapshotId)
+
+	snapshots, err := s.describeClusterSnapshots(params)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(snapshots) == 0 {
+		return Snapshot{}, redshiftError{Code: "ClusterSnapshotNotFound", Message: fmt.Sprintf("Snapshot %v not found.", snapshotId)}
+	}
+	return snapshots[0], nil
+}
+
+func (s *RedshiftService) describeClusterSnapshots(params url.Values) ([]Snapshot, error) {
+	snapshots := []Snapshot{}
+
+	for {
+		result := describeClusterSnapshotsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Snapshot{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Snapshot{}, err
+		}
+
+		snapshots = append(snapshots, result.Snapshots...)
+
+		if result.Marker == "" {
+			return snapshots, nil
+		}
+		params.Set("Marker", result.Marker)
+	}
+}
+
+// WaitUntilSnapshotAvailable checks a snapshot every WaitInterval until it is available. It fails if taking the snapshot fails,
+// and gives up after MaxWaits checks.
+func (s *RedshiftService) WaitUntilSnapshotAvailable(snapshotId string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	err := s.wait(func() (bool, error) {
+		var err error
+		snapshot, err = s.DescribeClusterSnapshot(snapshotId)
+		if err == nil && snapshot.Status == "failed" {
+			return false, redshiftError{Code: "GawsSnapshotFailed", Message: fmt.Sprintf("Snapshot %v failed.", snapshotId)}
+		}
+		return snapshot.Status == "available", err
+	})
+	return snapshot, err
+}
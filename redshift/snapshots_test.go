@@ -0,0 +1,156 @@
+package redshift
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testSnapshotMember = `<Snapshot>
+  <SnapshotIdentifier>events-2015-01-23</SnapshotIdentifier>
+  <ClusterIdentifier>events</ClusterIdentifier>
+  <SnapshotCreateTime>2015-01-23T01:09:03.149Z</SnapshotCreateTime>
+  <Status>%v</Status>
+  <SnapshotType>manual</SnapshotType>
+  <NodeType>dc1.large</NodeType>
+  <NumberOfNodes>2</NumberOfNodes>
+  <DBName>dev</DBName>
+  <TotalBackupSizeInMegaBytes>20.5</TotalBackupSizeInMegaBytes>
+</Snapshot>`
+
+// testSnapshotStatus returns a handler that describes a snapshot with the status.
+func testSnapshotStatus(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<DescribeClusterSnapshotsResponse><DescribeClusterSnapshotsResult><Snapshots>` +
+			fmt.Sprintf(testSnapshotMember, status) +
+			`</Snapshots></DescribeClusterSnapshotsResult></DescribeClusterSnapshotsResponse>`))
+	}
+}
+
+var expectedSnapshot = Snapshot{
+	SnapshotIdentifier:         "events-2015-01-23",
+	ClusterIdentifier:          "events",
+	SnapshotCreateTime:         time.Date(2015, 1, 23, 1, 9, 3, 149000000, time.UTC),
+	Status:                     "creating",
+	SnapshotType:               "manual",
+	NodeType:                   "dc1.large",
+	NumberOfNodes:              2,
+	DBName:                     "dev",
+	TotalBackupSizeInMegaBytes: 20.5,
+}
+
+func TestCreateClusterSnapshot(t *testing.T) {
+	Convey("Given a cluster", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<CreateClusterSnapshotResponse><CreateClusterSnapshotResult>`+fmt.Sprintf(testSnapshotMember, "creating")+`</CreateClusterSnapshotResult></CreateClusterSnapshotResponse>`, ""), &params)
+		defer ts.Close()
+
+		snapshot, err := s.CreateClusterSnapshot("events", "events-2015-01-23")
+
+		Convey("It returns the snapshot", func() {
+			So(err, ShouldBeNil)
+			So(snapshot, ShouldResemble, expectedSnapshot)
+		})
+		Convey("It sends the cluster and snapshot", func() {
+			So(params[0].Get("Action"), ShouldEqual, "CreateClusterSnapshot")
+			So(params[0].Get("ClusterIdentifier"), ShouldEqual, "events")
+			So(params[0].Get("SnapshotIdentifier"), ShouldEqual, "events-2015-01-23")
+		})
+	})
+	Convey("Given a cluster that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.CreateClusterSnapshot("events", "events-2015-01-23")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, clusterNotFoundError)
+		})
+	})
+}
+
+func TestDescribeClusterSnapshots(t *testing.T) {
+	Convey("Given snapshots that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<DescribeClusterSnapshotsResponse><DescribeClusterSnapshotsResult><Snapshots>`+fmt.Sprintf(testSnapshotMember, "creating")+`</Snapshots><Marker>page2</Marker></DescribeClusterSnapshotsResult></DescribeClusterSnapshotsResponse>`,
+			`<DescribeClusterSnapshotsResponse><DescribeClusterSnapshotsResult><Snapshots><Snapshot><SnapshotIdentifier>rs:events-2015-01-22</SnapshotIdentifier></Snapshot></Snapshots></DescribeClusterSnapshotsResult></DescribeClusterSnapshotsResponse>`,
+		), &params)
+		defer ts.Close()
+
+		snapshots, err := s.DescribeClusterSnapshots("events")
+
+		Convey("Every page is described", func() {
+			So(err, ShouldBeNil)
+			So(snapshots, ShouldResemble, []Snapshot{expectedSnapshot, {SnapshotIdentifier: "rs:events-2015-01-22"}})
+			So(params[0].Get("ClusterIdentifier"), ShouldEqual, "events")
+			So(params[1].Get("Marker"), ShouldEqual, "page2")
+		})
+	})
+}
+
+func TestDescribeClusterSnapshot(t *testing.T) {
+	Convey("Given a snapshot", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSnapshotStatus("creating"), &params)
+		defer ts.Close()
+
+		snapshot, err := s.DescribeClusterSnapshot("events-2015-01-23")
+
+		Convey("It is described", func() {
+			So(err, ShouldBeNil)
+			So(snapshot, ShouldResemble, expectedSnapshot)
+			So(params[0].Get("SnapshotIdentifier"), ShouldEqual, "events-2015-01-23")
+		})
+	})
+	Convey("Given no snapshots are described", t, func() {
+		ts, s := testService(testPages(`<DescribeClusterSnapshotsResponse/>`, ""), nil)
+		defer ts.Close()
+
+		_, err := s.DescribeClusterSnapshot("events-2015-01-23")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, redshiftError{Code: "ClusterSnapshotNotFound", Message: "Snapshot events-2015-01-23 not found."})
+		})
+	})
+}
+
+func TestWaitUntilSnapshotAvailable(t *testing.T) {
+	defer func(interval time.Duration) { WaitInterval = interval }(WaitInterval)
+	WaitInterval = time.Millisecond
+
+	Convey("Given a snapshot that is being taken", t, func() {
+		describes := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			describes++
+			if describes < 2 {
+				testSnapshotStatus("creating")(w, r)
+			} else {
+				testSnapshotStatus("available")(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		snapshot, err := s.WaitUntilSnapshotAvailable("events-2015-01-23")
+
+		Convey("It waits until the snapshot is available", func() {
+			So(err, ShouldBeNil)
+			So(describes, ShouldEqual, 2)
+			So(snapshot.Status, ShouldEqual, "available")
+		})
+	})
+	Convey("Given a snapshot that fails", t, func() {
+		ts, s := testService(testSnapshotStatus("failed"), nil)
+		defer ts.Close()
+
+		_, err := s.WaitUntilSnapshotAvailable("events-2015-01-23")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, redshiftError{Code: "GawsSnapshotFailed", Message: "Snapshot events-2015-01-23 failed."})
+		})
+	})
+}
@@ -0,0 +1,107 @@
+package gaws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// RefreshRetryDelay is how long a Refresher waits before trying again when getting new credentials fails.
+var RefreshRetryDelay = 30 * time.Second
+
+// RefreshingProvider is a CredentialsProvider whose credentials expire, like an sts.RoleProvider. Refresh gets new credentials now, even
+// if the ones it has not expired, and keeps them for Credentials to return.
+type RefreshingProvider interface {
+	CredentialsProvider
+	Refresh() (awsauth.Credentials, error)
+}
+
+// Refresher is a CredentialsProvider that gets new credentials from a RefreshingProvider in the background, some time before the ones it
+// has expire, so that requests neither wait for them nor fail when getting them fails. Failures are logged to Log and tried again after
+// RefreshRetryDelay. If they keep failing until the credentials expire, Credentials asks the provider, as if there were no Refresher.
+// Make one with StartRefresher, and Stop it when it is not used anymore.
+type Refresher struct {
+	provider RefreshingProvider
+	ahead    time.Duration
+
+	lock        sync.Mutex // Protects credentials
+	credentials awsauth.Credentials
+	stop        chan bool
+	done        chan bool
+}
+
+// StartRefresher returns a Refresher that gets new credentials from the provider ahead of when the ones it has expire, like
+// gaws.Credentials = gaws.StartRefresher(roleProvider, 10*time.Minute). It should be ahead by more than the provider's own expiry window,
+// or the provider gets them on the request path first. If it is ahead by more than half of what is left of the credentials, they are
+// refreshed halfway through instead, and never sooner than RefreshRetryDelay, so short-lived credentials are not refreshed in a loop.
+func StartRefresher(provider RefreshingProvider, ahead time.Duration) *Refresher {
+	if ahead < 0 {
+		ahead = 0
+	}
+	r := &Refresher{provider: provider, ahead: ahead, stop: make(chan bool), done: make(chan bool)}
+	go r.run()
+	return r
+}
+
+// Credentials returns the credentials the Refresher has, or the provider's if they have expired.
+func (r *Refresher) Credentials() (awsauth.Credentials, error) {
+	r.lock.Lock()
+	keys := r.credentials
+	r.lock.Unlock()
+
+	if keys.AccessKeyID != "" && DefaultClock.Now().Before(keys.Expiration) {
+		return keys, nil
+	}
+	return r.provider.Credentials()
+}
+
+// Stop stops refreshing the credentials. Credentials still works, but gets them from the provider once they expire.
+func (r *Refresher) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+// run gets credentials from the provider, and then new ones each time they are about to expire, until the Refresher is stopped.
+func (r *Refresher) run() {
+	defer close(r.done)
+
+	keys, err := r.provider.Credentials()
+	for {
+		wait := RefreshRetryDelay
+		if err == nil {
+			r.lock.Lock()
+			r.credentials = keys
+			r.lock.Unlock()
+
+			if keys.Expiration.IsZero() {
+				// They do not expire, so there is nothing to do.
+				<-r.stop
+				return
+			}
+			wait = refreshWait(keys.Expiration.Sub(DefaultClock.Now()), r.ahead)
+		} else {
+			logf("gaws: refreshing credentials failed: %v", err)
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-DefaultClock.After(wait):
+		}
+		keys, err = r.provider.Refresh()
+	}
+}
+
+// refreshWait returns how long to wait before refreshing credentials that expire after left: ahead of that, but at most halfway, and
+// at least RefreshRetryDelay.
+func refreshWait(left time.Duration, ahead time.Duration) time.Duration {
+	wait := left - ahead
+	if wait < left/2 {
+		wait = left / 2
+	}
+	if wait < RefreshRetryDelay {
+		wait = RefreshRetryDelay
+	}
+	return wait
+}
@@ -0,0 +1,165 @@
+package gaws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testRefreshingProvider hands out credentials that expire an hour after they are got, with a new access key each time.
+// Each Refresh waits to be told to go on, so tests can check on a Refresher between refreshes.
+type testRefreshingProvider struct {
+	lock        sync.Mutex
+	n           int
+	credentials awsauth.Credentials
+	err         error // Returned by Refresh
+
+	started chan bool // Told when a Refresh starts
+	proceed chan bool // Lets a Refresh go on
+	done    chan bool // Closed to stop waiting
+}
+
+func newTestRefreshingProvider(credentials awsauth.Credentials) *testRefreshingProvider {
+	return &testRefreshingProvider{credentials: credentials, started: make(chan bool), proceed: make(chan bool), done: make(chan bool)}
+}
+
+func (p *testRefreshingProvider) Credentials() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.credentials, nil
+}
+
+func (p *testRefreshingProvider) Refresh() (awsauth.Credentials, error) {
+	select {
+	case p.started <- true:
+		select {
+		case <-p.proceed:
+		case <-p.done:
+		}
+	case <-p.done:
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.err != nil {
+		return awsauth.Credentials{}, p.err
+	}
+	p.n++
+	p.credentials = awsauth.Credentials{AccessKeyID: fmt.Sprintf("AKID%d", p.n), Expiration: DefaultClock.Now().Add(time.Hour)}
+	return p.credentials, nil
+}
+
+func TestRefresher(t *testing.T) {
+	Convey("Given a refresher and credentials that expire in an hour", t, func() {
+		clock, restore := useTestClock()
+		defer restore()
+
+		p := newTestRefreshingProvider(awsauth.Credentials{AccessKeyID: "AKID0", Expiration: clock.Now().Add(time.Hour)})
+		r := StartRefresher(p, 10*time.Minute)
+		defer r.Stop()
+		defer close(p.done)
+
+		<-p.started
+		p.proceed <- true
+		<-p.started
+
+		Convey("It gets new ones ten minutes before they expire", func() {
+			So(clock.sleeps[0], ShouldEqual, 50*time.Minute)
+		})
+		Convey("Requests get the new ones without waiting", func() {
+			keys, err := r.Credentials()
+			So(err, ShouldBeNil)
+			So(keys.AccessKeyID, ShouldEqual, "AKID1")
+		})
+	})
+
+	Convey("Given a refresher whose refreshes fail", t, func() {
+		clock, restore := useTestClock()
+		defer restore()
+
+		b := &bytes.Buffer{}
+		Log = log.New(b, "", 0)
+		defer func() { Log = nil }()
+
+		p := newTestRefreshingProvider(awsauth.Credentials{AccessKeyID: "AKID0", Expiration: clock.Now().Add(time.Hour)})
+		p.err = errors.New("nope")
+		r := StartRefresher(p, 10*time.Minute)
+		defer r.Stop()
+		defer close(p.done)
+
+		<-p.started
+		p.proceed <- true
+		<-p.started
+
+		Convey("The failure is logged, and tried again after a delay", func() {
+			So(b.String(), ShouldContainSubstring, "gaws: refreshing credentials failed: nope")
+			So(clock.sleeps[1], ShouldEqual, RefreshRetryDelay)
+		})
+		Convey("Requests get the old credentials until they expire", func() {
+			keys, _ := r.Credentials()
+			So(keys.AccessKeyID, ShouldEqual, "AKID0")
+		})
+		Convey("Requests get them from the provider once they expire", func() {
+			clock.now = clock.now.Add(2 * time.Hour)
+			p.lock.Lock()
+			p.credentials.AccessKeyID = "PROVIDER"
+			p.lock.Unlock()
+			keys, _ := r.Credentials()
+			So(keys.AccessKeyID, ShouldEqual, "PROVIDER")
+		})
+	})
+
+	Convey("Given a refresher ahead by more than the credentials last", t, func() {
+		clock, restore := useTestClock()
+		defer restore()
+
+		p := newTestRefreshingProvider(awsauth.Credentials{AccessKeyID: "AKID0", Expiration: clock.Now().Add(15 * time.Minute)})
+		r := StartRefresher(p, 20*time.Minute)
+		defer r.Stop()
+		defer close(p.done)
+
+		<-p.started
+
+		Convey("It gets new ones halfway through instead of right away", func() {
+			So(clock.sleeps, ShouldResemble, []time.Duration{7*time.Minute + 30*time.Second})
+		})
+	})
+
+	Convey("Given how long credentials are left", t, func() {
+		Convey("Refreshes wait until ahead of when they expire", func() {
+			So(refreshWait(time.Hour, 10*time.Minute), ShouldEqual, 50*time.Minute)
+		})
+		Convey("Refreshes wait at most halfway", func() {
+			So(refreshWait(time.Hour, 2*time.Hour), ShouldEqual, 30*time.Minute)
+		})
+		Convey("Refreshes of credentials about to expire, or expired, wait the retry delay", func() {
+			So(refreshWait(10*time.Second, 10*time.Minute), ShouldEqual, RefreshRetryDelay)
+			So(refreshWait(-time.Minute, 10*time.Minute), ShouldEqual, RefreshRetryDelay)
+		})
+		Convey("A refresher is never ahead by less than nothing", func() {
+			p := newTestRefreshingProvider(awsauth.Credentials{AccessKeyID: "AKID0"})
+			r := StartRefresher(p, -time.Minute)
+			defer r.Stop()
+			So(r.ahead, ShouldEqual, 0)
+		})
+	})
+
+	Convey("Given credentials that do not expire", t, func() {
+		p := newTestRefreshingProvider(awsauth.Credentials{AccessKeyID: "AKID0"})
+		r := StartRefresher(p, 10*time.Minute)
+
+		Convey("They are not refreshed, and the refresher stops", func() {
+			keys, _ := r.Credentials()
+			So(keys.AccessKeyID, ShouldEqual, "AKID0")
+			r.Stop()
+			So(p.n, ShouldEqual, 0)
+		})
+	})
+}
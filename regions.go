@@ -1,4 +1,46 @@
 package gaws
 
+import (
+	"fmt"
+)
+
 // Region is the name of the default region for gaws to use.
 var Region string = "us-east-1"
+
+// AWSRegion is an AWS region and the endpoints of the services in it.
+type AWSRegion struct {
+	Name      string            // The name of the region, like us-east-1
+	Endpoints map[string]string // Endpoints that do not follow the https://service.region.amazonaws.com form, keyed by service
+}
+
+// Endpoint returns the URL of a service's endpoint in the region.
+func (r AWSRegion) Endpoint(service string) string {
+	if endpoint, ok := r.Endpoints[service]; ok {
+		return endpoint
+	}
+	return fmt.Sprintf("https://%v.%v.amazonaws.com", service, r.Name)
+}
+
+// Regions are the AWS regions gaws knows about, keyed by name. Endpoints can be changed here to point gaws at something other than AWS.
+var Regions = map[string]AWSRegion{
+	"us-east-1": AWSRegion{Name: "us-east-1", Endpoints: map[string]string{
+		"s3":  "https://s3.amazonaws.com",
+		"sdb": "https://sdb.amazonaws.com",
+	}},
+	"us-west-1":      AWSRegion{Name: "us-west-1"},
+	"us-west-2":      AWSRegion{Name: "us-west-2"},
+	"eu-west-1":      AWSRegion{Name: "eu-west-1"},
+	"ap-southeast-1": AWSRegion{Name: "ap-southeast-1"},
+	"ap-southeast-2": AWSRegion{Name: "ap-southeast-2"},
+	"ap-northeast-1": AWSRegion{Name: "ap-northeast-1"},
+	"sa-east-1":      AWSRegion{Name: "sa-east-1"},
+}
+
+// Endpoint returns the URL of a service's endpoint in the named region. Regions that are not in Regions use the standard form.
+func Endpoint(service string, region string) string {
+	r, ok := Regions[region]
+	if !ok {
+		r = AWSRegion{Name: region}
+	}
+	return r.Endpoint(service)
+}
@@ -0,0 +1,40 @@
+package gaws
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEndpoint(t *testing.T) {
+	Convey("Given a region without any special endpoints", t, func() {
+		r := Regions["us-west-2"]
+
+		Convey("Endpoint returns the standard endpoint for a service", func() {
+			So(r.Endpoint("kinesis"), ShouldEqual, "https://kinesis.us-west-2.amazonaws.com")
+		})
+	})
+	Convey("Given a region with a special endpoint for a service", t, func() {
+		r := Regions["us-east-1"]
+
+		Convey("Endpoint returns the special endpoint", func() {
+			So(r.Endpoint("s3"), ShouldEqual, "https://s3.amazonaws.com")
+		})
+		Convey("Endpoint returns the standard endpoint for other services", func() {
+			So(r.Endpoint("dynamodb"), ShouldEqual, "https://dynamodb.us-east-1.amazonaws.com")
+		})
+	})
+}
+
+func TestEndpointByName(t *testing.T) {
+	Convey("Given the name of a region gaws knows about", t, func() {
+		Convey("Endpoint uses the region's endpoints", func() {
+			So(Endpoint("s3", "us-east-1"), ShouldEqual, "https://s3.amazonaws.com")
+		})
+	})
+	Convey("Given the name of a region gaws does not know about", t, func() {
+		Convey("Endpoint returns the standard endpoint", func() {
+			So(Endpoint("kinesis", "xx-nowhere-1"), ShouldEqual, "https://kinesis.xx-nowhere-1.amazonaws.com")
+		})
+	})
+}
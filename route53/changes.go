@@ -0,0 +1,136 @@
+package route53
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// The actions a change can take.
+const (
+	Create = "CREATE" // Create a record set, failing if it exists
+	Delete = "DELETE" // Delete a record set, which has to match the one in the zone
+	Upsert = "UPSERT" // Create a record set, or replace it if it exists
+)
+
+// The statuses of a change.
+const (
+	Pending = "PENDING"
+	InSync  = "INSYNC" // The change has reached every Route 53 DNS server
+)
+
+// ResourceRecord is a value of a record set, like an IP address for an A record.
+type ResourceRecord struct {
+	Value string
+}
+
+// AliasTarget is what an alias record set points at, like a load balancer or another record set in the zone.
+type AliasTarget struct {
+	HostedZoneId         string
+	DNSName              string
+	EvaluateTargetHealth bool
+}
+
+// ResourceRecordSet is the records with a name and type.
+type ResourceRecordSet struct {
+	Name            string           // The domain name, like www.example.com.
+	Type            string           // Like A, AAAA, CNAME or TXT
+	SetIdentifier   string           `xml:",omitempty"` // Tells record sets with the same name and type apart, for weighted and latency routing
+	Weight          *int64           `xml:",omitempty"`
+	Region          string           `xml:",omitempty"`
+	TTL             int64            `xml:",omitempty"` // In seconds. Alias record sets do not have one.
+	ResourceRecords []ResourceRecord `xml:"ResourceRecords>ResourceRecord,omitempty"`
+	AliasTarget     *AliasTarget     `xml:",omitempty"`
+	HealthCheckId   string           `xml:",omitempty"`
+}
+
+// MarshalXML leaves ResourceRecords out when there are none, as Route 53 requires for alias record sets.
+func (r ResourceRecordSet) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type resourceRecordSet ResourceRecordSet
+	type resourceRecords struct {
+		ResourceRecords []ResourceRecord `xml:"ResourceRecord"`
+	}
+
+	set := struct {
+		resourceRecordSet
+		ResourceRecords *resourceRecords `xml:",omitempty"`
+		AliasTarget     *AliasTarget     `xml:",omitempty"`
+		HealthCheckId   string           `xml:",omitempty"`
+	}{resourceRecordSet: resourceRecordSet(r), AliasTarget: r.AliasTarget, HealthCheckId: r.HealthCheckId}
+	if len(r.ResourceRecords) > 0 {
+		set.ResourceRecords = &resourceRecords{r.ResourceRecords}
+	}
+	return e.EncodeElement(set, start)
+}
+
+// Change is a change to a record set.
+type Change struct {
+	Action            string // One of Create, Delete or Upsert
+	ResourceRecordSet ResourceRecordSet
+}
+
+type changeResourceRecordSetsRequest struct {
+	XMLName xml.Name `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Comment string   `xml:"ChangeBatch>Comment,omitempty"`
+	Changes []Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+// ChangeInfo is the status of a batch of changes.
+type ChangeInfo struct {
+	Id          string // The ID of the change, like /change/C2682N5HXP0BZ4
+	Status      string // Pending or InSync
+	SubmittedAt time.Time
+	Comment     string
+}
+
+type changeInfoResponse struct {
+	ChangeInfo ChangeInfo
+}
+
+// ChangeResourceRecordSets makes a batch of changes to record sets in a hosted zone. Either every change is made or none are.
+// A batch can have up to 1000 changes. The changes are made in the background, so use WaitForChange to know when they are done.
+// It is calling the ChangeResourceRecordSets API call.
+// See http://docs.aws.amazon.com/Route53/latest/APIReference/API_ChangeResourceRecordSets.html for more details.
+func (s *Route53Service) ChangeResourceRecordSets(zoneId string, comment string, changes ...Change) (ChangeInfo, error) {
+	result := changeInfoResponse{}
+
+	body, err := xml.Marshal(changeResourceRecordSetsRequest{Comment: comment, Changes: changes})
+	if err != nil {
+		return result.ChangeInfo, err
+	}
+
+	req := s.request("POST", "/hostedzone/"+trimId(zoneId)+"/rrset", append([]byte(xml.Header), body...))
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.ChangeInfo, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.ChangeInfo, err
+}
+
+// GetChange gets the status of a batch of changes. It is calling the GetChange API call.
+// See http://docs.aws.amazon.com/Route53/latest/APIReference/API_GetChange.html for more details.
+func (s *Route53Service) GetChange(id string) (ChangeInfo, error) {
+	result := changeInfoResponse{}
+
+	req := s.request("GET", "/change/"+trimId(id), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.ChangeInfo, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.ChangeInfo, err
+}
+
+// WaitForChange checks the status of a batch of changes every WaitInterval until it is InSync. It gives up after MaxWaits checks.
+func (s *Route53Service) WaitForChange(id string) error {
+	return s.wait(func() (bool, error) {
+		change, err := s.GetChange(id)
+		return change.Status == InSync, err
+	})
+}
@@ -0,0 +1,169 @@
+package route53
+
+import (
+	"bytes"
+	"encoding/xml"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testChangeInfo(status string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ChangeResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+  <ChangeInfo>
+    <Id>/change/C2682N5HXP0BZ4</Id>
+    <Status>` + status + `</Status>
+    <SubmittedAt>2015-10-07T18:32:27.142Z</SubmittedAt>
+    <Comment>Move www</Comment>
+  </ChangeInfo>
+</ChangeResourceRecordSetsResponse>`))
+	}
+}
+
+func TestChangeResourceRecordSets(t *testing.T) {
+	Convey("Given changes to a hosted zone", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testChangeInfo(Pending), &requests)
+		defer ts.Close()
+
+		change, err := s.ChangeResourceRecordSets("/hostedzone/Z1D633PJN98FT9", "Move www",
+			Change{Action: Upsert, ResourceRecordSet: ResourceRecordSet{
+				Name:            "www.example.com.",
+				Type:            "A",
+				TTL:             60,
+				ResourceRecords: []ResourceRecord{{Value: "192.0.2.1"}, {Value: "192.0.2.2"}},
+			}},
+			Change{Action: Delete, ResourceRecordSet: ResourceRecordSet{
+				Name:        "old.example.com.",
+				Type:        "A",
+				AliasTarget: &AliasTarget{HostedZoneId: "Z35SXDOTRQ7X7K", DNSName: "elb.example.com."},
+			}},
+		)
+
+		Convey("It returns the change", func() {
+			So(err, ShouldBeNil)
+			So(change, ShouldResemble, ChangeInfo{
+				Id:          "/change/C2682N5HXP0BZ4",
+				Status:      Pending,
+				SubmittedAt: time.Date(2015, 10, 7, 18, 32, 27, 142000000, time.UTC),
+				Comment:     "Move www",
+			})
+		})
+		Convey("It posts to the zone's record sets", func() {
+			So(requests[0].Method, ShouldEqual, "POST")
+			So(requests[0].URI, ShouldEqual, "/2013-04-01/hostedzone/Z1D633PJN98FT9/rrset")
+		})
+		Convey("It sends the batch", func() {
+			body := requests[0].Body
+			So(body, ShouldStartWith, xml.Header)
+			So(body, ShouldContainSubstring, `<ChangeResourceRecordSetsRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">`)
+			So(body, ShouldContainSubstring, "<ChangeBatch><Comment>Move www</Comment><Changes>")
+			So(body, ShouldContainSubstring, "<Change><Action>UPSERT</Action><ResourceRecordSet><Name>www.example.com.</Name><Type>A</Type><TTL>60</TTL>"+
+				"<ResourceRecords><ResourceRecord><Value>192.0.2.1</Value></ResourceRecord><ResourceRecord><Value>192.0.2.2</Value></ResourceRecord></ResourceRecords>"+
+				"</ResourceRecordSet></Change>")
+			So(body, ShouldContainSubstring, "<Change><Action>DELETE</Action><ResourceRecordSet><Name>old.example.com.</Name><Type>A</Type>"+
+				"<AliasTarget><HostedZoneId>Z35SXDOTRQ7X7K</HostedZoneId><DNSName>elb.example.com.</DNSName><EvaluateTargetHealth>false</EvaluateTargetHealth></AliasTarget>"+
+				"</ResourceRecordSet></Change>")
+			So(strings.Contains(body, "<SetIdentifier>"), ShouldBeFalse)
+		})
+	})
+	Convey("Given a hosted zone that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ChangeResourceRecordSets("Z1D633PJN98FT9", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchHostedZoneError)
+		})
+	})
+}
+
+func TestChangeResourceRecordSetsDryRun(t *testing.T) {
+	Convey("Given a client in dry-run mode", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testChangeInfo(Pending), &requests)
+		defer ts.Close()
+		logged := &bytes.Buffer{}
+		s.config.DryRun = gaws.LogDryRunner{Logger: log.New(logged, "", 0)}
+
+		change, err := s.ChangeResourceRecordSets("Z1D633PJN98FT9", "Move www",
+			Change{Action: Upsert, ResourceRecordSet: ResourceRecordSet{Name: "www.example.com.", Type: "A", TTL: 60, ResourceRecords: []ResourceRecord{{Value: "192.0.2.1"}}}},
+		)
+
+		Convey("The changes would be made, and are not", func() {
+			So(err, ShouldBeNil)
+			So(change, ShouldResemble, ChangeInfo{})
+			So(logged.String(), ShouldContainSubstring, "<Name>www.example.com.</Name>")
+			So(requests, ShouldBeEmpty)
+		})
+	})
+}
+
+func TestGetChange(t *testing.T) {
+	Convey("Given a change", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(testChangeInfo(InSync), &requests)
+		defer ts.Close()
+
+		change, err := s.GetChange("/change/C2682N5HXP0BZ4")
+
+		Convey("It returns its status", func() {
+			So(err, ShouldBeNil)
+			So(change.Status, ShouldEqual, InSync)
+			So(requests[0].Method, ShouldEqual, "GET")
+			So(requests[0].URI, ShouldEqual, "/2013-04-01/change/C2682N5HXP0BZ4")
+		})
+	})
+}
+
+func TestWaitForChange(t *testing.T) {
+	WaitInterval = time.Millisecond
+
+	Convey("Given a change that is being made", t, func() {
+		gets := 0
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			gets++
+			if gets < 3 {
+				testChangeInfo(Pending)(w, r)
+			} else {
+				testChangeInfo(InSync)(w, r)
+			}
+		}, nil)
+		defer ts.Close()
+
+		err := s.WaitForChange("/change/C2682N5HXP0BZ4")
+
+		Convey("It waits until it is in sync", func() {
+			So(err, ShouldBeNil)
+			So(gets, ShouldEqual, 3)
+		})
+	})
+	Convey("Given a change that is never made", t, func() {
+		ts, s := testService(testChangeInfo(Pending), nil)
+		defer ts.Close()
+
+		err := s.WaitForChange("C2682N5HXP0BZ4")
+
+		Convey("It gives up", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+	Convey("Given a change that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		err := s.WaitForChange("C2682N5HXP0BZ4")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchHostedZoneError)
+		})
+	})
+}
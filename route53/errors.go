@@ -0,0 +1,20 @@
+// Code generated by errgen from the go:generate line in route53.go. DO NOT EDIT.
+
+package route53
+
+// These are errors Route 53 returns. errors.Is(err, route53.ErrNoSuchHostedZone) is true for any error with the same code, whatever its message.
+var (
+	ErrNoSuchHostedZone        = route53Error{Code: "NoSuchHostedZone"}
+	ErrHostedZoneAlreadyExists = route53Error{Code: "HostedZoneAlreadyExists"}
+	ErrHostedZoneNotEmpty      = route53Error{Code: "HostedZoneNotEmpty"}
+	ErrNoSuchHealthCheck       = route53Error{Code: "NoSuchHealthCheck"}
+	ErrInvalidChangeBatch      = route53Error{Code: "InvalidChangeBatch"}
+	ErrInvalidInput            = route53Error{Code: "InvalidInput"}
+	ErrPriorRequestNotComplete = route53Error{Code: "PriorRequestNotComplete"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e route53Error) Is(target error) bool {
+	t, ok := target.(route53Error)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,115 @@
+// Package route53 provides a way to interact with the AWS Route 53 DNS service.
+package route53
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// apiVersion is the start of the path of every Route 53 API call.
+const apiVersion = "/2013-04-01"
+
+//go:generate go run ../internal/errgen -type route53Error -field Code -service "Route 53" NoSuchHostedZone HostedZoneAlreadyExists HostedZoneNotEmpty NoSuchHealthCheck InvalidChangeBatch InvalidInput PriorRequestNotComplete
+
+// route53Error is the error document returned from the Route 53 service.
+type route53Error struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the route53Error into an error message.
+func (e route53Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+var waiterTimeoutError = route53Error{Code: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks.
+var WaitInterval = 10 * time.Second
+
+// MaxWaits is the number of times a waiter checks before giving up.
+var MaxWaits = 30
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *Route53Service) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func route53RetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := route53Error{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	// PriorRequestNotComplete means an earlier change to the zone is still being made.
+	if error.Code == "Throttling" || error.Code == "PriorRequestNotComplete" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// Route53Service is the Route 53 service at AWS. Route 53 is global, so there is one endpoint, https://route53.amazonaws.com.
+type Route53Service struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// Route53API is implemented by *Route53Service. Code that takes a Route53API can be given a fake in tests instead.
+type Route53API interface {
+	ChangeResourceRecordSets(zoneId string, comment string, changes ...Change) (ChangeInfo, error)
+	GetChange(id string) (ChangeInfo, error)
+	ListHostedZones() ([]HostedZone, error)
+	ListResourceRecordSets(zoneId string) ([]ResourceRecordSet, error)
+	WaitForChange(id string) error
+}
+
+var _ Route53API = (*Route53Service)(nil)
+
+// New returns a Route53Service with the options applied. Route 53 is global, so gaws.WithRegion does not change its endpoint.
+func New(options ...gaws.Option) *Route53Service {
+	config := gaws.NewConfig(options...)
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://route53.amazonaws.com"
+	}
+	return &Route53Service{Endpoint: endpoint, config: config}
+}
+
+// request returns a request to a path under the API version, signed with signature version 4.
+func (s *Route53Service) request(method string, path string, body []byte) gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: route53RetryPredicate,
+		Method:         method,
+		URL:            s.Endpoint + apiVersion + path,
+		Body:           body,
+		Signer:         gaws.Sign4("route53", "us-east-1"),
+	}
+	if len(body) > 0 {
+		r.Headers = map[string]string{"Content-Type": "text/xml"}
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// trimId returns the ID without its prefix, like Z1D633PJN98FT9 for /hostedzone/Z1D633PJN98FT9. Route 53 returns IDs with prefixes, but wants them without.
+func trimId(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
@@ -0,0 +1,120 @@
+package route53
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testNoSuchHostedZoneError = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+  <Error>
+    <Type>Sender</Type>
+    <Code>NoSuchHostedZone</Code>
+    <Message>No hosted zone found with ID: Z1D633PJN98FT9</Message>
+  </Error>
+  <RequestId>ab0a5f7a-6b91-11e5-a0b6-example</RequestId>
+</ErrorResponse>`)
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(404)
+	w.Write(testNoSuchHostedZoneError)
+}
+
+var noSuchHostedZoneError = route53Error{Type: "Sender", Code: "NoSuchHostedZone", Message: "No hosted zone found with ID: Z1D633PJN98FT9"}
+
+// testRequest is a request the server got.
+type testRequest struct {
+	Method string
+	URI    string
+	Body   string
+}
+
+// testService returns a service on a server that runs handler and records every request.
+func testService(handler http.HandlerFunc, requests *[]testRequest) (*httptest.Server, Route53Service) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if requests != nil {
+			*requests = append(*requests, testRequest{Method: r.Method, URI: r.URL.RequestURI(), Body: string(body)})
+		}
+		handler(w, r)
+	}))
+	return ts, Route53Service{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := route53RetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := route53RetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalFailure</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := route53RetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"PriorRequestNotComplete\" code", t, func() {
+		result, _ := route53RetryPredicate(400, []byte("<ErrorResponse><Error><Code>PriorRequestNotComplete</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the zone does not exist", t, func() {
+		result, err := route53RetryPredicate(404, testNoSuchHostedZoneError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, noSuchHostedZoneError)
+		})
+	})
+}
+
+func TestTrimId(t *testing.T) {
+	Convey("Given IDs with and without prefixes", t, func() {
+		Convey("The prefix is removed", func() {
+			So(trimId("/hostedzone/Z1D633PJN98FT9"), ShouldEqual, "Z1D633PJN98FT9")
+			So(trimId("/change/C2682N5HXP0BZ4"), ShouldEqual, "C2682N5HXP0BZ4")
+			So(trimId("Z1D633PJN98FT9"), ShouldEqual, "Z1D633PJN98FT9")
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the global one", func() {
+			So(s.Endpoint, ShouldEqual, "https://route53.amazonaws.com")
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request("GET", "/", nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("Requests are signed with signature version 4", func() {
+			req, _ := http.NewRequest("POST", "http://localhost:4566", nil)
+			s.request("GET", "/", nil).Signer(req, awsauth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "Credential=AKID/")
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/us-east-1/route53/aws4_request")
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
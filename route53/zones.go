@@ -0,0 +1,105 @@
+package route53
+
+import (
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+// HostedZone is a Route 53 hosted zone.
+type HostedZone struct {
+	Id                     string // The ID of the zone, like /hostedzone/Z1D633PJN98FT9
+	Name                   string // The domain name, like example.com.
+	CallerReference        string
+	Comment                string `xml:"Config>Comment"`
+	PrivateZone            bool   `xml:"Config>PrivateZone"`
+	ResourceRecordSetCount int64
+}
+
+type listHostedZonesResponse struct {
+	HostedZones []HostedZone `xml:"HostedZones>HostedZone"`
+	IsTruncated bool
+	NextMarker  string
+}
+
+// ListHostedZones lists every hosted zone. It is calling the ListHostedZones API call until there are no more pages.
+// See http://docs.aws.amazon.com/Route53/latest/APIReference/API_ListHostedZones.html for more details.
+func (s *Route53Service) ListHostedZones() ([]HostedZone, error) {
+	zones := []HostedZone{}
+	query := url.Values{}
+
+	for {
+		result := listHostedZonesResponse{}
+
+		path := "/hostedzone"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		req := s.request("GET", path, nil)
+
+		resp, err := req.Do()
+		if err != nil {
+			return []HostedZone{}, err
+		}
+
+		err = gaws.DecodeXML(resp, &result)
+		if err != nil {
+			return []HostedZone{}, err
+		}
+
+		zones = append(zones, result.HostedZones...)
+
+		if !result.IsTruncated {
+			return zones, nil
+		}
+		query.Set("marker", result.NextMarker)
+	}
+}
+
+type listResourceRecordSetsResponse struct {
+	ResourceRecordSets   []ResourceRecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+	IsTruncated          bool
+	NextRecordName       string
+	NextRecordType       string
+	NextRecordIdentifier string
+}
+
+// ListResourceRecordSets lists every record set in a hosted zone, in order of name and type.
+// It is calling the ListResourceRecordSets API call until there are no more pages.
+// See http://docs.aws.amazon.com/Route53/latest/APIReference/API_ListResourceRecordSets.html for more details.
+func (s *Route53Service) ListResourceRecordSets(zoneId string) ([]ResourceRecordSet, error) {
+	sets := []ResourceRecordSet{}
+	query := url.Values{}
+
+	for {
+		result := listResourceRecordSetsResponse{}
+
+		path := "/hostedzone/" + trimId(zoneId) + "/rrset"
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+		req := s.request("GET", path, nil)
+
+		resp, err := req.Do()
+		if err != nil {
+			return []ResourceRecordSet{}, err
+		}
+
+		err = gaws.DecodeXML(resp, &result)
+		if err != nil {
+			return []ResourceRecordSet{}, err
+		}
+
+		sets = append(sets, result.ResourceRecordSets...)
+
+		if !result.IsTruncated {
+			return sets, nil
+		}
+
+		// The next page starts at the next record set's name, type and, for weighted and latency record sets, identifier.
+		query = url.Values{"name": {result.NextRecordName}, "type": {result.NextRecordType}}
+		if result.NextRecordIdentifier != "" {
+			query.Set("identifier", result.NextRecordIdentifier)
+		}
+	}
+}
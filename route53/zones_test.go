@@ -0,0 +1,124 @@
+package route53
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<ListHostedZonesResponse><HostedZones>"))
+}
+
+func TestListHostedZones(t *testing.T) {
+	Convey("Given hosted zones that take two pages", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("marker") == "Z2682N5HXP0BZ4" {
+				w.Write([]byte(`<ListHostedZonesResponse><HostedZones><HostedZone><Id>/hostedzone/Z2682N5HXP0BZ4</Id><Name>example.net.</Name><Config><PrivateZone>true</PrivateZone></Config></HostedZone></HostedZones><IsTruncated>false</IsTruncated></ListHostedZonesResponse>`))
+				return
+			}
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ListHostedZonesResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+  <HostedZones>
+    <HostedZone>
+      <Id>/hostedzone/Z1D633PJN98FT9</Id>
+      <Name>example.com.</Name>
+      <CallerReference>2015-10-07-18:32</CallerReference>
+      <Config>
+        <Comment>Public zone</Comment>
+        <PrivateZone>false</PrivateZone>
+      </Config>
+      <ResourceRecordSetCount>17</ResourceRecordSetCount>
+    </HostedZone>
+  </HostedZones>
+  <IsTruncated>true</IsTruncated>
+  <NextMarker>Z2682N5HXP0BZ4</NextMarker>
+  <MaxItems>1</MaxItems>
+</ListHostedZonesResponse>`))
+		}, &requests)
+		defer ts.Close()
+
+		zones, err := s.ListHostedZones()
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			So(zones, ShouldResemble, []HostedZone{
+				{Id: "/hostedzone/Z1D633PJN98FT9", Name: "example.com.", CallerReference: "2015-10-07-18:32", Comment: "Public zone", ResourceRecordSetCount: 17},
+				{Id: "/hostedzone/Z2682N5HXP0BZ4", Name: "example.net.", PrivateZone: true},
+			})
+			So(requests[0].URI, ShouldEqual, "/2013-04-01/hostedzone")
+			So(requests[1].URI, ShouldEqual, "/2013-04-01/hostedzone?marker=Z2682N5HXP0BZ4")
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.ListHostedZones()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListResourceRecordSets(t *testing.T) {
+	Convey("Given record sets that take two pages", t, func() {
+		requests := []testRequest{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name") != "" {
+				w.Write([]byte(`<ListResourceRecordSetsResponse><ResourceRecordSets>
+  <ResourceRecordSet><Name>www.example.com.</Name><Type>A</Type><SetIdentifier>blue</SetIdentifier><Weight>10</Weight><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>192.0.2.2</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+</ResourceRecordSets><IsTruncated>false</IsTruncated></ListResourceRecordSetsResponse>`))
+				return
+			}
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ListResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+  <ResourceRecordSets>
+    <ResourceRecordSet>
+      <Name>example.com.</Name>
+      <Type>A</Type>
+      <AliasTarget>
+        <HostedZoneId>Z35SXDOTRQ7X7K</HostedZoneId>
+        <DNSName>elb.example.com.</DNSName>
+        <EvaluateTargetHealth>true</EvaluateTargetHealth>
+      </AliasTarget>
+    </ResourceRecordSet>
+  </ResourceRecordSets>
+  <IsTruncated>true</IsTruncated>
+  <NextRecordName>www.example.com.</NextRecordName>
+  <NextRecordType>A</NextRecordType>
+  <NextRecordIdentifier>blue</NextRecordIdentifier>
+  <MaxItems>1</MaxItems>
+</ListResourceRecordSetsResponse>`))
+		}, &requests)
+		defer ts.Close()
+
+		sets, err := s.ListResourceRecordSets("/hostedzone/Z1D633PJN98FT9")
+
+		Convey("Every page is listed", func() {
+			So(err, ShouldBeNil)
+			weight := int64(10)
+			So(sets, ShouldResemble, []ResourceRecordSet{
+				{Name: "example.com.", Type: "A", AliasTarget: &AliasTarget{HostedZoneId: "Z35SXDOTRQ7X7K", DNSName: "elb.example.com.", EvaluateTargetHealth: true}},
+				{Name: "www.example.com.", Type: "A", SetIdentifier: "blue", Weight: &weight, TTL: 60, ResourceRecords: []ResourceRecord{{Value: "192.0.2.2"}}},
+			})
+		})
+		Convey("The next page starts at the next record set", func() {
+			So(requests[0].URI, ShouldEqual, "/2013-04-01/hostedzone/Z1D633PJN98FT9/rrset")
+			So(requests[1].URI, ShouldEqual, "/2013-04-01/hostedzone/Z1D633PJN98FT9/rrset?identifier=blue&name=www.example.com.&type=A")
+		})
+	})
+	Convey("Given a hosted zone that does not exist", t, func() {
+		ts, s := testService(testHTTP404, nil)
+		defer ts.Close()
+
+		_, err := s.ListResourceRecordSets("Z1D633PJN98FT9")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchHostedZoneError)
+		})
+	})
+}
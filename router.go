@@ -0,0 +1,167 @@
+package gaws
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HealthCheckTimeout is how long an EndpointRouter waits for an endpoint to answer a health check before it is unhealthy.
+var HealthCheckTimeout = 5 * time.Second
+
+// EndpointHealth is what an EndpointRouter knows about one of its endpoints, from its last health check.
+type EndpointHealth struct {
+	Endpoint string        // The endpoint, like https://vpce-1a2b3c4d.kinesis.us-east-1.vpce.amazonaws.com
+	Healthy  bool          // True if the endpoint answered the last health check without a server error
+	Latency  time.Duration // How long the last health check took
+	Err      error         // Why the endpoint is unhealthy, if it is
+	Checked  time.Time     // When the endpoint was last checked
+}
+
+// EndpointMetricsCollector is a MetricsCollector that is also told about the health checks of EndpointRouters. If Metrics is one,
+// EndpointChecked is called after each endpoint is checked.
+type EndpointMetricsCollector interface {
+	MetricsCollector
+	EndpointChecked(h EndpointHealth)
+}
+
+// EndpointRouter sends the requests of a client to the healthiest of several endpoints of a service, like a VPC endpoint and the public
+// one, or the endpoints of two regions. It checks them in the background, and requests go to the healthy endpoint whose last check was
+// quickest, or the first endpoint if none are healthy. Requests to an endpoint of another region are signed for that region.
+// Make one with StartEndpointRouter, give it to clients with WithEndpointRouter, and Stop it when it is not used anymore.
+type EndpointRouter struct {
+	endpoints []string
+	interval  time.Duration
+
+	lock   sync.Mutex // Protects health and best
+	health map[string]EndpointHealth
+	best   string
+	stop   chan bool
+	done   chan bool
+}
+
+// StartEndpointRouter returns an EndpointRouter for endpoints, which checks them every interval. It checks them once before it returns,
+// so the first requests go to the best of them. An endpoint is checked by sending it a GET through Transport, and is healthy if it
+// answers within HealthCheckTimeout with a status below 500. AWS endpoints answer unsigned requests with a 4xx, which is healthy.
+func StartEndpointRouter(endpoints []string, interval time.Duration) *EndpointRouter {
+	r := &EndpointRouter{endpoints: endpoints, interval: interval, health: map[string]EndpointHealth{}, stop: make(chan bool), done: make(chan bool)}
+	if len(endpoints) > 0 {
+		r.best = endpoints[0]
+	}
+	r.checkAll()
+	go r.run()
+	return r
+}
+
+// Endpoint returns the endpoint requests are sent to now.
+func (r *EndpointRouter) Endpoint() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.best
+}
+
+// Health returns what the router knows about each of its endpoints, in the order they were given.
+func (r *EndpointRouter) Health() []EndpointHealth {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	health := make([]EndpointHealth, len(r.endpoints))
+	for i, endpoint := range r.endpoints {
+		health[i] = r.health[endpoint]
+		health[i].Endpoint = endpoint
+	}
+	return health
+}
+
+// Stop stops checking the endpoints. Requests keep going to the endpoint that was best when it stopped.
+func (r *EndpointRouter) Stop() {
+	close(r.stop)
+	<-r.done
+}
+
+// route returns the URL of a request with its scheme and host replaced by those of the best endpoint. The path and query are kept.
+func (r *EndpointRouter) route(requestURL string) string {
+	endpoint, err := url.Parse(r.Endpoint())
+	if err != nil || endpoint.Host == "" {
+		return requestURL
+	}
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return requestURL
+	}
+	u.Scheme = endpoint.Scheme
+	u.Host = endpoint.Host
+	return u.String()
+}
+
+// run checks the endpoints every interval, until the router is stopped.
+func (r *EndpointRouter) run() {
+	defer close(r.done)
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-DefaultClock.After(r.interval):
+		}
+		r.checkAll()
+	}
+}
+
+// checkAll checks every endpoint at once, and picks the best.
+func (r *EndpointRouter) checkAll() {
+	results := make([]EndpointHealth, len(r.endpoints))
+	wg := sync.WaitGroup{}
+	for i, endpoint := range r.endpoints {
+		wg.Add(1)
+		go func(i int, endpoint string) {
+			defer wg.Done()
+			results[i] = checkEndpoint(endpoint)
+		}(i, endpoint)
+	}
+	wg.Wait()
+
+	r.lock.Lock()
+	best := -1
+	for i, h := range results {
+		r.health[h.Endpoint] = h
+		if h.Healthy && (best < 0 || h.Latency < results[best].Latency) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		r.best = results[best].Endpoint
+	} else if len(r.endpoints) > 0 {
+		r.best = r.endpoints[0]
+	}
+	r.lock.Unlock()
+
+	if collector, ok := Metrics.(EndpointMetricsCollector); ok {
+		for _, h := range results {
+			Protect("gaws.Metrics", func() error {
+				collector.EndpointChecked(h)
+				return nil
+			})
+		}
+	}
+}
+
+// checkEndpoint checks the health of an endpoint.
+func checkEndpoint(endpoint string) EndpointHealth {
+	h := EndpointHealth{Endpoint: endpoint, Checked: DefaultClock.Now()}
+
+	client := &http.Client{Transport: Transport, Timeout: HealthCheckTimeout}
+	resp, err := client.Get(endpoint)
+	h.Latency = DefaultClock.Now().Sub(h.Checked)
+	if err != nil {
+		h.Err = err
+		return h
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		h.Err = gawsError{Type: "GawsUnhealthyEndpoint", Message: "The endpoint answered the health check with " + resp.Status + "."}
+		return h
+	}
+	h.Healthy = true
+	return h
+}
@@ -0,0 +1,101 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testEndpointCollector struct {
+	testCollector
+	checks []EndpointHealth
+}
+
+func (c *testEndpointCollector) EndpointChecked(h EndpointHealth) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.checks = append(c.checks, h)
+}
+
+func testHTTP500(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(500)
+}
+
+func TestEndpointRouter(t *testing.T) {
+	Convey("Given a slow endpoint and a quick one", t, func() {
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+			testAWSThrottle(w, r)
+		}))
+		defer slow.Close()
+		quick := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer quick.Close()
+
+		c := &testEndpointCollector{}
+		Metrics = c
+		defer func() { Metrics = nil }()
+
+		router := StartEndpointRouter([]string{slow.URL, quick.URL}, time.Hour)
+		defer router.Stop()
+
+		Convey("Requests go to the quick one", func() {
+			So(router.Endpoint(), ShouldEqual, quick.URL)
+		})
+		Convey("Both are healthy", func() {
+			health := router.Health()
+			So(len(health), ShouldEqual, 2)
+			So(health[0].Endpoint, ShouldEqual, slow.URL)
+			So(health[0].Healthy, ShouldBeTrue)
+			So(health[0].Latency, ShouldBeGreaterThan, health[1].Latency)
+			So(health[1].Healthy, ShouldBeTrue)
+		})
+		Convey("Metrics are told about the checks", func() {
+			So(len(c.checks), ShouldEqual, 2)
+		})
+		Convey("A client's requests go to it, with their paths", func() {
+			r := AWSRequest{URL: "https://s3.amazonaws.com/bucket/key?acl"}
+			NewConfig(WithEndpointRouter(router)).Apply(&r)
+			So(r.URL, ShouldEqual, quick.URL+"/bucket/key?acl")
+		})
+	})
+
+	Convey("Given endpoints that are down or failing, and one that is healthy", t, func() {
+		down := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		down.Close()
+		failing := httptest.NewServer(http.HandlerFunc(testHTTP500))
+		defer failing.Close()
+		healthy := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		defer healthy.Close()
+
+		router := StartEndpointRouter([]string{down.URL, failing.URL, healthy.URL}, time.Hour)
+		defer router.Stop()
+
+		Convey("Requests go to the healthy one", func() {
+			So(router.Endpoint(), ShouldEqual, healthy.URL)
+		})
+		Convey("The others are unhealthy, and say why", func() {
+			health := router.Health()
+			So(health[0].Healthy, ShouldBeFalse)
+			So(health[0].Err, ShouldNotBeNil)
+			So(health[1].Healthy, ShouldBeFalse)
+			So(health[1].Err.Error(), ShouldContainSubstring, "500")
+		})
+	})
+
+	Convey("Given endpoints that are all down", t, func() {
+		down := httptest.NewServer(http.HandlerFunc(testHTTP200))
+		down.Close()
+		failing := httptest.NewServer(http.HandlerFunc(testHTTP500))
+		defer failing.Close()
+
+		router := StartEndpointRouter([]string{down.URL, failing.URL}, time.Hour)
+		defer router.Stop()
+
+		Convey("Requests go to the first", func() {
+			So(router.Endpoint(), ShouldEqual, down.URL)
+		})
+	})
+}
@@ -0,0 +1,150 @@
+package s3
+
+import (
+	"encoding/xml"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+// ACL is the access control list of an object: who owns it, and what others can do with it.
+type ACL struct {
+	OwnerID          string
+	OwnerDisplayName string
+	Grants           []Grant
+}
+
+// Grant gives a permission, like READ or FULL_CONTROL, to a grantee.
+// The grantee is a CanonicalUser with an ID, a Group with a URI, or an AmazonCustomerByEmail with an EmailAddress.
+type Grant struct {
+	GranteeType  string
+	ID           string
+	DisplayName  string
+	URI          string
+	EmailAddress string
+	Permission   string
+}
+
+// AllUsers is the URI of the group of everyone, including anonymous requests.
+const AllUsers = "http://acs.amazonaws.com/groups/global/AllUsers"
+
+// AuthenticatedUsers is the URI of the group of every AWS account.
+const AuthenticatedUsers = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+
+type aclOwner struct {
+	ID          string
+	DisplayName string `xml:",omitempty"`
+}
+
+// aclGrantee is a grantee as S3 sends it. The type is an xsi:type attribute.
+type aclGrantee struct {
+	Type         string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	ID           string
+	DisplayName  string
+	URI          string
+	EmailAddress string
+}
+
+// aclGranteeOut is a grantee as it is sent to S3, with the xsi namespace declared.
+type aclGranteeOut struct {
+	XSI          string `xml:"xmlns:xsi,attr"`
+	Type         string `xml:"xsi:type,attr"`
+	ID           string `xml:",omitempty"`
+	DisplayName  string `xml:",omitempty"`
+	URI          string `xml:",omitempty"`
+	EmailAddress string `xml:",omitempty"`
+}
+
+type accessControlPolicy struct {
+	Owner  aclOwner
+	Grants []struct {
+		Grantee    aclGrantee
+		Permission string
+	} `xml:"AccessControlList>Grant"`
+}
+
+type accessControlPolicyOut struct {
+	XMLName xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ AccessControlPolicy"`
+	Owner   aclOwner
+	Grants  []aclGrantOut `xml:"AccessControlList>Grant"`
+}
+
+type aclGrantOut struct {
+	Grantee    aclGranteeOut
+	Permission string
+}
+
+// aclPath returns the path of the object's ACL.
+func (o *Object) aclPath() string {
+	return o.path(url.Values{"acl": {""}})
+}
+
+// GetACL gets the access control list of the object. It is calling the GetObjectAcl API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectGETacl.html for more details.
+func (o *Object) GetACL() (ACL, error) {
+	result := accessControlPolicy{}
+
+	req := o.Bucket.Service.request("GET", o.aclPath(), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return ACL{}, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	if err != nil {
+		return ACL{}, err
+	}
+
+	acl := ACL{OwnerID: result.Owner.ID, OwnerDisplayName: result.Owner.DisplayName}
+	for _, g := range result.Grants {
+		acl.Grants = append(acl.Grants, Grant{
+			GranteeType:  g.Grantee.Type,
+			ID:           g.Grantee.ID,
+			DisplayName:  g.Grantee.DisplayName,
+			URI:          g.Grantee.URI,
+			EmailAddress: g.Grantee.EmailAddress,
+			Permission:   g.Permission,
+		})
+	}
+	return acl, nil
+}
+
+// PutACL replaces the access control list of the object. It is calling the PutObjectAcl API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPUTacl.html for more details.
+func (o *Object) PutACL(acl ACL) error {
+	policy := accessControlPolicyOut{Owner: aclOwner{ID: acl.OwnerID, DisplayName: acl.OwnerDisplayName}}
+	for _, g := range acl.Grants {
+		policy.Grants = append(policy.Grants, aclGrantOut{
+			Grantee: aclGranteeOut{
+				XSI:          "http://www.w3.org/2001/XMLSchema-instance",
+				Type:         g.GranteeType,
+				ID:           g.ID,
+				DisplayName:  g.DisplayName,
+				URI:          g.URI,
+				EmailAddress: g.EmailAddress,
+			},
+			Permission: g.Permission,
+		})
+	}
+
+	body, err := xml.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	req := o.Bucket.Service.request("PUT", o.aclPath(), body)
+
+	_, err = req.Do()
+	return err
+}
+
+// PutCannedACL replaces the access control list of the object with a canned ACL, like private or public-read. It is calling the PutObjectAcl API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPUTacl.html for more details.
+func (o *Object) PutCannedACL(acl string) error {
+	req := o.Bucket.Service.request("PUT", o.aclPath(), nil)
+	PutOptions{ACL: acl}.headers(req.Headers)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,105 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testACL = `<?xml version="1.0" encoding="UTF-8"?>
+<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Owner>
+    <ID>75aa57f09aa0c8caeab4f8c24e99d10f8e7faeebf76c078efc7c6caea54ba06a</ID>
+    <DisplayName>mtd@amazon.com</DisplayName>
+  </Owner>
+  <AccessControlList>
+    <Grant>
+      <Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="CanonicalUser">
+        <ID>75aa57f09aa0c8caeab4f8c24e99d10f8e7faeebf76c078efc7c6caea54ba06a</ID>
+        <DisplayName>mtd@amazon.com</DisplayName>
+      </Grantee>
+      <Permission>FULL_CONTROL</Permission>
+    </Grant>
+    <Grant>
+      <Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group">
+        <URI>http://acs.amazonaws.com/groups/global/AllUsers</URI>
+      </Grantee>
+      <Permission>READ</Permission>
+    </Grant>
+  </AccessControlList>
+</AccessControlPolicy>`
+
+func TestGetACL(t *testing.T) {
+	Convey("Given an object", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.Write([]byte(testACL))
+		})
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		acl, err := o.GetACL()
+
+		Convey("It returns the owner and grants", func() {
+			So(err, ShouldBeNil)
+			So(request.URL.RawQuery, ShouldEqual, "acl=")
+			So(acl.OwnerDisplayName, ShouldEqual, "mtd@amazon.com")
+			So(len(acl.Grants), ShouldEqual, 2)
+			So(acl.Grants[0].GranteeType, ShouldEqual, "CanonicalUser")
+			So(acl.Grants[0].Permission, ShouldEqual, "FULL_CONTROL")
+			So(acl.Grants[1], ShouldResemble, Grant{GranteeType: "Group", URI: AllUsers, Permission: "READ"})
+		})
+	})
+	Convey("Given an object that does not exist", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+		o := b.Object("missing")
+
+		_, err := o.GetACL()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPutACL(t *testing.T) {
+	Convey("Given an ACL", t, func() {
+		var request *http.Request
+		var body []byte
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			body, _ = ioutil.ReadAll(r.Body)
+		})
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		err := o.PutACL(ACL{OwnerID: "1", Grants: []Grant{{GranteeType: "Group", URI: AllUsers, Permission: "READ"}}})
+
+		Convey("It sends the ACL", func() {
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "PUT")
+			So(request.URL.RawQuery, ShouldEqual, "acl=")
+			So(string(body), ShouldEqual, `<AccessControlPolicy xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Owner><ID>1</ID></Owner><AccessControlList><Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant></AccessControlList></AccessControlPolicy>`)
+		})
+	})
+	Convey("Given a canned ACL", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+		})
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		err := o.PutCannedACL("public-read")
+
+		Convey("It sends the ACL as a header", func() {
+			So(err, ShouldBeNil)
+			So(request.URL.RawQuery, ShouldEqual, "acl=")
+			So(request.Header.Get("X-Amz-Acl"), ShouldEqual, "public-read")
+		})
+	})
+}
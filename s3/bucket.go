@@ -0,0 +1,95 @@
+package s3
+
+import (
+	"encoding/xml"
+	"errors"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// BucketSummary is a bucket returned by ListBuckets.
+type BucketSummary struct {
+	Name         string
+	CreationDate time.Time
+}
+
+type createBucketConfiguration struct {
+	XMLName            xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CreateBucketConfiguration"`
+	LocationConstraint string
+}
+
+type listBucketsResponse struct {
+	Buckets []BucketSummary `xml:"Buckets>Bucket"`
+}
+
+// CreateBucket creates a bucket in the service's region. Outside us-east-1, the region is sent as the bucket's location constraint.
+// It is calling the CreateBucket API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUT.html for more details.
+func (s *S3Service) CreateBucket(name string) (Bucket, error) {
+	b := Bucket{Name: name, Service: s}
+
+	body := []byte{}
+	if region := s.region(); region != "us-east-1" {
+		var err error
+		body, err = xml.Marshal(createBucketConfiguration{LocationConstraint: region})
+		if err != nil {
+			return b, err
+		}
+	}
+
+	req := s.request("PUT", b.path(nil), body)
+
+	_, err := req.Do()
+	return b, err
+}
+
+// ListBuckets lists every bucket the credentials own, in any region. It is calling the ListBuckets API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTServiceGET.html for more details.
+func (s *S3Service) ListBuckets() ([]BucketSummary, error) {
+	result := listBucketsResponse{}
+
+	req := s.request("GET", "/", nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Buckets, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.Buckets, err
+}
+
+// Delete deletes the bucket, which must be empty. It is calling the DeleteBucket API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketDELETE.html for more details.
+func (b *Bucket) Delete() error {
+	req := b.Service.request("DELETE", b.path(nil), nil)
+
+	_, err := req.Do()
+	return err
+}
+
+// Exists returns whether the bucket exists and the credentials can use it. It is calling the HeadBucket API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketHEAD.html for more details.
+func (b *Bucket) Exists() (bool, error) {
+	req := b.Service.request("HEAD", b.path(nil), nil)
+
+	_, err := req.Do()
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// WaitUntilExists checks whether the bucket exists every WaitInterval until it does. It gives up after MaxWaits checks.
+func (b *Bucket) WaitUntilExists() error {
+	return b.Service.wait(b.Exists)
+}
+
+// WaitUntilNotExists checks whether the bucket exists every WaitInterval until it does not. It gives up after MaxWaits checks.
+func (b *Bucket) WaitUntilNotExists() error {
+	return b.Service.wait(func() (bool, error) {
+		exists, err := b.Exists()
+		return !exists, err
+	})
+}
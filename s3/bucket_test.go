@@ -0,0 +1,202 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCreateBucket(t *testing.T) {
+	Convey("Given a service outside us-east-1", t, func() {
+		var method, path, body string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			b, _ := ioutil.ReadAll(r.Body)
+			body = string(b)
+		}))
+		defer ts.Close()
+		s := S3Service{Endpoint: ts.URL, Region: "eu-west-1"}
+
+		b, err := s.CreateBucket("foo")
+
+		Convey("It creates the bucket with a location constraint", func() {
+			So(err, ShouldBeNil)
+			So(b.Name, ShouldEqual, "foo")
+			So(method, ShouldEqual, "PUT")
+			So(path, ShouldEqual, "/foo")
+			So(body, ShouldEqual, `<CreateBucketConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><LocationConstraint>eu-west-1</LocationConstraint></CreateBucketConfiguration>`)
+		})
+	})
+	Convey("Given a service in us-east-1", t, func() {
+		body := "not called"
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := ioutil.ReadAll(r.Body)
+			body = string(b)
+		}))
+		defer ts.Close()
+		s := S3Service{Endpoint: ts.URL, Region: "us-east-1"}
+
+		_, err := s.CreateBucket("foo")
+
+		Convey("It creates the bucket without a location constraint", func() {
+			So(err, ShouldBeNil)
+			So(body, ShouldEqual, "")
+		})
+	})
+	Convey("Given a bucket that is already owned by someone else", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(409)
+			w.Write([]byte("<Error><Code>BucketAlreadyExists</Code><Message>The requested bucket name is not available.</Message></Error>"))
+		}))
+		defer ts.Close()
+		s := S3Service{Endpoint: ts.URL}
+
+		_, err := s.CreateBucket("foo")
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "BucketAlreadyExists")
+		})
+	})
+}
+
+func TestListBuckets(t *testing.T) {
+	Convey("Given an account with buckets", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<ListAllMyBucketsResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/"><Owner><ID>1</ID></Owner><Buckets><Bucket><Name>foo</Name><CreationDate>2006-02-03T16:45:09.000Z</CreationDate></Bucket><Bucket><Name>bar</Name><CreationDate>2006-02-03T16:41:58.000Z</CreationDate></Bucket></Buckets></ListAllMyBucketsResult>`))
+		}))
+		defer ts.Close()
+		s := S3Service{Endpoint: ts.URL}
+
+		buckets, err := s.ListBuckets()
+
+		Convey("It returns the buckets", func() {
+			So(err, ShouldBeNil)
+			So(len(buckets), ShouldEqual, 2)
+			So(buckets[0].Name, ShouldEqual, "foo")
+			So(buckets[1].CreationDate, ShouldResemble, time.Date(2006, 2, 3, 16, 41, 58, 0, time.UTC))
+		})
+	})
+	Convey("Given a service that returns an error", t, func() {
+		ts := httptest.NewServer(http.HandlerFunc(testHTTP404))
+		defer ts.Close()
+		s := S3Service{Endpoint: ts.URL}
+
+		_, err := s.ListBuckets()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeleteBucket(t *testing.T) {
+	Convey("Given a bucket", t, func() {
+		var method string
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			w.WriteHeader(204)
+		})
+		defer ts.Close()
+
+		err := b.Delete()
+
+		Convey("It deletes the bucket", func() {
+			So(err, ShouldBeNil)
+			So(method, ShouldEqual, "DELETE")
+		})
+	})
+}
+
+func TestBucketExists(t *testing.T) {
+	Convey("Given a bucket that exists", t, func() {
+		ts, b := testBucket(testHTTP200)
+		defer ts.Close()
+
+		exists, err := b.Exists()
+
+		Convey("It exists", func() {
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+		})
+	})
+	Convey("Given a bucket that does not exist", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		})
+		defer ts.Close()
+
+		exists, err := b.Exists()
+
+		Convey("It does not exist", func() {
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+	})
+	Convey("Given a bucket that is owned by someone else", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(403)
+		})
+		defer ts.Close()
+
+		_, err := b.Exists()
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "Forbidden")
+		})
+	})
+}
+
+func TestBucketWaiters(t *testing.T) {
+	WaitInterval = time.Millisecond
+
+	Convey("Given a bucket that is being created", t, func() {
+		heads := 0
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			heads++
+			if heads < 3 {
+				w.WriteHeader(404)
+			}
+		})
+		defer ts.Close()
+
+		err := b.WaitUntilExists()
+
+		Convey("It waits until the bucket exists", func() {
+			So(err, ShouldBeNil)
+			So(heads, ShouldEqual, 3)
+		})
+	})
+	Convey("Given a bucket that is being deleted", t, func() {
+		heads := 0
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			heads++
+			if heads >= 2 {
+				w.WriteHeader(404)
+			}
+		})
+		defer ts.Close()
+
+		err := b.WaitUntilNotExists()
+
+		Convey("It waits until the bucket does not exist", func() {
+			So(err, ShouldBeNil)
+			So(heads, ShouldEqual, 2)
+		})
+	})
+	Convey("Given a bucket that never exists", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		})
+		defer ts.Close()
+
+		err := b.WaitUntilExists()
+
+		Convey("It gives up", func() {
+			So(err, ShouldResemble, waiterTimeoutError)
+		})
+	})
+}
@@ -0,0 +1,119 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/controlgroup/gaws"
+)
+
+// MultipartCopyThreshold is the size above which objects are copied in parts. S3 cannot copy an object larger than 5 GB in one request.
+var MultipartCopyThreshold int64 = 5 * 1024 * 1024 * 1024
+
+// CopyPartSize is the size of each part when an object is copied in parts.
+var CopyPartSize int64 = 512 * 1024 * 1024
+
+// CopyOptions say how an object is copied.
+type CopyOptions struct {
+	ReplaceMetadata bool // Use the content type and metadata below instead of the source's
+	PutOptions           // The content type and metadata of the copy, if ReplaceMetadata is true. The ACL is always used.
+}
+
+type copyResponse struct {
+	ETag string
+}
+
+// CopyFrom copies the source object, which can be in another bucket in the same region, to this object without downloading it. It returns the ETag of the copy.
+// The source's content type and metadata are copied, unless options replace them. Objects larger than MultipartCopyThreshold are copied in parts.
+// It is calling the CopyObject API call, or UploadPartCopy for each part.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectCOPY.html for more details.
+func (o *Object) CopyFrom(source *Object, options CopyOptions) (string, error) {
+	info, err := source.Head()
+	if err != nil {
+		return "", err
+	}
+
+	if info.ContentLength > MultipartCopyThreshold {
+		if !options.ReplaceMetadata {
+			options.PutOptions = PutOptions{ACL: options.ACL, ContentType: info.ContentType, Metadata: info.Metadata}
+		}
+		return o.copyParts(source, info, options.PutOptions)
+	}
+
+	result := copyResponse{}
+
+	req := o.Bucket.Service.request("PUT", o.path(nil), nil)
+	req.Headers["X-Amz-Copy-Source"] = source.path(nil)
+	req.Headers["X-Amz-Copy-Source-If-Match"] = info.ETag
+	if options.ReplaceMetadata {
+		req.Headers["X-Amz-Metadata-Directive"] = "REPLACE"
+		options.headers(req.Headers)
+	} else {
+		req.Headers["X-Amz-Metadata-Directive"] = "COPY"
+		PutOptions{ACL: options.ACL}.headers(req.Headers)
+	}
+
+	resp, err := req.Do()
+	if err == nil {
+		err = errorInBody(resp)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.ETag, err
+}
+
+// copyParts copies the source object in parts of CopyPartSize. If a part fails, the upload is aborted.
+func (o *Object) copyParts(source *Object, info ObjectInfo, options PutOptions) (string, error) {
+	upload, err := o.createMultipartUpload(options)
+	if err != nil {
+		return "", err
+	}
+
+	for first := int64(0); first < info.ContentLength; first += CopyPartSize {
+		last := first + CopyPartSize - 1
+		if last >= info.ContentLength {
+			last = info.ContentLength - 1
+		}
+
+		err = upload.copyPart(source, info.ETag, first, last)
+		if err != nil {
+			upload.abort()
+			return "", err
+		}
+	}
+
+	etag, err := upload.complete()
+	if err != nil {
+		upload.abort()
+	}
+	return etag, err
+}
+
+// copyPart copies the bytes of the source object from first to last, inclusive, as the next part of the upload. It is calling the UploadPartCopy API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/mpUploadUploadPartCopy.html for more details.
+func (u *multipartUpload) copyPart(source *Object, etag string, first int64, last int64) error {
+	result := copyResponse{}
+	number := len(u.parts) + 1
+
+	req := u.object.Bucket.Service.request("PUT", u.partPath(number), nil)
+	req.Headers["X-Amz-Copy-Source"] = source.path(nil)
+	req.Headers["X-Amz-Copy-Source-If-Match"] = etag
+	req.Headers["X-Amz-Copy-Source-Range"] = fmt.Sprintf("bytes=%d-%d", first, last)
+
+	resp, err := req.Do()
+	if err == nil {
+		err = errorInBody(resp)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	if err != nil {
+		return err
+	}
+	u.addPart(number, result.ETag)
+	return nil
+}
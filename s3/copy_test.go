@@ -0,0 +1,145 @@
+package s3
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testCopyHandler answers HEAD requests for a source object of size bytes, and records the other requests.
+func testCopyHandler(size string, requests *[]*http.Request) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "HEAD":
+			w.Header().Set("Content-Length", size)
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", `"source"`)
+			w.Header().Set("X-Amz-Meta-Owner", "fox")
+			return
+		case r.Method == "POST" && r.URL.Query().Get("uploadId") == "":
+			w.Write([]byte("<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>"))
+		case r.Method == "POST":
+			w.Write([]byte(`<CompleteMultipartUploadResult><ETag>"copy-3"</ETag></CompleteMultipartUploadResult>`))
+		case r.Method == "PUT":
+			w.Write([]byte(`<CopyObjectResult><LastModified>2009-10-28T22:32:00</LastModified><ETag>"copy"</ETag></CopyObjectResult>`))
+		}
+		*requests = append(*requests, r)
+	}
+}
+
+func TestCopyFrom(t *testing.T) {
+	Convey("Given a small object", t, func() {
+		requests := []*http.Request{}
+		ts, b := testBucket(testCopyHandler("44", &requests))
+		defer ts.Close()
+		source := b.Object("fox 1.txt")
+		o := b.Object("fox2.txt")
+
+		etag, err := o.CopyFrom(&source, CopyOptions{PutOptions: PutOptions{ACL: "bucket-owner-full-control"}})
+
+		Convey("It copies the object in one request", func() {
+			So(err, ShouldBeNil)
+			So(etag, ShouldEqual, `"copy"`)
+			So(len(requests), ShouldEqual, 1)
+			So(requests[0].URL.Path, ShouldEqual, "/foo/fox2.txt")
+			So(requests[0].Header.Get("X-Amz-Copy-Source"), ShouldEqual, "/foo/fox%201.txt")
+			So(requests[0].Header.Get("X-Amz-Copy-Source-If-Match"), ShouldEqual, `"source"`)
+		})
+		Convey("It copies the metadata", func() {
+			So(requests[0].Header.Get("X-Amz-Metadata-Directive"), ShouldEqual, "COPY")
+		})
+		Convey("It sets the ACL of the copy", func() {
+			So(requests[0].Header.Get("X-Amz-Acl"), ShouldEqual, "bucket-owner-full-control")
+		})
+	})
+	Convey("Given new metadata", t, func() {
+		requests := []*http.Request{}
+		ts, b := testBucket(testCopyHandler("44", &requests))
+		defer ts.Close()
+		source := b.Object("fox.txt")
+		o := b.Object("fox2.txt")
+
+		_, err := o.CopyFrom(&source, CopyOptions{ReplaceMetadata: true, PutOptions: PutOptions{ContentType: "text/markdown", Metadata: map[string]string{"Owner": "dog"}}})
+
+		Convey("It replaces the metadata", func() {
+			So(err, ShouldBeNil)
+			So(requests[0].Header.Get("X-Amz-Metadata-Directive"), ShouldEqual, "REPLACE")
+			So(requests[0].Header.Get("Content-Type"), ShouldEqual, "text/markdown")
+			So(requests[0].Header.Get("X-Amz-Meta-Owner"), ShouldEqual, "dog")
+		})
+	})
+	Convey("Given an object larger than the threshold", t, func() {
+		MultipartCopyThreshold, CopyPartSize = 20, 16
+		defer func() { MultipartCopyThreshold, CopyPartSize = 5*1024*1024*1024, 512*1024*1024 }()
+
+		requests := []*http.Request{}
+		ts, b := testBucket(testCopyHandler("44", &requests))
+		defer ts.Close()
+		source := b.Object("fox.txt")
+		o := b.Object("fox2.txt")
+
+		etag, err := o.CopyFrom(&source, CopyOptions{})
+
+		Convey("It copies the object in parts", func() {
+			So(err, ShouldBeNil)
+			So(etag, ShouldEqual, `"copy-3"`)
+			So(len(requests), ShouldEqual, 5)
+			So(requests[1].Header.Get("X-Amz-Copy-Source-Range"), ShouldEqual, "bytes=0-15")
+			So(requests[2].URL.Query().Get("partNumber"), ShouldEqual, "2")
+			So(requests[3].Header.Get("X-Amz-Copy-Source-Range"), ShouldEqual, "bytes=32-43")
+		})
+		Convey("It starts the upload with the source's metadata", func() {
+			So(requests[0].Header.Get("Content-Type"), ShouldEqual, "text/plain")
+			So(requests[0].Header.Get("X-Amz-Meta-Owner"), ShouldEqual, "fox")
+		})
+	})
+	Convey("Given a copy that fails after it started", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "HEAD" {
+				return
+			}
+			w.Write([]byte("<Error><Code>InternalError</Code><Message>We encountered an internal error. Please try again.</Message></Error>"))
+		})
+		defer ts.Close()
+		source := b.Object("fox.txt")
+		o := b.Object("fox2.txt")
+
+		_, err := o.CopyFrom(&source, CopyOptions{})
+
+		Convey("It returns the error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "InternalError")
+		})
+	})
+	Convey("Given a source that does not exist", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		})
+		defer ts.Close()
+		source := b.Object("missing")
+		o := b.Object("fox2.txt")
+
+		_, err := o.CopyFrom(&source, CopyOptions{})
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "NotFound")
+		})
+	})
+}
+
+func TestCopyFromDryRun(t *testing.T) {
+	Convey("Given a bucket of a client in dry-run mode", t, func() {
+		ts, b, logged, requests := testDryRunBucket()
+		defer ts.Close()
+		source := b.Object("source.txt")
+		o := b.Object("copy.txt")
+
+		_, err := o.CopyFrom(&source, CopyOptions{})
+
+		Convey("The copy would be made, and is not", func() {
+			So(err, ShouldBeNil)
+			So(logged.String(), ShouldContainSubstring, "PUT "+ts.URL+"/foo/copy.txt")
+			So(*requests, ShouldEqual, 0)
+		})
+	})
+}
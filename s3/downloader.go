@@ -0,0 +1,305 @@
+package s3
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// DefaultPartSize is the size of the parts a Downloader fetches or an Uploader sends, if its PartSize is 0.
+const DefaultPartSize = 5 * 1024 * 1024
+
+// DefaultConcurrency is how many parts a Downloader fetches or an Uploader sends at once, if its Concurrency is 0.
+const DefaultConcurrency = 5
+
+// Downloader fetches objects in parts, several at a time. The zero value uses the defaults.
+type Downloader struct {
+	PartSize    int64 // How many bytes to fetch in each request
+	Concurrency int   // How many requests to make at once
+}
+
+func (d *Downloader) partSize() int64 {
+	if d.PartSize <= 0 {
+		return DefaultPartSize
+	}
+	return d.PartSize
+}
+
+func (d *Downloader) concurrency() int {
+	if d.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return d.Concurrency
+}
+
+// getPart gets the bytes of the object from first to last, inclusive. If etag is not empty, the object must still have that ETag.
+func (o *Object) getPart(first int64, last int64, etag string) (*http.Response, error) {
+	req := o.Bucket.Service.request("GET", o.path(nil), nil)
+	GetOptions{Range: fmt.Sprintf("bytes=%d-%d", first, last), IfMatch: etag}.headers(req.Headers)
+	return req.DoResponse()
+}
+
+// objectSize returns the size of the whole object from the Content-Range of a partial response, like "bytes 0-9/100".
+func objectSize(resp *http.Response) (int64, error) {
+	if resp.StatusCode != http.StatusPartialContent {
+		return resp.ContentLength, nil
+	}
+
+	contentRange, err := parseContentRange(resp.Header.Get("Content-Range"))
+	if err != nil {
+		return 0, err
+	}
+	return contentRange.Size, nil
+}
+
+// offsetWriter writes to w starting at off.
+type offsetWriter struct {
+	w   io.WriterAt
+	off int64
+}
+
+func (o *offsetWriter) Write(p []byte) (int, error) {
+	n, err := o.w.WriteAt(p, o.off)
+	o.off += int64(n)
+	return n, err
+}
+
+// writePart copies the body of a response to w at off, and closes it.
+func writePart(resp *http.Response, w io.WriterAt, off int64) error {
+	defer resp.Body.Close()
+	_, err := io.Copy(&offsetWriter{w: w, off: off}, resp.Body)
+	return err
+}
+
+// download is the state of one download. done has the parts that are already written, and partDone is called as each one is.
+type download struct {
+	object   *Object
+	w        io.WriterAt
+	partSize int64
+	size     int64
+	etag     string
+	done     map[int64]bool
+	partDone func(part int64) error
+}
+
+// start gets the first part, which says how large the object is and what its ETag is.
+func (dl *download) start() error {
+	resp, err := dl.object.getPart(0, dl.partSize-1, "")
+	if errors.Is(err, ErrInvalidRange) {
+		// The object is empty.
+		dl.size = 0
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dl.size, err = objectSize(resp)
+	if err != nil {
+		resp.Body.Close()
+		return err
+	}
+	dl.etag = resp.Header.Get("ETag")
+
+	whole := resp.StatusCode != http.StatusPartialContent
+	err = writePart(resp, dl.w, 0)
+	if err != nil {
+		return err
+	}
+
+	if whole {
+		// S3 sent the whole object instead of a part.
+		for part := int64(0); part*dl.partSize < dl.size; part++ {
+			dl.done[part] = true
+		}
+	}
+	dl.done[0] = true
+	return dl.partDone(0)
+}
+
+// rest gets the parts that are not done, with workers at a time. It returns the first error.
+func (dl *download) rest(workers int) error {
+	parts := make(chan int64)
+	lock := sync.Mutex{}
+	var firstErr error
+
+	failed := func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return firstErr != nil
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range parts {
+				if failed() {
+					continue
+				}
+
+				err := dl.get(part)
+				if err != nil {
+					lock.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+
+	for part := int64(0); part*dl.partSize < dl.size; part++ {
+		if !dl.done[part] {
+			parts <- part
+		}
+	}
+	close(parts)
+	wg.Wait()
+
+	return firstErr
+}
+
+// get gets one part and writes it.
+func (dl *download) get(part int64) error {
+	first := part * dl.partSize
+	last := first + dl.partSize - 1
+	if last >= dl.size {
+		last = dl.size - 1
+	}
+
+	resp, err := dl.object.getPart(first, last, dl.etag)
+	if err != nil {
+		return err
+	}
+
+	err = writePart(resp, dl.w, first)
+	if err != nil {
+		return err
+	}
+	return dl.partDone(part)
+}
+
+// Download fetches the object into w, in parts. It returns the size of the object.
+// If the object changes while it is being downloaded, it returns an error rather than mixing the old and new contents.
+func (d *Downloader) Download(o *Object, w io.WriterAt) (int64, error) {
+	dl := download{
+		object:   o,
+		w:        w,
+		partSize: d.partSize(),
+		done:     map[int64]bool{},
+		partDone: func(part int64) error { return nil },
+	}
+
+	err := dl.start()
+	if err != nil {
+		return 0, err
+	}
+
+	err = dl.rest(d.concurrency())
+	return dl.size, err
+}
+
+// downloadProgress is kept next to a file while it is downloaded, so a download that fails can be resumed.
+type downloadProgress struct {
+	ETag     string
+	PartSize int64
+	Size     int64
+	Done     []int64 // The parts that have been written
+}
+
+// progressPath returns where the progress of downloading to path is kept.
+func progressPath(path string) string {
+	return path + ".gaws-download"
+}
+
+// readProgress reads the progress of an earlier download to path. If there is none, or it was made with a different part size, it returns false.
+func readProgress(path string, partSize int64) (downloadProgress, bool) {
+	progress := downloadProgress{}
+
+	b, err := ioutil.ReadFile(progressPath(path))
+	if err != nil {
+		return progress, false
+	}
+	err = json.Unmarshal(b, &progress)
+	if err != nil || progress.PartSize != partSize || progress.ETag == "" {
+		return progress, false
+	}
+	return progress, true
+}
+
+// DownloadFile fetches the object into the file at path, in parts. It returns the size of the object.
+// Progress is kept in a file next to it, ending in .gaws-download, until the download is done. If a download to the same path failed,
+// the parts it finished are not fetched again, as long as the object has not changed since.
+func (d *Downloader) DownloadFile(o *Object, path string) (int64, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	progress, resuming := readProgress(path, d.partSize())
+
+	lock := sync.Mutex{}
+	dl := download{
+		object:   o,
+		w:        file,
+		partSize: d.partSize(),
+		done:     map[int64]bool{},
+	}
+	dl.partDone = func(part int64) error {
+		lock.Lock()
+		defer lock.Unlock()
+
+		progress.ETag = dl.etag
+		progress.Size = dl.size
+		progress.Done = append(progress.Done, part)
+		b, err := json.Marshal(progress)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(progressPath(path), b, 0666)
+	}
+
+	if resuming {
+		dl.size = progress.Size
+		dl.etag = progress.ETag
+		for _, part := range progress.Done {
+			dl.done[part] = true
+		}
+	} else {
+		progress = downloadProgress{PartSize: dl.partSize}
+		err = dl.start()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = dl.rest(d.concurrency())
+	if errors.Is(err, ErrPreconditionFailed) && resuming {
+		// The object changed since the download was started, so start again.
+		os.Remove(progressPath(path))
+		return d.DownloadFile(o, path)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = file.Truncate(dl.size)
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Remove(progressPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return dl.size, nil
+}
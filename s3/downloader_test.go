@@ -0,0 +1,161 @@
+package s3
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testContents = []byte("The quick brown fox jumps over the lazy dog.")
+
+// testRangeHandler serves contents with an ETag, honoring ranges and If-Match. It records the Range of every request.
+func testRangeHandler(contents []byte, etag string, ranges *[]string) http.HandlerFunc {
+	lock := sync.Mutex{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		*ranges = append(*ranges, r.Header.Get("Range"))
+		lock.Unlock()
+
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(contents))
+	}
+}
+
+// testWriterAt is an io.WriterAt in memory.
+type testWriterAt struct {
+	lock sync.Mutex
+	b    []byte
+}
+
+func (w *testWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for int64(len(w.b)) < off+int64(len(p)) {
+		w.b = append(w.b, 0)
+	}
+	copy(w.b[off:], p)
+	return len(p), nil
+}
+
+func TestDownload(t *testing.T) {
+	Convey("Given an object larger than a part", t, func() {
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler(testContents, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+		w := &testWriterAt{}
+
+		d := Downloader{PartSize: 10, Concurrency: 3}
+		size, err := d.Download(&o, w)
+
+		Convey("It downloads the whole object", func() {
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(testContents))
+			So(string(w.b), ShouldEqual, string(testContents))
+		})
+		Convey("It fetches one part at a time", func() {
+			So(len(ranges), ShouldEqual, 5)
+			So(ranges[0], ShouldEqual, "bytes=0-9")
+			So(ranges, ShouldContain, "bytes=40-43")
+		})
+	})
+	Convey("Given an empty object", t, func() {
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler([]byte{}, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("empty")
+
+		size, err := (&Downloader{}).Download(&o, &testWriterAt{})
+
+		Convey("It downloads nothing", func() {
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, 0)
+		})
+	})
+	Convey("Given an object that does not exist", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+		o := b.Object("missing")
+
+		_, err := (&Downloader{}).Download(&o, &testWriterAt{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDownloadFile(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "gaws")
+	defer os.RemoveAll(dir)
+
+	Convey("Given a file to download to", t, func() {
+		path := filepath.Join(dir, "fox.txt")
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler(testContents, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		size, err := (&Downloader{PartSize: 10}).DownloadFile(&o, path)
+		contents, _ := ioutil.ReadFile(path)
+		_, statErr := os.Stat(progressPath(path))
+
+		Convey("It downloads the whole object", func() {
+			So(err, ShouldBeNil)
+			So(size, ShouldEqual, len(testContents))
+			So(string(contents), ShouldEqual, string(testContents))
+		})
+		Convey("It removes the progress when it is done", func() {
+			So(os.IsNotExist(statErr), ShouldBeTrue)
+		})
+	})
+	Convey("Given a download that was interrupted", t, func() {
+		path := filepath.Join(dir, "resumed.txt")
+		ioutil.WriteFile(path, testContents[:20], 0666)
+		progress, _ := json.Marshal(downloadProgress{ETag: `"abc"`, PartSize: 10, Size: int64(len(testContents)), Done: []int64{0, 1}})
+		ioutil.WriteFile(progressPath(path), progress, 0666)
+
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler(testContents, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		_, err := (&Downloader{PartSize: 10}).DownloadFile(&o, path)
+		contents, _ := ioutil.ReadFile(path)
+
+		Convey("It only fetches the parts that were not done", func() {
+			So(err, ShouldBeNil)
+			So(len(ranges), ShouldEqual, 3)
+			So(ranges, ShouldNotContain, "bytes=0-9")
+			So(string(contents), ShouldEqual, string(testContents))
+		})
+	})
+	Convey("Given a download that was interrupted before the object changed", t, func() {
+		path := filepath.Join(dir, "changed.txt")
+		ioutil.WriteFile(path, []byte("old contents, not the fox"), 0666)
+		progress, _ := json.Marshal(downloadProgress{ETag: `"old"`, PartSize: 10, Size: int64(len(testContents)), Done: []int64{0, 1}})
+		ioutil.WriteFile(progressPath(path), progress, 0666)
+
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler(testContents, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		_, err := (&Downloader{PartSize: 10}).DownloadFile(&o, path)
+		contents, _ := ioutil.ReadFile(path)
+
+		Convey("It starts again", func() {
+			So(err, ShouldBeNil)
+			So(ranges, ShouldContain, "bytes=0-9")
+			So(string(contents), ShouldEqual, string(testContents))
+		})
+	})
+}
@@ -0,0 +1,27 @@
+// Code generated by errgen from the go:generate line in s3.go. DO NOT EDIT.
+
+package s3
+
+// These are errors S3 returns. errors.Is(err, s3.ErrNoSuchBucket) is true for any error with the same code, whatever its message.
+var (
+	ErrNoSuchBucket                 = s3Error{Code: "NoSuchBucket"}
+	ErrNoSuchKey                    = s3Error{Code: "NoSuchKey"}
+	ErrNoSuchUpload                 = s3Error{Code: "NoSuchUpload"}
+	ErrNotFound                     = s3Error{Code: "NotFound"}
+	ErrAccessDenied                 = s3Error{Code: "AccessDenied"}
+	ErrBucketAlreadyExists          = s3Error{Code: "BucketAlreadyExists"}
+	ErrBucketAlreadyOwnedByYou      = s3Error{Code: "BucketAlreadyOwnedByYou"}
+	ErrBucketNotEmpty               = s3Error{Code: "BucketNotEmpty"}
+	ErrPreconditionFailed           = s3Error{Code: "PreconditionFailed"}
+	ErrInvalidRange                 = s3Error{Code: "InvalidRange"}
+	ErrNoSuchLifecycleConfiguration = s3Error{Code: "NoSuchLifecycleConfiguration"}
+	ErrNoSuchBucketPolicy           = s3Error{Code: "NoSuchBucketPolicy"}
+	ErrEntityTooLarge               = s3Error{Code: "EntityTooLarge"}
+	ErrSlowDown                     = s3Error{Code: "SlowDown"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e s3Error) Is(target error) bool {
+	t, ok := target.(s3Error)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,172 @@
+package s3
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FS is a read-only view of the objects in a bucket under a prefix, as a file system. Slashes in keys separate directories.
+// Opening a file gets the object, and reading a directory lists the keys under it. It implements fs.FS and fs.ReadDirFS.
+type FS struct {
+	bucket *Bucket
+	prefix string
+}
+
+// FS returns a file system of the objects in the bucket whose keys start with the prefix. If the prefix is empty, it has every object.
+func (b *Bucket) FS(prefix string) *FS {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &FS{bucket: b, prefix: prefix}
+}
+
+// dirPrefix returns the prefix of the keys in the directory with the name.
+func (f *FS) dirPrefix(name string) string {
+	if name == "." {
+		return f.prefix
+	}
+	return f.prefix + name + "/"
+}
+
+// Open opens the object with the name under the prefix, or the directory with the name if there is no such object.
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name != "." {
+		o := f.bucket.Object(f.prefix + name)
+		body, info, err := o.Get()
+		if err == nil {
+			return &file{body: body, info: fileInfo{name: path.Base(name), size: info.ContentLength, modTime: info.LastModified}}, nil
+		}
+		if e, ok := err.(s3Error); !ok || e.Code != "NoSuchKey" {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+
+	entries, err := f.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &dir{name: name, info: fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+// ReadDir lists the directory with the name, sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+
+	entries, err := f.readDir(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return entries, nil
+}
+
+// readDir lists the keys in a directory. Keys that cannot be the names of files, like ones with two slashes in a row, are left out.
+// A directory exists if there is any key under it, even if it is only an empty object ending in a slash, like the consoles make.
+func (f *FS) readDir(name string) ([]fs.DirEntry, error) {
+	prefix := f.dirPrefix(name)
+	objects, prefixes, err := f.bucket.ListObjects(ListOptions{Prefix: prefix, Delimiter: "/"})
+	if err != nil {
+		return nil, err
+	}
+	if name != "." && len(objects) == 0 && len(prefixes) == 0 {
+		return nil, fs.ErrNotExist
+	}
+
+	entries := []fs.DirEntry{}
+	for _, p := range prefixes {
+		base := strings.TrimSuffix(strings.TrimPrefix(p, prefix), "/")
+		if base != "" && fs.ValidPath(base) {
+			entries = append(entries, fileInfo{name: base, dir: true})
+		}
+	}
+	for _, o := range objects {
+		base := strings.TrimPrefix(o.Key, prefix)
+		if base != "" && fs.ValidPath(base) {
+			entries = append(entries, fileInfo{name: base, size: o.Size, modTime: o.LastModified})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+// fileInfo describes an object or a directory. It is both an fs.FileInfo and an fs.DirEntry.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) ModTime() time.Time { return i.modTime }
+func (i fileInfo) IsDir() bool        { return i.dir }
+func (i fileInfo) Sys() interface{}   { return nil }
+
+func (i fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (i fileInfo) Type() fs.FileMode {
+	return i.Mode().Type()
+}
+
+func (i fileInfo) Info() (fs.FileInfo, error) {
+	return i, nil
+}
+
+// file is an object opened for reading.
+type file struct {
+	body io.ReadCloser
+	info fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Read(p []byte) (int, error) { return f.body.Read(p) }
+func (f *file) Close() error               { return f.body.Close() }
+
+// dir is a directory opened for reading. It is listed when it is opened.
+type dir struct {
+	name    string
+	info    fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n entries of the directory, or all of the rest if n is 0 or less.
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
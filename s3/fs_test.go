@@ -0,0 +1,132 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testLastModified = time.Date(2015, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// testETag returns the ETag S3 gives an object with the contents.
+func testETag(contents string) string {
+	sum := md5.Sum([]byte(contents))
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+// testFakeBucket acts like a bucket named foo with the objects, for GetObject, HeadObject, PutObject and ListObjectsV2 with a prefix and delimiter.
+func testFakeBucket(objects map[string]string) http.HandlerFunc {
+	lock := sync.Mutex{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		query := r.URL.Query()
+		if r.Method == "PUT" {
+			contents, _ := ioutil.ReadAll(r.Body)
+			objects[strings.TrimPrefix(r.URL.Path, "/foo/")] = string(contents)
+			w.Header().Set("ETag", testETag(string(contents)))
+			return
+		}
+		if query.Get("list-type") != "2" {
+			contents, ok := objects[strings.TrimPrefix(r.URL.Path, "/foo/")]
+			if !ok {
+				testHTTP404(w, r)
+				return
+			}
+			w.Header().Set("ETag", testETag(contents))
+			http.ServeContent(w, r, "", testLastModified, strings.NewReader(contents))
+			return
+		}
+
+		keys := []string{}
+		for key := range objects {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		result := listObjectsResponse{}
+		seen := map[string]bool{}
+		prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			rest := strings.TrimPrefix(key, prefix)
+			if i := strings.Index(rest, delimiter); delimiter != "" && i >= 0 {
+				common := prefix + rest[:i+1]
+				if !seen[common] {
+					seen[common] = true
+					result.CommonPrefixes = append(result.CommonPrefixes, common)
+				}
+				continue
+			}
+			result.Contents = append(result.Contents, ObjectSummary{Key: key, Size: int64(len(objects[key])), LastModified: testLastModified, ETag: testETag(objects[key])})
+		}
+
+		b, _ := xml.Marshal(struct {
+			XMLName xml.Name `xml:"ListBucketResult"`
+			listObjectsResponse
+		}{listObjectsResponse: result})
+		w.Write(b)
+	}
+}
+
+var testBucketObjects = map[string]string{
+	"site/index.html":        "<h1>Hello</h1>",
+	"site/css/main.css":      "h1 { color: red }",
+	"site/images/":           "",
+	"site/images/cat.jpg":    "meow",
+	"site/images/2015/a.jpg": "a",
+	"other/file.txt":         "not in the prefix",
+}
+
+func TestFS(t *testing.T) {
+	Convey("Given a file system over a prefix of a bucket", t, func() {
+		ts, b := testBucket(testFakeBucket(testBucketObjects))
+		defer ts.Close()
+		fsys := b.FS("site")
+
+		Convey("It passes the standard file system tests", func() {
+			So(fstest.TestFS(fsys, "index.html", "css/main.css", "images/cat.jpg", "images/2015/a.jpg"), ShouldBeNil)
+		})
+		Convey("It reads files", func() {
+			contents, err := fs.ReadFile(fsys, "css/main.css")
+			So(err, ShouldBeNil)
+			So(string(contents), ShouldEqual, "h1 { color: red }")
+		})
+		Convey("It lists directories", func() {
+			entries, err := fs.ReadDir(fsys, "images")
+			So(err, ShouldBeNil)
+			So(len(entries), ShouldEqual, 2)
+			So(entries[0].Name(), ShouldEqual, "2015")
+			So(entries[0].IsDir(), ShouldBeTrue)
+			So(entries[1].Name(), ShouldEqual, "cat.jpg")
+
+			info, _ := entries[1].Info()
+			So(info.Size(), ShouldEqual, 4)
+			So(info.ModTime(), ShouldResemble, testLastModified)
+		})
+		Convey("It does not have files that do not exist", func() {
+			_, err := fsys.Open("missing.txt")
+			So(err, ShouldNotBeNil)
+			So(errors.Is(err, fs.ErrNotExist), ShouldBeTrue)
+		})
+		Convey("It does not have files outside the prefix", func() {
+			_, err := fsys.Open("../other/file.txt")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
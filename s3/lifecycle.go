@@ -0,0 +1,102 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// LifecycleRule says what S3 does with the objects under a prefix as they age.
+type LifecycleRule struct {
+	ID                             string                          `xml:",omitempty"`
+	Prefix                         string                          `xml:"Filter>Prefix"` // The rule applies to keys that start with the prefix. If it is empty, it applies to every object.
+	Status                         string                          // Enabled or Disabled
+	Transitions                    []Transition                    `xml:"Transition"`
+	Expiration                     *Expiration                     `xml:",omitempty"`
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:",omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:",omitempty"`
+}
+
+// Transition moves objects to another storage class, like STANDARD_IA or GLACIER, after a number of days or on a date.
+type Transition struct {
+	Days         int        `xml:",omitempty"`
+	Date         *time.Time `xml:",omitempty"`
+	StorageClass string
+}
+
+// Expiration deletes objects after a number of days or on a date.
+type Expiration struct {
+	Days                      int        `xml:",omitempty"`
+	Date                      *time.Time `xml:",omitempty"`
+	ExpiredObjectDeleteMarker bool       `xml:",omitempty"` // In a versioned bucket, delete delete markers that have no versions left behind them
+}
+
+// NoncurrentVersionExpiration deletes old versions of objects, in a versioned bucket, a number of days after they are replaced.
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int
+}
+
+// AbortIncompleteMultipartUpload aborts multipart uploads that are not finished a number of days after they start, so their parts stop being stored.
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int
+}
+
+type lifecycleConfiguration struct {
+	XMLName xml.Name        `xml:"LifecycleConfiguration"`
+	Rules   []LifecycleRule `xml:"Rule"`
+}
+
+// lifecyclePath returns the path of the bucket's lifecycle configuration.
+func (b *Bucket) lifecyclePath() string {
+	return b.path(url.Values{"lifecycle": {""}})
+}
+
+// GetLifecycle gets the rules of the bucket's lifecycle configuration. If the bucket does not have one, it returns no rules.
+// It is calling the GetBucketLifecycleConfiguration API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketGETlifecycle.html for more details.
+func (b *Bucket) GetLifecycle() ([]LifecycleRule, error) {
+	result := lifecycleConfiguration{}
+
+	req := b.Service.request("GET", b.lifecyclePath(), nil)
+
+	resp, err := req.Do()
+	if errors.Is(err, ErrNoSuchLifecycleConfiguration) {
+		return result.Rules, nil
+	}
+	if err != nil {
+		return result.Rules, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.Rules, err
+}
+
+// PutLifecycle replaces the bucket's lifecycle configuration with the rules. It is calling the PutBucketLifecycleConfiguration API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTlifecycle.html for more details.
+func (b *Bucket) PutLifecycle(rules []LifecycleRule) error {
+	body, err := xml.Marshal(lifecycleConfiguration{Rules: rules})
+	if err != nil {
+		return err
+	}
+	checksum := md5.Sum(body)
+
+	req := b.Service.request("PUT", b.lifecyclePath(), body)
+	req.Headers["Content-MD5"] = base64.StdEncoding.EncodeToString(checksum[:])
+
+	_, err = req.Do()
+	return err
+}
+
+// DeleteLifecycle deletes the bucket's lifecycle configuration. It is calling the DeleteBucketLifecycle API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketDELETElifecycle.html for more details.
+func (b *Bucket) DeleteLifecycle() error {
+	req := b.Service.request("DELETE", b.lifecyclePath(), nil)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,110 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testLifecycle = `<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Rule>
+    <ID>logs</ID>
+    <Filter><Prefix>logs/</Prefix></Filter>
+    <Status>Enabled</Status>
+    <Transition><Days>30</Days><StorageClass>STANDARD_IA</StorageClass></Transition>
+    <Transition><Days>90</Days><StorageClass>GLACIER</StorageClass></Transition>
+    <Expiration><Days>365</Days></Expiration>
+  </Rule>
+  <Rule>
+    <ID>uploads</ID>
+    <Filter><Prefix></Prefix></Filter>
+    <Status>Enabled</Status>
+    <AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload>
+  </Rule>
+</LifecycleConfiguration>`
+
+func TestGetLifecycle(t *testing.T) {
+	Convey("Given a bucket with a lifecycle configuration", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.Write([]byte(testLifecycle))
+		})
+		defer ts.Close()
+
+		rules, err := b.GetLifecycle()
+
+		Convey("It returns the rules", func() {
+			So(err, ShouldBeNil)
+			So(request.URL.RawQuery, ShouldEqual, "lifecycle=")
+			So(len(rules), ShouldEqual, 2)
+			So(rules[0].Prefix, ShouldEqual, "logs/")
+			So(rules[0].Transitions, ShouldResemble, []Transition{{Days: 30, StorageClass: "STANDARD_IA"}, {Days: 90, StorageClass: "GLACIER"}})
+			So(rules[0].Expiration, ShouldResemble, &Expiration{Days: 365})
+			So(rules[1].Expiration, ShouldBeNil)
+			So(rules[1].AbortIncompleteMultipartUpload, ShouldResemble, &AbortIncompleteMultipartUpload{DaysAfterInitiation: 7})
+		})
+	})
+	Convey("Given a bucket without a lifecycle configuration", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			w.Write([]byte("<Error><Code>NoSuchLifecycleConfiguration</Code><Message>The lifecycle configuration does not exist</Message></Error>"))
+		})
+		defer ts.Close()
+
+		rules, err := b.GetLifecycle()
+
+		Convey("It returns no rules", func() {
+			So(err, ShouldBeNil)
+			So(len(rules), ShouldEqual, 0)
+		})
+	})
+}
+
+func TestPutLifecycle(t *testing.T) {
+	Convey("Given rules", t, func() {
+		var request *http.Request
+		var body []byte
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			body, _ = ioutil.ReadAll(r.Body)
+		})
+		defer ts.Close()
+
+		err := b.PutLifecycle([]LifecycleRule{
+			{ID: "logs", Prefix: "logs/", Status: "Enabled", Expiration: &Expiration{Days: 365}},
+			{Status: "Enabled", AbortIncompleteMultipartUpload: &AbortIncompleteMultipartUpload{DaysAfterInitiation: 7}},
+		})
+
+		Convey("It sends the configuration with its checksum", func() {
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "PUT")
+			So(request.Header.Get("Content-MD5"), ShouldNotEqual, "")
+			So(string(body), ShouldEqual, "<LifecycleConfiguration>"+
+				"<Rule><ID>logs</ID><Filter><Prefix>logs/</Prefix></Filter><Status>Enabled</Status><Expiration><Days>365</Days></Expiration></Rule>"+
+				"<Rule><Filter><Prefix></Prefix></Filter><Status>Enabled</Status><AbortIncompleteMultipartUpload><DaysAfterInitiation>7</DaysAfterInitiation></AbortIncompleteMultipartUpload></Rule>"+
+				"</LifecycleConfiguration>")
+		})
+	})
+}
+
+func TestDeleteLifecycle(t *testing.T) {
+	Convey("Given a bucket", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.WriteHeader(204)
+		})
+		defer ts.Close()
+
+		err := b.DeleteLifecycle()
+
+		Convey("It deletes the lifecycle configuration", func() {
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "DELETE")
+			So(request.URL.RawQuery, ShouldEqual, "lifecycle=")
+		})
+	})
+}
@@ -0,0 +1,123 @@
+package s3
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// ObjectSummary is an object returned by ListObjects.
+type ObjectSummary struct {
+	ETag         string
+	Key          string
+	LastModified time.Time
+	Size         int64
+	StorageClass string
+}
+
+// ListOptions narrow down which objects are listed. The zero value lists every object in the bucket.
+type ListOptions struct {
+	Delimiter  string // Keys that have the delimiter after the prefix are rolled up into a common prefix. "/" lists a bucket like a directory.
+	MaxKeys    int    // The most objects in each page. If it is 0, S3 returns up to 1000.
+	Prefix     string // Only list keys that start with the prefix
+	StartAfter string // Only list keys after this one
+}
+
+// ListPage is one page of a listing.
+type ListPage struct {
+	CommonPrefixes []string // The prefixes that were rolled up by the delimiter, like the subdirectories of a directory
+	Objects        []ObjectSummary
+}
+
+type listObjectsResponse struct {
+	CommonPrefixes        []string        `xml:"CommonPrefixes>Prefix"`
+	Contents              []ObjectSummary `xml:"Contents"`
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// ObjectPages goes through the pages of a listing, one request at a time. Call Next before each Page.
+type ObjectPages struct {
+	bucket *Bucket
+	query  url.Values
+	page   ListPage
+	done   bool
+	err    error
+}
+
+// ListPages returns the pages of the objects in the bucket. It is calling the ListObjectsV2 API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/v2-RESTBucketGET.html for more details.
+func (b *Bucket) ListPages(options ListOptions) *ObjectPages {
+	query := url.Values{"list-type": {"2"}}
+	if options.Delimiter != "" {
+		query.Set("delimiter", options.Delimiter)
+	}
+	if options.MaxKeys > 0 {
+		query.Set("max-keys", strconv.Itoa(options.MaxKeys))
+	}
+	if options.Prefix != "" {
+		query.Set("prefix", options.Prefix)
+	}
+	if options.StartAfter != "" {
+		query.Set("start-after", options.StartAfter)
+	}
+	return &ObjectPages{bucket: b, query: query}
+}
+
+// Next gets the next page. It returns false when there are no more pages or there was an error, which Err returns.
+func (p *ObjectPages) Next() bool {
+	if p.done {
+		return false
+	}
+
+	result := listObjectsResponse{}
+
+	req := p.bucket.Service.request("GET", p.bucket.path(p.query), nil)
+
+	resp, err := req.Do()
+	if err == nil {
+		err = gaws.DecodeXML(resp, &result)
+	}
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	p.page = ListPage{CommonPrefixes: result.CommonPrefixes, Objects: result.Contents}
+
+	if !result.IsTruncated || result.NextContinuationToken == "" {
+		p.done = true
+	}
+	p.query.Set("continuation-token", result.NextContinuationToken)
+	return true
+}
+
+// Page returns the page Next got.
+func (p *ObjectPages) Page() ListPage {
+	return p.page
+}
+
+// Err returns the error that stopped Next, if there was one.
+func (p *ObjectPages) Err() error {
+	return p.err
+}
+
+// ListObjects lists the objects in the bucket. It keeps asking for pages until there are no more, and returns the objects and common prefixes from all of them.
+func (b *Bucket) ListObjects(options ListOptions) ([]ObjectSummary, []string, error) {
+	objects := []ObjectSummary{}
+	prefixes := []string{}
+
+	pages := b.ListPages(options)
+	for pages.Next() {
+		objects = append(objects, pages.Page().Objects...)
+		prefixes = append(prefixes, pages.Page().CommonPrefixes...)
+	}
+
+	if pages.Err() != nil {
+		return []ObjectSummary{}, []string{}, pages.Err()
+	}
+	return objects, prefixes, nil
+}
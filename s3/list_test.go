@@ -0,0 +1,110 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testListHandler(queries *[]url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Query())
+		if r.URL.Query().Get("continuation-token") == "" {
+			w.Write([]byte(`<ListBucketResult>
+  <Name>foo</Name>
+  <Prefix>photos/</Prefix>
+  <KeyCount>2</KeyCount>
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>next</NextContinuationToken>
+  <Contents>
+    <Key>photos/cat.jpg</Key>
+    <LastModified>2009-10-12T17:50:30.000Z</LastModified>
+    <ETag>"fba9dede5f27731c9771645a39863328"</ETag>
+    <Size>434234</Size>
+    <StorageClass>STANDARD</StorageClass>
+  </Contents>
+  <CommonPrefixes><Prefix>photos/2014/</Prefix></CommonPrefixes>
+</ListBucketResult>`))
+			return
+		}
+		w.Write([]byte(`<ListBucketResult>
+  <IsTruncated>false</IsTruncated>
+  <Contents><Key>photos/dog.jpg</Key><Size>12</Size></Contents>
+  <CommonPrefixes><Prefix>photos/2015/</Prefix></CommonPrefixes>
+</ListBucketResult>`))
+	}
+}
+
+func TestListPages(t *testing.T) {
+	Convey("Given a bucket with objects over two pages", t, func() {
+		queries := []url.Values{}
+		ts, b := testBucket(testListHandler(&queries))
+		defer ts.Close()
+
+		pages := b.ListPages(ListOptions{Prefix: "photos/", Delimiter: "/", MaxKeys: 1})
+		found := []ListPage{}
+		for pages.Next() {
+			found = append(found, pages.Page())
+		}
+
+		Convey("It returns each page", func() {
+			So(pages.Err(), ShouldBeNil)
+			So(len(found), ShouldEqual, 2)
+			So(found[0].Objects[0].Key, ShouldEqual, "photos/cat.jpg")
+			So(found[0].Objects[0].Size, ShouldEqual, 434234)
+			So(found[0].Objects[0].LastModified.Year(), ShouldEqual, 2009)
+			So(found[0].CommonPrefixes, ShouldResemble, []string{"photos/2014/"})
+			So(found[1].Objects[0].Key, ShouldEqual, "photos/dog.jpg")
+		})
+		Convey("It sends the options and the continuation token", func() {
+			So(queries[0].Get("list-type"), ShouldEqual, "2")
+			So(queries[0].Get("prefix"), ShouldEqual, "photos/")
+			So(queries[0].Get("delimiter"), ShouldEqual, "/")
+			So(queries[0].Get("max-keys"), ShouldEqual, "1")
+			So(queries[1].Get("continuation-token"), ShouldEqual, "next")
+		})
+		Convey("Next keeps returning false after the last page", func() {
+			So(pages.Next(), ShouldBeFalse)
+		})
+	})
+	Convey("Given a bucket that returns errors", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+
+		pages := b.ListPages(ListOptions{})
+
+		Convey("Next returns false and Err returns the error", func() {
+			So(pages.Next(), ShouldBeFalse)
+			So(pages.Err(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListObjects(t *testing.T) {
+	Convey("Given a bucket with objects over two pages", t, func() {
+		queries := []url.Values{}
+		ts, b := testBucket(testListHandler(&queries))
+		defer ts.Close()
+
+		objects, prefixes, err := b.ListObjects(ListOptions{Delimiter: "/"})
+
+		Convey("It returns the objects and prefixes from every page", func() {
+			So(err, ShouldBeNil)
+			So(len(objects), ShouldEqual, 2)
+			So(prefixes, ShouldResemble, []string{"photos/2014/", "photos/2015/"})
+		})
+	})
+	Convey("Given a bucket that returns errors", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+
+		objects, _, err := b.ListObjects(ListOptions{})
+
+		Convey("It returns an error and no objects", func() {
+			So(err, ShouldNotBeNil)
+			So(objects, ShouldResemble, []ObjectSummary{})
+		})
+	})
+}
@@ -0,0 +1,138 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/controlgroup/gaws"
+)
+
+// multipartUpload is an object being uploaded in parts. Each part is uploaded or copied separately, and then they are put together.
+type multipartUpload struct {
+	object *Object
+	id     string
+	lock   sync.Mutex
+	parts  []completedPart
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+type createMultipartUploadResponse struct {
+	UploadId string
+}
+
+type completeMultipartUpload struct {
+	XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+	Parts   []completedPart `xml:"Part"`
+}
+
+type completeMultipartUploadResponse struct {
+	ETag string
+}
+
+// errorInBody returns the error in a response that succeeded, if there is one. S3 can fail a long request after it has sent the status.
+func errorInBody(body []byte) error {
+	root := struct {
+		XMLName xml.Name
+	}{}
+	err := xml.Unmarshal(body, &root)
+	if err != nil || root.XMLName.Local != "Error" {
+		return nil
+	}
+
+	e := s3Error{}
+	xml.Unmarshal(body, &e)
+	return e
+}
+
+// createMultipartUpload starts uploading the object in parts. It is calling the CreateMultipartUpload API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/mpUploadInitiate.html for more details.
+func (o *Object) createMultipartUpload(options PutOptions) (*multipartUpload, error) {
+	result := createMultipartUploadResponse{}
+
+	req := o.Bucket.Service.request("POST", o.path(url.Values{"uploads": {""}}), nil)
+	options.headers(req.Headers)
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &multipartUpload{object: o, id: result.UploadId}, nil
+}
+
+// partPath returns the path of a part of the upload.
+func (u *multipartUpload) partPath(number int) string {
+	return u.object.path(url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {u.id}})
+}
+
+// addPart records a part that was uploaded. Parts can be added in any order.
+func (u *multipartUpload) addPart(number int, etag string) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.parts = append(u.parts, completedPart{PartNumber: number, ETag: etag})
+}
+
+// uploadPart uploads the contents as the part with the number. It is calling the UploadPart API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/mpUploadUploadPart.html for more details.
+func (u *multipartUpload) uploadPart(number int, contents []byte) error {
+	checksum := md5.Sum(contents)
+
+	req := u.object.Bucket.Service.request("PUT", u.partPath(number), contents)
+	req.Headers["Content-MD5"] = base64.StdEncoding.EncodeToString(checksum[:])
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	u.addPart(number, resp.Header.Get("ETag"))
+	return nil
+}
+
+// complete puts the parts together into the object. It returns the ETag of the object. It is calling the CompleteMultipartUpload API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/mpUploadComplete.html for more details.
+func (u *multipartUpload) complete() (string, error) {
+	result := completeMultipartUploadResponse{}
+
+	sort.Slice(u.parts, func(i, j int) bool { return u.parts[i].PartNumber < u.parts[j].PartNumber })
+	body, err := xml.Marshal(completeMultipartUpload{Parts: u.parts})
+	if err != nil {
+		return "", err
+	}
+
+	req := u.object.Bucket.Service.request("POST", u.object.path(url.Values{"uploadId": {u.id}}), body)
+
+	resp, err := req.Do()
+	if err == nil {
+		err = errorInBody(resp)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result.ETag, err
+}
+
+// abort stops the upload and deletes the parts that were uploaded. It is calling the AbortMultipartUpload API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/mpUploadAbort.html for more details.
+func (u *multipartUpload) abort() error {
+	req := u.object.Bucket.Service.request("DELETE", u.object.path(url.Values{"uploadId": {u.id}}), nil)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,91 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestErrorInBody(t *testing.T) {
+	Convey("Given a successful response with an error in it", t, func() {
+		err := errorInBody([]byte("<Error><Code>InternalError</Code><Message>We encountered an internal error. Please try again.</Message></Error>"))
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, s3Error{Code: "InternalError", Message: "We encountered an internal error. Please try again."})
+		})
+	})
+	Convey("Given a successful response", t, func() {
+		err := errorInBody([]byte("<CopyObjectResult><ETag>\"abc\"</ETag></CopyObjectResult>"))
+
+		Convey("It returns nil", func() {
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestMultipartUpload(t *testing.T) {
+	Convey("Given an upload with parts", t, func() {
+		requests := []*http.Request{}
+		bodies := []string{}
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+			switch {
+			case r.Method == "POST" && r.URL.Query().Get("uploadId") == "":
+				w.Write([]byte("<InitiateMultipartUploadResult><Bucket>foo</Bucket><Key>bar</Key><UploadId>1</UploadId></InitiateMultipartUploadResult>"))
+			case r.Method == "POST":
+				w.Write([]byte(`<CompleteMultipartUploadResult><ETag>"abc-2"</ETag></CompleteMultipartUploadResult>`))
+			}
+		})
+		defer ts.Close()
+		o := b.Object("bar")
+
+		upload, err := o.createMultipartUpload(PutOptions{ContentType: "text/plain"})
+		upload.parts = []completedPart{{PartNumber: 1, ETag: `"a"`}, {PartNumber: 2, ETag: `"b"`}}
+		etag, completeErr := upload.complete()
+
+		Convey("It starts the upload with the options", func() {
+			So(err, ShouldBeNil)
+			So(upload.id, ShouldEqual, "1")
+			So(requests[0].URL.RawQuery, ShouldEqual, "uploads=")
+			So(requests[0].Header.Get("Content-Type"), ShouldEqual, "text/plain")
+		})
+		Convey("It completes the upload with the parts", func() {
+			So(completeErr, ShouldBeNil)
+			So(etag, ShouldEqual, `"abc-2"`)
+			So(requests[1].URL.Query().Get("uploadId"), ShouldEqual, "1")
+			So(bodies[1], ShouldEqual, `<CompleteMultipartUpload><Part><PartNumber>1</PartNumber><ETag>&#34;a&#34;</ETag></Part><Part><PartNumber>2</PartNumber><ETag>&#34;b&#34;</ETag></Part></CompleteMultipartUpload>`)
+		})
+		Convey("Parts have their number and the upload in the path", func() {
+			So(upload.partPath(3), ShouldEqual, "/foo/bar?partNumber=3&uploadId=1")
+		})
+	})
+	Convey("Given an upload that fails to complete", t, func() {
+		var aborted string
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "DELETE" {
+				aborted = r.URL.Query().Get("uploadId")
+				w.WriteHeader(204)
+				return
+			}
+			w.Write([]byte("<Error><Code>InvalidPart</Code><Message>One or more of the specified parts could not be found.</Message></Error>"))
+		})
+		defer ts.Close()
+		o := b.Object("bar")
+		upload := multipartUpload{object: &o, id: "1"}
+
+		_, err := upload.complete()
+		abortErr := upload.abort()
+
+		Convey("It returns the error in the body", func() {
+			So(err.(s3Error).Code, ShouldEqual, "InvalidPart")
+		})
+		Convey("It can be aborted", func() {
+			So(abortErr, ShouldBeNil)
+			So(aborted, ShouldEqual, "1")
+		})
+	})
+}
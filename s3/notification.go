@@ -0,0 +1,235 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"regexp"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/sns"
+	"github.com/controlgroup/gaws/sqs"
+)
+
+// The events S3 can send notifications for. See http://docs.aws.amazon.com/AmazonS3/latest/dev/NotificationHowTo.html for the rest.
+const (
+	ObjectCreated          = "s3:ObjectCreated:*"
+	ObjectCreatedPut       = "s3:ObjectCreated:Put"
+	ObjectCreatedCopy      = "s3:ObjectCreated:Copy"
+	ObjectCreatedMultipart = "s3:ObjectCreated:CompleteMultipartUpload"
+	ObjectRemoved          = "s3:ObjectRemoved:*"
+	ObjectRemovedDelete    = "s3:ObjectRemoved:Delete"
+	ReducedRedundancyLost  = "s3:ReducedRedundancyLostObject"
+)
+
+// FilterRule limits notifications to keys with a prefix or a suffix. Name is prefix or suffix.
+type FilterRule struct {
+	Name  string
+	Value string
+}
+
+// NotificationFilter limits notifications to the keys that match all of its rules.
+type NotificationFilter struct {
+	Rules []FilterRule `xml:"S3Key>FilterRule"`
+}
+
+// TopicConfiguration sends notifications of the events to an SNS topic.
+type TopicConfiguration struct {
+	Id       string              `xml:",omitempty"`
+	TopicArn string              `xml:"Topic"`
+	Events   []string            `xml:"Event"`
+	Filter   *NotificationFilter `xml:",omitempty"`
+}
+
+// QueueConfiguration sends notifications of the events to an SQS queue.
+type QueueConfiguration struct {
+	Id       string              `xml:",omitempty"`
+	QueueArn string              `xml:"Queue"`
+	Events   []string            `xml:"Event"`
+	Filter   *NotificationFilter `xml:",omitempty"`
+}
+
+// LambdaFunctionConfiguration sends notifications of the events to a Lambda function.
+type LambdaFunctionConfiguration struct {
+	Id          string              `xml:",omitempty"`
+	FunctionArn string              `xml:"CloudFunction"`
+	Events      []string            `xml:"Event"`
+	Filter      *NotificationFilter `xml:",omitempty"`
+}
+
+// NotificationConfiguration says where S3 sends notifications of events in a bucket.
+type NotificationConfiguration struct {
+	XMLName                      xml.Name                      `xml:"NotificationConfiguration"`
+	TopicConfigurations          []TopicConfiguration          `xml:"TopicConfiguration"`
+	QueueConfigurations          []QueueConfiguration          `xml:"QueueConfiguration"`
+	LambdaFunctionConfigurations []LambdaFunctionConfiguration `xml:"CloudFunctionConfiguration"`
+}
+
+// notificationPath returns the path of the bucket's notification configuration.
+func (b *Bucket) notificationPath() string {
+	return b.path(url.Values{"notification": {""}})
+}
+
+// GetNotificationConfiguration gets the bucket's notification configuration. If the bucket does not send notifications, it is empty.
+// It is calling the GetBucketNotificationConfiguration API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketGETnotificationConfiguration.html for more details.
+func (b *Bucket) GetNotificationConfiguration() (NotificationConfiguration, error) {
+	result := NotificationConfiguration{}
+
+	req := b.Service.request("GET", b.notificationPath(), nil)
+
+	resp, err := req.Do()
+	if err != nil {
+		return result, err
+	}
+
+	err = gaws.DecodeXML(resp, &result)
+	return result, err
+}
+
+// PutNotificationConfiguration replaces the bucket's notification configuration. An empty configuration turns notifications off.
+// S3 checks that it may send to each destination, so their policies must allow it first; see NotifyTopic and NotifyQueue.
+// It is calling the PutBucketNotificationConfiguration API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTnotificationConfiguration.html for more details.
+func (b *Bucket) PutNotificationConfiguration(config NotificationConfiguration) error {
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	checksum := md5.Sum(body)
+
+	req := b.Service.request("PUT", b.notificationPath(), body)
+	req.Headers["Content-MD5"] = base64.StdEncoding.EncodeToString(checksum[:])
+
+	_, err = req.Do()
+	return err
+}
+
+// Arn returns the ARN of the bucket, which is what policies use as the source of S3's notifications.
+func (b *Bucket) Arn() string {
+	return "arn:aws:s3:::" + b.Name
+}
+
+// notAlphanumeric matches the characters that cannot be in the Sid of a policy statement.
+var notAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// notificationPolicyStatementId returns the Sid of the statement that lets the bucket send notifications.
+func notificationPolicyStatementId(bucketArn string) string {
+	return "AllowS3" + notAlphanumeric.ReplaceAllString(bucketArn, "")
+}
+
+// notificationPolicy adds a statement that lets the bucket do action on the resource, a topic or a queue, to its policy, which may be empty.
+// A statement for the same bucket is replaced, so it can be applied more than once.
+func notificationPolicy(policy string, action string, resourceArn string, bucketArn string) (string, error) {
+	document := map[string]interface{}{}
+	if policy != "" {
+		err := json.Unmarshal([]byte(policy), &document)
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, ok := document["Version"]; !ok {
+		document["Version"] = "2012-10-17"
+	}
+
+	sid := notificationPolicyStatementId(bucketArn)
+	statements := []interface{}{}
+	switch existing := document["Statement"].(type) {
+	case []interface{}:
+		statements = existing
+	case map[string]interface{}:
+		statements = []interface{}{existing}
+	}
+
+	kept := []interface{}{}
+	for _, s := range statements {
+		if statement, ok := s.(map[string]interface{}); ok && statement["Sid"] == sid {
+			continue
+		}
+		kept = append(kept, s)
+	}
+
+	document["Statement"] = append(kept, map[string]interface{}{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": "s3.amazonaws.com"},
+		"Action":    action,
+		"Resource":  resourceArn,
+		"Condition": map[string]interface{}{"ArnLike": map[string]string{"aws:SourceArn": bucketArn}},
+	})
+
+	b, err := json.Marshal(document)
+	return string(b), err
+}
+
+// NotifyTopic sends notifications of the events to the topic. It lets the bucket publish to the topic in the topic's policy,
+// keeping the rest of the policy, then adds the topic to the bucket's notification configuration, replacing what was there for the topic.
+// If filter is nil, every key is notified.
+func (b *Bucket) NotifyTopic(topic *sns.Topic, filter *NotificationFilter, events ...string) error {
+	attributes, err := topic.GetAttributes()
+	if err != nil {
+		return err
+	}
+
+	policy, err := notificationPolicy(attributes.Policy, "sns:Publish", topic.Arn, b.Arn())
+	if err != nil {
+		return err
+	}
+
+	err = topic.SetAttributes(sns.TopicAttributes{Policy: policy})
+	if err != nil {
+		return err
+	}
+
+	config, err := b.GetNotificationConfiguration()
+	if err != nil {
+		return err
+	}
+
+	kept := []TopicConfiguration{}
+	for _, c := range config.TopicConfigurations {
+		if c.TopicArn != topic.Arn {
+			kept = append(kept, c)
+		}
+	}
+	config.TopicConfigurations = append(kept, TopicConfiguration{TopicArn: topic.Arn, Events: events, Filter: filter})
+
+	return b.PutNotificationConfiguration(config)
+}
+
+// NotifyQueue sends notifications of the events to the queue. It lets the bucket send to the queue in the queue's policy,
+// keeping the rest of the policy, then adds the queue to the bucket's notification configuration, replacing what was there for the queue.
+// If filter is nil, every key is notified.
+func (b *Bucket) NotifyQueue(queue *sqs.Queue, filter *NotificationFilter, events ...string) error {
+	attributes, err := queue.GetAttributes()
+	if err != nil {
+		return err
+	}
+
+	policy, err := notificationPolicy(attributes.Policy, "sqs:SendMessage", attributes.QueueArn, b.Arn())
+	if err != nil {
+		return err
+	}
+
+	err = queue.SetAttributes(sqs.QueueAttributes{Policy: policy})
+	if err != nil {
+		return err
+	}
+
+	config, err := b.GetNotificationConfiguration()
+	if err != nil {
+		return err
+	}
+
+	kept := []QueueConfiguration{}
+	for _, c := range config.QueueConfigurations {
+		if c.QueueArn != attributes.QueueArn {
+			kept = append(kept, c)
+		}
+	}
+	config.QueueConfigurations = append(kept, QueueConfiguration{QueueArn: attributes.QueueArn, Events: events, Filter: filter})
+
+	return b.PutNotificationConfiguration(config)
+}
@@ -0,0 +1,240 @@
+package s3
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws/sns"
+	"github.com/controlgroup/gaws/sqs"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testNotification = `<NotificationConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <TopicConfiguration>
+    <Id>uploads</Id>
+    <Topic>arn:aws:sns:us-east-1:123456789012:uploads</Topic>
+    <Event>s3:ObjectCreated:*</Event>
+    <Filter><S3Key><FilterRule><Name>prefix</Name><Value>uploads/</Value></FilterRule></S3Key></Filter>
+  </TopicConfiguration>
+  <QueueConfiguration>
+    <Id>deletes</Id>
+    <Queue>arn:aws:sqs:us-east-1:123456789012:deletes</Queue>
+    <Event>s3:ObjectRemoved:*</Event>
+  </QueueConfiguration>
+</NotificationConfiguration>`
+
+func TestGetNotificationConfiguration(t *testing.T) {
+	Convey("Given a bucket with a notification configuration", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.Write([]byte(testNotification))
+		})
+		defer ts.Close()
+
+		config, err := b.GetNotificationConfiguration()
+
+		Convey("It returns the configuration", func() {
+			So(err, ShouldBeNil)
+			So(request.URL.RawQuery, ShouldEqual, "notification=")
+			So(config.TopicConfigurations, ShouldResemble, []TopicConfiguration{{
+				Id:       "uploads",
+				TopicArn: "arn:aws:sns:us-east-1:123456789012:uploads",
+				Events:   []string{ObjectCreated},
+				Filter:   &NotificationFilter{Rules: []FilterRule{{Name: "prefix", Value: "uploads/"}}},
+			}})
+			So(config.QueueConfigurations, ShouldResemble, []QueueConfiguration{{
+				Id:       "deletes",
+				QueueArn: "arn:aws:sqs:us-east-1:123456789012:deletes",
+				Events:   []string{ObjectRemoved},
+			}})
+		})
+	})
+	Convey("Given a bucket that returns an error", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			w.Write([]byte("<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>"))
+		})
+		defer ts.Close()
+
+		_, err := b.GetNotificationConfiguration()
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPutNotificationConfiguration(t *testing.T) {
+	Convey("Given a configuration", t, func() {
+		var request *http.Request
+		var body []byte
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			body, _ = ioutil.ReadAll(r.Body)
+		})
+		defer ts.Close()
+
+		err := b.PutNotificationConfiguration(NotificationConfiguration{
+			QueueConfigurations: []QueueConfiguration{{QueueArn: "arn:aws:sqs:us-east-1:123456789012:deletes", Events: []string{ObjectRemoved}}},
+		})
+
+		Convey("It puts the configuration with a checksum", func() {
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "PUT")
+			So(request.URL.RawQuery, ShouldEqual, "notification=")
+			So(request.Header.Get("Content-MD5"), ShouldNotEqual, "")
+			So(string(body), ShouldEqual, "<NotificationConfiguration><QueueConfiguration><Queue>arn:aws:sqs:us-east-1:123456789012:deletes</Queue><Event>s3:ObjectRemoved:*</Event></QueueConfiguration></NotificationConfiguration>")
+		})
+	})
+}
+
+func TestNotificationPolicy(t *testing.T) {
+	Convey("Given a topic without a policy", t, func() {
+		policy, err := notificationPolicy("", "sns:Publish", "arn:aws:sns:us-east-1:123456789012:uploads", "arn:aws:s3:::foo")
+		document := map[string]interface{}{}
+		json.Unmarshal([]byte(policy), &document)
+		statements := document["Statement"].([]interface{})
+		statement := statements[0].(map[string]interface{})
+
+		Convey("It makes a policy that lets the bucket publish to the topic", func() {
+			So(err, ShouldBeNil)
+			So(document["Version"], ShouldEqual, "2012-10-17")
+			So(len(statements), ShouldEqual, 1)
+			So(statement["Sid"], ShouldEqual, "AllowS3arnawss3foo")
+			So(statement["Principal"], ShouldResemble, map[string]interface{}{"Service": "s3.amazonaws.com"})
+			So(statement["Action"], ShouldEqual, "sns:Publish")
+			So(statement["Resource"], ShouldEqual, "arn:aws:sns:us-east-1:123456789012:uploads")
+			So(statement["Condition"], ShouldResemble, map[string]interface{}{"ArnLike": map[string]interface{}{"aws:SourceArn": "arn:aws:s3:::foo"}})
+		})
+	})
+	Convey("Given a queue with a policy", t, func() {
+		existing := `{"Version":"2008-10-17","Statement":{"Sid":"Mine","Effect":"Allow","Principal":"*","Action":"sqs:ReceiveMessage"}}`
+		policy, err := notificationPolicy(existing, "sqs:SendMessage", "arn:aws:sqs:us-east-1:123456789012:deletes", "arn:aws:s3:::foo")
+		again, _ := notificationPolicy(policy, "sqs:SendMessage", "arn:aws:sqs:us-east-1:123456789012:deletes", "arn:aws:s3:::foo")
+
+		document := map[string]interface{}{}
+		json.Unmarshal([]byte(again), &document)
+		statements := document["Statement"].([]interface{})
+
+		Convey("It keeps the existing statements", func() {
+			So(err, ShouldBeNil)
+			So(document["Version"], ShouldEqual, "2008-10-17")
+			So(statements[0].(map[string]interface{})["Sid"], ShouldEqual, "Mine")
+		})
+		Convey("It only adds the bucket's statement once", func() {
+			So(len(statements), ShouldEqual, 2)
+			So(again, ShouldEqual, policy)
+		})
+	})
+	Convey("Given a policy that is not JSON", t, func() {
+		_, err := notificationPolicy("{", "sns:Publish", "arn:aws:sns:us-east-1:123456789012:uploads", "arn:aws:s3:::foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestNotifyTopic(t *testing.T) {
+	Convey("Given a bucket that sends notifications to another topic", t, func() {
+		params := []url.Values{}
+		put := NotificationConfiguration{}
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST":
+				r.ParseForm()
+				params = append(params, r.PostForm)
+				if r.PostForm.Get("Action") == "GetTopicAttributes" {
+					w.Write([]byte("<GetTopicAttributesResponse><GetTopicAttributesResult><Attributes><entry><key>Policy</key><value></value></entry></Attributes></GetTopicAttributesResult></GetTopicAttributesResponse>"))
+					return
+				}
+				w.Write([]byte("<Response></Response>"))
+			case r.Method == "GET":
+				w.Write([]byte(testNotification))
+			case r.Method == "PUT":
+				body, _ := ioutil.ReadAll(r.Body)
+				xml.Unmarshal(body, &put)
+			}
+		})
+		defer ts.Close()
+		topic := &sns.Topic{Arn: "arn:aws:sns:us-east-1:123456789012:created", Service: &sns.SNSService{Endpoint: ts.URL}}
+
+		err := b.NotifyTopic(topic, &NotificationFilter{Rules: []FilterRule{{Name: "suffix", Value: ".jpg"}}}, ObjectCreated)
+
+		Convey("It lets the bucket publish to the topic", func() {
+			So(err, ShouldBeNil)
+			So(params[1].Get("Action"), ShouldEqual, "SetTopicAttributes")
+			So(params[1].Get("AttributeName"), ShouldEqual, "Policy")
+			So(params[1].Get("AttributeValue"), ShouldContainSubstring, `"aws:SourceArn":"arn:aws:s3:::foo"`)
+		})
+		Convey("It adds the topic and keeps the other destinations", func() {
+			So(len(put.TopicConfigurations), ShouldEqual, 2)
+			So(put.TopicConfigurations[1], ShouldResemble, TopicConfiguration{
+				TopicArn: "arn:aws:sns:us-east-1:123456789012:created",
+				Events:   []string{ObjectCreated},
+				Filter:   &NotificationFilter{Rules: []FilterRule{{Name: "suffix", Value: ".jpg"}}},
+			})
+			So(len(put.QueueConfigurations), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestNotifyQueue(t *testing.T) {
+	Convey("Given a bucket that already sends notifications to the queue", t, func() {
+		params := []url.Values{}
+		put := NotificationConfiguration{}
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == "POST":
+				r.ParseForm()
+				params = append(params, r.PostForm)
+				if r.PostForm.Get("Action") == "GetQueueAttributes" {
+					w.Write([]byte("<GetQueueAttributesResponse><GetQueueAttributesResult><Attribute><Name>QueueArn</Name><Value>arn:aws:sqs:us-east-1:123456789012:deletes</Value></Attribute></GetQueueAttributesResult></GetQueueAttributesResponse>"))
+					return
+				}
+				w.Write([]byte("<Response></Response>"))
+			case r.Method == "GET":
+				w.Write([]byte(testNotification))
+			case r.Method == "PUT":
+				body, _ := ioutil.ReadAll(r.Body)
+				xml.Unmarshal(body, &put)
+			}
+		})
+		defer ts.Close()
+		q := &sqs.Queue{URL: ts.URL + "/123456789012/deletes", Service: &sqs.SQSService{Endpoint: ts.URL}}
+
+		err := b.NotifyQueue(q, nil, ObjectRemoved, ObjectCreated)
+
+		Convey("It lets the bucket send to the queue", func() {
+			So(err, ShouldBeNil)
+			So(params[1].Get("Action"), ShouldEqual, "SetQueueAttributes")
+			So(params[1].Get("Attribute.1.Value"), ShouldContainSubstring, `"Action":"sqs:SendMessage"`)
+		})
+		Convey("It replaces the queue's configuration", func() {
+			So(put.QueueConfigurations, ShouldResemble, []QueueConfiguration{{
+				QueueArn: "arn:aws:sqs:us-east-1:123456789012:deletes",
+				Events:   []string{ObjectRemoved, ObjectCreated},
+			}})
+			So(len(put.TopicConfigurations), ShouldEqual, 1)
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(400)
+			w.Write([]byte("<ErrorResponse><Error><Code>AccessDenied</Code></Error></ErrorResponse>"))
+		})
+		defer ts.Close()
+		q := &sqs.Queue{URL: ts.URL + "/123456789012/deletes", Service: &sqs.SQSService{Endpoint: ts.URL}}
+
+		err := b.NotifyQueue(q, nil, ObjectRemoved)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
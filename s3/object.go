@@ -0,0 +1,206 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// metadataPrefix is the start of the headers that hold user metadata.
+const metadataPrefix = "X-Amz-Meta-"
+
+var badContentRangeError = s3Error{Code: "GawsBadContentRange", Message: "S3 did not say which part of the object it sent."}
+
+// ObjectInfo is what S3 says about an object when it is read.
+type ObjectInfo struct {
+	ContentLength int64
+	ContentType   string
+	ETag          string // The entity tag. For objects that were not uploaded in parts, it is the quoted hex MD5 of the contents.
+	LastModified  time.Time
+	Metadata      map[string]string // User metadata, with lowercase names
+	StorageClass  string            // Like STANDARD_IA or GLACIER. S3 leaves it out for STANDARD, so it is empty.
+	ContentRange  *ContentRange     // The part of the object that was returned, if it was not all of it
+}
+
+// ContentRange is the part of an object in a partial response.
+type ContentRange struct {
+	First int64 // The first byte
+	Last  int64 // The last byte, inclusive
+	Size  int64 // The size of the whole object
+}
+
+// parseContentRange parses a Content-Range header, like "bytes 0-9/100".
+func parseContentRange(header string) (*ContentRange, error) {
+	r := ContentRange{}
+	_, err := fmt.Sscanf(header, "bytes %d-%d/%d", &r.First, &r.Last, &r.Size)
+	if err != nil {
+		return nil, badContentRangeError
+	}
+	return &r, nil
+}
+
+// objectInfo reads the ObjectInfo from the headers of a response.
+func objectInfo(resp *http.Response) ObjectInfo {
+	info := ObjectInfo{
+		ContentLength: resp.ContentLength,
+		ContentType:   resp.Header.Get("Content-Type"),
+		ETag:          resp.Header.Get("ETag"),
+		StorageClass:  resp.Header.Get("X-Amz-Storage-Class"),
+	}
+	info.LastModified, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	if resp.StatusCode == http.StatusPartialContent {
+		info.ContentRange, _ = parseContentRange(resp.Header.Get("Content-Range"))
+	}
+
+	for name, values := range resp.Header {
+		if strings.HasPrefix(name, metadataPrefix) && len(values) > 0 {
+			if info.Metadata == nil {
+				info.Metadata = map[string]string{}
+			}
+			info.Metadata[strings.ToLower(strings.TrimPrefix(name, metadataPrefix))] = values[0]
+		}
+	}
+	return info
+}
+
+// Get reads the object. It returns the contents, which must be closed, and what S3 says about the object. It is calling the GetObject API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectGET.html for more details.
+func (o *Object) Get() (io.ReadCloser, ObjectInfo, error) {
+	req := o.Bucket.Service.request("GET", o.path(nil), nil)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	return resp.Body, objectInfo(resp), nil
+}
+
+var notModifiedError = s3Error{Code: "NotModified", Message: "The object has not been modified since the given time, or still has the given ETag."}
+
+// GetOptions say which part of an object to get, and when to get it.
+type GetOptions struct {
+	Range             string    // The bytes to get, like "bytes=0-9", or "bytes=-10" for the last 10. If it is empty, the whole object is returned.
+	IfMatch           string    // Only get the object if it has this ETag
+	IfNoneMatch       string    // Only get the object if it does not have this ETag
+	IfModifiedSince   time.Time // Only get the object if it was modified after this
+	IfUnmodifiedSince time.Time // Only get the object if it was not modified after this
+}
+
+// headers adds the options to the headers of a request.
+func (g GetOptions) headers(headers map[string]string) {
+	if g.Range != "" {
+		headers["Range"] = g.Range
+	}
+	if g.IfMatch != "" {
+		headers["If-Match"] = g.IfMatch
+	}
+	if g.IfNoneMatch != "" {
+		headers["If-None-Match"] = g.IfNoneMatch
+	}
+	if !g.IfModifiedSince.IsZero() {
+		headers["If-Modified-Since"] = g.IfModifiedSince.UTC().Format(http.TimeFormat)
+	}
+	if !g.IfUnmodifiedSince.IsZero() {
+		headers["If-Unmodified-Since"] = g.IfUnmodifiedSince.UTC().Format(http.TimeFormat)
+	}
+}
+
+// GetWithOptions reads part of the object, or reads it only if the conditions in options are met. It returns the contents, which must be closed,
+// and what S3 says about the object, with the ContentRange of a partial response. If IfMatch or IfUnmodifiedSince are not met, the error has the code
+// PreconditionFailed, and if IfNoneMatch or IfModifiedSince are not met, the code is NotModified. It is calling the GetObject API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectGET.html for more details.
+func (o *Object) GetWithOptions(options GetOptions) (io.ReadCloser, ObjectInfo, error) {
+	req := o.Bucket.Service.request("GET", o.path(nil), nil)
+	options.headers(req.Headers)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, notModifiedError
+	}
+	return resp.Body, objectInfo(resp), nil
+}
+
+// Head gets what S3 says about the object, without its contents. It is calling the HeadObject API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectHEAD.html for more details.
+func (o *Object) Head() (ObjectInfo, error) {
+	req := o.Bucket.Service.request("HEAD", o.path(nil), nil)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+	return objectInfo(resp), nil
+}
+
+// Exists returns whether the object exists. It is calling the HeadObject API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectHEAD.html for more details.
+func (o *Object) Exists() (bool, error) {
+	_, err := o.Head()
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Delete deletes the object. Deleting an object that does not exist is not an error. It is calling the DeleteObject API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectDELETE.html for more details.
+func (o *Object) Delete() error {
+	req := o.Bucket.Service.request("DELETE", o.path(nil), nil)
+
+	_, err := req.Do()
+	return err
+}
+
+// PutOptions are the optional headers of a PutObject request.
+type PutOptions struct {
+	ACL         string            // A canned ACL, like private or public-read. If it is empty, S3 uses private.
+	ContentType string            // The MIME type of the object. If it is empty, S3 uses binary/octet-stream.
+	Metadata    map[string]string // User metadata to keep with the object
+}
+
+// headers adds the options to the headers of a request.
+func (p PutOptions) headers(headers map[string]string) {
+	if p.ACL != "" {
+		headers["X-Amz-Acl"] = p.ACL
+	}
+	if p.ContentType != "" {
+		headers["Content-Type"] = p.ContentType
+	}
+	for name, value := range p.Metadata {
+		headers[metadataPrefix+name] = value
+	}
+}
+
+// Put writes the contents of body to the object, replacing it if it exists. It returns the ETag of the new object. It is calling the PutObject API call.
+// body is read into memory, so it can be hashed for the signature and sent again if the request is retried. S3 checks the contents with their MD5.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTObjectPUT.html for more details.
+func (o *Object) Put(body io.Reader, options PutOptions) (string, error) {
+	contents, err := ioutil.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	checksum := md5.Sum(contents)
+
+	req := o.Bucket.Service.request("PUT", o.path(nil), contents)
+	req.Headers["Content-MD5"] = base64.StdEncoding.EncodeToString(checksum[:])
+	options.headers(req.Headers)
+
+	resp, err := req.DoResponse()
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	return resp.Header.Get("ETag"), nil
+}
@@ -0,0 +1,266 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestGetObject(t *testing.T) {
+	Convey("Given a bucket with an object", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("ETag", `"ed076287532e86365e841e92bfc50d8c"`)
+			w.Header().Set("Last-Modified", "Wed, 12 Oct 2009 17:50:00 GMT")
+			w.Header().Set("X-Amz-Meta-Color", "red")
+			w.Write([]byte("Hello World!"))
+		})
+		defer ts.Close()
+		o := b.Object("greetings/hello.txt")
+
+		body, info, err := o.Get()
+
+		Convey("It returns the contents", func() {
+			So(err, ShouldBeNil)
+			defer body.Close()
+			contents, _ := ioutil.ReadAll(body)
+			So(string(contents), ShouldEqual, "Hello World!")
+		})
+		Convey("It returns what S3 says about the object", func() {
+			So(info.ContentLength, ShouldEqual, 12)
+			So(info.ContentType, ShouldEqual, "text/plain")
+			So(info.ETag, ShouldEqual, `"ed076287532e86365e841e92bfc50d8c"`)
+			So(info.LastModified.Year(), ShouldEqual, 2009)
+			So(info.Metadata, ShouldResemble, map[string]string{"color": "red"})
+		})
+		Convey("It gets the object by its path", func() {
+			So(request.Method, ShouldEqual, "GET")
+			So(request.URL.Path, ShouldEqual, "/foo/greetings/hello.txt")
+			So(request.Header.Get("X-Amz-Content-Sha256"), ShouldEqual, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+		})
+	})
+	Convey("Given a bucket without the object", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+		o := b.Object("missing")
+
+		body, _, err := o.Get()
+
+		Convey("It returns an error and no contents", func() {
+			So(err.Error(), ShouldStartWith, "NoSuchKey")
+			So(body, ShouldBeNil)
+		})
+	})
+}
+
+func TestGetObjectWithOptions(t *testing.T) {
+	lastModified := time.Date(2009, 10, 12, 17, 50, 0, 0, time.UTC)
+
+	Convey("Given a range of an object", t, func() {
+		ranges := []string{}
+		ts, b := testBucket(testRangeHandler(testContents, `"abc"`, &ranges))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		body, info, err := o.GetWithOptions(GetOptions{Range: "bytes=4-8", IfMatch: `"abc"`})
+
+		Convey("It returns that part of the object", func() {
+			So(err, ShouldBeNil)
+			defer body.Close()
+			contents, _ := ioutil.ReadAll(body)
+			So(string(contents), ShouldEqual, "quick")
+			So(ranges, ShouldResemble, []string{"bytes=4-8"})
+		})
+		Convey("It returns which part it is", func() {
+			So(info.ContentRange, ShouldResemble, &ContentRange{First: 4, Last: 8, Size: 44})
+		})
+	})
+	Convey("Given conditions", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			http.ServeContent(w, r, "", lastModified, strings.NewReader("Hello World!"))
+		})
+		defer ts.Close()
+		o := b.Object("hello.txt")
+
+		body, info, err := o.GetWithOptions(GetOptions{IfNoneMatch: `"old"`, IfUnmodifiedSince: lastModified})
+
+		Convey("They are sent as headers", func() {
+			So(request.Header.Get("If-None-Match"), ShouldEqual, `"old"`)
+			So(request.Header.Get("If-Unmodified-Since"), ShouldEqual, "Mon, 12 Oct 2009 17:50:00 GMT")
+		})
+		Convey("It returns the whole object when they are met", func() {
+			So(err, ShouldBeNil)
+			body.Close()
+			So(info.ContentRange, ShouldBeNil)
+			So(info.ContentLength, ShouldEqual, 12)
+		})
+	})
+	Convey("Given an object that has not been modified", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "", lastModified, strings.NewReader("Hello World!"))
+		})
+		defer ts.Close()
+		o := b.Object("hello.txt")
+
+		body, _, err := o.GetWithOptions(GetOptions{IfModifiedSince: lastModified})
+
+		Convey("It returns an error and no contents", func() {
+			So(err, ShouldResemble, notModifiedError)
+			So(body, ShouldBeNil)
+		})
+	})
+	Convey("Given an object that has changed", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(412)
+			w.Write([]byte("<Error><Code>PreconditionFailed</Code><Message>At least one of the preconditions you specified did not hold.</Message></Error>"))
+		})
+		defer ts.Close()
+		o := b.Object("hello.txt")
+
+		_, _, err := o.GetWithOptions(GetOptions{IfMatch: `"old"`})
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "PreconditionFailed")
+		})
+	})
+}
+
+func TestParseContentRange(t *testing.T) {
+	Convey("Given a Content-Range", t, func() {
+		r, err := parseContentRange("bytes 0-9/100")
+
+		Convey("It is parsed", func() {
+			So(err, ShouldBeNil)
+			So(r, ShouldResemble, &ContentRange{First: 0, Last: 9, Size: 100})
+		})
+	})
+	Convey("Given a Content-Range without the size", t, func() {
+		_, err := parseContentRange("bytes 0-9/*")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, badContentRangeError)
+		})
+	})
+}
+
+func TestHeadObject(t *testing.T) {
+	Convey("Given a bucket with an object", t, func() {
+		var method string
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			w.Header().Set("Content-Length", "434234")
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("ETag", `"fba9dede5f27731c9771645a39863328"`)
+			w.Header().Set("X-Amz-Meta-Color", "red")
+			w.Header().Set("X-Amz-Storage-Class", "STANDARD_IA")
+		})
+		defer ts.Close()
+		o := b.Object("photo.jpg")
+
+		info, err := o.Head()
+		exists, existsErr := o.Exists()
+
+		Convey("It returns what S3 says about the object", func() {
+			So(err, ShouldBeNil)
+			So(method, ShouldEqual, "HEAD")
+			So(info.ContentLength, ShouldEqual, 434234)
+			So(info.ContentType, ShouldEqual, "image/jpeg")
+			So(info.ETag, ShouldEqual, `"fba9dede5f27731c9771645a39863328"`)
+			So(info.Metadata, ShouldResemble, map[string]string{"color": "red"})
+			So(info.StorageClass, ShouldEqual, "STANDARD_IA")
+		})
+		Convey("It exists", func() {
+			So(existsErr, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+		})
+	})
+	Convey("Given a bucket without the object", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+		})
+		defer ts.Close()
+		o := b.Object("missing")
+
+		_, err := o.Head()
+		exists, existsErr := o.Exists()
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "NotFound")
+		})
+		Convey("It does not exist", func() {
+			So(existsErr, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+	})
+}
+
+func TestPutObject(t *testing.T) {
+	Convey("Given a bucket", t, func() {
+		var request *http.Request
+		var contents []byte
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			contents, _ = ioutil.ReadAll(r.Body)
+			w.Header().Set("ETag", `"ed076287532e86365e841e92bfc50d8c"`)
+		})
+		defer ts.Close()
+		o := b.Object("hello.txt")
+
+		etag, err := o.Put(strings.NewReader("Hello World!"), PutOptions{ACL: "public-read", ContentType: "text/plain", Metadata: map[string]string{"color": "red"}})
+
+		Convey("It returns the ETag", func() {
+			So(err, ShouldBeNil)
+			So(etag, ShouldEqual, `"ed076287532e86365e841e92bfc50d8c"`)
+		})
+		Convey("It sends the contents with their checksums", func() {
+			So(request.Method, ShouldEqual, "PUT")
+			So(string(contents), ShouldEqual, "Hello World!")
+			So(request.Header.Get("Content-MD5"), ShouldEqual, "7Qdih1MuhjZehB6Sv8UNjA==")
+			So(request.Header.Get("X-Amz-Content-Sha256"), ShouldEqual, "7f83b1657ff1fc53b92dc18148a1d65dfc2d4b1fa3d677284addd200126d9069")
+		})
+		Convey("It sends the ACL, content type and metadata", func() {
+			So(request.Header.Get("X-Amz-Acl"), ShouldEqual, "public-read")
+			So(request.Header.Get("Content-Type"), ShouldEqual, "text/plain")
+			So(request.Header.Get("X-Amz-Meta-Color"), ShouldEqual, "red")
+		})
+	})
+	Convey("Given a bucket that returns errors", t, func() {
+		ts, b := testBucket(testHTTP404)
+		defer ts.Close()
+		o := b.Object("hello.txt")
+
+		_, err := o.Put(strings.NewReader("Hello World!"), PutOptions{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeleteObject(t *testing.T) {
+	Convey("Given a bucket with an object", t, func() {
+		var request *http.Request
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			w.WriteHeader(204)
+		})
+		defer ts.Close()
+		o := b.Object("greetings/hello.txt")
+
+		err := o.Delete()
+
+		Convey("It deletes the object by its path", func() {
+			So(err, ShouldBeNil)
+			So(request.Method, ShouldEqual, "DELETE")
+			So(request.URL.Path, ShouldEqual, "/foo/greetings/hello.txt")
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package s3
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/controlgroup/gaws/sqs"
+)
+
+// PayloadStore keeps the bodies of large SQS messages in S3. Set it as the Store of an sqs.PayloadOffload.
+type PayloadStore struct {
+	Service *S3Service // The service for the region of the buckets
+}
+
+var _ sqs.PayloadStore = (*PayloadStore)(nil)
+
+// object returns the object with the key in the bucket.
+func (p *PayloadStore) object(bucket string, key string) Object {
+	b := Bucket{Name: bucket, Service: p.Service}
+	return b.Object(key)
+}
+
+// PutPayload puts a message body in the bucket under the key.
+func (p *PayloadStore) PutPayload(bucket string, key string, payload []byte) error {
+	o := p.object(bucket, key)
+	_, err := o.Put(bytes.NewReader(payload), PutOptions{})
+	return err
+}
+
+// GetPayload reads the message body in the bucket under the key.
+func (p *PayloadStore) GetPayload(bucket string, key string) ([]byte, error) {
+	o := p.object(bucket, key)
+	body, _, err := o.Get()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return ioutil.ReadAll(body)
+}
+
+// DeletePayload deletes the message body in the bucket under the key.
+func (p *PayloadStore) DeletePayload(bucket string, key string) error {
+	o := p.object(bucket, key)
+	return o.Delete()
+}
@@ -0,0 +1,63 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testObjectServer returns a server that keeps objects in memory, by their path.
+func testObjectServer() (*httptest.Server, map[string][]byte) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case "PUT":
+			objects[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+		case "GET":
+			contents, ok := objects[r.URL.Path]
+			if !ok {
+				testHTTP404(w, r)
+				return
+			}
+			w.Write(contents)
+		case "DELETE":
+			delete(objects, r.URL.Path)
+			w.WriteHeader(204)
+		}
+	}))
+	return ts, objects
+}
+
+func TestPayloadStore(t *testing.T) {
+	Convey("Given a payload store", t, func() {
+		ts, objects := testObjectServer()
+		defer ts.Close()
+		p := PayloadStore{Service: &S3Service{Endpoint: ts.URL}}
+
+		Convey("A payload that is put can be read back", func() {
+			err := p.PutPayload("payloads", "1f0e2d3c", []byte("a large body"))
+			So(err, ShouldBeNil)
+			So(string(objects["/payloads/1f0e2d3c"]), ShouldEqual, "a large body")
+
+			payload, err := p.GetPayload("payloads", "1f0e2d3c")
+			So(err, ShouldBeNil)
+			So(string(payload), ShouldEqual, "a large body")
+
+			Convey("And deleting it removes it from the bucket", func() {
+				err := p.DeletePayload("payloads", "1f0e2d3c")
+				So(err, ShouldBeNil)
+				So(objects, ShouldBeEmpty)
+
+				_, err = p.GetPayload("payloads", "1f0e2d3c")
+				So(err.Error(), ShouldStartWith, "NoSuchKey")
+			})
+		})
+	})
+}
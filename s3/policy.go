@@ -0,0 +1,44 @@
+package s3
+
+import (
+	"errors"
+	"net/url"
+)
+
+// policyPath returns the path of the bucket's policy.
+func (b *Bucket) policyPath() string {
+	return b.path(url.Values{"policy": {""}})
+}
+
+// GetPolicy gets the bucket's policy, a JSON document. If the bucket does not have a policy, it returns an empty string. It is calling the GetBucketPolicy API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketGETpolicy.html for more details.
+func (b *Bucket) GetPolicy() (string, error) {
+	req := b.Service.request("GET", b.policyPath(), nil)
+
+	resp, err := req.Do()
+	if errors.Is(err, ErrNoSuchBucketPolicy) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(resp), nil
+}
+
+// PutPolicy replaces the bucket's policy with a JSON document. It is calling the PutBucketPolicy API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketPUTpolicy.html for more details.
+func (b *Bucket) PutPolicy(policy string) error {
+	req := b.Service.request("PUT", b.policyPath(), []byte(policy))
+
+	_, err := req.Do()
+	return err
+}
+
+// DeletePolicy deletes the bucket's policy. It is calling the DeleteBucketPolicy API call.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/RESTBucketDELETEpolicy.html for more details.
+func (b *Bucket) DeletePolicy() error {
+	req := b.Service.request("DELETE", b.policyPath(), nil)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,74 @@
+package s3
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testPolicy = `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::foo/*"}]}`
+
+func TestBucketPolicy(t *testing.T) {
+	Convey("Given a bucket with a policy", t, func() {
+		requests := []*http.Request{}
+		bodies := []string{}
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			requests = append(requests, r)
+			bodies = append(bodies, string(body))
+			if r.Method == "GET" {
+				w.Write([]byte(testPolicy))
+			}
+		})
+		defer ts.Close()
+
+		policy, err := b.GetPolicy()
+		putErr := b.PutPolicy(testPolicy)
+		deleteErr := b.DeletePolicy()
+
+		Convey("It gets the policy", func() {
+			So(err, ShouldBeNil)
+			So(policy, ShouldEqual, testPolicy)
+			So(requests[0].URL.RawQuery, ShouldEqual, "policy=")
+		})
+		Convey("It puts the policy", func() {
+			So(putErr, ShouldBeNil)
+			So(requests[1].Method, ShouldEqual, "PUT")
+			So(bodies[1], ShouldEqual, testPolicy)
+		})
+		Convey("It deletes the policy", func() {
+			So(deleteErr, ShouldBeNil)
+			So(requests[2].Method, ShouldEqual, "DELETE")
+			So(requests[2].URL.RawQuery, ShouldEqual, "policy=")
+		})
+	})
+	Convey("Given a bucket without a policy", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			w.Write([]byte("<Error><Code>NoSuchBucketPolicy</Code><Message>The bucket policy does not exist</Message></Error>"))
+		})
+		defer ts.Close()
+
+		policy, err := b.GetPolicy()
+
+		Convey("It returns an empty policy", func() {
+			So(err, ShouldBeNil)
+			So(policy, ShouldEqual, "")
+		})
+	})
+	Convey("Given a bucket that does not exist", t, func() {
+		ts, b := testBucket(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			w.Write([]byte("<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist</Message></Error>"))
+		})
+		defer ts.Close()
+
+		_, err := b.GetPolicy()
+
+		Convey("It returns an error", func() {
+			So(err.(s3Error).Code, ShouldEqual, "NoSuchBucket")
+		})
+	})
+}
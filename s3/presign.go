@@ -0,0 +1,73 @@
+package s3
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// MaxPresignExpiry is the longest S3 accepts a presigned URL for.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+var badExpiryError = s3Error{Code: "GawsBadExpiry", Message: "A presigned URL must expire after more than 0 seconds and no more than 7 days."}
+
+// ResponseHeaders override the headers S3 sends with an object, when it is read through a presigned URL.
+// Empty headers are not overridden.
+type ResponseHeaders struct {
+	CacheControl       string
+	ContentDisposition string // For example, attachment; filename="report.pdf" to make browsers save the object
+	ContentEncoding    string
+	ContentLanguage    string
+	ContentType        string
+	Expires            string
+}
+
+// query returns the query parameters that override the headers.
+func (h ResponseHeaders) query() url.Values {
+	query := url.Values{}
+	for name, value := range map[string]string{
+		"response-cache-control":       h.CacheControl,
+		"response-content-disposition": h.ContentDisposition,
+		"response-content-encoding":    h.ContentEncoding,
+		"response-content-language":    h.ContentLanguage,
+		"response-content-type":        h.ContentType,
+		"response-expires":             h.Expires,
+	} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	return query
+}
+
+// presign returns a URL for a request to the object, signed so it can be used without credentials until it expires.
+func (o *Object) presign(method string, query url.Values, expires time.Duration, credentials []awsauth.Credentials) (string, error) {
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return "", badExpiryError
+	}
+
+	req, err := http.NewRequest(method, o.Bucket.Service.Endpoint+o.path(query), nil)
+	if err != nil {
+		return "", err
+	}
+
+	gaws.Presign(req, "s3", o.Bucket.Service.region(), expires, credentials...)
+	return req.URL.String(), nil
+}
+
+// PresignGet returns a URL that reads the object until it expires, like a temporary link to share it. The headers S3 sends with it can be overridden.
+// If no credentials are given, they are read from the environment.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html for more details.
+func (o *Object) PresignGet(expires time.Duration, headers ResponseHeaders, credentials ...awsauth.Credentials) (string, error) {
+	return o.presign("GET", headers.query(), expires, credentials)
+}
+
+// PresignPut returns a URL that writes the object with a PUT request until it expires, like an upload from a browser.
+// If no credentials are given, they are read from the environment.
+// See http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html for more details.
+func (o *Object) PresignPut(expires time.Duration, credentials ...awsauth.Credentials) (string, error) {
+	return o.presign("PUT", nil, expires, credentials)
+}
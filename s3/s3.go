@@ -0,0 +1,161 @@
+// Package s3 provides a way to interact with the AWS Simple Storage Service.
+package s3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type s3Error -field Code -service S3 NoSuchBucket NoSuchKey NoSuchUpload NotFound AccessDenied BucketAlreadyExists BucketAlreadyOwnedByYou BucketNotEmpty PreconditionFailed InvalidRange NoSuchLifecycleConfiguration NoSuchBucketPolicy EntityTooLarge SlowDown
+
+// s3Error is the error document returned from the S3 service.
+type s3Error struct {
+	Code    string
+	Message string
+}
+
+// Error formats the s3Error into an error message.
+func (e s3Error) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+var waiterTimeoutError = s3Error{Code: "GawsWaiterTimeout", Message: "The resource did not reach the desired state in time."}
+
+// WaitInterval is how long waiters sleep between checks.
+var WaitInterval = 5 * time.Second
+
+// MaxWaits is the number of times a waiter checks before giving up.
+var MaxWaits = 20
+
+// wait calls done every WaitInterval, or as the client's gaws.WithWaitBackoff says, until it returns true or an error. It gives up after MaxWaits calls.
+func (s *S3Service) wait(done func() (bool, error)) error {
+	return s.config.Wait(WaitInterval, MaxWaits, waiterTimeoutError, done)
+}
+
+func s3RetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := s3Error{}
+
+	// HEAD responses do not have a body, so the status is all there is.
+	if len(body) == 0 {
+		error = s3Error{Code: strings.Replace(http.StatusText(status), " ", "", -1), Message: http.StatusText(status)}
+	} else {
+		err := xml.Unmarshal(body, &error)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "SlowDown" || error.Code == "RequestTimeout" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// S3Service is the S3 service at AWS. The endpoint for a region is gaws.Endpoint("s3", region).
+// Buckets are addressed by path, like https://s3.amazonaws.com/bucket/key.
+type S3Service struct {
+	Endpoint string
+	Region   string      // The region of the endpoint, which presigned URLs are signed for. If it is empty, gaws.Region is used.
+	config   gaws.Config // Set by New
+}
+
+// S3API is implemented by *S3Service. Code that takes an S3API can be given a fake in tests instead.
+type S3API interface {
+	CreateBucket(name string) (Bucket, error)
+	ListBuckets() ([]BucketSummary, error)
+}
+
+var _ S3API = (*S3Service)(nil)
+
+// New returns an S3Service with the options applied. Its endpoint and Region are for the region from gaws.WithRegion, unless
+// gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *S3Service {
+	config := gaws.NewConfig(options...)
+	return &S3Service{Endpoint: config.ServiceEndpoint("s3"), Region: config.Region, config: config}
+}
+
+// region returns the region of the endpoint.
+func (s *S3Service) region() string {
+	if s.Region == "" {
+		return gaws.Region
+	}
+	return s.Region
+}
+
+// request returns a request to a path on the endpoint. It is signed with signature version 4, including the SHA256 hash of the body.
+func (s *S3Service) request(method string, path string, body []byte) gaws.AWSRequest {
+	hash := sha256.Sum256(body)
+
+	r := gaws.AWSRequest{
+		RetryPredicate: s3RetryPredicate,
+		Method:         method,
+		URL:            s.Endpoint + path,
+		Headers: map[string]string{
+			"X-Amz-Content-Sha256": hex.EncodeToString(hash[:]),
+		},
+		Body:   body,
+		Signer: gaws.Sign4("s3", s.region()),
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// Bucket is an S3 bucket.
+type Bucket struct {
+	Name    string     // The name of the bucket
+	Service *S3Service // The service for the bucket's region
+}
+
+// path returns the path of the bucket, with query parameters if there are any.
+func (b *Bucket) path(query url.Values) string {
+	p := "/" + b.Name
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
+	return p
+}
+
+// Object returns the object in the bucket with the key. It does not check that the object exists.
+func (b *Bucket) Object(key string) Object {
+	return Object{Key: key, Bucket: b}
+}
+
+// Object is an object in a bucket.
+type Object struct {
+	Key    string  // The key of the object
+	Bucket *Bucket // The bucket the object is in
+}
+
+// path returns the path of the object, with query parameters if there are any. Each part of the key is escaped, but the slashes are kept.
+func (o *Object) path(query url.Values) string {
+	parts := strings.Split(o.Key, "/")
+	for i, part := range parts {
+		parts[i] = url.QueryEscape(part)
+		parts[i] = strings.Replace(parts[i], "+", "%20", -1)
+	}
+
+	p := "/" + o.Bucket.Name + "/" + strings.Join(parts, "/")
+	if len(query) > 0 {
+		p += "?" + query.Encode()
+	}
+	return p
+}
@@ -0,0 +1,135 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+var testNoSuchKeyError = []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<Error>
+  <Code>NoSuchKey</Code>
+  <Message>The resource you requested does not exist</Message>
+  <Resource>/mybucket/myfoto.jpg</Resource>
+  <RequestId>4442587FB7D0A2F9</RequestId>
+</Error>`)
+
+func testHTTP404(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(404)
+	w.Write(testNoSuchKeyError)
+}
+
+// testBucket returns a bucket on a server that runs handler.
+func testBucket(handler http.HandlerFunc) (*httptest.Server, Bucket) {
+	ts := httptest.NewServer(handler)
+	service := S3Service{Endpoint: ts.URL}
+	return ts, Bucket{Name: "foo", Service: &service}
+}
+
+// testDryRunBucket returns a bucket of a client in dry-run mode, what the client logs, and how many requests the server got.
+func testDryRunBucket() (*httptest.Server, Bucket, *bytes.Buffer, *int) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	b := &bytes.Buffer{}
+	s := New(gaws.WithEndpoint(ts.URL), gaws.WithDryRun(gaws.LogDryRunner{Logger: log.New(b, "", 0)}))
+	return ts, Bucket{Name: "foo", Service: s}, b, &requests
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := s3RetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := s3RetryPredicate(500, []byte("<Error><Code>InternalError</Code></Error>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"SlowDown\" code", t, func() {
+		result, _ := s3RetryPredicate(503, []byte("<Error><Code>SlowDown</Code></Error>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the key does not exist", t, func() {
+		result, err := s3RetryPredicate(404, testNoSuchKeyError)
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("The error has the code and message", func() {
+			So(err.Error(), ShouldEqual, "NoSuchKey: The resource you requested does not exist")
+		})
+	})
+	Convey("Given an error response without a body", t, func() {
+		result, err := s3RetryPredicate(404, []byte{})
+		Convey("The error comes from the status", func() {
+			So(result, ShouldBeFalse)
+			So(err.Error(), ShouldEqual, "NotFound: Not Found")
+		})
+	})
+}
+
+func TestPaths(t *testing.T) {
+	Convey("Given a bucket and an object with a key that needs escaping", t, func() {
+		b := Bucket{Name: "foo"}
+		o := b.Object("photos/2014/my photo+1.jpg")
+
+		Convey("The bucket's path has its name and query", func() {
+			So(b.path(nil), ShouldEqual, "/foo")
+			So(b.path(url.Values{"location": {""}}), ShouldEqual, "/foo?location=")
+		})
+		Convey("The object's path has each part of the key escaped", func() {
+			So(o.path(nil), ShouldEqual, "/foo/photos/2014/my%20photo%2B1.jpg")
+		})
+	})
+}
+
+func TestErrors(t *testing.T) {
+	Convey("Given an error from S3", t, func() {
+		_, err := s3RetryPredicate(404, testNoSuchKeyError)
+
+		Convey("It is the error for its code", func() {
+			So(errors.Is(err, ErrNoSuchKey), ShouldBeTrue)
+			So(errors.Is(err, ErrNoSuchBucket), ShouldBeFalse)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("s3", "eu-west-1"))
+			So(s.Region, ShouldEqual, "eu-west-1")
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request("GET", "/", nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
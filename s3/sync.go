@@ -0,0 +1,252 @@
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SyncOptions say which files are synced, and how.
+type SyncOptions struct {
+	Concurrency int      // How many files to transfer at once. If it is 0, DefaultConcurrency is used.
+	Include     []string // Only sync paths that match one of these patterns. If it is empty, every path is synced.
+	Exclude     []string // Do not sync paths that match one of these patterns
+	DryRun      bool     // Return what would be transferred, without transferring it
+}
+
+// matchAny returns whether the path relative to the directory matches any of the patterns. Patterns are matched with path.Match against the slash-separated path relative to the directory, and against its last element,
+// so "*.log" matches logs in every directory and "tmp/*" matches files directly in tmp.
+func matchAny(patterns []string, rel string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// included returns whether the path relative to the directory is synced.
+func (s SyncOptions) included(rel string) bool {
+	if len(s.Include) > 0 && !matchAny(s.Include, rel) {
+		return false
+	}
+	return !matchAny(s.Exclude, rel)
+}
+
+func (s SyncOptions) concurrency() int {
+	if s.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return s.Concurrency
+}
+
+// localFile is a file found in the directory being synced.
+type localFile struct {
+	path string
+	size int64
+}
+
+// localFiles walks the directory and returns the files in it by their slash-separated path relative to it.
+// Files left by an unfinished DownloadFile are left out.
+func localFiles(dir string) (map[string]localFile, error) {
+	files := map[string]localFile{}
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() || strings.HasSuffix(p, progressPath("")) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = localFile{path: p, size: info.Size()}
+		return nil
+	})
+	return files, err
+}
+
+// remoteObjects lists the objects under the prefix by their key after it.
+func (b *Bucket) remoteObjects(prefix string) (map[string]ObjectSummary, error) {
+	objects := map[string]ObjectSummary{}
+	summaries, _, err := b.ListObjects(ListOptions{Prefix: prefix})
+	if err != nil {
+		return objects, err
+	}
+	for _, o := range summaries {
+		rel := strings.TrimPrefix(o.Key, prefix)
+		if rel != "" && !strings.HasSuffix(rel, "/") {
+			objects[rel] = o
+		}
+	}
+	return objects, nil
+}
+
+// same returns whether a local file has the same contents as an object. Objects uploaded in parts have an ETag that is not their MD5,
+// so only their sizes are compared.
+func same(file localFile, object ObjectSummary) (bool, error) {
+	if file.size != object.Size {
+		return false, nil
+	}
+
+	etag := strings.Trim(object.ETag, `"`)
+	if etag == "" || strings.Contains(etag, "-") {
+		return true, nil
+	}
+
+	f, err := os.Open(file.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	hash := md5.New()
+	_, err = io.Copy(hash, f)
+	if err != nil {
+		return false, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)) == etag, nil
+}
+
+// transfer calls do for each of the paths, with concurrency at a time. It returns the first error.
+func transfer(paths []string, concurrency int, do func(rel string) error) error {
+	work := make(chan string)
+	lock := sync.Mutex{}
+	var firstErr error
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for rel := range work {
+				err := do(rel)
+				if err != nil {
+					lock.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					lock.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, rel := range paths {
+		work <- rel
+	}
+	close(work)
+	wg.Wait()
+
+	return firstErr
+}
+
+// SyncUp uploads the files in the directory that are not in the bucket under the prefix, or that are different there. A prefix like "backups/"
+// puts a file at dir/a/b.txt at backups/a/b.txt. It returns the slash-separated paths, relative to the directory, that were uploaded, sorted.
+// Objects that are not in the directory are left alone.
+func (b *Bucket) SyncUp(dir string, prefix string, options SyncOptions) ([]string, error) {
+	files, err := localFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := b.remoteObjects(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := []string{}
+	for rel, file := range files {
+		if !options.included(rel) {
+			continue
+		}
+		if object, ok := objects[rel]; ok {
+			unchanged, err := same(file, object)
+			if err != nil {
+				return nil, err
+			}
+			if unchanged {
+				continue
+			}
+		}
+		changed = append(changed, rel)
+	}
+	sort.Strings(changed)
+
+	if options.DryRun {
+		return changed, nil
+	}
+
+	err = transfer(changed, options.concurrency(), func(rel string) error {
+		f, err := os.Open(files[rel].path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		o := b.Object(prefix + rel)
+		_, err = (&Uploader{}).Upload(&o, f, PutOptions{})
+		return err
+	})
+	return changed, err
+}
+
+// SyncDown downloads the objects in the bucket under the prefix that are not in the directory, or that are different there. An object at
+// backups/a/b.txt, with the prefix "backups/", is put at dir/a/b.txt. It returns the slash-separated paths, relative to the directory, that were
+// downloaded, sorted. Files that are not in the bucket are left alone, and so are keys that would be outside the directory, like ones with "..".
+func (b *Bucket) SyncDown(prefix string, dir string, options SyncOptions) ([]string, error) {
+	objects, err := b.remoteObjects(prefix)
+	if err != nil {
+		return nil, err
+	}
+	files, err := localFiles(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	changed := []string{}
+	for rel, object := range objects {
+		if !options.included(rel) || !fs.ValidPath(rel) {
+			continue
+		}
+		if file, ok := files[rel]; ok {
+			unchanged, err := same(file, object)
+			if err != nil {
+				return nil, err
+			}
+			if unchanged {
+				continue
+			}
+		}
+		changed = append(changed, rel)
+	}
+	sort.Strings(changed)
+
+	if options.DryRun {
+		return changed, nil
+	}
+
+	err = transfer(changed, options.concurrency(), func(rel string) error {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		err := os.MkdirAll(filepath.Dir(p), 0777)
+		if err != nil {
+			return err
+		}
+
+		o := b.Object(prefix + rel)
+		_, err = (&Downloader{}).DownloadFile(&o, p)
+		return err
+	})
+	return changed, err
+}
@@ -0,0 +1,128 @@
+package s3
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testDir makes a directory with the files, by slash-separated path.
+func testDir(files map[string]string) string {
+	dir, _ := ioutil.TempDir("", "gaws")
+	for rel, contents := range files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		os.MkdirAll(filepath.Dir(p), 0777)
+		ioutil.WriteFile(p, []byte(contents), 0666)
+	}
+	return dir
+}
+
+func TestSyncUp(t *testing.T) {
+	Convey("Given a directory and a bucket with some of its files", t, func() {
+		dir := testDir(map[string]string{
+			"same.txt":                  "unchanged",
+			"changed.txt":               "new contents",
+			"sub/new.txt":               "new file",
+			"sub/debug.log":             "excluded",
+			"partial.txt.gaws-download": "{}",
+		})
+		defer os.RemoveAll(dir)
+		objects := map[string]string{
+			"backup/same.txt":    "unchanged",
+			"backup/changed.txt": "old contents",
+			"backup/gone.txt":    "only in the bucket",
+		}
+		ts, b := testBucket(testFakeBucket(objects))
+		defer ts.Close()
+
+		Convey("A dry run returns what would be uploaded", func() {
+			uploaded, err := b.SyncUp(dir, "backup/", SyncOptions{DryRun: true, Exclude: []string{"*.log"}})
+			So(err, ShouldBeNil)
+			So(uploaded, ShouldResemble, []string{"changed.txt", "sub/new.txt"})
+			So(objects["backup/changed.txt"], ShouldEqual, "old contents")
+		})
+		Convey("It uploads the files that are different", func() {
+			uploaded, err := b.SyncUp(dir, "backup/", SyncOptions{Exclude: []string{"*.log"}})
+			So(err, ShouldBeNil)
+			So(uploaded, ShouldResemble, []string{"changed.txt", "sub/new.txt"})
+			So(objects["backup/changed.txt"], ShouldEqual, "new contents")
+			So(objects["backup/sub/new.txt"], ShouldEqual, "new file")
+			So(objects["backup/gone.txt"], ShouldEqual, "only in the bucket")
+			_, ok := objects["backup/sub/debug.log"]
+			So(ok, ShouldBeFalse)
+		})
+		Convey("It only uploads the files that are included", func() {
+			uploaded, err := b.SyncUp(dir, "backup/", SyncOptions{Include: []string{"sub/*"}})
+			So(err, ShouldBeNil)
+			So(uploaded, ShouldResemble, []string{"sub/debug.log", "sub/new.txt"})
+		})
+	})
+	Convey("Given a directory that does not exist", t, func() {
+		ts, b := testBucket(testFakeBucket(map[string]string{}))
+		defer ts.Close()
+
+		_, err := b.SyncUp("/does/not/exist", "", SyncOptions{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSyncDown(t *testing.T) {
+	Convey("Given a bucket and a directory with some of its objects", t, func() {
+		dir := testDir(map[string]string{
+			"same.txt":    "unchanged",
+			"changed.txt": "a longer local version",
+			"local.txt":   "only in the directory",
+		})
+		defer os.RemoveAll(dir)
+		ts, b := testBucket(testFakeBucket(map[string]string{
+			"backup/same.txt":      "unchanged",
+			"backup/changed.txt":   "new contents",
+			"backup/sub/new.txt":   "new file",
+			"backup/sub/":          "",
+			"backup/../escape.txt": "outside",
+		}))
+		defer ts.Close()
+
+		Convey("A dry run returns what would be downloaded", func() {
+			downloaded, err := b.SyncDown("backup/", dir, SyncOptions{DryRun: true})
+			contents, _ := ioutil.ReadFile(filepath.Join(dir, "changed.txt"))
+			So(err, ShouldBeNil)
+			So(downloaded, ShouldResemble, []string{"changed.txt", "sub/new.txt"})
+			So(string(contents), ShouldEqual, "a longer local version")
+		})
+		Convey("It downloads the objects that are different", func() {
+			downloaded, err := b.SyncDown("backup/", dir, SyncOptions{Concurrency: 1})
+			So(err, ShouldBeNil)
+			So(downloaded, ShouldResemble, []string{"changed.txt", "sub/new.txt"})
+
+			changed, _ := ioutil.ReadFile(filepath.Join(dir, "changed.txt"))
+			created, _ := ioutil.ReadFile(filepath.Join(dir, "sub", "new.txt"))
+			local, _ := ioutil.ReadFile(filepath.Join(dir, "local.txt"))
+			So(string(changed), ShouldEqual, "new contents")
+			So(string(created), ShouldEqual, "new file")
+			So(string(local), ShouldEqual, "only in the directory")
+		})
+	})
+	Convey("Given a directory that does not exist yet", t, func() {
+		parent, _ := ioutil.TempDir("", "gaws")
+		defer os.RemoveAll(parent)
+		dir := filepath.Join(parent, "new")
+		ts, b := testBucket(testFakeBucket(map[string]string{"a.txt": "a"}))
+		defer ts.Close()
+
+		downloaded, err := b.SyncDown("", dir, SyncOptions{})
+		contents, _ := ioutil.ReadFile(filepath.Join(dir, "a.txt"))
+
+		Convey("It is made", func() {
+			So(err, ShouldBeNil)
+			So(downloaded, ShouldResemble, []string{"a.txt"})
+			So(string(contents), ShouldEqual, "a")
+		})
+	})
+}
@@ -0,0 +1,139 @@
+package s3
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// MaxParts is the most parts S3 puts together into one object.
+const MaxParts = 10000
+
+var tooManyPartsError = s3Error{Code: "GawsTooManyParts", Message: "The object needs more than 10000 parts. Use a larger PartSize."}
+
+// Uploader sends objects in parts, several at a time, so objects can be uploaded from a reader without knowing how long it is. The zero value uses the defaults.
+// S3 needs each part but the last to be at least 5 MB.
+type Uploader struct {
+	PartSize    int64 // How many bytes to send in each request
+	Concurrency int   // How many requests to make at once
+}
+
+func (u *Uploader) partSize() int64 {
+	if u.PartSize <= 0 {
+		return DefaultPartSize
+	}
+	return u.PartSize
+}
+
+func (u *Uploader) concurrency() int {
+	if u.Concurrency <= 0 {
+		return DefaultConcurrency
+	}
+	return u.Concurrency
+}
+
+// readPart reads up to size bytes from r. It returns false when r has nothing more after them.
+func readPart(r io.Reader, size int64) ([]byte, bool, error) {
+	part := make([]byte, size)
+	n, err := io.ReadFull(r, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return part[:n], false, nil
+	}
+	return part, err == nil, err
+}
+
+// Upload writes everything read from r to the object, replacing it if it exists. It returns the ETag of the new object.
+// Only Concurrency parts are kept in memory at once, so r can be much larger than memory, like the output of another process.
+// If r ends within the first part, the object is sent with one PutObject request. Otherwise it is sent in parts, which are aborted if one fails.
+// See http://docs.aws.amazon.com/AmazonS3/latest/dev/uploadobjusingmpu.html for more details.
+func (u *Uploader) Upload(o *Object, r io.Reader, options PutOptions) (string, error) {
+	first, more, err := readPart(r, u.partSize())
+	if err != nil {
+		return "", err
+	}
+	if !more {
+		return o.Put(bytes.NewReader(first), options)
+	}
+
+	upload, err := o.createMultipartUpload(options)
+	if err != nil {
+		return "", err
+	}
+
+	err = u.sendParts(upload, first, r)
+	if err != nil {
+		upload.abort()
+		return "", err
+	}
+
+	etag, err := upload.complete()
+	if err != nil {
+		upload.abort()
+	}
+	return etag, err
+}
+
+type numberedPart struct {
+	number   int
+	contents []byte
+}
+
+// sendParts uploads the first part, and the rest of r, with Concurrency workers. It returns the first error.
+func (u *Uploader) sendParts(upload *multipartUpload, first []byte, r io.Reader) error {
+	parts := make(chan numberedPart)
+	lock := sync.Mutex{}
+	var firstErr error
+
+	failed := func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return firstErr != nil
+	}
+	fail := func(err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < u.concurrency(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for part := range parts {
+				if failed() {
+					continue
+				}
+
+				err := upload.uploadPart(part.number, part.contents)
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	contents, more := first, true
+	for number := 1; len(contents) > 0 && !failed(); number++ {
+		if number > MaxParts {
+			fail(tooManyPartsError)
+			break
+		}
+		parts <- numberedPart{number: number, contents: contents}
+		if !more {
+			break
+		}
+
+		var err error
+		contents, more, err = readPart(r, u.partSize())
+		if err != nil {
+			fail(err)
+		}
+	}
+	close(parts)
+	wg.Wait()
+
+	return firstErr
+}
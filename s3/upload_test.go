@@ -0,0 +1,147 @@
+package s3
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testUpload is what a testUploadHandler was sent.
+type testUpload struct {
+	lock    sync.Mutex
+	puts    []string          // The bodies of PutObject requests
+	parts   map[string]string // The bodies of parts, by part number
+	aborted bool
+}
+
+// testUploadHandler acts like S3 for uploads. It fails parts with the number failPart.
+func testUploadHandler(upload *testUpload, failPart string) http.HandlerFunc {
+	upload.parts = map[string]string{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		query := r.URL.Query()
+
+		upload.lock.Lock()
+		defer upload.lock.Unlock()
+		switch {
+		case r.Method == "POST" && query.Get("uploadId") == "":
+			w.Write([]byte("<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>"))
+		case r.Method == "POST":
+			w.Write([]byte(`<CompleteMultipartUploadResult><ETag>"multipart"</ETag></CompleteMultipartUploadResult>`))
+		case r.Method == "DELETE":
+			upload.aborted = true
+			w.WriteHeader(204)
+		case failPart != "" && query.Get("partNumber") == failPart:
+			w.WriteHeader(400)
+			w.Write([]byte("<Error><Code>InvalidDigest</Code><Message>The Content-MD5 you specified was invalid.</Message></Error>"))
+		case query.Get("partNumber") != "":
+			upload.parts[query.Get("partNumber")] = string(body)
+			w.Header().Set("ETag", `"part`+query.Get("partNumber")+`"`)
+		default:
+			upload.puts = append(upload.puts, string(body))
+			w.Header().Set("ETag", `"single"`)
+		}
+	}
+}
+
+// testFailingReader returns an error after reading contents.
+type testFailingReader struct {
+	contents io.Reader
+}
+
+func (r *testFailingReader) Read(p []byte) (int, error) {
+	n, err := r.contents.Read(p)
+	if err == io.EOF {
+		return n, errors.New("broken pipe")
+	}
+	return n, err
+}
+
+func TestUpload(t *testing.T) {
+	Convey("Given a reader smaller than a part", t, func() {
+		upload := testUpload{}
+		ts, b := testBucket(testUploadHandler(&upload, ""))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		etag, err := (&Uploader{PartSize: 100}).Upload(&o, bytes.NewReader(testContents), PutOptions{})
+
+		Convey("It is uploaded in one request", func() {
+			So(err, ShouldBeNil)
+			So(etag, ShouldEqual, `"single"`)
+			So(upload.puts, ShouldResemble, []string{string(testContents)})
+			So(len(upload.parts), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a reader larger than a part", t, func() {
+		upload := testUpload{}
+		ts, b := testBucket(testUploadHandler(&upload, ""))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		etag, err := (&Uploader{PartSize: 10, Concurrency: 2}).Upload(&o, bytes.NewReader(testContents), PutOptions{})
+
+		Convey("It is uploaded in parts", func() {
+			So(err, ShouldBeNil)
+			So(etag, ShouldEqual, `"multipart"`)
+			So(len(upload.puts), ShouldEqual, 0)
+			So(upload.parts, ShouldResemble, map[string]string{
+				"1": "The quick ",
+				"2": "brown fox ",
+				"3": "jumps over",
+				"4": " the lazy ",
+				"5": "dog.",
+			})
+		})
+	})
+	Convey("Given a part that fails", t, func() {
+		upload := testUpload{}
+		ts, b := testBucket(testUploadHandler(&upload, "2"))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		_, err := (&Uploader{PartSize: 10}).Upload(&o, bytes.NewReader(testContents), PutOptions{})
+
+		Convey("It returns the error and aborts the upload", func() {
+			So(err.(s3Error).Code, ShouldEqual, "InvalidDigest")
+			So(upload.aborted, ShouldBeTrue)
+		})
+	})
+	Convey("Given a reader that fails", t, func() {
+		upload := testUpload{}
+		ts, b := testBucket(testUploadHandler(&upload, ""))
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		_, err := (&Uploader{PartSize: 10}).Upload(&o, &testFailingReader{strings.NewReader(string(testContents))}, PutOptions{})
+
+		Convey("It returns the error and aborts the upload", func() {
+			So(err.Error(), ShouldEqual, "broken pipe")
+			So(upload.aborted, ShouldBeTrue)
+		})
+	})
+}
+
+func TestUploadDryRun(t *testing.T) {
+	Convey("Given a bucket of a client in dry-run mode and a reader larger than a part", t, func() {
+		ts, b, logged, requests := testDryRunBucket()
+		defer ts.Close()
+		o := b.Object("fox.txt")
+
+		_, err := (&Uploader{PartSize: 10, Concurrency: 2}).Upload(&o, bytes.NewReader(testContents), PutOptions{})
+
+		Convey("The parts would be uploaded and put together, and are not", func() {
+			So(err, ShouldBeNil)
+			So(strings.Count(logged.String(), "partNumber="), ShouldEqual, 5)
+			So(logged.String(), ShouldContainSubstring, "<CompleteMultipartUpload>")
+			So(*requests, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,55 @@
+package ses
+
+import (
+	"encoding/xml"
+)
+
+// Email is an email to send.
+type Email struct {
+	Source           string // Who the email is from, like "Alerts <alerts@example.com>". It must be verified with SES.
+	Destination      Destination
+	ReplyTo          []string
+	Subject          string
+	Text             string // The plain text body
+	HTML             string // The HTML body. Either or both of Text and HTML can be set.
+	ConfigurationSet string // The configuration set to use when sending, if any
+}
+
+type sendEmailResponse struct {
+	MessageId string `xml:"SendEmailResult>MessageId"`
+}
+
+// SendEmail sends an email, and returns the message ID. Text is sent in UTF-8. It is calling the SendEmail API call.
+// See http://docs.aws.amazon.com/ses/latest/APIReference/API_SendEmail.html for more details.
+func (s *SESService) SendEmail(e Email) (string, error) {
+	result := sendEmailResponse{}
+
+	params := query("SendEmail")
+	params.Set("Source", e.Source)
+	e.Destination.setParams(params)
+	setList(params, "ReplyToAddresses", e.ReplyTo)
+	params.Set("Message.Subject.Data", e.Subject)
+	params.Set("Message.Subject.Charset", "UTF-8")
+	if e.Text != "" {
+		params.Set("Message.Body.Text.Data", e.Text)
+		params.Set("Message.Body.Text.Charset", "UTF-8")
+	}
+	if e.HTML != "" {
+		params.Set("Message.Body.Html.Data", e.HTML)
+		params.Set("Message.Body.Html.Charset", "UTF-8")
+	}
+	if e.ConfigurationSet != "" {
+		params.Set("ConfigurationSetName", e.ConfigurationSet)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.MessageId, err
+}
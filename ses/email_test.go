@@ -0,0 +1,83 @@
+package ses
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testSendEmail(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`<SendEmailResponse><SendEmailResult><MessageId>00000137-a7d4-4b4a-b0e1-EXAMPLE-000000</MessageId></SendEmailResult></SendEmailResponse>`))
+}
+
+func TestSendEmail(t *testing.T) {
+	Convey("Given an email", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSendEmail, &params)
+		defer ts.Close()
+
+		id, err := s.SendEmail(Email{
+			Source:           "Alerts <alerts@example.com>",
+			Destination:      Destination{ToAddresses: []string{"ops@example.com"}, BccAddresses: []string{"audit@example.com"}},
+			ReplyTo:          []string{"noreply@example.com"},
+			Subject:          "Stream is behind",
+			Text:             "The stream is 5 minutes behind.",
+			HTML:             "<p>The stream is 5 minutes behind.</p>",
+			ConfigurationSet: "alerts",
+		})
+
+		Convey("It returns the message ID", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "00000137-a7d4-4b4a-b0e1-EXAMPLE-000000")
+		})
+		Convey("It sends the email", func() {
+			So(params[0].Get("Action"), ShouldEqual, "SendEmail")
+			So(params[0].Get("Version"), ShouldEqual, "2010-12-01")
+			So(params[0].Get("Source"), ShouldEqual, "Alerts <alerts@example.com>")
+			So(params[0].Get("Destination.ToAddresses.member.1"), ShouldEqual, "ops@example.com")
+			So(params[0].Get("Destination.BccAddresses.member.1"), ShouldEqual, "audit@example.com")
+			So(params[0].Get("ReplyToAddresses.member.1"), ShouldEqual, "noreply@example.com")
+			So(params[0].Get("Message.Subject.Data"), ShouldEqual, "Stream is behind")
+			So(params[0].Get("Message.Subject.Charset"), ShouldEqual, "UTF-8")
+			So(params[0].Get("Message.Body.Text.Data"), ShouldEqual, "The stream is 5 minutes behind.")
+			So(params[0].Get("Message.Body.Html.Data"), ShouldEqual, "<p>The stream is 5 minutes behind.</p>")
+			So(params[0].Get("ConfigurationSetName"), ShouldEqual, "alerts")
+		})
+	})
+	Convey("Given an email with only text", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSendEmail, &params)
+		defer ts.Close()
+
+		s.SendEmail(Email{Source: "alerts@example.com", Subject: "Hi", Text: "Hello"})
+
+		Convey("No HTML body or configuration set is sent", func() {
+			_, hasHTML := params[0]["Message.Body.Html.Data"]
+			So(hasHTML, ShouldBeFalse)
+			_, hasSet := params[0]["ConfigurationSetName"]
+			So(hasSet, ShouldBeFalse)
+		})
+	})
+	Convey("Given a server that rejects the email", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.SendEmail(Email{Source: "alerts@example.com"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, messageRejectedError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.SendEmail(Email{Source: "alerts@example.com"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,18 @@
+// Code generated by errgen from the go:generate line in ses.go. DO NOT EDIT.
+
+package ses
+
+// These are errors SES returns. errors.Is(err, ses.ErrMessageRejected) is true for any error with the same code, whatever its message.
+var (
+	ErrMessageRejected              = sesError{Code: "MessageRejected"}
+	ErrMailFromDomainNotVerified    = sesError{Code: "MailFromDomainNotVerifiedException"}
+	ErrConfigurationSetDoesNotExist = sesError{Code: "ConfigurationSetDoesNotExist"}
+	ErrAlreadyExists                = sesError{Code: "AlreadyExists"}
+	ErrLimitExceeded                = sesError{Code: "LimitExceeded"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e sesError) Is(target error) bool {
+	t, ok := target.(sesError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
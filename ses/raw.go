@@ -0,0 +1,203 @@
+package ses
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// Attachment is a file attached to an email.
+type Attachment struct {
+	Name        string // The file name
+	ContentType string // The MIME type, like application/pdf. If it is empty, application/octet-stream is used.
+	Data        []byte
+}
+
+// MIME returns the email as a MIME message with the attachments, for SendRawEmail.
+// Bcc addresses are left out of the headers, so they have to be passed to SendRawEmail as destinations.
+func (e Email) MIME(attachments ...Attachment) ([]byte, error) {
+	// The text and HTML bodies are alternatives to each other, and the attachments are mixed in with them.
+	body := &bytes.Buffer{}
+	alternative := multipart.NewWriter(body)
+	if e.Text != "" {
+		err := writeText(alternative, "text/plain", e.Text)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if e.HTML != "" {
+		err := writeText(alternative, "text/html", e.HTML)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err := alternative.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	message := &bytes.Buffer{}
+	mixed := multipart.NewWriter(message)
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", e.Source)
+	if len(e.Destination.ToAddresses) > 0 {
+		header.Set("To", strings.Join(e.Destination.ToAddresses, ", "))
+	}
+	if len(e.Destination.CcAddresses) > 0 {
+		header.Set("Cc", strings.Join(e.Destination.CcAddresses, ", "))
+	}
+	if len(e.ReplyTo) > 0 {
+		header.Set("Reply-To", strings.Join(e.ReplyTo, ", "))
+	}
+	header.Set("Subject", mime.QEncoding.Encode("UTF-8", e.Subject))
+	header.Set("MIME-Version", "1.0")
+	header.Set("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()}))
+	writeHeader(message, header)
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": alternative.Boundary()})},
+	})
+	if err != nil {
+		return nil, err
+	}
+	_, err = part.Write(body.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		err = writeAttachment(mixed, a)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = mixed.Close()
+	return message.Bytes(), err
+}
+
+// writeHeader writes the header of a message, sorted so it is always written the same way.
+func writeHeader(w io.Writer, header textproto.MIMEHeader) {
+	keys := []string{}
+	for key := range header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(w, "%v: %v\r\n", key, value)
+		}
+	}
+	fmt.Fprint(w, "\r\n")
+}
+
+// writeText writes a UTF-8 body, quoted-printable encoded.
+func writeText(w *multipart.Writer, contentType string, text string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"charset": "UTF-8"})},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	_, err = qp.Write([]byte(text))
+	if err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeAttachment writes an attachment, base64 encoded in lines of 76 characters.
+func writeAttachment(w *multipart.Writer, a Attachment) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": a.Name})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(a.Data)
+	for len(encoded) > 76 {
+		_, err = fmt.Fprintf(part, "%v\r\n", encoded[:76])
+		if err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = fmt.Fprintf(part, "%v\r\n", encoded)
+	return err
+}
+
+// RawEmail is a MIME message to send, like one returned by Email.MIME.
+type RawEmail struct {
+	Source           string   // Who the email is from. If it is empty, the From header of the message is used.
+	Destinations     []string // Who the email is sent to. If it is empty, the To, Cc and Bcc headers of the message are used.
+	Data             []byte   // The MIME message, headers and all
+	ConfigurationSet string   // The configuration set to use when sending, if any
+}
+
+type sendRawEmailResponse struct {
+	MessageId string `xml:"SendRawEmailResult>MessageId"`
+}
+
+// SendRawEmail sends a MIME message, and returns the message ID. It is calling the SendRawEmail API call.
+// See http://docs.aws.amazon.com/ses/latest/APIReference/API_SendRawEmail.html for more details.
+func (s *SESService) SendRawEmail(e RawEmail) (string, error) {
+	result := sendRawEmailResponse{}
+
+	params := query("SendRawEmail")
+	params.Set("RawMessage.Data", base64.StdEncoding.EncodeToString(e.Data))
+	if e.Source != "" {
+		params.Set("Source", e.Source)
+	}
+	setList(params, "Destinations", e.Destinations)
+	if e.ConfigurationSet != "" {
+		params.Set("ConfigurationSetName", e.ConfigurationSet)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.MessageId, err
+}
+
+// SendMIMEEmail sends an email with attachments as a MIME message, and returns the message ID.
+// Bcc addresses are sent the email, but are not in its headers.
+func (s *SESService) SendMIMEEmail(e Email, attachments ...Attachment) (string, error) {
+	data, err := e.MIME(attachments...)
+	if err != nil {
+		return "", err
+	}
+
+	return s.SendRawEmail(RawEmail{
+		Source:           e.Source,
+		Destinations:     e.Destination.Addresses(),
+		Data:             data,
+		ConfigurationSet: e.ConfigurationSet,
+	})
+}
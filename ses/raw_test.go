@@ -0,0 +1,181 @@
+package ses
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/mail"
+	"net/textproto"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testSendRawEmail(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(`<SendRawEmailResponse><SendRawEmailResult><MessageId>00000131d51d2292-159ad6eb-077c-46e6-ad09-ae7c05925ed4-000000</MessageId></SendRawEmailResult></SendRawEmailResponse>`))
+}
+
+var testEmail = Email{
+	Source:      "Alerts <alerts@example.com>",
+	Destination: Destination{ToAddresses: []string{"ops@example.com"}, CcAddresses: []string{"dev@example.com"}, BccAddresses: []string{"audit@example.com"}},
+	ReplyTo:     []string{"noreply@example.com"},
+	Subject:     "Stream is behind ☃",
+	Text:        "The stream is 5 minutes behind.",
+	HTML:        "<p>The stream is 5 minutes behind.</p>",
+}
+
+// testPart is a part of a multipart body.
+type testPart struct {
+	Header textproto.MIMEHeader
+	Body   []byte
+}
+
+// testParts returns the parts of a multipart body.
+func testParts(contentType string, body []byte) []testPart {
+	_, params, err := mime.ParseMediaType(contentType)
+	So(err, ShouldBeNil)
+
+	parts := []testPart{}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return parts
+		}
+		data, _ := ioutil.ReadAll(part)
+		parts = append(parts, testPart{Header: part.Header, Body: data})
+	}
+}
+
+func TestMIME(t *testing.T) {
+	Convey("Given an email with an attachment", t, func() {
+		data := bytes.Repeat([]byte("offset,lag\n"), 20)
+		raw, err := testEmail.MIME(Attachment{Name: "lag.csv", ContentType: "text/csv", Data: data})
+		So(err, ShouldBeNil)
+
+		message, err := mail.ReadMessage(bytes.NewReader(raw))
+		So(err, ShouldBeNil)
+
+		Convey("The headers are set", func() {
+			So(message.Header.Get("From"), ShouldEqual, "Alerts <alerts@example.com>")
+			So(message.Header.Get("To"), ShouldEqual, "ops@example.com")
+			So(message.Header.Get("Cc"), ShouldEqual, "dev@example.com")
+			So(message.Header.Get("Reply-To"), ShouldEqual, "noreply@example.com")
+			So(message.Header.Get("MIME-Version"), ShouldEqual, "1.0")
+		})
+		Convey("Bcc addresses are left out", func() {
+			So(message.Header.Get("Bcc"), ShouldEqual, "")
+			So(string(raw), ShouldNotContainSubstring, "audit@example.com")
+		})
+		Convey("The subject is encoded", func() {
+			subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+			So(err, ShouldBeNil)
+			So(subject, ShouldEqual, "Stream is behind ☃")
+		})
+		Convey("The bodies and attachment are parts", func() {
+			body, _ := ioutil.ReadAll(message.Body)
+			mixed := testParts(message.Header.Get("Content-Type"), body)
+			So(len(mixed), ShouldEqual, 2)
+
+			bodies := testParts(mixed[0].Header.Get("Content-Type"), mixed[0].Body)
+			So(len(bodies), ShouldEqual, 2)
+			So(bodies[0].Header.Get("Content-Type"), ShouldEqual, "text/plain; charset=UTF-8")
+			So(string(bodies[0].Body), ShouldEqual, "The stream is 5 minutes behind.")
+			So(bodies[1].Header.Get("Content-Type"), ShouldEqual, "text/html; charset=UTF-8")
+			So(string(bodies[1].Body), ShouldEqual, "<p>The stream is 5 minutes behind.</p>")
+
+			So(mixed[1].Header.Get("Content-Disposition"), ShouldEqual, "attachment; filename=lag.csv")
+			So(mixed[1].Header.Get("Content-Type"), ShouldEqual, "text/csv; name=lag.csv")
+			decoded, err := base64.StdEncoding.DecodeString(string(bytes.Replace(mixed[1].Body, []byte("\r\n"), nil, -1)))
+			So(err, ShouldBeNil)
+			So(decoded, ShouldResemble, data)
+		})
+	})
+	Convey("Given an attachment without a content type", t, func() {
+		raw, err := Email{Source: "alerts@example.com", Text: "Hi"}.MIME(Attachment{Name: "dump", Data: []byte{0, 1, 2}})
+
+		Convey("It is an octet stream", func() {
+			So(err, ShouldBeNil)
+			So(string(raw), ShouldContainSubstring, "Content-Type: application/octet-stream; name=dump")
+		})
+	})
+}
+
+func TestSendRawEmail(t *testing.T) {
+	Convey("Given a raw email", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSendRawEmail, &params)
+		defer ts.Close()
+
+		id, err := s.SendRawEmail(RawEmail{
+			Source:           "alerts@example.com",
+			Destinations:     []string{"ops@example.com", "audit@example.com"},
+			Data:             []byte("Subject: Hi\r\n\r\nHello"),
+			ConfigurationSet: "alerts",
+		})
+
+		Convey("It returns the message ID", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "00000131d51d2292-159ad6eb-077c-46e6-ad09-ae7c05925ed4-000000")
+		})
+		Convey("It sends the message base64 encoded", func() {
+			So(params[0].Get("Action"), ShouldEqual, "SendRawEmail")
+			So(params[0].Get("RawMessage.Data"), ShouldEqual, base64.StdEncoding.EncodeToString([]byte("Subject: Hi\r\n\r\nHello")))
+			So(params[0].Get("Source"), ShouldEqual, "alerts@example.com")
+			So(params[0].Get("Destinations.member.1"), ShouldEqual, "ops@example.com")
+			So(params[0].Get("Destinations.member.2"), ShouldEqual, "audit@example.com")
+			So(params[0].Get("ConfigurationSetName"), ShouldEqual, "alerts")
+		})
+	})
+	Convey("Given a raw email with no source or destinations", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSendRawEmail, &params)
+		defer ts.Close()
+
+		s.SendRawEmail(RawEmail{Data: []byte("Subject: Hi\r\n\r\nHello")})
+
+		Convey("The headers are left to SES", func() {
+			_, hasSource := params[0]["Source"]
+			So(hasSource, ShouldBeFalse)
+			_, hasDestinations := params[0]["Destinations.member.1"]
+			So(hasDestinations, ShouldBeFalse)
+		})
+	})
+	Convey("Given a server that rejects the email", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.SendRawEmail(RawEmail{Data: []byte("Hello")})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, messageRejectedError)
+		})
+	})
+}
+
+func TestSendMIMEEmail(t *testing.T) {
+	Convey("Given an email with an attachment", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testSendRawEmail, &params)
+		defer ts.Close()
+
+		_, err := s.SendMIMEEmail(testEmail, Attachment{Name: "lag.csv", Data: []byte("offset,lag\n")})
+
+		Convey("It is sent to every destination, Bcc included", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Source"), ShouldEqual, "Alerts <alerts@example.com>")
+			So(params[0]["Destinations.member.3"], ShouldResemble, []string{"audit@example.com"})
+		})
+		Convey("The message is the MIME of the email", func() {
+			data, err := base64.StdEncoding.DecodeString(params[0].Get("RawMessage.Data"))
+			So(err, ShouldBeNil)
+			message, err := mail.ReadMessage(bytes.NewReader(data))
+			So(err, ShouldBeNil)
+			So(message.Header.Get("To"), ShouldEqual, "ops@example.com")
+		})
+	})
+}
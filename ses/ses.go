@@ -0,0 +1,122 @@
+// Package ses provides a way to interact with the AWS Simple Email Service.
+package ses
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type sesError -field Code -service SES MessageRejected MailFromDomainNotVerifiedException ConfigurationSetDoesNotExist AlreadyExists LimitExceeded
+
+// sesError is the error document returned from the SES service.
+type sesError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the sesError into an error message.
+func (e sesError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func sesRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := sesError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	// Throttling is also returned when the maximum sending rate is exceeded.
+	if error.Code == "Throttling" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// SESService is the SES service at AWS. The endpoint for a region is gaws.Endpoint("email", region).
+type SESService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// SESAPI is implemented by *SESService. Code that takes an SESAPI can be given a fake in tests instead.
+type SESAPI interface {
+	SendEmail(e Email) (string, error)
+	SendMIMEEmail(e Email, attachments ...Attachment) (string, error)
+	SendRawEmail(e RawEmail) (string, error)
+}
+
+var _ SESAPI = (*SESService)(nil)
+
+// New returns an SESService with the options applied. Its endpoint is gaws.Endpoint("email", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *SESService {
+	config := gaws.NewConfig(options...)
+	return &SESService{Endpoint: config.ServiceEndpoint("email"), config: config}
+}
+
+func (s *SESService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: sesRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("ses", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an SES API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-12-01"},
+	}
+}
+
+// setList sets a list parameter, like ReplyToAddresses.member.1.
+func setList(params url.Values, name string, values []string) {
+	for i, value := range values {
+		params.Set(fmt.Sprintf("%v.member.%d", name, i+1), value)
+	}
+}
+
+// Destination is who an email is sent to.
+type Destination struct {
+	ToAddresses  []string
+	CcAddresses  []string
+	BccAddresses []string
+}
+
+// Addresses returns every address in the destination.
+func (d Destination) Addresses() []string {
+	addresses := []string{}
+	addresses = append(addresses, d.ToAddresses...)
+	addresses = append(addresses, d.CcAddresses...)
+	addresses = append(addresses, d.BccAddresses...)
+	return addresses
+}
+
+func (d Destination) setParams(params url.Values) {
+	setList(params, "Destination.ToAddresses", d.ToAddresses)
+	setList(params, "Destination.CcAddresses", d.CcAddresses)
+	setList(params, "Destination.BccAddresses", d.BccAddresses)
+}
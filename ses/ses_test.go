@@ -0,0 +1,117 @@
+package ses
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<SendEmailResponse><SendEmailResult>"))
+}
+
+var testMessageRejectedError = []byte(`<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>MessageRejected</Code>
+    <Message>Email address is not verified.</Message>
+  </Error>
+  <RequestId>9dd01905-5012-5f99-8663-4b3ecd0dfaef</RequestId>
+</ErrorResponse>`)
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(400)
+	w.Write(testMessageRejectedError)
+}
+
+var messageRejectedError = sesError{Type: "Sender", Code: "MessageRejected", Message: "Email address is not verified."}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, SESService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, SESService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := sesRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := sesRetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalFailure</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := sesRetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code><Message>Maximum sending rate exceeded.</Message></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the message was rejected", t, func() {
+		result, err := sesRetryPredicate(400, testMessageRejectedError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, messageRejectedError)
+		})
+	})
+}
+
+func TestDestination(t *testing.T) {
+	Convey("Given a destination", t, func() {
+		d := Destination{
+			ToAddresses:  []string{"a@example.com", "b@example.com"},
+			CcAddresses:  []string{"c@example.com"},
+			BccAddresses: []string{"d@example.com"},
+		}
+
+		Convey("Addresses returns every address", func() {
+			So(d.Addresses(), ShouldResemble, []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"})
+		})
+		Convey("Its parameters are lists", func() {
+			params := url.Values{}
+			d.setParams(params)
+			So(params, ShouldResemble, url.Values{
+				"Destination.ToAddresses.member.1":  {"a@example.com"},
+				"Destination.ToAddresses.member.2":  {"b@example.com"},
+				"Destination.CcAddresses.member.1":  {"c@example.com"},
+				"Destination.BccAddresses.member.1": {"d@example.com"},
+			})
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("email", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
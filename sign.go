@@ -0,0 +1,178 @@
+package gaws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// signingScope is what a signature version 4 signing key is derived for.
+type signingScope struct {
+	secret  string
+	date    string
+	region  string
+	service string
+}
+
+// signingKeyCache keeps the signing keys of the current day, so that they are not derived with four HMACs for every request.
+// Keys are only good for the day they were derived for, so the cache is emptied when the day changes.
+type signingKeyCache struct {
+	lock sync.Mutex
+	date string
+	keys map[signingScope][]byte
+}
+
+// signingKeys are the signing keys gaws has derived today.
+var signingKeys = &signingKeyCache{}
+
+// key returns the signing key for a secret access key, date, region and service.
+func (c *signingKeyCache) key(secret string, date string, region string, service string) []byte {
+	scope := signingScope{secret: secret, date: date, region: region, service: service}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if key, ok := c.keys[scope]; ok {
+		return key
+	}
+
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	if date > c.date {
+		c.date = date
+		c.keys = map[signingScope][]byte{}
+	}
+	if date == c.date {
+		c.keys[scope] = key
+	}
+	return key
+}
+
+// hostRegion returns the region in an AWS endpoint's host, like us-east-1 in kinesis.us-east-1.amazonaws.com or in a VPC endpoint's host,
+// or "" if it has none.
+func hostRegion(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if !strings.HasSuffix(host, ".amazonaws.com") {
+		return ""
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) < 4 {
+		return ""
+	}
+	if parts[len(parts)-3] == "vpce" && len(parts) >= 5 {
+		// VPC endpoints are like vpce-1a2b3c4d.kinesis.us-east-1.vpce.amazonaws.com.
+		return parts[len(parts)-4]
+	}
+	return parts[len(parts)-3]
+}
+
+// Sign4 returns a signer for AWSRequest.Signer that signs requests to a service with signature version 4, like awsauth.Sign4, but keeps
+// the signing keys it derives for the rest of the day. The region is the one in the endpoint's host, and if it has none, like a local
+// emulator, it is region, or Region if that is empty. Requests without credentials are signed with the ones in the environment, where
+// awsauth looks first, and if there are none there, by awsauth.Sign4, which gets the instance role's.
+// It signs every header the request has, so headers must not be added after it.
+func Sign4(service string, region string) func(*http.Request, ...awsauth.Credentials) *http.Request {
+	return func(req *http.Request, keys ...awsauth.Credentials) *http.Request {
+		if len(keys) == 0 {
+			env := envCredentials()
+			if env.AccessKeyID == "" {
+				return awsauth.Sign4(req)
+			}
+			keys = []awsauth.Credentials{env}
+		}
+
+		r := hostRegion(req.URL.Host)
+		if r == "" {
+			r = region
+		}
+		if r == "" {
+			r = Region
+		}
+		return sign4(req, service, r, DefaultClock.Now(), keys[0])
+	}
+}
+
+// sign4 signs the headers of a request as of t.
+func sign4(req *http.Request, service string, region string, t time.Time, keys awsauth.Credentials) *http.Request {
+	t = t.UTC()
+	date := t.Format("20060102")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+
+	req.Header.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	if keys.SecurityToken != "" {
+		req.Header.Set("X-Amz-Security-Token", keys.SecurityToken)
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		if b, err := req.GetBody(); err == nil {
+			body, _ = ioutil.ReadAll(b)
+			b.Close()
+		}
+	}
+	payloadHash := sha256.Sum256(body)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	canonicalHeaders := ""
+	for _, name := range names {
+		canonicalHeaders += name + ":" + headers[name] + "\n"
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	hash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		t.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := signingKeys.key(keys.SecretAccessKey, date, region, service)
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+keys.AccessKeyID+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+	return req
+}
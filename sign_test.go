@@ -0,0 +1,148 @@
+package gaws
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testSigningKeys are the credentials of the AWS signature version 4 test suite.
+var testSigningKeys = awsauth.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"}
+
+func TestSigningKeys(t *testing.T) {
+	Convey("Given the key derivation from the AWS documentation", t, func() {
+		c := &signingKeyCache{}
+		key := c.key("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+
+		Convey("It derives the same key", func() {
+			So(hex.EncodeToString(key), ShouldEqual, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d")
+		})
+		Convey("It keeps it for the day", func() {
+			So(len(c.keys), ShouldEqual, 1)
+			again := c.key("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+			So(&again[0], ShouldEqual, &key[0])
+		})
+		Convey("It forgets it the next day", func() {
+			c.key("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120216", "us-east-1", "iam")
+			So(len(c.keys), ShouldEqual, 1)
+			So(c.date, ShouldEqual, "20120216")
+		})
+		Convey("It does not keep keys for an earlier day", func() {
+			c.key("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120214", "us-east-1", "iam")
+			So(len(c.keys), ShouldEqual, 1)
+			So(c.date, ShouldEqual, "20120215")
+		})
+	})
+}
+
+func TestSign4(t *testing.T) {
+	Convey("Given the get-vanilla request from the AWS test suite", t, func() {
+		req, _ := http.NewRequest("GET", "https://example.amazonaws.com/", nil)
+		sign4(req, "service", "us-east-1", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC), testSigningKeys)
+
+		Convey("It has the same signature as the test suite", func() {
+			So(req.Header.Get("X-Amz-Date"), ShouldEqual, "20150830T123600Z")
+			So(req.Header.Get("Authorization"), ShouldEqual, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+				"SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31")
+		})
+	})
+	Convey("Given a request with a body and temporary credentials", t, func() {
+		req, _ := http.NewRequest("POST", "https://kinesis.eu-west-1.amazonaws.com", bytes.NewReader([]byte(`{"StreamName":"foo"}`)))
+		req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+		req.Header.Set("X-Amz-Target", "Kinesis_20131202.DescribeStream")
+		keys := testSigningKeys
+		keys.SecurityToken = "TOKEN"
+		Sign4("kinesis", "")(req, keys)
+
+		Convey("Every header is signed, with the token", func() {
+			So(req.Header.Get("X-Amz-Security-Token"), ShouldEqual, "TOKEN")
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target,")
+		})
+		Convey("The region is the one in the host", func() {
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/eu-west-1/kinesis/aws4_request")
+		})
+	})
+	Convey("Given a request to a local endpoint", t, func() {
+		req, _ := http.NewRequest("POST", "http://localhost:4566", nil)
+		Sign4("kinesis", "ap-southeast-2")(req, testSigningKeys)
+
+		Convey("The region is the one it was given", func() {
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/ap-southeast-2/kinesis/aws4_request")
+		})
+		Convey("Without a region, it is Region", func() {
+			req, _ := http.NewRequest("POST", "http://localhost:4566", nil)
+			Sign4("kinesis", "")(req, testSigningKeys)
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/"+Region+"/kinesis/aws4_request")
+		})
+	})
+}
+
+func TestSign4DefaultCredentials(t *testing.T) {
+	Convey("Given no credentials but the ones in the environment", t, func() {
+		for name, value := range map[string]string{"AWS_ACCESS_KEY_ID": testSigningKeys.AccessKeyID, "AWS_SECRET_ACCESS_KEY": testSigningKeys.SecretAccessKey, "AWS_SESSION_TOKEN": ""} {
+			old, ok := os.LookupEnv(name)
+			os.Setenv(name, value)
+			if ok {
+				defer os.Setenv(name, old)
+			} else {
+				defer os.Unsetenv(name)
+			}
+		}
+		keys := signingKeys
+		signingKeys = &signingKeyCache{}
+		defer func() { signingKeys = keys }()
+
+		req, _ := http.NewRequest("POST", "https://sqs.us-west-2.amazonaws.com", nil)
+		Sign4("sqs", "")(req)
+
+		Convey("The request is signed with them", func() {
+			So(req.Header.Get("Authorization"), ShouldStartWith, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/")
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/us-west-2/sqs/aws4_request")
+		})
+		Convey("The signing key is kept, and used for the next request", func() {
+			So(len(signingKeys.keys), ShouldEqual, 1)
+			var key []byte
+			for _, k := range signingKeys.keys {
+				key = k
+			}
+
+			again, _ := http.NewRequest("POST", "https://sqs.us-west-2.amazonaws.com", nil)
+			Sign4("sqs", "")(again)
+			So(len(signingKeys.keys), ShouldEqual, 1)
+			for _, k := range signingKeys.keys {
+				So(&k[0], ShouldEqual, &key[0])
+			}
+		})
+	})
+}
+
+func TestHostRegion(t *testing.T) {
+	Convey("Given hosts", t, func() {
+		Convey("The region of an AWS endpoint is found", func() {
+			So(hostRegion("kinesis.us-east-1.amazonaws.com"), ShouldEqual, "us-east-1")
+			So(hostRegion("dynamodb.eu-west-1.amazonaws.com:443"), ShouldEqual, "eu-west-1")
+			So(hostRegion("vpce-1a2b3c4d.kinesis.us-east-1.vpce.amazonaws.com"), ShouldEqual, "us-east-1")
+		})
+		Convey("Other hosts have none", func() {
+			So(hostRegion("s3.amazonaws.com"), ShouldEqual, "")
+			So(hostRegion("localhost:4566"), ShouldEqual, "")
+		})
+	})
+}
+
+func BenchmarkSign4(b *testing.B) {
+	body := []byte(`{"StreamName":"foo","Data":"aGVsbG8=","PartitionKey":"bar"}`)
+	sign := Sign4("kinesis", "")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest("POST", "https://kinesis.us-east-1.amazonaws.com", bytes.NewReader(body))
+		req.Header.Set("X-Amz-Target", "Kinesis_20131202.PutRecord")
+		sign(req, testSigningKeys)
+	}
+}
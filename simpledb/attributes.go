@@ -0,0 +1,113 @@
+package simpledb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+// Attribute is a name and value of an item. An item can have several values for the same name.
+type Attribute struct {
+	Name    string
+	Value   string
+	Replace bool `xml:"-"` // Whether PutAttributes replaces the values the name has, instead of adding to them
+}
+
+// Item is a named set of attributes in a domain.
+type Item struct {
+	Name       string
+	Attributes []Attribute `xml:"Attribute"`
+}
+
+// Get returns the first value of the named attribute, or "" if the item does not have it.
+func (i Item) Get(name string) string {
+	for _, attribute := range i.Attributes {
+		if attribute.Name == name {
+			return attribute.Value
+		}
+	}
+	return ""
+}
+
+// Values returns every value of the named attribute.
+func (i Item) Values(name string) []string {
+	values := []string{}
+	for _, attribute := range i.Attributes {
+		if attribute.Name == name {
+			values = append(values, attribute.Value)
+		}
+	}
+	return values
+}
+
+// setAttributes sets the Attribute.N parameters. Values are left out if they are empty, so DeleteAttributes deletes every value of the name.
+func setAttributes(params url.Values, attributes []Attribute) {
+	for i, attribute := range attributes {
+		prefix := fmt.Sprintf("Attribute.%v.", i+1)
+		params.Set(prefix+"Name", attribute.Name)
+		if attribute.Value != "" {
+			params.Set(prefix+"Value", attribute.Value)
+		}
+		if attribute.Replace {
+			params.Set(prefix+"Replace", "true")
+		}
+	}
+}
+
+// PutAttributes adds attributes to an item, creating it if it does not exist. It is calling the PutAttributes API call.
+// See http://docs.aws.amazon.com/AmazonSimpleDB/latest/DeveloperGuide/SDB_API_PutAttributes.html for more details.
+func (s *SimpleDBService) PutAttributes(domain string, item string, attributes ...Attribute) error {
+	params := query("PutAttributes")
+	params.Set("DomainName", domain)
+	params.Set("ItemName", item)
+	setAttributes(params, attributes)
+
+	req := s.request(params)
+
+	_, err := req.Do()
+	return err
+}
+
+type getAttributesResponse struct {
+	Attributes []Attribute `xml:"GetAttributesResult>Attribute"`
+}
+
+// GetAttributes returns the attributes of an item, or only the named ones if there are names. An item that does not exist has no attributes.
+// consistent asks for a consistent read, which sees every write that finished before it. It is calling the GetAttributes API call.
+// See http://docs.aws.amazon.com/AmazonSimpleDB/latest/DeveloperGuide/SDB_API_GetAttributes.html for more details.
+func (s *SimpleDBService) GetAttributes(domain string, item string, consistent bool, names ...string) (Item, error) {
+	result := getAttributesResponse{}
+
+	params := query("GetAttributes")
+	params.Set("DomainName", domain)
+	params.Set("ItemName", item)
+	for i, name := range names {
+		params.Set(fmt.Sprintf("AttributeName.%v", i+1), name)
+	}
+	setConsistentRead(params, consistent)
+
+	req := s.request(params)
+
+	resp, err := req.Do()
+	if err != nil {
+		return Item{Name: item}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return Item{Name: item, Attributes: result.Attributes}, err
+}
+
+// DeleteAttributes deletes attributes from an item. An attribute without a value deletes every value of its name,
+// and no attributes deletes the whole item. It is calling the DeleteAttributes API call.
+// See http://docs.aws.amazon.com/AmazonSimpleDB/latest/DeveloperGuide/SDB_API_DeleteAttributes.html for more details.
+func (s *SimpleDBService) DeleteAttributes(domain string, item string, attributes ...Attribute) error {
+	params := query("DeleteAttributes")
+	params.Set("DomainName", domain)
+	params.Set("ItemName", item)
+	setAttributes(params, attributes)
+
+	req := s.request(params)
+
+	_, err := req.Do()
+	return err
+}
@@ -0,0 +1,146 @@
+package simpledb
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestItem(t *testing.T) {
+	Convey("Given an item with an attribute that has several values", t, func() {
+		item := Item{Name: "user-1", Attributes: []Attribute{
+			{Name: "email", Value: "a@example.com"},
+			{Name: "role", Value: "admin"},
+			{Name: "role", Value: "billing"},
+		}}
+
+		Convey("Get returns the first value", func() {
+			So(item.Get("role"), ShouldEqual, "admin")
+			So(item.Get("phone"), ShouldEqual, "")
+		})
+		Convey("Values returns every value", func() {
+			So(item.Values("role"), ShouldResemble, []string{"admin", "billing"})
+			So(item.Values("phone"), ShouldResemble, []string{})
+		})
+	})
+}
+
+func TestPutAttributes(t *testing.T) {
+	Convey("Given attributes, one of which replaces", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.PutAttributes("users", "user-1", Attribute{Name: "email", Value: "a@example.com", Replace: true}, Attribute{Name: "role", Value: "admin"})
+
+		Convey("They are put", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "PutAttributes")
+			So(params[0].Get("DomainName"), ShouldEqual, "users")
+			So(params[0].Get("ItemName"), ShouldEqual, "user-1")
+			So(params[0].Get("Attribute.1.Name"), ShouldEqual, "email")
+			So(params[0].Get("Attribute.1.Value"), ShouldEqual, "a@example.com")
+			So(params[0].Get("Attribute.1.Replace"), ShouldEqual, "true")
+			So(params[0].Get("Attribute.2.Name"), ShouldEqual, "role")
+			_, hasReplace := params[0]["Attribute.2.Replace"]
+			So(hasReplace, ShouldBeFalse)
+		})
+	})
+	Convey("Given a domain that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		err := s.PutAttributes("users", "user-1", Attribute{Name: "email", Value: "a@example.com"})
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchDomainError)
+		})
+	})
+}
+
+func TestGetAttributes(t *testing.T) {
+	Convey("Given an item", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<GetAttributesResponse><GetAttributesResult>
+			<Attribute><Name>email</Name><Value>a@example.com</Value></Attribute>
+			<Attribute><Name>role</Name><Value>admin</Value></Attribute>
+		</GetAttributesResult></GetAttributesResponse>`, ""), &params)
+		defer ts.Close()
+
+		item, err := s.GetAttributes("users", "user-1", true, "email", "role")
+
+		Convey("It returns the attributes", func() {
+			So(err, ShouldBeNil)
+			So(item, ShouldResemble, Item{Name: "user-1", Attributes: []Attribute{
+				{Name: "email", Value: "a@example.com"},
+				{Name: "role", Value: "admin"},
+			}})
+		})
+		Convey("It asks for the names with a consistent read", func() {
+			So(params[0].Get("Action"), ShouldEqual, "GetAttributes")
+			So(params[0].Get("DomainName"), ShouldEqual, "users")
+			So(params[0].Get("ItemName"), ShouldEqual, "user-1")
+			So(params[0].Get("AttributeName.1"), ShouldEqual, "email")
+			So(params[0].Get("AttributeName.2"), ShouldEqual, "role")
+			So(params[0].Get("ConsistentRead"), ShouldEqual, "true")
+		})
+	})
+	Convey("Given an eventually consistent read", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(`<GetAttributesResponse><GetAttributesResult></GetAttributesResult></GetAttributesResponse>`, ""), &params)
+		defer ts.Close()
+
+		item, err := s.GetAttributes("users", "user-2", false)
+
+		Convey("An item that does not exist has no attributes", func() {
+			So(err, ShouldBeNil)
+			So(len(item.Attributes), ShouldEqual, 0)
+			_, hasConsistentRead := params[0]["ConsistentRead"]
+			So(hasConsistentRead, ShouldBeFalse)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.GetAttributes("users", "user-1", false)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeleteAttributes(t *testing.T) {
+	Convey("Given an attribute with a value and one without", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		err := s.DeleteAttributes("users", "user-1", Attribute{Name: "role", Value: "billing"}, Attribute{Name: "email"})
+
+		Convey("The value and every value of the name are deleted", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "DeleteAttributes")
+			So(params[0].Get("Attribute.1.Name"), ShouldEqual, "role")
+			So(params[0].Get("Attribute.1.Value"), ShouldEqual, "billing")
+			So(params[0].Get("Attribute.2.Name"), ShouldEqual, "email")
+			_, hasValue := params[0]["Attribute.2.Value"]
+			So(hasValue, ShouldBeFalse)
+		})
+	})
+	Convey("Given no attributes", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		s.DeleteAttributes("users", "user-1")
+
+		Convey("The whole item is deleted", func() {
+			So(params[0].Get("ItemName"), ShouldEqual, "user-1")
+			_, hasAttributes := params[0]["Attribute.1.Name"]
+			So(hasAttributes, ShouldBeFalse)
+		})
+	})
+}
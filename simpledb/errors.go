@@ -0,0 +1,19 @@
+// Code generated by errgen from the go:generate line in simpledb.go. DO NOT EDIT.
+
+package simpledb
+
+// These are errors SimpleDB returns. errors.Is(err, simpledb.ErrNoSuchDomain) is true for any error with the same code, whatever its message.
+var (
+	ErrNoSuchDomain           = simpleDBError{Code: "NoSuchDomain"}
+	ErrAttributeDoesNotExist  = simpleDBError{Code: "AttributeDoesNotExist"}
+	ErrConditionalCheckFailed = simpleDBError{Code: "ConditionalCheckFailed"}
+	ErrNumberDomainsExceeded  = simpleDBError{Code: "NumberDomainsExceeded"}
+	ErrInvalidParameterValue  = simpleDBError{Code: "InvalidParameterValue"}
+	ErrMissingParameter       = simpleDBError{Code: "MissingParameter"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e simpleDBError) Is(target error) bool {
+	t, ok := target.(simpleDBError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
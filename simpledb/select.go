@@ -0,0 +1,44 @@
+package simpledb
+
+import (
+	"encoding/xml"
+)
+
+type selectResponse struct {
+	Items     []Item `xml:"SelectResult>Item"`
+	NextToken string `xml:"SelectResult>NextToken"`
+}
+
+// Select returns the items that match a select expression, like "select * from users where email = 'a@example.com'".
+// consistent asks for a consistent read, which sees every write that finished before it.
+// It is calling the Select API call until there are no more pages.
+// See http://docs.aws.amazon.com/AmazonSimpleDB/latest/DeveloperGuide/SDB_API_Select.html for more details.
+func (s *SimpleDBService) Select(expression string, consistent bool) ([]Item, error) {
+	items := []Item{}
+
+	params := query("Select")
+	params.Set("SelectExpression", expression)
+	setConsistentRead(params, consistent)
+
+	for {
+		result := selectResponse{}
+
+		req := s.request(params)
+
+		resp, err := req.Do()
+		if err != nil {
+			return items, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, result.Items...)
+
+		if result.NextToken == "" {
+			return items, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
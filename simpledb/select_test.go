@@ -0,0 +1,56 @@
+package simpledb
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSelect(t *testing.T) {
+	Convey("Given items that take two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPages(
+			`<SelectResponse><SelectResult><Item><Name>user-1</Name><Attribute><Name>role</Name><Value>admin</Value></Attribute></Item><NextToken>page2</NextToken></SelectResult></SelectResponse>`,
+			`<SelectResponse><SelectResult><Item><Name>user-2</Name><Attribute><Name>role</Name><Value>admin</Value></Attribute></Item></SelectResult></SelectResponse>`,
+		), &params)
+		defer ts.Close()
+
+		items, err := s.Select("select * from users where role = 'admin'", true)
+
+		Convey("Every page is returned", func() {
+			So(err, ShouldBeNil)
+			So(items, ShouldResemble, []Item{
+				{Name: "user-1", Attributes: []Attribute{{Name: "role", Value: "admin"}}},
+				{Name: "user-2", Attributes: []Attribute{{Name: "role", Value: "admin"}}},
+			})
+		})
+		Convey("The expression is sent with each page", func() {
+			So(params[0].Get("Action"), ShouldEqual, "Select")
+			So(params[0].Get("SelectExpression"), ShouldEqual, "select * from users where role = 'admin'")
+			So(params[0].Get("ConsistentRead"), ShouldEqual, "true")
+			So(params[1].Get("SelectExpression"), ShouldEqual, "select * from users where role = 'admin'")
+			So(params[1].Get("NextToken"), ShouldEqual, "page2")
+		})
+	})
+	Convey("Given a domain that does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.Select("select * from users", false)
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, noSuchDomainError)
+		})
+	})
+	Convey("Given a response that is not XML", t, func() {
+		ts, s := testService(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := s.Select("select * from users", false)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
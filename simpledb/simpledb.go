@@ -0,0 +1,97 @@
+// Package simpledb provides a way to interact with the AWS SimpleDB service.
+package simpledb
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+//go:generate go run ../internal/errgen -type simpleDBError -field Code -service SimpleDB NoSuchDomain AttributeDoesNotExist ConditionalCheckFailed NumberDomainsExceeded InvalidParameterValue MissingParameter
+
+// simpleDBError is the error document returned from the SimpleDB service.
+type simpleDBError struct {
+	Code    string `xml:"Errors>Error>Code"`
+	Message string `xml:"Errors>Error>Message"`
+}
+
+// Error formats the simpleDBError into an error message.
+func (e simpleDBError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func simpleDBRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := simpleDBError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error, like ServiceUnavailable when requests are throttled
+	if status >= 500 {
+		return true, error
+	}
+
+	return false, error
+}
+
+// SimpleDBService is the SimpleDB service at AWS. The endpoint for a region is gaws.Endpoint("sdb", region).
+type SimpleDBService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// SimpleDBAPI is implemented by *SimpleDBService. Code that takes a SimpleDBAPI can be given a fake in tests instead.
+type SimpleDBAPI interface {
+	DeleteAttributes(domain string, item string, attributes ...Attribute) error
+	GetAttributes(domain string, item string, consistent bool, names ...string) (Item, error)
+	PutAttributes(domain string, item string, attributes ...Attribute) error
+	Select(expression string, consistent bool) ([]Item, error)
+}
+
+var _ SimpleDBAPI = (*SimpleDBService)(nil)
+
+// New returns a SimpleDBService with the options applied. Its endpoint is gaws.Endpoint("sdb", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *SimpleDBService {
+	config := gaws.NewConfig(options...)
+	return &SimpleDBService{Endpoint: config.ServiceEndpoint("sdb"), config: config}
+}
+
+// request returns a request with the parameters. SimpleDB only takes signature version 2, which signs the query string,
+// so the parameters are sent there instead of in the body.
+func (s *SimpleDBService) request(params url.Values) gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: simpleDBRetryPredicate,
+		Method:         "GET",
+		URL:            s.Endpoint + "/?" + params.Encode(),
+		Headers:        map[string]string{},
+		Signer:         awsauth.Sign2,
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for a SimpleDB API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2009-04-15"},
+	}
+}
+
+// setConsistentRead asks for a consistent read, which sees every write that finished before it, if consistent is true.
+func setConsistentRead(params url.Values, consistent bool) {
+	if consistent {
+		params.Set("ConsistentRead", strconv.FormatBool(consistent))
+	}
+}
@@ -0,0 +1,122 @@
+package simpledb
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<SelectResponse><SelectResult>"))
+}
+
+var testNoSuchDomainError = []byte(`<Response>
+  <Errors>
+    <Error>
+      <Code>NoSuchDomain</Code>
+      <Message>The specified domain does not exist.</Message>
+      <BoxUsage>0.0000071759</BoxUsage>
+    </Error>
+  </Errors>
+  <RequestID>c3b7e5f6-1c38-4ddd-9d5b-example</RequestID>
+</Response>`)
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(400)
+	w.Write(testNoSuchDomainError)
+}
+
+var noSuchDomainError = simpleDBError{Code: "NoSuchDomain", Message: "The specified domain does not exist."}
+
+// testPages returns a handler that returns first, then second when it is asked for the page after NextToken page2.
+func testPages(first string, second string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Form.Get("NextToken") == "page2" {
+			w.Write([]byte(second))
+			return
+		}
+		w.Write([]byte(first))
+	}
+}
+
+// testService returns a service on a server that runs handler and records the query parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, SimpleDBService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.URL.Query())
+		}
+		handler(w, r)
+	}))
+	return ts, SimpleDBService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := simpleDBRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 503", t, func() {
+		result, _ := simpleDBRetryPredicate(503, []byte("<Response><Errors><Error><Code>ServiceUnavailable</Code></Error></Errors></Response>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the domain does not exist", t, func() {
+		result, err := simpleDBRetryPredicate(400, testNoSuchDomainError)
+		Convey("RetryPredicate returns false and the error", func() {
+			So(result, ShouldBeFalse)
+			So(err, ShouldResemble, noSuchDomainError)
+		})
+	})
+}
+
+func TestRequest(t *testing.T) {
+	Convey("Given parameters", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testHTTP200, &params)
+		defer ts.Close()
+
+		req := s.request(query("ListDomains"))
+		_, err := req.Do()
+
+		Convey("They are sent in the query string", func() {
+			So(err, ShouldBeNil)
+			So(req.Method, ShouldEqual, "GET")
+			So(params[0].Get("Action"), ShouldEqual, "ListDomains")
+			So(params[0].Get("Version"), ShouldEqual, "2009-04-15")
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("sdb", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request(nil).Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
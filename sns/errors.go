@@ -0,0 +1,21 @@
+// Code generated by errgen from the go:generate line in sns.go. DO NOT EDIT.
+
+package sns
+
+// These are errors SNS returns. errors.Is(err, sns.ErrNotFound) is true for any error with the same code, whatever its message.
+var (
+	ErrNotFound                    = snsError{Code: "NotFound"}
+	ErrInvalidParameter            = snsError{Code: "InvalidParameter"}
+	ErrAuthorization               = snsError{Code: "AuthorizationError"}
+	ErrEndpointDisabled            = snsError{Code: "EndpointDisabled"}
+	ErrPlatformApplicationDisabled = snsError{Code: "PlatformApplicationDisabled"}
+	ErrSubscriptionLimitExceeded   = snsError{Code: "SubscriptionLimitExceeded"}
+	ErrTopicLimitExceeded          = snsError{Code: "TopicLimitExceeded"}
+	ErrInternal                    = snsError{Code: "InternalError"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e snsError) Is(target error) bool {
+	t, ok := target.(snsError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
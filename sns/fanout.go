@@ -0,0 +1,91 @@
+package sns
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/controlgroup/gaws/sqs"
+)
+
+// notAlphanumeric matches the characters that cannot be in the Sid of a policy statement.
+var notAlphanumeric = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// queuePolicyStatementId returns the Sid of the statement that lets the topic send to a queue.
+func queuePolicyStatementId(topicArn string) string {
+	return "AllowSNS" + notAlphanumeric.ReplaceAllString(topicArn, "")
+}
+
+// queuePolicy adds a statement that lets the topic send messages to the queue to the queue's policy, which may be empty.
+// A statement for the same topic is replaced, so it can be applied more than once.
+func queuePolicy(policy string, queueArn string, topicArn string) (string, error) {
+	document := map[string]interface{}{}
+	if policy != "" {
+		err := json.Unmarshal([]byte(policy), &document)
+		if err != nil {
+			return "", err
+		}
+	}
+	if _, ok := document["Version"]; !ok {
+		document["Version"] = "2012-10-17"
+	}
+
+	sid := queuePolicyStatementId(topicArn)
+	statements := []interface{}{}
+	switch existing := document["Statement"].(type) {
+	case []interface{}:
+		statements = existing
+	case map[string]interface{}:
+		statements = []interface{}{existing}
+	}
+
+	kept := []interface{}{}
+	for _, s := range statements {
+		if statement, ok := s.(map[string]interface{}); ok && statement["Sid"] == sid {
+			continue
+		}
+		kept = append(kept, s)
+	}
+
+	document["Statement"] = append(kept, map[string]interface{}{
+		"Sid":       sid,
+		"Effect":    "Allow",
+		"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+		"Action":    "sqs:SendMessage",
+		"Resource":  queueArn,
+		"Condition": map[string]interface{}{"ArnEquals": map[string]string{"aws:SourceArn": topicArn}},
+	})
+
+	b, err := json.Marshal(document)
+	return string(b), err
+}
+
+// SubscribeQueues sends the topic's messages to each of the queues. For each queue, it lets the topic send to the queue in the queue's policy,
+// keeping the rest of the policy, and subscribes the queue with raw message delivery, so messages arrive as they were published.
+// It returns the subscriptions it made before an error, if there was one.
+func (t *Topic) SubscribeQueues(queues ...sqs.QueueAPI) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+
+	for _, q := range queues {
+		attributes, err := q.GetAttributes()
+		if err != nil {
+			return subscriptions, err
+		}
+
+		policy, err := queuePolicy(attributes.Policy, attributes.QueueArn, t.Arn)
+		if err != nil {
+			return subscriptions, err
+		}
+
+		err = q.SetAttributes(sqs.QueueAttributes{Policy: policy})
+		if err != nil {
+			return subscriptions, err
+		}
+
+		subscription, err := t.Subscribe("sqs", attributes.QueueArn, SubscriptionAttributes{RawMessageDelivery: true})
+		if err != nil {
+			return subscriptions, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
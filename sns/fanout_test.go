@@ -0,0 +1,143 @@
+package sns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws/sqs"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testFanoutTopicArn = "arn:aws:sns:us-east-1:123456789012:foo"
+
+func TestQueuePolicy(t *testing.T) {
+	Convey("Given a queue without a policy", t, func() {
+		policy, err := queuePolicy("", "arn:aws:sqs:us-east-1:123456789012:bar", testFanoutTopicArn)
+		document := map[string]interface{}{}
+		json.Unmarshal([]byte(policy), &document)
+		statements := document["Statement"].([]interface{})
+		statement := statements[0].(map[string]interface{})
+
+		Convey("It makes a policy that lets the topic send to the queue", func() {
+			So(err, ShouldBeNil)
+			So(document["Version"], ShouldEqual, "2012-10-17")
+			So(len(statements), ShouldEqual, 1)
+			So(statement["Sid"], ShouldEqual, "AllowSNSarnawssnsuseast1123456789012foo")
+			So(statement["Principal"], ShouldResemble, map[string]interface{}{"Service": "sns.amazonaws.com"})
+			So(statement["Action"], ShouldEqual, "sqs:SendMessage")
+			So(statement["Resource"], ShouldEqual, "arn:aws:sqs:us-east-1:123456789012:bar")
+			So(statement["Condition"], ShouldResemble, map[string]interface{}{"ArnEquals": map[string]interface{}{"aws:SourceArn": testFanoutTopicArn}})
+		})
+	})
+	Convey("Given a queue with a policy", t, func() {
+		existing := `{"Version":"2008-10-17","Statement":{"Sid":"Mine","Effect":"Allow","Principal":"*","Action":"sqs:ReceiveMessage"}}`
+		policy, err := queuePolicy(existing, "arn:aws:sqs:us-east-1:123456789012:bar", testFanoutTopicArn)
+		again, _ := queuePolicy(policy, "arn:aws:sqs:us-east-1:123456789012:bar", testFanoutTopicArn)
+
+		document := map[string]interface{}{}
+		json.Unmarshal([]byte(again), &document)
+		statements := document["Statement"].([]interface{})
+
+		Convey("It keeps the existing statements", func() {
+			So(err, ShouldBeNil)
+			So(document["Version"], ShouldEqual, "2008-10-17")
+			So(statements[0].(map[string]interface{})["Sid"], ShouldEqual, "Mine")
+		})
+		Convey("It only adds the topic's statement once", func() {
+			So(len(statements), ShouldEqual, 2)
+			So(again, ShouldEqual, policy)
+		})
+	})
+	Convey("Given a queue with a policy that is not JSON", t, func() {
+		_, err := queuePolicy("{", "arn:aws:sqs:us-east-1:123456789012:bar", testFanoutTopicArn)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSubscribeQueues(t *testing.T) {
+	Convey("Given a topic and a queue", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			switch r.PostForm.Get("Action") {
+			case "GetQueueAttributes":
+				w.Write([]byte("<GetQueueAttributesResponse><GetQueueAttributesResult><Attribute><Name>QueueArn</Name><Value>arn:aws:sqs:us-east-1:123456789012:bar</Value></Attribute></GetQueueAttributesResult></GetQueueAttributesResponse>"))
+			case "Subscribe":
+				w.Write([]byte("<SubscribeResponse><SubscribeResult><SubscriptionArn>arn:aws:sns:us-east-1:123456789012:foo:1</SubscriptionArn></SubscribeResult></SubscribeResponse>"))
+			default:
+				w.Write([]byte("<Response></Response>"))
+			}
+		}, &params)
+		defer ts.Close()
+		q := &sqs.Queue{URL: ts.URL + "/123456789012/bar", Service: &sqs.SQSService{Endpoint: ts.URL}}
+
+		subscriptions, err := topic.SubscribeQueues(q)
+
+		Convey("It returns the subscription", func() {
+			So(err, ShouldBeNil)
+			So(len(subscriptions), ShouldEqual, 1)
+			So(subscriptions[0].Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo:1")
+		})
+		Convey("It sets the queue's policy", func() {
+			So(params[1].Get("Action"), ShouldEqual, "SetQueueAttributes")
+			So(params[1].Get("Attribute.1.Name"), ShouldEqual, "Policy")
+			So(params[1].Get("Attribute.1.Value"), ShouldContainSubstring, `"aws:SourceArn":"arn:aws:sns:us-east-1:123456789012:foo"`)
+		})
+		Convey("It subscribes the queue with raw message delivery", func() {
+			So(params[2].Get("Protocol"), ShouldEqual, "sqs")
+			So(params[2].Get("Endpoint"), ShouldEqual, "arn:aws:sqs:us-east-1:123456789012:bar")
+			So(params[2].Get("Attributes.entry.1.key"), ShouldEqual, "RawMessageDelivery")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+		q := &sqs.Queue{URL: ts.URL + "/123456789012/bar", Service: &sqs.SQSService{Endpoint: ts.URL}}
+
+		subscriptions, err := topic.SubscribeQueues(q)
+
+		Convey("It returns an error and no subscriptions", func() {
+			So(err, ShouldNotBeNil)
+			So(len(subscriptions), ShouldEqual, 0)
+		})
+	})
+}
+
+// fakeQueue is an sqs.QueueAPI without a server. It records the policy it is given.
+type fakeQueue struct {
+	sqs.QueueAPI
+	policy string
+}
+
+func (q *fakeQueue) GetAttributes() (sqs.QueueAttributes, error) {
+	return sqs.QueueAttributes{QueueArn: "arn:aws:sqs:us-east-1:123456789012:fake"}, nil
+}
+
+func (q *fakeQueue) SetAttributes(attributes sqs.QueueAttributes) error {
+	q.policy = attributes.Policy
+	return nil
+}
+
+func TestSubscribeFakeQueues(t *testing.T) {
+	Convey("Given a topic and a fake queue", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<SubscribeResponse><SubscribeResult><SubscriptionArn>arn:aws:sns:us-east-1:123456789012:foo:1</SubscriptionArn></SubscribeResult></SubscribeResponse>"))
+		}, &params)
+		defer ts.Close()
+		q := &fakeQueue{}
+
+		subscriptions, err := topic.SubscribeQueues(q)
+
+		Convey("It sets the fake queue's policy and subscribes it", func() {
+			So(err, ShouldBeNil)
+			So(len(subscriptions), ShouldEqual, 1)
+			So(q.policy, ShouldContainSubstring, `"aws:SourceArn":"arn:aws:sns:us-east-1:123456789012:foo"`)
+			So(params[0].Get("Endpoint"), ShouldEqual, "arn:aws:sqs:us-east-1:123456789012:fake")
+		})
+	})
+}
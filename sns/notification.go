@@ -0,0 +1,178 @@
+package sns
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var badCertURLError = snsError{Type: "Gaws", Code: "GawsBadSigningCertURL", Message: "The SigningCertURL is not an SNS certificate."}
+
+var badSubscribeURLError = snsError{Type: "Gaws", Code: "GawsBadSubscribeURL", Message: "The SubscribeURL is not an SNS URL."}
+
+var badCertError = snsError{Type: "Gaws", Code: "GawsBadSigningCert", Message: "The signing certificate is not a PEM encoded certificate."}
+
+var badSignatureVersionError = snsError{Type: "Gaws", Code: "GawsBadSignatureVersion", Message: "The SignatureVersion is not 1 or 2."}
+
+// snsHost matches the hosts SNS sends certificates and subscription confirmations from.
+var snsHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Notification is a message SNS sends to an HTTP or HTTPS subscription. Type is Notification, SubscriptionConfirmation, or UnsubscribeConfirmation.
+// See http://docs.aws.amazon.com/sns/latest/dg/sns-message-and-json-formats.html for more details.
+type Notification struct {
+	Message           string
+	MessageAttributes map[string]struct{ Type, Value string }
+	MessageId         string
+	Signature         string
+	SignatureVersion  string
+	SigningCertURL    string
+	Subject           string
+	SubscribeURL      string // Visit it to confirm a subscription
+	Timestamp         string
+	Token             string // Can be passed to Topic.ConfirmSubscription instead of visiting SubscribeURL
+	TopicArn          string
+	Type              string
+	UnsubscribeURL    string
+}
+
+// ParseNotification parses the body of a request from SNS and verifies its signature.
+// Only use the Notification if the error is nil.
+func ParseNotification(body []byte) (Notification, error) {
+	n := Notification{}
+
+	err := json.Unmarshal(body, &n)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	err = n.Verify()
+	if err != nil {
+		return Notification{}, err
+	}
+	return n, nil
+}
+
+// stringToSign returns what SNS signed: some of the fields, in order, as name and value lines.
+func (n Notification) stringToSign() string {
+	fields := [][2]string{{"Message", n.Message}, {"MessageId", n.MessageId}}
+	if n.Type == "Notification" {
+		if n.Subject != "" {
+			fields = append(fields, [2]string{"Subject", n.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", n.Timestamp})
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", n.SubscribeURL}, [2]string{"Timestamp", n.Timestamp}, [2]string{"Token", n.Token})
+	}
+	fields = append(fields, [2]string{"TopicArn", n.TopicArn}, [2]string{"Type", n.Type})
+
+	s := ""
+	for _, f := range fields {
+		s += f[0] + "\n" + f[1] + "\n"
+	}
+	return s
+}
+
+// Verify checks that the notification was signed by SNS. The SigningCertURL must be an HTTPS URL on an SNS host.
+// See http://docs.aws.amazon.com/sns/latest/dg/sns-verify-signature-of-message.html for more details.
+func (n Notification) Verify() error {
+	u, err := url.Parse(n.SigningCertURL)
+	if err != nil || u.Scheme != "https" || !snsHost.MatchString(u.Host) || !strings.HasSuffix(u.Path, ".pem") {
+		return badCertURLError
+	}
+
+	var algorithm x509.SignatureAlgorithm
+	switch n.SignatureVersion {
+	case "1":
+		algorithm = x509.SHA1WithRSA
+	case "2":
+		algorithm = x509.SHA256WithRSA
+	default:
+		return badSignatureVersionError
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(n.Signature)
+	if err != nil {
+		return err
+	}
+
+	cert, err := signingCert(n.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	return cert.CheckSignature(algorithm, []byte(n.stringToSign()), signature)
+}
+
+// certs caches signing certificates by URL, since SNS uses the same one for many messages.
+var certs = struct {
+	sync.Mutex
+	byURL map[string]*x509.Certificate
+}{byURL: map[string]*x509.Certificate{}}
+
+// signingCert returns the certificate at a URL, from the cache if it has been fetched before.
+func signingCert(certURL string) (*x509.Certificate, error) {
+	certs.Lock()
+	cert, ok := certs.byURL[certURL]
+	certs.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	cert, err := fetchCert(certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	certs.Lock()
+	certs.byURL[certURL] = cert
+	certs.Unlock()
+	return cert, nil
+}
+
+// fetchCert downloads a PEM encoded certificate. It is a variable so tests can replace it.
+var fetchCert = func(certURL string) (*x509.Certificate, error) {
+	resp, err := http.Get(certURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, badCertError
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// Confirm confirms a subscription by visiting the SubscribeURL of a SubscriptionConfirmation. The URL must be on an SNS host.
+func (n Notification) Confirm() error {
+	u, err := url.Parse(n.SubscribeURL)
+	if err != nil || u.Scheme != "https" || !snsHost.MatchString(u.Host) {
+		return badSubscribeURLError
+	}
+
+	resp, err := http.Get(n.SubscribeURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	_, err = snsRetryPredicate(resp.StatusCode, body)
+	return err
+}
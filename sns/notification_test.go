@@ -0,0 +1,129 @@
+package sns
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-0000000000000000000000.pem"
+
+// testSigner returns a function that signs notifications with a new key, and makes fetchCert return the key's certificate.
+func testSigner() func(n *Notification) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	cert, _ := x509.ParseCertificate(der)
+
+	certs.Lock()
+	certs.byURL = map[string]*x509.Certificate{}
+	certs.Unlock()
+	fetchCert = func(certURL string) (*x509.Certificate, error) { return cert, nil }
+
+	return func(n *Notification) {
+		n.SigningCertURL = testCertURL
+		var signature []byte
+		if n.SignatureVersion == "2" {
+			hash := sha256.Sum256([]byte(n.stringToSign()))
+			signature, _ = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+		} else {
+			n.SignatureVersion = "1"
+			hash := sha1.Sum([]byte(n.stringToSign()))
+			signature, _ = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, hash[:])
+		}
+		n.Signature = base64.StdEncoding.EncodeToString(signature)
+	}
+}
+
+func TestNotification(t *testing.T) {
+	sign := testSigner()
+
+	Convey("Given a signed notification", t, func() {
+		n := Notification{Type: "Notification", MessageId: "1", TopicArn: "arn:aws:sns:us-east-1:123456789012:foo", Subject: "greeting", Message: "hello", Timestamp: "2012-05-02T00:54:06.655Z"}
+		sign(&n)
+		body, _ := json.Marshal(n)
+
+		parsed, err := ParseNotification(body)
+
+		Convey("It is parsed and verified", func() {
+			So(err, ShouldBeNil)
+			So(parsed.Message, ShouldEqual, "hello")
+			So(parsed.Subject, ShouldEqual, "greeting")
+		})
+	})
+	Convey("Given a subscription confirmation signed with SHA256", t, func() {
+		n := Notification{Type: "SubscriptionConfirmation", MessageId: "1", Token: "token", TopicArn: "arn:aws:sns:us-east-1:123456789012:foo", Message: "confirm", SubscribeURL: "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription", Timestamp: "2012-05-02T00:54:06.655Z", SignatureVersion: "2"}
+		sign(&n)
+
+		Convey("It is verified", func() {
+			So(n.Verify(), ShouldBeNil)
+		})
+	})
+	Convey("Given a notification that was changed after it was signed", t, func() {
+		n := Notification{Type: "Notification", MessageId: "1", TopicArn: "arn:aws:sns:us-east-1:123456789012:foo", Message: "hello", Timestamp: "2012-05-02T00:54:06.655Z"}
+		sign(&n)
+		n.Message = "goodbye"
+		body, _ := json.Marshal(n)
+
+		_, err := ParseNotification(body)
+
+		Convey("It is not verified", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a notification with a certificate that is not from SNS", t, func() {
+		n := Notification{Type: "Notification", Message: "hello"}
+		sign(&n)
+
+		Convey("It is not verified", func() {
+			for _, u := range []string{
+				"http://sns.us-east-1.amazonaws.com/cert.pem",
+				"https://sns.us-east-1.amazonaws.com.example.com/cert.pem",
+				"https://example.com/sns.us-east-1.amazonaws.com/cert.pem",
+				"https://sns.us-east-1.amazonaws.com/cert.txt",
+			} {
+				n.SigningCertURL = u
+				So(n.Verify(), ShouldResemble, badCertURLError)
+			}
+		})
+	})
+	Convey("Given a notification with an unknown signature version", t, func() {
+		n := Notification{Type: "Notification", Message: "hello"}
+		sign(&n)
+		n.SignatureVersion = "3"
+
+		Convey("It is not verified", func() {
+			So(n.Verify(), ShouldResemble, badSignatureVersionError)
+		})
+	})
+	Convey("Given a body that is not JSON", t, func() {
+		_, err := ParseNotification([]byte("hello"))
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a subscription confirmation with a SubscribeURL that is not from SNS", t, func() {
+		n := Notification{Type: "SubscriptionConfirmation", SubscribeURL: "https://example.com/?Action=ConfirmSubscription"}
+
+		Convey("It is not visited", func() {
+			So(n.Confirm(), ShouldResemble, badSubscribeURLError)
+		})
+	})
+}
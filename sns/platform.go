@@ -0,0 +1,148 @@
+package sns
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strconv"
+)
+
+// PlatformApplication is a push notification service, like APNS or GCM, set up in SNS. Devices are added to it as endpoints.
+type PlatformApplication struct {
+	Arn     string      // The ARN of the platform application
+	Service *SNSService // The service for this region
+}
+
+// PlatformEndpoint is a device, or an app on a device, that push notifications can be sent to.
+type PlatformEndpoint struct {
+	Arn     string      // The ARN of the endpoint
+	Service *SNSService // The service for this region
+}
+
+// EndpointAttributes are the attributes of a platform endpoint.
+type EndpointAttributes struct {
+	CustomUserData string // Any data to keep with the endpoint, like a user ID
+	Enabled        bool   // Whether notifications are sent to the endpoint. SNS disables it when the push service says the token is no longer valid.
+	Token          string // The device token or registration ID the push service gave the app
+}
+
+// PushMessage returns a Message with a payload for each platform, like "APNS", "APNS_SANDBOX" or "GCM". Each payload is encoded as JSON.
+// defaultMessage is sent to the platforms that are not in payloads.
+func PushMessage(defaultMessage string, payloads map[string]interface{}) (Message, error) {
+	messages := map[string]string{"default": defaultMessage}
+
+	for platform, payload := range payloads {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return Message{}, err
+		}
+		messages[platform] = string(b)
+	}
+	return ProtocolMessage(messages)
+}
+
+type createPlatformEndpointResponse struct {
+	EndpointArn string `xml:"CreatePlatformEndpointResult>EndpointArn"`
+}
+
+// CreateEndpoint adds a device to the platform application. token is the device token or registration ID the push service gave the app.
+// If an endpoint with the same token already exists, it returns that endpoint.
+// See http://docs.aws.amazon.com/sns/latest/api/API_CreatePlatformEndpoint.html for more details.
+func (a *PlatformApplication) CreateEndpoint(token string, customUserData string) (PlatformEndpoint, error) {
+	result := createPlatformEndpointResponse{}
+
+	params := query("CreatePlatformEndpoint")
+	params.Set("PlatformApplicationArn", a.Arn)
+	params.Set("Token", token)
+	if customUserData != "" {
+		params.Set("CustomUserData", customUserData)
+	}
+
+	req := a.Service.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return PlatformEndpoint{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return PlatformEndpoint{}, err
+	}
+	return PlatformEndpoint{Arn: result.EndpointArn, Service: a.Service}, nil
+}
+
+// Publish sends a push notification to the endpoint. Use PushMessage to send a different payload to each platform.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Publish.html for more details.
+func (e *PlatformEndpoint) Publish(m Message) (string, error) {
+	params := query("Publish")
+	params.Set("TargetArn", e.Arn)
+	return e.Service.publish(params, m)
+}
+
+type getEndpointAttributesResponse struct {
+	Attributes []entry `xml:"GetEndpointAttributesResult>Attributes>entry"`
+}
+
+// GetAttributes returns the attributes of the endpoint. It is calling the GetEndpointAttributes API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_GetEndpointAttributes.html for more details.
+func (e *PlatformEndpoint) GetAttributes() (EndpointAttributes, error) {
+	result := getEndpointAttributesResponse{}
+
+	params := query("GetEndpointAttributes")
+	params.Set("EndpointArn", e.Arn)
+
+	req := e.Service.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return EndpointAttributes{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return EndpointAttributes{}, err
+	}
+
+	m := entriesToMap(result.Attributes)
+	return EndpointAttributes{CustomUserData: m["CustomUserData"], Enabled: m["Enabled"] == "true", Token: m["Token"]}, nil
+}
+
+// SetAttributes changes the attributes of the endpoint. CustomUserData and Token are left alone if they are empty, but Enabled is always set.
+// It is calling the SetEndpointAttributes API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_SetEndpointAttributes.html for more details.
+func (e *PlatformEndpoint) SetAttributes(attributes EndpointAttributes) error {
+	m := map[string]string{"Enabled": strconv.FormatBool(attributes.Enabled)}
+	if attributes.CustomUserData != "" {
+		m["CustomUserData"] = attributes.CustomUserData
+	}
+	if attributes.Token != "" {
+		m["Token"] = attributes.Token
+	}
+
+	params := query("SetEndpointAttributes")
+	params.Set("EndpointArn", e.Arn)
+	setEntryParams(params, m)
+
+	req := e.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+// Delete removes the endpoint from its platform application. It is calling the DeleteEndpoint API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_DeleteEndpoint.html for more details.
+func (e *PlatformEndpoint) Delete() error {
+	params := query("DeleteEndpoint")
+	params.Set("EndpointArn", e.Arn)
+
+	req := e.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
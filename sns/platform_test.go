@@ -0,0 +1,147 @@
+package sns
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testEndpoint returns a platform endpoint on a server that runs handler and records the parameters of every request.
+func testEndpoint(handler http.HandlerFunc, params *[]url.Values) (func(), PlatformEndpoint) {
+	ts, s := testService(handler, params)
+	return ts.Close, PlatformEndpoint{Arn: "arn:aws:sns:us-east-1:123456789012:endpoint/GCM/app/1", Service: &s}
+}
+
+func TestPushMessage(t *testing.T) {
+	Convey("Given payloads for each platform", t, func() {
+		m, err := PushMessage("hello", map[string]interface{}{"GCM": map[string]interface{}{"data": map[string]string{"message": "hello"}}})
+
+		Convey("Each payload is a JSON string in the message", func() {
+			So(err, ShouldBeNil)
+			So(m.MessageStructure, ShouldEqual, "json")
+			So(m.Message, ShouldEqual, `{"GCM":"{\"data\":{\"message\":\"hello\"}}","default":"hello"}`)
+		})
+	})
+	Convey("Given a payload that cannot be encoded", t, func() {
+		_, err := PushMessage("hello", map[string]interface{}{"GCM": func() {}})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCreateEndpoint(t *testing.T) {
+	Convey("Given a platform application", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<CreatePlatformEndpointResponse><CreatePlatformEndpointResult><EndpointArn>arn:aws:sns:us-east-1:123456789012:endpoint/GCM/app/1</EndpointArn></CreatePlatformEndpointResult></CreatePlatformEndpointResponse>"))
+		}, &params)
+		defer ts.Close()
+		app := PlatformApplication{Arn: "arn:aws:sns:us-east-1:123456789012:app/GCM/app", Service: &s}
+
+		e, err := app.CreateEndpoint("device-token", "user-1")
+
+		Convey("It returns the endpoint", func() {
+			So(err, ShouldBeNil)
+			So(e.Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:endpoint/GCM/app/1")
+			So(e.Service, ShouldEqual, &s)
+		})
+		Convey("It sends the token and user data", func() {
+			So(params[0].Get("PlatformApplicationArn"), ShouldEqual, app.Arn)
+			So(params[0].Get("Token"), ShouldEqual, "device-token")
+			So(params[0].Get("CustomUserData"), ShouldEqual, "user-1")
+		})
+	})
+	Convey("Given a platform application that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+		app := PlatformApplication{Arn: "arn:aws:sns:us-east-1:123456789012:app/GCM/app", Service: &s}
+
+		_, err := app.CreateEndpoint("device-token", "")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPublishToEndpoint(t *testing.T) {
+	Convey("Given an endpoint", t, func() {
+		params := []url.Values{}
+		stop, e := testEndpoint(testPublished, &params)
+		defer stop()
+
+		id, err := e.Publish(Message{Message: "hello"})
+
+		Convey("It sends the message to the endpoint", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "94f20ce6-13c5-43a0-9a9e-ca52d816e90b")
+			So(params[0].Get("TargetArn"), ShouldEqual, e.Arn)
+		})
+	})
+}
+
+func TestEndpointAttributes(t *testing.T) {
+	Convey("Given an endpoint with attributes", t, func() {
+		stop, e := testEndpoint(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<GetEndpointAttributesResponse><GetEndpointAttributesResult><Attributes><entry><key>Enabled</key><value>false</value></entry><entry><key>Token</key><value>device-token</value></entry></Attributes></GetEndpointAttributesResult></GetEndpointAttributesResponse>"))
+		}, nil)
+		defer stop()
+
+		a, err := e.GetAttributes()
+
+		Convey("It returns the attributes", func() {
+			So(err, ShouldBeNil)
+			So(a, ShouldResemble, EndpointAttributes{Enabled: false, Token: "device-token"})
+		})
+	})
+	Convey("Given an endpoint to enable with a new token", t, func() {
+		params := []url.Values{}
+		stop, e := testEndpoint(testHTTP200, &params)
+		defer stop()
+
+		err := e.SetAttributes(EndpointAttributes{Enabled: true, Token: "new-token"})
+		attributes := map[string]string{}
+		for n := 1; n <= 2; n++ {
+			attributes[params[0].Get("Attributes.entry."+strconv.Itoa(n)+".key")] = params[0].Get("Attributes.entry." + strconv.Itoa(n) + ".value")
+		}
+
+		Convey("It sends Enabled and the token", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("EndpointArn"), ShouldEqual, e.Arn)
+			So(attributes, ShouldResemble, map[string]string{"Enabled": "true", "Token": "new-token"})
+		})
+	})
+	Convey("Given an endpoint that returns errors", t, func() {
+		stop, e := testEndpoint(testHTTP400, nil)
+		defer stop()
+
+		_, getErr := e.GetAttributes()
+
+		Convey("It returns errors", func() {
+			So(getErr, ShouldNotBeNil)
+			So(e.SetAttributes(EndpointAttributes{}), ShouldNotBeNil)
+			So(e.Delete(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeleteEndpoint(t *testing.T) {
+	Convey("Given an endpoint", t, func() {
+		params := []url.Values{}
+		stop, e := testEndpoint(testHTTP200, &params)
+		defer stop()
+
+		err := e.Delete()
+
+		Convey("It deletes the endpoint", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "DeleteEndpoint")
+			So(params[0].Get("EndpointArn"), ShouldEqual, e.Arn)
+		})
+	})
+}
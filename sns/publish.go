@@ -0,0 +1,132 @@
+package sns
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var noDefaultMessageError = snsError{Type: "Gaws", Code: "GawsNoDefaultMessage", Message: "A message for each protocol must include a default message."}
+
+// MessageAttributeValue is the value of a message attribute. Subscription filter policies match against them.
+type MessageAttributeValue struct {
+	BinaryValue []byte // The value of a Binary attribute
+	DataType    string // String, String.Array, Number, or Binary
+	StringValue string // The value of a String, String.Array or Number attribute
+}
+
+// StringAttribute returns a String message attribute.
+func StringAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "String", StringValue: value}
+}
+
+// StringArrayAttribute returns a String.Array message attribute. A filter policy matches it if it matches any of the values.
+func StringArrayAttribute(values []string) MessageAttributeValue {
+	value, _ := json.Marshal(values)
+	return MessageAttributeValue{DataType: "String.Array", StringValue: string(value)}
+}
+
+// NumberAttribute returns a Number message attribute. Numbers are sent as strings so no precision is lost.
+func NumberAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "Number", StringValue: value}
+}
+
+// BinaryAttribute returns a Binary message attribute. Filter policies ignore binary attributes.
+func BinaryAttribute(value []byte) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "Binary", BinaryValue: value}
+}
+
+// setMessageAttributeParams adds message attributes to the parameters of a request as numbered MessageAttributes.entry.N entries. Binary values are base64 encoded.
+func setMessageAttributeParams(params url.Values, attributes map[string]MessageAttributeValue) {
+	n := 1
+	for name, v := range attributes {
+		p := "MessageAttributes.entry." + strconv.Itoa(n)
+		params.Set(p+".Name", name)
+		params.Set(p+".Value.DataType", v.DataType)
+		if strings.HasPrefix(v.DataType, "Binary") {
+			params.Set(p+".Value.BinaryValue", base64.StdEncoding.EncodeToString(v.BinaryValue))
+		} else {
+			params.Set(p+".Value.StringValue", v.StringValue)
+		}
+		n++
+	}
+}
+
+// Message is a message to publish.
+type Message struct {
+	Attributes       map[string]MessageAttributeValue // Optional message attributes, which subscription filter policies match against
+	Message          string                           // The message. If MessageStructure is json, it is a JSON object with a message for each protocol.
+	MessageStructure string                           // json to send a different message to each protocol, or empty to send Message to all of them
+	Subject          string                           // The subject of email messages, and the Subject of the notifications sent to the other protocols except SMS
+}
+
+// ProtocolMessage returns a Message that sends a different message to each protocol, like "email", "sqs" or "http".
+// messages must have a "default" message, which is sent to the protocols that are not in it.
+func ProtocolMessage(messages map[string]string) (Message, error) {
+	if _, ok := messages["default"]; !ok {
+		return Message{}, noDefaultMessageError
+	}
+
+	message, err := json.Marshal(messages)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{Message: string(message), MessageStructure: "json"}, nil
+}
+
+// setParams adds the message to the parameters of a request.
+func (m Message) setParams(params url.Values) {
+	params.Set("Message", m.Message)
+	if m.MessageStructure != "" {
+		params.Set("MessageStructure", m.MessageStructure)
+	}
+	if m.Subject != "" {
+		params.Set("Subject", m.Subject)
+	}
+	setMessageAttributeParams(params, m.Attributes)
+}
+
+type publishResponse struct {
+	MessageId string `xml:"PublishResult>MessageId"`
+}
+
+// publish sends a Publish request. params says where the message goes.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Publish.html for more details.
+func (s *SNSService) publish(params url.Values, m Message) (string, error) {
+	result := publishResponse{}
+
+	m.setParams(params)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return "", err
+	}
+	return result.MessageId, nil
+}
+
+// Publish sends a message to everything subscribed to the topic. It returns the ID of the message and an error if it fails.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Publish.html for more details.
+func (t *Topic) Publish(m Message) (string, error) {
+	params := query("Publish")
+	params.Set("TopicArn", t.Arn)
+	return t.Service.publish(params, m)
+}
+
+// PublishToPhone sends a message as an SMS to a phone number in E.164 format, like +14155552671. It returns the ID of the message and an error if it fails.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Publish.html for more details.
+func (s *SNSService) PublishToPhone(phoneNumber string, message string) (string, error) {
+	params := query("Publish")
+	params.Set("PhoneNumber", phoneNumber)
+	return s.publish(params, Message{Message: message})
+}
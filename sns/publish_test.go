@@ -0,0 +1,126 @@
+package sns
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testPublished(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<PublishResponse><PublishResult><MessageId>94f20ce6-13c5-43a0-9a9e-ca52d816e90b</MessageId></PublishResult></PublishResponse>"))
+}
+
+func TestProtocolMessage(t *testing.T) {
+	Convey("Given messages for each protocol", t, func() {
+		m, err := ProtocolMessage(map[string]string{"default": "hello", "email": "Hello, friend"})
+
+		Convey("They are sent as a JSON object", func() {
+			So(err, ShouldBeNil)
+			So(m.Message, ShouldEqual, `{"default":"hello","email":"Hello, friend"}`)
+			So(m.MessageStructure, ShouldEqual, "json")
+		})
+	})
+	Convey("Given messages without a default", t, func() {
+		_, err := ProtocolMessage(map[string]string{"email": "Hello, friend"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, noDefaultMessageError)
+		})
+	})
+}
+
+func TestMessageAttributes(t *testing.T) {
+	Convey("Given a string array attribute", t, func() {
+		a := StringArrayAttribute([]string{"red", "blue"})
+
+		Convey("Its value is a JSON array", func() {
+			So(a.DataType, ShouldEqual, "String.Array")
+			So(a.StringValue, ShouldEqual, `["red","blue"]`)
+		})
+	})
+	Convey("Given a binary attribute", t, func() {
+		params := url.Values{}
+		setMessageAttributeParams(params, map[string]MessageAttributeValue{"thumb": BinaryAttribute([]byte{1, 2})})
+
+		Convey("It is sent base64 encoded", func() {
+			So(params.Get("MessageAttributes.entry.1.Name"), ShouldEqual, "thumb")
+			So(params.Get("MessageAttributes.entry.1.Value.DataType"), ShouldEqual, "Binary")
+			So(params.Get("MessageAttributes.entry.1.Value.BinaryValue"), ShouldEqual, "AQI=")
+		})
+	})
+}
+
+func TestPublish(t *testing.T) {
+	Convey("Given a topic", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(testPublished, &params)
+		defer ts.Close()
+
+		id, err := topic.Publish(Message{Message: "hello", Subject: "greeting"})
+
+		Convey("It returns the message ID", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "94f20ce6-13c5-43a0-9a9e-ca52d816e90b")
+		})
+		Convey("It sends the message to the topic", func() {
+			So(params[0].Get("Action"), ShouldEqual, "Publish")
+			So(params[0].Get("TopicArn"), ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo")
+			So(params[0].Get("Message"), ShouldEqual, "hello")
+			So(params[0].Get("Subject"), ShouldEqual, "greeting")
+			So(params[0].Get("MessageStructure"), ShouldEqual, "")
+		})
+	})
+	Convey("Given a message with attributes", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(testPublished, &params)
+		defer ts.Close()
+
+		_, err := topic.Publish(Message{Message: "hello", Attributes: map[string]MessageAttributeValue{"size": NumberAttribute("12")}})
+
+		Convey("It sends the attributes", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("MessageAttributes.entry.1.Name"), ShouldEqual, "size")
+			So(params[0].Get("MessageAttributes.entry.1.Value.DataType"), ShouldEqual, "Number")
+			So(params[0].Get("MessageAttributes.entry.1.Value.StringValue"), ShouldEqual, "12")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := topic.Publish(Message{Message: "hello"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a topic that returns bad data", t, func() {
+		ts, topic := testTopic(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := topic.Publish(Message{Message: "hello"})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestPublishToPhone(t *testing.T) {
+	Convey("Given a phone number", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testPublished, &params)
+		defer ts.Close()
+
+		id, err := s.PublishToPhone("+14155552671", "hello")
+
+		Convey("It sends the message to the phone number", func() {
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "94f20ce6-13c5-43a0-9a9e-ca52d816e90b")
+			So(params[0].Get("PhoneNumber"), ShouldEqual, "+14155552671")
+			So(params[0].Get("TopicArn"), ShouldEqual, "")
+		})
+	})
+}
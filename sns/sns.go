@@ -0,0 +1,114 @@
+// Package sns provides a way to interact with the AWS Simple Notification Service.
+package sns
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/sqs"
+)
+
+//go:generate go run ../internal/errgen -type snsError -field Code -service SNS NotFound InvalidParameter AuthorizationError EndpointDisabled PlatformApplicationDisabled SubscriptionLimitExceeded TopicLimitExceeded InternalError
+
+// snsError is the error document returned from the SNS service.
+type snsError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the snsError into an error message.
+func (e snsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func snsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := snsError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" || error.Code == "ThrottledException" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// SNSService is the SNS service at AWS. The endpoint for a region is gaws.Endpoint("sns", region).
+type SNSService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// SNSAPI is implemented by *SNSService. Code that takes an SNSAPI can be given a fake in tests instead.
+type SNSAPI interface {
+	CreateTopic(name string, attributes TopicAttributes) (Topic, error)
+	ListTopics() ([]Topic, error)
+	PublishToPhone(phoneNumber string, message string) (string, error)
+}
+
+var _ SNSAPI = (*SNSService)(nil)
+
+// New returns an SNSService with the options applied. Its endpoint is gaws.Endpoint("sns", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *SNSService {
+	config := gaws.NewConfig(options...)
+	return &SNSService{Endpoint: config.ServiceEndpoint("sns"), config: config}
+}
+
+func (s *SNSService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: snsRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("sns", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an SNS API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2010-03-31"},
+	}
+}
+
+// Topic is an SNS topic.
+type Topic struct {
+	Arn     string      // The ARN of the topic
+	Service *SNSService // The service for this region
+}
+
+// TopicAPI is implemented by *Topic. Code that takes a TopicAPI can be given a fake in tests instead.
+type TopicAPI interface {
+	ConfirmSubscription(token string) (Subscription, error)
+	Delete() error
+	GetAttributes() (TopicAttributes, error)
+	ListSubscriptions() ([]Subscription, error)
+	Publish(m Message) (string, error)
+	SetAttribute(name string, value string) error
+	SetAttributes(attributes TopicAttributes) error
+	Subscribe(protocol string, endpoint string, attributes SubscriptionAttributes) (Subscription, error)
+	SubscribeQueues(queues ...sqs.QueueAPI) ([]Subscription, error)
+}
+
+var _ TopicAPI = (*Topic)(nil)
@@ -0,0 +1,181 @@
+package sns
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// Subscription is a subscription to a topic.
+type Subscription struct {
+	Arn      string `xml:"SubscriptionArn"` // The ARN of the subscription. It is "pending confirmation" until the endpoint confirms it.
+	Endpoint string // Where messages are delivered, like a URL, email address, or queue ARN
+	Owner    string
+	Protocol string // How messages are delivered, like http, https, email, sqs, or lambda
+	TopicArn string
+	Service  *SNSService `xml:"-"` // The service for this region
+}
+
+// SubscriptionAttributes are the attributes of a subscription that can be set when subscribing.
+// Fields that are empty or false are not sent, so SNS uses the default.
+type SubscriptionAttributes struct {
+	DeliveryPolicy     string // How SNS retries deliveries to an HTTP endpoint, as a JSON document
+	FilterPolicy       string // Which messages are delivered, as a JSON document matched against their message attributes
+	RawMessageDelivery bool   // Deliver the message as it was published, instead of wrapped in JSON. It is for sqs and http/s subscriptions.
+}
+
+// attributeMap returns the attributes that are set.
+func (a SubscriptionAttributes) attributeMap() map[string]string {
+	m := map[string]string{}
+
+	if a.DeliveryPolicy != "" {
+		m["DeliveryPolicy"] = a.DeliveryPolicy
+	}
+	if a.FilterPolicy != "" {
+		m["FilterPolicy"] = a.FilterPolicy
+	}
+	if a.RawMessageDelivery {
+		m["RawMessageDelivery"] = "true"
+	}
+	return m
+}
+
+type subscribeResponse struct {
+	SubscriptionArn string `xml:"SubscribeResult>SubscriptionArn"`
+}
+
+// Subscribe subscribes an endpoint to the topic. Most endpoints have to confirm the subscription before messages are delivered to them.
+// It returns the Subscription, whose Arn is "pending confirmation" if it still has to be confirmed.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Subscribe.html for more details.
+func (t *Topic) Subscribe(protocol string, endpoint string, attributes SubscriptionAttributes) (Subscription, error) {
+	result := subscribeResponse{}
+
+	params := query("Subscribe")
+	params.Set("TopicArn", t.Arn)
+	params.Set("Protocol", protocol)
+	params.Set("Endpoint", endpoint)
+	params.Set("ReturnSubscriptionArn", "true")
+	setEntryParams(params, attributes.attributeMap())
+
+	req := t.Service.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return Subscription{}, err
+	}
+	return Subscription{Arn: result.SubscriptionArn, Endpoint: endpoint, Protocol: protocol, TopicArn: t.Arn, Service: t.Service}, nil
+}
+
+type confirmSubscriptionResponse struct {
+	SubscriptionArn string `xml:"ConfirmSubscriptionResult>SubscriptionArn"`
+}
+
+// ConfirmSubscription confirms a subscription to the topic with the token SNS sent to the endpoint.
+// See http://docs.aws.amazon.com/sns/latest/api/API_ConfirmSubscription.html for more details.
+func (t *Topic) ConfirmSubscription(token string) (Subscription, error) {
+	result := confirmSubscriptionResponse{}
+
+	params := query("ConfirmSubscription")
+	params.Set("TopicArn", t.Arn)
+	params.Set("Token", token)
+
+	req := t.Service.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return Subscription{}, err
+	}
+	return Subscription{Arn: result.SubscriptionArn, TopicArn: t.Arn, Service: t.Service}, nil
+}
+
+type listSubscriptionsByTopicResponse struct {
+	NextToken     string         `xml:"ListSubscriptionsByTopicResult>NextToken"`
+	Subscriptions []Subscription `xml:"ListSubscriptionsByTopicResult>Subscriptions>member"`
+}
+
+// ListSubscriptions lists all of the subscriptions to the topic. It is calling the ListSubscriptionsByTopic API call, and keeps asking for pages until there are no more.
+// See http://docs.aws.amazon.com/sns/latest/api/API_ListSubscriptionsByTopic.html for more details.
+func (t *Topic) ListSubscriptions() ([]Subscription, error) {
+	subscriptions := []Subscription{}
+
+	params := query("ListSubscriptionsByTopic")
+	params.Set("TopicArn", t.Arn)
+
+	for {
+		result := listSubscriptionsByTopicResponse{}
+
+		req := t.Service.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Subscription{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Subscription{}, err
+		}
+
+		for _, s := range result.Subscriptions {
+			s.Service = t.Service
+			subscriptions = append(subscriptions, s)
+		}
+
+		if result.NextToken == "" {
+			return subscriptions, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
+
+// Unsubscribe deletes the subscription.
+// See http://docs.aws.amazon.com/sns/latest/api/API_Unsubscribe.html for more details.
+func (s *Subscription) Unsubscribe() error {
+	params := query("Unsubscribe")
+	params.Set("SubscriptionArn", s.Arn)
+
+	req := s.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+// SetAttribute sets one attribute of the subscription. It is calling the SetSubscriptionAttributes API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_SetSubscriptionAttributes.html for more details.
+func (s *Subscription) SetAttribute(name string, value string) error {
+	params := query("SetSubscriptionAttributes")
+	params.Set("SubscriptionArn", s.Arn)
+	params.Set("AttributeName", name)
+	params.Set("AttributeValue", value)
+
+	req := s.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+// SetFilterPolicy sets the JSON document that decides which messages are delivered to the subscription. An empty policy delivers every message.
+func (s *Subscription) SetFilterPolicy(policy string) error {
+	return s.SetAttribute("FilterPolicy", policy)
+}
+
+// SetRawMessageDelivery turns raw message delivery on or off. With it on, messages are delivered as they were published, instead of wrapped in JSON.
+func (s *Subscription) SetRawMessageDelivery(raw bool) error {
+	return s.SetAttribute("RawMessageDelivery", strconv.FormatBool(raw))
+}
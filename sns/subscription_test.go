@@ -0,0 +1,183 @@
+package sns
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testSubscription returns a subscription on a server that runs handler and records the parameters of every request.
+func testSubscription(handler http.HandlerFunc, params *[]url.Values) (func(), Subscription) {
+	ts, s := testService(handler, params)
+	return ts.Close, Subscription{Arn: "arn:aws:sns:us-east-1:123456789012:foo:80289ba6-0fd4-4079-afb4-ce8c8260f0ca", Service: &s}
+}
+
+func TestSubscriptionAttributes(t *testing.T) {
+	Convey("Given subscription attributes", t, func() {
+		a := SubscriptionAttributes{FilterPolicy: `{"color":["red"]}`, RawMessageDelivery: true}
+
+		Convey("Only the fields that are set are sent", func() {
+			So(a.attributeMap(), ShouldResemble, map[string]string{"FilterPolicy": `{"color":["red"]}`, "RawMessageDelivery": "true"})
+		})
+	})
+}
+
+func TestSubscribe(t *testing.T) {
+	Convey("Given a topic that accepts subscriptions", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<SubscribeResponse><SubscribeResult><SubscriptionArn>pending confirmation</SubscriptionArn></SubscribeResult></SubscribeResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		s, err := topic.Subscribe("https", "https://example.com/sns", SubscriptionAttributes{RawMessageDelivery: true})
+
+		Convey("It returns the subscription", func() {
+			So(err, ShouldBeNil)
+			So(s.Arn, ShouldEqual, "pending confirmation")
+			So(s.Endpoint, ShouldEqual, "https://example.com/sns")
+			So(s.TopicArn, ShouldEqual, topic.Arn)
+		})
+		Convey("It sends the protocol, endpoint and attributes", func() {
+			So(params[0].Get("Protocol"), ShouldEqual, "https")
+			So(params[0].Get("Endpoint"), ShouldEqual, "https://example.com/sns")
+			So(params[0].Get("Attributes.entry.1.key"), ShouldEqual, "RawMessageDelivery")
+			So(params[0].Get("Attributes.entry.1.value"), ShouldEqual, "true")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := topic.Subscribe("sqs", "arn:aws:sqs:us-east-1:123456789012:foo", SubscriptionAttributes{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestConfirmSubscription(t *testing.T) {
+	Convey("Given a topic with a pending subscription", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<ConfirmSubscriptionResponse><ConfirmSubscriptionResult><SubscriptionArn>arn:aws:sns:us-east-1:123456789012:foo:1</SubscriptionArn></ConfirmSubscriptionResult></ConfirmSubscriptionResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		s, err := topic.ConfirmSubscription("token")
+
+		Convey("It returns the confirmed subscription", func() {
+			So(err, ShouldBeNil)
+			So(s.Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo:1")
+			So(params[0].Get("Token"), ShouldEqual, "token")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := topic.ConfirmSubscription("token")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListSubscriptions(t *testing.T) {
+	Convey("Given a topic with subscriptions over two pages", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			if r.PostForm.Get("NextToken") == "" {
+				w.Write([]byte("<ListSubscriptionsByTopicResponse><ListSubscriptionsByTopicResult><Subscriptions><member><TopicArn>arn:aws:sns:us-east-1:123456789012:foo</TopicArn><Protocol>email</Protocol><SubscriptionArn>arn:aws:sns:us-east-1:123456789012:foo:1</SubscriptionArn><Owner>123456789012</Owner><Endpoint>someone@example.com</Endpoint></member></Subscriptions><NextToken>next</NextToken></ListSubscriptionsByTopicResult></ListSubscriptionsByTopicResponse>"))
+				return
+			}
+			w.Write([]byte("<ListSubscriptionsByTopicResponse><ListSubscriptionsByTopicResult><Subscriptions><member><Protocol>sqs</Protocol><SubscriptionArn>arn:aws:sns:us-east-1:123456789012:foo:2</SubscriptionArn></member></Subscriptions></ListSubscriptionsByTopicResult></ListSubscriptionsByTopicResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		subscriptions, err := topic.ListSubscriptions()
+
+		Convey("It returns the subscriptions from every page", func() {
+			So(err, ShouldBeNil)
+			So(len(subscriptions), ShouldEqual, 2)
+			So(subscriptions[0].Endpoint, ShouldEqual, "someone@example.com")
+			So(subscriptions[0].Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo:1")
+			So(subscriptions[1].Protocol, ShouldEqual, "sqs")
+			So(subscriptions[1].Service, ShouldEqual, topic.Service)
+		})
+		Convey("It sends the topic and the token for the next page", func() {
+			So(params[0].Get("TopicArn"), ShouldEqual, topic.Arn)
+			So(params[1].Get("NextToken"), ShouldEqual, "next")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		subscriptions, err := topic.ListSubscriptions()
+
+		Convey("It returns an error and no subscriptions", func() {
+			So(err, ShouldNotBeNil)
+			So(subscriptions, ShouldResemble, []Subscription{})
+		})
+	})
+}
+
+func TestUnsubscribe(t *testing.T) {
+	Convey("Given a subscription", t, func() {
+		params := []url.Values{}
+		stop, s := testSubscription(testHTTP200, &params)
+		defer stop()
+
+		err := s.Unsubscribe()
+
+		Convey("It deletes the subscription", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "Unsubscribe")
+			So(params[0].Get("SubscriptionArn"), ShouldEqual, s.Arn)
+		})
+	})
+	Convey("Given a subscription that returns errors", t, func() {
+		stop, s := testSubscription(testHTTP400, nil)
+		defer stop()
+
+		Convey("It returns an error", func() {
+			So(s.Unsubscribe(), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSetSubscriptionAttributes(t *testing.T) {
+	Convey("Given a subscription", t, func() {
+		params := []url.Values{}
+		stop, s := testSubscription(testHTTP200, &params)
+		defer stop()
+
+		filterErr := s.SetFilterPolicy(`{"color":["red"]}`)
+		rawErr := s.SetRawMessageDelivery(false)
+
+		Convey("It sets the filter policy", func() {
+			So(filterErr, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "SetSubscriptionAttributes")
+			So(params[0].Get("AttributeName"), ShouldEqual, "FilterPolicy")
+			So(params[0].Get("AttributeValue"), ShouldEqual, `{"color":["red"]}`)
+		})
+		Convey("It sets raw message delivery", func() {
+			So(rawErr, ShouldBeNil)
+			So(params[1].Get("AttributeName"), ShouldEqual, "RawMessageDelivery")
+			So(params[1].Get("AttributeValue"), ShouldEqual, "false")
+		})
+	})
+	Convey("Given a subscription that returns errors", t, func() {
+		stop, s := testSubscription(testHTTP400, nil)
+		defer stop()
+
+		Convey("It returns an error", func() {
+			So(s.SetFilterPolicy("{}"), ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,222 @@
+package sns
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+)
+
+// TopicAttributes are the attributes of a topic.
+// When a topic is created or its attributes are set, fields that are empty are not sent, so SNS keeps its current value or uses the default.
+type TopicAttributes struct {
+	DeliveryPolicy string // How SNS retries deliveries to HTTP endpoints, as a JSON document
+	DisplayName    string // The name used in the "From" of SMS and email messages
+	KmsMasterKeyId string // The KMS key used to encrypt messages at rest
+	Policy         string // The topic's access policy document
+
+	// These are set by SNS and ignored when setting attributes.
+	EffectiveDeliveryPolicy string
+	Owner                   string
+	SubscriptionsConfirmed  int
+	SubscriptionsDeleted    int
+	SubscriptionsPending    int
+	TopicArn                string
+}
+
+// attributeMap returns the attributes that can be set and are not empty.
+func (a TopicAttributes) attributeMap() map[string]string {
+	m := map[string]string{}
+
+	values := map[string]string{
+		"DeliveryPolicy": a.DeliveryPolicy,
+		"DisplayName":    a.DisplayName,
+		"KmsMasterKeyId": a.KmsMasterKeyId,
+		"Policy":         a.Policy,
+	}
+	for name, value := range values {
+		if value != "" {
+			m[name] = value
+		}
+	}
+	return m
+}
+
+// topicAttributesFromMap turns the attributes SNS returns into TopicAttributes. Attributes it does not know about are ignored.
+func topicAttributesFromMap(m map[string]string) TopicAttributes {
+	a := TopicAttributes{
+		DeliveryPolicy:          m["DeliveryPolicy"],
+		DisplayName:             m["DisplayName"],
+		EffectiveDeliveryPolicy: m["EffectiveDeliveryPolicy"],
+		KmsMasterKeyId:          m["KmsMasterKeyId"],
+		Owner:                   m["Owner"],
+		Policy:                  m["Policy"],
+		TopicArn:                m["TopicArn"],
+	}
+
+	a.SubscriptionsConfirmed, _ = strconv.Atoi(m["SubscriptionsConfirmed"])
+	a.SubscriptionsDeleted, _ = strconv.Atoi(m["SubscriptionsDeleted"])
+	a.SubscriptionsPending, _ = strconv.Atoi(m["SubscriptionsPending"])
+	return a
+}
+
+// entry is a key and value in an SNS attribute map.
+type entry struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// entriesToMap turns the entries of an SNS attribute map into a map.
+func entriesToMap(entries []entry) map[string]string {
+	m := map[string]string{}
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+// setEntryParams adds the attributes to the parameters of a request as numbered Attributes.entry.N.key and Attributes.entry.N.value pairs.
+func setEntryParams(params url.Values, attributes map[string]string) {
+	n := 1
+	for name, value := range attributes {
+		params.Set("Attributes.entry."+strconv.Itoa(n)+".key", name)
+		params.Set("Attributes.entry."+strconv.Itoa(n)+".value", value)
+		n++
+	}
+}
+
+type createTopicResponse struct {
+	TopicArn string `xml:"CreateTopicResult>TopicArn"`
+}
+
+// CreateTopic creates a new SNS topic. It returns the Topic and an error if it fails.
+// If a topic with the same name already exists, it returns that topic.
+// See http://docs.aws.amazon.com/sns/latest/api/API_CreateTopic.html for more details.
+func (s *SNSService) CreateTopic(name string, attributes TopicAttributes) (Topic, error) {
+	result := createTopicResponse{}
+
+	params := query("CreateTopic")
+	params.Set("Name", name)
+	setEntryParams(params, attributes.attributeMap())
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return Topic{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return Topic{}, err
+	}
+	return Topic{Arn: result.TopicArn, Service: s}, nil
+}
+
+type listTopicsResponse struct {
+	NextToken string   `xml:"ListTopicsResult>NextToken"`
+	TopicArns []string `xml:"ListTopicsResult>Topics>member>TopicArn"`
+}
+
+// ListTopics lists all of the topics in the region. It keeps asking for pages until there are no more.
+// See http://docs.aws.amazon.com/sns/latest/api/API_ListTopics.html for more details.
+func (s *SNSService) ListTopics() ([]Topic, error) {
+	topics := []Topic{}
+
+	params := query("ListTopics")
+
+	for {
+		result := listTopicsResponse{}
+
+		req := s.request()
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Topic{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Topic{}, err
+		}
+
+		for _, arn := range result.TopicArns {
+			topics = append(topics, Topic{Arn: arn, Service: s})
+		}
+
+		if result.NextToken == "" {
+			return topics, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
+
+// Delete deletes the topic and all of its subscriptions. It is calling the DeleteTopic API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_DeleteTopic.html for more details.
+func (t *Topic) Delete() error {
+	params := query("DeleteTopic")
+	params.Set("TopicArn", t.Arn)
+
+	req := t.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+type getTopicAttributesResponse struct {
+	Attributes []entry `xml:"GetTopicAttributesResult>Attributes>entry"`
+}
+
+// GetAttributes returns the attributes of the topic. It is calling the GetTopicAttributes API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_GetTopicAttributes.html for more details.
+func (t *Topic) GetAttributes() (TopicAttributes, error) {
+	result := getTopicAttributesResponse{}
+
+	params := query("GetTopicAttributes")
+	params.Set("TopicArn", t.Arn)
+
+	req := t.Service.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return TopicAttributes{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return TopicAttributes{}, err
+	}
+	return topicAttributesFromMap(entriesToMap(result.Attributes)), nil
+}
+
+// SetAttribute sets one attribute of the topic. It is calling the SetTopicAttributes API call.
+// See http://docs.aws.amazon.com/sns/latest/api/API_SetTopicAttributes.html for more details.
+func (t *Topic) SetAttribute(name string, value string) error {
+	params := query("SetTopicAttributes")
+	params.Set("TopicArn", t.Arn)
+	params.Set("AttributeName", name)
+	params.Set("AttributeValue", value)
+
+	req := t.Service.request()
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+// SetAttributes changes the attributes of the topic. Fields that are empty are left alone.
+// SNS only sets one attribute at a time, so it stops at the first one that fails.
+func (t *Topic) SetAttributes(attributes TopicAttributes) error {
+	for name, value := range attributes.attributeMap() {
+		err := t.SetAttribute(name, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package sns
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTopicAttributes(t *testing.T) {
+	Convey("Given topic attributes with some fields set", t, func() {
+		a := TopicAttributes{DisplayName: "Foo", Owner: "123456789012"}
+
+		Convey("Only the settable fields that are not empty are sent", func() {
+			So(a.attributeMap(), ShouldResemble, map[string]string{"DisplayName": "Foo"})
+		})
+	})
+	Convey("Given attributes to send", t, func() {
+		params := url.Values{}
+		setEntryParams(params, map[string]string{"DisplayName": "Foo"})
+
+		Convey("They are numbered key and value pairs", func() {
+			So(params.Get("Attributes.entry.1.key"), ShouldEqual, "DisplayName")
+			So(params.Get("Attributes.entry.1.value"), ShouldEqual, "Foo")
+		})
+	})
+}
+
+func TestCreateTopic(t *testing.T) {
+	Convey("Given a service that creates topics", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<CreateTopicResponse><CreateTopicResult><TopicArn>arn:aws:sns:us-east-1:123456789012:foo</TopicArn></CreateTopicResult></CreateTopicResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		topic, err := s.CreateTopic("foo", TopicAttributes{DisplayName: "Foo"})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the topic", func() {
+			So(topic.Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo")
+			So(topic.Service, ShouldEqual, &s)
+		})
+		Convey("It sends the name and attributes", func() {
+			So(params[0].Get("Name"), ShouldEqual, "foo")
+			So(params[0].Get("Attributes.entry.1.key"), ShouldEqual, "DisplayName")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.CreateTopic("foo", TopicAttributes{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListTopics(t *testing.T) {
+	Convey("Given a service with topics over two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.PostForm.Get("NextToken") == "" {
+				w.Write([]byte("<ListTopicsResponse><ListTopicsResult><Topics><member><TopicArn>arn:aws:sns:us-east-1:123456789012:foo</TopicArn></member><member><TopicArn>arn:aws:sns:us-east-1:123456789012:bar</TopicArn></member></Topics><NextToken>next</NextToken></ListTopicsResult></ListTopicsResponse>"))
+				return
+			}
+			w.Write([]byte("<ListTopicsResponse><ListTopicsResult><Topics><member><TopicArn>arn:aws:sns:us-east-1:123456789012:baz</TopicArn></member></Topics></ListTopicsResult></ListTopicsResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		topics, err := s.ListTopics()
+
+		Convey("It returns the topics from every page", func() {
+			So(err, ShouldBeNil)
+			So(len(topics), ShouldEqual, 3)
+			So(topics[2].Arn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:baz")
+		})
+		Convey("It sends the token for the next page", func() {
+			So(params[1].Get("NextToken"), ShouldEqual, "next")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		topics, err := s.ListTopics()
+
+		Convey("It returns an error and no topics", func() {
+			So(err, ShouldNotBeNil)
+			So(topics, ShouldResemble, []Topic{})
+		})
+	})
+}
+
+func TestDeleteTopic(t *testing.T) {
+	Convey("Given a topic that can be deleted", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(testHTTP200, &params)
+		defer ts.Close()
+
+		err := topic.Delete()
+
+		Convey("It deletes the topic", func() {
+			So(err, ShouldBeNil)
+			So(params[0].Get("Action"), ShouldEqual, "DeleteTopic")
+			So(params[0].Get("TopicArn"), ShouldEqual, topic.Arn)
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		err := topic.Delete()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestGetTopicAttributes(t *testing.T) {
+	Convey("Given a topic with attributes", t, func() {
+		ts, topic := testTopic(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<GetTopicAttributesResponse><GetTopicAttributesResult><Attributes>
+  <entry><key>Owner</key><value>123456789012</value></entry>
+  <entry><key>DisplayName</key><value>Foo</value></entry>
+  <entry><key>SubscriptionsConfirmed</key><value>2</value></entry>
+  <entry><key>TopicArn</key><value>arn:aws:sns:us-east-1:123456789012:foo</value></entry>
+</Attributes></GetTopicAttributesResult></GetTopicAttributesResponse>`))
+		}, nil)
+		defer ts.Close()
+
+		a, err := topic.GetAttributes()
+
+		Convey("It returns the attributes", func() {
+			So(err, ShouldBeNil)
+			So(a.Owner, ShouldEqual, "123456789012")
+			So(a.DisplayName, ShouldEqual, "Foo")
+			So(a.SubscriptionsConfirmed, ShouldEqual, 2)
+			So(a.TopicArn, ShouldEqual, "arn:aws:sns:us-east-1:123456789012:foo")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := topic.GetAttributes()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSetTopicAttributes(t *testing.T) {
+	Convey("Given a topic", t, func() {
+		params := []url.Values{}
+		ts, topic := testTopic(testHTTP200, &params)
+		defer ts.Close()
+
+		err := topic.SetAttributes(TopicAttributes{DisplayName: "Foo"})
+
+		Convey("It sets each attribute that is set", func() {
+			So(err, ShouldBeNil)
+			So(len(params), ShouldEqual, 1)
+			So(params[0].Get("Action"), ShouldEqual, "SetTopicAttributes")
+			So(params[0].Get("AttributeName"), ShouldEqual, "DisplayName")
+			So(params[0].Get("AttributeValue"), ShouldEqual, "Foo")
+		})
+	})
+	Convey("Given a topic that returns errors", t, func() {
+		ts, topic := testTopic(testHTTP400, nil)
+		defer ts.Close()
+
+		err := topic.SetAttributes(TopicAttributes{DisplayName: "Foo", Policy: "{}"})
+
+		Convey("It stops at the first error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,125 @@
+package sqs
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// QueueAttributes are the attributes of a queue. Times are in seconds.
+// When a queue is created or its attributes are set, fields that are zero are not sent, so SQS keeps its current value or uses the default.
+type QueueAttributes struct {
+	ContentBasedDeduplication     bool           // FIFO queues only. Use a hash of the body when a message has no MessageDeduplicationId.
+	DelaySeconds                  int            // How long new messages are hidden. Up to 900.
+	FifoQueue                     bool           // Makes a FIFO queue. The queue's name must end in .fifo. It cannot be changed after the queue is created.
+	MaximumMessageSize            int            // The largest message, in bytes, that the queue accepts. Up to 262144.
+	MessageRetentionPeriod        int            // How long messages are kept. From 60 to 1209600 (14 days).
+	Policy                        string         // The queue's access policy document
+	ReceiveMessageWaitTimeSeconds int            // How long ReceiveMessage waits for messages to arrive. Up to 20.
+	RedrivePolicy                 *RedrivePolicy // Where messages go after they have been received too many times
+	VisibilityTimeout             int            // How long a received message is hidden from other receivers. Up to 43200 (12 hours).
+
+	// These are set by SQS and ignored when setting attributes.
+	ApproximateNumberOfMessages           int
+	ApproximateNumberOfMessagesDelayed    int
+	ApproximateNumberOfMessagesNotVisible int
+	CreatedTimestamp                      int64
+	LastModifiedTimestamp                 int64
+	QueueArn                              string
+}
+
+// attributeMap returns the attributes that can be set and are not zero.
+func (a QueueAttributes) attributeMap() map[string]string {
+	m := map[string]string{}
+
+	ints := map[string]int{
+		"DelaySeconds":                  a.DelaySeconds,
+		"MaximumMessageSize":            a.MaximumMessageSize,
+		"MessageRetentionPeriod":        a.MessageRetentionPeriod,
+		"ReceiveMessageWaitTimeSeconds": a.ReceiveMessageWaitTimeSeconds,
+		"VisibilityTimeout":             a.VisibilityTimeout,
+	}
+	for name, value := range ints {
+		if value != 0 {
+			m[name] = strconv.Itoa(value)
+		}
+	}
+
+	if a.Policy != "" {
+		m["Policy"] = a.Policy
+	}
+
+	if a.FifoQueue {
+		m["FifoQueue"] = "true"
+	}
+	if a.ContentBasedDeduplication {
+		m["ContentBasedDeduplication"] = "true"
+	}
+
+	if a.RedrivePolicy != nil {
+		policy, _ := json.Marshal(a.RedrivePolicy)
+		m["RedrivePolicy"] = string(policy)
+	}
+	return m
+}
+
+// queueAttributesFromMap fills in QueueAttributes from the attributes SQS returns. Attributes gaws does not know about are ignored.
+func queueAttributesFromMap(m map[string]string) QueueAttributes {
+	a := QueueAttributes{
+		ContentBasedDeduplication: m["ContentBasedDeduplication"] == "true",
+		FifoQueue:                 m["FifoQueue"] == "true",
+		Policy:                    m["Policy"],
+		QueueArn:                  m["QueueArn"],
+	}
+
+	ints := map[string]*int{
+		"DelaySeconds":                          &a.DelaySeconds,
+		"MaximumMessageSize":                    &a.MaximumMessageSize,
+		"MessageRetentionPeriod":                &a.MessageRetentionPeriod,
+		"ReceiveMessageWaitTimeSeconds":         &a.ReceiveMessageWaitTimeSeconds,
+		"VisibilityTimeout":                     &a.VisibilityTimeout,
+		"ApproximateNumberOfMessages":           &a.ApproximateNumberOfMessages,
+		"ApproximateNumberOfMessagesDelayed":    &a.ApproximateNumberOfMessagesDelayed,
+		"ApproximateNumberOfMessagesNotVisible": &a.ApproximateNumberOfMessagesNotVisible,
+	}
+	for name, field := range ints {
+		*field, _ = strconv.Atoi(m[name])
+	}
+
+	a.CreatedTimestamp, _ = strconv.ParseInt(m["CreatedTimestamp"], 10, 64)
+	a.LastModifiedTimestamp, _ = strconv.ParseInt(m["LastModifiedTimestamp"], 10, 64)
+
+	if m["RedrivePolicy"] != "" {
+		policy := RedrivePolicy{}
+		if json.Unmarshal([]byte(m["RedrivePolicy"]), &policy) == nil {
+			a.RedrivePolicy = &policy
+		}
+	}
+	return a
+}
+
+// setAttributeParams adds attributes to the parameters of a request as Attribute.N.Name and Attribute.N.Value.
+func setAttributeParams(params url.Values, attributes map[string]string) {
+	n := 1
+	for name, value := range attributes {
+		prefix := "Attribute." + strconv.Itoa(n)
+		params.Set(prefix+".Name", name)
+		params.Set(prefix+".Value", value)
+		n++
+	}
+}
+
+// attribute is a name and value pair in an SQS response.
+type attribute struct {
+	Name  string
+	Value string
+}
+
+// attributesToMap turns the attributes from an SQS response into a map.
+func attributesToMap(attributes []attribute) map[string]string {
+	m := map[string]string{}
+	for _, a := range attributes {
+		m[a.Name] = a.Value
+	}
+	return m
+}
@@ -0,0 +1,53 @@
+package sqs
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestQueueAttributes(t *testing.T) {
+	Convey("Given queue attributes with some fields set", t, func() {
+		a := QueueAttributes{VisibilityTimeout: 60, MessageRetentionPeriod: 86400, ApproximateNumberOfMessages: 5}
+
+		Convey("Only the settable fields that are not zero are sent", func() {
+			So(a.attributeMap(), ShouldResemble, map[string]string{"VisibilityTimeout": "60", "MessageRetentionPeriod": "86400"})
+		})
+	})
+	Convey("Given the attributes of a FIFO queue", t, func() {
+		a := QueueAttributes{FifoQueue: true, ContentBasedDeduplication: true}
+
+		Convey("They are sent as true", func() {
+			So(a.attributeMap(), ShouldResemble, map[string]string{"FifoQueue": "true", "ContentBasedDeduplication": "true"})
+		})
+		Convey("They are parsed back", func() {
+			So(queueAttributesFromMap(a.attributeMap()), ShouldResemble, a)
+		})
+	})
+	Convey("Given the attributes SQS returns", t, func() {
+		a := queueAttributesFromMap(map[string]string{
+			"VisibilityTimeout":           "30",
+			"ApproximateNumberOfMessages": "12",
+			"CreatedTimestamp":            "1286771522",
+			"QueueArn":                    "arn:aws:sqs:us-east-1:123456789012:foo",
+			"SomethingNew":                "true",
+		})
+
+		Convey("They are parsed into the struct", func() {
+			So(a.VisibilityTimeout, ShouldEqual, 30)
+			So(a.ApproximateNumberOfMessages, ShouldEqual, 12)
+			So(a.CreatedTimestamp, ShouldEqual, 1286771522)
+			So(a.QueueArn, ShouldEqual, "arn:aws:sqs:us-east-1:123456789012:foo")
+		})
+	})
+	Convey("Given attributes to send", t, func() {
+		params := url.Values{}
+		setAttributeParams(params, map[string]string{"VisibilityTimeout": "60"})
+
+		Convey("They are numbered name and value pairs", func() {
+			So(params.Get("Attribute.1.Name"), ShouldEqual, "VisibilityTimeout")
+			So(params.Get("Attribute.1.Value"), ShouldEqual, "60")
+		})
+	})
+}
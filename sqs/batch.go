@@ -0,0 +1,224 @@
+package sqs
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// maxBatchEntries is the most entries SQS accepts in one batch request.
+const maxBatchEntries = 10
+
+var batchFailedError = sqsError{Type: "Gaws", Code: "GawsBatchEntriesFailed", Message: "Some entries in the batch failed. The result for each entry has its error."}
+
+var missingResultError = sqsError{Type: "Gaws", Code: "GawsMissingResult", Message: "SQS did not return a result for this entry."}
+
+var outOfOrderError = sqsError{Type: "Gaws", Code: "GawsEarlierMessageFailed", Message: "An earlier message in this message group failed, so this one was not sent."}
+
+// BatchResult is the result of one entry in a batch request.
+type BatchResult struct {
+	MessageId      string // The ID of the sent message. It is empty for other batch requests.
+	SequenceNumber string // The sequence number of a message sent to a FIFO queue
+	Err            error  // Why the entry failed, or nil if it succeeded
+
+	// The checksums SQS computed for a sent message
+	md5OfBody       string
+	md5OfAttributes string
+}
+
+// batchResultEntry is a successful or failed entry in a batch response. Which one it is comes from the name of the element.
+type batchResultEntry struct {
+	XMLName                xml.Name
+	Id                     string
+	MessageId              string
+	MD5OfMessageAttributes string
+	MD5OfMessageBody       string
+	SequenceNumber         string
+	SenderFault            bool
+	Code                   string
+	Message                string
+}
+
+type batchResponse struct {
+	Result struct {
+		Entries []batchResultEntry `xml:",any"`
+	} `xml:",any"`
+	ResponseMetadata struct{}
+}
+
+// batch sends entries in chunks of 10. entry adds the parameters for the entry at index i, under prefix.
+// Entries that fail because of SQS, and not the sender, are retried with the backoff and tries of the service's retry policy.
+// If group is not nil, it returns the message group of each entry. Once an entry fails, later entries in its group are not sent, so they cannot get ahead of it.
+// If size is not nil, it returns the size of each entry, and a chunk is also ended before its entries add up to more than SQS takes in one request.
+func (q *Queue) batch(action string, entryName string, n int, group func(i int) string, size func(i int) int, entry func(params url.Values, prefix string, i int)) ([]BatchResult, error) {
+	results := make([]BatchResult, n)
+	for i := range results {
+		results[i].Err = missingResultError
+	}
+	failedGroups := map[string]bool{}
+
+	for next := 0; next < n; {
+		chunk := []int{}
+		chunkSize := 0
+		for ; next < n && len(chunk) < maxBatchEntries; next++ {
+			if group != nil && failedGroups[group(next)] {
+				results[next].Err = outOfOrderError
+				continue
+			}
+			if size != nil {
+				if len(chunk) > 0 && chunkSize+size(next) > maxMessageSize {
+					break
+				}
+				chunkSize += size(next)
+			}
+			chunk = append(chunk, next)
+		}
+
+		pending := chunk
+		var waited time.Duration
+		for try := 1; len(pending) > 0; try++ {
+			params := query(action)
+			for j, i := range pending {
+				prefix := entryName + "." + strconv.Itoa(j+1)
+				params.Set(prefix+".Id", strconv.Itoa(i))
+				entry(params, prefix, i)
+			}
+
+			retry, err := q.sendBatch(params, results)
+			if err != nil {
+				return results, err
+			}
+			if q.dryRun() {
+				// A dry run has no results, and every entry would have been sent.
+				for _, i := range pending {
+					results[i] = BatchResult{}
+				}
+				break
+			}
+
+			if len(retry) == 0 || try >= q.Service.config.RetryPolicy.Tries() {
+				break
+			}
+			pending = retry
+
+			// Back off before the retry, as the service's retry policy says, on its clock
+			waited = q.Service.config.RetryPolicy.Delay(try, waited)
+			<-q.Service.config.CurrentClock().After(waited)
+		}
+
+		for _, i := range chunk {
+			if group != nil && results[i].Err != nil {
+				failedGroups[group(i)] = true
+			}
+		}
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return results, batchFailedError
+		}
+	}
+	return results, nil
+}
+
+// sendBatch makes one batch request and records the result of each entry. It returns the indexes of the entries that should be retried.
+func (q *Queue) sendBatch(params url.Values, results []BatchResult) ([]int, error) {
+	response := batchResponse{}
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return []int{}, err
+	}
+
+	err = xml.Unmarshal(resp, &response)
+	if err != nil {
+		return []int{}, err
+	}
+
+	retry := []int{}
+	for _, e := range response.Result.Entries {
+		i, err := strconv.Atoi(e.Id)
+		if err != nil || i < 0 || i >= len(results) {
+			continue
+		}
+
+		if e.XMLName.Local != "BatchResultErrorEntry" {
+			results[i] = BatchResult{MessageId: e.MessageId, SequenceNumber: e.SequenceNumber, md5OfBody: e.MD5OfMessageBody, md5OfAttributes: e.MD5OfMessageAttributes}
+			continue
+		}
+
+		results[i] = BatchResult{Err: sqsError{Type: "Receiver", Code: e.Code, Message: e.Message}}
+		if e.SenderFault {
+			results[i].Err = sqsError{Type: "Sender", Code: e.Code, Message: e.Message}
+		} else {
+			retry = append(retry, i)
+		}
+	}
+	return retry, nil
+}
+
+// SendMessageBatch sends messages to the queue, 10 at a time, or fewer if they add up to more than the 256 KB SQS takes in one request.
+// It returns a result for each message, in the same order as messages.
+// If any message could not be sent, it also returns an error.
+// On a FIFO queue, once a message fails, the later messages in its message group are not sent, so the group stays in order.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_SendMessageBatch.html for more details.
+func (q *Queue) SendMessageBatch(messages []OutgoingMessage) ([]BatchResult, error) {
+	if q.Offload != nil {
+		offloaded := make([]OutgoingMessage, len(messages))
+		for i, m := range messages {
+			var err error
+			offloaded[i], err = q.Offload.offload(m, q.dryRun())
+			if err != nil {
+				return []BatchResult{}, err
+			}
+		}
+		messages = offloaded
+	}
+
+	var group func(i int) string
+	if q.IsFIFO() {
+		group = func(i int) string { return messages[i].MessageGroupId }
+	}
+
+	size := func(i int) int { return messageSize(messages[i]) }
+	results, err := q.batch("SendMessageBatch", "SendMessageBatchRequestEntry", len(messages), group, size, func(params url.Values, prefix string, i int) {
+		messages[i].setParams(params, prefix+".")
+	})
+
+	for i, r := range results {
+		if r.Err != nil || q.dryRun() {
+			continue
+		}
+
+		results[i].Err = messages[i].checksum(r.md5OfBody, r.md5OfAttributes)
+		if results[i].Err != nil {
+			err = batchFailedError
+		}
+	}
+	return results, err
+}
+
+// DeleteMessageBatch deletes messages from the queue, 10 at a time. It takes the ReceiptHandles of the messages and returns a result for each one, in the same order.
+// If any message could not be deleted, it also returns an error. Bodies of the deleted messages that are in S3 are deleted too.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_DeleteMessageBatch.html for more details.
+func (q *Queue) DeleteMessageBatch(receiptHandles []string) ([]BatchResult, error) {
+	results, err := q.batch("DeleteMessageBatch", "DeleteMessageBatchRequestEntry", len(receiptHandles), nil, nil, func(params url.Values, prefix string, i int) {
+		params.Set(prefix+".ReceiptHandle", sqsReceiptHandle(receiptHandles[i]))
+	})
+
+	for i, r := range results {
+		if r.Err != nil {
+			continue
+		}
+
+		results[i].Err = q.deletePayload(receiptHandles[i])
+		if results[i].Err != nil {
+			err = batchFailedError
+		}
+	}
+	return results, err
+}
@@ -0,0 +1,265 @@
+package sqs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testBatchHandler answers batch requests. Entries whose body or receipt handle is in fail get the error, and the sender is blamed if senderFault is true.
+// Every failure after the first request succeeds.
+func testBatchHandler(action string, fail map[string]bool, senderFault bool, requests *[]url.Values) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, r.PostForm)
+		first := len(*requests) == 1
+
+		fmt.Fprintf(w, "<%vResponse><%vResult>", action, action)
+		for n := 1; ; n++ {
+			prefix := action + "RequestEntry." + strconv.Itoa(n)
+			id := r.PostForm.Get(prefix + ".Id")
+			if id == "" {
+				break
+			}
+			value := r.PostForm.Get(prefix + ".MessageBody")
+			if value == "" {
+				value = r.PostForm.Get(prefix + ".ReceiptHandle")
+			}
+
+			if first && fail[value] {
+				fmt.Fprintf(w, "<BatchResultErrorEntry><Id>%v</Id><SenderFault>%v</SenderFault><Code>InternalError</Code><Message>nope</Message></BatchResultErrorEntry>", id, senderFault)
+				continue
+			}
+			fmt.Fprintf(w, "<%vResultEntry><Id>%v</Id><MessageId>id-%v</MessageId><MD5OfMessageBody>%v</MD5OfMessageBody></%vResultEntry>", action, id, value, md5Hex(value), action)
+		}
+		fmt.Fprintf(w, "</%vResult><ResponseMetadata><RequestId>1</RequestId></ResponseMetadata></%vResponse>", action, action)
+	}
+}
+
+func TestSendMessageBatch(t *testing.T) {
+	bodies := []OutgoingMessage{}
+	for i := 0; i < 23; i++ {
+		bodies = append(bodies, OutgoingMessage{Body: "message " + strconv.Itoa(i)})
+	}
+
+	Convey("Given a queue that accepts batches", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", nil, false, &requests), nil)
+		defer ts.Close()
+
+		results, err := q.SendMessageBatch(bodies)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends the messages 10 at a time", func() {
+			So(len(requests), ShouldEqual, 3)
+			So(requests[2].Get("SendMessageBatchRequestEntry.3.MessageBody"), ShouldEqual, "message 22")
+			So(requests[2].Get("SendMessageBatchRequestEntry.4.MessageBody"), ShouldEqual, "")
+		})
+		Convey("It returns the message IDs in order", func() {
+			So(len(results), ShouldEqual, 23)
+			So(results[17].MessageId, ShouldEqual, "id-message 17")
+		})
+	})
+	Convey("Given a queue where an entry fails because of SQS the first time", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", map[string]bool{"message 4": true}, false, &requests), nil)
+		defer ts.Close()
+
+		clock := gawstest.NewClock(time.Now())
+		q.Service.config.Clock = clock
+
+		results, err := q.SendMessageBatch(bodies[:10])
+
+		Convey("It retries only that entry", func() {
+			So(err, ShouldBeNil)
+			So(len(requests), ShouldEqual, 2)
+			So(requests[1].Get("SendMessageBatchRequestEntry.1.MessageBody"), ShouldEqual, "message 4")
+			So(requests[1].Get("SendMessageBatchRequestEntry.2.MessageBody"), ShouldEqual, "")
+			So(results[4].MessageId, ShouldEqual, "id-message 4")
+		})
+		Convey("It retries after a backoff on the client's clock", func() {
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{200 * time.Millisecond})
+		})
+	})
+	Convey("Given a queue of a client that tries once, where an entry fails because of SQS the first time", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", map[string]bool{"message 4": true}, false, &requests), nil)
+		defer ts.Close()
+		q.Service.config.RetryPolicy = gaws.RetryPolicy{MaxTries: 1}
+
+		results, err := q.SendMessageBatch(bodies[:10])
+
+		Convey("It does not retry the entry", func() {
+			So(err, ShouldResemble, batchFailedError)
+			So(len(requests), ShouldEqual, 1)
+			So(results[4].Err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given ten messages that add up to more than SQS takes in one request", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", nil, false, &requests), nil)
+		defer ts.Close()
+
+		large := []OutgoingMessage{}
+		for i := 0; i < 10; i++ {
+			large = append(large, OutgoingMessage{Body: strconv.Itoa(i) + strings.Repeat("x", 30*1024-1)})
+		}
+		_, err := q.SendMessageBatch(large)
+
+		Convey("They are sent in requests that are small enough", func() {
+			So(err, ShouldBeNil)
+			So(len(requests), ShouldEqual, 2)
+			So(requests[0].Get("SendMessageBatchRequestEntry.8.MessageBody"), ShouldEqual, large[7].Body)
+			So(requests[0].Get("SendMessageBatchRequestEntry.9.MessageBody"), ShouldEqual, "")
+			So(requests[1].Get("SendMessageBatchRequestEntry.2.MessageBody"), ShouldEqual, large[9].Body)
+		})
+	})
+	Convey("Given a queue of a client in dry-run mode", t, func() {
+		ts, q, b, requests := testDryRunQueue()
+		defer ts.Close()
+
+		results, err := q.SendMessageBatch(bodies)
+
+		Convey("The messages would be sent 10 at a time, and are not", func() {
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 23)
+			So(results[22].Err, ShouldBeNil)
+			So(strings.Count(b.String(), "Action=SendMessageBatch"), ShouldEqual, 3)
+			So(*requests, ShouldEqual, 0)
+		})
+	})
+	Convey("Given a queue where an entry fails because of the sender", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", map[string]bool{"message 4": true}, true, &requests), nil)
+		defer ts.Close()
+
+		results, err := q.SendMessageBatch(bodies[:10])
+
+		Convey("It does not retry", func() {
+			So(len(requests), ShouldEqual, 1)
+		})
+		Convey("It returns an error and the error for the entry", func() {
+			So(err, ShouldResemble, batchFailedError)
+			So(results[4].Err.Error(), ShouldEqual, "InternalError: nope")
+			So(results[3].Err, ShouldBeNil)
+		})
+	})
+	Convey("Given messages with attributes", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", nil, false, &requests), nil)
+		defer ts.Close()
+
+		results, err := q.SendMessageBatch([]OutgoingMessage{{Body: "Hello", Attributes: map[string]MessageAttributeValue{"trace": StringAttribute("abc")}}})
+
+		Convey("The attributes are sent with each entry", func() {
+			So(requests[0].Get("SendMessageBatchRequestEntry.1.MessageAttribute.1.Name"), ShouldEqual, "trace")
+		})
+		Convey("A missing attribute checksum is an error for that entry", func() {
+			So(err, ShouldResemble, batchFailedError)
+			So(results[0].Err, ShouldResemble, checksumError)
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := q.SendMessageBatch(bodies)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a queue that leaves entries out of its response", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<SendMessageBatchResponse><SendMessageBatchResult></SendMessageBatchResult></SendMessageBatchResponse>"))
+		}, nil)
+		defer ts.Close()
+
+		results, err := q.SendMessageBatch(bodies[:1])
+
+		Convey("Those entries have errors", func() {
+			So(err, ShouldNotBeNil)
+			So(results[0].Err, ShouldResemble, missingResultError)
+		})
+	})
+}
+
+func TestSendMessageBatchFIFO(t *testing.T) {
+	messages := []OutgoingMessage{}
+	for i := 0; i < 12; i++ {
+		group := "a"
+		if i%2 == 1 {
+			group = "b"
+		}
+		messages = append(messages, OutgoingMessage{Body: "message " + strconv.Itoa(i), MessageGroupId: group})
+	}
+
+	Convey("Given a FIFO queue where a message fails because of the sender", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", map[string]bool{"message 3": true}, true, &requests), nil)
+		defer ts.Close()
+		q.URL += ".fifo"
+
+		results, err := q.SendMessageBatch(messages)
+
+		Convey("It sends the message groups", func() {
+			So(requests[0].Get("SendMessageBatchRequestEntry.1.MessageGroupId"), ShouldEqual, "a")
+			So(requests[0].Get("SendMessageBatchRequestEntry.2.MessageGroupId"), ShouldEqual, "b")
+		})
+		Convey("Later messages in its group are not sent", func() {
+			So(err, ShouldResemble, batchFailedError)
+			So(len(requests), ShouldEqual, 2)
+			So(requests[1].Get("SendMessageBatchRequestEntry.1.MessageBody"), ShouldEqual, "message 10")
+			So(requests[1].Get("SendMessageBatchRequestEntry.2.MessageBody"), ShouldEqual, "")
+			So(results[11].Err, ShouldResemble, outOfOrderError)
+		})
+		Convey("Other groups are not affected", func() {
+			So(results[10].Err, ShouldBeNil)
+			So(results[10].MessageId, ShouldEqual, "id-message 10")
+		})
+	})
+	Convey("Given a standard queue where a message fails because of the sender", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("SendMessageBatch", map[string]bool{"message 3": true}, true, &requests), nil)
+		defer ts.Close()
+
+		results, _ := q.SendMessageBatch(messages)
+
+		Convey("The rest of the messages are still sent", func() {
+			So(len(requests), ShouldEqual, 2)
+			So(results[11].Err, ShouldBeNil)
+		})
+	})
+}
+
+func TestDeleteMessageBatch(t *testing.T) {
+	Convey("Given a queue that accepts batches", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("DeleteMessageBatch", nil, false, &requests), nil)
+		defer ts.Close()
+
+		results, err := q.DeleteMessageBatch([]string{"a", "b", "c"})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 3)
+		})
+		Convey("It sends the receipt handles", func() {
+			So(requests[0].Get("Action"), ShouldEqual, "DeleteMessageBatch")
+			So(strings.Join([]string{
+				requests[0].Get("DeleteMessageBatchRequestEntry.1.ReceiptHandle"),
+				requests[0].Get("DeleteMessageBatchRequestEntry.2.ReceiptHandle"),
+				requests[0].Get("DeleteMessageBatchRequestEntry.3.ReceiptHandle"),
+			}, ","), ShouldEqual, "a,b,c")
+		})
+	})
+}
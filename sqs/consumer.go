@@ -0,0 +1,146 @@
+package sqs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// ReceiveErrorDelay is how long a Consumer's worker waits before receiving again when receiving messages fails.
+var ReceiveErrorDelay = 5 * time.Second
+
+// ReceiveErrorBackoff is how long a Consumer's worker waits before receiving again when receiving messages fails again and again, like
+// gaws.CappedBackoff{Backoff: gaws.ExponentialBackoff{Base: time.Second}, Max: time.Minute}. If it is nil, it waits ReceiveErrorDelay.
+var ReceiveErrorBackoff gaws.Backoff
+
+// receiveErrorWait returns how long to wait after the given failure to receive in a row, when the wait after the one before was last.
+func receiveErrorWait(failures int, last time.Duration) time.Duration {
+	if ReceiveErrorBackoff == nil {
+		return ReceiveErrorDelay
+	}
+	return ReceiveErrorBackoff.Wait(failures, last)
+}
+
+// Handler handles a message received by a Consumer. If it returns an error, the message is not deleted, so it will be received again.
+// If it panics, the panic is logged to gaws.Log and the message is received again, as if it returned an error.
+type Handler func(m Message) error
+
+// Consumer receives messages from a queue with a pool of workers and passes each message to a handler. It is the SQS equivalent of a Kinesis consumer.
+// Messages are deleted when the handler succeeds. When it fails, they become visible again after RetryDelay seconds, so they can be retried.
+// Errors from receiving and deleting messages are returned by Stop. Workers keep going after them.
+type Consumer struct {
+	RetryDelay        int // How long a message that failed stays hidden before it is retried. If it is 0, it is retried right away.
+	VisibilityTimeout int // If it is not 0, messages are kept hidden with a Heartbeat while they are handled. It should not be longer than the queue's visibility timeout.
+
+	queue   QueueAPI
+	handler Handler
+	workers int
+	lock    sync.Mutex // Protects err
+	err     error
+	stop    chan bool
+	running sync.WaitGroup
+}
+
+// NewConsumer returns a Consumer for the queue with workers workers, which each handle one message at a time. Set its fields and then call Start.
+func (q *Queue) NewConsumer(workers int, handler Handler) *Consumer {
+	return NewConsumer(q, workers, handler)
+}
+
+// NewConsumer returns a Consumer for any QueueAPI, like a fake queue in tests. Otherwise it is the same as Queue.NewConsumer.
+func NewConsumer(queue QueueAPI, workers int, handler Handler) *Consumer {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Consumer{queue: queue, handler: handler, workers: workers, stop: make(chan bool)}
+}
+
+// Start starts the workers. Each one long polls the queue for LongPollSeconds at a time.
+func (c *Consumer) Start() {
+	for i := 0; i < c.workers; i++ {
+		c.running.Add(1)
+		go c.work()
+	}
+}
+
+// Stop stops receiving messages and waits for the workers to finish the messages they have. It can take up to LongPollSeconds for a receive to return.
+// It returns the first error the workers had, if there was one. The Consumer cannot be used after it is stopped.
+func (c *Consumer) Stop() error {
+	close(c.stop)
+	c.running.Wait()
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.err
+}
+
+func (c *Consumer) work() {
+	defer c.running.Done()
+
+	failures := 0
+	var waited time.Duration
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+
+		messages, err := c.queue.ReceiveMessage(1, LongPollSeconds)
+		if err != nil {
+			c.fail(err)
+			failures++
+			waited = receiveErrorWait(failures, waited)
+			select {
+			case <-c.stop:
+				return
+			case <-queueClock(c.queue).After(waited):
+			}
+			continue
+		}
+		failures, waited = 0, 0
+
+		for _, m := range messages {
+			c.handle(m)
+		}
+	}
+}
+
+// handle runs the handler on a message, and then deletes it or makes it visible again.
+func (c *Consumer) handle(m Message) {
+	var heartbeat *Heartbeat
+	if c.VisibilityTimeout > 0 {
+		heartbeat = startHeartbeat(c.queue, m.ReceiptHandle, c.VisibilityTimeout)
+	}
+
+	handlerErr := gaws.Protect("sqs.Consumer handler", func() error {
+		return c.handler(m)
+	})
+
+	if heartbeat != nil {
+		err := heartbeat.Stop()
+		if err != nil {
+			c.fail(err)
+		}
+	}
+
+	var err error
+	if handlerErr != nil {
+		err = c.queue.ChangeMessageVisibility(m.ReceiptHandle, c.RetryDelay)
+	} else {
+		err = c.queue.DeleteMessage(m.ReceiptHandle)
+	}
+	if err != nil {
+		c.fail(err)
+	}
+}
+
+// fail records the first error.
+func (c *Consumer) fail(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.err == nil {
+		c.err = err
+	}
+}
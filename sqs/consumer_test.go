@@ -0,0 +1,244 @@
+package sqs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testConsumerQueue returns a queue that gives out n messages, one per receive, and then none. It records every request that is not a receive.
+func testConsumerQueue(n int) (func() []url.Values, *Queue, func()) {
+	lock := sync.Mutex{}
+	requests := []url.Values{}
+	received := 0
+
+	ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		if r.PostForm.Get("Action") != "ReceiveMessage" {
+			requests = append(requests, r.PostForm)
+			w.Write([]byte("<Response></Response>"))
+			return
+		}
+
+		w.Write([]byte("<ReceiveMessageResponse><ReceiveMessageResult>"))
+		if received < n {
+			fmt.Fprintf(w, "<Message><MessageId>%v</MessageId><ReceiptHandle>handle-%v</ReceiptHandle><MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody><Body>Hello World!</Body></Message>", received, received)
+			received++
+		}
+		w.Write([]byte("</ReceiveMessageResult></ReceiveMessageResponse>"))
+	}, nil)
+
+	recorded := func() []url.Values {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]url.Values{}, requests...)
+	}
+	return recorded, &q, ts.Close
+}
+
+// waitForRequests waits up to two seconds for n requests to be recorded.
+func waitForRequests(recorded func() []url.Values, n int) []url.Values {
+	for i := 0; i < 200 && len(recorded()) < n; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	return recorded()
+}
+
+func TestConsumer(t *testing.T) {
+	LongPollSeconds = 0
+	ReceiveErrorDelay = 10 * time.Millisecond
+
+	Convey("Given a consumer whose handler succeeds", t, func() {
+		recorded, q, stop := testConsumerQueue(5)
+		defer stop()
+
+		lock := sync.Mutex{}
+		handled := 0
+		c := q.NewConsumer(3, func(m Message) error {
+			lock.Lock()
+			defer lock.Unlock()
+			handled++
+			return nil
+		})
+		c.Start()
+		requests := waitForRequests(recorded, 5)
+		err := c.Stop()
+
+		Convey("It handles and deletes every message", func() {
+			So(err, ShouldBeNil)
+			So(handled, ShouldEqual, 5)
+			So(len(requests), ShouldEqual, 5)
+			So(requests[0].Get("Action"), ShouldEqual, "DeleteMessage")
+		})
+	})
+	Convey("Given a consumer whose handler fails", t, func() {
+		recorded, q, stop := testConsumerQueue(1)
+		defer stop()
+
+		c := q.NewConsumer(1, func(m Message) error { return errors.New("nope") })
+		c.RetryDelay = 30
+		c.Start()
+		requests := waitForRequests(recorded, 1)
+		c.Stop()
+
+		Convey("It does not delete the message, and delays its retry", func() {
+			So(len(requests), ShouldEqual, 1)
+			So(requests[0].Get("Action"), ShouldEqual, "ChangeMessageVisibility")
+			So(requests[0].Get("ReceiptHandle"), ShouldEqual, "handle-0")
+			So(requests[0].Get("VisibilityTimeout"), ShouldEqual, "30")
+		})
+	})
+	Convey("Given a consumer whose handler panics on the first message", t, func() {
+		recorded, q, stop := testConsumerQueue(2)
+		defer stop()
+
+		c := q.NewConsumer(1, func(m Message) error {
+			if m.MessageId == "0" {
+				panic("bad message")
+			}
+			return nil
+		})
+		c.Start()
+		requests := waitForRequests(recorded, 2)
+		err := c.Stop()
+
+		Convey("The message is retried, and the consumer keeps going", func() {
+			So(err, ShouldBeNil)
+			So(len(requests), ShouldEqual, 2)
+			So(requests[0].Get("Action"), ShouldEqual, "ChangeMessageVisibility")
+			So(requests[0].Get("ReceiptHandle"), ShouldEqual, "handle-0")
+			So(requests[1].Get("Action"), ShouldEqual, "DeleteMessage")
+		})
+	})
+	Convey("Given a consumer with a visibility timeout and a slow handler", t, func() {
+		recorded, q, stop := testConsumerQueue(1)
+		defer stop()
+
+		c := q.NewConsumer(1, func(m Message) error {
+			time.Sleep(1200 * time.Millisecond)
+			return nil
+		})
+		c.VisibilityTimeout = 2
+		c.Start()
+		requests := waitForRequests(recorded, 2)
+		c.Stop()
+
+		Convey("It extends the visibility of the message while it is handled", func() {
+			So(len(requests), ShouldEqual, 2)
+			So(requests[0].Get("Action"), ShouldEqual, "ChangeMessageVisibility")
+			So(requests[1].Get("Action"), ShouldEqual, "DeleteMessage")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		c := q.NewConsumer(1, func(m Message) error { return nil })
+		c.Start()
+		time.Sleep(20 * time.Millisecond)
+		err := c.Stop()
+
+		Convey("Stop returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a queue of a client with a clock that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+		clock := gawstest.NewManualClock(time.Now())
+		q.Service.config.Clock = clock
+
+		c := q.NewConsumer(1, func(m Message) error { return nil })
+		c.Start()
+		clock.BlockUntil(1)
+		c.Stop()
+
+		Convey("It waits on the client's clock before receiving again", func() {
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{ReceiveErrorDelay})
+		})
+	})
+
+	LongPollSeconds = 20
+	ReceiveErrorDelay = 5 * time.Second
+}
+
+// fakeQueue is a QueueAPI without a server. It gives out its messages one per receive, and records the receipt handles it deletes.
+// Calls it does not implement panic, through the nil QueueAPI it embeds.
+type fakeQueue struct {
+	QueueAPI
+	lock     sync.Mutex
+	messages []Message
+	deleted  []string
+}
+
+func (q *fakeQueue) ReceiveMessage(max int, waitTimeSeconds int) ([]Message, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if len(q.messages) == 0 {
+		return nil, nil
+	}
+	m := q.messages[0]
+	q.messages = q.messages[1:]
+	return []Message{m}, nil
+}
+
+func (q *fakeQueue) DeleteMessage(receiptHandle string) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.deleted = append(q.deleted, receiptHandle)
+	return nil
+}
+
+func (q *fakeQueue) deletedHandles() []string {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return append([]string{}, q.deleted...)
+}
+
+func TestConsumerWithFakeQueue(t *testing.T) {
+	Convey("Given a consumer of a fake queue", t, func() {
+		q := &fakeQueue{messages: []Message{{Body: "one", ReceiptHandle: "handle-1"}, {Body: "two", ReceiptHandle: "handle-2"}}}
+		bodies := make(chan string, 2)
+		c := NewConsumer(q, 1, func(m Message) error {
+			bodies <- m.Body
+			return nil
+		})
+		c.Start()
+
+		Convey("It handles and deletes the messages without a server", func() {
+			So(<-bodies, ShouldEqual, "one")
+			So(<-bodies, ShouldEqual, "two")
+			So(c.Stop(), ShouldBeNil)
+			So(q.deletedHandles(), ShouldResemble, []string{"handle-1", "handle-2"})
+		})
+	})
+}
+
+func TestReceiveErrorWait(t *testing.T) {
+	Convey("Given no receive error backoff", t, func() {
+		Convey("Failed receives wait ReceiveErrorDelay", func() {
+			So(receiveErrorWait(3, ReceiveErrorDelay), ShouldEqual, ReceiveErrorDelay)
+		})
+	})
+
+	Convey("Given a receive error backoff", t, func() {
+		ReceiveErrorBackoff = gaws.CappedBackoff{Backoff: gaws.ExponentialBackoff{Base: time.Second}, Max: 3 * time.Second}
+		defer func() { ReceiveErrorBackoff = nil }()
+
+		Convey("Failed receives in a row wait as it says", func() {
+			So(receiveErrorWait(1, 0), ShouldEqual, time.Second)
+			So(receiveErrorWait(2, time.Second), ShouldEqual, 2*time.Second)
+			So(receiveErrorWait(3, 2*time.Second), ShouldEqual, 3*time.Second)
+		})
+	})
+}
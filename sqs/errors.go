@@ -0,0 +1,22 @@
+// Code generated by errgen from the go:generate line in sqs.go. DO NOT EDIT.
+
+package sqs
+
+// These are errors SQS returns. errors.Is(err, sqs.ErrNonExistentQueue) is true for any error with the same code, whatever its message.
+var (
+	ErrNonExistentQueue             = sqsError{Code: "AWS.SimpleQueueService.NonExistentQueue"}
+	ErrQueueAlreadyExists           = sqsError{Code: "QueueAlreadyExists"}
+	ErrQueueDeletedRecently         = sqsError{Code: "AWS.SimpleQueueService.QueueDeletedRecently"}
+	ErrPurgeQueueInProgress         = sqsError{Code: "AWS.SimpleQueueService.PurgeQueueInProgress"}
+	ErrReceiptHandleIsInvalid       = sqsError{Code: "ReceiptHandleIsInvalid"}
+	ErrInvalidMessageContents       = sqsError{Code: "InvalidMessageContents"}
+	ErrOverLimit                    = sqsError{Code: "OverLimit"}
+	ErrTooManyEntriesInBatchRequest = sqsError{Code: "AWS.SimpleQueueService.TooManyEntriesInBatchRequest"}
+	ErrBatchEntryIdsNotDistinct     = sqsError{Code: "AWS.SimpleQueueService.BatchEntryIdsNotDistinct"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e sqsError) Is(target error) bool {
+	t, ok := target.(sqsError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
@@ -0,0 +1,189 @@
+package sqs
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var checksumError = sqsError{Type: "Gaws", Code: "GawsChecksumMismatch", Message: "The MD5 checksum SQS returned does not match the message."}
+
+// md5Hex returns the hex encoded MD5 checksum of s, which is how SQS sends checksums.
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// MessageAttributeValue is the value of a message attribute. Use StringAttribute, NumberAttribute, or BinaryAttribute to make one.
+type MessageAttributeValue struct {
+	BinaryValue []byte // The value of a Binary attribute
+	DataType    string // String, Number, or Binary. A custom type can follow a period, like Number.float.
+	StringValue string // The value of a String or Number attribute
+}
+
+// StringAttribute returns a String message attribute.
+func StringAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "String", StringValue: value}
+}
+
+// NumberAttribute returns a Number message attribute. Numbers are sent as strings so no precision is lost.
+func NumberAttribute(value string) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "Number", StringValue: value}
+}
+
+// BinaryAttribute returns a Binary message attribute.
+func BinaryAttribute(value []byte) MessageAttributeValue {
+	return MessageAttributeValue{DataType: "Binary", BinaryValue: value}
+}
+
+// isBinary reports whether the attribute is sent as binary, including custom types like Binary.gif.
+func (v MessageAttributeValue) isBinary() bool {
+	return strings.HasPrefix(v.DataType, "Binary")
+}
+
+// md5OfMessageAttributes returns the checksum SQS computes for a set of message attributes. It is empty if there are no attributes.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-message-metadata.html for how it is computed.
+func md5OfMessageAttributes(attributes map[string]MessageAttributeValue) string {
+	if len(attributes) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := md5.New()
+	writeWithLength := func(b []byte) {
+		binary.Write(hash, binary.BigEndian, uint32(len(b)))
+		hash.Write(b)
+	}
+
+	for _, name := range names {
+		v := attributes[name]
+		writeWithLength([]byte(name))
+		writeWithLength([]byte(v.DataType))
+		if v.isBinary() {
+			hash.Write([]byte{2})
+			writeWithLength(v.BinaryValue)
+		} else {
+			hash.Write([]byte{1})
+			writeWithLength([]byte(v.StringValue))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// setMessageAttributeParams adds message attributes to the parameters of a request, under prefix.
+func setMessageAttributeParams(params url.Values, prefix string, attributes map[string]MessageAttributeValue) {
+	n := 1
+	for name, v := range attributes {
+		p := prefix + "MessageAttribute." + strconv.Itoa(n)
+		params.Set(p+".Name", name)
+		params.Set(p+".Value.DataType", v.DataType)
+		if v.isBinary() {
+			params.Set(p+".Value.BinaryValue", base64.StdEncoding.EncodeToString(v.BinaryValue))
+		} else {
+			params.Set(p+".Value.StringValue", v.StringValue)
+		}
+		n++
+	}
+}
+
+// OutgoingMessage is a message to send to a queue.
+type OutgoingMessage struct {
+	Body       string                           // The contents of the message
+	Attributes map[string]MessageAttributeValue // Optional message attributes
+
+	// These are for FIFO queues.
+	MessageGroupId         string // Messages in the same group are received in the order they were sent. It is required for FIFO queues.
+	MessageDeduplicationId string // Messages with the same ID sent within 5 minutes are only delivered once. It is not needed if the queue has ContentBasedDeduplication.
+}
+
+// setParams adds the message to the parameters of a request, under prefix.
+func (m OutgoingMessage) setParams(params url.Values, prefix string) {
+	params.Set(prefix+"MessageBody", m.Body)
+	setMessageAttributeParams(params, prefix, m.Attributes)
+
+	if m.MessageGroupId != "" {
+		params.Set(prefix+"MessageGroupId", m.MessageGroupId)
+	}
+	if m.MessageDeduplicationId != "" {
+		params.Set(prefix+"MessageDeduplicationId", m.MessageDeduplicationId)
+	}
+}
+
+// checksum checks the checksums SQS computed for a sent message.
+func (m OutgoingMessage) checksum(md5OfBody string, md5OfAttributes string) error {
+	if md5OfBody != md5Hex(m.Body) || md5OfAttributes != md5OfMessageAttributes(m.Attributes) {
+		return checksumError
+	}
+	return nil
+}
+
+// Message is a message received from a queue.
+type Message struct {
+	Attributes       map[string]MessageAttributeValue // The message attributes it was sent with
+	Body             string                           // The contents of the message
+	MD5OfBody        string
+	MessageGroupId   string // The message group of a message from a FIFO queue
+	MessageId        string
+	ReceiptHandle    string            // Identifies this receipt of the message. It is needed to delete the message.
+	SequenceNumber   string            // The order of a message from a FIFO queue within its group
+	SystemAttributes map[string]string // The attributes SQS sets, like SentTimestamp and ApproximateReceiveCount
+
+	queue *Queue // The queue it was received from, for Ack and Nack
+}
+
+// receivedMessage is a message in a ReceiveMessage response.
+type receivedMessage struct {
+	Body                   string
+	MD5OfBody              string
+	MD5OfMessageAttributes string
+	MessageAttributes      []struct {
+		Name  string
+		Value struct {
+			BinaryValue string
+			DataType    string
+			StringValue string
+		}
+	} `xml:"MessageAttribute"`
+	MessageId        string
+	ReceiptHandle    string
+	SystemAttributes []attribute `xml:"Attribute"`
+}
+
+// message turns a received message into a Message and checks its checksums.
+func (r receivedMessage) message() (Message, error) {
+	m := Message{Body: r.Body, MD5OfBody: r.MD5OfBody, MessageId: r.MessageId, ReceiptHandle: r.ReceiptHandle}
+
+	m.SystemAttributes = attributesToMap(r.SystemAttributes)
+	m.MessageGroupId = m.SystemAttributes["MessageGroupId"]
+	m.SequenceNumber = m.SystemAttributes["SequenceNumber"]
+
+	if len(r.MessageAttributes) > 0 {
+		m.Attributes = map[string]MessageAttributeValue{}
+	}
+	for _, a := range r.MessageAttributes {
+		v := MessageAttributeValue{DataType: a.Value.DataType, StringValue: a.Value.StringValue}
+		if v.isBinary() {
+			b, err := base64.StdEncoding.DecodeString(a.Value.BinaryValue)
+			if err != nil {
+				return Message{}, err
+			}
+			v = MessageAttributeValue{DataType: a.Value.DataType, BinaryValue: b}
+		}
+		m.Attributes[a.Name] = v
+	}
+
+	if r.MD5OfBody != md5Hex(r.Body) || r.MD5OfMessageAttributes != md5OfMessageAttributes(m.Attributes) {
+		return Message{}, checksumError
+	}
+	return m, nil
+}
@@ -0,0 +1,39 @@
+package sqs
+
+import (
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMD5OfMessageAttributes(t *testing.T) {
+	Convey("Given message attributes of every type", t, func() {
+		attributes := map[string]MessageAttributeValue{
+			"thumb": BinaryAttribute([]byte{1, 2}),
+			"color": StringAttribute("red"),
+			"size":  NumberAttribute("12"),
+		}
+
+		Convey("The checksum is computed over the sorted, length prefixed attributes", func() {
+			So(md5OfMessageAttributes(attributes), ShouldEqual, "265db244dd52f6c43648a2bf236559a1")
+		})
+	})
+	Convey("Given no message attributes", t, func() {
+		Convey("The checksum is empty", func() {
+			So(md5OfMessageAttributes(nil), ShouldEqual, "")
+		})
+	})
+}
+
+func TestSetMessageAttributeParams(t *testing.T) {
+	Convey("Given a binary attribute", t, func() {
+		params := url.Values{}
+		setMessageAttributeParams(params, "", map[string]MessageAttributeValue{"thumb": {DataType: "Binary.png", BinaryValue: []byte{1, 2}}})
+
+		Convey("It is sent base64 encoded with its custom type", func() {
+			So(params.Get("MessageAttribute.1.Value.DataType"), ShouldEqual, "Binary.png")
+			So(params.Get("MessageAttribute.1.Value.BinaryValue"), ShouldEqual, "AQI=")
+		})
+	})
+}
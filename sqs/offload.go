@@ -0,0 +1,176 @@
+package sqs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// maxMessageSize is the largest message SQS accepts, in bytes. It counts the body and the message attributes.
+const maxMessageSize = 262144
+
+// payloadSizeAttribute marks a message whose body is in S3. It is the name the AWS extended clients use, so they can read messages sent by gaws and the other way around.
+const payloadSizeAttribute = "ExtendedPayloadSize"
+
+// payloadPointerClass is the type name the AWS extended clients put in front of the pointer.
+const payloadPointerClass = "software.amazon.payloadoffloading.PayloadS3Pointer"
+
+// The markers put around the bucket and key of the payload in the receipt handle of a received message, so it can be deleted with the message.
+const (
+	bucketMarker = "-..s3BucketName..-"
+	keyMarker    = "-..s3Key..-"
+)
+
+var badPointerError = sqsError{Type: "Gaws", Code: "GawsBadPayloadPointer", Message: "The message says its body is in S3, but it does not point to it."}
+
+// PayloadStore stores message bodies. It is usually an S3 bucket, and s3.PayloadStore keeps them in one.
+type PayloadStore interface {
+	PutPayload(bucket string, key string, payload []byte) error
+	GetPayload(bucket string, key string) ([]byte, error)
+	DeletePayload(bucket string, key string) error
+}
+
+// PayloadOffload sends messages that are too large for SQS through S3. The body is put in the bucket and SQS gets a pointer to it.
+// Set it as the Offload of a Queue, and sending, receiving, and deleting messages take care of the rest.
+type PayloadOffload struct {
+	Bucket    string       // The bucket to put bodies in
+	Store     PayloadStore // Where the bodies are put
+	Threshold int          // Messages larger than this many bytes are offloaded. If it is 0, it is the largest message SQS accepts.
+}
+
+// payloadPointer is where a body is in S3. It is sent as the body of the message.
+type payloadPointer struct {
+	S3BucketName string `json:"s3BucketName"`
+	S3Key        string `json:"s3Key"`
+}
+
+// messageSize returns the size SQS counts for a message: its body and the names, types and values of its attributes.
+func messageSize(m OutgoingMessage) int {
+	size := len(m.Body)
+	for name, v := range m.Attributes {
+		size += len(name) + len(v.DataType) + len(v.StringValue) + len(v.BinaryValue)
+	}
+	return size
+}
+
+// newPayloadKey returns a random key for a body, formatted like a UUID.
+func newPayloadKey() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
+
+// offload puts the body of a message in S3 if the message is too large, and returns the message with a pointer to it instead.
+// In a dry run the body is not put in S3, but the message still gets a pointer, so it is what would be sent.
+func (o *PayloadOffload) offload(m OutgoingMessage, dryRun bool) (OutgoingMessage, error) {
+	threshold := o.Threshold
+	if threshold == 0 {
+		threshold = maxMessageSize
+	}
+	if messageSize(m) <= threshold {
+		return m, nil
+	}
+
+	key, err := newPayloadKey()
+	if err != nil {
+		return m, err
+	}
+
+	if !dryRun {
+		err = o.Store.PutPayload(o.Bucket, key, []byte(m.Body))
+		if err != nil {
+			return m, err
+		}
+	}
+
+	pointer, err := json.Marshal([]interface{}{payloadPointerClass, payloadPointer{S3BucketName: o.Bucket, S3Key: key}})
+	if err != nil {
+		return m, err
+	}
+
+	attributes := map[string]MessageAttributeValue{payloadSizeAttribute: NumberAttribute(strconv.Itoa(len(m.Body)))}
+	for name, v := range m.Attributes {
+		attributes[name] = v
+	}
+
+	m.Body = string(pointer)
+	m.Attributes = attributes
+	return m, nil
+}
+
+// retrieve replaces the body of a message with the body in S3 that it points to. The bucket and key are added to the receipt handle, so deleting the message deletes the body too.
+// Messages without a pointer are returned as they are.
+func (o *PayloadOffload) retrieve(m Message) (Message, error) {
+	if _, ok := m.Attributes[payloadSizeAttribute]; !ok {
+		return m, nil
+	}
+
+	pointer := payloadPointer{}
+	envelope := []json.RawMessage{}
+	err := json.Unmarshal([]byte(m.Body), &envelope)
+	if err != nil || len(envelope) != 2 {
+		return m, badPointerError
+	}
+	err = json.Unmarshal(envelope[1], &pointer)
+	if err != nil || pointer.S3BucketName == "" || pointer.S3Key == "" {
+		return m, badPointerError
+	}
+
+	payload, err := o.Store.GetPayload(pointer.S3BucketName, pointer.S3Key)
+	if err != nil {
+		return m, err
+	}
+
+	attributes := map[string]MessageAttributeValue{}
+	for name, v := range m.Attributes {
+		if name != payloadSizeAttribute {
+			attributes[name] = v
+		}
+	}
+	if len(attributes) == 0 {
+		attributes = nil
+	}
+
+	m.Body = string(payload)
+	m.Attributes = attributes
+	m.ReceiptHandle = bucketMarker + pointer.S3BucketName + bucketMarker + keyMarker + pointer.S3Key + keyMarker + m.ReceiptHandle
+	return m, nil
+}
+
+// splitReceiptHandle takes apart a receipt handle made by retrieve. It returns the bucket and key of the body, which are empty if the body was not in S3, and the receipt handle SQS gave out.
+func splitReceiptHandle(receiptHandle string) (string, string, string) {
+	if !strings.HasPrefix(receiptHandle, bucketMarker) {
+		return "", "", receiptHandle
+	}
+
+	parts := strings.SplitN(receiptHandle, bucketMarker, 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[2], keyMarker) {
+		return "", "", receiptHandle
+	}
+	keyParts := strings.SplitN(parts[2], keyMarker, 3)
+	if len(keyParts) != 3 {
+		return "", "", receiptHandle
+	}
+	return parts[1], keyParts[1], keyParts[2]
+}
+
+// sqsReceiptHandle returns the receipt handle SQS gave out, without a pointer to the body.
+func sqsReceiptHandle(receiptHandle string) string {
+	_, _, receiptHandle = splitReceiptHandle(receiptHandle)
+	return receiptHandle
+}
+
+// deletePayload deletes the body in S3 that a receipt handle points to, if there is one. In a dry run it is kept, since the message is.
+func (q *Queue) deletePayload(receiptHandle string) error {
+	bucket, key, _ := splitReceiptHandle(receiptHandle)
+	if bucket == "" || q.Offload == nil || q.dryRun() {
+		return nil
+	}
+	return q.Offload.Store.DeletePayload(bucket, key)
+}
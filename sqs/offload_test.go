@@ -0,0 +1,158 @@
+package sqs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testPayloadStore keeps payloads in a map.
+type testPayloadStore map[string][]byte
+
+func (s testPayloadStore) PutPayload(bucket string, key string, payload []byte) error {
+	s[bucket+"/"+key] = payload
+	return nil
+}
+
+func (s testPayloadStore) GetPayload(bucket string, key string) ([]byte, error) {
+	payload, ok := s[bucket+"/"+key]
+	if !ok {
+		return nil, fmt.Errorf("no such key: %v", key)
+	}
+	return payload, nil
+}
+
+func (s testPayloadStore) DeletePayload(bucket string, key string) error {
+	delete(s, bucket+"/"+key)
+	return nil
+}
+
+func TestPayloadOffload(t *testing.T) {
+	Convey("Given a message that is small enough for SQS", t, func() {
+		o := PayloadOffload{Bucket: "payloads", Store: testPayloadStore{}}
+		m, err := o.offload(OutgoingMessage{Body: "Hello World!"}, false)
+
+		Convey("It is not offloaded", func() {
+			So(err, ShouldBeNil)
+			So(m.Body, ShouldEqual, "Hello World!")
+			So(len(o.Store.(testPayloadStore)), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a message that is too large", t, func() {
+		store := testPayloadStore{}
+		o := PayloadOffload{Bucket: "payloads", Store: store, Threshold: 10}
+		m, err := o.offload(OutgoingMessage{Body: "Hello World!", Attributes: map[string]MessageAttributeValue{"trace": StringAttribute("abc")}}, false)
+
+		Convey("Its body is put in the bucket", func() {
+			So(err, ShouldBeNil)
+			So(len(store), ShouldEqual, 1)
+			for _, payload := range store {
+				So(string(payload), ShouldEqual, "Hello World!")
+			}
+		})
+		Convey("A pointer to it is sent instead", func() {
+			So(m.Body, ShouldStartWith, `["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"payloads","s3Key":"`)
+			So(m.Attributes[payloadSizeAttribute], ShouldResemble, NumberAttribute("12"))
+			So(m.Attributes["trace"], ShouldResemble, StringAttribute("abc"))
+		})
+		Convey("Retrieving it gets the body back", func() {
+			received, err := o.retrieve(Message{Body: m.Body, Attributes: m.Attributes, ReceiptHandle: "handle"})
+			So(err, ShouldBeNil)
+			So(received.Body, ShouldEqual, "Hello World!")
+			So(received.Attributes, ShouldResemble, map[string]MessageAttributeValue{"trace": StringAttribute("abc")})
+
+			bucket, key, receiptHandle := splitReceiptHandle(received.ReceiptHandle)
+			So(bucket, ShouldEqual, "payloads")
+			So(m.Body, ShouldContainSubstring, key)
+			So(receiptHandle, ShouldEqual, "handle")
+		})
+	})
+	Convey("Given a message with a bad pointer", t, func() {
+		o := PayloadOffload{Bucket: "payloads", Store: testPayloadStore{}}
+		_, err := o.retrieve(Message{Body: "Hello World!", Attributes: map[string]MessageAttributeValue{payloadSizeAttribute: NumberAttribute("12")}})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldResemble, badPointerError)
+		})
+	})
+	Convey("Given a receipt handle from SQS", t, func() {
+		bucket, key, receiptHandle := splitReceiptHandle("handle")
+
+		Convey("It has no pointer", func() {
+			So(bucket, ShouldEqual, "")
+			So(key, ShouldEqual, "")
+			So(receiptHandle, ShouldEqual, "handle")
+		})
+	})
+}
+
+func TestOffloadedMessages(t *testing.T) {
+	pointer := `["software.amazon.payloadoffloading.PayloadS3Pointer",{"s3BucketName":"payloads","s3Key":"abc"}]`
+	attributes := map[string]MessageAttributeValue{payloadSizeAttribute: NumberAttribute("12")}
+
+	Convey("Given a queue with a message whose body is in S3", t, func() {
+		store := testPayloadStore{"payloads/abc": []byte("Hello World!")}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `<ReceiveMessageResponse><ReceiveMessageResult><Message>
+  <ReceiptHandle>handle</ReceiptHandle>
+  <MD5OfBody>%v</MD5OfBody>
+  <Body>%v</Body>
+  <MD5OfMessageAttributes>%v</MD5OfMessageAttributes>
+  <MessageAttribute><Name>ExtendedPayloadSize</Name><Value><DataType>Number</DataType><StringValue>12</StringValue></Value></MessageAttribute>
+</Message></ReceiveMessageResult></ReceiveMessageResponse>`, md5Hex(pointer), pointer, md5OfMessageAttributes(attributes))
+		}, nil)
+		defer ts.Close()
+		q.Offload = &PayloadOffload{Bucket: "payloads", Store: store}
+
+		messages, err := q.ReceiveMessage(1, 0)
+
+		Convey("It returns the body from S3", func() {
+			So(err, ShouldBeNil)
+			So(messages[0].Body, ShouldEqual, "Hello World!")
+			So(messages[0].Attributes, ShouldBeNil)
+		})
+	})
+	Convey("Given a received message whose body is in S3", t, func() {
+		store := testPayloadStore{"payloads/abc": []byte("Hello World!")}
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+		q.Offload = &PayloadOffload{Bucket: "payloads", Store: store}
+
+		m, _ := q.Offload.retrieve(Message{Body: pointer, Attributes: attributes, ReceiptHandle: "handle"})
+		err := q.DeleteMessage(m.ReceiptHandle)
+
+		Convey("Deleting it sends SQS its own receipt handle", func() {
+			So(err, ShouldBeNil)
+			So(params.Get("ReceiptHandle"), ShouldEqual, "handle")
+		})
+		Convey("Deleting it deletes the body", func() {
+			So(len(store), ShouldEqual, 0)
+		})
+	})
+	Convey("Given a queue that offloads messages", t, func() {
+		store := testPayloadStore{}
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			// Answer with the checksums of what was sent
+			body := r.PostForm.Get("MessageBody")
+			fmt.Fprintf(w, "<SendMessageResponse><SendMessageResult><MD5OfMessageBody>%v</MD5OfMessageBody><MD5OfMessageAttributes>%v</MD5OfMessageAttributes><MessageId>1</MessageId></SendMessageResult></SendMessageResponse>",
+				md5Hex(body), md5OfMessageAttributes(map[string]MessageAttributeValue{payloadSizeAttribute: NumberAttribute(r.PostForm.Get("MessageAttribute.1.Value.StringValue"))}))
+		}, &params)
+		defer ts.Close()
+		q.Offload = &PayloadOffload{Bucket: "payloads", Store: store, Threshold: 5}
+
+		_, err := q.SendMessage("Hello World!", nil)
+
+		Convey("It sends a pointer to the body", func() {
+			So(err, ShouldBeNil)
+			So(len(store), ShouldEqual, 1)
+			So(strings.HasPrefix(params.Get("MessageBody"), `["software.amazon.payloadoffloading.PayloadS3Pointer"`), ShouldBeTrue)
+			So(params.Get("MessageAttribute.1.Name"), ShouldEqual, payloadSizeAttribute)
+		})
+	})
+}
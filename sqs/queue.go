@@ -0,0 +1,234 @@
+package sqs
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/controlgroup/gaws"
+)
+
+// LongPollSeconds is how long the high level ways of receiving messages wait for messages to arrive. 20 seconds is the longest SQS allows.
+var LongPollSeconds = 20
+
+// Queue is an SQS queue.
+type Queue struct {
+	URL     string          // The URL of the queue
+	Service *SQSService     // The service for this region
+	Offload *PayloadOffload // If it is set, bodies of messages that are too large for SQS go through S3
+}
+
+// QueueAPI is implemented by *Queue. The helpers that work on a queue, like Consumer, take a QueueAPI, so they can be given a fake in tests instead.
+type QueueAPI interface {
+	ChangeMessageVisibility(receiptHandle string, visibilityTimeout int) error
+	ChangeMessageVisibilityBatch(receiptHandles []string, visibilityTimeout int) ([]BatchResult, error)
+	Delete() error
+	DeleteMessage(receiptHandle string) error
+	DeleteMessageBatch(receiptHandles []string) ([]BatchResult, error)
+	GetAttributes() (QueueAttributes, error)
+	GetRedrivePolicy() (*RedrivePolicy, error)
+	IsFIFO() bool
+	ReceiveMessage(max int, waitTimeSeconds int) ([]Message, error)
+	Send(m OutgoingMessage) (string, error)
+	SendMessage(body string, attributes map[string]MessageAttributeValue) (string, error)
+	SendMessageBatch(messages []OutgoingMessage) ([]BatchResult, error)
+	SetAttributes(attributes QueueAttributes) error
+	SetRedrivePolicy(deadLetterQueueArn string, maxReceiveCount int) error
+}
+
+var _ QueueAPI = (*Queue)(nil)
+
+// queueClock returns the clock of the queue's client, or gaws.DefaultClock if the queue is a fake.
+func queueClock(queue QueueAPI) gaws.Clock {
+	if q, ok := queue.(*Queue); ok && q.Service != nil {
+		return q.Service.config.CurrentClock()
+	}
+	return gaws.DefaultClock
+}
+
+// IsFIFO reports whether the queue is a FIFO queue. The names of FIFO queues end in .fifo.
+func (q *Queue) IsFIFO() bool {
+	return strings.HasSuffix(q.URL, ".fifo")
+}
+
+// dryRun returns true if the queue's client is in dry-run mode, so its requests get empty responses.
+func (q *Queue) dryRun() bool {
+	return q.Service != nil && q.Service.config.DryRun != nil
+}
+
+type sendMessageResponse struct {
+	MD5OfMessageAttributes string `xml:"SendMessageResult>MD5OfMessageAttributes"`
+	MD5OfMessageBody       string `xml:"SendMessageResult>MD5OfMessageBody"`
+	MessageId              string `xml:"SendMessageResult>MessageId"`
+}
+
+// SendMessage sends a message with optional message attributes to the queue. It returns the ID of the message and an error if it fails.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_SendMessage.html for more details.
+func (q *Queue) SendMessage(body string, attributes map[string]MessageAttributeValue) (string, error) {
+	return q.Send(OutgoingMessage{Body: body, Attributes: attributes})
+}
+
+// Send sends a message to the queue. It returns the ID of the message and an error if it fails.
+// The checksums SQS returns for the body and attributes are checked. If the queue has an Offload, a body that is too large is put in S3 first.
+func (q *Queue) Send(m OutgoingMessage) (string, error) {
+	result := sendMessageResponse{}
+
+	if q.Offload != nil {
+		var err error
+		m, err = q.Offload.offload(m, q.dryRun())
+		if err != nil {
+			return "", err
+		}
+	}
+
+	params := query("SendMessage")
+	m.setParams(params, "")
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return "", err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return "", err
+	}
+
+	if q.dryRun() {
+		// The message was not sent, so there are no checksums to check.
+		return "", nil
+	}
+	err = m.checksum(result.MD5OfMessageBody, result.MD5OfMessageAttributes)
+	if err != nil {
+		return "", err
+	}
+	return result.MessageId, nil
+}
+
+type receiveMessageResponse struct {
+	Messages []receivedMessage `xml:"ReceiveMessageResult>Message"`
+}
+
+// ReceiveMessage receives up to max messages from the queue, with all of their message and system attributes. max can be up to 10. If it is 0, SQS returns one message.
+// waitTimeSeconds is how long to wait for messages to arrive, up to 20 seconds. If it is 0, the queue's ReceiveMessageWaitTimeSeconds is used.
+// Without a wait, it may return no messages even if there are some in the queue. Waiting is called long polling, and it makes empty receives much less common.
+// If the queue has an Offload, bodies that are in S3 are fetched.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ReceiveMessage.html for more details.
+func (q *Queue) ReceiveMessage(max int, waitTimeSeconds int) ([]Message, error) {
+	result := receiveMessageResponse{}
+
+	params := query("ReceiveMessage")
+	params.Set("AttributeName.1", "All")
+	params.Set("MessageAttributeName.1", "All")
+	if max > 0 {
+		params.Set("MaxNumberOfMessages", strconv.Itoa(max))
+	}
+	if waitTimeSeconds > 0 {
+		params.Set("WaitTimeSeconds", strconv.Itoa(waitTimeSeconds))
+	}
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return []Message{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return []Message{}, err
+	}
+
+	messages := make([]Message, len(result.Messages))
+	for i, r := range result.Messages {
+		messages[i], err = r.message()
+		if err != nil {
+			return []Message{}, err
+		}
+		if q.Offload != nil {
+			messages[i], err = q.Offload.retrieve(messages[i])
+			if err != nil {
+				return []Message{}, err
+			}
+		}
+		messages[i].queue = q
+	}
+	return messages, nil
+}
+
+// DeleteMessage deletes a message from the queue. It takes the ReceiptHandle of the message. If its body is in S3, that is deleted too.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_DeleteMessage.html for more details.
+func (q *Queue) DeleteMessage(receiptHandle string) error {
+	params := query("DeleteMessage")
+	params.Set("ReceiptHandle", sqsReceiptHandle(receiptHandle))
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+	if err != nil {
+		return err
+	}
+
+	return q.deletePayload(receiptHandle)
+}
+
+// Delete deletes the queue and any messages in it. It is calling the DeleteQueue API call.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_DeleteQueue.html for more details.
+func (q *Queue) Delete() error {
+	params := query("DeleteQueue")
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+type getQueueAttributesResponse struct {
+	Attributes []attribute `xml:"GetQueueAttributesResult>Attribute"`
+}
+
+// GetAttributes gets all of the attributes of the queue. It is calling the GetQueueAttributes API call.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_GetQueueAttributes.html for more details.
+func (q *Queue) GetAttributes() (QueueAttributes, error) {
+	result := getQueueAttributesResponse{}
+
+	params := query("GetQueueAttributes")
+	params.Set("AttributeName.1", "All")
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return QueueAttributes{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return QueueAttributes{}, err
+	}
+	return queueAttributesFromMap(attributesToMap(result.Attributes)), nil
+}
+
+// SetAttributes changes the attributes of the queue. Fields that are zero are left alone, and FifoQueue can only be set when a queue is created. It is calling the SetQueueAttributes API call.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_SetQueueAttributes.html for more details.
+func (q *Queue) SetAttributes(attributes QueueAttributes) error {
+	params := query("SetQueueAttributes")
+	attributeMap := attributes.attributeMap()
+	delete(attributeMap, "FifoQueue")
+	setAttributeParams(params, attributeMap)
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
@@ -0,0 +1,474 @@
+package sqs
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testSendMessageResponse = []byte(`<SendMessageResponse>
+  <SendMessageResult>
+    <MD5OfMessageBody>ed076287532e86365e841e92bfc50d8c</MD5OfMessageBody>
+    <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+  </SendMessageResult>
+  <ResponseMetadata>
+    <RequestId>27daac76-34dd-47df-bd01-1f6e873584a0</RequestId>
+  </ResponseMetadata>
+</SendMessageResponse>`)
+
+// testQueue returns a queue on a server that runs handler and records the parameters of the last request.
+func testQueue(handler http.HandlerFunc, params *url.Values) (*httptest.Server, Queue) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = r.PostForm
+		}
+		handler(w, r)
+	}))
+	service := SQSService{Endpoint: ts.URL}
+	return ts, Queue{URL: ts.URL + "/123456789012/foo", Service: &service}
+}
+
+func TestSendMessage(t *testing.T) {
+	Convey("Given a queue that accepts messages", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testSendMessageResponse) }, &params)
+		defer ts.Close()
+
+		id, err := q.SendMessage("Hello World!", nil)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the message ID", func() {
+			So(id, ShouldEqual, "5fea7756-0ea4-451a-a703-a558b933e274")
+		})
+		Convey("It sends the body with the SendMessage action", func() {
+			So(params.Get("Action"), ShouldEqual, "SendMessage")
+			So(params.Get("MessageBody"), ShouldEqual, "Hello World!")
+		})
+	})
+	Convey("Given a queue that returns the wrong checksum", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testSendMessageResponse) }, nil)
+		defer ts.Close()
+
+		_, err := q.SendMessage("Something else", nil)
+
+		Convey("It returns a checksum error", func() {
+			So(err, ShouldResemble, checksumError)
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := q.SendMessage("Hello World!", nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a queue that returns bad data", t, func() {
+		ts, q := testQueue(testBadXml, nil)
+		defer ts.Close()
+
+		_, err := q.SendMessage("Hello World!", nil)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+var testReceiveMessageResponse = []byte(`<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>5fea7756-0ea4-451a-a703-a558b933e274</MessageId>
+      <ReceiptHandle>MbZj6wDWli+JvwwJaBV+3dcjk2YW2vA3+STFFljTM8tJJg6HRG6PYSasuWXPJB+CwLj1FjgXUv1uSj1gUPAWV66FU/WeR4mq2OKpEGYWbnLmpRCJVAyeMjeU5ZBdtcQ+QEauMZc8ZRv37sIW2iJKq3M9MFx1YvV11A2x/KSbkJ0=</ReceiptHandle>
+      <MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody>
+      <Body>Hello World!</Body>
+    </Message>
+  </ReceiveMessageResult>
+  <ResponseMetadata>
+    <RequestId>b6633655-283d-45b4-aee4-4e84e0ae6afa</RequestId>
+  </ResponseMetadata>
+</ReceiveMessageResponse>`)
+
+// testDryRunQueue returns a queue of a client in dry-run mode, which logs to the buffer, on a server that counts the requests it gets.
+func testDryRunQueue() (*httptest.Server, Queue, *bytes.Buffer, *int) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	b := &bytes.Buffer{}
+	s := New(gaws.WithEndpoint(ts.URL), gaws.WithDryRun(gaws.LogDryRunner{Logger: log.New(b, "", 0)}))
+	return ts, Queue{URL: ts.URL + "/123456789012/foo", Service: s}, b, &requests
+}
+
+func TestSendMessageDryRun(t *testing.T) {
+	Convey("Given a queue of a client in dry-run mode", t, func() {
+		ts, q, b, requests := testDryRunQueue()
+		defer ts.Close()
+
+		_, err := q.Send(OutgoingMessage{Body: "Hello World!", Attributes: map[string]MessageAttributeValue{"Color": {DataType: "String", StringValue: "red"}}})
+
+		Convey("The message would be sent, and is not", func() {
+			So(err, ShouldBeNil)
+			So(b.String(), ShouldContainSubstring, "Action=SendMessage")
+			So(*requests, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestSendLargeMessageDryRun(t *testing.T) {
+	Convey("Given a queue of a client in dry-run mode that offloads large messages", t, func() {
+		ts, q, b, requests := testDryRunQueue()
+		defer ts.Close()
+		store := testPayloadStore{}
+		q.Offload = &PayloadOffload{Bucket: "payloads", Store: store, Threshold: 10}
+
+		_, err := q.Send(OutgoingMessage{Body: "Hello World!"})
+
+		Convey("A pointer would be sent, and the body is not put in S3", func() {
+			So(err, ShouldBeNil)
+			So(b.String(), ShouldContainSubstring, "PayloadS3Pointer")
+			So(store, ShouldBeEmpty)
+			So(*requests, ShouldEqual, 0)
+		})
+	})
+}
+
+func TestReceiveMessage(t *testing.T) {
+	Convey("Given a queue with a message in it", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testReceiveMessageResponse) }, &params)
+		defer ts.Close()
+
+		messages, err := q.ReceiveMessage(10, 20)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the message", func() {
+			So(len(messages), ShouldEqual, 1)
+			So(messages[0].Body, ShouldEqual, "Hello World!")
+			So(messages[0].MessageId, ShouldEqual, "5fea7756-0ea4-451a-a703-a558b933e274")
+		})
+		Convey("It asks for the maximum number of messages", func() {
+			So(params.Get("MaxNumberOfMessages"), ShouldEqual, "10")
+		})
+		Convey("It asks SQS to wait for messages", func() {
+			So(params.Get("WaitTimeSeconds"), ShouldEqual, "20")
+		})
+	})
+	Convey("Given an empty queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<ReceiveMessageResponse><ReceiveMessageResult/></ReceiveMessageResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		messages, err := q.ReceiveMessage(0, 0)
+
+		Convey("It returns no messages and no error", func() {
+			So(err, ShouldBeNil)
+			So(messages, ShouldResemble, []Message{})
+		})
+		Convey("It leaves the wait time to the queue", func() {
+			_, ok := params["WaitTimeSeconds"]
+			So(ok, ShouldBeFalse)
+		})
+	})
+	Convey("Given a queue that returns a message with a bad checksum", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<ReceiveMessageResponse><ReceiveMessageResult><Message><MD5OfBody>abc</MD5OfBody><Body>Hello</Body></Message></ReceiveMessageResult></ReceiveMessageResponse>"))
+		}, nil)
+		defer ts.Close()
+
+		_, err := q.ReceiveMessage(0, 0)
+
+		Convey("It returns a checksum error", func() {
+			So(err, ShouldResemble, checksumError)
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := q.ReceiveMessage(0, 0)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSendMessageWithAttributes(t *testing.T) {
+	Convey("Given a queue that accepts messages with attributes", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<SendMessageResponse><SendMessageResult><MD5OfMessageBody>ed076287532e86365e841e92bfc50d8c</MD5OfMessageBody><MD5OfMessageAttributes>7e45be12b1fb4cf84869065ab4ab94a7</MD5OfMessageAttributes><MessageId>1</MessageId></SendMessageResult></SendMessageResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		_, err := q.SendMessage("Hello World!", map[string]MessageAttributeValue{"trace": StringAttribute("abc")})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends the attributes", func() {
+			So(params.Get("MessageAttribute.1.Name"), ShouldEqual, "trace")
+			So(params.Get("MessageAttribute.1.Value.DataType"), ShouldEqual, "String")
+			So(params.Get("MessageAttribute.1.Value.StringValue"), ShouldEqual, "abc")
+		})
+	})
+	Convey("Given a queue that returns the wrong attribute checksum", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<SendMessageResponse><SendMessageResult><MD5OfMessageBody>ed076287532e86365e841e92bfc50d8c</MD5OfMessageBody><MD5OfMessageAttributes>00000000000000000000000000000000</MD5OfMessageAttributes><MessageId>1</MessageId></SendMessageResult></SendMessageResponse>"))
+		}, nil)
+		defer ts.Close()
+
+		_, err := q.SendMessage("Hello World!", map[string]MessageAttributeValue{"trace": StringAttribute("abc")})
+
+		Convey("It returns a checksum error", func() {
+			So(err, ShouldResemble, checksumError)
+		})
+	})
+}
+
+func TestReceiveMessageWithAttributes(t *testing.T) {
+	Convey("Given a queue with a message that has attributes", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<ReceiveMessageResponse><ReceiveMessageResult><Message>
+  <MessageId>1</MessageId>
+  <ReceiptHandle>handle</ReceiptHandle>
+  <MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody>
+  <Body>Hello World!</Body>
+  <MD5OfMessageAttributes>265db244dd52f6c43648a2bf236559a1</MD5OfMessageAttributes>
+  <MessageAttribute><Name>color</Name><Value><DataType>String</DataType><StringValue>red</StringValue></Value></MessageAttribute>
+  <MessageAttribute><Name>size</Name><Value><DataType>Number</DataType><StringValue>12</StringValue></Value></MessageAttribute>
+  <MessageAttribute><Name>thumb</Name><Value><DataType>Binary</DataType><BinaryValue>AQI=</BinaryValue></Value></MessageAttribute>
+</Message></ReceiveMessageResult></ReceiveMessageResponse>`))
+		}, &params)
+		defer ts.Close()
+
+		messages, err := q.ReceiveMessage(1, 0)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It asks for all of the message attributes", func() {
+			So(params.Get("MessageAttributeName.1"), ShouldEqual, "All")
+		})
+		Convey("It returns the typed attributes", func() {
+			So(messages[0].Attributes, ShouldResemble, map[string]MessageAttributeValue{
+				"color": StringAttribute("red"),
+				"size":  NumberAttribute("12"),
+				"thumb": BinaryAttribute([]byte{1, 2}),
+			})
+		})
+	})
+	Convey("Given a message whose attributes do not match their checksum", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<ReceiveMessageResponse><ReceiveMessageResult><Message><MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody><Body>Hello World!</Body><MD5OfMessageAttributes>265db244dd52f6c43648a2bf236559a1</MD5OfMessageAttributes><MessageAttribute><Name>color</Name><Value><DataType>String</DataType><StringValue>blue</StringValue></Value></MessageAttribute></Message></ReceiveMessageResult></ReceiveMessageResponse>`))
+		}, nil)
+		defer ts.Close()
+
+		_, err := q.ReceiveMessage(1, 0)
+
+		Convey("It returns a checksum error", func() {
+			So(err, ShouldResemble, checksumError)
+		})
+	})
+}
+
+func TestDeleteMessage(t *testing.T) {
+	Convey("Given a queue that deletes messages", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.DeleteMessage("handle")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends the receipt handle", func() {
+			So(params.Get("Action"), ShouldEqual, "DeleteMessage")
+			So(params.Get("ReceiptHandle"), ShouldEqual, "handle")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		Convey("It returns an error", func() {
+			So(q.DeleteMessage("handle"), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeleteQueue(t *testing.T) {
+	Convey("Given a queue that can be deleted", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.Delete()
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+			So(params.Get("Action"), ShouldEqual, "DeleteQueue")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		Convey("It returns an error", func() {
+			So(q.Delete(), ShouldNotBeNil)
+		})
+	})
+}
+
+var testGetQueueAttributesResponse = []byte(`<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute><Name>ReceiveMessageWaitTimeSeconds</Name><Value>2</Value></Attribute>
+    <Attribute><Name>VisibilityTimeout</Name><Value>30</Value></Attribute>
+    <Attribute><Name>ApproximateNumberOfMessages</Name><Value>0</Value></Attribute>
+    <Attribute><Name>MessageRetentionPeriod</Name><Value>345600</Value></Attribute>
+    <Attribute><Name>QueueArn</Name><Value>arn:aws:sqs:us-east-1:123456789012:foo</Value></Attribute>
+  </GetQueueAttributesResult>
+</GetQueueAttributesResponse>`)
+
+func TestGetQueueAttributes(t *testing.T) {
+	Convey("Given a queue with attributes", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testGetQueueAttributesResponse) }, &params)
+		defer ts.Close()
+
+		attributes, err := q.GetAttributes()
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the attributes", func() {
+			So(attributes.VisibilityTimeout, ShouldEqual, 30)
+			So(attributes.MessageRetentionPeriod, ShouldEqual, 345600)
+			So(attributes.QueueArn, ShouldEqual, "arn:aws:sqs:us-east-1:123456789012:foo")
+		})
+		Convey("It asks for all of the attributes", func() {
+			So(params.Get("AttributeName.1"), ShouldEqual, "All")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := q.GetAttributes()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestSetQueueAttributes(t *testing.T) {
+	Convey("Given a queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.SetAttributes(QueueAttributes{MessageRetentionPeriod: 1209600})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends only the attributes that are set", func() {
+			So(params.Get("Attribute.1.Name"), ShouldEqual, "MessageRetentionPeriod")
+			So(params.Get("Attribute.1.Value"), ShouldEqual, "1209600")
+			So(params.Get("Attribute.2.Name"), ShouldEqual, "")
+		})
+	})
+	Convey("Given a FIFO queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.SetAttributes(QueueAttributes{FifoQueue: true, ContentBasedDeduplication: true})
+
+		Convey("It does not try to change FifoQueue", func() {
+			So(err, ShouldBeNil)
+			So(params.Get("Attribute.1.Name"), ShouldEqual, "ContentBasedDeduplication")
+			So(params.Get("Attribute.2.Name"), ShouldEqual, "")
+		})
+	})
+}
+
+func TestFIFOQueue(t *testing.T) {
+	Convey("Given queues with and without the .fifo suffix", t, func() {
+		Convey("Only the one with the suffix is a FIFO queue", func() {
+			So((&Queue{URL: "https://sqs.us-east-1.amazonaws.com/123456789012/foo.fifo"}).IsFIFO(), ShouldBeTrue)
+			So((&Queue{URL: "https://sqs.us-east-1.amazonaws.com/123456789012/foo"}).IsFIFO(), ShouldBeFalse)
+		})
+	})
+	Convey("Given a message for a FIFO queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testSendMessageResponse) }, &params)
+		defer ts.Close()
+
+		_, err := q.Send(OutgoingMessage{Body: "Hello World!", MessageGroupId: "orders", MessageDeduplicationId: "order-1"})
+
+		Convey("It sends the group and deduplication IDs", func() {
+			So(err, ShouldBeNil)
+			So(params.Get("MessageGroupId"), ShouldEqual, "orders")
+			So(params.Get("MessageDeduplicationId"), ShouldEqual, "order-1")
+		})
+	})
+	Convey("Given a message without a deduplication ID", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testSendMessageResponse) }, &params)
+		defer ts.Close()
+
+		q.Send(OutgoingMessage{Body: "Hello World!", MessageGroupId: "orders"})
+
+		Convey("It leaves deduplication to the queue", func() {
+			_, ok := params["MessageDeduplicationId"]
+			So(ok, ShouldBeFalse)
+		})
+	})
+	Convey("Given a FIFO queue with a message in it", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<ReceiveMessageResponse><ReceiveMessageResult><Message>
+  <MessageId>1</MessageId>
+  <ReceiptHandle>handle</ReceiptHandle>
+  <MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody>
+  <Body>Hello World!</Body>
+  <Attribute><Name>MessageGroupId</Name><Value>orders</Value></Attribute>
+  <Attribute><Name>SequenceNumber</Name><Value>18849496460467696128</Value></Attribute>
+  <Attribute><Name>ApproximateReceiveCount</Name><Value>1</Value></Attribute>
+</Message></ReceiveMessageResult></ReceiveMessageResponse>`))
+		}, &params)
+		defer ts.Close()
+
+		messages, err := q.ReceiveMessage(1, 0)
+
+		Convey("It asks for the system attributes", func() {
+			So(err, ShouldBeNil)
+			So(params.Get("AttributeName.1"), ShouldEqual, "All")
+		})
+		Convey("It returns the group and sequence number", func() {
+			So(messages[0].MessageGroupId, ShouldEqual, "orders")
+			So(messages[0].SequenceNumber, ShouldEqual, "18849496460467696128")
+			So(messages[0].SystemAttributes["ApproximateReceiveCount"], ShouldEqual, "1")
+		})
+	})
+}
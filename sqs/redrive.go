@@ -0,0 +1,56 @@
+package sqs
+
+import (
+	"strings"
+)
+
+// RedrivePolicy sends messages that have been received too many times to a dead-letter queue.
+type RedrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"` // The ARN of the dead-letter queue
+	MaxReceiveCount     int    `json:"maxReceiveCount"`     // How many times a message is received before it is moved
+}
+
+// deadLetterRetention is how long dead-letter queues made by CreateQueueWithDeadLetterQueue keep messages. It is the longest SQS allows, 14 days.
+const deadLetterRetention = 1209600
+
+// SetRedrivePolicy makes the queue send messages that have been received maxReceiveCount times to the dead-letter queue with the given ARN.
+func (q *Queue) SetRedrivePolicy(deadLetterQueueArn string, maxReceiveCount int) error {
+	return q.SetAttributes(QueueAttributes{RedrivePolicy: &RedrivePolicy{DeadLetterTargetArn: deadLetterQueueArn, MaxReceiveCount: maxReceiveCount}})
+}
+
+// GetRedrivePolicy gets the queue's redrive policy. It returns nil if the queue does not have a dead-letter queue.
+func (q *Queue) GetRedrivePolicy() (*RedrivePolicy, error) {
+	attributes, err := q.GetAttributes()
+	if err != nil {
+		return nil, err
+	}
+	return attributes.RedrivePolicy, nil
+}
+
+// deadLetterQueueName returns the name of the dead-letter queue for a queue. FIFO queues need FIFO dead-letter queues, so the .fifo suffix is kept at the end.
+func deadLetterQueueName(name string) string {
+	if strings.HasSuffix(name, ".fifo") {
+		return strings.TrimSuffix(name, ".fifo") + "-dlq.fifo"
+	}
+	return name + "-dlq"
+}
+
+// CreateQueueWithDeadLetterQueue creates a queue and a dead-letter queue for it, named with a -dlq suffix. Messages received maxReceiveCount times are moved to the dead-letter queue, which keeps them for 14 days.
+// The dead-letter queue of a FIFO queue is a FIFO queue too.
+// It returns the queue and the dead-letter queue.
+func (s *SQSService) CreateQueueWithDeadLetterQueue(name string, attributes QueueAttributes, maxReceiveCount int) (Queue, Queue, error) {
+	deadLetterQueue, err := s.CreateQueue(deadLetterQueueName(name), QueueAttributes{MessageRetentionPeriod: deadLetterRetention, FifoQueue: attributes.FifoQueue})
+	if err != nil {
+		return Queue{}, Queue{}, err
+	}
+
+	deadLetterAttributes, err := deadLetterQueue.GetAttributes()
+	if err != nil {
+		return Queue{}, deadLetterQueue, err
+	}
+
+	attributes.RedrivePolicy = &RedrivePolicy{DeadLetterTargetArn: deadLetterAttributes.QueueArn, MaxReceiveCount: maxReceiveCount}
+
+	queue, err := s.CreateQueue(name, attributes)
+	return queue, deadLetterQueue, err
+}
@@ -0,0 +1,123 @@
+package sqs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSetRedrivePolicy(t *testing.T) {
+	Convey("Given a queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.SetRedrivePolicy("arn:aws:sqs:us-east-1:123456789012:foo-dlq", 5)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sets the RedrivePolicy attribute", func() {
+			So(params.Get("Attribute.1.Name"), ShouldEqual, "RedrivePolicy")
+			So(params.Get("Attribute.1.Value"), ShouldEqual, `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:foo-dlq","maxReceiveCount":5}`)
+		})
+	})
+}
+
+func TestGetRedrivePolicy(t *testing.T) {
+	Convey("Given a queue with a dead-letter queue", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`<GetQueueAttributesResponse><GetQueueAttributesResult><Attribute><Name>RedrivePolicy</Name><Value>{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:foo-dlq","maxReceiveCount":5}</Value></Attribute></GetQueueAttributesResult></GetQueueAttributesResponse>`))
+		}, nil)
+		defer ts.Close()
+
+		policy, err := q.GetRedrivePolicy()
+
+		Convey("It returns the policy", func() {
+			So(err, ShouldBeNil)
+			So(policy, ShouldResemble, &RedrivePolicy{DeadLetterTargetArn: "arn:aws:sqs:us-east-1:123456789012:foo-dlq", MaxReceiveCount: 5})
+		})
+	})
+	Convey("Given a queue without a dead-letter queue", t, func() {
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) { w.Write(testGetQueueAttributesResponse) }, nil)
+		defer ts.Close()
+
+		policy, err := q.GetRedrivePolicy()
+
+		Convey("It returns nil", func() {
+			So(err, ShouldBeNil)
+			So(policy, ShouldBeNil)
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := q.GetRedrivePolicy()
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestCreateQueueWithDeadLetterQueue(t *testing.T) {
+	Convey("Given a service that creates queues", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			switch r.PostForm.Get("Action") {
+			case "CreateQueue":
+				fmt.Fprintf(w, "<CreateQueueResponse><CreateQueueResult><QueueUrl>%v/123456789012/%v</QueueUrl></CreateQueueResult></CreateQueueResponse>", "http://"+r.Host, r.PostForm.Get("QueueName"))
+			case "GetQueueAttributes":
+				w.Write([]byte("<GetQueueAttributesResponse><GetQueueAttributesResult><Attribute><Name>QueueArn</Name><Value>arn:aws:sqs:us-east-1:123456789012:foo-dlq</Value></Attribute></GetQueueAttributesResult></GetQueueAttributesResponse>"))
+			}
+		}, &params)
+		defer ts.Close()
+
+		queue, deadLetterQueue, err := s.CreateQueueWithDeadLetterQueue("foo", QueueAttributes{VisibilityTimeout: 60}, 3)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns both queues", func() {
+			So(queue.URL, ShouldEndWith, "/foo")
+			So(deadLetterQueue.URL, ShouldEndWith, "/foo-dlq")
+		})
+		Convey("It creates the dead-letter queue first, with the longest retention", func() {
+			So(params[0].Get("QueueName"), ShouldEqual, "foo-dlq")
+			So(params[0].Get("Attribute.1.Value"), ShouldEqual, "1209600")
+		})
+		Convey("It creates the queue with a redrive policy to the dead-letter queue", func() {
+			attributes := map[string]string{}
+			for n := 1; n <= 2; n++ {
+				attributes[params[2].Get(fmt.Sprintf("Attribute.%v.Name", n))] = params[2].Get(fmt.Sprintf("Attribute.%v.Value", n))
+			}
+			So(attributes["RedrivePolicy"], ShouldEqual, `{"deadLetterTargetArn":"arn:aws:sqs:us-east-1:123456789012:foo-dlq","maxReceiveCount":3}`)
+			So(attributes["VisibilityTimeout"], ShouldEqual, "60")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, _, err := s.CreateQueueWithDeadLetterQueue("foo", QueueAttributes{}, 3)
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestDeadLetterQueueName(t *testing.T) {
+	Convey("The dead-letter queue name", t, func() {
+		Convey("Has a -dlq suffix", func() {
+			So(deadLetterQueueName("foo"), ShouldEqual, "foo-dlq")
+		})
+		Convey("Keeps the .fifo suffix at the end", func() {
+			So(deadLetterQueueName("foo.fifo"), ShouldEqual, "foo-dlq.fifo")
+		})
+	})
+}
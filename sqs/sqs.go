@@ -0,0 +1,192 @@
+// Package sqs provides a way to interact with the AWS Simple Queue Service.
+package sqs
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"github.com/controlgroup/gaws"
+)
+
+//go:generate go run ../internal/errgen -type sqsError -field Code -service SQS ErrNonExistentQueue=AWS.SimpleQueueService.NonExistentQueue QueueAlreadyExists ErrQueueDeletedRecently=AWS.SimpleQueueService.QueueDeletedRecently ErrPurgeQueueInProgress=AWS.SimpleQueueService.PurgeQueueInProgress ReceiptHandleIsInvalid InvalidMessageContents OverLimit ErrTooManyEntriesInBatchRequest=AWS.SimpleQueueService.TooManyEntriesInBatchRequest ErrBatchEntryIdsNotDistinct=AWS.SimpleQueueService.BatchEntryIdsNotDistinct
+
+// sqsError is the error document returned from the SQS service.
+type sqsError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the sqsError into an error message.
+func (e sqsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func sqsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := sqsError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" || error.Code == "RequestThrottled" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// SQSService is the SQS service at AWS. The endpoint for a region is gaws.Endpoint("sqs", region).
+type SQSService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// SQSAPI is implemented by *SQSService. Code that takes an SQSAPI can be given a fake in tests instead.
+type SQSAPI interface {
+	CreateQueue(name string, attributes QueueAttributes) (Queue, error)
+	CreateQueueWithDeadLetterQueue(name string, attributes QueueAttributes, maxReceiveCount int) (Queue, Queue, error)
+	GetQueue(name string) (Queue, error)
+	ListQueues(prefix string) ([]Queue, error)
+}
+
+var _ SQSAPI = (*SQSService)(nil)
+
+// New returns an SQSService with the options applied. Its endpoint is gaws.Endpoint("sqs", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *SQSService {
+	config := gaws.NewConfig(options...)
+	return &SQSService{Endpoint: config.ServiceEndpoint("sqs"), config: config}
+}
+
+// request returns a request to an SQS URL, which is either the service's endpoint or a queue's URL.
+func (s *SQSService) request(url string) gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: sqsRetryPredicate,
+		Method:         "POST",
+		URL:            url,
+		Signer:         gaws.Sign4("sqs", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an SQS API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2012-11-05"},
+	}
+}
+
+type createQueueResponse struct {
+	QueueUrl string `xml:"CreateQueueResult>QueueUrl"`
+}
+
+// CreateQueue creates a new SQS queue. It returns the Queue and an error if it fails.
+// If a queue with the same name and attributes already exists, it returns that queue.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_CreateQueue.html for more details.
+func (s *SQSService) CreateQueue(name string, attributes QueueAttributes) (Queue, error) {
+	result := createQueueResponse{}
+
+	params := query("CreateQueue")
+	params.Set("QueueName", name)
+	setAttributeParams(params, attributes.attributeMap())
+
+	req := s.request(s.Endpoint)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return Queue{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return Queue{}, err
+	}
+	return Queue{URL: result.QueueUrl, Service: s}, nil
+}
+
+type listQueuesResponse struct {
+	NextToken string   `xml:"ListQueuesResult>NextToken"`
+	QueueUrls []string `xml:"ListQueuesResult>QueueUrl"`
+}
+
+// ListQueues lists the queues whose names start with prefix. If prefix is empty, it lists all of the queues.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListQueues.html for more details.
+func (s *SQSService) ListQueues(prefix string) ([]Queue, error) {
+	queues := []Queue{}
+
+	params := query("ListQueues")
+	if prefix != "" {
+		params.Set("QueueNamePrefix", prefix)
+	}
+
+	for {
+		result := listQueuesResponse{}
+
+		req := s.request(s.Endpoint)
+		req.Body = []byte(params.Encode())
+
+		resp, err := req.Do()
+		if err != nil {
+			return []Queue{}, err
+		}
+
+		err = xml.Unmarshal(resp, &result)
+		if err != nil {
+			return []Queue{}, err
+		}
+
+		for _, u := range result.QueueUrls {
+			queues = append(queues, Queue{URL: u, Service: s})
+		}
+
+		if result.NextToken == "" {
+			return queues, nil
+		}
+		params.Set("NextToken", result.NextToken)
+	}
+}
+
+type getQueueUrlResponse struct {
+	QueueUrl string `xml:"GetQueueUrlResult>QueueUrl"`
+}
+
+// GetQueue finds a queue by name. It is calling the GetQueueUrl API call.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_GetQueueUrl.html for more details.
+func (s *SQSService) GetQueue(name string) (Queue, error) {
+	result := getQueueUrlResponse{}
+
+	params := query("GetQueueUrl")
+	params.Set("QueueName", name)
+
+	req := s.request(s.Endpoint)
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return Queue{}, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	if err != nil {
+		return Queue{}, err
+	}
+	return Queue{URL: result.QueueUrl, Service: s}, nil
+}
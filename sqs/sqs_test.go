@@ -0,0 +1,218 @@
+package sqs
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testHTTP200(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<SendMessageResponse><SendMessageResult>"))
+}
+
+var testNotFoundError = []byte(`<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>AWS.SimpleQueueService.NonExistentQueue</Code>
+    <Message>The specified queue does not exist for this wsdl version.</Message>
+    <Detail/>
+  </Error>
+  <RequestId>b5293cb5-d306-4a17-9048-b263635abe42</RequestId>
+</ErrorResponse>`)
+
+func testHTTP400(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(400)
+	w.Write(testNotFoundError)
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := sqsRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := sqsRetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalError</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"RequestThrottled\" code", t, func() {
+		result, _ := sqsRetryPredicate(403, []byte("<ErrorResponse><Error><Code>RequestThrottled</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says the queue does not exist", t, func() {
+		result, err := sqsRetryPredicate(400, testNotFoundError)
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("The error has the code and message", func() {
+			So(err.Error(), ShouldEqual, "AWS.SimpleQueueService.NonExistentQueue: The specified queue does not exist for this wsdl version.")
+		})
+	})
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, SQSService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, SQSService{Endpoint: ts.URL}
+}
+
+func TestCreateQueue(t *testing.T) {
+	Convey("Given a service that creates queues", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<CreateQueueResponse><CreateQueueResult><QueueUrl>https://queue.amazonaws.com/123456789012/foo</QueueUrl></CreateQueueResult></CreateQueueResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		q, err := s.CreateQueue("foo", QueueAttributes{VisibilityTimeout: 60})
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the queue", func() {
+			So(q.URL, ShouldEqual, "https://queue.amazonaws.com/123456789012/foo")
+			So(q.Service, ShouldEqual, &s)
+		})
+		Convey("It sends the name and attributes", func() {
+			So(params[0].Get("QueueName"), ShouldEqual, "foo")
+			So(params[0].Get("Attribute.1.Name"), ShouldEqual, "VisibilityTimeout")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.CreateQueue("foo", QueueAttributes{})
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestListQueues(t *testing.T) {
+	Convey("Given a service with queues over two pages", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			if r.PostForm.Get("NextToken") == "" {
+				w.Write([]byte("<ListQueuesResponse><ListQueuesResult><QueueUrl>https://queue.amazonaws.com/123456789012/foo</QueueUrl><QueueUrl>https://queue.amazonaws.com/123456789012/foobar</QueueUrl><NextToken>next</NextToken></ListQueuesResult></ListQueuesResponse>"))
+				return
+			}
+			w.Write([]byte("<ListQueuesResponse><ListQueuesResult><QueueUrl>https://queue.amazonaws.com/123456789012/foobaz</QueueUrl></ListQueuesResult></ListQueuesResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		queues, err := s.ListQueues("foo")
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It returns the queues from every page", func() {
+			So(len(queues), ShouldEqual, 3)
+			So(queues[2].URL, ShouldEqual, "https://queue.amazonaws.com/123456789012/foobaz")
+		})
+		Convey("It sends the prefix and the token for the next page", func() {
+			So(params[0].Get("QueueNamePrefix"), ShouldEqual, "foo")
+			So(params[1].Get("NextToken"), ShouldEqual, "next")
+		})
+	})
+	Convey("Given a service that returns errors", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		queues, err := s.ListQueues("")
+
+		Convey("It returns an error and no queues", func() {
+			So(err, ShouldNotBeNil)
+			So(queues, ShouldResemble, []Queue{})
+		})
+	})
+}
+
+func TestGetQueue(t *testing.T) {
+	Convey("Given a service that knows a queue's URL", t, func() {
+		params := []url.Values{}
+		ts, s := testService(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<GetQueueUrlResponse><GetQueueUrlResult><QueueUrl>https://queue.amazonaws.com/123456789012/foo</QueueUrl></GetQueueUrlResult></GetQueueUrlResponse>"))
+		}, &params)
+		defer ts.Close()
+
+		q, err := s.GetQueue("foo")
+
+		Convey("It returns the queue", func() {
+			So(err, ShouldBeNil)
+			So(q.URL, ShouldEqual, "https://queue.amazonaws.com/123456789012/foo")
+			So(params[0].Get("Action"), ShouldEqual, "GetQueueUrl")
+		})
+	})
+	Convey("Given a service where the queue does not exist", t, func() {
+		ts, s := testService(testHTTP400, nil)
+		defer ts.Close()
+
+		_, err := s.GetQueue("foo")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestErrors(t *testing.T) {
+	Convey("Given an error from SQS", t, func() {
+		_, err := sqsRetryPredicate(400, testNotFoundError)
+
+		Convey("It is the error for its code", func() {
+			So(errors.Is(err, ErrNonExistentQueue), ShouldBeTrue)
+			So(errors.Is(err, ErrQueueAlreadyExists), ShouldBeFalse)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("sqs", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request(s.Endpoint).Credentials, ShouldResemble, credentials)
+		})
+		Convey("Requests are signed with signature version 4", func() {
+			req, _ := http.NewRequest("POST", "http://localhost:4566", nil)
+			s.request(s.Endpoint).Signer(req, awsauth.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"})
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "Credential=AKID/")
+			So(req.Header.Get("Authorization"), ShouldContainSubstring, "/eu-west-1/sqs/aws4_request")
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
@@ -0,0 +1,71 @@
+package sqs
+
+import (
+	"context"
+	"time"
+)
+
+var noQueueError = sqsError{Type: "Gaws", Code: "GawsNoQueue", Message: "The message was not received from a queue."}
+
+// Messages returns a channel of messages received from the queue. It long polls for LongPollSeconds at a time until ctx is done, and then closes the channel.
+// Messages are received up to 10 at a time, and are hidden for the queue's visibility timeout from when they are received, not from when they are read from the channel.
+// Messages that were received but not read when ctx is done are made visible again. Failed receives are retried after ReceiveErrorDelay, or as ReceiveErrorBackoff says.
+// Call Ack or Nack on each message when you are done with it.
+func (q *Queue) Messages(ctx context.Context) <-chan Message {
+	ch := make(chan Message)
+
+	go func() {
+		defer close(ch)
+
+		failures := 0
+		var waited time.Duration
+		for ctx.Err() == nil {
+			messages, err := q.ReceiveMessage(maxBatchEntries, LongPollSeconds)
+			if err != nil {
+				failures++
+				waited = receiveErrorWait(failures, waited)
+				select {
+				case <-ctx.Done():
+				case <-q.Service.config.CurrentClock().After(waited):
+				}
+				continue
+			}
+			failures, waited = 0, 0
+
+			for i, m := range messages {
+				select {
+				case ch <- m:
+				case <-ctx.Done():
+					q.release(messages[i:])
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// release makes messages visible again right away.
+func (q *Queue) release(messages []Message) {
+	receiptHandles := make([]string, len(messages))
+	for i, m := range messages {
+		receiptHandles[i] = m.ReceiptHandle
+	}
+	q.ChangeMessageVisibilityBatch(receiptHandles, 0)
+}
+
+// Ack deletes the message from the queue it was received from, because it has been handled.
+func (m Message) Ack() error {
+	if m.queue == nil {
+		return noQueueError
+	}
+	return m.queue.DeleteMessage(m.ReceiptHandle)
+}
+
+// Nack gives the message back to the queue it was received from, so it can be received again after delaySeconds. If delaySeconds is 0, it can be received right away.
+func (m Message) Nack(delaySeconds int) error {
+	if m.queue == nil {
+		return noQueueError
+	}
+	return m.queue.ChangeMessageVisibility(m.ReceiptHandle, delaySeconds)
+}
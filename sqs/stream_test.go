@@ -0,0 +1,115 @@
+package sqs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testThreeMessages = []byte(`<ReceiveMessageResponse><ReceiveMessageResult>
+<Message><MessageId>0</MessageId><ReceiptHandle>handle-0</ReceiptHandle><MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody><Body>Hello World!</Body></Message>
+<Message><MessageId>1</MessageId><ReceiptHandle>handle-1</ReceiptHandle><MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody><Body>Hello World!</Body></Message>
+<Message><MessageId>2</MessageId><ReceiptHandle>handle-2</ReceiptHandle><MD5OfBody>ed076287532e86365e841e92bfc50d8c</MD5OfBody><Body>Hello World!</Body></Message>
+</ReceiveMessageResult></ReceiveMessageResponse>`)
+
+func TestMessages(t *testing.T) {
+	LongPollSeconds = 0
+
+	Convey("Given a queue with messages in it", t, func() {
+		recorded, q, stop := testConsumerQueue(2)
+		defer stop()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		messages := q.Messages(ctx)
+
+		first := <-messages
+		second := <-messages
+		ackErr := first.Ack()
+		nackErr := second.Nack(10)
+		cancel()
+
+		Convey("It sends the messages on the channel", func() {
+			So(first.ReceiptHandle, ShouldEqual, "handle-0")
+			So(second.ReceiptHandle, ShouldEqual, "handle-1")
+		})
+		Convey("Ack deletes the message", func() {
+			So(ackErr, ShouldBeNil)
+			So(recorded()[0].Get("Action"), ShouldEqual, "DeleteMessage")
+			So(recorded()[0].Get("ReceiptHandle"), ShouldEqual, "handle-0")
+		})
+		Convey("Nack changes the visibility of the message", func() {
+			So(nackErr, ShouldBeNil)
+			So(recorded()[1].Get("Action"), ShouldEqual, "ChangeMessageVisibility")
+			So(recorded()[1].Get("VisibilityTimeout"), ShouldEqual, "10")
+		})
+		Convey("The channel is closed when the context is done", func() {
+			for range messages {
+			}
+		})
+	})
+	Convey("Given messages that were received but not read", t, func() {
+		lock := sync.Mutex{}
+		released := url.Values{}
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			if r.PostForm.Get("Action") == "ReceiveMessage" {
+				w.Write(testThreeMessages)
+				return
+			}
+			lock.Lock()
+			released = r.PostForm
+			lock.Unlock()
+			w.Write([]byte("<Response></Response>"))
+		}, nil)
+		defer ts.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		messages := q.Messages(ctx)
+		<-messages
+		cancel()
+		for range messages {
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		Convey("They are made visible again", func() {
+			lock.Lock()
+			defer lock.Unlock()
+			So(released.Get("Action"), ShouldEqual, "ChangeMessageVisibilityBatch")
+			So(released.Get("ChangeMessageVisibilityBatchRequestEntry.1.ReceiptHandle"), ShouldEqual, "handle-1")
+			So(released.Get("ChangeMessageVisibilityBatchRequestEntry.2.ReceiptHandle"), ShouldEqual, "handle-2")
+			So(released.Get("ChangeMessageVisibilityBatchRequestEntry.1.VisibilityTimeout"), ShouldEqual, "0")
+		})
+	})
+	Convey("Given a message that was not received from a queue", t, func() {
+		m := Message{ReceiptHandle: "handle"}
+
+		Convey("Ack and Nack return an error", func() {
+			So(m.Ack(), ShouldResemble, noQueueError)
+			So(m.Nack(0), ShouldResemble, noQueueError)
+		})
+	})
+	Convey("Given a queue of a client with a clock that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+		clock := gawstest.NewManualClock(time.Now())
+		q.Service.config.Clock = clock
+
+		ctx, cancel := context.WithCancel(context.Background())
+		messages := q.Messages(ctx)
+		clock.BlockUntil(1)
+		cancel()
+		for range messages {
+		}
+
+		Convey("It waits on the client's clock before receiving again", func() {
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{ReceiveErrorDelay})
+		})
+	})
+
+	LongPollSeconds = 20
+}
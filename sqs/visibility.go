@@ -0,0 +1,86 @@
+package sqs
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ChangeMessageVisibility changes how long a received message stays hidden from other receivers. The new timeout starts now.
+// A visibilityTimeout of 0 makes the message visible again right away.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ChangeMessageVisibility.html for more details.
+func (q *Queue) ChangeMessageVisibility(receiptHandle string, visibilityTimeout int) error {
+	params := query("ChangeMessageVisibility")
+	params.Set("ReceiptHandle", sqsReceiptHandle(receiptHandle))
+	params.Set("VisibilityTimeout", strconv.Itoa(visibilityTimeout))
+
+	req := q.Service.request(q.URL)
+	req.Body = []byte(params.Encode())
+
+	_, err := req.Do()
+
+	return err
+}
+
+// ChangeMessageVisibilityBatch changes the visibility timeout of several messages, 10 at a time. It returns a result for each receipt handle, in the same order.
+// If any message could not be changed, it also returns an error.
+// See http://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ChangeMessageVisibilityBatch.html for more details.
+func (q *Queue) ChangeMessageVisibilityBatch(receiptHandles []string, visibilityTimeout int) ([]BatchResult, error) {
+	results, err := q.batch("ChangeMessageVisibilityBatch", "ChangeMessageVisibilityBatchRequestEntry", len(receiptHandles), nil, nil, func(params url.Values, prefix string, i int) {
+		params.Set(prefix+".ReceiptHandle", sqsReceiptHandle(receiptHandles[i]))
+		params.Set(prefix+".VisibilityTimeout", strconv.Itoa(visibilityTimeout))
+	})
+	return results, err
+}
+
+// Heartbeat keeps a message hidden while it is being worked on, so it is not given to another receiver.
+type Heartbeat struct {
+	stop    chan bool
+	stopped chan bool
+	err     error
+}
+
+// StartHeartbeat extends the visibility timeout of a message to visibilityTimeout seconds, over and over, halfway through each timeout.
+// It keeps going until Stop is called or extending the timeout fails.
+func (q *Queue) StartHeartbeat(receiptHandle string, visibilityTimeout int) *Heartbeat {
+	return startHeartbeat(q, receiptHandle, visibilityTimeout)
+}
+
+// startHeartbeat is StartHeartbeat for any QueueAPI, so a Consumer can keep messages of a fake queue hidden too.
+func startHeartbeat(queue QueueAPI, receiptHandle string, visibilityTimeout int) *Heartbeat {
+	if visibilityTimeout < 1 {
+		visibilityTimeout = 1
+	}
+
+	h := &Heartbeat{stop: make(chan bool), stopped: make(chan bool)}
+
+	clock := queueClock(queue)
+	go func() {
+		defer close(h.stopped)
+
+		for {
+			select {
+			case <-h.stop:
+				return
+			case <-clock.After(time.Duration(visibilityTimeout) * time.Second / 2):
+				err := queue.ChangeMessageVisibility(receiptHandle, visibilityTimeout)
+				if err != nil {
+					h.err = err
+					return
+				}
+			}
+		}
+	}()
+	return h
+}
+
+// Stop stops the heartbeat. It returns the error that stopped the heartbeat early, if there was one.
+func (h *Heartbeat) Stop() error {
+	select {
+	case <-h.stopped:
+	default:
+		close(h.stop)
+		<-h.stopped
+	}
+	return h.err
+}
@@ -0,0 +1,124 @@
+package sqs
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestChangeMessageVisibility(t *testing.T) {
+	Convey("Given a queue", t, func() {
+		params := url.Values{}
+		ts, q := testQueue(testHTTP200, &params)
+		defer ts.Close()
+
+		err := q.ChangeMessageVisibility("handle", 120)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+		})
+		Convey("It sends the receipt handle and the new timeout", func() {
+			So(params.Get("Action"), ShouldEqual, "ChangeMessageVisibility")
+			So(params.Get("ReceiptHandle"), ShouldEqual, "handle")
+			So(params.Get("VisibilityTimeout"), ShouldEqual, "120")
+		})
+	})
+	Convey("Given a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		Convey("It returns an error", func() {
+			So(q.ChangeMessageVisibility("handle", 120), ShouldNotBeNil)
+		})
+	})
+}
+
+func TestChangeMessageVisibilityBatch(t *testing.T) {
+	Convey("Given a queue that accepts batches", t, func() {
+		requests := []url.Values{}
+		ts, q := testQueue(testBatchHandler("ChangeMessageVisibilityBatch", nil, false, &requests), nil)
+		defer ts.Close()
+
+		results, err := q.ChangeMessageVisibilityBatch([]string{"a", "b"}, 0)
+
+		Convey("It does not return an error", func() {
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 2)
+		})
+		Convey("It sends the timeout for every entry", func() {
+			So(requests[0].Get("ChangeMessageVisibilityBatchRequestEntry.2.ReceiptHandle"), ShouldEqual, "b")
+			So(requests[0].Get("ChangeMessageVisibilityBatchRequestEntry.2.VisibilityTimeout"), ShouldEqual, "0")
+		})
+	})
+}
+
+func TestHeartbeat(t *testing.T) {
+	Convey("Given a heartbeat on a message with a one second timeout", t, func() {
+		var lock sync.Mutex
+		extensions := 0
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			extensions++
+			lock.Unlock()
+			w.Write([]byte("OK"))
+		}, nil)
+		defer ts.Close()
+
+		h := q.StartHeartbeat("handle", 1)
+		time.Sleep(1200 * time.Millisecond)
+		err := h.Stop()
+
+		Convey("It extends the timeout every half second and stops without an error", func() {
+			lock.Lock()
+			defer lock.Unlock()
+			So(extensions, ShouldEqual, 2)
+			So(err, ShouldBeNil)
+		})
+	})
+	Convey("Given a heartbeat on a message with a one minute timeout, on a client with a manual clock", t, func() {
+		var lock sync.Mutex
+		extensions := 0
+		ts, q := testQueue(func(w http.ResponseWriter, r *http.Request) {
+			lock.Lock()
+			extensions++
+			lock.Unlock()
+			w.Write([]byte("OK"))
+		}, nil)
+		defer ts.Close()
+		clock := gawstest.NewManualClock(time.Now())
+		q.Service.config.Clock = clock
+
+		h := q.StartHeartbeat("handle", 60)
+		defer h.Stop()
+		clock.BlockUntil(1)
+
+		Convey("It extends the timeout halfway through each timeout", func() {
+			clock.Advance(29 * time.Second)
+			So(clock.Sleeps(), ShouldResemble, []time.Duration{30 * time.Second})
+			clock.Advance(time.Second)
+			clock.BlockUntil(1)
+			clock.Advance(30 * time.Second)
+			clock.BlockUntil(1)
+
+			lock.Lock()
+			defer lock.Unlock()
+			So(extensions, ShouldEqual, 2)
+		})
+	})
+	Convey("Given a heartbeat on a queue that returns errors", t, func() {
+		ts, q := testQueue(testHTTP400, nil)
+		defer ts.Close()
+
+		h := q.StartHeartbeat("handle", 1)
+		time.Sleep(700 * time.Millisecond)
+
+		Convey("Stop returns the error", func() {
+			So(h.Stop(), ShouldNotBeNil)
+		})
+	})
+}
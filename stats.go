@@ -0,0 +1,74 @@
+package gaws
+
+import (
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are counts of the requests gaws has made since the process started, so that a running producer or consumer can be inspected
+// without a MetricsCollector. Requests in dry-run mode are not counted. They can be published with expvar, like
+//
+//	expvar.Publish("gaws", expvar.Func(func() interface{} { return gaws.CurrentStats() }))
+type Stats struct {
+	Requests  int64 // Requests that have finished, each counted once however many times it was tried
+	Errors    int64 // Requests that returned an error
+	Tries     int64 // Times requests were sent
+	Retries   int64 // Times requests were sent again after a try failed
+	Throttles int64 // Tries AWS throttled
+
+	InFlight int64         // Requests being made now, including any waiting before a retry
+	Waiting  int64         // Requests waiting before a retry now
+	Backoff  time.Duration // How long the last request to wait before a retry waited, or is waiting
+
+	ConnectionsOpened int64 // Connections opened to send tries. Connections are kept by the transport, which does not say when it closes them.
+	ConnectionsReused int64 // Tries sent on a connection that was already open
+}
+
+// stats are the counts behind CurrentStats.
+var stats struct {
+	requests, errors, tries, retries, throttles atomic.Int64
+	inFlight, waiting, backoff                  atomic.Int64
+	connectionsOpened, connectionsReused        atomic.Int64
+}
+
+// CurrentStats returns the counts of the requests gaws has made so far. Each count is read on its own, so while requests are being made
+// they can be a little out of step with each other.
+func CurrentStats() Stats {
+	return Stats{
+		Requests:          stats.requests.Load(),
+		Errors:            stats.errors.Load(),
+		Tries:             stats.tries.Load(),
+		Retries:           stats.retries.Load(),
+		Throttles:         stats.throttles.Load(),
+		InFlight:          stats.inFlight.Load(),
+		Waiting:           stats.waiting.Load(),
+		Backoff:           time.Duration(stats.backoff.Load()),
+		ConnectionsOpened: stats.connectionsOpened.Load(),
+		ConnectionsReused: stats.connectionsReused.Load(),
+	}
+}
+
+// countDone counts a request that has finished.
+func countDone(m RequestMetrics, err error) {
+	stats.requests.Add(1)
+	if err != nil {
+		stats.errors.Add(1)
+	}
+	stats.tries.Add(int64(m.Tries))
+	if m.Tries > 1 {
+		stats.retries.Add(int64(m.Tries - 1))
+	}
+	stats.throttles.Add(int64(m.Throttles))
+}
+
+// connectionTrace counts the connections tries are sent on.
+var connectionTrace = &httptrace.ClientTrace{
+	GotConn: func(info httptrace.GotConnInfo) {
+		if info.Reused {
+			stats.connectionsReused.Add(1)
+		} else {
+			stats.connectionsOpened.Add(1)
+		}
+	},
+}
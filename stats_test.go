@@ -0,0 +1,68 @@
+package gaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStats(t *testing.T) {
+	Convey("Given a request that succeeds", t, func() {
+		var inFlight int64
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight = CurrentStats().InFlight
+			testHTTP200(w, r)
+		}))
+		defer ts.Close()
+
+		before := CurrentStats()
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.Do()
+		r.Do()
+		after := CurrentStats()
+
+		Convey("It is counted", func() {
+			So(after.Requests-before.Requests, ShouldEqual, 2)
+			So(after.Tries-before.Tries, ShouldEqual, 2)
+			So(after.Errors-before.Errors, ShouldEqual, 0)
+		})
+		Convey("It is in flight while it is made", func() {
+			So(inFlight, ShouldBeGreaterThan, 0)
+			So(after.InFlight, ShouldEqual, before.InFlight)
+		})
+		Convey("Its connections are counted", func() {
+			So(after.ConnectionsOpened-before.ConnectionsOpened, ShouldEqual, 1)
+			So(after.ConnectionsReused-before.ConnectionsReused, ShouldEqual, 1)
+		})
+	})
+
+	Convey("Given a request that is throttled", t, func() {
+		_, restore := useTestClock()
+		defer restore()
+
+		ts := httptest.NewServer(http.HandlerFunc(testAWSThrottle))
+		defer ts.Close()
+
+		before := CurrentStats()
+		r := canonicalRequest()
+		r.URL = ts.URL
+		r.Do()
+		after := CurrentStats()
+
+		Convey("Its retries and throttles are counted", func() {
+			So(after.Requests-before.Requests, ShouldEqual, 1)
+			So(after.Errors-before.Errors, ShouldEqual, 1)
+			So(after.Tries-before.Tries, ShouldEqual, MaxTries-1)
+			So(after.Retries-before.Retries, ShouldEqual, MaxTries-2)
+			So(after.Throttles-before.Throttles, ShouldEqual, MaxTries-1)
+		})
+		Convey("Its last backoff is kept", func() {
+			So(after.Backoff, ShouldEqual, 1600*time.Millisecond)
+			So(after.Waiting, ShouldEqual, before.Waiting)
+		})
+	})
+}
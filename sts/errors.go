@@ -0,0 +1,20 @@
+// Code generated by errgen from the go:generate line in sts.go. DO NOT EDIT.
+
+package sts
+
+// These are errors STS returns. errors.Is(err, sts.ErrAccessDenied) is true for any error with the same code, whatever its message.
+var (
+	ErrAccessDenied            = stsError{Code: "AccessDenied"}
+	ErrExpiredToken            = stsError{Code: "ExpiredToken"}
+	ErrRegionDisabled          = stsError{Code: "RegionDisabledException"}
+	ErrMalformedPolicyDocument = stsError{Code: "MalformedPolicyDocument"}
+	ErrPackedPolicyTooLarge    = stsError{Code: "PackedPolicyTooLarge"}
+	ErrIDPRejectedClaim        = stsError{Code: "IDPRejectedClaim"}
+	ErrInvalidIdentityToken    = stsError{Code: "InvalidIdentityToken"}
+)
+
+// Is reports whether target is an error with the same code as e, so that errors.Is matches the errors above.
+func (e stsError) Is(target error) bool {
+	t, ok := target.(stsError)
+	return ok && t.Code != "" && e.Code == t.Code
+}
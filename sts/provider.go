@@ -0,0 +1,77 @@
+package sts
+
+import (
+	"sync"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+// ExpiryWindow is how long before they expire a RoleProvider gets new credentials, so requests are not signed with credentials that are about to expire.
+var ExpiryWindow = 5 * time.Minute
+
+// RoleProvider is a gaws.CredentialsProvider that assumes a role, and assumes it again when the credentials are about to expire.
+// It signs its AssumeRole requests with the Credentials of its Service. If they are nil, awsauth finds them in the environment or the instance's role,
+// so a RoleProvider can be used as gaws.Credentials.
+type RoleProvider struct {
+	Service *STSService
+	Role    AssumeRoleInput
+
+	lock        sync.Mutex // Protects credentials, and makes sure the role is only assumed once at a time
+	credentials Credentials
+}
+
+var _ gaws.RefreshingProvider = (*RoleProvider)(nil)
+
+// NewRoleProvider returns a RoleProvider for the role. It does not assume the role until credentials are needed.
+func (s *STSService) NewRoleProvider(role AssumeRoleInput) *RoleProvider {
+	return &RoleProvider{Service: s, Role: role}
+}
+
+// Credentials returns the credentials for the role, assuming it if they are missing or about to expire.
+func (p *RoleProvider) Credentials() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	now := gaws.DefaultClock.Now()
+	if p.Service != nil {
+		now = p.Service.config.CurrentClock().Now()
+	}
+	if p.credentials.expiredAt(now, ExpiryWindow) {
+		err := p.assume()
+		if err != nil {
+			return awsauth.Credentials{}, err
+		}
+	}
+	return p.credentials.Credentials()
+}
+
+// Refresh assumes the role now, even if the credentials have not expired, and returns the new credentials. A gaws.Refresher calls it
+// to get them ahead of time. If it fails, the old credentials are kept.
+func (p *RoleProvider) Refresh() (awsauth.Credentials, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	err := p.assume()
+	if err != nil {
+		return awsauth.Credentials{}, err
+	}
+	return p.credentials.Credentials()
+}
+
+// assume assumes the role and keeps the credentials. The lock must be held.
+func (p *RoleProvider) assume() error {
+	// Do not sign with gaws.Credentials, which may be this provider.
+	s := *p.Service
+	if s.Credentials == nil {
+		s.Credentials = gaws.StaticCredentials{}
+	}
+
+	result, err := s.AssumeRole(p.Role)
+	if err != nil {
+		return err
+	}
+	p.credentials = result.Credentials
+	return nil
+}
@@ -0,0 +1,119 @@
+package sts
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testAssumeRoleExpiring returns a handler that assumes roles with credentials that expire after d. Each has a new access key.
+func testAssumeRoleExpiring(d time.Duration) http.HandlerFunc {
+	n := 0
+	return func(w http.ResponseWriter, r *http.Request) {
+		n++
+		fmt.Fprintf(w, `<AssumeRoleResponse><AssumeRoleResult><Credentials>
+<AccessKeyId>ASIA%d</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token</SessionToken><Expiration>%v</Expiration>
+</Credentials></AssumeRoleResult></AssumeRoleResponse>`, n, time.Now().Add(d).UTC().Format(time.RFC3339))
+	}
+}
+
+func TestRoleProvider(t *testing.T) {
+	role := AssumeRoleInput{RoleArn: "arn:aws:iam::123456789012:role/worker", RoleSessionName: "web-1"}
+
+	Convey("Given a role provider", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testAssumeRoleExpiring(time.Hour), &params)
+		defer ts.Close()
+		p := s.NewRoleProvider(role)
+
+		Convey("It assumes the role once and caches the credentials", func() {
+			first, err := p.Credentials()
+			So(err, ShouldBeNil)
+			second, _ := p.Credentials()
+
+			So(len(params), ShouldEqual, 1)
+			So(params[0].Get("RoleArn"), ShouldEqual, role.RoleArn)
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(first.SecurityToken, ShouldEqual, "token")
+			So(second, ShouldResemble, first)
+		})
+
+		Convey("Refresh assumes the role again even though the credentials have not expired", func() {
+			first, _ := p.Credentials()
+			refreshed, err := p.Refresh()
+			So(err, ShouldBeNil)
+			second, _ := p.Credentials()
+
+			So(len(params), ShouldEqual, 2)
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(refreshed.AccessKeyID, ShouldEqual, "ASIA2")
+			So(second, ShouldResemble, refreshed)
+		})
+
+		Convey("When it is gaws.Credentials", func() {
+			gaws.Credentials = p
+			defer func() { gaws.Credentials = nil }()
+
+			_, err := p.Credentials()
+
+			Convey("It does not sign its own requests with itself", func() {
+				So(err, ShouldBeNil)
+				So(len(params), ShouldEqual, 1)
+			})
+		})
+	})
+	Convey("Given credentials that are about to expire", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testAssumeRoleExpiring(time.Minute), &params)
+		defer ts.Close()
+		p := s.NewRoleProvider(role)
+
+		first, _ := p.Credentials()
+		second, _ := p.Credentials()
+
+		Convey("The role is assumed again", func() {
+			So(len(params), ShouldEqual, 2)
+			So(first.AccessKeyID, ShouldEqual, "ASIA1")
+			So(second.AccessKeyID, ShouldEqual, "ASIA2")
+		})
+	})
+	Convey("Given a role provider of a client with a clock", t, func() {
+		params := []url.Values{}
+		ts, s := testService(testAssumeRoleExpiring(time.Hour), &params)
+		defer ts.Close()
+		clock := gawstest.NewClock(time.Now())
+		s.config.Clock = clock
+		p := s.NewRoleProvider(role)
+		p.Credentials()
+
+		Convey("The role is assumed again when the credentials are about to expire by the clock", func() {
+			clock.Advance(50 * time.Minute)
+			p.Credentials()
+			So(len(params), ShouldEqual, 1)
+
+			clock.Advance(6 * time.Minute)
+			p.Credentials()
+			So(len(params), ShouldEqual, 2)
+		})
+	})
+	Convey("Given a role that cannot be assumed", t, func() {
+		ts, s := testService(testHTTP403, nil)
+		defer ts.Close()
+
+		_, err := s.NewRoleProvider(role).Credentials()
+
+		Convey("It returns the error", func() {
+			So(err, ShouldNotBeNil)
+		})
+		Convey("Refresh returns the error", func() {
+			_, err := s.NewRoleProvider(role).Refresh()
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,134 @@
+// Package sts provides a way to interact with the AWS Security Token Service.
+package sts
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/smartystreets/go-aws-auth"
+)
+
+//go:generate go run ../internal/errgen -type stsError -field Code -service STS AccessDenied ExpiredToken RegionDisabledException MalformedPolicyDocument PackedPolicyTooLarge IDPRejectedClaim InvalidIdentityToken
+
+// stsError is the error document returned from the STS service.
+type stsError struct {
+	Type    string `xml:"Error>Type"`
+	Code    string `xml:"Error>Code"`
+	Message string `xml:"Error>Message"`
+}
+
+// Error formats the stsError into an error message.
+func (e stsError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Code, e.Message)
+}
+
+func stsRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := stsError{}
+
+	err := xml.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.Code == "Throttling" || error.Code == "IDPCommunicationError" {
+		return true, error
+	}
+
+	return false, error
+}
+
+// STSService is the STS service at AWS. The global endpoint is https://sts.amazonaws.com, and the endpoint for a region is gaws.Endpoint("sts", region).
+type STSService struct {
+	Endpoint    string
+	Credentials gaws.CredentialsProvider // The credentials requests are signed with. If it is nil, gaws.Credentials is used.
+	config      gaws.Config              // Set by New
+}
+
+// STSAPI is implemented by *STSService. Code that takes an STSAPI can be given a fake in tests instead.
+type STSAPI interface {
+	AssumeRole(input AssumeRoleInput) (AssumeRoleResult, error)
+	GetCallerIdentity() (CallerIdentity, error)
+	GetFederationToken(name string, policy string, duration time.Duration) (FederationTokenResult, error)
+	GetSessionToken(duration time.Duration, serialNumber string, tokenCode string) (Credentials, error)
+}
+
+var _ STSAPI = (*STSService)(nil)
+
+// New returns an STSService with the options applied. Its endpoint is gaws.Endpoint("sts", region) unless gaws.WithEndpoint is given,
+// and it signs requests with the credentials from gaws.WithCredentials.
+func New(options ...gaws.Option) *STSService {
+	config := gaws.NewConfig(options...)
+	return &STSService{Endpoint: config.ServiceEndpoint("sts"), Credentials: config.Credentials, config: config}
+}
+
+func (s *STSService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: stsRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("sts", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-www-form-urlencoded",
+		},
+		Credentials: s.Credentials,
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// query returns the parameters for an STS API call.
+func query(action string) url.Values {
+	return url.Values{
+		"Action":  {action},
+		"Version": {"2011-06-15"},
+	}
+}
+
+// Credentials are temporary security credentials. They are a gaws.CredentialsProvider, so they can be used as gaws.Credentials,
+// or as the Credentials of a request or service.
+type Credentials struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+}
+
+// Credentials returns the credentials for awsauth to sign requests with.
+func (c Credentials) Credentials() (awsauth.Credentials, error) {
+	return awsauth.Credentials{
+		AccessKeyID:     c.AccessKeyId,
+		SecretAccessKey: c.SecretAccessKey,
+		SecurityToken:   c.SessionToken,
+		Expiration:      c.Expiration,
+	}, nil
+}
+
+// Expired returns true if the credentials expire within d, by gaws.DefaultClock.
+func (c Credentials) Expired(d time.Duration) bool {
+	return c.expiredAt(gaws.DefaultClock.Now(), d)
+}
+
+// expiredAt returns true if the credentials expire within d of now.
+func (c Credentials) expiredAt(now time.Time, d time.Duration) bool {
+	return !c.Expiration.After(now.Add(d))
+}
+
+// setDuration sets the DurationSeconds parameter, if there is a duration.
+func setDuration(params url.Values, d time.Duration) {
+	if d > 0 {
+		params.Set("DurationSeconds", fmt.Sprint(int64(d/time.Second)))
+	}
+}
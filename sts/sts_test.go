@@ -0,0 +1,132 @@
+package sts
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/controlgroup/gaws"
+	"github.com/controlgroup/gaws/gawstest"
+	"github.com/smartystreets/go-aws-auth"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func testBadXml(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("<AssumeRoleResponse><AssumeRoleResult>"))
+}
+
+var testAccessDeniedError = []byte(`<ErrorResponse>
+  <Error>
+    <Type>Sender</Type>
+    <Code>AccessDenied</Code>
+    <Message>User is not authorized to perform: sts:AssumeRole</Message>
+  </Error>
+  <RequestId>9dd01905-5012-5f99-8663-4b3ecd0dfaef</RequestId>
+</ErrorResponse>`)
+
+func testHTTP403(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(403)
+	w.Write(testAccessDeniedError)
+}
+
+// testService returns a service on a server that runs handler and records the parameters of every request.
+func testService(handler http.HandlerFunc, params *[]url.Values) (*httptest.Server, STSService) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if params != nil {
+			*params = append(*params, r.PostForm)
+		}
+		handler(w, r)
+	}))
+	return ts, STSService{Endpoint: ts.URL}
+}
+
+func TestRetryPredicate(t *testing.T) {
+	Convey("Given a response that is not XML", t, func() {
+		result, err := stsRetryPredicate(400, []byte("bad data"))
+
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("Error is not nil", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given a response that has a status of 500", t, func() {
+		result, _ := stsRetryPredicate(500, []byte("<ErrorResponse><Error><Code>InternalError</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that is a \"Throttling\" code", t, func() {
+		result, _ := stsRetryPredicate(400, []byte("<ErrorResponse><Error><Code>Throttling</Code></Error></ErrorResponse>"))
+		Convey("RetryPredicate returns true", func() {
+			So(result, ShouldBeTrue)
+		})
+	})
+	Convey("Given a response that says access is denied", t, func() {
+		result, err := stsRetryPredicate(403, testAccessDeniedError)
+		Convey("RetryPredicate returns false", func() {
+			So(result, ShouldBeFalse)
+		})
+		Convey("The error has the code and message", func() {
+			So(err.Error(), ShouldEqual, "AccessDenied: User is not authorized to perform: sts:AssumeRole")
+		})
+	})
+}
+
+func TestCredentials(t *testing.T) {
+	Convey("Given temporary credentials", t, func() {
+		expiration := time.Now().Add(time.Hour)
+		c := Credentials{AccessKeyId: "ASIA", SecretAccessKey: "secret", SessionToken: "token", Expiration: expiration}
+
+		Convey("They provide credentials for awsauth", func() {
+			keys, err := c.Credentials()
+
+			So(err, ShouldBeNil)
+			So(keys, ShouldResemble, awsauth.Credentials{AccessKeyID: "ASIA", SecretAccessKey: "secret", SecurityToken: "token", Expiration: expiration})
+		})
+		Convey("They are expired if they expire within the duration", func() {
+			So(c.Expired(time.Minute), ShouldBeFalse)
+			So(c.Expired(2*time.Hour), ShouldBeTrue)
+		})
+	})
+	Convey("Given credentials that expire in an hour by a clock", t, func() {
+		clock := gawstest.NewClock(time.Now())
+		defaultClock := gaws.DefaultClock
+		gaws.DefaultClock = clock
+		defer func() { gaws.DefaultClock = defaultClock }()
+		c := Credentials{AccessKeyId: "ASIA", Expiration: clock.Now().Add(time.Hour)}
+
+		Convey("They expire by the clock", func() {
+			So(c.Expired(0), ShouldBeFalse)
+			clock.Advance(time.Hour)
+			So(c.Expired(0), ShouldBeTrue)
+		})
+	})
+	Convey("Given no credentials", t, func() {
+		Convey("They are expired", func() {
+			So(Credentials{}.Expired(0), ShouldBeTrue)
+		})
+	})
+}
+
+func TestNew(t *testing.T) {
+	Convey("Given options", t, func() {
+		credentials := gaws.StaticCredentials{AccessKeyID: "AKID"}
+		s := New(gaws.WithRegion("eu-west-1"), gaws.WithCredentials(credentials))
+
+		Convey("The endpoint is the one in the region", func() {
+			So(s.Endpoint, ShouldEqual, gaws.Endpoint("sts", "eu-west-1"))
+		})
+		Convey("Requests are signed with the credentials", func() {
+			So(s.request().Credentials, ShouldResemble, credentials)
+		})
+		Convey("An endpoint is used instead", func() {
+			s = New(gaws.WithEndpoint("http://localhost:4566"))
+			So(s.Endpoint, ShouldEqual, "http://localhost:4566")
+		})
+	})
+}
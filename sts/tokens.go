@@ -0,0 +1,164 @@
+package sts
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// AssumeRoleInput says which role AssumeRole assumes and how.
+type AssumeRoleInput struct {
+	RoleArn         string
+	RoleSessionName string        // A name for the session, like the name of the process. It shows up in CloudTrail.
+	Duration        time.Duration // How long the credentials last. If it is 0, they last an hour.
+	ExternalId      string        // Required by some roles that are assumed by other accounts
+	Policy          string        // A JSON policy that limits what the credentials can do further than the role does
+	SerialNumber    string        // The MFA device, if the role requires MFA
+	TokenCode       string        // The code from the MFA device
+}
+
+// AssumedRoleUser is the user the credentials from AssumeRole act as.
+type AssumedRoleUser struct {
+	Arn           string
+	AssumedRoleId string
+}
+
+// AssumeRoleResult is the result of AssumeRole.
+type AssumeRoleResult struct {
+	Credentials      Credentials
+	AssumedRoleUser  AssumedRoleUser
+	PackedPolicySize int // The size of Policy as a percentage of the most allowed
+}
+
+type assumeRoleResponse struct {
+	Result AssumeRoleResult `xml:"AssumeRoleResult"`
+}
+
+// AssumeRole gets temporary credentials for a role. It is calling the AssumeRole API call.
+// See http://docs.aws.amazon.com/STS/latest/APIReference/API_AssumeRole.html for more details.
+func (s *STSService) AssumeRole(input AssumeRoleInput) (AssumeRoleResult, error) {
+	result := assumeRoleResponse{}
+
+	params := query("AssumeRole")
+	params.Set("RoleArn", input.RoleArn)
+	params.Set("RoleSessionName", input.RoleSessionName)
+	setDuration(params, input.Duration)
+	for name, value := range map[string]string{"ExternalId": input.ExternalId, "Policy": input.Policy, "SerialNumber": input.SerialNumber, "TokenCode": input.TokenCode} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Result, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Result, err
+}
+
+type getSessionTokenResponse struct {
+	Credentials Credentials `xml:"GetSessionTokenResult>Credentials"`
+}
+
+// GetSessionToken gets temporary credentials for the caller, which last for duration, or 12 hours if it is 0.
+// If the caller must use MFA, serialNumber is its MFA device and tokenCode is the code from it. Otherwise they are empty.
+// It is calling the GetSessionToken API call.
+// See http://docs.aws.amazon.com/STS/latest/APIReference/API_GetSessionToken.html for more details.
+func (s *STSService) GetSessionToken(duration time.Duration, serialNumber string, tokenCode string) (Credentials, error) {
+	result := getSessionTokenResponse{}
+
+	params := query("GetSessionToken")
+	setDuration(params, duration)
+	if serialNumber != "" {
+		params.Set("SerialNumber", serialNumber)
+		params.Set("TokenCode", tokenCode)
+	}
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Credentials, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Credentials, err
+}
+
+// FederatedUser is the user the credentials from GetFederationToken act as.
+type FederatedUser struct {
+	Arn             string
+	FederatedUserId string
+}
+
+// FederationTokenResult is the result of GetFederationToken.
+type FederationTokenResult struct {
+	Credentials      Credentials
+	FederatedUser    FederatedUser
+	PackedPolicySize int
+}
+
+type getFederationTokenResponse struct {
+	Result FederationTokenResult `xml:"GetFederationTokenResult"`
+}
+
+// GetFederationToken gets temporary credentials for a federated user with the name, like a user of your application.
+// They can do what both the caller and policy, a JSON policy, allow. They last for duration, or 12 hours if it is 0.
+// It is calling the GetFederationToken API call.
+// See http://docs.aws.amazon.com/STS/latest/APIReference/API_GetFederationToken.html for more details.
+func (s *STSService) GetFederationToken(name string, policy string, duration time.Duration) (FederationTokenResult, error) {
+	result := getFederationTokenResponse{}
+
+	params := query("GetFederationToken")
+	params.Set("Name", name)
+	if policy != "" {
+		params.Set("Policy", policy)
+	}
+	setDuration(params, duration)
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Result, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Result, err
+}
+
+// CallerIdentity is who the credentials a request is signed with belong to.
+type CallerIdentity struct {
+	Account string
+	Arn     string
+	UserId  string
+}
+
+type getCallerIdentityResponse struct {
+	Identity CallerIdentity `xml:"GetCallerIdentityResult"`
+}
+
+// GetCallerIdentity returns who the credentials the service signs requests with belong to. It is calling the GetCallerIdentity API call.
+// See http://docs.aws.amazon.com/STS/latest/APIReference/API_GetCallerIdentity.html for more details.
+func (s *STSService) GetCallerIdentity() (CallerIdentity, error) {
+	result := getCallerIdentityResponse{}
+
+	params := query("GetCallerIdentity")
+
+	req := s.request()
+	req.Body = []byte(params.Encode())
+
+	resp, err := req.Do()
+	if err != nil {
+		return result.Identity, err
+	}
+
+	err = xml.Unmarshal(resp, &result)
+	return result.Identity, err
+}
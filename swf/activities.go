@@ -0,0 +1,127 @@
+package swf
+
+import (
+	"encoding/json"
+
+	"github.com/controlgroup/gaws"
+)
+
+// ActivityTask is an activity for a worker to do.
+type ActivityTask struct {
+	TaskToken         string            `json:"taskToken"` // Identifies the task when responding to it
+	ActivityId        string            `json:"activityId"`
+	StartedEventId    int64             `json:"startedEventId"`
+	Input             string            `json:"input"`
+	WorkflowExecution WorkflowExecution `json:"workflowExecution"`
+	ActivityType      ActivityType      `json:"activityType"`
+}
+
+type pollForActivityTaskRequest struct {
+	Domain   string   `json:"domain"`
+	TaskList TaskList `json:"taskList"`
+	Identity string   `json:"identity,omitempty"`
+}
+
+// PollForActivityTask waits up to 60 seconds for an activity task on the task list. It returns nil if there is none, so workers should poll again.
+// identity names the worker in the workflow history, like its host name. It is calling the PollForActivityTask API call.
+// See http://docs.aws.amazon.com/amazonswf/latest/apireference/API_PollForActivityTask.html for more details.
+func (s *SWFService) PollForActivityTask(domain string, taskList string, identity string) (*ActivityTask, error) {
+	bodyAsJson, err := json.Marshal(pollForActivityTaskRequest{Domain: domain, TaskList: TaskList{Name: taskList}, Identity: identity})
+	if err != nil {
+		return nil, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "SimpleWorkflowService.PollForActivityTask"
+	req.Timeout = PollTimeout
+
+	resp, err := req.Do()
+	if err != nil {
+		return nil, err
+	}
+
+	task := ActivityTask{}
+	err = gaws.DecodeJSON(resp, &task)
+	if err != nil || task.TaskToken == "" {
+		return nil, err
+	}
+	return &task, nil
+}
+
+type respondActivityTaskCompletedRequest struct {
+	TaskToken string `json:"taskToken"`
+	Result    string `json:"result,omitempty"`
+}
+
+// RespondActivityTaskCompleted tells SWF the activity task is done, and what its result is. It is calling the RespondActivityTaskCompleted API call.
+// See http://docs.aws.amazon.com/amazonswf/latest/apireference/API_RespondActivityTaskCompleted.html for more details.
+func (s *SWFService) RespondActivityTaskCompleted(taskToken string, result string) error {
+	bodyAsJson, err := json.Marshal(respondActivityTaskCompletedRequest{TaskToken: taskToken, Result: result})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "SimpleWorkflowService.RespondActivityTaskCompleted"
+
+	_, err = req.Do()
+	return err
+}
+
+type respondActivityTaskFailedRequest struct {
+	TaskToken string `json:"taskToken"`
+	Reason    string `json:"reason,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// RespondActivityTaskFailed tells SWF the activity task failed, and why. It is calling the RespondActivityTaskFailed API call.
+// See http://docs.aws.amazon.com/amazonswf/latest/apireference/API_RespondActivityTaskFailed.html for more details.
+func (s *SWFService) RespondActivityTaskFailed(taskToken string, reason string, details string) error {
+	bodyAsJson, err := json.Marshal(respondActivityTaskFailedRequest{TaskToken: taskToken, Reason: reason, Details: details})
+	if err != nil {
+		return err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "SimpleWorkflowService.RespondActivityTaskFailed"
+
+	_, err = req.Do()
+	return err
+}
+
+type recordActivityTaskHeartbeatRequest struct {
+	TaskToken string `json:"taskToken"`
+	Details   string `json:"details,omitempty"`
+}
+
+type recordActivityTaskHeartbeatResult struct {
+	CancelRequested bool `json:"cancelRequested"`
+}
+
+// RecordActivityTaskHeartbeat tells SWF the worker is still doing the activity task, so it does not time out.
+// It returns true if the workflow asked for the task to be cancelled, in which case the worker should stop.
+// It is calling the RecordActivityTaskHeartbeat API call.
+// See http://docs.aws.amazon.com/amazonswf/latest/apireference/API_RecordActivityTaskHeartbeat.html for more details.
+func (s *SWFService) RecordActivityTaskHeartbeat(taskToken string, details string) (bool, error) {
+	result := recordActivityTaskHeartbeatResult{}
+
+	bodyAsJson, err := json.Marshal(recordActivityTaskHeartbeatRequest{TaskToken: taskToken, Details: details})
+	if err != nil {
+		return false, err
+	}
+
+	req := s.request()
+	req.Body = bodyAsJson
+	req.Headers["X-Amz-Target"] = "SimpleWorkflowService.RecordActivityTaskHeartbeat"
+
+	resp, err := req.Do()
+	if err != nil {
+		return false, err
+	}
+
+	err = gaws.DecodeJSON(resp, &result)
+	return result.CancelRequested, err
+}
@@ -0,0 +1,162 @@
+package swf
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPollForActivityTask(t *testing.T) {
+	Convey("Given an activity task", t, func() {
+		ts, s, requests := testService(testJson(`{"taskToken":"token-1","activityId":"charge","startedEventId":6,"input":"order-42",
+			"workflowExecution":{"workflowId":"order-42","runId":"run-1"},"activityType":{"name":"Charge","version":"1"}}`))
+		defer ts.Close()
+
+		task, err := s.PollForActivityTask("orders", "payments", "worker-1")
+
+		Convey("It returns the task", func() {
+			So(err, ShouldBeNil)
+			So(*task, ShouldResemble, ActivityTask{
+				TaskToken:         "token-1",
+				ActivityId:        "charge",
+				StartedEventId:    6,
+				Input:             "order-42",
+				WorkflowExecution: WorkflowExecution{WorkflowId: "order-42", RunId: "run-1"},
+				ActivityType:      ActivityType{Name: "Charge", Version: "1"},
+			})
+		})
+		Convey("It polls the task list", func() {
+			So(requests()[0].Target, ShouldEqual, "SimpleWorkflowService.PollForActivityTask")
+			So(requests()[0].Body, ShouldResemble, map[string]interface{}{
+				"domain":   "orders",
+				"taskList": map[string]interface{}{"name": "payments"},
+				"identity": "worker-1",
+			})
+		})
+	})
+	Convey("Given no activity task within the poll", t, func() {
+		ts, s, _ := testService(testJson(`{"startedEventId":0}`))
+		defer ts.Close()
+
+		task, err := s.PollForActivityTask("orders", "payments", "")
+
+		Convey("It returns no task", func() {
+			So(err, ShouldBeNil)
+			So(task, ShouldBeNil)
+		})
+	})
+	Convey("Given a poll that takes longer than PollTimeout", t, func() {
+		ts, s, _ := testService(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		})
+		defer ts.Close()
+		defer func(timeout time.Duration) { PollTimeout = timeout }(PollTimeout)
+		PollTimeout = 50 * time.Millisecond
+
+		_, err := s.PollForActivityTask("orders", "payments", "")
+
+		Convey("It gives up", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given an unknown domain", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.PollForActivityTask("orders", "payments", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, unknownResourceError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.PollForActivityTask("orders", "payments", "")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestRespondActivityTaskCompleted(t *testing.T) {
+	Convey("Given a task and its result", t, func() {
+		ts, s, requests := testService(testJson(``))
+		defer ts.Close()
+
+		err := s.RespondActivityTaskCompleted("token-1", "charged")
+
+		Convey("It is sent", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "SimpleWorkflowService.RespondActivityTaskCompleted")
+			So(requests()[0].Body, ShouldResemble, map[string]interface{}{"taskToken": "token-1", "result": "charged"})
+		})
+	})
+	Convey("Given a task that is no longer open", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		err := s.RespondActivityTaskCompleted("token-1", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, unknownResourceError)
+		})
+	})
+}
+
+func TestRespondActivityTaskFailed(t *testing.T) {
+	Convey("Given a task and why it failed", t, func() {
+		ts, s, requests := testService(testJson(``))
+		defer ts.Close()
+
+		err := s.RespondActivityTaskFailed("token-1", "CardDeclined", "insufficient funds")
+
+		Convey("It is sent", func() {
+			So(err, ShouldBeNil)
+			So(requests()[0].Target, ShouldEqual, "SimpleWorkflowService.RespondActivityTaskFailed")
+			So(requests()[0].Body, ShouldResemble, map[string]interface{}{"taskToken": "token-1", "reason": "CardDeclined", "details": "insufficient funds"})
+		})
+	})
+}
+
+func TestRecordActivityTaskHeartbeat(t *testing.T) {
+	Convey("Given a task that is not cancelled", t, func() {
+		ts, s, requests := testService(testJson(`{"cancelRequested":false}`))
+		defer ts.Close()
+
+		cancelled, err := s.RecordActivityTaskHeartbeat("token-1", "50%")
+
+		Convey("It returns false", func() {
+			So(err, ShouldBeNil)
+			So(cancelled, ShouldBeFalse)
+			So(requests()[0].Target, ShouldEqual, "SimpleWorkflowService.RecordActivityTaskHeartbeat")
+			So(requests()[0].Body, ShouldResemble, map[string]interface{}{"taskToken": "token-1", "details": "50%"})
+		})
+	})
+	Convey("Given a task that is asked to be cancelled", t, func() {
+		ts, s, _ := testService(testJson(`{"cancelRequested":true}`))
+		defer ts.Close()
+
+		cancelled, err := s.RecordActivityTaskHeartbeat("token-1", "")
+
+		Convey("It returns true", func() {
+			So(err, ShouldBeNil)
+			So(cancelled, ShouldBeTrue)
+		})
+	})
+	Convey("Given a server that returns errors", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.RecordActivityTaskHeartbeat("token-1", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, unknownResourceError)
+		})
+	})
+}
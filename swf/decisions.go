@@ -0,0 +1,125 @@
+package swf
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// HistoryEvent is an event in the history of a workflow execution.
+type HistoryEvent struct {
+	EventId        int64
+	EventType      string // Like WorkflowExecutionStarted or ActivityTaskCompleted
+	EventTimestamp time.Time
+	Attributes     json.RawMessage // The attributes of the event, which depend on its type. Use DecodeAttributes to read them.
+}
+
+// UnmarshalJSON decodes an event. Each type of event has its attributes under a different name, like activityTaskCompletedEventAttributes,
+// so they are kept as they are in Attributes.
+func (e *HistoryEvent) UnmarshalJSON(data []byte) error {
+	fields := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return err
+	}
+
+	event := struct {
+		EventId        int64   `json:"eventId"`
+		EventType      string  `json:"eventType"`
+		EventTimestamp float64 `json:"eventTimestamp"`
+	}{}
+	err = json.Unmarshal(data, &event)
+	if err != nil {
+		return err
+	}
+
+	seconds, fraction := math.Modf(event.EventTimestamp)
+	*e = HistoryEvent{
+		EventId:        event.EventId,
+		EventType:      event.EventType,
+		EventTimestamp: time.Unix(int64(seconds), int64(fraction*1e9)).UTC(),
+	}
+	for name, value := range fields {
+		if strings.HasSuffix(name, "EventAttributes") {
+			e.Attributes = value
+		}
+	}
+	return nil
+}
+
+// DecodeAttributes decodes the event's attributes into v, like a struct with the fields of activityTaskCompletedEventAttributes.
+func (e HistoryEvent) DecodeAttributes(v interface{}) error {
+	return json.Unmarshal(e.Attributes, v)
+}
+
+// DecisionTask is a workflow execution that needs a decider to decide what to do next.
+type DecisionTask struct {
+	TaskToken              string            `json:"taskToken"` // Identifies the task when responding to it
+	StartedEventId         int64             `json:"startedEventId"`
+	PreviousStartedEventId int64             `json:"previousStartedEventId"` // The event the last decision task started at, so deciders can tell which events are new
+	WorkflowExecution      WorkflowExecution `json:"workflowExecution"`
+	WorkflowType           WorkflowType      `json:"workflowType"`
+	Events                 []HistoryEvent    `json:"events"` // The whole history of the execution, oldest first
+}
+
+type pollForDecisionTaskRequest struct {
+	Domain        string   `json:"domain"`
+	TaskList      TaskList `json:"taskList"`
+	Identity      string   `json:"identity,omitempty"`
+	NextPageToken string   `json:"nextPageToken,omitempty"`
+}
+
+type pollForDecisionTaskResult struct {
+	DecisionTask
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// PollForDecisionTask waits up to 60 seconds for a decision task on the task list. It returns nil if there is none, so deciders should poll again.
+// identity names the decider in the workflow history, like its host name. The history can take several pages, which are all read.
+// It is calling the PollForDecisionTask API call.
+// See http://docs.aws.amazon.com/amazonswf/latest/apireference/API_PollForDecisionTask.html for more details.
+func (s *SWFService) PollForDecisionTask(domain string, taskList string, identity string) (*DecisionTask, error) {
+	var task *DecisionTask
+	input := pollForDecisionTaskRequest{Domain: domain, TaskList: TaskList{Name: taskList}, Identity: identity}
+
+	for {
+		result := pollForDecisionTaskResult{}
+
+		bodyAsJson, err := json.Marshal(input)
+		if err != nil {
+			return nil, err
+		}
+
+		req := s.request()
+		req.Body = bodyAsJson
+		req.Headers["X-Amz-Target"] = "SimpleWorkflowService.PollForDecisionTask"
+		req.Timeout = PollTimeout
+
+		resp, err := req.Do()
+		if err != nil {
+			return nil, err
+		}
+
+		err = gaws.DecodeJSON(resp, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		if task == nil {
+			if result.TaskToken == "" {
+				return nil, nil
+			}
+			task = &result.DecisionTask
+		} else {
+			task.Events = append(task.Events, result.Events...)
+		}
+
+		if result.NextPageToken == "" {
+			return task, nil
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+}
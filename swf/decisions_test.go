@@ -0,0 +1,115 @@
+package swf
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestPollForDecisionTask(t *testing.T) {
+	Convey("Given a decision task", t, func() {
+		ts, s, requests := testService(testJson(`{"taskToken":"token-1","startedEventId":3,
+			"workflowExecution":{"workflowId":"order-42","runId":"run-1"},"workflowType":{"name":"Order","version":"1"},
+			"events":[{"eventId":1,"eventType":"WorkflowExecutionStarted","eventTimestamp":1438000000.5,"workflowExecutionStartedEventAttributes":{"input":"order-42"}}]}`))
+		defer ts.Close()
+
+		task, err := s.PollForDecisionTask("orders", "deciders", "decider-1")
+
+		Convey("It returns the task", func() {
+			So(err, ShouldBeNil)
+			So(task.TaskToken, ShouldEqual, "token-1")
+			So(task.StartedEventId, ShouldEqual, 3)
+			So(task.WorkflowExecution, ShouldResemble, WorkflowExecution{WorkflowId: "order-42", RunId: "run-1"})
+			So(task.WorkflowType, ShouldResemble, WorkflowType{Name: "Order", Version: "1"})
+		})
+		Convey("It returns the events", func() {
+			So(len(task.Events), ShouldEqual, 1)
+			So(task.Events[0].EventId, ShouldEqual, 1)
+			So(task.Events[0].EventType, ShouldEqual, "WorkflowExecutionStarted")
+			So(task.Events[0].EventTimestamp, ShouldResemble, time.Date(2015, 7, 27, 12, 26, 40, 500000000, time.UTC))
+
+			attributes := struct{ Input string }{}
+			So(task.Events[0].DecodeAttributes(&attributes), ShouldBeNil)
+			So(attributes.Input, ShouldEqual, "order-42")
+		})
+		Convey("It polls the task list", func() {
+			So(requests()[0].Target, ShouldEqual, "SimpleWorkflowService.PollForDecisionTask")
+			So(requests()[0].Body, ShouldResemble, map[string]interface{}{
+				"domain":   "orders",
+				"taskList": map[string]interface{}{"name": "deciders"},
+				"identity": "decider-1",
+			})
+		})
+	})
+	Convey("Given a history that takes two pages", t, func() {
+		ts, s, requests := testService(func(w http.ResponseWriter, r *http.Request) {
+			request := struct{ NextPageToken string }{}
+			json.NewDecoder(r.Body).Decode(&request)
+			if request.NextPageToken == "page2" {
+				w.Write([]byte(`{"taskToken":"token-1","startedEventId":3,"events":[{"eventId":2,"eventType":"DecisionTaskScheduled","eventTimestamp":1438000000}]}`))
+				return
+			}
+			w.Write([]byte(`{"taskToken":"token-1","startedEventId":3,"events":[{"eventId":1,"eventType":"WorkflowExecutionStarted","eventTimestamp":1438000000}],"nextPageToken":"page2"}`))
+		})
+		defer ts.Close()
+
+		task, err := s.PollForDecisionTask("orders", "deciders", "")
+
+		Convey("Every page of events is returned", func() {
+			So(err, ShouldBeNil)
+			So(len(task.Events), ShouldEqual, 2)
+			So(task.Events[1].EventType, ShouldEqual, "DecisionTaskScheduled")
+			So(requests()[1].Body["nextPageToken"], ShouldEqual, "page2")
+		})
+	})
+	Convey("Given no decision task within the poll", t, func() {
+		ts, s, requests := testService(testJson(`{"startedEventId":0}`))
+		defer ts.Close()
+
+		task, err := s.PollForDecisionTask("orders", "deciders", "")
+
+		Convey("It returns no task", func() {
+			So(err, ShouldBeNil)
+			So(task, ShouldBeNil)
+			So(len(requests()), ShouldEqual, 1)
+		})
+	})
+	Convey("Given a poll that takes longer than PollTimeout", t, func() {
+		ts, s, _ := testService(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+			w.Write([]byte(`{}`))
+		})
+		defer ts.Close()
+		defer func(timeout time.Duration) { PollTimeout = timeout }(PollTimeout)
+		PollTimeout = 50 * time.Millisecond
+
+		_, err := s.PollForDecisionTask("orders", "deciders", "")
+
+		Convey("It gives up", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+	Convey("Given an unknown domain", t, func() {
+		ts, s, _ := testService(testHTTP400)
+		defer ts.Close()
+
+		_, err := s.PollForDecisionTask("orders", "deciders", "")
+
+		Convey("It returns the error", func() {
+			So(err, ShouldResemble, unknownResourceError)
+		})
+	})
+	Convey("Given a response that is not JSON", t, func() {
+		ts, s, _ := testService(testBadJson)
+		defer ts.Close()
+
+		_, err := s.PollForDecisionTask("orders", "deciders", "")
+
+		Convey("It returns an error", func() {
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
@@ -0,0 +1,24 @@
+// Code generated by errgen from the go:generate line in swf.go. DO NOT EDIT.
+
+package swf
+
+import "strings"
+
+// These are errors SWF returns. errors.Is(err, swf.ErrUnknownResource) is true for any error with the same type, whatever its message.
+var (
+	ErrUnknownResource                 = swfError{Type: "UnknownResourceFault"}
+	ErrTypeAlreadyExists               = swfError{Type: "TypeAlreadyExistsFault"}
+	ErrDomainAlreadyExists             = swfError{Type: "DomainAlreadyExistsFault"}
+	ErrWorkflowExecutionAlreadyStarted = swfError{Type: "WorkflowExecutionAlreadyStartedFault"}
+	ErrTypeDeprecated                  = swfError{Type: "TypeDeprecatedFault"}
+	ErrDomainDeprecated                = swfError{Type: "DomainDeprecatedFault"}
+	ErrOperationNotPermitted           = swfError{Type: "OperationNotPermittedFault"}
+	ErrDefaultUndefined                = swfError{Type: "DefaultUndefinedFault"}
+	ErrLimitExceeded                   = swfError{Type: "LimitExceededFault"}
+)
+
+// Is reports whether target is an error with the same type as e, so that errors.Is matches the errors above.
+func (e swfError) Is(target error) bool {
+	t, ok := target.(swfError)
+	return ok && t.Type != "" && (e.Type == t.Type || strings.HasSuffix(e.Type, "#"+t.Type))
+}
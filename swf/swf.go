@@ -0,0 +1,118 @@
+// Package swf provides a way to interact with the AWS Simple Workflow Service.
+package swf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/controlgroup/gaws"
+)
+
+// PollTimeout is how long a poll for a task can take. SWF holds polls open for up to 60 seconds when there is no task,
+// so it is a little longer than that, to give up on connections that are gone.
+var PollTimeout = 70 * time.Second
+
+//go:generate go run ../internal/errgen -type swfError -field Type -service SWF UnknownResourceFault TypeAlreadyExistsFault DomainAlreadyExistsFault WorkflowExecutionAlreadyStartedFault TypeDeprecatedFault DomainDeprecatedFault OperationNotPermittedFault DefaultUndefinedFault LimitExceededFault
+
+// swfError is the error document returned from the SWF service.
+type swfError struct {
+	Type    string `json:"__type"`
+	Message string `json:"message"`
+}
+
+// Error formats the swfError into an error message.
+func (e swfError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Type, e.Message)
+}
+
+// is reports whether the error is of the given type. Error types may be prefixed with a namespace, so only the end is compared.
+func (e swfError) is(errorType string) bool {
+	return strings.HasSuffix(e.Type, "#"+errorType) || e.Type == errorType
+}
+
+func swfRetryPredicate(status int, body []byte) (bool, error) {
+	if status < 400 {
+		return false, nil
+	}
+
+	// The request failed, but why?
+	error := swfError{}
+
+	err := json.Unmarshal(body, &error)
+	if err != nil {
+		return false, err
+	}
+
+	// retry if it is an AWS error
+	if status >= 500 {
+		return true, error
+	}
+
+	if error.is("ThrottlingException") {
+		return true, error
+	}
+
+	return false, error
+}
+
+// SWFService is the SWF service at AWS. The endpoint for a region is gaws.Endpoint("swf", region).
+type SWFService struct {
+	Endpoint string
+	config   gaws.Config // Set by New
+}
+
+// SWFAPI is implemented by *SWFService. Code that takes an SWFAPI can be given a fake in tests instead.
+type SWFAPI interface {
+	PollForActivityTask(domain string, taskList string, identity string) (*ActivityTask, error)
+	PollForDecisionTask(domain string, taskList string, identity string) (*DecisionTask, error)
+	RecordActivityTaskHeartbeat(taskToken string, details string) (bool, error)
+	RespondActivityTaskCompleted(taskToken string, result string) error
+	RespondActivityTaskFailed(taskToken string, reason string, details string) error
+}
+
+var _ SWFAPI = (*SWFService)(nil)
+
+// New returns an SWFService with the options applied. Its endpoint is gaws.Endpoint("swf", region) unless gaws.WithEndpoint is given.
+func New(options ...gaws.Option) *SWFService {
+	config := gaws.NewConfig(options...)
+	return &SWFService{Endpoint: config.ServiceEndpoint("swf"), config: config}
+}
+
+func (s *SWFService) request() gaws.AWSRequest {
+	r := gaws.AWSRequest{
+		RetryPredicate: swfRetryPredicate,
+		Method:         "POST",
+		URL:            s.Endpoint,
+		Signer:         gaws.Sign4("swf", s.config.Region),
+		Headers: map[string]string{
+			"Content-Type": "application/x-amz-json-1.0",
+		},
+	}
+	s.config.Apply(&r)
+	return r
+}
+
+// TaskList is a list that tasks are scheduled on and polled from.
+type TaskList struct {
+	Name string `json:"name"`
+}
+
+// WorkflowExecution is a run of a workflow.
+type WorkflowExecution struct {
+	WorkflowId string `json:"workflowId"`
+	RunId      string `json:"runId"`
+}
+
+// WorkflowType is a registered type of workflow.
+type WorkflowType struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ActivityType is a registered type of activity.
+type ActivityType struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
@@ -0,0 +1,22 @@
+package gaws
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewIdempotencyToken returns a random version 4 UUID to use as the ClientToken or ClientRequestToken of a call. AWS does a call with a
+// token it has seen only once, so a retry of a call that timed out does not, say, start a second copy of a task. Requests keep their body
+// when they are retried, so a token made once for a call is sent with every retry of it.
+func NewIdempotencyToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40 // Version 4
+	b[8] = b[8]&0x3f | 0x80 // The variant in RFC 4122
+
+	s := hex.EncodeToString(b)
+	return s[0:8] + "-" + s[8:12] + "-" + s[12:16] + "-" + s[16:20] + "-" + s[20:], nil
+}
@@ -0,0 +1,26 @@
+package gaws
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testTokenPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewIdempotencyToken(t *testing.T) {
+	Convey("Given two tokens", t, func() {
+		first, err := NewIdempotencyToken()
+		So(err, ShouldBeNil)
+		second, err := NewIdempotencyToken()
+		So(err, ShouldBeNil)
+
+		Convey("They are version 4 UUIDs", func() {
+			So(testTokenPattern.MatchString(first), ShouldBeTrue)
+		})
+		Convey("They are different", func() {
+			So(first, ShouldNotEqual, second)
+		})
+	})
+}
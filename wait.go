@@ -0,0 +1,30 @@
+package gaws
+
+import (
+	"time"
+)
+
+// Wait calls done until it returns true or an error, for waiters like a DynamoDB table's WaitUntilActive. Between calls it sleeps on the
+// client's clock, as its WaitBackoff says, or interval if it has none. It gives up after maxWaits calls and returns timeout.
+func (c Config) Wait(interval time.Duration, maxWaits int, timeout error, done func() (bool, error)) error {
+	backoff := c.WaitBackoff
+	if backoff == nil {
+		backoff = ConstantBackoff{Delay: interval}
+	}
+	clock := c.CurrentClock()
+
+	var waited time.Duration
+	for i := 0; i < maxWaits; i++ {
+		finished, err := done()
+		if err != nil {
+			return err
+		}
+
+		if finished {
+			return nil
+		}
+		waited = backoff.Wait(i+1, waited)
+		<-clock.After(waited)
+	}
+	return timeout
+}
@@ -0,0 +1,46 @@
+package gaws
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWait(t *testing.T) {
+	timeout := errors.New("timed out")
+
+	Convey("Given something that is done on the third check", t, func() {
+		clock := &testClock{now: time.Now()}
+		checks := 0
+		done := func() (bool, error) {
+			checks++
+			return checks == 3, nil
+		}
+
+		Convey("Without a wait backoff, it sleeps the interval on the config's clock", func() {
+			err := Config{Clock: clock}.Wait(5*time.Second, 10, timeout, done)
+			So(err, ShouldBeNil)
+			So(clock.sleeps, ShouldResemble, []time.Duration{5 * time.Second, 5 * time.Second})
+		})
+		Convey("With a wait backoff, it sleeps as the backoff says", func() {
+			err := NewConfig(WithClock(clock), WithWaitBackoff(ExponentialBackoff{Base: time.Second})).Wait(5*time.Second, 10, timeout, done)
+			So(err, ShouldBeNil)
+			So(clock.sleeps, ShouldResemble, []time.Duration{time.Second, 2 * time.Second})
+		})
+		Convey("With too few checks, it returns the timeout error", func() {
+			err := Config{Clock: clock}.Wait(time.Second, 2, timeout, done)
+			So(err, ShouldEqual, timeout)
+			So(checks, ShouldEqual, 2)
+		})
+	})
+	Convey("Given a check that fails", t, func() {
+		failure := errors.New("no")
+		err := Config{Clock: &testClock{now: time.Now()}}.Wait(time.Second, 10, timeout, func() (bool, error) { return false, failure })
+
+		Convey("It returns the error", func() {
+			So(err, ShouldEqual, failure)
+		})
+	})
+}
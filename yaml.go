@@ -0,0 +1,158 @@
+package gaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a line of a YAML document that has a key, like "region: eu-west-1" or "endpoints:".
+type yamlLine struct {
+	number int // From 1
+	indent int
+	key    string
+	value  string // Empty if the key's value is the lines indented under it
+}
+
+// yamlNumberPattern matches the plain scalars that are numbers, like 3 or -1.5.
+var yamlNumberPattern = regexp.MustCompile(`^[-+]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][-+]?[0-9]+)?$`)
+
+// parseYAML reads the part of YAML a config file needs: mappings nested by indentation, with plain, single-quoted or double-quoted
+// scalars, and comments. It returns the mapping as it would be decoded from JSON, so that it can be checked as JSON is. Sequences,
+// flow collections, anchors, tags and multi-line scalars are refused, since a ConfigFile has no use for them.
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	lines := []yamlLine{}
+	for i, text := range strings.Split(string(data), "\n") {
+		text = strings.TrimRight(stripYAMLComment(text), " \t\r")
+		trimmed := strings.TrimLeft(text, " ")
+		if trimmed == "" || (trimmed == "---" && len(lines) == 0) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: indentation must be spaces, not tabs", i+1)
+		}
+
+		line := yamlLine{number: i + 1, indent: len(text) - len(trimmed)}
+		colon := strings.Index(trimmed, ": ")
+		switch {
+		case colon > 0:
+			line.key, line.value = trimmed[:colon], strings.TrimSpace(trimmed[colon+2:])
+		case strings.HasSuffix(trimmed, ":") && len(trimmed) > 1:
+			line.key = trimmed[:len(trimmed)-1]
+		default:
+			return nil, fmt.Errorf("line %d: %q is not a key and a value, like region: eu-west-1", line.number, trimmed)
+		}
+		// Keys can be quoted too. Keys that are other scalars, like 3, are kept as they are written.
+		key, err := yamlScalar(line.number, line.key)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := key.(string); ok {
+			line.key = key
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	mapping, next, err := parseYAMLMapping(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, fmt.Errorf("line %d: the indentation does not match the lines before it", lines[next].number)
+	}
+	return mapping, nil
+}
+
+// parseYAMLMapping reads the mapping whose keys start at lines[i] with the indent, and returns it and the index of the line after it.
+func parseYAMLMapping(lines []yamlLine, i int, indent int) (map[string]interface{}, int, error) {
+	mapping := map[string]interface{}{}
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		if _, ok := mapping[line.key]; ok {
+			return nil, i, fmt.Errorf("line %d: %q is already set", line.number, line.key)
+		}
+		i++
+
+		if line.value != "" {
+			value, err := yamlScalar(line.number, line.value)
+			if err != nil {
+				return nil, i, err
+			}
+			mapping[line.key] = value
+			continue
+		}
+		if i < len(lines) && lines[i].indent > indent {
+			value, next, err := parseYAMLMapping(lines, i, lines[i].indent)
+			if err != nil {
+				return nil, next, err
+			}
+			mapping[line.key], i = value, next
+			continue
+		}
+		mapping[line.key] = nil
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, i, fmt.Errorf("line %d: the indentation does not match the lines before it", lines[i].number)
+	}
+	return mapping, i, nil
+}
+
+// yamlScalar returns the value of a scalar: a string, a bool, a json.Number, or nil for null.
+func yamlScalar(number int, text string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(text, `"`):
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v is not a double-quoted string", number, text)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") {
+			return nil, fmt.Errorf("line %d: %v is not a single-quoted string", number, text)
+		}
+		return strings.Replace(text[1:len(text)-1], "''", "'", -1), nil
+	case strings.ContainsAny(text[:1], "[]{}&*!|>%@`") || text == "-" || strings.HasPrefix(text, "- "):
+		return nil, fmt.Errorf("line %d: %v is not supported, only mappings and scalars are", number, text)
+	}
+
+	switch text {
+	case "true", "True", "TRUE":
+		return true, nil
+	case "false", "False", "FALSE":
+		return false, nil
+	case "null", "Null", "NULL", "~":
+		return nil, nil
+	}
+	if yamlNumberPattern.MatchString(text) {
+		return json.Number(text), nil
+	}
+	return text, nil
+}
+
+// stripYAMLComment returns the line without its comment, which starts with a # at the start of the line or after a space, outside quotes.
+func stripYAMLComment(line string) string {
+	var quote rune
+	escaped := false
+	for i, c := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			// A backslash in double quotes escapes the next character, which might be a quote.
+			escaped = c == '\\' && quote == '"'
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,76 @@
+package gaws
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseYAML(t *testing.T) {
+	Convey("Given YAML with nested mappings, scalars and comments", t, func() {
+		mapping, err := parseYAML([]byte(`---
+# A comment
+name: worker
+"quoted key": 'it''s # not a comment'
+escaped: "say \"hi\" # still not" # a comment
+nested:
+    count: 3
+    ratio: -1.5
+    enabled: true
+    deeper:
+      nothing: ~
+
+empty:
+last: http://localhost:4566
+`))
+
+		Convey("It returns what JSON with the same values would decode to", func() {
+			So(err, ShouldBeNil)
+			So(mapping, ShouldResemble, map[string]interface{}{
+				"name":       "worker",
+				"quoted key": "it's # not a comment",
+				"escaped":    `say "hi" # still not`,
+				"nested": map[string]interface{}{
+					"count":   json.Number("3"),
+					"ratio":   json.Number("-1.5"),
+					"enabled": true,
+					"deeper":  map[string]interface{}{"nothing": nil},
+				},
+				"empty": nil,
+				"last":  "http://localhost:4566",
+			})
+		})
+	})
+
+	Convey("Given an empty document", t, func() {
+		mapping, err := parseYAML([]byte("# Nothing yet\n"))
+
+		Convey("It is an empty mapping", func() {
+			So(err, ShouldBeNil)
+			So(mapping, ShouldResemble, map[string]interface{}{})
+		})
+	})
+
+	Convey("Given YAML that a config file cannot have", t, func() {
+		Convey("Errors say which line is wrong", func() {
+			for yaml, message := range map[string]string{
+				"a: 1\nlist:\n  - one\n": `line 3: "- one" is not a key and a value, like region: eu-west-1`,
+				"a: {b: 1}\n":            "line 1: {b: 1} is not supported, only mappings and scalars are",
+				"a: &anchor 1\n":         "line 1: &anchor 1 is not supported, only mappings and scalars are",
+				"a: |\n":                 "line 1: | is not supported, only mappings and scalars are",
+				"a:\n  b: 1\n c: 2\n":    "line 3: the indentation does not match the lines before it",
+				"a: 1\n  b: 2\n":         "line 2: the indentation does not match the lines before it",
+				"a: 1\na: 2\n":           `line 2: "a" is already set`,
+				"a:\n\tb: 1\n":           "line 2: indentation must be spaces, not tabs",
+				"a: \"open\n":            `line 1: "open is not a double-quoted string`,
+				"a: 'open\n":             "line 1: 'open is not a single-quoted string",
+				"just a value\n":         `line 1: "just a value" is not a key and a value, like region: eu-west-1`,
+			} {
+				_, err := parseYAML([]byte(yaml))
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, message)
+			}
+		})
+	})
+}